@@ -18,13 +18,18 @@ package azure
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/url"
+	"os"
+	"strings"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+
+	"github.com/fluxcd/pkg/auth/resilience"
 )
 
 const (
@@ -34,6 +39,11 @@ const (
 	// can be used to call Azure DevOps API by passing it in the headers as a
 	// Bearer Token : https://learn.microsoft.com/en-us/azure/devops/integrate/get-started/authentication/service-principal-managed-identity?view=azure-devops#q-can-i-use-a-service-principal-or-managed-identity-with-azure-cli
 	AzureDevOpsRestApiScope = "499b84ac-1321-427f-aa17-267ca6975798/.default"
+
+	// EnvironmentEnvVar is the environment variable selecting the Azure
+	// cloud when none is configured with WithCloud, e.g. "AzureChinaCloud".
+	// It uses the environment names of the Azure CLI and the Azure SDKs.
+	EnvironmentEnvVar = "AZURE_ENVIRONMENT"
 )
 
 // Client is an authentication provider for Azure.
@@ -41,6 +51,8 @@ type Client struct {
 	credential azcore.TokenCredential
 	scopes     []string
 	proxyURL   *url.URL
+	guard      *resilience.Guard
+	cloud      *cloud.Configuration
 }
 
 // OptFunc enables specifying options for the provider.
@@ -50,14 +62,27 @@ type OptFunc func(*Client)
 // credentials using a default credential chain with options.
 // https://pkg.go.dev/github.com/Azure/azure-sdk-for-go/sdk/azidentity#NewDefaultAzureCredential
 // The default scope is to ARM endpoint in Azure Cloud. The scope is overridden
-// using OptFunc.
+// using OptFunc. The Azure Cloud is the public cloud, unless it is set with
+// WithCloud or the EnvironmentEnvVar environment variable.
 func New(opts ...OptFunc) (*Client, error) {
 	p := &Client{}
 	for _, opt := range opts {
 		opt(p)
 	}
 
+	if p.cloud == nil {
+		cfg := cloud.AzurePublic
+		if name := os.Getenv(EnvironmentEnvVar); name != "" {
+			var err error
+			if cfg, err = CloudFromEnvironmentName(name); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", EnvironmentEnvVar, err)
+			}
+		}
+		p.cloud = &cfg
+	}
+
 	clientOpts := &azidentity.DefaultAzureCredentialOptions{}
+	clientOpts.Cloud = *p.cloud
 
 	if p.proxyURL != nil {
 		transport := http.DefaultTransport.(*http.Transport).Clone()
@@ -74,12 +99,36 @@ func New(opts ...OptFunc) (*Client, error) {
 	}
 
 	if len(p.scopes) == 0 {
-		p.scopes = []string{cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint + "/" + ".default"}
+		p.scopes = []string{p.cloud.Services[cloud.ResourceManager].Endpoint + "/" + ".default"}
 	}
 
 	return p, nil
 }
 
+// WithCloud configures the Azure cloud, e.g. cloud.AzureChina or
+// cloud.AzureGovernment, which determines the Microsoft Entra authority of
+// the default credential chain and the default ARM scope.
+func WithCloud(cfg cloud.Configuration) OptFunc {
+	return func(p *Client) {
+		p.cloud = &cfg
+	}
+}
+
+// CloudFromEnvironmentName returns the cloud configuration of the given
+// Azure environment name, e.g. "AzureUSGovernment" or
+// "AzureUSGovernmentCloud". The name is case-insensitive.
+func CloudFromEnvironmentName(name string) (cloud.Configuration, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "azurecloud", "azurepubliccloud", "azurepublic":
+		return cloud.AzurePublic, nil
+	case "azurechinacloud", "azurechina":
+		return cloud.AzureChina, nil
+	case "azureusgovernmentcloud", "azureusgovernment", "azuregovernment":
+		return cloud.AzureGovernment, nil
+	}
+	return cloud.Configuration{}, fmt.Errorf("unknown Azure environment '%s'", name)
+}
+
 // WithCredential configures the credential to use to fetch the resource manager
 // token.
 func WithCredential(cred azcore.TokenCredential) OptFunc {
@@ -110,9 +159,33 @@ func WithProxyURL(proxyURL *url.URL) OptFunc {
 	}
 }
 
+// WithGuard configures the guard protecting the token requests with
+// timeouts, retries and a circuit breaker.
+func WithGuard(guard *resilience.Guard) OptFunc {
+	return func(p *Client) {
+		p.guard = guard
+	}
+}
+
 // GetToken gets an OAuth token using azcore TokenCredential
 func (p *Client) GetToken(ctx context.Context) (azcore.AccessToken, error) {
-	return p.credential.GetToken(ctx, policy.TokenRequestOptions{
-		Scopes: p.scopes,
+	getToken := func(ctx context.Context) (azcore.AccessToken, error) {
+		return p.credential.GetToken(ctx, policy.TokenRequestOptions{
+			Scopes: p.scopes,
+		})
+	}
+	if p.guard == nil {
+		return getToken(ctx)
+	}
+
+	var token azcore.AccessToken
+	err := p.guard.Do(ctx, func(ctx context.Context) error {
+		t, err := getToken(ctx)
+		if err != nil {
+			return err
+		}
+		token = t
+		return nil
 	})
+	return token, err
 }
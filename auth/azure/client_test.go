@@ -22,10 +22,13 @@ import (
 	"fmt"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/auth/resilience"
 )
 
 func TestGetProviderToken(t *testing.T) {
@@ -114,3 +117,78 @@ func TestGetProviderToken(t *testing.T) {
 		})
 	}
 }
+
+func TestGetProviderToken_WithGuard(t *testing.T) {
+	g := NewWithT(t)
+
+	guard, err := resilience.New("azure",
+		resilience.WithRetryBudget(1, time.Millisecond),
+		resilience.WithCircuitBreaker(1, time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	client, err := New(WithCredential(&FakeTokenCredential{Err: errors.New("oh no!")}), WithGuard(guard))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = client.GetToken(context.TODO())
+	g.Expect(err).To(MatchError(resilience.ErrRetryBudgetExhausted))
+
+	_, err = client.GetToken(context.TODO())
+	g.Expect(err).To(MatchError(resilience.ErrCircuitOpen))
+}
+
+func TestGetProviderToken_Cloud(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		opts      []OptFunc
+		wantScope string
+		wantErr   string
+	}{
+		{
+			name:      "public cloud by default",
+			wantScope: cloud.AzurePublic.Services[cloud.ResourceManager].Endpoint + "/" + ".default",
+		},
+		{
+			name:      "cloud from environment",
+			env:       "AzureChinaCloud",
+			wantScope: cloud.AzureChina.Services[cloud.ResourceManager].Endpoint + "/" + ".default",
+		},
+		{
+			name:      "cloud option takes precedence",
+			env:       "AzureChinaCloud",
+			opts:      []OptFunc{WithCloud(cloud.AzureGovernment)},
+			wantScope: cloud.AzureGovernment.Services[cloud.ResourceManager].Endpoint + "/" + ".default",
+		},
+		{
+			name:      "explicit scope",
+			opts:      []OptFunc{WithCloud(cloud.AzureGovernment), WithAzureDevOpsScope()},
+			wantScope: AzureDevOpsRestApiScope,
+		},
+		{
+			name:    "unknown environment",
+			env:     "AzureMoonCloud",
+			wantErr: "invalid AZURE_ENVIRONMENT: unknown Azure environment 'AzureMoonCloud'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv(EnvironmentEnvVar, tt.env)
+
+			opts := append(tt.opts, WithCredential(&FakeTokenCredential{Token: "foo"}))
+			client, err := New(opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			str := ""
+			ctx := context.WithValue(context.TODO(), "scope", &str)
+			_, err = client.GetToken(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(str).To(Equal(tt.wantScope))
+		})
+	}
+}
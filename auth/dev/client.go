@@ -0,0 +1,140 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dev provides an authentication provider for local development and
+// end-to-end tests, e.g. with Git servers and container registries running
+// in a kind or minikube cluster. It mints deterministic fake credentials
+// without contacting any identity provider, and must never be enabled in
+// production.
+package dev
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultUsername is the username returned by the provider if none is
+	// configured.
+	DefaultUsername = "flux-dev"
+
+	// DefaultTTL is the default validity of the minted tokens.
+	DefaultTTL = time.Hour
+
+	// tokenPrefix is the prefix of the minted tokens, which makes them easy
+	// to recognize in the logs of the servers under test.
+	tokenPrefix = "fluxdev_"
+)
+
+// ErrNotEnabled is returned by New when the provider has not been explicitly
+// enabled using WithEnabled.
+var ErrNotEnabled = errors.New("the dev authentication provider is not enabled")
+
+// Client is an authentication provider minting fake credentials for local
+// development.
+type Client struct {
+	enabled  bool
+	username string
+	subject  string
+	ttl      time.Duration
+	now      func() time.Time
+}
+
+// OptFunc enables specifying options for the provider.
+type OptFunc func(*Client)
+
+// New returns a new dev authentication provider. It returns ErrNotEnabled
+// unless the provider is enabled using WithEnabled, so that it cannot be
+// selected by accident through user input alone.
+func New(opts ...OptFunc) (*Client, error) {
+	p := &Client{
+		username: DefaultUsername,
+		ttl:      DefaultTTL,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if !p.enabled {
+		return nil, ErrNotEnabled
+	}
+
+	return p, nil
+}
+
+// WithEnabled enables the provider. Controllers should only set this when
+// an explicit flag or environment variable has been set by the operator.
+func WithEnabled(enabled bool) OptFunc {
+	return func(p *Client) {
+		p.enabled = enabled
+	}
+}
+
+// WithUsername configures the username returned with the token.
+func WithUsername(username string) OptFunc {
+	return func(p *Client) {
+		p.username = username
+	}
+}
+
+// WithSubject configures the subject the token is minted for, e.g. the URL
+// of the repository or registry. Tokens minted for the same subject are
+// identical, which allows servers under test to be configured with the
+// expected credentials upfront.
+func WithSubject(subject string) OptFunc {
+	return func(p *Client) {
+		p.subject = subject
+	}
+}
+
+// WithTTL configures the validity of the minted tokens.
+func WithTTL(ttl time.Duration) OptFunc {
+	return func(p *Client) {
+		p.ttl = ttl
+	}
+}
+
+// Token is a fake token minted by the dev provider.
+type Token struct {
+	Username  string
+	Token     string
+	ExpiresAt time.Time
+}
+
+// GetToken returns a fake token for the configured subject.
+func (p *Client) GetToken(ctx context.Context) (*Token, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return &Token{
+		Username:  p.username,
+		Token:     TokenFor(p.username, p.subject),
+		ExpiresAt: p.now().Add(p.ttl),
+	}, nil
+}
+
+// TokenFor returns the token the provider mints for the given username and
+// subject. It can be used to configure the servers under test with the
+// credentials they should accept.
+func TokenFor(username, subject string) string {
+	sum := sha256.Sum256([]byte(username + "\x00" + subject))
+	return tokenPrefix + hex.EncodeToString(sum[:16])
+}
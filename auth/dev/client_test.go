@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dev
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestClient_Options(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []OptFunc
+		wantErr error
+	}{
+		{
+			name: "Create new client",
+			opts: []OptFunc{WithEnabled(true)},
+		},
+		{
+			name: "Create new client with subject and username",
+			opts: []OptFunc{WithEnabled(true), WithSubject("http://gitea.local/org/repo"), WithUsername("e2e")},
+		},
+		{
+			name:    "Provider not enabled",
+			opts:    []OptFunc{WithSubject("http://gitea.local/org/repo")},
+			wantErr: ErrNotEnabled,
+		},
+		{
+			name:    "Provider explicitly disabled",
+			opts:    []OptFunc{WithEnabled(false)},
+			wantErr: ErrNotEnabled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := New(tt.opts...)
+			if tt.wantErr != nil {
+				g.Expect(err).To(MatchError(tt.wantErr))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClient_GetToken(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	subject := "registry.kind.local:5000"
+
+	client, err := New(WithEnabled(true), WithSubject(subject), WithTTL(10*time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+	client.now = func() time.Time { return now }
+
+	token, err := client.GetToken(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token.Username).To(Equal(DefaultUsername))
+	g.Expect(token.Token).To(HavePrefix("fluxdev_"))
+	g.Expect(token.Token).To(Equal(TokenFor(DefaultUsername, subject)))
+	g.Expect(token.ExpiresAt).To(Equal(now.Add(10 * time.Minute)))
+
+	// Tokens are deterministic per username and subject.
+	again, err := client.GetToken(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(again.Token).To(Equal(token.Token))
+	g.Expect(TokenFor(DefaultUsername, "other")).ToNot(Equal(token.Token))
+	g.Expect(TokenFor("other", subject)).ToNot(Equal(token.Token))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = client.GetToken(ctx)
+	g.Expect(err).To(MatchError(context.Canceled))
+}
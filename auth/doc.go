@@ -15,5 +15,8 @@ limitations under the License.
 */
 
 // Package auth is a Go package for OIDC-based authentication against Git SaaS providers.
-// Includes support for Azure DevOps and GitHub Apps.
+// Includes support for Azure DevOps, GitHub Apps, SPIFFE workload identities,
+// HashiCorp Vault and generic OIDC token exchange (RFC 8693) for self-hosted services,
+// a dev provider minting fake credentials for local development and e2e tests,
+// and helpers to schedule the refresh of expiring credentials.
 package auth
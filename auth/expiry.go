@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"time"
+)
+
+const (
+	// DefaultExpiryMargin is the margin before the expiry of credentials
+	// within which RequeueAfter schedules the reconciliation using them, so
+	// that they are refreshed before they are rejected.
+	DefaultExpiryMargin = time.Minute
+
+	// MinRequeueInterval is the minimum interval returned by RequeueAfter,
+	// so that credentials expiring soon, or expired, don't cause a hot loop.
+	MinRequeueInterval = 10 * time.Second
+)
+
+// Expiring is implemented by the credentials of the providers, e.g. the
+// tokens and credentials of the generic and vault packages, and by the
+// tokens stored in a cache.TokenCache.
+type Expiring interface {
+	// GetDuration returns the remaining validity of the credentials.
+	GetDuration() time.Duration
+}
+
+// ExpiringCredentials are credentials together with their expiry.
+type ExpiringCredentials[T Expiring] struct {
+	// Credentials are the credentials returned by the provider.
+	Credentials T
+	// ExpiresAt is the expiry of the credentials.
+	ExpiresAt time.Time
+}
+
+// WithExpiry returns the credentials returned by a provider together with
+// their expiry, e.g.:
+//
+//	creds, err := auth.WithExpiry(client.GetCredentials(ctx))
+func WithExpiry[T Expiring](creds T, err error) (*ExpiringCredentials[T], error) {
+	if err != nil {
+		return nil, err
+	}
+	return &ExpiringCredentials[T]{
+		Credentials: creds,
+		ExpiresAt:   time.Now().Add(creds.GetDuration()),
+	}, nil
+}
+
+// RequeueAfter returns the interval after which the reconciliation using
+// the credentials should be scheduled.
+func (c *ExpiringCredentials[T]) RequeueAfter(interval time.Duration) time.Duration {
+	return RequeueAfter(c.ExpiresAt, interval)
+}
+
+// RequeueAfter returns the interval after which an object using credentials
+// expiring at expiresAt should be reconciled, so that the credentials are
+// refreshed before they expire. It is the given interval, unless the
+// credentials are within DefaultExpiryMargin of their expiry before, in
+// which case it is the time until then, but at least MinRequeueInterval.
+// A zero expiresAt means the credentials don't expire.
+func RequeueAfter(expiresAt time.Time, interval time.Duration) time.Duration {
+	if expiresAt.IsZero() {
+		return interval
+	}
+	refreshIn := time.Until(expiresAt) - DefaultExpiryMargin
+	if interval > 0 && interval <= refreshIn {
+		return interval
+	}
+	return max(refreshIn, MinRequeueInterval)
+}
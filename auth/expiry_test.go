@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type testToken struct {
+	duration time.Duration
+}
+
+func (t *testToken) GetDuration() time.Duration {
+	return t.duration
+}
+
+func TestWithExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	token := &testToken{duration: time.Hour}
+	creds, err := WithExpiry(token, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(creds.Credentials).To(Equal(token))
+	g.Expect(creds.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+	g.Expect(creds.RequeueAfter(10*time.Minute)).To(Equal(10 * time.Minute))
+
+	_, err = WithExpiry[*testToken](nil, errors.New("boom"))
+	g.Expect(err).To(MatchError("boom"))
+}
+
+func TestRequeueAfter(t *testing.T) {
+	tests := []struct {
+		name      string
+		expiresIn time.Duration
+		noExpiry  bool
+		interval  time.Duration
+		want      time.Duration
+	}{
+		{
+			name:     "no expiry",
+			noExpiry: true,
+			interval: 10 * time.Minute,
+			want:     10 * time.Minute,
+		},
+		{
+			name:      "interval before expiry",
+			expiresIn: time.Hour,
+			interval:  10 * time.Minute,
+			want:      10 * time.Minute,
+		},
+		{
+			name:      "expiry before interval",
+			expiresIn: 15 * time.Minute,
+			interval:  time.Hour,
+			want:      14 * time.Minute,
+		},
+		{
+			name:      "no interval",
+			expiresIn: 15 * time.Minute,
+			want:      14 * time.Minute,
+		},
+		{
+			name:      "expiring soon",
+			expiresIn: 30 * time.Second,
+			interval:  time.Hour,
+			want:      MinRequeueInterval,
+		},
+		{
+			name:      "expired",
+			expiresIn: -time.Minute,
+			interval:  time.Hour,
+			want:      MinRequeueInterval,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var expiresAt time.Time
+			if !tt.noExpiry {
+				expiresAt = time.Now().Add(tt.expiresIn)
+			}
+			g.Expect(RequeueAfter(expiresAt, tt.interval)).To(BeNumerically("~", tt.want, time.Second))
+		})
+	}
+}
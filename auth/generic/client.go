@@ -0,0 +1,405 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package generic provides an authentication provider for self-hosted
+// services federated with the Kubernetes OIDC issuer, e.g. Harbor or
+// Artifactory. It exchanges a Kubernetes ServiceAccount token for an access
+// token at the token endpoint of an OIDC provider, using the OAuth 2.0 Token
+// Exchange (RFC 8693), and returns the access token as the password of
+// registry or Git credentials.
+package generic
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/fluxcd/pkg/auth/resilience"
+)
+
+const (
+	// GrantTypeTokenExchange is the grant type of the token exchange.
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+	// TokenTypeJWT is the token type of the Kubernetes ServiceAccount
+	// tokens sent as subject tokens.
+	TokenTypeJWT = "urn:ietf:params:oauth:token-type:jwt"
+
+	// TokenTypeAccessToken is the token type requested when none is
+	// configured using WithRequestedTokenType.
+	TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+	// DefaultUsername is the username of the credentials when none is
+	// configured using WithUsername.
+	DefaultUsername = "oauth2"
+
+	// DefaultTokenTTL is the validity assumed for the exchanged tokens when
+	// the token endpoint does not return their lifetime.
+	DefaultTokenTTL = 5 * time.Minute
+
+	// DefaultServiceAccountTokenPath is the path of the token of the
+	// controller's ServiceAccount, used when no token is configured using
+	// WithServiceAccountToken.
+	DefaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	maxErrorBodyLength  = 4096
+	maxResponseBodySize = 1 << 20
+)
+
+// ErrNoTokenURL is returned by New when the URL of the token endpoint has
+// not been configured using WithTokenURL.
+var ErrNoTokenURL = errors.New("the URL of the token endpoint must be provided")
+
+// Client is an authentication provider exchanging Kubernetes ServiceAccount
+// tokens at an OIDC token endpoint.
+type Client struct {
+	tokenURL           string
+	audience           string
+	resource           string
+	scopes             []string
+	requestedTokenType string
+	clientID           string
+	clientSecret       string
+	username           string
+	saToken            string
+	saTokenPath        string
+	proxyURL           *url.URL
+	httpClient         *http.Client
+	guard              *resilience.Guard
+	now                func() time.Time
+}
+
+// OptFunc enables specifying options for the provider.
+type OptFunc func(*Client)
+
+// New returns a new generic OIDC authentication provider. By default, it
+// exchanges the token of the controller's ServiceAccount, read from
+// DefaultServiceAccountTokenPath on every exchange, so that the rotations of
+// the projected token are picked up.
+func New(opts ...OptFunc) (*Client, error) {
+	p := &Client{
+		requestedTokenType: TokenTypeAccessToken,
+		username:           DefaultUsername,
+		saTokenPath:        DefaultServiceAccountTokenPath,
+		now:                time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.tokenURL == "" {
+		return nil, ErrNoTokenURL
+	}
+	u, err := url.Parse(p.tokenURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid token endpoint URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid token endpoint URL '%s': the scheme must be http or https", p.tokenURL)
+	}
+
+	if p.httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if p.proxyURL != nil {
+			proxyStr := p.proxyURL.String()
+			proxyConfig := &httpproxy.Config{
+				HTTPProxy:  proxyStr,
+				HTTPSProxy: proxyStr,
+			}
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				return proxyConfig.ProxyFunc()(req.URL)
+			}
+		}
+		p.httpClient = &http.Client{Transport: transport}
+	}
+
+	return p, nil
+}
+
+// WithTokenURL sets the URL of the token endpoint of the OIDC provider,
+// e.g. https://idp.example.com/oauth2/token.
+func WithTokenURL(tokenURL string) OptFunc {
+	return func(p *Client) {
+		p.tokenURL = tokenURL
+	}
+}
+
+// WithAudience sets the logical name of the service the token is requested
+// for, e.g. harbor.example.com.
+func WithAudience(audience string) OptFunc {
+	return func(p *Client) {
+		p.audience = audience
+	}
+}
+
+// WithResource sets the URI of the service the token is requested for,
+// e.g. https://harbor.example.com.
+func WithResource(resource string) OptFunc {
+	return func(p *Client) {
+		p.resource = resource
+	}
+}
+
+// WithScopes sets the scopes of the requested token.
+func WithScopes(scopes ...string) OptFunc {
+	return func(p *Client) {
+		p.scopes = scopes
+	}
+}
+
+// WithRequestedTokenType sets the type of the requested token. Defaults to
+// TokenTypeAccessToken.
+func WithRequestedTokenType(tokenType string) OptFunc {
+	return func(p *Client) {
+		p.requestedTokenType = tokenType
+	}
+}
+
+// WithClientCredentials sets the credentials of the client, for the token
+// endpoints requiring the client to authenticate with HTTP basic auth.
+func WithClientCredentials(clientID, clientSecret string) OptFunc {
+	return func(p *Client) {
+		p.clientID = clientID
+		p.clientSecret = clientSecret
+	}
+}
+
+// WithUsername sets the username of the credentials, e.g. the name of the
+// robot account expected by the registry. Defaults to DefaultUsername.
+func WithUsername(username string) OptFunc {
+	return func(p *Client) {
+		p.username = username
+	}
+}
+
+// WithServiceAccountToken sets the ServiceAccount token to exchange, e.g. a
+// token of the ServiceAccount of the object being reconciled issued with
+// the TokenRequest API, instead of the controller's token.
+func WithServiceAccountToken(token string) OptFunc {
+	return func(p *Client) {
+		p.saToken = token
+	}
+}
+
+// WithServiceAccountTokenPath sets the path of the file holding the
+// ServiceAccount token to exchange. Defaults to
+// DefaultServiceAccountTokenPath.
+func WithServiceAccountTokenPath(path string) OptFunc {
+	return func(p *Client) {
+		p.saTokenPath = path
+	}
+}
+
+// WithProxyURL sets the proxy URL to use with the transport.
+func WithProxyURL(proxyURL *url.URL) OptFunc {
+	return func(p *Client) {
+		p.proxyURL = proxyURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the token requests, e.g. to trust
+// the CA of a private OIDC provider.
+func WithHTTPClient(httpClient *http.Client) OptFunc {
+	return func(p *Client) {
+		p.httpClient = httpClient
+	}
+}
+
+// WithGuard configures the guard protecting the token requests with
+// timeouts, retries and a circuit breaker.
+func WithGuard(guard *resilience.Guard) OptFunc {
+	return func(p *Client) {
+		p.guard = guard
+	}
+}
+
+// Token is a token issued by the token endpoint. It implements the Token
+// interface of the cache package, so that it can be stored in a TokenCache.
+type Token struct {
+	// AccessToken is the issued token.
+	AccessToken string
+	// IssuedTokenType is the type of the issued token, e.g.
+	// TokenTypeAccessToken.
+	IssuedTokenType string
+	// TokenType is the type of the access token, e.g. Bearer.
+	TokenType string
+	// ExpiresAt is the expiry of the issued token.
+	ExpiresAt time.Time
+}
+
+// GetDuration returns the remaining validity of the token.
+func (t *Token) GetDuration() time.Duration {
+	return time.Until(t.ExpiresAt)
+}
+
+// Credentials are a username and password for a registry or a Git server,
+// the password being the issued token. They implement the Token interface
+// of the cache package, so that they can be stored in a TokenCache.
+type Credentials struct {
+	Username  string
+	Password  string
+	ExpiresAt time.Time
+}
+
+// GetDuration returns the remaining validity of the credentials.
+func (c *Credentials) GetDuration() time.Duration {
+	return time.Until(c.ExpiresAt)
+}
+
+// ExchangeToken exchanges the ServiceAccount token for a token of the
+// configured audience, resource and scopes.
+func (p *Client) ExchangeToken(ctx context.Context) (*Token, error) {
+	var token *Token
+	err := p.do(ctx, func(ctx context.Context) error {
+		t, err := p.exchangeToken(ctx)
+		if err != nil {
+			return err
+		}
+		token = t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// GetCredentials exchanges the ServiceAccount token, and returns the issued
+// token as the password of the configured username.
+func (p *Client) GetCredentials(ctx context.Context) (*Credentials, error) {
+	token, err := p.ExchangeToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Credentials{
+		Username:  p.username,
+		Password:  token.AccessToken,
+		ExpiresAt: token.ExpiresAt,
+	}, nil
+}
+
+// do calls the request with the guard, if any.
+func (p *Client) do(ctx context.Context, request func(ctx context.Context) error) error {
+	if p.guard == nil {
+		return request(ctx)
+	}
+	return p.guard.Do(ctx, request)
+}
+
+// tokenResponse is the successful response of the token endpoint.
+type tokenResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type"`
+	ExpiresIn       int64  `json:"expires_in"`
+}
+
+func (p *Client) exchangeToken(ctx context.Context) (*Token, error) {
+	subjectToken := p.saToken
+	if subjectToken == "" {
+		b, err := os.ReadFile(p.saTokenPath)
+		if err != nil {
+			return nil, resilience.Permanent(fmt.Errorf("failed to read the ServiceAccount token: %w", err))
+		}
+		subjectToken = strings.TrimSpace(string(b))
+	}
+
+	form := url.Values{
+		"grant_type":           {GrantTypeTokenExchange},
+		"subject_token":        {subjectToken},
+		"subject_token_type":   {TokenTypeJWT},
+		"requested_token_type": {p.requestedTokenType},
+	}
+	if p.audience != "" {
+		form.Set("audience", p.audience)
+	}
+	if p.resource != "" {
+		form.Set("resource", p.resource)
+	}
+	if len(p.scopes) > 0 {
+		form.Set("scope", strings.Join(p.scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if p.clientID != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		err := fmt.Errorf("token exchange failed: status '%s': %s", resp.Status, errorMessage(body))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			// The subject token or the request are rejected, retrying won't help.
+			return nil, resilience.Permanent(err)
+		}
+		return nil, err
+	}
+
+	var result tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBodySize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode the token exchange response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return nil, errors.New("the token exchange response has no access token")
+	}
+
+	ttl := DefaultTokenTTL
+	if result.ExpiresIn > 0 {
+		ttl = time.Duration(result.ExpiresIn) * time.Second
+	}
+	return &Token{
+		AccessToken:     result.AccessToken,
+		IssuedTokenType: result.IssuedTokenType,
+		TokenType:       result.TokenType,
+		ExpiresAt:       p.now().Add(ttl),
+	}, nil
+}
+
+// errorMessage returns the error and its description of an OAuth 2.0 error
+// response, or the body if it is not an OAuth 2.0 error response.
+func errorMessage(body []byte) string {
+	var oauthErr struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &oauthErr); err == nil && oauthErr.Error != "" {
+		if oauthErr.ErrorDescription != "" {
+			return oauthErr.Error + ": " + oauthErr.ErrorDescription
+		}
+		return oauthErr.Error
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/auth/resilience"
+)
+
+// newTestTokenEndpoint returns a fake token endpoint exchanging the given
+// ServiceAccount token for a token of the harbor audience.
+func newTestTokenEndpoint(t *testing.T, saToken string) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := r.ParseForm(); err != nil ||
+			r.Form.Get("grant_type") != GrantTypeTokenExchange ||
+			r.Form.Get("subject_token_type") != TokenTypeJWT ||
+			r.Form.Get("requested_token_type") != TokenTypeAccessToken ||
+			r.Form.Get("audience") != "harbor" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+			return
+		}
+		if r.Form.Get("subject_token") != saToken {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"the subject token is not trusted"}`))
+			return
+		}
+		if id, secret, ok := r.BasicAuth(); ok && (id != "flux" || secret != "s3cr3t") {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		if r.Form.Get("scope") == "no-expiry" {
+			_, _ = w.Write([]byte(`{"access_token":"short-lived","token_type":"Bearer"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"harbor-token","issued_token_type":"` + TokenTypeAccessToken +
+			`","token_type":"Bearer","expires_in":3600}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient_Options(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []OptFunc
+		wantErr string
+	}{
+		{
+			name: "Create new client",
+			opts: []OptFunc{WithTokenURL("https://idp.example.com/oauth2/token")},
+		},
+		{
+			name:    "No token URL",
+			opts:    []OptFunc{WithAudience("harbor")},
+			wantErr: ErrNoTokenURL.Error(),
+		},
+		{
+			name:    "Invalid token URL",
+			opts:    []OptFunc{WithTokenURL("idp.example.com/oauth2/token")},
+			wantErr: "the scheme must be http or https",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := New(tt.opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestClient_ExchangeToken(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := newTestTokenEndpoint(t, "sa-token")
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	g.Expect(os.WriteFile(tokenPath, []byte("sa-token\n"), 0o600)).To(Succeed())
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	client, err := New(
+		WithTokenURL(srv.URL),
+		WithAudience("harbor"),
+		WithClientCredentials("flux", "s3cr3t"),
+		WithServiceAccountTokenPath(tokenPath),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+	client.now = func() time.Time { return now }
+
+	token, err := client.ExchangeToken(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token).To(Equal(&Token{
+		AccessToken:     "harbor-token",
+		IssuedTokenType: TokenTypeAccessToken,
+		TokenType:       "Bearer",
+		ExpiresAt:       now.Add(time.Hour),
+	}))
+
+	// The token is assumed to be short-lived if its lifetime is not returned.
+	client.scopes = []string{"no-expiry"}
+	token, err = client.ExchangeToken(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token.ExpiresAt).To(Equal(now.Add(DefaultTokenTTL)))
+
+	// The ServiceAccount token of the object takes precedence.
+	client.saToken = "object-token"
+	_, err = client.ExchangeToken(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring(
+		"token exchange failed: status '400 Bad Request': invalid_grant: the subject token is not trusted")))
+	g.Expect(resilience.IsPermanent(err)).To(BeTrue())
+}
+
+func TestClient_GetCredentials(t *testing.T) {
+	srv := newTestTokenEndpoint(t, "sa-token")
+
+	tests := []struct {
+		name    string
+		opts    []OptFunc
+		want    *Credentials
+		wantErr string
+	}{
+		{
+			name: "Default username",
+			opts: []OptFunc{WithAudience("harbor")},
+			want: &Credentials{Username: DefaultUsername, Password: "harbor-token"},
+		},
+		{
+			name: "Robot account",
+			opts: []OptFunc{WithAudience("harbor"), WithUsername("robot$flux")},
+			want: &Credentials{Username: "robot$flux", Password: "harbor-token"},
+		},
+		{
+			name:    "Invalid client credentials",
+			opts:    []OptFunc{WithAudience("harbor"), WithClientCredentials("flux", "wrong")},
+			wantErr: "status '401 Unauthorized': invalid_client",
+		},
+		{
+			name:    "Unknown audience",
+			opts:    []OptFunc{WithAudience("artifactory")},
+			wantErr: "status '400 Bad Request': invalid_request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			opts := append([]OptFunc{WithTokenURL(srv.URL), WithServiceAccountToken("sa-token")}, tt.opts...)
+			client, err := New(opts...)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			creds, err := client.GetCredentials(context.Background())
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(creds.Username).To(Equal(tt.want.Username))
+			g.Expect(creds.Password).To(Equal(tt.want.Password))
+			g.Expect(creds.GetDuration()).To(BeNumerically("~", time.Hour, 5*time.Second))
+		})
+	}
+}
+
+func TestClient_guard(t *testing.T) {
+	g := NewWithT(t)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"error":"temporarily_unavailable"}`))
+	}))
+	defer srv.Close()
+
+	guard, err := resilience.New("generic", resilience.WithRetryBudget(2, time.Millisecond))
+	g.Expect(err).ToNot(HaveOccurred())
+	client, err := New(WithTokenURL(srv.URL), WithServiceAccountToken("sa-token"), WithGuard(guard))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = client.ExchangeToken(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("temporarily_unavailable")))
+	// The unavailable token endpoint is retried.
+	g.Expect(requests).To(Equal(3))
+}
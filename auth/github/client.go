@@ -26,6 +26,9 @@ import (
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	"golang.org/x/net/http/httpproxy"
+
+	"github.com/fluxcd/pkg/auth/resilience"
+	"github.com/fluxcd/pkg/cache"
 )
 
 const (
@@ -33,6 +36,13 @@ const (
 	AppInstallationIDKey = "githubAppInstallationID"
 	AppPrivateKey        = "githubAppPrivateKey"
 	AppBaseUrlKey        = "githubAppBaseURL"
+
+	// ProviderName is the name of the provider in the keys of the tokens
+	// stored in a cache.TokenCache.
+	ProviderName = "github"
+	// DefaultAPIURL is the GitHub API endpoint used when none is configured
+	// with WithAppBaseURL.
+	DefaultAPIURL = "https://api.github.com"
 )
 
 // Client is an authentication provider for GitHub Apps.
@@ -43,6 +53,9 @@ type Client struct {
 	apiURL         string
 	proxyURL       *url.URL
 	ghTransport    *ghinstallation.Transport
+	guard          *resilience.Guard
+	tokenCache     *cache.TokenCache
+	involvedObject cache.InvolvedObject
 }
 
 // OptFunc enables specifying options for the provider.
@@ -129,7 +142,9 @@ func WithAppBaseURL(appBaseURL string) OptFunc {
 	}
 }
 
-// WithAppData configures the client using data from a map
+// WithAppData configures the client using data from a map, e.g. the data of
+// the Kubernetes Secret referenced by a Flux object, using the AppIDKey,
+// AppInstallationIDKey, AppPrivateKey and AppBaseUrlKey keys.
 func WithAppData(appData map[string][]byte) OptFunc {
 	return func(p *Client) {
 		val, ok := appData[AppIDKey]
@@ -158,17 +173,89 @@ func WithProxyURL(proxyURL *url.URL) OptFunc {
 	}
 }
 
+// WithGuard configures the guard protecting the token requests with
+// timeouts, retries and a circuit breaker.
+func WithGuard(guard *resilience.Guard) OptFunc {
+	return func(p *Client) {
+		p.guard = guard
+	}
+}
+
+// WithCache configures the cache storing the installation tokens for the
+// given involved object, so that a token is only requested again shortly
+// before it expires. If the involved object is empty, the tokens are stored
+// for the involved object of the context of GetToken, set with
+// cache.WithInvolvedObject.
+func WithCache(tokenCache *cache.TokenCache, involvedObject cache.InvolvedObject) OptFunc {
+	return func(p *Client) {
+		p.tokenCache = tokenCache
+		p.involvedObject = involvedObject
+	}
+}
+
 // AppToken contains a GitHub App installation token and its expiry.
 type AppToken struct {
 	Token     string    `json:"token"`
 	ExpiresAt time.Time `json:"expires_at"`
 }
 
+// GetDuration returns the remaining validity of the token.
+func (t *AppToken) GetDuration() time.Duration {
+	return time.Until(t.ExpiresAt)
+}
+
 // GetToken returns the token that can be used to authenticate
 // as a GitHub App installation.
 // Ref: https://docs.github.com/en/apps/creating-github-apps/authenticating-with-a-github-app/authenticating-as-a-github-app-installation
+//
+// If a cache is configured with WithCache, the token is returned from the
+// cache if present, and stored in the cache otherwise.
 func (p *Client) GetToken(ctx context.Context) (*AppToken, error) {
-	token, err := p.ghTransport.Token(ctx)
+	if p.tokenCache == nil {
+		return p.getToken(ctx)
+	}
+
+	token, _, err := p.tokenCache.GetOrSet(ctx, p.cacheKey(), func(ctx context.Context) (cache.Token, error) {
+		return p.getToken(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token.(*AppToken), nil
+}
+
+// cacheKey returns the key of the installation tokens of the client in the
+// cache. The audience is the installation token endpoint, which identifies
+// the GitHub App, as the installation IDs are unique across apps.
+func (p *Client) cacheKey() cache.TokenKey {
+	apiURL := p.apiURL
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return cache.TokenKey{
+		InvolvedObject: p.involvedObject,
+		Provider:       ProviderName,
+		Audience:       fmt.Sprintf("%s/app/installations/%s/access_tokens", apiURL, p.installationID),
+		Scopes:         []string{p.appID},
+	}
+}
+
+// getToken requests an installation token from the GitHub API.
+func (p *Client) getToken(ctx context.Context) (*AppToken, error) {
+	var token string
+	var err error
+	if p.guard == nil {
+		token, err = p.ghTransport.Token(ctx)
+	} else {
+		err = p.guard.Do(ctx, func(ctx context.Context) error {
+			t, err := p.ghTransport.Token(ctx)
+			if err != nil {
+				return err
+			}
+			token = t
+			return nil
+		})
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -20,12 +20,14 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/fluxcd/pkg/cache"
 	"github.com/fluxcd/pkg/ssh"
 	. "github.com/onsi/gomega"
 )
@@ -221,3 +223,61 @@ func TestClient_GetToken(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_GetToken_WithCache(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		g.Expect(r.URL.Path).To(Equal("/app/installations/123/access_tokens"))
+		w.WriteHeader(http.StatusCreated)
+		response, err := json.Marshal(&AppToken{
+			Token:     fmt.Sprintf("access-token-%d", requests),
+			ExpiresAt: time.Now().UTC().Add(time.Hour),
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		w.Write(response)
+	}))
+	t.Cleanup(srv.Close)
+
+	tokenCache, err := cache.NewTokenCache(10)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer tokenCache.Close()
+
+	kp, err := ssh.GenerateKeyPair(ssh.RSA_4096)
+	g.Expect(err).ToNot(HaveOccurred())
+	newClient := func(name string) *Client {
+		var obj cache.InvolvedObject
+		if name != "" {
+			obj = cache.InvolvedObject{Kind: "GitRepository", Name: name, Namespace: "default"}
+		}
+		client, err := New(WithAppBaseURL(srv.URL), WithInstllationID("123"), WithAppID("456"),
+			WithPrivateKey(kp.PrivateKey), WithCache(tokenCache, obj))
+		g.Expect(err).ToNot(HaveOccurred())
+		return client
+	}
+
+	// The token is cached across the clients of the same object.
+	for range 2 {
+		token, err := newClient("app").GetToken(context.TODO())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(token.Token).To(Equal("access-token-1"))
+		g.Expect(token.GetDuration()).To(BeNumerically("~", time.Hour, time.Minute))
+	}
+	g.Expect(requests).To(Equal(1))
+
+	token, err := newClient("other").GetToken(context.TODO())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token.Token).To(Equal("access-token-2"))
+	g.Expect(requests).To(Equal(2))
+
+	// Without an involved object, the token is cached for the involved
+	// object of the context.
+	ctx := cache.WithInvolvedObject(context.TODO(),
+		cache.InvolvedObject{Kind: "GitRepository", Name: "app", Namespace: "default"})
+	token, err = newClient("").GetToken(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token.Token).To(Equal("access-token-1"))
+	g.Expect(requests).To(Equal(2))
+}
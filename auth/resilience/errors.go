@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resilience
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorReason is a type that represents the reason for a provider error.
+type ErrorReason struct {
+	reason string
+	msg    string
+}
+
+// Error gives a human-readable description of the error.
+func (e ErrorReason) Error() string {
+	return e.msg
+}
+
+// ProviderError is the error returned by Guard.Do when a request to a
+// provider could not be completed.
+type ProviderError struct {
+	Provider string
+	Reason   ErrorReason
+	Err      error
+}
+
+// Error returns Err as a string, prefixed with the Provider and the Reason
+// to provide context.
+func (e *ProviderError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("%s: %s", e.Provider, e.Reason.Error())
+	}
+	return fmt.Sprintf("%s: %s: %s", e.Provider, e.Reason.Error(), e.Err.Error())
+}
+
+// Is returns true if the Reason or Err equals target:
+//
+//	err := guard.Do(ctx, fetchToken)
+//	if errors.Is(err, resilience.ErrCircuitOpen) {
+//		// requeue without reporting a failure
+//	}
+func (e *ProviderError) Is(target error) bool {
+	if e.Reason == target {
+		return true
+	}
+	return errors.Is(e.Err, target)
+}
+
+// Unwrap returns the underlying Err.
+func (e *ProviderError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrTimeout is the Reason of the ProviderError returned when the last
+	// request to the provider timed out.
+	ErrTimeout = ErrorReason{"Timeout", "request timed out"}
+	// ErrRetryBudgetExhausted is the Reason of the ProviderError returned
+	// when all the attempts allowed by the retry budget failed.
+	ErrRetryBudgetExhausted = ErrorReason{"RetryBudgetExhausted", "retry budget exhausted"}
+	// ErrCircuitOpen is the Reason of the ProviderError returned without
+	// contacting the provider while the circuit breaker is open.
+	ErrCircuitOpen = ErrorReason{"CircuitOpen", "circuit breaker is open"}
+)
+
+// permanentError marks an error that must not be retried.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps an error returned by a request to signal that it must not
+// be retried, e.g. because the credentials are invalid. Permanent errors do
+// not count as failures for the circuit breaker, as the provider did respond.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent returns true if the error has been wrapped with Permanent.
+func IsPermanent(err error) bool {
+	var perr *permanentError
+	return errors.As(err, &perr)
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resilience protects the requests made to authentication providers,
+// e.g. cloud metadata or STS endpoints, with per-request timeouts, a retry
+// budget and a circuit breaker. During an outage of a provider, the circuit
+// breaker opens after a number of consecutive failures and requests fail
+// fast with ErrCircuitOpen until the cool-down elapses, instead of stalling
+// reconciliations.
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultTimeout is the default timeout of a single request.
+	DefaultTimeout = 10 * time.Second
+	// DefaultRetryBudget is the default number of times a failed request is
+	// retried.
+	DefaultRetryBudget = 2
+	// DefaultRetryInterval is the default time waited before the first
+	// retry. It doubles with every retry.
+	DefaultRetryInterval = 500 * time.Millisecond
+	// DefaultFailureThreshold is the default number of consecutive failures
+	// after which the circuit breaker opens.
+	DefaultFailureThreshold = 5
+	// DefaultCoolDown is the default time the circuit breaker stays open.
+	DefaultCoolDown = time.Minute
+)
+
+// circuitState is the state of a circuit breaker.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// Guard runs the requests to an authentication provider. It is safe for
+// concurrent use, and a single Guard should be used for all the requests to
+// the same provider so that they share the circuit breaker.
+type Guard struct {
+	provider         string
+	timeout          time.Duration
+	retryBudget      int
+	retryInterval    time.Duration
+	failureThreshold int
+	coolDown         time.Duration
+	metrics          *Metrics
+	now              func() time.Time
+
+	mu        sync.Mutex
+	state     circuitState
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// OptFunc enables specifying options for the guard.
+type OptFunc func(*Guard)
+
+// New returns a new Guard for the given provider, e.g. "aws" or "azure".
+func New(provider string, opts ...OptFunc) (*Guard, error) {
+	g := &Guard{
+		provider:         provider,
+		timeout:          DefaultTimeout,
+		retryBudget:      DefaultRetryBudget,
+		retryInterval:    DefaultRetryInterval,
+		failureThreshold: DefaultFailureThreshold,
+		coolDown:         DefaultCoolDown,
+		now:              time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if provider == "" {
+		return nil, errors.New("provider name must be provided")
+	}
+	if g.timeout <= 0 {
+		return nil, fmt.Errorf("invalid timeout %s, must be greater than zero", g.timeout)
+	}
+	if g.retryBudget < 0 {
+		return nil, fmt.Errorf("invalid retry budget %d, must not be negative", g.retryBudget)
+	}
+	if g.failureThreshold < 0 {
+		return nil, fmt.Errorf("invalid failure threshold %d, must not be negative", g.failureThreshold)
+	}
+	if g.failureThreshold > 0 && g.coolDown <= 0 {
+		return nil, fmt.Errorf("invalid cool-down %s, must be greater than zero", g.coolDown)
+	}
+
+	return g, nil
+}
+
+// WithTimeout configures the timeout of a single request to the provider.
+func WithTimeout(timeout time.Duration) OptFunc {
+	return func(g *Guard) {
+		g.timeout = timeout
+	}
+}
+
+// WithRetryBudget configures the number of times a failed request is
+// retried, and the time waited before the first retry, which doubles with
+// every retry. A budget of zero disables retries.
+func WithRetryBudget(retries int, interval time.Duration) OptFunc {
+	return func(g *Guard) {
+		g.retryBudget = retries
+		g.retryInterval = interval
+	}
+}
+
+// WithCircuitBreaker configures the number of consecutive failed requests
+// after which the circuit breaker opens, and the time it stays open before
+// a single request is let through to probe the provider. A threshold of
+// zero disables the circuit breaker.
+func WithCircuitBreaker(threshold int, coolDown time.Duration) OptFunc {
+	return func(g *Guard) {
+		g.failureThreshold = threshold
+		g.coolDown = coolDown
+	}
+}
+
+// WithMetrics configures the metrics the requests are recorded with.
+func WithMetrics(m *Metrics) OptFunc {
+	return func(g *Guard) {
+		g.metrics = m
+	}
+}
+
+// Provider returns the name of the provider.
+func (g *Guard) Provider() string {
+	return g.provider
+}
+
+// Do calls the given request function, with a context that times out after
+// the configured timeout, until it succeeds or the retry budget is
+// exhausted. Errors wrapped with Permanent are not retried. The request is
+// not made if the circuit breaker is open, and a ProviderError with
+// ErrCircuitOpen is returned instead.
+func (g *Guard) Do(ctx context.Context, request func(ctx context.Context) error) error {
+	start := g.now()
+	if err := g.allow(); err != nil {
+		g.metrics.recordRequest(g.provider, StatusRejected, 0)
+		return err
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = g.attempt(ctx, request)
+		if err == nil {
+			g.succeeded()
+			g.metrics.recordRequest(g.provider, StatusSuccess, g.now().Sub(start))
+			return nil
+		}
+		if IsPermanent(err) {
+			// The provider responded, it is not a failure of the provider.
+			g.succeeded()
+			g.metrics.recordRequest(g.provider, StatusFailure, g.now().Sub(start))
+			return err
+		}
+		if ctx.Err() != nil {
+			g.cancelled()
+			return err
+		}
+		if attempt >= g.retryBudget {
+			break
+		}
+
+		g.metrics.recordRetry(g.provider)
+		select {
+		case <-ctx.Done():
+			g.cancelled()
+			return err
+		case <-time.After(g.retryInterval << attempt):
+		}
+	}
+
+	g.failed()
+	status := StatusFailure
+	if errors.Is(err, ErrTimeout) {
+		status = StatusTimeout
+	}
+	g.metrics.recordRequest(g.provider, status, g.now().Sub(start))
+
+	if g.retryBudget > 0 {
+		return &ProviderError{Provider: g.provider, Reason: ErrRetryBudgetExhausted, Err: err}
+	}
+	return err
+}
+
+// attempt calls the request function once. It returns a ProviderError with
+// ErrTimeout if the request does not return before the timeout, even if the
+// request function does not honour the context.
+func (g *Guard) attempt(ctx context.Context, request func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- request(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && !IsPermanent(err) {
+		return &ProviderError{
+			Provider: g.provider,
+			Reason:   ErrTimeout,
+			Err:      fmt.Errorf("no response after %s: %w", g.timeout, err),
+		}
+	}
+	return err
+}
+
+// allow returns an error if the circuit breaker is open. Once the cool-down
+// elapses, a single request is allowed to probe the provider.
+func (g *Guard) allow() error {
+	if g.failureThreshold == 0 {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	switch g.state {
+	case circuitOpen:
+		now := g.now()
+		if now.Before(g.openUntil) {
+			return &ProviderError{
+				Provider: g.provider,
+				Reason:   ErrCircuitOpen,
+				Err:      fmt.Errorf("retry after %s", g.openUntil.Sub(now).Round(time.Second)),
+			}
+		}
+		g.state = circuitHalfOpen
+		g.probing = true
+	case circuitHalfOpen:
+		if g.probing {
+			return &ProviderError{
+				Provider: g.provider,
+				Reason:   ErrCircuitOpen,
+				Err:      errors.New("waiting for the provider to recover"),
+			}
+		}
+		g.probing = true
+	}
+	return nil
+}
+
+// succeeded closes the circuit breaker.
+func (g *Guard) succeeded() {
+	if g.failureThreshold == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.failures = 0
+	g.probing = false
+	if g.state != circuitClosed {
+		g.state = circuitClosed
+		g.metrics.setCircuitOpen(g.provider, false)
+	}
+}
+
+// failed records a failure, and opens the circuit breaker if the failure
+// threshold is reached or if the request was probing the provider.
+func (g *Guard) failed() {
+	if g.failureThreshold == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.failures++
+	g.probing = false
+	if g.state == circuitHalfOpen || g.failures >= g.failureThreshold {
+		g.state = circuitOpen
+		g.openUntil = g.now().Add(g.coolDown)
+		g.metrics.setCircuitOpen(g.provider, true)
+	}
+}
+
+// cancelled lets another request probe the provider if the probing request
+// was cancelled by the caller.
+func (g *Guard) cancelled() {
+	if g.failureThreshold == 0 {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.probing = false
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestGuard_Do(t *testing.T) {
+	errProvider := errors.New("metadata endpoint unavailable")
+
+	tests := []struct {
+		name      string
+		opts      []OptFunc
+		responses []error
+		wantCalls int
+		wantErr   []error
+	}{
+		{
+			name:      "succeeds",
+			responses: []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "succeeds after retries",
+			opts:      []OptFunc{WithRetryBudget(2, time.Millisecond)},
+			responses: []error{errProvider, errProvider, nil},
+			wantCalls: 3,
+		},
+		{
+			name:      "exhausts the retry budget",
+			opts:      []OptFunc{WithRetryBudget(2, time.Millisecond)},
+			responses: []error{errProvider, errProvider, errProvider},
+			wantCalls: 3,
+			wantErr:   []error{ErrRetryBudgetExhausted, errProvider},
+		},
+		{
+			name:      "does not retry without budget",
+			opts:      []OptFunc{WithRetryBudget(0, 0)},
+			responses: []error{errProvider},
+			wantCalls: 1,
+			wantErr:   []error{errProvider},
+		},
+		{
+			name:      "does not retry permanent errors",
+			opts:      []OptFunc{WithRetryBudget(2, time.Millisecond)},
+			responses: []error{Permanent(errProvider)},
+			wantCalls: 1,
+			wantErr:   []error{errProvider},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			guard, err := New("test", tt.opts...)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var calls int
+			err = guard.Do(context.TODO(), func(ctx context.Context) error {
+				calls++
+				return tt.responses[calls-1]
+			})
+			g.Expect(calls).To(Equal(tt.wantCalls))
+			if tt.wantErr == nil {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			for _, want := range tt.wantErr {
+				g.Expect(err).To(MatchError(want))
+			}
+		})
+	}
+}
+
+func TestGuard_Timeout(t *testing.T) {
+	g := NewWithT(t)
+
+	guard, err := New("test", WithTimeout(10*time.Millisecond), WithRetryBudget(0, 0))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The request does not honour the context.
+	block := make(chan struct{})
+	defer close(block)
+	err = guard.Do(context.TODO(), func(ctx context.Context) error {
+		<-block
+		return nil
+	})
+	g.Expect(err).To(MatchError(ErrTimeout))
+	g.Expect(err).To(MatchError(context.DeadlineExceeded))
+
+	var perr *ProviderError
+	g.Expect(errors.As(err, &perr)).To(BeTrue())
+	g.Expect(perr.Provider).To(Equal("test"))
+}
+
+func TestGuard_CircuitBreaker(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Now()
+	reg := prometheus.NewPedanticRegistry()
+	metrics := NewMetrics("", reg)
+	guard, err := New("test",
+		WithRetryBudget(0, 0),
+		WithCircuitBreaker(2, time.Minute),
+		WithMetrics(metrics))
+	g.Expect(err).ToNot(HaveOccurred())
+	guard.now = func() time.Time { return now }
+
+	var calls int
+	fail := func(ctx context.Context) error {
+		calls++
+		return errors.New("unavailable")
+	}
+	succeed := func(ctx context.Context) error {
+		calls++
+		return nil
+	}
+
+	// The circuit opens after two consecutive failures.
+	g.Expect(guard.Do(context.TODO(), fail)).ToNot(MatchError(ErrCircuitOpen))
+	g.Expect(guard.Do(context.TODO(), fail)).ToNot(MatchError(ErrCircuitOpen))
+	g.Expect(testutil.ToFloat64(metrics.circuitOpenGauge.WithLabelValues("test"))).To(Equal(float64(1)))
+
+	// The requests are rejected without calling the provider.
+	g.Expect(guard.Do(context.TODO(), succeed)).To(MatchError(ErrCircuitOpen))
+	g.Expect(calls).To(Equal(2))
+	g.Expect(testutil.ToFloat64(metrics.requestsCounter.WithLabelValues("test", StatusRejected))).To(Equal(float64(1)))
+
+	// A failed probe after the cool-down opens the circuit again.
+	now = now.Add(time.Minute)
+	g.Expect(guard.Do(context.TODO(), fail)).ToNot(MatchError(ErrCircuitOpen))
+	g.Expect(guard.Do(context.TODO(), succeed)).To(MatchError(ErrCircuitOpen))
+	g.Expect(calls).To(Equal(3))
+
+	// A successful probe closes the circuit.
+	now = now.Add(time.Minute)
+	g.Expect(guard.Do(context.TODO(), succeed)).To(Succeed())
+	g.Expect(guard.Do(context.TODO(), succeed)).To(Succeed())
+	g.Expect(calls).To(Equal(5))
+	g.Expect(testutil.ToFloat64(metrics.circuitOpenGauge.WithLabelValues("test"))).To(Equal(float64(0)))
+	g.Expect(testutil.ToFloat64(metrics.requestsCounter.WithLabelValues("test", StatusFailure))).To(Equal(float64(3)))
+	g.Expect(testutil.ToFloat64(metrics.requestsCounter.WithLabelValues("test", StatusSuccess))).To(Equal(float64(2)))
+}
+
+func TestNew_Validation(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := New("")
+	g.Expect(err).To(HaveOccurred())
+	_, err = New("test", WithTimeout(0))
+	g.Expect(err).To(HaveOccurred())
+	_, err = New("test", WithRetryBudget(-1, 0))
+	g.Expect(err).To(HaveOccurred())
+	_, err = New("test", WithCircuitBreaker(1, 0))
+	g.Expect(err).To(HaveOccurred())
+	_, err = New("test", WithCircuitBreaker(0, 0))
+	g.Expect(err).ToNot(HaveOccurred())
+}
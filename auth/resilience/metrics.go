@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resilience
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	// StatusSuccess is the status for successful requests.
+	StatusSuccess = "success"
+	// StatusFailure is the status for failed requests.
+	StatusFailure = "failure"
+	// StatusTimeout is the status for requests that timed out.
+	StatusTimeout = "timeout"
+	// StatusRejected is the status for requests rejected by an open circuit
+	// breaker.
+	StatusRejected = "rejected"
+)
+
+// Metrics holds the metrics of the requests made through Guards. A single
+// Metrics should be shared by all the Guards of a controller, the metrics
+// are partitioned by provider.
+type Metrics struct {
+	requestsCounter  *prometheus.CounterVec
+	retriesCounter   *prometheus.CounterVec
+	durationObserver *prometheus.HistogramVec
+	circuitOpenGauge *prometheus.GaugeVec
+}
+
+// NewMetrics returns a new Metrics, registered with the given registerer.
+// The names of the metrics are prefixed with the given prefix, e.g.
+// "gotk_".
+func NewMetrics(prefix string, reg prometheus.Registerer) *Metrics {
+	return &Metrics{
+		requestsCounter: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("%sauth_requests_total", prefix),
+				Help: "Total number of requests to authentication providers partitioned by status.",
+			},
+			[]string{"provider", "status"},
+		),
+		retriesCounter: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("%sauth_retries_total", prefix),
+				Help: "Total number of retried requests to authentication providers.",
+			},
+			[]string{"provider"},
+		),
+		durationObserver: promauto.With(reg).NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name:    fmt.Sprintf("%sauth_request_duration_seconds", prefix),
+				Help:    "The duration in seconds of requests to authentication providers, including retries.",
+				Buckets: prometheus.ExponentialBuckets(0.05, 2, 10),
+			},
+			[]string{"provider"},
+		),
+		circuitOpenGauge: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: fmt.Sprintf("%sauth_circuit_open", prefix),
+				Help: "Whether the circuit breaker of an authentication provider is open (1) or closed (0).",
+			},
+			[]string{"provider"},
+		),
+	}
+}
+
+func (m *Metrics) recordRequest(provider, status string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.requestsCounter.WithLabelValues(provider, status).Inc()
+	if status != StatusRejected {
+		m.durationObserver.WithLabelValues(provider).Observe(duration.Seconds())
+	}
+}
+
+func (m *Metrics) recordRetry(provider string) {
+	if m == nil {
+		return
+	}
+	m.retriesCounter.WithLabelValues(provider).Inc()
+}
+
+func (m *Metrics) setCircuitOpen(provider string, open bool) {
+	if m == nil {
+		return
+	}
+	var v float64
+	if open {
+		v = 1
+	}
+	m.circuitOpenGauge.WithLabelValues(provider).Set(v)
+}
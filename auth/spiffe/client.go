@@ -0,0 +1,400 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package spiffe provides an authentication provider for clusters using
+// SPIFFE workload identities, e.g. issued by SPIRE. It fetches an SVID of
+// the controller from the SPIFFE Workload API, and exchanges it for a
+// short-lived access token of a Git server or container registry at a
+// federation endpoint implementing the OAuth 2.0 token exchange (RFC 8693).
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/fluxcd/pkg/auth/resilience"
+)
+
+const (
+	// DefaultSocketPath is the address of the Workload API used when
+	// neither WithSocketPath nor the SPIFFE_ENDPOINT_SOCKET environment
+	// variable are set.
+	DefaultSocketPath = "unix:///run/spire/sockets/agent.sock"
+
+	grantTypeTokenExchange      = "urn:ietf:params:oauth:grant-type:token-exchange"
+	grantTypeClientCredentials  = "client_credentials"
+	tokenTypeJWT                = "urn:ietf:params:oauth:token-type:jwt"
+	tokenTypeAccessToken        = "urn:ietf:params:oauth:token-type:access_token"
+	maxExchangeErrorBodyLength  = 4096
+	maxExchangeResponseBodySize = 1 << 20
+)
+
+// SVIDType is the type of the SVID exchanged for the access token.
+type SVIDType string
+
+const (
+	// SVIDTypeJWT exchanges a JWT-SVID as the subject token of an OAuth 2.0
+	// token exchange.
+	SVIDTypeJWT SVIDType = "jwt"
+	// SVIDTypeX509 authenticates to the federation endpoint with an
+	// X.509-SVID as TLS client certificate, and requests the access token
+	// with the client credentials grant.
+	SVIDTypeX509 SVIDType = "x509"
+)
+
+// ErrNoExchangeURL is returned by New when no federation endpoint has been
+// configured using WithExchangeURL.
+var ErrNoExchangeURL = errors.New("the URL of the token exchange endpoint must be provided")
+
+// Client is an authentication provider for SPIFFE workload identities.
+type Client struct {
+	socketPath  string
+	svidType    SVIDType
+	spiffeID    string
+	audience    []string
+	scopes      []string
+	exchangeURL string
+	proxyURL    *url.URL
+	workloadAPI WorkloadAPI
+	httpClient  *http.Client
+	guard       *resilience.Guard
+	now         func() time.Time
+}
+
+// OptFunc enables specifying options for the provider.
+type OptFunc func(*Client)
+
+// New returns a new authentication provider for SPIFFE workload identities.
+// The Workload API is contacted at the address set with WithSocketPath, or
+// in the SPIFFE_ENDPOINT_SOCKET environment variable, or at
+// DefaultSocketPath.
+func New(opts ...OptFunc) (*Client, error) {
+	p := &Client{
+		svidType: SVIDTypeJWT,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.exchangeURL == "" {
+		return nil, ErrNoExchangeURL
+	}
+	if _, err := url.Parse(p.exchangeURL); err != nil {
+		return nil, fmt.Errorf("invalid token exchange URL: %w", err)
+	}
+	switch p.svidType {
+	case SVIDTypeJWT:
+		if len(p.audience) == 0 {
+			return nil, errors.New("the audience must be provided to use JWT-SVIDs")
+		}
+	case SVIDTypeX509:
+	default:
+		return nil, fmt.Errorf("unsupported SVID type '%s'", p.svidType)
+	}
+
+	if p.workloadAPI == nil {
+		if p.socketPath == "" {
+			p.socketPath = os.Getenv(EndpointSocketEnvVar)
+		}
+		if p.socketPath == "" {
+			p.socketPath = DefaultSocketPath
+		}
+		workloadAPI, err := NewWorkloadAPIClient(p.socketPath)
+		if err != nil {
+			return nil, err
+		}
+		p.workloadAPI = workloadAPI
+	}
+
+	if p.httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if p.proxyURL != nil {
+			proxyStr := p.proxyURL.String()
+			proxyConfig := &httpproxy.Config{
+				HTTPProxy:  proxyStr,
+				HTTPSProxy: proxyStr,
+			}
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				return proxyConfig.ProxyFunc()(req.URL)
+			}
+		}
+		p.httpClient = &http.Client{Transport: transport}
+	}
+
+	return p, nil
+}
+
+// WithSocketPath sets the address of the Workload API, e.g.
+// unix:///run/spire/sockets/agent.sock.
+func WithSocketPath(socketPath string) OptFunc {
+	return func(p *Client) {
+		p.socketPath = socketPath
+	}
+}
+
+// WithSVIDType sets the type of the SVID exchanged for the access token.
+// Defaults to SVIDTypeJWT.
+func WithSVIDType(svidType SVIDType) OptFunc {
+	return func(p *Client) {
+		p.svidType = svidType
+	}
+}
+
+// WithSPIFFEID selects the SVID with the given SPIFFE ID, when the workload
+// is entitled to several. By default, the first SVID returned by the
+// Workload API is used.
+func WithSPIFFEID(id string) OptFunc {
+	return func(p *Client) {
+		p.spiffeID = id
+	}
+}
+
+// WithAudience sets the audience of the JWT-SVID, i.e. the federation
+// endpoint, which is also sent as the audience of the token exchange
+// request.
+func WithAudience(audience ...string) OptFunc {
+	return func(p *Client) {
+		p.audience = audience
+	}
+}
+
+// WithScopes sets the scopes of the requested access token.
+func WithScopes(scopes ...string) OptFunc {
+	return func(p *Client) {
+		p.scopes = scopes
+	}
+}
+
+// WithExchangeURL sets the URL of the federation endpoint exchanging the
+// SVIDs for access tokens.
+func WithExchangeURL(exchangeURL string) OptFunc {
+	return func(p *Client) {
+		p.exchangeURL = exchangeURL
+	}
+}
+
+// WithProxyURL sets the proxy URL to use with the transport.
+func WithProxyURL(proxyURL *url.URL) OptFunc {
+	return func(p *Client) {
+		p.proxyURL = proxyURL
+	}
+}
+
+// WithWorkloadAPI sets the client of the Workload API, instead of
+// connecting to the socket.
+func WithWorkloadAPI(workloadAPI WorkloadAPI) OptFunc {
+	return func(p *Client) {
+		p.workloadAPI = workloadAPI
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the token exchange requests. The
+// TLS client certificate of the X.509-SVIDs is configured on a clone of its
+// transport.
+func WithHTTPClient(httpClient *http.Client) OptFunc {
+	return func(p *Client) {
+		p.httpClient = httpClient
+	}
+}
+
+// WithGuard configures the guard protecting the token requests with
+// timeouts, retries and a circuit breaker.
+func WithGuard(guard *resilience.Guard) OptFunc {
+	return func(p *Client) {
+		p.guard = guard
+	}
+}
+
+// Token is an access token obtained with an SVID. It implements the Token
+// interface of the cache package, so that it can be stored in a TokenCache.
+type Token struct {
+	// SPIFFEID is the SPIFFE ID of the SVID exchanged for the token.
+	SPIFFEID string
+	// AccessToken is the access token of the Git server or registry.
+	AccessToken string
+	// ExpiresAt is the expiry of the access token.
+	ExpiresAt time.Time
+}
+
+// GetDuration returns the remaining validity of the token.
+func (t *Token) GetDuration() time.Duration {
+	return time.Until(t.ExpiresAt)
+}
+
+// Close closes the connection to the Workload API.
+func (p *Client) Close() error {
+	if c, ok := p.workloadAPI.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// GetToken fetches an SVID from the Workload API and exchanges it for an
+// access token at the federation endpoint.
+func (p *Client) GetToken(ctx context.Context) (*Token, error) {
+	if p.guard == nil {
+		return p.getToken(ctx)
+	}
+
+	var token *Token
+	err := p.guard.Do(ctx, func(ctx context.Context) error {
+		t, err := p.getToken(ctx)
+		if err != nil {
+			return err
+		}
+		token = t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (p *Client) getToken(ctx context.Context) (*Token, error) {
+	form := url.Values{}
+	for _, aud := range p.audience {
+		form.Add("audience", aud)
+	}
+	if len(p.scopes) > 0 {
+		form.Set("scope", strings.Join(p.scopes, " "))
+	}
+
+	httpClient := p.httpClient
+	var spiffeID string
+	var svidExpiresAt time.Time
+	switch p.svidType {
+	case SVIDTypeX509:
+		svid, err := p.workloadAPI.FetchX509SVID(ctx, p.spiffeID)
+		if err != nil {
+			return nil, err
+		}
+		spiffeID = svid.ID
+		svidExpiresAt = svid.Certificates[0].NotAfter
+		httpClient, err = p.mtlsClient(svid)
+		if err != nil {
+			return nil, err
+		}
+		form.Set("grant_type", grantTypeClientCredentials)
+		form.Set("client_id", svid.ID)
+	default:
+		svid, err := p.workloadAPI.FetchJWTSVID(ctx, p.audience, p.spiffeID)
+		if err != nil {
+			return nil, err
+		}
+		spiffeID = svid.ID
+		svidExpiresAt = svid.ExpiresAt
+		form.Set("grant_type", grantTypeTokenExchange)
+		form.Set("subject_token", svid.Token)
+		form.Set("subject_token_type", tokenTypeJWT)
+		form.Set("requested_token_type", tokenTypeAccessToken)
+	}
+
+	resp, err := p.exchange(ctx, httpClient, form)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := svidExpiresAt
+	if resp.ExpiresIn > 0 {
+		expiresAt = p.now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return &Token{
+		SPIFFEID:    spiffeID,
+		AccessToken: resp.AccessToken,
+		ExpiresAt:   expiresAt,
+	}, nil
+}
+
+// mtlsClient returns a client of the federation endpoint presenting the
+// X.509-SVID as TLS client certificate.
+func (p *Client) mtlsClient(svid *X509SVID) (*http.Client, error) {
+	transport, ok := p.httpClient.Transport.(*http.Transport)
+	if !ok {
+		if p.httpClient.Transport != nil {
+			return nil, fmt.Errorf("unsupported transport type %T for X.509-SVIDs", p.httpClient.Transport)
+		}
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	cert := tls.Certificate{PrivateKey: svid.PrivateKey, Leaf: svid.Certificates[0]}
+	for _, c := range svid.Certificates {
+		cert.Certificate = append(cert.Certificate, c.Raw)
+	}
+	transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+
+	client := *p.httpClient
+	client.Transport = transport
+	return &client, nil
+}
+
+// exchangeResponse is the successful response of the token exchange
+// endpoint.
+type exchangeResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// exchange sends the token request to the federation endpoint.
+func (p *Client) exchange(ctx context.Context, httpClient *http.Client, form url.Values) (*exchangeResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.exchangeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token exchange request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxExchangeErrorBodyLength))
+		err := fmt.Errorf("token exchange failed with status '%s': %s", resp.Status, strings.TrimSpace(string(body)))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			// The SVID or the request are rejected, retrying won't help.
+			return nil, resilience.Permanent(err)
+		}
+		return nil, err
+	}
+
+	var result exchangeResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxExchangeResponseBodySize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode token exchange response: %w", err)
+	}
+	if result.AccessToken == "" {
+		return nil, errors.New("the token exchange response has no access token")
+	}
+	return &result, nil
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spiffe
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/auth/resilience"
+)
+
+const testSPIFFEID = "spiffe://example.org/ns/flux-system/sa/source-controller"
+
+type fakeWorkloadAPI struct {
+	jwtSVID  *JWTSVID
+	x509SVID *X509SVID
+	err      error
+	audience []string
+	id       string
+}
+
+func (f *fakeWorkloadAPI) FetchJWTSVID(_ context.Context, audience []string, id string) (*JWTSVID, error) {
+	f.audience = audience
+	f.id = id
+	return f.jwtSVID, f.err
+}
+
+func (f *fakeWorkloadAPI) FetchX509SVID(_ context.Context, id string) (*X509SVID, error) {
+	f.id = id
+	return f.x509SVID, f.err
+}
+
+func TestClient_Options(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []OptFunc
+		wantErr string
+	}{
+		{
+			name: "Create new client",
+			opts: []OptFunc{WithExchangeURL("https://sts.example.com/token"), WithAudience("sts.example.com")},
+		},
+		{
+			name: "Create new client with X.509-SVIDs",
+			opts: []OptFunc{WithExchangeURL("https://sts.example.com/token"), WithSVIDType(SVIDTypeX509)},
+		},
+		{
+			name: "Create new client with socket path",
+			opts: []OptFunc{WithExchangeURL("https://sts.example.com/token"), WithAudience("sts.example.com"),
+				WithSocketPath("/tmp/agent.sock")},
+		},
+		{
+			name:    "No exchange URL",
+			opts:    []OptFunc{WithAudience("sts.example.com")},
+			wantErr: ErrNoExchangeURL.Error(),
+		},
+		{
+			name:    "No audience",
+			opts:    []OptFunc{WithExchangeURL("https://sts.example.com/token")},
+			wantErr: "the audience must be provided",
+		},
+		{
+			name:    "Unsupported SVID type",
+			opts:    []OptFunc{WithExchangeURL("https://sts.example.com/token"), WithSVIDType("oidc")},
+			wantErr: "unsupported SVID type 'oidc'",
+		},
+		{
+			name: "Unsupported socket address",
+			opts: []OptFunc{WithExchangeURL("https://sts.example.com/token"), WithAudience("sts.example.com"),
+				WithSocketPath("agent.sock")},
+			wantErr: "unsupported Workload API address 'agent.sock'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client, err := New(tt.opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(client.Close()).To(Succeed())
+		})
+	}
+}
+
+func TestClient_GetToken_JWT(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Form.Get("grant_type") != grantTypeTokenExchange ||
+			r.Form.Get("subject_token") != "jwt-svid" ||
+			r.Form.Get("subject_token_type") != tokenTypeJWT ||
+			r.Form.Get("audience") != "sts.example.com" ||
+			r.Form.Get("scope") != "repo:read registry:pull" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   600,
+		})
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	workloadAPI := &fakeWorkloadAPI{jwtSVID: &JWTSVID{
+		ID:        testSPIFFEID,
+		Token:     "jwt-svid",
+		ExpiresAt: now.Add(5 * time.Minute),
+	}}
+	client, err := New(
+		WithExchangeURL(srv.URL),
+		WithAudience("sts.example.com"),
+		WithScopes("repo:read", "registry:pull"),
+		WithSPIFFEID(testSPIFFEID),
+		WithWorkloadAPI(workloadAPI),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+	client.now = func() time.Time { return now }
+
+	token, err := client.GetToken(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token).To(Equal(&Token{
+		SPIFFEID:    testSPIFFEID,
+		AccessToken: "access-token",
+		ExpiresAt:   now.Add(10 * time.Minute),
+	}))
+	g.Expect(workloadAPI.audience).To(Equal([]string{"sts.example.com"}))
+	g.Expect(workloadAPI.id).To(Equal(testSPIFFEID))
+
+	// The token expires with the SVID if the endpoint doesn't return an
+	// expiry.
+	noExpiry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token":"access-token"}`))
+	}))
+	defer noExpiry.Close()
+	client.exchangeURL = noExpiry.URL
+	token, err = client.GetToken(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token.ExpiresAt).To(Equal(now.Add(5 * time.Minute)))
+}
+
+func TestClient_GetToken_X509(t *testing.T) {
+	g := NewWithT(t)
+
+	svid := newTestX509SVID(t, testSPIFFEID)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		certs := r.TLS.PeerCertificates
+		if len(certs) == 0 || len(certs[0].URIs) == 0 || certs[0].URIs[0].String() != testSPIFFEID ||
+			r.Form.Get("grant_type") != grantTypeClientCredentials ||
+			r.Form.Get("client_id") != testSPIFFEID {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"access-token","expires_in":60}`))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	client, err := New(
+		WithExchangeURL(srv.URL),
+		WithSVIDType(SVIDTypeX509),
+		WithHTTPClient(srv.Client()),
+		WithWorkloadAPI(&fakeWorkloadAPI{x509SVID: svid}),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	token, err := client.GetToken(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token.SPIFFEID).To(Equal(testSPIFFEID))
+	g.Expect(token.AccessToken).To(Equal("access-token"))
+	g.Expect(token.GetDuration()).To(BeNumerically("~", time.Minute, 5*time.Second))
+
+	// The HTTP client is not modified.
+	g.Expect(srv.Client().Transport.(*http.Transport).TLSClientConfig.Certificates).To(BeEmpty())
+}
+
+func TestClient_GetToken_errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		body          string
+		workloadErr   error
+		wantErr       string
+		wantPermanent bool
+	}{
+		{
+			name:        "Workload API error",
+			workloadErr: errors.New("no identity issued"),
+			wantErr:     "no identity issued",
+		},
+		{
+			name:          "SVID rejected",
+			status:        http.StatusUnauthorized,
+			body:          `{"error":"invalid_grant"}`,
+			wantErr:       `token exchange failed with status '401 Unauthorized': {"error":"invalid_grant"}`,
+			wantPermanent: true,
+		},
+		{
+			name:    "Server error",
+			status:  http.StatusServiceUnavailable,
+			wantErr: "token exchange failed with status '503 Service Unavailable'",
+		},
+		{
+			name:    "No access token",
+			status:  http.StatusOK,
+			body:    `{"token_type":"Bearer"}`,
+			wantErr: "the token exchange response has no access token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			client, err := New(
+				WithExchangeURL(srv.URL),
+				WithAudience("sts.example.com"),
+				WithWorkloadAPI(&fakeWorkloadAPI{
+					jwtSVID: &JWTSVID{ID: testSPIFFEID, Token: "jwt-svid"},
+					err:     tt.workloadErr,
+				}),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = client.GetToken(context.Background())
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+			g.Expect(resilience.IsPermanent(err)).To(Equal(tt.wantPermanent))
+		})
+	}
+}
+
+func TestClient_GetToken_guard(t *testing.T) {
+	g := NewWithT(t)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	guard, err := resilience.New("spiffe", resilience.WithRetryBudget(2, time.Millisecond))
+	g.Expect(err).ToNot(HaveOccurred())
+	client, err := New(
+		WithExchangeURL(srv.URL),
+		WithAudience("sts.example.com"),
+		WithWorkloadAPI(&fakeWorkloadAPI{jwtSVID: &JWTSVID{ID: testSPIFFEID, Token: "jwt-svid"}}),
+		WithGuard(guard),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = client.GetToken(context.Background())
+	g.Expect(err).To(HaveOccurred())
+	// The rejected SVID is not retried.
+	g.Expect(requests).To(Equal(1))
+}
+
+// newTestX509SVID returns an X.509-SVID with the given SPIFFE ID, issued by
+// a self-signed CA.
+func newTestX509SVID(t *testing.T, id string) *X509SVID {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "example.org"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, caKey.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uri, err := url.Parse(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{uri},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, key.Public(), caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &X509SVID{
+		ID:           id,
+		Certificates: []*x509.Certificate{cert},
+		PrivateKey:   crypto.Signer(key),
+		Bundle:       []*x509.Certificate{ca},
+	}
+}
@@ -0,0 +1,470 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spiffe
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// EndpointSocketEnvVar is the environment variable holding the address
+	// of the Workload API, as defined by the SPIFFE specification.
+	EndpointSocketEnvVar = "SPIFFE_ENDPOINT_SOCKET"
+
+	// workloadAPIHeader is the metadata key the Workload API requires in
+	// every request, to protect against server-side request forgery.
+	workloadAPIHeader = "workload.spiffe.io"
+
+	fetchJWTSVIDMethod  = "/SpiffeWorkloadAPI/FetchJWTSVID"
+	fetchX509SVIDMethod = "/SpiffeWorkloadAPI/FetchX509SVID"
+)
+
+// JWTSVID is a JWT-SVID fetched from the Workload API.
+type JWTSVID struct {
+	// ID is the SPIFFE ID of the SVID, e.g. spiffe://example.org/flux.
+	ID string
+	// Token is the signed JWT.
+	Token string
+	// ExpiresAt is the expiry of the JWT.
+	ExpiresAt time.Time
+}
+
+// X509SVID is an X.509-SVID fetched from the Workload API.
+type X509SVID struct {
+	// ID is the SPIFFE ID of the SVID, e.g. spiffe://example.org/flux.
+	ID string
+	// Certificates is the certificate chain of the SVID, leaf first.
+	Certificates []*x509.Certificate
+	// PrivateKey is the private key of the leaf certificate.
+	PrivateKey crypto.Signer
+	// Bundle is the X.509 bundle of the trust domain of the SVID.
+	Bundle []*x509.Certificate
+}
+
+// WorkloadAPI fetches the SVIDs of the workload.
+type WorkloadAPI interface {
+	// FetchJWTSVID returns a JWT-SVID for the given audience. If id is
+	// empty, the default SVID of the workload is returned.
+	FetchJWTSVID(ctx context.Context, audience []string, id string) (*JWTSVID, error)
+	// FetchX509SVID returns an X.509-SVID. If id is empty, the default SVID
+	// of the workload is returned.
+	FetchX509SVID(ctx context.Context, id string) (*X509SVID, error)
+}
+
+// WorkloadAPIClient is a client of the SPIFFE Workload API served by an
+// agent, e.g. SPIRE, on a Unix domain socket.
+type WorkloadAPIClient struct {
+	conn *grpc.ClientConn
+}
+
+// NewWorkloadAPIClient returns a client of the Workload API at the given
+// address, e.g. unix:///run/spire/sockets/agent.sock or tcp://127.0.0.1:8081.
+// A path without a scheme is a Unix domain socket. The connection is
+// established on the first request.
+func NewWorkloadAPIClient(addr string) (*WorkloadAPIClient, error) {
+	target, err := workloadAPITarget(addr)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(codec{})))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Workload API client: %w", err)
+	}
+	return &WorkloadAPIClient{conn: conn}, nil
+}
+
+// workloadAPITarget returns the gRPC target of the Workload API address.
+func workloadAPITarget(addr string) (string, error) {
+	switch {
+	case addr == "":
+		return "", errors.New("the Workload API address is empty")
+	case strings.HasPrefix(addr, "unix:"):
+		return addr, nil
+	case strings.HasPrefix(addr, "tcp://"):
+		return "passthrough:///" + strings.TrimPrefix(addr, "tcp://"), nil
+	case strings.HasPrefix(addr, "/"):
+		return "unix://" + addr, nil
+	default:
+		return "", fmt.Errorf("unsupported Workload API address '%s'", addr)
+	}
+}
+
+// Close closes the connection to the Workload API.
+func (c *WorkloadAPIClient) Close() error {
+	return c.conn.Close()
+}
+
+// FetchJWTSVID returns a JWT-SVID for the given audience.
+func (c *WorkloadAPIClient) FetchJWTSVID(ctx context.Context, audience []string, id string) (*JWTSVID, error) {
+	ctx = metadata.AppendToOutgoingContext(ctx, workloadAPIHeader, "true")
+	req := &jwtSVIDRequest{audience: audience, spiffeID: id}
+	resp := &jwtSVIDResponse{}
+	if err := c.conn.Invoke(ctx, fetchJWTSVIDMethod, req, resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWT-SVID: %w", err)
+	}
+	if len(resp.svids) == 0 {
+		return nil, errors.New("the Workload API returned no JWT-SVID")
+	}
+
+	svid := resp.svids[0]
+	expiresAt, err := jwtExpiry(svid.svid)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT-SVID '%s': %w", svid.spiffeID, err)
+	}
+	return &JWTSVID{
+		ID:        svid.spiffeID,
+		Token:     svid.svid,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// FetchX509SVID returns an X.509-SVID from the first response of the
+// Workload API stream.
+func (c *WorkloadAPIClient) FetchX509SVID(ctx context.Context, id string) (*X509SVID, error) {
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(ctx, workloadAPIHeader, "true"))
+	defer cancel()
+
+	desc := &grpc.StreamDesc{StreamName: "FetchX509SVID", ServerStreams: true}
+	stream, err := c.conn.NewStream(ctx, desc, fetchX509SVIDMethod)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch X.509-SVID: %w", err)
+	}
+	if err := stream.SendMsg(&x509SVIDRequest{}); err != nil {
+		return nil, fmt.Errorf("failed to fetch X.509-SVID: %w", err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, fmt.Errorf("failed to fetch X.509-SVID: %w", err)
+	}
+	resp := &x509SVIDResponse{}
+	if err := stream.RecvMsg(resp); err != nil {
+		return nil, fmt.Errorf("failed to fetch X.509-SVID: %w", err)
+	}
+
+	for _, svid := range resp.svids {
+		if id != "" && svid.spiffeID != id {
+			continue
+		}
+		return parseX509SVID(svid)
+	}
+	if id != "" {
+		return nil, fmt.Errorf("the Workload API returned no X.509-SVID with ID '%s'", id)
+	}
+	return nil, errors.New("the Workload API returned no X.509-SVID")
+}
+
+// parseX509SVID decodes the DER certificates and key of the SVID.
+func parseX509SVID(svid x509SVID) (*X509SVID, error) {
+	certs, err := x509.ParseCertificates(svid.x509SVID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificates of X.509-SVID '%s': %w", svid.spiffeID, err)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("X.509-SVID '%s' has no certificate", svid.spiffeID)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(svid.x509SVIDKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key of X.509-SVID '%s': %w", svid.spiffeID, err)
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T of X.509-SVID '%s'", key, svid.spiffeID)
+	}
+	bundle, err := x509.ParseCertificates(svid.bundle)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bundle of X.509-SVID '%s': %w", svid.spiffeID, err)
+	}
+	return &X509SVID{
+		ID:           svid.spiffeID,
+		Certificates: certs,
+		PrivateKey:   signer,
+		Bundle:       bundle,
+	}, nil
+}
+
+// jwtExpiry returns the expiry of the JWT from its exp claim. The signature
+// is not verified, the JWT being issued to the workload by its agent.
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, errors.New("malformed JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed JWT payload: %w", err)
+	}
+	var claims struct {
+		Exp *json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("malformed JWT claims: %w", err)
+	}
+	if claims.Exp == nil {
+		return time.Time{}, errors.New("the JWT has no exp claim")
+	}
+	exp, err := claims.Exp.Float64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid exp claim: %w", err)
+	}
+	return time.Unix(int64(exp), 0), nil
+}
+
+// message is a message of the Workload API. The messages are encoded with
+// protowire, which avoids depending on the generated code of the SPIFFE
+// protobuf definitions.
+type message interface {
+	marshal() []byte
+	unmarshal(b []byte) error
+}
+
+// codec is the gRPC codec of the Workload API messages.
+type codec struct{}
+
+func (codec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(message)
+	if !ok {
+		return nil, fmt.Errorf("unsupported message type %T", v)
+	}
+	return m.marshal(), nil
+}
+
+func (codec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(message)
+	if !ok {
+		return fmt.Errorf("unsupported message type %T", v)
+	}
+	return m.unmarshal(data)
+}
+
+func (codec) Name() string {
+	return "proto"
+}
+
+// jwtSVIDRequest is the JWTSVIDRequest message.
+type jwtSVIDRequest struct {
+	audience []string
+	spiffeID string
+}
+
+func (m *jwtSVIDRequest) marshal() []byte {
+	var b []byte
+	for _, aud := range m.audience {
+		b = appendString(b, 1, aud)
+	}
+	return appendString(b, 2, m.spiffeID)
+}
+
+func (m *jwtSVIDRequest) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v []byte) error {
+		switch num {
+		case 1:
+			m.audience = append(m.audience, string(v))
+		case 2:
+			m.spiffeID = string(v)
+		}
+		return nil
+	})
+}
+
+// jwtSVIDResponse is the JWTSVIDResponse message.
+type jwtSVIDResponse struct {
+	svids []jwtSVID
+}
+
+func (m *jwtSVIDResponse) marshal() []byte {
+	var b []byte
+	for _, svid := range m.svids {
+		b = appendBytes(b, 1, svid.marshal())
+	}
+	return b
+}
+
+func (m *jwtSVIDResponse) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v []byte) error {
+		if num == 1 {
+			var svid jwtSVID
+			if err := svid.unmarshal(v); err != nil {
+				return err
+			}
+			m.svids = append(m.svids, svid)
+		}
+		return nil
+	})
+}
+
+// jwtSVID is the JWTSVID message.
+type jwtSVID struct {
+	spiffeID string
+	svid     string
+	hint     string
+}
+
+func (m *jwtSVID) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.spiffeID)
+	b = appendString(b, 2, m.svid)
+	return appendString(b, 3, m.hint)
+}
+
+func (m *jwtSVID) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v []byte) error {
+		switch num {
+		case 1:
+			m.spiffeID = string(v)
+		case 2:
+			m.svid = string(v)
+		case 3:
+			m.hint = string(v)
+		}
+		return nil
+	})
+}
+
+// x509SVIDRequest is the X509SVIDRequest message, which has no field.
+type x509SVIDRequest struct{}
+
+func (m *x509SVIDRequest) marshal() []byte {
+	return nil
+}
+
+func (m *x509SVIDRequest) unmarshal(b []byte) error {
+	return consumeFields(b, func(protowire.Number, []byte) error { return nil })
+}
+
+// x509SVIDResponse is the X509SVIDResponse message, without the CRLs and
+// the federated bundles.
+type x509SVIDResponse struct {
+	svids []x509SVID
+}
+
+func (m *x509SVIDResponse) marshal() []byte {
+	var b []byte
+	for _, svid := range m.svids {
+		b = appendBytes(b, 1, svid.marshal())
+	}
+	return b
+}
+
+func (m *x509SVIDResponse) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v []byte) error {
+		if num == 1 {
+			var svid x509SVID
+			if err := svid.unmarshal(v); err != nil {
+				return err
+			}
+			m.svids = append(m.svids, svid)
+		}
+		return nil
+	})
+}
+
+// x509SVID is the X509SVID message.
+type x509SVID struct {
+	spiffeID    string
+	x509SVID    []byte
+	x509SVIDKey []byte
+	bundle      []byte
+	hint        string
+}
+
+func (m *x509SVID) marshal() []byte {
+	var b []byte
+	b = appendString(b, 1, m.spiffeID)
+	b = appendBytes(b, 2, m.x509SVID)
+	b = appendBytes(b, 3, m.x509SVIDKey)
+	b = appendBytes(b, 4, m.bundle)
+	return appendString(b, 5, m.hint)
+}
+
+func (m *x509SVID) unmarshal(b []byte) error {
+	return consumeFields(b, func(num protowire.Number, v []byte) error {
+		switch num {
+		case 1:
+			m.spiffeID = string(v)
+		case 2:
+			m.x509SVID = v
+		case 3:
+			m.x509SVIDKey = v
+		case 4:
+			m.bundle = v
+		case 5:
+			m.hint = string(v)
+		}
+		return nil
+	})
+}
+
+// appendString appends the string field, omitted if empty.
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+// appendBytes appends the bytes field, omitted if empty.
+func appendBytes(b []byte, num protowire.Number, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// consumeFields calls fn with the value of each length-delimited field of
+// the message, and skips the fields of the other wire types.
+func consumeFields(b []byte, fn func(num protowire.Number, v []byte) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			b = b[n:]
+			continue
+		}
+		v, n := protowire.ConsumeBytes(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		if err := fn(num, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spiffe
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// startWorkloadAPI serves a fake Workload API on a Unix domain socket, and
+// returns the address of the socket.
+func startWorkloadAPI(t *testing.T, jwtSVIDs []jwtSVID, x509SVIDs []x509SVID) string {
+	t.Helper()
+
+	checkHeader := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if !slices.Equal(md.Get(workloadAPIHeader), []string{"true"}) {
+			return status.Error(codes.InvalidArgument, "security header missing from request")
+		}
+		return nil
+	}
+
+	srv := grpc.NewServer(grpc.ForceServerCodec(codec{}))
+	srv.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "SpiffeWorkloadAPI",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "FetchJWTSVID",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				if err := checkHeader(ctx); err != nil {
+					return nil, err
+				}
+				req := &jwtSVIDRequest{}
+				if err := dec(req); err != nil {
+					return nil, err
+				}
+				if len(req.audience) == 0 {
+					return nil, status.Error(codes.InvalidArgument, "audience must be specified")
+				}
+				resp := &jwtSVIDResponse{}
+				for _, svid := range jwtSVIDs {
+					if req.spiffeID == "" || req.spiffeID == svid.spiffeID {
+						resp.svids = append(resp.svids, svid)
+					}
+				}
+				return resp, nil
+			},
+		}},
+		Streams: []grpc.StreamDesc{{
+			StreamName:    "FetchX509SVID",
+			ServerStreams: true,
+			Handler: func(_ any, stream grpc.ServerStream) error {
+				if err := checkHeader(stream.Context()); err != nil {
+					return err
+				}
+				if err := stream.RecvMsg(&x509SVIDRequest{}); err != nil {
+					return err
+				}
+				if err := stream.SendMsg(&x509SVIDResponse{svids: x509SVIDs}); err != nil {
+					return err
+				}
+				// The Workload API streams the updates of the SVIDs until
+				// the client cancels the request.
+				<-stream.Context().Done()
+				return nil
+			},
+		}},
+	}, struct{}{})
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = srv.Serve(l)
+	}()
+	t.Cleanup(srv.Stop)
+
+	return "unix://" + socketPath
+}
+
+func TestWorkloadAPIClient_FetchJWTSVID(t *testing.T) {
+	g := NewWithT(t)
+
+	expiresAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	token := testJWT(`{"sub":"` + testSPIFFEID + `","exp":` + "1767225600" + `}`)
+	addr := startWorkloadAPI(t, []jwtSVID{
+		{spiffeID: testSPIFFEID, svid: token},
+		{spiffeID: "spiffe://example.org/other", svid: testJWT(`{"exp":1}`)},
+	}, nil)
+
+	client, err := NewWorkloadAPIClient(addr)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer client.Close()
+
+	svid, err := client.FetchJWTSVID(context.Background(), []string{"sts.example.com"}, "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(svid).To(Equal(&JWTSVID{
+		ID:        testSPIFFEID,
+		Token:     token,
+		ExpiresAt: expiresAt.Local(),
+	}))
+
+	svid, err = client.FetchJWTSVID(context.Background(), []string{"sts.example.com"}, "spiffe://example.org/other")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(svid.ID).To(Equal("spiffe://example.org/other"))
+
+	_, err = client.FetchJWTSVID(context.Background(), nil, "")
+	g.Expect(err).To(MatchError(ContainSubstring("audience must be specified")))
+
+	_, err = client.FetchJWTSVID(context.Background(), []string{"sts.example.com"}, "spiffe://example.org/unknown")
+	g.Expect(err).To(MatchError("the Workload API returned no JWT-SVID"))
+}
+
+func TestWorkloadAPIClient_FetchX509SVID(t *testing.T) {
+	g := NewWithT(t)
+
+	want := newTestX509SVID(t, testSPIFFEID)
+	key, err := x509.MarshalPKCS8PrivateKey(want.PrivateKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	addr := startWorkloadAPI(t, nil, []x509SVID{{
+		spiffeID:    testSPIFFEID,
+		x509SVID:    want.Certificates[0].Raw,
+		x509SVIDKey: key,
+		bundle:      want.Bundle[0].Raw,
+	}})
+
+	client, err := NewWorkloadAPIClient(addr)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer client.Close()
+
+	svid, err := client.FetchX509SVID(context.Background(), "")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(svid.ID).To(Equal(testSPIFFEID))
+	g.Expect(svid.Certificates).To(HaveLen(1))
+	g.Expect(svid.Certificates[0].Equal(want.Certificates[0])).To(BeTrue())
+	g.Expect(svid.Bundle).To(HaveLen(1))
+	g.Expect(svid.Bundle[0].Equal(want.Bundle[0])).To(BeTrue())
+	g.Expect(svid.PrivateKey.Public()).To(Equal(want.PrivateKey.Public()))
+
+	_, err = client.FetchX509SVID(context.Background(), "spiffe://example.org/unknown")
+	g.Expect(err).To(MatchError("the Workload API returned no X.509-SVID with ID 'spiffe://example.org/unknown'"))
+}
+
+func Test_workloadAPITarget(t *testing.T) {
+	tests := []struct {
+		addr    string
+		want    string
+		wantErr string
+	}{
+		{addr: "unix:///run/spire/sockets/agent.sock", want: "unix:///run/spire/sockets/agent.sock"},
+		{addr: "/run/spire/sockets/agent.sock", want: "unix:///run/spire/sockets/agent.sock"},
+		{addr: "tcp://127.0.0.1:8081", want: "passthrough:///127.0.0.1:8081"},
+		{addr: "", wantErr: "the Workload API address is empty"},
+		{addr: "https://spire.example.com", wantErr: "unsupported Workload API address"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := workloadAPITarget(tt.addr)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_jwtExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	exp, err := jwtExpiry(testJWT(`{"exp":1767225600}`))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exp.UTC()).To(Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = jwtExpiry(testJWT(`{"sub":"flux"}`))
+	g.Expect(err).To(MatchError("the JWT has no exp claim"))
+
+	_, err = jwtExpiry("not-a-jwt")
+	g.Expect(err).To(MatchError("malformed JWT"))
+}
+
+// testJWT returns an unsigned JWT with the given claims.
+func testJWT(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"ES256"}`)) + "." + enc.EncodeToString([]byte(claims)) + ".c2ln"
+}
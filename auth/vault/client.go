@@ -0,0 +1,438 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package vault provides an authentication provider for HashiCorp Vault. It
+// logs in to Vault with a Kubernetes ServiceAccount token using the JWT auth
+// method, and reads the credentials issued by Vault, e.g. the dynamic
+// credentials of a database or the static credentials of a container
+// registry stored in a KV secrets engine.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/fluxcd/pkg/auth/resilience"
+)
+
+const (
+	// DefaultMountPath is the path of the JWT auth method used when none is
+	// configured using WithMountPath.
+	DefaultMountPath = "jwt"
+
+	// DefaultServiceAccountTokenPath is the path of the token of the
+	// controller's ServiceAccount, used when no token is configured using
+	// WithServiceAccountToken.
+	DefaultServiceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+	maxErrorBodyLength  = 4096
+	maxResponseBodySize = 1 << 20
+)
+
+var (
+	// ErrNoAddress is returned by New when the address of Vault has not
+	// been configured using WithAddress.
+	ErrNoAddress = errors.New("the address of Vault must be provided")
+
+	// ErrNoRole is returned by New when the role of the JWT auth method has
+	// not been configured using WithRole.
+	ErrNoRole = errors.New("the role of the JWT auth method must be provided")
+)
+
+// Client is an authentication provider for HashiCorp Vault.
+type Client struct {
+	address     string
+	role        string
+	mountPath   string
+	namespace   string
+	saToken     string
+	saTokenPath string
+	proxyURL    *url.URL
+	httpClient  *http.Client
+	guard       *resilience.Guard
+	now         func() time.Time
+}
+
+// OptFunc enables specifying options for the provider.
+type OptFunc func(*Client)
+
+// New returns a new authentication provider for HashiCorp Vault. By default,
+// it logs in with the token of the controller's ServiceAccount, read from
+// DefaultServiceAccountTokenPath on every login, so that the rotations of
+// the projected token are picked up.
+func New(opts ...OptFunc) (*Client, error) {
+	p := &Client{
+		mountPath:   DefaultMountPath,
+		saTokenPath: DefaultServiceAccountTokenPath,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.address == "" {
+		return nil, ErrNoAddress
+	}
+	u, err := url.Parse(p.address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Vault address: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid Vault address '%s': the scheme must be http or https", p.address)
+	}
+	p.address = strings.TrimSuffix(p.address, "/")
+	if p.role == "" {
+		return nil, ErrNoRole
+	}
+	p.mountPath = strings.Trim(p.mountPath, "/")
+
+	if p.httpClient == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		if p.proxyURL != nil {
+			proxyStr := p.proxyURL.String()
+			proxyConfig := &httpproxy.Config{
+				HTTPProxy:  proxyStr,
+				HTTPSProxy: proxyStr,
+			}
+			transport.Proxy = func(req *http.Request) (*url.URL, error) {
+				return proxyConfig.ProxyFunc()(req.URL)
+			}
+		}
+		p.httpClient = &http.Client{Transport: transport}
+	}
+
+	return p, nil
+}
+
+// WithAddress sets the address of Vault, e.g. https://vault.example.com:8200.
+func WithAddress(address string) OptFunc {
+	return func(p *Client) {
+		p.address = address
+	}
+}
+
+// WithRole sets the role of the JWT auth method to log in with.
+func WithRole(role string) OptFunc {
+	return func(p *Client) {
+		p.role = role
+	}
+}
+
+// WithMountPath sets the path of the JWT auth method, e.g. kubernetes-jwt.
+// Defaults to DefaultMountPath.
+func WithMountPath(mountPath string) OptFunc {
+	return func(p *Client) {
+		p.mountPath = mountPath
+	}
+}
+
+// WithNamespace sets the Vault Enterprise namespace of the requests.
+func WithNamespace(namespace string) OptFunc {
+	return func(p *Client) {
+		p.namespace = namespace
+	}
+}
+
+// WithServiceAccountToken sets the ServiceAccount token to log in with,
+// e.g. a token of the ServiceAccount of the object being reconciled issued
+// with the TokenRequest API, instead of the controller's token.
+func WithServiceAccountToken(token string) OptFunc {
+	return func(p *Client) {
+		p.saToken = token
+	}
+}
+
+// WithServiceAccountTokenPath sets the path of the file holding the
+// ServiceAccount token to log in with. Defaults to
+// DefaultServiceAccountTokenPath.
+func WithServiceAccountTokenPath(path string) OptFunc {
+	return func(p *Client) {
+		p.saTokenPath = path
+	}
+}
+
+// WithProxyURL sets the proxy URL to use with the transport.
+func WithProxyURL(proxyURL *url.URL) OptFunc {
+	return func(p *Client) {
+		p.proxyURL = proxyURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client of the Vault requests, e.g. to trust
+// the CA of a private Vault server.
+func WithHTTPClient(httpClient *http.Client) OptFunc {
+	return func(p *Client) {
+		p.httpClient = httpClient
+	}
+}
+
+// WithGuard configures the guard protecting the Vault requests with
+// timeouts, retries and a circuit breaker.
+func WithGuard(guard *resilience.Guard) OptFunc {
+	return func(p *Client) {
+		p.guard = guard
+	}
+}
+
+// Token is a Vault token obtained by logging in with a ServiceAccount
+// token. It implements the Token interface of the cache package, so that it
+// can be stored in a TokenCache.
+type Token struct {
+	// ClientToken is the Vault token.
+	ClientToken string
+	// Accessor is the accessor of the Vault token, which can be logged to
+	// identify the token without revealing it.
+	Accessor string
+	// ExpiresAt is the expiry of the Vault token.
+	ExpiresAt time.Time
+}
+
+// GetDuration returns the remaining validity of the token.
+func (t *Token) GetDuration() time.Duration {
+	return time.Until(t.ExpiresAt)
+}
+
+// Secret is a secret read from Vault.
+type Secret struct {
+	// Data is the data of the secret. The data of the secrets of the KV
+	// version 2 secrets engine is nested under the "data" key.
+	Data map[string]any
+	// LeaseID is the ID of the lease of dynamic credentials.
+	LeaseID string
+	// ExpiresAt is the expiry of the lease of the secret, or zero if the
+	// secret has no lease.
+	ExpiresAt time.Time
+}
+
+// Credentials are a username and password issued by Vault, e.g. the
+// credentials of a container registry. They implement the Token interface
+// of the cache package, so that they can be stored in a TokenCache.
+type Credentials struct {
+	Username string
+	Password string
+	// ExpiresAt is the expiry of the lease of the credentials, or the
+	// expiry of the Vault token used to read them if they have no lease.
+	ExpiresAt time.Time
+}
+
+// GetDuration returns the remaining validity of the credentials.
+func (c *Credentials) GetDuration() time.Duration {
+	return time.Until(c.ExpiresAt)
+}
+
+// Login logs in to Vault with the ServiceAccount token using the JWT auth
+// method, and returns the Vault token.
+func (p *Client) Login(ctx context.Context) (*Token, error) {
+	var token *Token
+	err := p.do(ctx, func(ctx context.Context) error {
+		t, err := p.login(ctx)
+		if err != nil {
+			return err
+		}
+		token = t
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// ReadSecret reads the secret at the given path, e.g.
+// database/creds/readonly, with the Vault token.
+func (p *Client) ReadSecret(ctx context.Context, token *Token, path string) (*Secret, error) {
+	var secret *Secret
+	err := p.do(ctx, func(ctx context.Context) error {
+		s, err := p.readSecret(ctx, token, path)
+		if err != nil {
+			return err
+		}
+		secret = s
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// GetCredentials logs in to Vault, and returns the username and password of
+// the secret at the given path, e.g. the dynamic credentials of a secrets
+// engine, or registry credentials stored in a KV secrets engine.
+func (p *Client) GetCredentials(ctx context.Context, path string) (*Credentials, error) {
+	token, err := p.Login(ctx)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := p.ReadSecret(ctx, token, path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]any); ok && data["username"] == nil {
+		// KV version 2 secrets engine.
+		data = nested
+	}
+	username, _ := data["username"].(string)
+	password, _ := data["password"].(string)
+	if username == "" || password == "" {
+		return nil, fmt.Errorf("the secret at '%s' has no username and password", path)
+	}
+
+	expiresAt := secret.ExpiresAt
+	if expiresAt.IsZero() {
+		expiresAt = token.ExpiresAt
+	}
+	return &Credentials{
+		Username:  username,
+		Password:  password,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// do calls the request with the guard, if any.
+func (p *Client) do(ctx context.Context, request func(ctx context.Context) error) error {
+	if p.guard == nil {
+		return request(ctx)
+	}
+	return p.guard.Do(ctx, request)
+}
+
+// response is the response of the Vault API.
+type response struct {
+	LeaseID       string         `json:"lease_id"`
+	LeaseDuration int64          `json:"lease_duration"`
+	Data          map[string]any `json:"data"`
+	Auth          *struct {
+		ClientToken   string `json:"client_token"`
+		Accessor      string `json:"accessor"`
+		LeaseDuration int64  `json:"lease_duration"`
+	} `json:"auth"`
+}
+
+func (p *Client) login(ctx context.Context) (*Token, error) {
+	jwt := p.saToken
+	if jwt == "" {
+		b, err := os.ReadFile(p.saTokenPath)
+		if err != nil {
+			return nil, resilience.Permanent(fmt.Errorf("failed to read the ServiceAccount token: %w", err))
+		}
+		jwt = strings.TrimSpace(string(b))
+	}
+
+	body, err := json.Marshal(map[string]string{
+		"role": p.role,
+		"jwt":  jwt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.request(ctx, http.MethodPost, "auth/"+p.mountPath+"/login", "", body)
+	if err != nil {
+		return nil, fmt.Errorf("login to Vault failed: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return nil, errors.New("the Vault login response has no client token")
+	}
+	return &Token{
+		ClientToken: resp.Auth.ClientToken,
+		Accessor:    resp.Auth.Accessor,
+		ExpiresAt:   p.now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second),
+	}, nil
+}
+
+func (p *Client) readSecret(ctx context.Context, token *Token, path string) (*Secret, error) {
+	resp, err := p.request(ctx, http.MethodGet, strings.Trim(path, "/"), token.ClientToken, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the Vault secret at '%s': %w", path, err)
+	}
+	secret := &Secret{
+		Data:    resp.Data,
+		LeaseID: resp.LeaseID,
+	}
+	if resp.LeaseDuration > 0 {
+		secret.ExpiresAt = p.now().Add(time.Duration(resp.LeaseDuration) * time.Second)
+	}
+	return secret, nil
+}
+
+// request sends a request to the Vault API, and decodes its response.
+func (p *Client) request(ctx context.Context, method, path, vaultToken string, body []byte) (*response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.address+"/v1/"+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if vaultToken != "" {
+		req.Header.Set("X-Vault-Token", vaultToken)
+	}
+	if p.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.namespace)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyLength))
+		err := fmt.Errorf("status '%s': %s", resp.Status, errorMessage(body))
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+			// The token or the request are rejected, retrying won't help.
+			return nil, resilience.Permanent(err)
+		}
+		return nil, err
+	}
+
+	var result response
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseBodySize)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode Vault response: %w", err)
+	}
+	return &result, nil
+}
+
+// errorMessage returns the errors of a Vault error response, or the body
+// if it is not a Vault error response.
+func errorMessage(body []byte) string {
+	var vaultErr struct {
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &vaultErr); err == nil && len(vaultErr.Errors) > 0 {
+		return strings.Join(vaultErr.Errors, "; ")
+	}
+	return strings.TrimSpace(string(body))
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/auth/resilience"
+)
+
+// newTestVault returns a fake Vault server accepting the given
+// ServiceAccount token for the flux role, and serving the given secrets.
+func newTestVault(t *testing.T, saToken string, secrets map[string]string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/auth/jwt/login", func(w http.ResponseWriter, r *http.Request) {
+		var req map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil ||
+			req["role"] != "flux" || req["jwt"] != saToken ||
+			r.Header.Get("X-Vault-Namespace") != "tenant" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or JWT"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"hvs.token","accessor":"accessor","lease_duration":3600}}`))
+	})
+	mux.HandleFunc("GET /v1/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "hvs.token" {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		secret, ok := secrets[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(secret))
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestClient_Options(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []OptFunc
+		wantErr string
+	}{
+		{
+			name: "Create new client",
+			opts: []OptFunc{WithAddress("https://vault.example.com:8200"), WithRole("flux")},
+		},
+		{
+			name:    "No address",
+			opts:    []OptFunc{WithRole("flux")},
+			wantErr: ErrNoAddress.Error(),
+		},
+		{
+			name:    "Invalid address",
+			opts:    []OptFunc{WithAddress("vault.example.com"), WithRole("flux")},
+			wantErr: "the scheme must be http or https",
+		},
+		{
+			name:    "No role",
+			opts:    []OptFunc{WithAddress("https://vault.example.com:8200")},
+			wantErr: ErrNoRole.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			_, err := New(tt.opts...)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestClient_Login(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := newTestVault(t, "sa-token", nil)
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	g.Expect(os.WriteFile(tokenPath, []byte("sa-token\n"), 0o600)).To(Succeed())
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	client, err := New(
+		WithAddress(srv.URL+"/"),
+		WithRole("flux"),
+		WithMountPath("/jwt/"),
+		WithNamespace("tenant"),
+		WithServiceAccountTokenPath(tokenPath),
+	)
+	g.Expect(err).ToNot(HaveOccurred())
+	client.now = func() time.Time { return now }
+
+	token, err := client.Login(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(token).To(Equal(&Token{
+		ClientToken: "hvs.token",
+		Accessor:    "accessor",
+		ExpiresAt:   now.Add(time.Hour),
+	}))
+
+	// The ServiceAccount token of the object takes precedence.
+	client.saToken = "object-token"
+	_, err = client.Login(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("login to Vault failed: status '400 Bad Request': invalid role or JWT")))
+	g.Expect(resilience.IsPermanent(err)).To(BeTrue())
+}
+
+func TestClient_GetCredentials(t *testing.T) {
+	srv := newTestVault(t, "sa-token", map[string]string{
+		"/v1/registry/creds/flux": `{"lease_id":"registry/creds/flux/123","lease_duration":600,` +
+			`"data":{"username":"v-flux","password":"secret"}}`,
+		"/v1/secret/data/registry": `{"data":{"data":{"username":"flux","password":"static"},"metadata":{"version":1}}}`,
+		"/v1/secret/data/invalid":  `{"data":{"data":{"token":"secret"}}}`,
+	})
+
+	tests := []struct {
+		name    string
+		path    string
+		want    *Credentials
+		wantErr string
+	}{
+		{
+			name: "Dynamic credentials",
+			path: "registry/creds/flux",
+			want: &Credentials{Username: "v-flux", Password: "secret", ExpiresAt: time.Date(2026, 1, 1, 0, 10, 0, 0, time.UTC)},
+		},
+		{
+			name: "KV version 2 credentials",
+			path: "/secret/data/registry",
+			want: &Credentials{Username: "flux", Password: "static", ExpiresAt: time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:    "No username and password",
+			path:    "secret/data/invalid",
+			wantErr: "the secret at 'secret/data/invalid' has no username and password",
+		},
+		{
+			name:    "Not found",
+			path:    "secret/data/unknown",
+			wantErr: "failed to read the Vault secret at 'secret/data/unknown': status '404 Not Found'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client, err := New(
+				WithAddress(srv.URL),
+				WithRole("flux"),
+				WithNamespace("tenant"),
+				WithServiceAccountToken("sa-token"),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+			client.now = func() time.Time { return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+			creds, err := client.GetCredentials(context.Background(), tt.path)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(creds).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestClient_ReadSecret(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := newTestVault(t, "sa-token", map[string]string{
+		"/v1/database/creds/readonly": `{"lease_id":"database/creds/readonly/abc","lease_duration":60,` +
+			`"data":{"username":"v-readonly","password":"secret"}}`,
+	})
+	client, err := New(WithAddress(srv.URL), WithRole("flux"), WithNamespace("tenant"), WithServiceAccountToken("sa-token"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	token, err := client.Login(context.Background())
+	g.Expect(err).ToNot(HaveOccurred())
+	secret, err := client.ReadSecret(context.Background(), token, "database/creds/readonly")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(secret.LeaseID).To(Equal("database/creds/readonly/abc"))
+	g.Expect(secret.Data).To(HaveKeyWithValue("username", "v-readonly"))
+	g.Expect(time.Until(secret.ExpiresAt)).To(BeNumerically("~", time.Minute, 5*time.Second))
+
+	_, err = client.ReadSecret(context.Background(), &Token{ClientToken: "revoked"}, "database/creds/readonly")
+	g.Expect(err).To(MatchError(ContainSubstring("status '403 Forbidden': permission denied")))
+}
+
+func TestClient_guard(t *testing.T) {
+	g := NewWithT(t)
+
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"errors":["Vault is sealed"]}`))
+	}))
+	defer srv.Close()
+
+	guard, err := resilience.New("vault", resilience.WithRetryBudget(2, time.Millisecond))
+	g.Expect(err).ToNot(HaveOccurred())
+	client, err := New(WithAddress(srv.URL), WithRole("flux"), WithServiceAccountToken("sa-token"), WithGuard(guard))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = client.Login(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("Vault is sealed")))
+	// The sealed Vault is retried.
+	g.Expect(requests).To(Equal(3))
+}
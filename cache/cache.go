@@ -18,6 +18,7 @@ package cache
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"slices"
 	"sort"
 	"sync"
@@ -30,6 +31,8 @@ const (
 	noExpiration = time.Second * 86400 * 365 * 10 // 10 years
 	// defaultInterval is the default interval for the janitor to run.
 	defaultInterval = time.Minute
+	// defaultNegativeTTL is the default time to live of cached lookup failures.
+	defaultNegativeTTL = 30 * time.Second
 )
 
 // Cache[T] is a thread-safe in-memory key/value store.
@@ -45,6 +48,8 @@ type item[T any] struct {
 	value T
 	// expiresAt is the item's expiration time.
 	expiresAt time.Time
+	// err is the cached lookup failure of a negative item.
+	err error
 }
 
 type cache[T any] struct {
@@ -57,9 +62,12 @@ type cache[T any] struct {
 	sorted bool
 	// capacity is the maximum number of index the cache can hold.
 	capacity int
-	metrics  *cacheMetrics
-	janitor  *janitor[T]
-	closed   bool
+	// negativeTTL is the time to live of negative items.
+	negativeTTL time.Duration
+	jitter      float64
+	metrics     *cacheMetrics
+	janitor     *janitor[T]
+	closed      bool
 
 	mu sync.RWMutex
 }
@@ -74,10 +82,12 @@ func New[T any](capacity int, opts ...Options) (*Cache[T], error) {
 	}
 
 	c := &cache[T]{
-		index:    make(map[string]*item[T]),
-		items:    make([]*item[T], 0, capacity),
-		sorted:   true,
-		capacity: capacity,
+		index:       make(map[string]*item[T]),
+		items:       make([]*item[T], 0, capacity),
+		sorted:      true,
+		capacity:    capacity,
+		negativeTTL: opt.negativeTTL,
+		jitter:      opt.jitter,
 		janitor: &janitor[T]{
 			interval: opt.interval,
 			stop:     make(chan bool),
@@ -108,6 +118,9 @@ func makeOptions(opts ...Options) (*storeOptions, error) {
 	if opt.interval <= 0 {
 		opt.interval = defaultInterval
 	}
+	if opt.negativeTTL <= 0 {
+		opt.negativeTTL = defaultNegativeTTL
+	}
 	return &opt, nil
 }
 
@@ -152,26 +165,123 @@ func (c *Cache[T]) Set(key string, value T) error {
 	return ErrCacheFull
 }
 
+// SetWithTTL sets an item in the cache which expires after the given time
+// to live, overriding the expiration of an existing item. If the cache is
+// configured with WithJitter, the expiration is brought forward by a random
+// fraction of the time to live, so that the items set together don't expire
+// together. If the cache is full, an error is returned.
+func (c *Cache[T]) SetWithTTL(key string, value T, ttl time.Duration) error {
+	if ttl <= 0 {
+		recordRequest(c.metrics, StatusFailure)
+		return ErrInvalidTTL
+	}
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return ErrCacheClosed
+	}
+	expiresAt := time.Now().Add(c.jitteredTTL(ttl))
+	existing, found := c.index[key]
+	switch {
+	case found:
+		existing.value = value
+		existing.err = nil
+		existing.expiresAt = expiresAt
+	case c.capacity > 0 && len(c.index) < c.capacity:
+		c.setItem(&item[T]{
+			key:       key,
+			value:     value,
+			expiresAt: expiresAt,
+		})
+	default:
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return ErrCacheFull
+	}
+	c.sorted = false
+	c.mu.Unlock()
+	recordRequest(c.metrics, StatusSuccess)
+	if !found {
+		recordItemIncrement(c.metrics)
+	}
+	return nil
+}
+
+// jitteredTTL returns the time to live reduced by a random fraction of it,
+// up to the jitter of the cache.
+func (c *cache[T]) jitteredTTL(ttl time.Duration) time.Duration {
+	if c.jitter <= 0 {
+		return ttl
+	}
+	return ttl - time.Duration(rand.Float64()*c.jitter*float64(ttl))
+}
+
 func (c *cache[T]) set(key string, value T) {
-	item := item[T]{
+	c.setItem(&item[T]{
 		key:       key,
 		value:     value,
 		expiresAt: time.Now().Add(noExpiration),
+	})
+}
+
+// SetNegative caches the failure of a lookup for the given key, so that
+// repeated lookups do not hit the external service every time. Until the
+// negative TTL expires, Get returns a *CacheError with the Reason
+// ErrNegativeHit wrapping err. A subsequent Set for the key overwrites the
+// negative item. If the cache is full, an error is returned.
+func (c *Cache[T]) SetNegative(key string, err error) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return ErrCacheClosed
+	}
+	_, found := c.index[key]
+	if !found && (c.capacity <= 0 || len(c.index) >= c.capacity) {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return ErrCacheFull
+	}
+	c.setItem(&item[T]{
+		key:       key,
+		expiresAt: time.Now().Add(c.negativeTTL),
+		err:       err,
+	})
+	// the negative item expires before the items without expiration
+	c.sorted = false
+	c.mu.Unlock()
+	recordRequest(c.metrics, StatusSuccess)
+	if !found {
+		recordItemIncrement(c.metrics)
 	}
+	return nil
+}
 
-	if _, found := c.index[key]; found {
-		// item already exists, update it only
-		c.index[key] = &item
+func (c *cache[T]) setItem(item *item[T]) {
+	key := item.key
+	if existing, found := c.index[key]; found {
+		// item already exists, update it in place so that the
+		// items slice keeps pointing to it. The expiration of a
+		// positive item is kept when updating its value.
+		if existing.err != nil || item.err != nil {
+			existing.expiresAt = item.expiresAt
+			c.sorted = false
+		}
+		existing.value = item.value
+		existing.err = item.err
 		return
 	}
-	c.index[key] = &item
-	c.items = append(c.items, &item)
+	c.index[key] = item
+	c.items = append(c.items, item)
 }
 
 // Get returns an item in the cache for the given key. If no item is found, an
-// error is returned.
-// The caller can record cache hit or miss based on the result with
-// Cache.RecordCacheEvent().
+// error is returned. If a lookup failure has been cached for the key with
+// SetNegative, a *CacheError with the Reason ErrNegativeHit wrapping the
+// failure is returned.
+// The caller can record cache hit, negative hit or miss based on the result
+// with Cache.RecordCacheEvent().
 func (c *Cache[T]) Get(key string) (T, error) {
 	var res T
 	c.mu.RLock()
@@ -184,17 +294,20 @@ func (c *Cache[T]) Get(key string) (T, error) {
 	if !found {
 		c.mu.RUnlock()
 		recordRequest(c.metrics, StatusSuccess)
-		return res, ErrNotFound
+		return res, errNotFound
 	}
 	if !item.expiresAt.IsZero() {
 		if item.expiresAt.Compare(time.Now()) < 0 {
 			c.mu.RUnlock()
 			recordRequest(c.metrics, StatusSuccess)
-			return res, ErrNotFound
+			return res, errNotFound
 		}
 	}
 	c.mu.RUnlock()
 	recordRequest(c.metrics, StatusSuccess)
+	if item.err != nil {
+		return res, &CacheError{Reason: ErrNegativeHit, Err: item.err}
+	}
 	return item.value, nil
 }
 
@@ -333,7 +446,7 @@ func (c *Cache[T]) SetExpiration(key string, expiration time.Time) error {
 	if !ok {
 		c.mu.Unlock()
 		recordRequest(c.metrics, StatusFailure)
-		return ErrNotFound
+		return errNotFound
 	}
 	item.expiresAt = expiration
 	// mark the items as not sorted
@@ -357,18 +470,15 @@ func (c *Cache[T]) GetExpiration(key string) (time.Time, error) {
 	if !ok {
 		c.mu.RUnlock()
 		recordRequest(c.metrics, StatusSuccess)
-		return time.Time{}, ErrNotFound
-	}
-	if !item.expiresAt.IsZero() {
-		if item.expiresAt.Compare(time.Now()) < 0 {
-			c.mu.RUnlock()
-			recordRequest(c.metrics, StatusSuccess)
-			return time.Time{}, nil
-		}
+		return time.Time{}, errNotFound
 	}
+	expiresAt := item.expiresAt
 	c.mu.RUnlock()
 	recordRequest(c.metrics, StatusSuccess)
-	return item.expiresAt, nil
+	if !expiresAt.IsZero() && expiresAt.Compare(time.Now()) < 0 {
+		return time.Time{}, nil
+	}
+	return expiresAt, nil
 }
 
 // deleteExpired deletes all expired index from the cache.
@@ -405,13 +515,13 @@ func (c *cache[T]) deleteExpired() {
 	c.mu.Unlock()
 }
 
-// RecordCacheEvent records a cache event (cache_miss or cache_hit) with kind,
+// RecordCacheEvent records a cache event (cache_miss, cache_hit or cache_negative_hit) with kind,
 // name and namespace of the associated object being reconciled.
 func (c *Cache[T]) RecordCacheEvent(event, kind, name, namespace string) {
 	recordCacheEvent(c.metrics, event, kind, name, namespace)
 }
 
-// DeleteCacheEvent deletes the cache event (cache_miss or cache_hit) metric for
+// DeleteCacheEvent deletes the cache event (cache_miss, cache_hit or cache_negative_hit) metric for
 // the associated object being reconciled, given their kind, name and namespace.
 func (c *Cache[T]) DeleteCacheEvent(event, kind, name, namespace string) {
 	deleteCacheEvent(c.metrics, event, kind, name, namespace)
@@ -17,14 +17,17 @@ limitations under the License.
 package cache
 
 import (
+	"errors"
 	"fmt"
 	"math/rand/v2"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 )
 
 func TestCache(t *testing.T) {
@@ -261,6 +264,141 @@ func Test_Cache_Get(t *testing.T) {
 
 }
 
+func Test_Cache_SetNegative(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewPedanticRegistry()
+	cache, err := New[string](1,
+		WithMetricsRegisterer(reg),
+		WithMetricsPrefix("gotk_"),
+		WithNegativeTTL(50*time.Millisecond),
+		WithCleanupInterval(10*time.Millisecond))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	recObjKind := "TestObject"
+	recObjName := "test"
+	recObjNamespace := "test-ns"
+
+	key := "key1"
+	lookupErr := errors.New("registry unavailable")
+
+	err = cache.SetNegative(key, lookupErr)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	got, err := cache.Get(key)
+	g.Expect(got).To(BeEmpty())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(errors.Is(err, ErrNegativeHit)).To(BeTrue())
+	g.Expect(errors.Is(err, lookupErr)).To(BeTrue())
+	g.Expect(err.Error()).To(Equal("cached lookup failure: registry unavailable"))
+	cache.RecordCacheEvent(CacheEventTypeNegativeHit, recObjKind, recObjName, recObjNamespace)
+
+	// the capacity is enforced for negative items
+	err = cache.SetNegative("key2", lookupErr)
+	g.Expect(err).To(Equal(ErrCacheFull))
+
+	validateMetrics(reg, `
+	# HELP gotk_cache_events_total Total number of cache retrieval events for a Gitops Toolkit resource reconciliation.
+	# TYPE gotk_cache_events_total counter
+	gotk_cache_events_total{event_type="cache_negative_hit",kind="TestObject",name="test",namespace="test-ns"} 1
+	# HELP gotk_cache_evictions_total Total number of cache evictions.
+	# TYPE gotk_cache_evictions_total counter
+	gotk_cache_evictions_total 0
+	# HELP gotk_cache_requests_total Total number of cache requests partioned by success or failure.
+	# TYPE gotk_cache_requests_total counter
+	gotk_cache_requests_total{status="failure"} 1
+	gotk_cache_requests_total{status="success"} 2
+	# HELP gotk_cached_items Total number of items in the cache.
+	# TYPE gotk_cached_items gauge
+	gotk_cached_items 1
+`, t)
+
+	// the negative item expires after the negative TTL
+	g.Eventually(func() error {
+		_, err := cache.Get(key)
+		return err
+	}, time.Second, 10*time.Millisecond).Should(Equal(ErrNotFound))
+
+	// a successful lookup overwrites the negative item
+	err = cache.SetNegative(key, lookupErr)
+	g.Expect(err).ToNot(HaveOccurred())
+	err = cache.Set(key, "val1")
+	g.Expect(err).ToNot(HaveOccurred())
+	time.Sleep(100 * time.Millisecond)
+	got, err = cache.Get(key)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal("val1"))
+}
+
+func Test_Cache_SetWithTTL(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewPedanticRegistry()
+	cache, err := New[string](1,
+		WithMetricsRegisterer(reg),
+		WithMetricsPrefix("gotk_"),
+		WithCleanupInterval(10*time.Millisecond))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	key := "key1"
+	err = cache.SetWithTTL(key, "val1", 50*time.Millisecond)
+	g.Expect(err).ToNot(HaveOccurred())
+	got, err := cache.Get(key)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal("val1"))
+
+	// the capacity is enforced
+	err = cache.SetWithTTL("key2", "val2", time.Hour)
+	g.Expect(err).To(Equal(ErrCacheFull))
+	err = cache.SetWithTTL("key2", "val2", 0)
+	g.Expect(err).To(Equal(ErrInvalidTTL))
+
+	// the time to live overrides the expiration of the existing item
+	err = cache.SetWithTTL(key, "val2", time.Hour)
+	g.Expect(err).ToNot(HaveOccurred())
+	expiresAt, err := cache.GetExpiration(key)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+	err = cache.SetWithTTL(key, "val3", 10*time.Millisecond)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Eventually(func() []string {
+		keys, _ := cache.ListKeys()
+		return keys
+	}, time.Second, 10*time.Millisecond).Should(BeEmpty())
+
+	err = testutil.GatherAndCompare(reg, strings.NewReader(`
+	# HELP gotk_cache_evictions_total Total number of cache evictions.
+	# TYPE gotk_cache_evictions_total counter
+	gotk_cache_evictions_total 1
+	# HELP gotk_cached_items Total number of items in the cache.
+	# TYPE gotk_cached_items gauge
+	gotk_cached_items 0
+`), "gotk_cache_evictions_total", "gotk_cached_items")
+	g.Expect(err).ToNot(HaveOccurred())
+}
+
+func Test_Cache_SetWithTTL_jitter(t *testing.T) {
+	g := NewWithT(t)
+	cache, err := New[string](100, WithJitter(0.5))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	now := time.Now()
+	expirations := make(map[time.Time]struct{})
+	for i := range 100 {
+		key := fmt.Sprintf("key%d", i)
+		err = cache.SetWithTTL(key, "val", time.Hour)
+		g.Expect(err).ToNot(HaveOccurred())
+		expiresAt, err := cache.GetExpiration(key)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(expiresAt).To(BeTemporally(">=", now.Add(30*time.Minute)))
+		g.Expect(expiresAt).To(BeTemporally("<=", time.Now().Add(time.Hour)))
+		expirations[expiresAt] = struct{}{}
+	}
+	// the items set together don't expire together
+	g.Expect(len(expirations)).To(BeNumerically(">", 1))
+
+	_, err = New[string](1, WithJitter(1))
+	g.Expect(err).To(MatchError(ContainSubstring("jitter must be between 0 and 1")))
+}
+
 func Test_Cache_Delete(t *testing.T) {
 	g := NewWithT(t)
 	reg := prometheus.NewPedanticRegistry()
@@ -475,3 +613,60 @@ func TestCache_Concurrent(t *testing.T) {
 		g.Expect(val).To(Equal("test-token"))
 	}
 }
+
+func TestCache_Get_allocs(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, err := New[string](10, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cache.Set("key", "value")).To(Succeed())
+
+	g.Expect(testing.AllocsPerRun(100, func() {
+		_, _ = cache.Get("key")
+	})).To(BeZero())
+	g.Expect(testing.AllocsPerRun(100, func() {
+		_, _ = cache.Get("missing")
+	})).To(BeZero())
+	g.Expect(testing.AllocsPerRun(100, func() {
+		cache.RecordCacheEvent(CacheEventTypeHit, "Kind", "name", "namespace")
+	})).To(BeZero())
+}
+
+func BenchmarkCache_Get(b *testing.B) {
+	cache, err := New[string](1000, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		if err := cache.Set(keys[i], "value"); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("hit", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for i := 0; pb.Next(); i++ {
+				if _, err := cache.Get(keys[i%len(keys)]); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	})
+	b.Run("miss", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				_, _ = cache.Get("missing")
+			}
+		})
+	})
+	b.Run("record event", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			cache.RecordCacheEvent(CacheEventTypeHit, "Kind", "name", "namespace")
+		}
+	})
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+)
+
+// involvedObjectKey is the key of the involved object in a context.
+type involvedObjectKey struct{}
+
+// WithInvolvedObject returns a copy of the context carrying the involved
+// object, typically the object being reconciled, for which the cache events
+// recorded with the context are attributed. A TokenCache uses the involved
+// object of the context for the keys without one.
+func WithInvolvedObject(ctx context.Context, obj InvolvedObject) context.Context {
+	return context.WithValue(ctx, involvedObjectKey{}, obj)
+}
+
+// InvolvedObjectFromContext returns the involved object carried by the
+// context, and false if there is none.
+func InvolvedObjectFromContext(ctx context.Context) (InvolvedObject, bool) {
+	obj, ok := ctx.Value(involvedObjectKey{}).(InvolvedObject)
+	return obj, ok
+}
+
+// RecordCacheEventFromContext records a cache event for the involved object
+// of the context. It does nothing if the context carries no involved object.
+func (c *Cache[T]) RecordCacheEventFromContext(ctx context.Context, event string) {
+	if obj, ok := InvolvedObjectFromContext(ctx); ok {
+		c.RecordCacheEvent(event, obj.Kind, obj.Name, obj.Namespace)
+	}
+}
+
+// RecordCacheEventFromContext records a cache event for the involved object
+// of the context. It does nothing if the context carries no involved object.
+func (c *LRU[T]) RecordCacheEventFromContext(ctx context.Context, event string) {
+	if obj, ok := InvolvedObjectFromContext(ctx); ok {
+		c.RecordCacheEvent(event, obj.Kind, obj.Name, obj.Namespace)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInvolvedObjectFromContext(t *testing.T) {
+	g := NewWithT(t)
+
+	_, ok := InvolvedObjectFromContext(context.Background())
+	g.Expect(ok).To(BeFalse())
+
+	obj := InvolvedObject{Kind: "GitRepository", Name: "repo", Namespace: "default"}
+	got, ok := InvolvedObjectFromContext(WithInvolvedObject(context.Background(), obj))
+	g.Expect(ok).To(BeTrue())
+	g.Expect(got).To(Equal(obj))
+}
+
+func TestRecordCacheEventFromContext(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := InvolvedObject{Kind: "GitRepository", Name: "repo", Namespace: "default"}
+	ctx := WithInvolvedObject(context.Background(), obj)
+
+	c, err := New[string](5, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	g.Expect(err).ToNot(HaveOccurred())
+	c.RecordCacheEventFromContext(ctx, CacheEventTypeHit)
+	c.RecordCacheEventFromContext(context.Background(), CacheEventTypeHit)
+	g.Expect(testutil.CollectAndCount(c.metrics.cacheEventsCounter)).To(Equal(1))
+	hits := c.metrics.cacheEventsCounter.WithLabelValues(CacheEventTypeHit, obj.Kind, obj.Name, obj.Namespace)
+	g.Expect(testutil.ToFloat64(hits)).To(Equal(float64(1)))
+
+	lru, err := NewLRU[string](5, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	g.Expect(err).ToNot(HaveOccurred())
+	lru.RecordCacheEventFromContext(ctx, CacheEventTypeMiss)
+	lru.RecordCacheEventFromContext(context.Background(), CacheEventTypeMiss)
+	g.Expect(testutil.CollectAndCount(lru.metrics.cacheEventsCounter)).To(Equal(1))
+	misses := lru.metrics.cacheEventsCounter.WithLabelValues(CacheEventTypeMiss, obj.Kind, obj.Name, obj.Namespace)
+	g.Expect(testutil.ToFloat64(misses)).To(Equal(float64(1)))
+}
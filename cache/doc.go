@@ -25,6 +25,50 @@ limitations under the License.
 //
 //	cache, err := New[string](10)
 //
+// The items of the expiring cache can be given their own time to live, and
+// their expiration can be spread with a random jitter, so that the items set
+// together are not refreshed at once
+//
+//	cache, err := New[string](10, WithJitter(0.1))
+//	err = cache.SetWithTTL("key", "value", time.Hour)
+//
+// Values too large to be held in memory, e.g. rendered manifests or chart
+// archives, can be stored in a Tiered cache, which holds the most recently
+// used values in memory and all of them on disk, within the budget of each
+// tier
+//
+//	cache, err := NewTiered[[]byte]("/tmp/cache", BytesCodec{},
+//	  WithMemoryBudget(16<<20), WithDiskBudget(1<<30))
+//
+// Access tokens can be stored in a TokenCache, keyed by the object they are
+// requested for, their provider, audience and scopes. The tokens expire
+// before the end of their lifetime
+//
+//	token, cached, err := tokenCache.GetOrSet(ctx, TokenKey{
+//	  InvolvedObject: InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"},
+//	  Provider:       "gcp",
+//	  Audience:       "registry",
+//	}, newToken)
+//
+// In multi-tenant clusters, the tokens of an object or a namespace can be
+// limited with quotas, evicting their tokens expiring first, so that a tenant
+// can't fill the cache
+//
+//	tokenCache, err := NewTokenCache(1000, WithObjectQuota(10), WithNamespaceQuota(100))
+//
+// The tokens in use can be refreshed in the background before they expire,
+// a cache hit within the refresh ahead window requesting a new token
+//
+//	tokenCache, err := NewTokenCache(1000, WithRefreshAhead(5*time.Minute))
+//
+// The object being reconciled can be carried by the context instead of the
+// keys, so that the token requests of the layers below the reconciler, e.g.
+// the authentication providers, are attributed to it without passing it
+// around
+//
+//	ctx = WithInvolvedObject(ctx, InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"})
+//	token, cached, err := tokenCache.GetOrSet(ctx, TokenKey{Provider: "gcp", Audience: "registry"}, newToken)
+//
 // The cache implementations are self-instrumenting and export metrics about the
 // internal operations of the cache if it is configured with a metrics
 // registerer.
@@ -41,13 +85,30 @@ limitations under the License.
 //
 //	if err == ErrNotFound {
 //	  cache.RecordCacheEvent(CacheEventTypeMiss, "GitRepository", "repoA", "testNS")
+//	} else if errors.Is(err, ErrNegativeHit) {
+//	  cache.RecordCacheEvent(CacheEventTypeNegativeHit, "GitRepository", "repoA", "testNS")
 //	} else {
 //	  cache.RecordCacheEvent(CacheEventTypeHit, "GitRepository", "repoA", "testNS")
 //	}
 //
+// or record it for the involved object carried by the context
+//
+//	cache.RecordCacheEventFromContext(ctx, CacheEventTypeHit)
+//
+// Lookup failures can be cached with a shorter time to live than successful
+// lookups, configured with WithNegativeTTL, to avoid hammering an external
+// service that keeps failing
+//
+//	cache, err := New[string](10, WithNegativeTTL(10*time.Second))
+//	...
+//	if err := lookup(); err != nil {
+//	  cache.SetNegative("foo", err)
+//	}
+//
 // When the Flux object associated with the cache metrics is deleted, the
 // metrics can be deleted as follows
 //
 //	cache.DeleteCacheEvent(CacheEventTypeHit, "GitRepository", "repoA", "testNS")
 //	cache.DeleteCacheEvent(CacheEventTypeMiss, "GitRepository", "repoA", "testNS")
+//	cache.DeleteCacheEvent(CacheEventTypeNegativeHit, "GitRepository", "repoA", "testNS")
 package cache
@@ -68,4 +68,19 @@ var (
 	ErrCacheClosed = CacheErrorReason{"CacheClosed", "cache is closed"}
 	ErrCacheFull   = CacheErrorReason{"CacheFull", "cache is full"}
 	ErrInvalidSize = CacheErrorReason{"InvalidSize", "invalid size"}
+	// ErrInvalidTTL is returned by Cache.SetWithTTL for a time to live
+	// which is not greater than zero.
+	ErrInvalidTTL = CacheErrorReason{"InvalidTTL", "invalid TTL"}
+	// ErrNegativeHit is the Reason of the CacheError returned by Cache.Get
+	// for a key of which the lookup failure has been cached with
+	// Cache.SetNegative.
+	ErrNegativeHit = CacheErrorReason{"NegativeHit", "cached lookup failure"}
+	// ErrDigestMismatch is the Reason of the CacheError returned by
+	// Tiered.Get for a value of which the content on disk does not match
+	// the digest recorded when it was stored.
+	ErrDigestMismatch = CacheErrorReason{"DigestMismatch", "digest mismatch"}
 )
+
+// errNotFound is ErrNotFound converted to an error once, as converting it on
+// each cache miss allocates.
+var errNotFound error = ErrNotFound
@@ -181,7 +181,7 @@ func (c *LRU[T]) Get(key string) (T, error) {
 	if !ok {
 		c.mu.Unlock()
 		recordRequest(c.metrics, StatusSuccess)
-		return res, ErrNotFound
+		return res, errNotFound
 	}
 	c.delete(node)
 	_ = c.add(node)
@@ -228,13 +228,13 @@ func (c *LRU[T]) Resize(size int) (int, error) {
 	return overflow, nil
 }
 
-// RecordCacheEvent records a cache event (cache_miss or cache_hit) with kind,
+// RecordCacheEvent records a cache event (cache_miss, cache_hit or cache_negative_hit) with kind,
 // name and namespace of the associated object being reconciled.
 func (c *LRU[T]) RecordCacheEvent(event, kind, name, namespace string) {
 	recordCacheEvent(c.metrics, event, kind, name, namespace)
 }
 
-// DeleteCacheEvent deletes the cache event (cache_miss or cache_hit) metric for
+// DeleteCacheEvent deletes the cache event (cache_miss, cache_hit or cache_negative_hit) metric for
 // the associated object being reconciled, given their kind, name and namespace.
 func (c *LRU[T]) DeleteCacheEvent(event, kind, name, namespace string) {
 	deleteCacheEvent(c.metrics, event, kind, name, namespace)
@@ -326,3 +326,47 @@ func TestLRU_int(t *testing.T) {
 	g.Expect(err).To(Succeed())
 	g.Expect(got).To(Equal(4))
 }
+
+func TestLRU_Get_allocs(t *testing.T) {
+	g := NewWithT(t)
+
+	cache, err := NewLRU[string](10, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cache.Set("key", "value")).To(Succeed())
+
+	g.Expect(testing.AllocsPerRun(100, func() {
+		_, _ = cache.Get("key")
+	})).To(BeZero())
+	g.Expect(testing.AllocsPerRun(100, func() {
+		_, _ = cache.Get("missing")
+	})).To(BeZero())
+}
+
+func BenchmarkLRU_Get(b *testing.B) {
+	cache, err := NewLRU[string](1000, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		if err := cache.Set(keys[i], "value"); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.Run("hit", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := cache.Get(keys[i%len(keys)]); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("miss", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = cache.Get("missing")
+		}
+	})
+}
@@ -18,6 +18,7 @@ package cache
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -28,10 +29,33 @@ const (
 	CacheEventTypeMiss = "cache_miss"
 	// CacheEventTypeHit is the event type for cache hits.
 	CacheEventTypeHit = "cache_hit"
+	// CacheEventTypeNegativeHit is the event type for cache hits of cached
+	// lookup failures.
+	CacheEventTypeNegativeHit = "cache_negative_hit"
+	// CacheEventTypeCoalesced is the event type for cache misses of a
+	// TokenCache sharing the token request of a concurrent cache miss.
+	CacheEventTypeCoalesced = "cache_coalesced"
+	// CacheEventTypeRefreshAhead is the event type for the background token
+	// requests of a TokenCache refreshing a token before it expires.
+	CacheEventTypeRefreshAhead = "cache_refresh_ahead"
+	// CacheEventTypeTokenIssued is the event type for the tokens issued for
+	// a TokenCache, on cache misses and refreshes ahead.
+	CacheEventTypeTokenIssued = "cache_token_issued"
 	// StatusSuccess is the status for successful cache requests.
 	StatusSuccess = "success"
 	// StatusFailure is the status for failed cache requests.
 	StatusFailure = "failure"
+	// TierMemory is the tier label value for the memory tier of a Tiered
+	// cache.
+	TierMemory = "memory"
+	// TierDisk is the tier label value for the disk tier of a Tiered cache.
+	TierDisk = "disk"
+	// QuotaObject is the quota label value for the evictions caused by the
+	// object quota of a TokenCache.
+	QuotaObject = "object"
+	// QuotaNamespace is the quota label value for the evictions caused by
+	// the namespace quota of a TokenCache.
+	QuotaNamespace = "namespace"
 )
 
 type cacheMetrics struct {
@@ -85,10 +109,22 @@ func (m *cacheMetrics) collectors() []prometheus.Collector {
 	}
 }
 
+// labelValuesPool holds the buffers of the label values of the cache events,
+// so that recording an event does not allocate.
+var labelValuesPool = sync.Pool{
+	New: func() any {
+		lvs := make([]string, 0, 4)
+		return &lvs
+	},
+}
+
 // incCacheEventCount increment by 1 the cache event count for the given event type, name and namespace.
 func (m *cacheMetrics) incCacheEvents(event string, lvs ...string) {
-	lvs = append([]string{event}, lvs...)
-	m.cacheEventsCounter.WithLabelValues(lvs...).Inc()
+	buf := labelValuesPool.Get().(*[]string)
+	*buf = append(append((*buf)[:0], event), lvs...)
+	m.cacheEventsCounter.WithLabelValues(*buf...).Inc()
+	clear(*buf)
+	labelValuesPool.Put(buf)
 }
 
 // deleteCacheEvent deletes the cache event metric.
@@ -122,6 +158,91 @@ func (m *cacheMetrics) incCacheEvictions() {
 	m.cacheEvictionCounter.Inc()
 }
 
+// tierMetrics holds the metrics of the tiers of a Tiered cache, in addition
+// to the cacheMetrics of the cache as a whole.
+type tierMetrics struct {
+	tierHitsCounter         *prometheus.CounterVec
+	tierBytesGauge          *prometheus.GaugeVec
+	digestMismatchesCounter prometheus.Counter
+}
+
+// newTierMetrics returns a new tierMetrics.
+func newTierMetrics(prefix string, reg prometheus.Registerer) *tierMetrics {
+	return &tierMetrics{
+		tierHitsCounter: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("%scache_tier_hits_total", prefix),
+				Help: "Total number of cache hits partitioned by tier.",
+			},
+			[]string{"tier"},
+		),
+		tierBytesGauge: promauto.With(reg).NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: fmt.Sprintf("%scache_tier_bytes", prefix),
+				Help: "Total size in bytes of the values held in the cache partitioned by tier.",
+			},
+			[]string{"tier"},
+		),
+		digestMismatchesCounter: promauto.With(reg).NewCounter(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("%scache_digest_mismatches_total", prefix),
+				Help: "Total number of values read from disk not matching their digest.",
+			},
+		),
+	}
+}
+
+// collectors returns the metrics.Collector objects for the tierMetrics.
+func (m *tierMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.tierHitsCounter,
+		m.tierBytesGauge,
+		m.digestMismatchesCounter,
+	}
+}
+
+func recordTierHit(metrics *tierMetrics, tier string) {
+	if metrics != nil {
+		metrics.tierHitsCounter.WithLabelValues(tier).Inc()
+	}
+}
+
+func recordTierBytes(metrics *tierMetrics, tier string, bytes int64) {
+	if metrics != nil {
+		metrics.tierBytesGauge.WithLabelValues(tier).Set(float64(bytes))
+	}
+}
+
+func recordDigestMismatch(metrics *tierMetrics) {
+	if metrics != nil {
+		metrics.digestMismatchesCounter.Inc()
+	}
+}
+
+// quotaMetrics holds the metrics of the quotas of a TokenCache.
+type quotaMetrics struct {
+	quotaEvictionsCounter *prometheus.CounterVec
+}
+
+// newQuotaMetrics returns a new quotaMetrics.
+func newQuotaMetrics(prefix string, reg prometheus.Registerer) *quotaMetrics {
+	return &quotaMetrics{
+		quotaEvictionsCounter: promauto.With(reg).NewCounterVec(
+			prometheus.CounterOpts{
+				Name: fmt.Sprintf("%scache_quota_evictions_total", prefix),
+				Help: "Total number of tokens evicted to keep within a quota, partitioned by quota and namespace.",
+			},
+			[]string{"quota", "namespace"},
+		),
+	}
+}
+
+func recordQuotaEviction(metrics *quotaMetrics, quota, namespace string) {
+	if metrics != nil {
+		metrics.quotaEvictionsCounter.WithLabelValues(quota, namespace).Inc()
+	}
+}
+
 // MustMakeMetrics registers the metrics collectors in the given registerer.
 func MustMakeMetrics(r prometheus.Registerer, m *cacheMetrics) {
 	r.MustRegister(m.collectors()...)
@@ -17,6 +17,7 @@ limitations under the License.
 package cache
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -45,8 +46,19 @@ type Expirable[T any] interface {
 
 type storeOptions struct {
 	interval      time.Duration
+	negativeTTL   time.Duration
+	jitter        float64
 	registerer    prometheus.Registerer
 	metricsPrefix string
+	memoryBudget  int64
+	diskBudget    int64
+	// objectQuota and namespaceQuota are the maximum numbers of tokens of a
+	// TokenCache per involved object and per namespace.
+	objectQuota    int
+	namespaceQuota int
+	// refreshAhead is the window before the expiry of the tokens of a
+	// TokenCache in which they are refreshed in the background.
+	refreshAhead time.Duration
 }
 
 // Options is a function that sets the store options.
@@ -60,6 +72,34 @@ func WithCleanupInterval(interval time.Duration) Options {
 	}
 }
 
+// WithNegativeTTL sets the time to live of the lookup failures cached with
+// Cache.SetNegative. It should be shorter than the expiration of successful
+// lookups, so that a fixed misconfiguration is picked up quickly.
+func WithNegativeTTL(ttl time.Duration) Options {
+	return func(o *storeOptions) error {
+		if ttl <= 0 {
+			return fmt.Errorf("negative TTL must be greater than zero")
+		}
+		o.negativeTTL = ttl
+		return nil
+	}
+}
+
+// WithJitter sets the maximum fraction of the time to live by which the
+// expiration of the items set with Cache.SetWithTTL is randomly brought
+// forward, e.g. 0.1 for up to 10%. It spreads the expiration of the items
+// set together, so that they are not all refreshed at once. The fraction
+// must be between 0 and 1.
+func WithJitter(fraction float64) Options {
+	return func(o *storeOptions) error {
+		if fraction < 0 || fraction >= 1 {
+			return fmt.Errorf("jitter must be between 0 and 1")
+		}
+		o.jitter = fraction
+		return nil
+	}
+}
+
 // WithMetricsRegisterer sets the Prometheus registerer for the cache metrics.
 func WithMetricsRegisterer(r prometheus.Registerer) Options {
 	return func(o *storeOptions) error {
@@ -75,3 +115,69 @@ func WithMetricsPrefix(prefix string) Options {
 		return nil
 	}
 }
+
+// WithMemoryBudget sets the maximum size in bytes of the encoded values held
+// in the memory tier of a Tiered cache.
+func WithMemoryBudget(bytes int64) Options {
+	return func(o *storeOptions) error {
+		if bytes <= 0 {
+			return fmt.Errorf("memory budget must be greater than zero")
+		}
+		o.memoryBudget = bytes
+		return nil
+	}
+}
+
+// WithDiskBudget sets the maximum size in bytes of the encoded values held
+// in the disk tier of a Tiered cache.
+func WithDiskBudget(bytes int64) Options {
+	return func(o *storeOptions) error {
+		if bytes <= 0 {
+			return fmt.Errorf("disk budget must be greater than zero")
+		}
+		o.diskBudget = bytes
+		return nil
+	}
+}
+
+// WithObjectQuota sets the maximum number of tokens a TokenCache holds for
+// an involved object. Storing a token above the quota evicts the token of
+// the object expiring first.
+func WithObjectQuota(n int) Options {
+	return func(o *storeOptions) error {
+		if n <= 0 {
+			return fmt.Errorf("object quota must be greater than zero")
+		}
+		o.objectQuota = n
+		return nil
+	}
+}
+
+// WithNamespaceQuota sets the maximum number of tokens a TokenCache holds
+// for the involved objects of a namespace, so that the objects of a
+// namespace can't fill the cache on their own. Storing a token above the
+// quota evicts the token of the namespace expiring first.
+func WithNamespaceQuota(n int) Options {
+	return func(o *storeOptions) error {
+		if n <= 0 {
+			return fmt.Errorf("namespace quota must be greater than zero")
+		}
+		o.namespaceQuota = n
+		return nil
+	}
+}
+
+// WithRefreshAhead enables the background refresh of the tokens of a
+// TokenCache. A cache hit of a token expiring from the cache within the
+// given window returns the cached token, and requests a new token in the
+// background, so that the tokens in use are replaced before they expire
+// instead of causing a cache miss.
+func WithRefreshAhead(window time.Duration) Options {
+	return func(o *storeOptions) error {
+		if window <= 0 {
+			return fmt.Errorf("refresh ahead window must be greater than zero")
+		}
+		o.refreshAhead = window
+		return nil
+	}
+}
@@ -0,0 +1,452 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+const (
+	// defaultMemoryBudget is the default size in bytes of the memory tier.
+	defaultMemoryBudget = 16 << 20
+	// defaultDiskBudget is the default size in bytes of the disk tier.
+	defaultDiskBudget = 512 << 20
+	// tieredFileExt is the extension of the files holding the values of the
+	// disk tier.
+	tieredFileExt = ".blob"
+)
+
+// Codec encodes the values of a Tiered cache to store them on disk, and
+// decodes them when they are read back.
+type Codec[T any] interface {
+	// Encode returns the encoded value.
+	Encode(value T) ([]byte, error)
+	// Decode returns the value decoded from data.
+	Decode(data []byte) (T, error)
+}
+
+// JSONCodec is a Codec encoding the values as JSON.
+type JSONCodec[T any] struct{}
+
+// Encode returns the JSON encoding of value.
+func (JSONCodec[T]) Encode(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+// Decode returns the value decoded from the JSON data.
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+// BytesCodec is a Codec storing byte slices as is.
+type BytesCodec struct{}
+
+// Encode returns value.
+func (BytesCodec) Encode(value []byte) ([]byte, error) {
+	return value, nil
+}
+
+// Decode returns data.
+func (BytesCodec) Decode(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// Tiered is a thread-safe key/value store for values too large to be held
+// in memory, e.g. rendered manifests or chart archives.
+// All methods are safe for concurrent use.
+//
+// All the values are written to a disk tier, in the directory of the cache.
+// The most recently used values are also held in a memory tier, from which
+// they are returned without reading nor decoding them. Values read from disk
+// are promoted to the memory tier, and their content is verified against the
+// digest recorded when they were stored.
+//
+// Both tiers evict their least recently used values to stay within their
+// budget, configured with WithMemoryBudget and WithDiskBudget. Values larger
+// than the memory budget are only held on disk. Values evicted from the disk
+// tier are removed from the cache.
+//
+// Use the NewTiered function to create a new cache that is ready to use.
+type Tiered[T any] struct {
+	dir         string
+	codec       Codec[T]
+	memory      *tier[T]
+	disk        *tier[T]
+	seq         uint64
+	metrics     *cacheMetrics
+	tierMetrics *tierMetrics
+	closed      bool
+
+	mu sync.Mutex
+}
+
+var _ Store[any] = &Tiered[any]{}
+
+// NewTiered creates a new Tiered cache storing its disk tier in the given
+// directory, which must be dedicated to the cache. The values of a previous
+// cache left in the directory are removed. The values are encoded on disk
+// with the given Codec.
+func NewTiered[T any](dir string, codec Codec[T], opts ...Options) (*Tiered[T], error) {
+	opt, err := makeOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply options: %w", err)
+	}
+	if opt.memoryBudget <= 0 {
+		opt.memoryBudget = defaultMemoryBudget
+	}
+	if opt.diskBudget <= 0 {
+		opt.diskBudget = defaultDiskBudget
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	if err := removeValues(dir); err != nil {
+		return nil, fmt.Errorf("failed to clean cache directory: %w", err)
+	}
+
+	c := &Tiered[T]{
+		dir:    dir,
+		codec:  codec,
+		memory: newTier[T](opt.memoryBudget),
+		disk:   newTier[T](opt.diskBudget),
+	}
+
+	if opt.registerer != nil {
+		c.metrics = newCacheMetrics(opt.metricsPrefix, opt.registerer)
+		c.tierMetrics = newTierMetrics(opt.metricsPrefix, opt.registerer)
+	}
+
+	return c, nil
+}
+
+// Close closes the cache and removes the values stored on disk.
+func (c *Tiered[T]) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return ErrCacheClosed
+	}
+	c.closed = true
+	c.memory = newTier[T](c.memory.budget)
+	c.disk = newTier[T](c.disk.budget)
+	c.recordSize()
+	return removeValues(c.dir)
+}
+
+// Set an item in the cache, existing index will be overwritten.
+// The value is written to disk, and held in memory if it is within the
+// memory budget. An error is returned if it exceeds the disk budget.
+func (c *Tiered[T]) Set(key string, value T) error {
+	data, err := c.codec.Encode(value)
+	if err != nil {
+		recordRequest(c.metrics, StatusFailure)
+		return fmt.Errorf("failed to encode value: %w", err)
+	}
+	size := int64(len(data))
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return ErrCacheClosed
+	}
+	if size > c.disk.budget {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return &CacheError{
+			Reason: ErrCacheFull,
+			Err:    fmt.Errorf("value of %d bytes exceeds the disk budget of %d bytes", size, c.disk.budget),
+		}
+	}
+	// Every value is written to a new file, so that concurrent reads of the
+	// value being replaced are not affected.
+	c.seq++
+	path := filepath.Join(c.dir, fmt.Sprintf("%x-%d%s", sha256.Sum256([]byte(key)), c.seq, tieredFileExt))
+	c.mu.Unlock()
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		_ = os.Remove(path)
+		recordRequest(c.metrics, StatusFailure)
+		return fmt.Errorf("failed to write value to disk: %w", err)
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		_ = os.Remove(path)
+		recordRequest(c.metrics, StatusFailure)
+		return ErrCacheClosed
+	}
+	var evicted int
+	removed := c.disk.add(&tierEntry[T]{
+		key:    key,
+		size:   size,
+		path:   path,
+		digest: sha256.Sum256(data),
+	})
+	for _, e := range removed {
+		if e.key != key {
+			c.memory.remove(e.key)
+			evicted++
+		}
+	}
+	c.memory.remove(key)
+	if size <= c.memory.budget {
+		c.memory.add(&tierEntry[T]{key: key, size: size, value: value})
+	}
+	c.recordSize()
+	c.mu.Unlock()
+
+	for _, e := range removed {
+		_ = os.Remove(e.path)
+	}
+	recordRequest(c.metrics, StatusSuccess)
+	for range evicted {
+		recordEviction(c.metrics)
+	}
+	return nil
+}
+
+// Get returns an item in the cache for the given key. If no item is found, an
+// error is returned. If the value read from disk does not match its digest,
+// it is removed from the cache and a CacheError with the ErrDigestMismatch
+// Reason is returned.
+// The caller can record cache hit or miss based on the result with
+// Tiered.RecordCacheEvent().
+func (c *Tiered[T]) Get(key string) (T, error) {
+	var res T
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return res, ErrCacheClosed
+	}
+	if e, ok := c.memory.get(key); ok {
+		// Keep the value on disk as recently used as in memory.
+		c.disk.get(key)
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusSuccess)
+		recordTierHit(c.tierMetrics, TierMemory)
+		return e.value, nil
+	}
+	e, ok := c.disk.get(key)
+	if !ok {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusSuccess)
+		return res, errNotFound
+	}
+	path, size, digest := e.path, e.size, e.digest
+	c.mu.Unlock()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// The value has been replaced or removed concurrently.
+			recordRequest(c.metrics, StatusSuccess)
+			return res, errNotFound
+		}
+		recordRequest(c.metrics, StatusFailure)
+		return res, fmt.Errorf("failed to read value from disk: %w", err)
+	}
+	if sha256.Sum256(data) != digest {
+		c.deleteIfCurrent(key, path)
+		recordDigestMismatch(c.tierMetrics)
+		recordRequest(c.metrics, StatusFailure)
+		return res, &CacheError{
+			Reason: ErrDigestMismatch,
+			Err:    fmt.Errorf("content of '%s' does not match the digest of the value", path),
+		}
+	}
+	value, err := c.codec.Decode(data)
+	if err != nil {
+		recordRequest(c.metrics, StatusFailure)
+		return res, fmt.Errorf("failed to decode value: %w", err)
+	}
+
+	if size <= c.memory.budget {
+		c.mu.Lock()
+		if current, ok := c.disk.peek(key); ok && current.path == path {
+			c.memory.add(&tierEntry[T]{key: key, size: size, value: value})
+			c.recordSize()
+		}
+		c.mu.Unlock()
+	}
+	recordRequest(c.metrics, StatusSuccess)
+	recordTierHit(c.tierMetrics, TierDisk)
+	return value, nil
+}
+
+// Delete an item from the cache. Does nothing if the key is not in the cache.
+func (c *Tiered[T]) Delete(key string) error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		recordRequest(c.metrics, StatusFailure)
+		return ErrCacheClosed
+	}
+	c.memory.remove(key)
+	e, ok := c.disk.remove(key)
+	c.recordSize()
+	c.mu.Unlock()
+
+	if ok {
+		_ = os.Remove(e.path)
+	}
+	recordRequest(c.metrics, StatusSuccess)
+	return nil
+}
+
+// deleteIfCurrent removes the value of the given key from the cache, if it
+// is still stored at the given path.
+func (c *Tiered[T]) deleteIfCurrent(key, path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.disk.peek(key); ok && e.path == path {
+		c.memory.remove(key)
+		c.disk.remove(key)
+		c.recordSize()
+		_ = os.Remove(path)
+	}
+}
+
+// recordSize records the number of items and the size of the tiers. It must
+// be called with the lock held.
+func (c *Tiered[T]) recordSize() {
+	if c.metrics != nil {
+		c.metrics.setCachedItems(float64(len(c.disk.entries)))
+	}
+	recordTierBytes(c.tierMetrics, TierMemory, c.memory.size)
+	recordTierBytes(c.tierMetrics, TierDisk, c.disk.size)
+}
+
+// RecordCacheEvent records a cache event (cache_miss, cache_hit or cache_negative_hit) with kind,
+// name and namespace of the associated object being reconciled.
+func (c *Tiered[T]) RecordCacheEvent(event, kind, name, namespace string) {
+	recordCacheEvent(c.metrics, event, kind, name, namespace)
+}
+
+// DeleteCacheEvent deletes the cache event (cache_miss, cache_hit or cache_negative_hit) metric for
+// the associated object being reconciled, given their kind, name and namespace.
+func (c *Tiered[T]) DeleteCacheEvent(event, kind, name, namespace string) {
+	deleteCacheEvent(c.metrics, event, kind, name, namespace)
+}
+
+// removeValues removes the files holding the values of a disk tier from the
+// given directory.
+func removeValues(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+tieredFileExt))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, f := range files {
+		if err := os.Remove(f); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// tierEntry is a value held in a tier. The memory tier holds the decoded
+// value, and the disk tier the path and digest of the encoded value.
+type tierEntry[T any] struct {
+	key    string
+	size   int64
+	value  T
+	path   string
+	digest [sha256.Size]byte
+}
+
+// tier is a least recently used set of entries within a size budget.
+// It is not safe for concurrent use.
+type tier[T any] struct {
+	budget  int64
+	size    int64
+	entries map[string]*list.Element
+	// order holds the entries from the most to the least recently used.
+	order *list.List
+}
+
+func newTier[T any](budget int64) *tier[T] {
+	return &tier[T]{
+		budget:  budget,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the entry for the given key, and marks it as the most
+// recently used.
+func (t *tier[T]) get(key string) (*tierEntry[T], bool) {
+	el, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	t.order.MoveToFront(el)
+	return el.Value.(*tierEntry[T]), true
+}
+
+// peek returns the entry for the given key, without marking it as used.
+func (t *tier[T]) peek(key string) (*tierEntry[T], bool) {
+	el, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return el.Value.(*tierEntry[T]), true
+}
+
+// add adds the entry as the most recently used one. It returns the removed
+// entries: the one replaced with the same key, if any, and the least
+// recently used ones evicted to stay within the budget.
+func (t *tier[T]) add(e *tierEntry[T]) []*tierEntry[T] {
+	var removed []*tierEntry[T]
+	if old, ok := t.remove(e.key); ok {
+		removed = append(removed, old)
+	}
+	t.entries[e.key] = t.order.PushFront(e)
+	t.size += e.size
+	for t.size > t.budget && t.order.Len() > 1 {
+		removed = append(removed, t.removeElement(t.order.Back()))
+	}
+	return removed
+}
+
+// remove removes the entry for the given key, and returns it.
+func (t *tier[T]) remove(key string) (*tierEntry[T], bool) {
+	el, ok := t.entries[key]
+	if !ok {
+		return nil, false
+	}
+	return t.removeElement(el), true
+}
+
+func (t *tier[T]) removeElement(el *list.Element) *tierEntry[T] {
+	e := t.order.Remove(el).(*tierEntry[T])
+	delete(t.entries, e.key)
+	t.size -= e.size
+	return e
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func Test_Tiered(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewPedanticRegistry()
+	dir := t.TempDir()
+	cache, err := NewTiered[[]byte](dir, BytesCodec{},
+		WithMemoryBudget(10),
+		WithDiskBudget(30),
+		WithMetricsRegisterer(reg),
+		WithMetricsPrefix("gotk_"))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Values within the memory budget are held in both tiers.
+	g.Expect(cache.Set("small", []byte("small"))).To(Succeed())
+	// Values larger than the memory budget are only held on disk.
+	g.Expect(cache.Set("large", bytes.Repeat([]byte("l"), 20))).To(Succeed())
+	g.Expect(cache.memory.entries).To(HaveLen(1))
+	g.Expect(cache.disk.entries).To(HaveLen(2))
+	g.Expect(filepath.Glob(filepath.Join(dir, "*"+tieredFileExt))).To(HaveLen(2))
+
+	got, err := cache.Get("small")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal([]byte("small")))
+	got, err = cache.Get("large")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(bytes.Repeat([]byte("l"), 20)))
+
+	_, err = cache.Get("missing")
+	g.Expect(err).To(MatchError(ErrNotFound))
+
+	// Values exceeding the disk budget are rejected.
+	err = cache.Set("huge", bytes.Repeat([]byte("h"), 31))
+	g.Expect(errors.Is(err, ErrCacheFull)).To(BeTrue())
+
+	// The least recently used value is evicted from disk.
+	g.Expect(cache.Set("other", bytes.Repeat([]byte("o"), 8))).To(Succeed())
+	_, err = cache.Get("small")
+	g.Expect(err).To(MatchError(ErrNotFound))
+	g.Expect(filepath.Glob(filepath.Join(dir, "*"+tieredFileExt))).To(HaveLen(2))
+
+	validateMetrics(reg, `
+	# HELP gotk_cache_digest_mismatches_total Total number of values read from disk not matching their digest.
+	# TYPE gotk_cache_digest_mismatches_total counter
+	gotk_cache_digest_mismatches_total 0
+	# HELP gotk_cache_evictions_total Total number of cache evictions.
+	# TYPE gotk_cache_evictions_total counter
+	gotk_cache_evictions_total 1
+	# HELP gotk_cache_requests_total Total number of cache requests partioned by success or failure.
+	# TYPE gotk_cache_requests_total counter
+	gotk_cache_requests_total{status="failure"} 1
+	gotk_cache_requests_total{status="success"} 7
+	# HELP gotk_cache_tier_bytes Total size in bytes of the values held in the cache partitioned by tier.
+	# TYPE gotk_cache_tier_bytes gauge
+	gotk_cache_tier_bytes{tier="disk"} 28
+	gotk_cache_tier_bytes{tier="memory"} 8
+	# HELP gotk_cache_tier_hits_total Total number of cache hits partitioned by tier.
+	# TYPE gotk_cache_tier_hits_total counter
+	gotk_cache_tier_hits_total{tier="disk"} 1
+	gotk_cache_tier_hits_total{tier="memory"} 1
+	# HELP gotk_cached_items Total number of items in the cache.
+	# TYPE gotk_cached_items gauge
+	gotk_cached_items 2
+`, t)
+}
+
+func Test_Tiered_Promote(t *testing.T) {
+	g := NewWithT(t)
+	cache, err := NewTiered[map[string]string](t.TempDir(), JSONCodec[map[string]string]{},
+		WithMemoryBudget(40))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for i := range 3 {
+		g.Expect(cache.Set(fmt.Sprintf("key%d", i), map[string]string{"value": fmt.Sprintf("value%d", i)})).To(Succeed())
+	}
+	// The values are 19 bytes each, the first one was evicted from memory.
+	g.Expect(cache.memory.entries).ToNot(HaveKey("key0"))
+
+	got, err := cache.Get("key0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(map[string]string{"value": "value0"}))
+	g.Expect(cache.memory.entries).To(HaveKey("key0"))
+	g.Expect(cache.memory.entries).ToNot(HaveKey("key1"))
+}
+
+func Test_Tiered_DigestMismatch(t *testing.T) {
+	g := NewWithT(t)
+	reg := prometheus.NewPedanticRegistry()
+	cache, err := NewTiered[[]byte](t.TempDir(), BytesCodec{},
+		WithMemoryBudget(1),
+		WithMetricsRegisterer(reg))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(cache.Set("key", []byte("value"))).To(Succeed())
+	e, ok := cache.disk.peek("key")
+	g.Expect(ok).To(BeTrue())
+	g.Expect(os.WriteFile(e.path, []byte("corrupted"), 0o600)).To(Succeed())
+
+	_, err = cache.Get("key")
+	g.Expect(errors.Is(err, ErrDigestMismatch)).To(BeTrue())
+	g.Expect(e.path).ToNot(BeAnExistingFile())
+
+	_, err = cache.Get("key")
+	g.Expect(err).To(MatchError(ErrNotFound))
+}
+
+func Test_Tiered_Delete(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	cache, err := NewTiered[string](dir, JSONCodec[string]{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(cache.Set("key", "value")).To(Succeed())
+	g.Expect(cache.Set("key", "new-value")).To(Succeed())
+	g.Expect(filepath.Glob(filepath.Join(dir, "*"+tieredFileExt))).To(HaveLen(1))
+	got, err := cache.Get("key")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal("new-value"))
+
+	g.Expect(cache.Delete("key")).To(Succeed())
+	_, err = cache.Get("key")
+	g.Expect(err).To(MatchError(ErrNotFound))
+	g.Expect(filepath.Glob(filepath.Join(dir, "*"+tieredFileExt))).To(BeEmpty())
+	g.Expect(cache.memory.size).To(BeZero())
+	g.Expect(cache.disk.size).To(BeZero())
+
+	// Deleting a missing key is a no-op.
+	g.Expect(cache.Delete("key")).To(Succeed())
+}
+
+func Test_Tiered_Close(t *testing.T) {
+	g := NewWithT(t)
+	dir := t.TempDir()
+	g.Expect(os.WriteFile(filepath.Join(dir, "stale"+tieredFileExt), []byte("stale"), 0o600)).To(Succeed())
+
+	cache, err := NewTiered[string](dir, JSONCodec[string]{})
+	g.Expect(err).ToNot(HaveOccurred())
+	// The values left by a previous cache are removed.
+	g.Expect(filepath.Glob(filepath.Join(dir, "*"+tieredFileExt))).To(BeEmpty())
+
+	g.Expect(cache.Set("key", "value")).To(Succeed())
+	g.Expect(cache.Close()).To(Succeed())
+	g.Expect(filepath.Glob(filepath.Join(dir, "*"+tieredFileExt))).To(BeEmpty())
+
+	g.Expect(cache.Close()).To(MatchError(ErrCacheClosed))
+	g.Expect(cache.Set("key", "value")).To(MatchError(ErrCacheClosed))
+	_, err = cache.Get("key")
+	g.Expect(err).To(MatchError(ErrCacheClosed))
+	g.Expect(cache.Delete("key")).To(MatchError(ErrCacheClosed))
+}
+
+func Test_Tiered_Options(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewTiered[string](t.TempDir(), JSONCodec[string]{}, WithMemoryBudget(0))
+	g.Expect(err).To(MatchError(ContainSubstring("memory budget must be greater than zero")))
+	_, err = NewTiered[string](t.TempDir(), JSONCodec[string]{}, WithDiskBudget(-1))
+	g.Expect(err).To(MatchError(ContainSubstring("disk budget must be greater than zero")))
+
+	cache, err := NewTiered[string](t.TempDir(), JSONCodec[string]{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cache.memory.budget).To(Equal(int64(defaultMemoryBudget)))
+	g.Expect(cache.disk.budget).To(Equal(int64(defaultDiskBudget)))
+}
+
+func Test_Tiered_Concurrent(t *testing.T) {
+	g := NewWithT(t)
+	cache, err := NewTiered[[]byte](t.TempDir(), BytesCodec{},
+		WithMemoryBudget(64),
+		WithDiskBudget(256))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("key%d", i%3)
+			value := bytes.Repeat([]byte{byte('a' + i%3)}, 32)
+			for range 20 {
+				_ = cache.Set(key, value)
+				if got, err := cache.Get(key); err == nil && !bytes.Equal(got, value) {
+					t.Errorf("unexpected value for %s: %s", key, got)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	g.Expect(cache.disk.size).To(BeNumerically("<=", 256))
+	g.Expect(cache.memory.size).To(BeNumerically("<=", 64))
+}
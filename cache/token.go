@@ -0,0 +1,425 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenRefreshFraction is the fraction of the lifetime of a token after
+// which it is evicted from the cache, so that a token returned by the cache
+// is always valid long enough to be used.
+const tokenRefreshFraction = 0.8
+
+// Token is an access token which can be stored in a TokenCache.
+type Token interface {
+	// GetDuration returns the lifetime of the token.
+	GetDuration() time.Duration
+}
+
+// InvolvedObject is the Flux object for which a token is requested.
+type InvolvedObject struct {
+	Kind      string
+	Name      string
+	Namespace string
+}
+
+// TokenKey is the key of a token in a TokenCache. A controller may need
+// tokens for several audiences for the same object, e.g. for a container
+// registry, a Git server and a cluster API, hence the key includes the
+// audience and the scopes of the token besides the object.
+type TokenKey struct {
+	// InvolvedObject is the object for which the token is requested.
+	InvolvedObject InvolvedObject
+	// Provider is the name of the provider issuing the token, e.g. aws,
+	// azure or gcp.
+	Provider string
+	// Audience is the audience of the token.
+	Audience string
+	// Scopes are the scopes of the token. Their order doesn't matter.
+	Scopes []string
+}
+
+// String returns the canonical form of the key. It allocates the returned
+// string only, unless the key has more than maxInlineScopes scopes which are
+// not sorted, as it is called on every cache lookup.
+func (k TokenKey) String() string {
+	scopes := k.Scopes
+	if !slices.IsSorted(scopes) {
+		var buf [maxInlineScopes]string
+		scopes = append(buf[:0], scopes...)
+		slices.Sort(scopes)
+	}
+
+	// The fields are separated by a NUL byte, which can't appear in the
+	// names of Kubernetes objects, so that the keys don't collide.
+	fields := [...]string{k.InvolvedObject.Kind, k.InvolvedObject.Namespace,
+		k.InvolvedObject.Name, k.Provider, k.Audience}
+	n := len(fields)
+	for _, s := range fields {
+		n += len(s)
+	}
+	for _, s := range scopes {
+		n += len(s) + 1
+	}
+
+	var b strings.Builder
+	b.Grow(n)
+	for _, s := range fields {
+		b.WriteString(s)
+		b.WriteByte(0)
+	}
+	for i, s := range scopes {
+		if i > 0 && s == scopes[i-1] {
+			continue
+		}
+		if i > 0 {
+			b.WriteByte(0)
+		}
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// maxInlineScopes is the number of scopes of a TokenKey which are sorted
+// without allocating.
+const maxInlineScopes = 8
+
+// errTokenRequestAborted is the error returned to the concurrent callers of
+// GetOrSet sharing a token request which did not return, i.e. panicked.
+var errTokenRequestAborted = errors.New("token request aborted")
+
+// TokenCache is a thread-safe cache of access tokens, which expire before
+// the end of their lifetime.
+type TokenCache struct {
+	cache *Cache[Token]
+
+	mu    sync.Mutex
+	calls map[string]*tokenCall
+
+	objectQuota    int
+	namespaceQuota int
+	refreshAhead   time.Duration
+	quotaMetrics   *quotaMetrics
+	// quotaMu guards the keys of the cached tokens by involved object and
+	// by namespace, which are tracked when a quota is set.
+	quotaMu       sync.Mutex
+	objectKeys    map[InvolvedObject]map[string]struct{}
+	namespaceKeys map[string]map[string]struct{}
+}
+
+// tokenCall is an in-flight token request shared by the concurrent cache
+// misses of a key.
+type tokenCall struct {
+	done  chan struct{}
+	token Token
+	err   error
+}
+
+// NewTokenCache creates a new TokenCache holding at most capacity tokens.
+// The tokens of an involved object, or of a namespace, can be limited with
+// WithObjectQuota and WithNamespaceQuota, so that a tenant with many objects
+// can't fill the cache.
+func NewTokenCache(capacity int, opts ...Options) (*TokenCache, error) {
+	opt, err := makeOptions(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply options: %w", err)
+	}
+	c, err := New[Token](capacity, opts...)
+	if err != nil {
+		return nil, err
+	}
+	tc := &TokenCache{
+		cache:          c,
+		calls:          make(map[string]*tokenCall),
+		objectQuota:    opt.objectQuota,
+		namespaceQuota: opt.namespaceQuota,
+		refreshAhead:   opt.refreshAhead,
+		objectKeys:     make(map[InvolvedObject]map[string]struct{}),
+		namespaceKeys:  make(map[string]map[string]struct{}),
+	}
+	if opt.registerer != nil && (tc.objectQuota > 0 || tc.namespaceQuota > 0) {
+		tc.quotaMetrics = newQuotaMetrics(opt.metricsPrefix, opt.registerer)
+	}
+	return tc, nil
+}
+
+// Close closes the cache.
+func (c *TokenCache) Close() error {
+	return c.cache.Close()
+}
+
+// GetOrSet returns the token of the key from the cache, or calls newToken
+// and stores the token it returns in the cache. It returns true if the
+// token was found in the cache. The cache hit or miss, and the issuance of
+// the token, are recorded for the involved object of the key, or of ctx if
+// the key has none, see WithInvolvedObject. The token is returned even if it
+// can't be stored in the cache, e.g. because the cache is full.
+//
+// The concurrent calls for the same key on a cache miss share a single call
+// to newToken, and get its token or error. They are recorded as
+// CacheEventTypeCoalesced events instead of misses, so that the misses
+// count the token requests.
+//
+// If WithRefreshAhead is set, a cache hit of a token expiring from the
+// cache within the refresh ahead window calls newToken in the background,
+// with a context which is not canceled with ctx, to replace the token.
+func (c *TokenCache) GetOrSet(ctx context.Context, key TokenKey,
+	newToken func(context.Context) (Token, error)) (Token, bool, error) {
+	if key.InvolvedObject == (InvolvedObject{}) {
+		key.InvolvedObject, _ = InvolvedObjectFromContext(ctx)
+	}
+	k := key.String()
+	obj := key.InvolvedObject
+
+	if token, err := c.cache.Get(k); err == nil {
+		c.cache.RecordCacheEvent(CacheEventTypeHit, obj.Kind, obj.Name, obj.Namespace)
+		c.maybeRefresh(ctx, k, obj, newToken)
+		return token, true, nil
+	} else if errors.Is(err, ErrCacheClosed) {
+		return nil, false, err
+	}
+
+	c.mu.Lock()
+	if call, ok := c.calls[k]; ok {
+		c.mu.Unlock()
+		c.cache.RecordCacheEvent(CacheEventTypeCoalesced, obj.Kind, obj.Name, obj.Namespace)
+		select {
+		case <-call.done:
+			return call.token, false, call.err
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		}
+	}
+	// The token may have been stored by a request which completed since
+	// the cache lookup.
+	if token, err := c.cache.Get(k); err == nil {
+		c.mu.Unlock()
+		c.cache.RecordCacheEvent(CacheEventTypeHit, obj.Kind, obj.Name, obj.Namespace)
+		return token, true, nil
+	}
+	call := &tokenCall{done: make(chan struct{}), err: errTokenRequestAborted}
+	c.calls[k] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, k)
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	c.cache.RecordCacheEvent(CacheEventTypeMiss, obj.Kind, obj.Name, obj.Namespace)
+	token, err := newToken(ctx)
+	call.token, call.err = token, err
+	if err != nil {
+		return nil, false, err
+	}
+	c.cache.RecordCacheEvent(CacheEventTypeTokenIssued, obj.Kind, obj.Name, obj.Namespace)
+	c.store(k, obj, token)
+	return token, false, nil
+}
+
+// maybeRefresh requests a new token for the key in the background if the
+// cached token expires within the refresh ahead window, and no request for
+// the key is in flight. The cached token is kept if the request fails, as
+// it is still valid.
+func (c *TokenCache) maybeRefresh(ctx context.Context, k string, obj InvolvedObject,
+	newToken func(context.Context) (Token, error)) {
+	if c.refreshAhead <= 0 {
+		return
+	}
+	expiresAt, err := c.cache.GetExpiration(k)
+	if err != nil || expiresAt.IsZero() || time.Until(expiresAt) > c.refreshAhead {
+		return
+	}
+
+	c.mu.Lock()
+	if _, ok := c.calls[k]; ok {
+		c.mu.Unlock()
+		return
+	}
+	call := &tokenCall{done: make(chan struct{}), err: errTokenRequestAborted}
+	c.calls[k] = call
+	c.mu.Unlock()
+
+	c.cache.RecordCacheEvent(CacheEventTypeRefreshAhead, obj.Kind, obj.Name, obj.Namespace)
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.calls, k)
+			c.mu.Unlock()
+			close(call.done)
+		}()
+
+		token, err := newToken(context.WithoutCancel(ctx))
+		call.token, call.err = token, err
+		if err != nil {
+			return
+		}
+		c.cache.RecordCacheEvent(CacheEventTypeTokenIssued, obj.Kind, obj.Name, obj.Namespace)
+		c.store(k, obj, token)
+	}()
+}
+
+// store stores the token in the cache, after evicting the tokens above the
+// quotas of the involved object.
+func (c *TokenCache) store(k string, obj InvolvedObject, token Token) {
+	quotas := c.objectQuota > 0 || c.namespaceQuota > 0
+	if quotas {
+		c.quotaMu.Lock()
+		defer c.quotaMu.Unlock()
+		if c.objectQuota > 0 {
+			c.makeRoom(c.objectKeys[obj], k, c.objectQuota, QuotaObject, obj.Namespace)
+		}
+		if c.namespaceQuota > 0 {
+			c.makeRoom(c.namespaceKeys[obj.Namespace], k, c.namespaceQuota, QuotaNamespace, obj.Namespace)
+		}
+	}
+
+	lifetime := time.Duration(float64(token.GetDuration()) * tokenRefreshFraction)
+	if err := c.cache.SetWithTTL(k, token, lifetime); err != nil {
+		return
+	}
+
+	if quotas {
+		addKey(c.objectKeys, obj, k)
+		addKey(c.namespaceKeys, obj.Namespace, k)
+	}
+}
+
+// makeRoom evicts the tokens of the given keys expiring first, until a new
+// token can be stored within the quota. The keys of the tokens no longer in
+// the cache are removed from the keys.
+func (c *TokenCache) makeRoom(keys map[string]struct{}, newKey string, quota int, quotaName, namespace string) {
+	type entry struct {
+		key       string
+		expiresAt time.Time
+	}
+	var entries []entry
+	for key := range keys {
+		expiresAt, err := c.cache.GetExpiration(key)
+		if err != nil || expiresAt.IsZero() {
+			// The token expired, or was deleted.
+			delete(keys, key)
+			continue
+		}
+		if key == newKey {
+			// The token is replaced.
+			return
+		}
+		entries = append(entries, entry{key: key, expiresAt: expiresAt})
+	}
+
+	slices.SortFunc(entries, func(a, b entry) int {
+		return a.expiresAt.Compare(b.expiresAt)
+	})
+	for i := 0; len(entries)-i >= quota; i++ {
+		key := entries[i].key
+		delete(keys, key)
+		if err := c.cache.Delete(key); err == nil {
+			recordQuotaEviction(c.quotaMetrics, quotaName, namespace)
+		}
+	}
+}
+
+// addKey adds the key to the keys of the group.
+func addKey[K comparable](keys map[K]map[string]struct{}, group K, key string) {
+	set, ok := keys[group]
+	if !ok {
+		set = make(map[string]struct{})
+		keys[group] = set
+	}
+	set[key] = struct{}{}
+}
+
+// removeKey removes the key from the keys of the group.
+func removeKey[K comparable](keys map[K]map[string]struct{}, group K, key string) {
+	set, ok := keys[group]
+	if !ok {
+		return
+	}
+	delete(set, key)
+	if len(set) == 0 {
+		delete(keys, group)
+	}
+}
+
+// Delete removes the token of the key from the cache, e.g. when it has been
+// rejected by the server.
+func (c *TokenCache) Delete(key TokenKey) error {
+	k := key.String()
+	if c.objectQuota > 0 || c.namespaceQuota > 0 {
+		c.quotaMu.Lock()
+		defer c.quotaMu.Unlock()
+		removeKey(c.objectKeys, key.InvolvedObject, k)
+		removeKey(c.namespaceKeys, key.InvolvedObject.Namespace, k)
+	}
+	return c.cache.Delete(k)
+}
+
+// TokenRequest is a request of a token for an audience in
+// TokenCache.Prefetch.
+type TokenRequest struct {
+	// Provider is the name of the provider issuing the token.
+	Provider string
+	// Audience is the audience of the token.
+	Audience string
+	// Scopes are the scopes of the token.
+	Scopes []string
+	// NewToken is called to get the token on a cache miss.
+	NewToken func(context.Context) (Token, error)
+}
+
+// Prefetch gets the tokens of all the requests for the involved object
+// concurrently, from the cache or by calling their NewToken function, e.g.
+// at the start of a reconciliation needing tokens for several audiences.
+// It returns the tokens in the order of the requests, and the errors of the
+// failed requests joined. The tokens of the failed requests are nil. If obj
+// is empty, the tokens are requested for the involved object of ctx.
+func (c *TokenCache) Prefetch(ctx context.Context, obj InvolvedObject, reqs ...TokenRequest) ([]Token, error) {
+	tokens := make([]Token, len(reqs))
+	errs := make([]error, len(reqs))
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := TokenKey{
+				InvolvedObject: obj,
+				Provider:       req.Provider,
+				Audience:       req.Audience,
+				Scopes:         req.Scopes,
+			}
+			token, _, err := c.GetOrSet(ctx, key, req.NewToken)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to get token of provider '%s' for audience '%s': %w",
+					req.Provider, req.Audience, err)
+				return
+			}
+			tokens[i] = token
+		}()
+	}
+	wg.Wait()
+	return tokens, errors.Join(errs...)
+}
@@ -0,0 +1,560 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type testToken struct {
+	value    string
+	duration time.Duration
+}
+
+func (t *testToken) GetDuration() time.Duration {
+	return t.duration
+}
+
+func TestTokenKey_String(t *testing.T) {
+	obj := InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"}
+
+	tests := []struct {
+		name  string
+		a, b  TokenKey
+		equal bool
+	}{
+		{
+			name:  "scopes order doesn't matter",
+			a:     TokenKey{InvolvedObject: obj, Provider: "azure", Scopes: []string{"a", "b"}},
+			b:     TokenKey{InvolvedObject: obj, Provider: "azure", Scopes: []string{"b", "a", "a"}},
+			equal: true,
+		},
+		{
+			name: "order of many scopes doesn't matter",
+			a: TokenKey{InvolvedObject: obj, Provider: "azure",
+				Scopes: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}},
+			b: TokenKey{InvolvedObject: obj, Provider: "azure",
+				Scopes: []string{"j", "i", "h", "g", "f", "e", "d", "c", "b", "a", "a"}},
+			equal: true,
+		},
+		{
+			name:  "different audiences",
+			a:     TokenKey{InvolvedObject: obj, Provider: "gcp", Audience: "registry"},
+			b:     TokenKey{InvolvedObject: obj, Provider: "gcp", Audience: "git"},
+			equal: false,
+		},
+		{
+			name:  "different scopes",
+			a:     TokenKey{InvolvedObject: obj, Provider: "azure", Scopes: []string{"a"}},
+			b:     TokenKey{InvolvedObject: obj, Provider: "azure", Scopes: []string{"a", "b"}},
+			equal: false,
+		},
+		{
+			name:  "fields don't collide",
+			a:     TokenKey{InvolvedObject: obj, Provider: "aws", Audience: "a"},
+			b:     TokenKey{InvolvedObject: obj, Provider: "aws", Scopes: []string{"a"}},
+			equal: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(tt.a.String() == tt.b.String()).To(Equal(tt.equal))
+		})
+	}
+}
+
+func TestTokenCache_GetOrSet(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	c, err := NewTokenCache(10)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	obj := InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"}
+	registryKey := TokenKey{InvolvedObject: obj, Provider: "gcp", Audience: "registry"}
+	gitKey := TokenKey{InvolvedObject: obj, Provider: "gcp", Audience: "git"}
+
+	var calls atomic.Int32
+	newToken := func(value string) func(context.Context) (Token, error) {
+		return func(context.Context) (Token, error) {
+			calls.Add(1)
+			return &testToken{value: value, duration: time.Hour}, nil
+		}
+	}
+
+	token, cached, err := c.GetOrSet(ctx, registryKey, newToken("registry"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeFalse())
+	g.Expect(token.(*testToken).value).To(Equal("registry"))
+
+	token, cached, err = c.GetOrSet(ctx, gitKey, newToken("git"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeFalse())
+	g.Expect(token.(*testToken).value).To(Equal("git"))
+
+	token, cached, err = c.GetOrSet(ctx, registryKey, newToken("other"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeTrue())
+	g.Expect(token.(*testToken).value).To(Equal("registry"))
+	g.Expect(calls.Load()).To(Equal(int32(2)))
+
+	expiration, err := c.cache.GetExpiration(registryKey.String())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expiration).To(BeTemporally("~", time.Now().Add(48*time.Minute), time.Minute))
+
+	g.Expect(c.Delete(registryKey)).To(Succeed())
+	_, cached, err = c.GetOrSet(ctx, registryKey, newToken("registry"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeFalse())
+
+	_, _, err = c.GetOrSet(ctx, TokenKey{InvolvedObject: obj, Audience: "failing"},
+		func(context.Context) (Token, error) {
+			return nil, errors.New("boom")
+		})
+	g.Expect(err).To(MatchError("boom"))
+}
+
+func TestTokenCache_GetOrSet_allocs(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	c, err := NewTokenCache(10, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	key := TokenKey{
+		InvolvedObject: InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"},
+		Provider:       "azure",
+		Audience:       "registry",
+		Scopes:         []string{"repository:app:pull", "registry:catalog:*"},
+	}
+	newToken := func(context.Context) (Token, error) {
+		return &testToken{duration: time.Hour}, nil
+	}
+	_, _, err = c.GetOrSet(ctx, key, newToken)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Only the key is allocated on a cache hit.
+	g.Expect(testing.AllocsPerRun(100, func() {
+		_ = key.String()
+	})).To(BeNumerically("<=", 1))
+	g.Expect(testing.AllocsPerRun(100, func() {
+		_, _, _ = c.GetOrSet(ctx, key, newToken)
+	})).To(BeNumerically("<=", 1))
+}
+
+func BenchmarkTokenCache_GetOrSet(b *testing.B) {
+	ctx := context.Background()
+	c, err := NewTokenCache(10, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer c.Close()
+
+	key := TokenKey{
+		InvolvedObject: InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"},
+		Provider:       "azure",
+		Audience:       "registry",
+		Scopes:         []string{"repository:app:pull", "registry:catalog:*"},
+	}
+	newToken := func(context.Context) (Token, error) {
+		return &testToken{duration: time.Hour}, nil
+	}
+	if _, _, err := c.GetOrSet(ctx, key, newToken); err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("hit", func(b *testing.B) {
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, cached, err := c.GetOrSet(ctx, key, newToken); err != nil || !cached {
+					b.Fatal("expected a cache hit")
+				}
+			}
+		})
+	})
+}
+
+func TestTokenCache_GetOrSet_contextObject(t *testing.T) {
+	g := NewWithT(t)
+
+	c, err := NewTokenCache(10, WithMetricsRegisterer(prometheus.NewPedanticRegistry()))
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	obj := InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"}
+	ctx := WithInvolvedObject(context.Background(), obj)
+	newToken := func(ctx context.Context) (Token, error) {
+		// The involved object is available to the token request.
+		got, ok := InvolvedObjectFromContext(ctx)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(got).To(Equal(obj))
+		return &testToken{value: "registry", duration: time.Hour}, nil
+	}
+
+	// A key without involved object is the key of the object of the context.
+	_, cached, err := c.GetOrSet(ctx, TokenKey{Provider: "gcp", Audience: "registry"}, newToken)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeFalse())
+	_, cached, err = c.GetOrSet(ctx, TokenKey{InvolvedObject: obj, Provider: "gcp", Audience: "registry"}, newToken)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeTrue())
+
+	// The involved object of the key takes precedence.
+	other := InvolvedObject{Kind: "OCIRepository", Name: "other", Namespace: "default"}
+	_, cached, err = c.GetOrSet(ctx, TokenKey{InvolvedObject: other, Provider: "gcp", Audience: "registry"},
+		func(context.Context) (Token, error) {
+			return &testToken{value: "other", duration: time.Hour}, nil
+		})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeFalse())
+
+	events := c.cache.metrics.cacheEventsCounter
+	for _, tt := range []struct {
+		obj   InvolvedObject
+		event string
+		want  float64
+	}{
+		{obj: obj, event: CacheEventTypeMiss, want: 1},
+		{obj: obj, event: CacheEventTypeHit, want: 1},
+		{obj: obj, event: CacheEventTypeTokenIssued, want: 1},
+		{obj: other, event: CacheEventTypeMiss, want: 1},
+		{obj: other, event: CacheEventTypeHit, want: 0},
+		{obj: other, event: CacheEventTypeTokenIssued, want: 1},
+	} {
+		counter := events.WithLabelValues(tt.event, tt.obj.Kind, tt.obj.Name, tt.obj.Namespace)
+		g.Expect(testutil.ToFloat64(counter)).To(Equal(tt.want), "%s of %s", tt.event, tt.obj.Name)
+	}
+}
+
+func TestTokenCache_GetOrSet_coalesced(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	reg := prometheus.NewPedanticRegistry()
+	c, err := NewTokenCache(10, WithMetricsRegisterer(reg))
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	obj := InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"}
+	key := TokenKey{InvolvedObject: obj, Provider: "aws", Audience: "registry"}
+
+	const callers = 10
+	var calls atomic.Int32
+	release := make(chan struct{})
+	newToken := func(context.Context) (Token, error) {
+		calls.Add(1)
+		<-release
+		return &testToken{value: "registry", duration: time.Hour}, nil
+	}
+
+	var wg sync.WaitGroup
+	tokens := make([]Token, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens[i], _, errs[i] = c.GetOrSet(ctx, key, newToken)
+		}()
+	}
+
+	// Wait for all the callers to share the in-flight request.
+	coalesced := c.cache.metrics.cacheEventsCounter.WithLabelValues(CacheEventTypeCoalesced, obj.Kind, obj.Name, obj.Namespace)
+	g.Eventually(func() float64 {
+		return testutil.ToFloat64(coalesced)
+	}).Should(Equal(float64(callers - 1)))
+	close(release)
+	wg.Wait()
+
+	g.Expect(calls.Load()).To(Equal(int32(1)))
+	for i := range callers {
+		g.Expect(errs[i]).ToNot(HaveOccurred())
+		g.Expect(tokens[i]).To(BeIdenticalTo(tokens[0]))
+	}
+	misses := c.cache.metrics.cacheEventsCounter.WithLabelValues(CacheEventTypeMiss, obj.Kind, obj.Name, obj.Namespace)
+	g.Expect(testutil.ToFloat64(misses)).To(Equal(float64(1)))
+
+	// The error of the shared request is returned to all the callers, and
+	// is not cached.
+	failingKey := TokenKey{InvolvedObject: obj, Provider: "aws", Audience: "failing"}
+	release = make(chan struct{})
+	failing := func(context.Context) (Token, error) {
+		<-release
+		return nil, errors.New("boom")
+	}
+	for i := range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, errs[i] = c.GetOrSet(ctx, failingKey, failing)
+		}()
+	}
+	g.Eventually(func() float64 {
+		return testutil.ToFloat64(coalesced)
+	}).Should(Equal(float64(callers)))
+	close(release)
+	wg.Wait()
+	g.Expect(errs[0]).To(MatchError("boom"))
+	g.Expect(errs[1]).To(MatchError("boom"))
+
+	// A waiting caller returns when its context is canceled.
+	release = make(chan struct{})
+	defer close(release)
+	go func() {
+		_, _, _ = c.GetOrSet(ctx, failingKey, failing)
+	}()
+	g.Eventually(func() int {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return len(c.calls)
+	}).Should(Equal(1))
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, _, err = c.GetOrSet(cancelCtx, failingKey, failing)
+	g.Expect(err).To(MatchError(context.Canceled))
+}
+
+func TestTokenCache_quotas(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	reg := prometheus.NewPedanticRegistry()
+	c, err := NewTokenCache(100, WithObjectQuota(2), WithNamespaceQuota(3), WithMetricsRegisterer(reg))
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	newToken := func(duration time.Duration) func(context.Context) (Token, error) {
+		return func(context.Context) (Token, error) {
+			return &testToken{duration: duration}, nil
+		}
+	}
+	cached := func(key TokenKey) bool {
+		_, err := c.cache.Get(key.String())
+		return err == nil
+	}
+
+	app := InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "tenant"}
+	appKeys := []TokenKey{
+		{InvolvedObject: app, Provider: "aws", Audience: "a"},
+		{InvolvedObject: app, Provider: "aws", Audience: "b"},
+		{InvolvedObject: app, Provider: "aws", Audience: "c"},
+	}
+	_, _, err = c.GetOrSet(ctx, appKeys[0], newToken(2*time.Hour))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, _, err = c.GetOrSet(ctx, appKeys[1], newToken(time.Hour))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Replacing a token of the object doesn't evict another one.
+	g.Expect(c.Delete(appKeys[1])).To(Succeed())
+	_, _, err = c.GetOrSet(ctx, appKeys[1], newToken(time.Hour))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached(appKeys[0])).To(BeTrue())
+
+	// The object quota evicts the token of the object expiring first.
+	_, _, err = c.GetOrSet(ctx, appKeys[2], newToken(3*time.Hour))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached(appKeys[0])).To(BeTrue())
+	g.Expect(cached(appKeys[1])).To(BeFalse())
+	g.Expect(cached(appKeys[2])).To(BeTrue())
+
+	// The namespace quota evicts the token of the namespace expiring
+	// first, without affecting the other namespaces.
+	other := InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "other"}
+	otherKey := TokenKey{InvolvedObject: other, Provider: "aws", Audience: "a"}
+	_, _, err = c.GetOrSet(ctx, otherKey, newToken(time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, name := range []string{"infra", "web"} {
+		obj := InvolvedObject{Kind: "OCIRepository", Name: name, Namespace: "tenant"}
+		_, _, err = c.GetOrSet(ctx, TokenKey{InvolvedObject: obj, Provider: "aws"}, newToken(4*time.Hour))
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(cached(appKeys[0])).To(BeFalse())
+	g.Expect(cached(appKeys[2])).To(BeTrue())
+	g.Expect(cached(otherKey)).To(BeTrue())
+	g.Expect(c.namespaceKeys["tenant"]).To(HaveLen(3))
+
+	evictions := c.quotaMetrics.quotaEvictionsCounter
+	g.Expect(testutil.ToFloat64(evictions.WithLabelValues(QuotaObject, "tenant"))).To(Equal(float64(1)))
+	g.Expect(testutil.ToFloat64(evictions.WithLabelValues(QuotaNamespace, "tenant"))).To(Equal(float64(1)))
+}
+
+func TestNewTokenCache_invalidQuota(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewTokenCache(10, WithObjectQuota(0))
+	g.Expect(err).To(MatchError(ContainSubstring("object quota must be greater than zero")))
+	_, err = NewTokenCache(10, WithNamespaceQuota(-1))
+	g.Expect(err).To(MatchError(ContainSubstring("namespace quota must be greater than zero")))
+}
+
+func TestTokenCache_Prefetch(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	c, err := NewTokenCache(10)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	obj := InvolvedObject{Kind: "Kustomization", Name: "app", Namespace: "default"}
+	newToken := func(value string) func(context.Context) (Token, error) {
+		return func(context.Context) (Token, error) {
+			return &testToken{value: value, duration: time.Hour}, nil
+		}
+	}
+
+	tokens, err := c.Prefetch(ctx, obj,
+		TokenRequest{Provider: "azure", Audience: "registry", NewToken: newToken("registry")},
+		TokenRequest{Provider: "azure", Audience: "git", NewToken: newToken("git")},
+		TokenRequest{Provider: "azure", Audience: "cluster", Scopes: []string{"api"}, NewToken: newToken("cluster")},
+		TokenRequest{Provider: "azure", Audience: "failing", NewToken: func(context.Context) (Token, error) {
+			return nil, errors.New("boom")
+		}},
+	)
+	g.Expect(err).To(MatchError(ContainSubstring("audience 'failing': boom")))
+	g.Expect(tokens).To(HaveLen(4))
+	g.Expect(tokens[0].(*testToken).value).To(Equal("registry"))
+	g.Expect(tokens[1].(*testToken).value).To(Equal("git"))
+	g.Expect(tokens[2].(*testToken).value).To(Equal("cluster"))
+	g.Expect(tokens[3]).To(BeNil())
+
+	token, cached, err := c.GetOrSet(ctx, TokenKey{
+		InvolvedObject: obj,
+		Provider:       "azure",
+		Audience:       "cluster",
+		Scopes:         []string{"api"},
+	}, newToken("other"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeTrue())
+	g.Expect(token.(*testToken).value).To(Equal("cluster"))
+}
+
+func TestTokenCache_RefreshAhead(t *testing.T) {
+	g := NewWithT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	reg := prometheus.NewPedanticRegistry()
+	c, err := NewTokenCache(10, WithRefreshAhead(time.Minute), WithMetricsRegisterer(reg))
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	obj := InvolvedObject{Kind: "OCIRepository", Name: "app", Namespace: "default"}
+	key := TokenKey{InvolvedObject: obj, Provider: "gcp", Audience: "registry"}
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	newToken := func(ctx context.Context) (Token, error) {
+		n := calls.Add(1)
+		if n > 1 {
+			<-release
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+		}
+		// The token of the first call expires from the cache in 48s, within
+		// the refresh ahead window.
+		duration := time.Minute
+		if n > 1 {
+			duration = time.Hour
+		}
+		return &testToken{value: fmt.Sprintf("token-%d", n), duration: duration}, nil
+	}
+
+	token, cached, err := c.GetOrSet(ctx, key, newToken)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeFalse())
+	g.Expect(token.(*testToken).value).To(Equal("token-1"))
+
+	// The cache hits return the cached token while it is refreshed once in
+	// the background, even if the context of the hit is canceled.
+	for range 3 {
+		token, cached, err = c.GetOrSet(ctx, key, newToken)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(cached).To(BeTrue())
+		g.Expect(token.(*testToken).value).To(Equal("token-1"))
+	}
+	cancel()
+	close(release)
+
+	g.Eventually(func() string {
+		token, _, _ := c.GetOrSet(context.Background(), key, newToken)
+		return token.(*testToken).value
+	}).Should(Equal("token-2"))
+	g.Expect(calls.Load()).To(Equal(int32(2)))
+
+	refreshes := c.cache.metrics.cacheEventsCounter.WithLabelValues(CacheEventTypeRefreshAhead, obj.Kind, obj.Name, obj.Namespace)
+	g.Expect(testutil.ToFloat64(refreshes)).To(Equal(float64(1)))
+
+	// The refreshed token is not refreshed before it is within the window.
+	_, _, err = c.GetOrSet(context.Background(), key, newToken)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(calls.Load()).To(Equal(int32(2)))
+}
+
+func TestTokenCache_RefreshAhead_failure(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	c, err := NewTokenCache(10, WithRefreshAhead(time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+	defer c.Close()
+
+	key := TokenKey{Provider: "gcp", Audience: "registry"}
+	_, _, err = c.GetOrSet(ctx, key, func(context.Context) (Token, error) {
+		return &testToken{value: "valid", duration: time.Minute}, nil
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var calls atomic.Int32
+	failing := func(context.Context) (Token, error) {
+		calls.Add(1)
+		return nil, errors.New("boom")
+	}
+	token, cached, err := c.GetOrSet(ctx, key, failing)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cached).To(BeTrue())
+	g.Expect(token.(*testToken).value).To(Equal("valid"))
+
+	// The token is kept when the refresh fails, and refreshed again on the
+	// next hit.
+	g.Eventually(func() int32 {
+		token, _, err := c.GetOrSet(ctx, key, failing)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(token.(*testToken).value).To(Equal("valid"))
+		return calls.Load()
+	}).Should(BeNumerically(">=", 2))
+}
+
+func TestNewTokenCache_invalidRefreshAhead(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewTokenCache(10, WithRefreshAhead(0))
+	g.Expect(err).To(MatchError(ContainSubstring("refresh ahead window must be greater than zero")))
+}
@@ -22,12 +22,17 @@ import (
 	"time"
 
 	"github.com/fluxcd/pkg/auth/azure"
+	"github.com/fluxcd/pkg/auth/dev"
 	"github.com/fluxcd/pkg/auth/github"
 )
 
 const (
 	ProviderAzure  = "azure"
 	ProviderGitHub = "github"
+	// ProviderDev mints fake credentials for Git servers used in local
+	// development and e2e tests. It must be explicitly enabled with
+	// dev.WithEnabled in ProviderOptions.DevOpts.
+	ProviderDev = "dev"
 
 	GitHubAccessTokenUsername = "x-access-token"
 )
@@ -92,6 +97,21 @@ func GetCredentials(ctx context.Context, providerOpts *ProviderOptions) (*Creden
 			Password: appToken.Token,
 		}
 		return &creds, appToken.ExpiresAt, nil
+	case ProviderDev:
+		client, err := dev.New(providerOpts.DevOpts...)
+		if err != nil {
+			return nil, expiresOn, err
+		}
+		devToken, err := client.GetToken(ctx)
+		if err != nil {
+			return nil, expiresOn, err
+		}
+
+		creds = Credentials{
+			Username: devToken.Username,
+			Password: devToken.Token,
+		}
+		return &creds, devToken.ExpiresAt, nil
 	default:
 		return nil, expiresOn, fmt.Errorf("invalid provider")
 	}
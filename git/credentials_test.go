@@ -26,6 +26,7 @@ import (
 	"time"
 
 	"github.com/fluxcd/pkg/auth/azure"
+	"github.com/fluxcd/pkg/auth/dev"
 	"github.com/fluxcd/pkg/auth/github"
 	"github.com/fluxcd/pkg/ssh"
 	. "github.com/onsi/gomega"
@@ -185,3 +186,24 @@ func TestGetCredentials_GitHub(t *testing.T) {
 	}
 
 }
+
+func TestGetCredentials_dev(t *testing.T) {
+	g := NewWithT(t)
+
+	_, _, err := GetCredentials(context.TODO(), &ProviderOptions{
+		Name: ProviderDev,
+	})
+	g.Expect(err).To(MatchError(dev.ErrNotEnabled))
+
+	repoURL := "http://gitea.gitea.svc:3000/flux/podinfo"
+	creds, expiry, err := GetCredentials(context.TODO(), &ProviderOptions{
+		Name:    ProviderDev,
+		DevOpts: []dev.OptFunc{dev.WithEnabled(true), dev.WithSubject(repoURL)},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(*creds).To(Equal(Credentials{
+		Username: dev.DefaultUsername,
+		Password: dev.TokenFor(dev.DefaultUsername, repoURL),
+	}))
+	g.Expect(expiry).To(BeTemporally(">", time.Now()))
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Forge is the software hosting a Git repository.
+type Forge string
+
+const (
+	ForgeGitHub      Forge = "github"
+	ForgeGitLab      Forge = "gitlab"
+	ForgeBitbucket   Forge = "bitbucket"
+	ForgeAzureDevOps Forge = "azure-devops"
+	ForgeGitea       Forge = "gitea"
+	// ForgeGeneric is any other Git server.
+	ForgeGeneric Forge = "generic"
+)
+
+// ForgeCapabilities are the features a forge is known to support.
+type ForgeCapabilities struct {
+	// BearerAuth is true if the forge accepts bearer tokens in the
+	// Authorization header of the Git HTTP requests, instead of tokens as
+	// the password of basic authentication.
+	BearerAuth bool
+	// ForceWithLease is true if the forge supports pushing with the
+	// expected current value of the references, i.e. git push
+	// --force-with-lease.
+	ForceWithLease bool
+	// RefFiltering is true if the forge supports filtering the advertised
+	// references by prefix with the Git wire protocol v2.
+	RefFiltering bool
+}
+
+// DetectedProvider is the forge of a repository URL detected by
+// DetectProvider, with its capabilities.
+type DetectedProvider struct {
+	Forge        Forge
+	Capabilities ForgeCapabilities
+}
+
+// forgeCapabilities are the capabilities of the known forges.
+var forgeCapabilities = map[Forge]ForgeCapabilities{
+	ForgeGitHub:      {ForceWithLease: true, RefFiltering: true},
+	ForgeGitLab:      {ForceWithLease: true, RefFiltering: true},
+	ForgeBitbucket:   {BearerAuth: true, ForceWithLease: true, RefFiltering: true},
+	ForgeAzureDevOps: {BearerAuth: true, ForceWithLease: true},
+	ForgeGitea:       {BearerAuth: true, ForceWithLease: true, RefFiltering: true},
+	ForgeGeneric:     {ForceWithLease: true},
+}
+
+// DetectProvider returns the likely forge of the repository URL from its
+// host name, and the capabilities of the forge, which controllers can use
+// to pick defaults for authentication and error handling. The URL can be
+// an http, https or ssh URL, or an SCP-like address, e.g.
+// git@github.com:org/repo. The self-hosted instances are detected from the
+// conventional first label of their host name, e.g. gitlab.example.com, and
+// ForgeGeneric is returned for the other hosts and the invalid URLs.
+//
+// The capabilities are the ones of the forge's hosted service and latest
+// self-hosted versions; ProbeRemote returns the actual capabilities of a
+// server.
+func DetectProvider(repoURL string) DetectedProvider {
+	forge := detectForge(repoHost(repoURL))
+	return DetectedProvider{
+		Forge:        forge,
+		Capabilities: forgeCapabilities[forge],
+	}
+}
+
+// detectForge returns the forge of the lowercase host name.
+func detectForge(host string) Forge {
+	switch {
+	case host == "":
+		return ForgeGeneric
+	case host == "github.com" || strings.HasSuffix(host, ".github.com") || strings.HasSuffix(host, ".ghe.com"):
+		return ForgeGitHub
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return ForgeGitLab
+	case host == "bitbucket.org" || strings.HasPrefix(host, "bitbucket."):
+		return ForgeBitbucket
+	case host == "dev.azure.com" || strings.HasSuffix(host, ".dev.azure.com") || strings.HasSuffix(host, ".visualstudio.com"):
+		return ForgeAzureDevOps
+	case host == "gitea.com" || host == "codeberg.org" ||
+		strings.HasPrefix(host, "gitea.") || strings.HasPrefix(host, "forgejo."):
+		return ForgeGitea
+	default:
+		return ForgeGeneric
+	}
+}
+
+// repoHost returns the lowercase host name of the repository URL, or an
+// empty string if it is invalid.
+func repoHost(repoURL string) string {
+	if !strings.Contains(repoURL, "://") {
+		// SCP-like address, i.e. [user@]host:path.
+		host, _, ok := strings.Cut(repoURL, ":")
+		if !ok {
+			return ""
+		}
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+		return strings.ToLower(host)
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDetectProvider(t *testing.T) {
+	tests := []struct {
+		repoURL string
+		want    Forge
+	}{
+		{repoURL: "https://github.com/fluxcd/flux2", want: ForgeGitHub},
+		{repoURL: "ssh://git@ssh.github.com:443/fluxcd/flux2", want: ForgeGitHub},
+		{repoURL: "git@github.com:fluxcd/flux2.git", want: ForgeGitHub},
+		{repoURL: "https://octo.ghe.com/org/repo", want: ForgeGitHub},
+		{repoURL: "https://GitLab.com/org/repo", want: ForgeGitLab},
+		{repoURL: "https://gitlab.example.com/org/repo", want: ForgeGitLab},
+		{repoURL: "git@bitbucket.org:org/repo.git", want: ForgeBitbucket},
+		{repoURL: "https://bitbucket.example.com/scm/org/repo.git", want: ForgeBitbucket},
+		{repoURL: "https://dev.azure.com/org/project/_git/repo", want: ForgeAzureDevOps},
+		{repoURL: "git@ssh.dev.azure.com:v3/org/project/repo", want: ForgeAzureDevOps},
+		{repoURL: "https://org.visualstudio.com/project/_git/repo", want: ForgeAzureDevOps},
+		{repoURL: "https://codeberg.org/org/repo", want: ForgeGitea},
+		{repoURL: "http://gitea.kind.local:3000/org/repo", want: ForgeGitea},
+		{repoURL: "https://git.example.com/org/repo", want: ForgeGeneric},
+		{repoURL: "https://github.com.example.com/org/repo", want: ForgeGeneric},
+		{repoURL: "https://example.com/gitlab/repo", want: ForgeGeneric},
+		{repoURL: "file:///tmp/repo", want: ForgeGeneric},
+		{repoURL: "invalid", want: ForgeGeneric},
+	}
+	for _, tt := range tests {
+		t.Run(tt.repoURL, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got := DetectProvider(tt.repoURL)
+			g.Expect(got.Forge).To(Equal(tt.want))
+			g.Expect(got.Capabilities).To(Equal(forgeCapabilities[tt.want]))
+		})
+	}
+}
+
+func TestDetectProvider_capabilities(t *testing.T) {
+	g := NewWithT(t)
+
+	azure := DetectProvider("https://dev.azure.com/org/project/_git/repo").Capabilities
+	g.Expect(azure.BearerAuth).To(BeTrue())
+	g.Expect(azure.RefFiltering).To(BeFalse())
+
+	github := DetectProvider("https://github.com/fluxcd/flux2").Capabilities
+	g.Expect(github.BearerAuth).To(BeFalse())
+	g.Expect(github.RefFiltering).To(BeTrue())
+
+	generic := DetectProvider("https://git.example.com/org/repo").Capabilities
+	g.Expect(generic).To(Equal(ForgeCapabilities{ForceWithLease: true}))
+}
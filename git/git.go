@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -29,6 +30,9 @@ import (
 const (
 	// HashTypeSHA1 is the SHA1 hash algorithm.
 	HashTypeSHA1 = "sha1"
+	// HashTypeSHA256 is the SHA256 hash algorithm, used by the repositories
+	// initialized with the SHA256 object format.
+	HashTypeSHA256 = "sha256"
 	// HashTypeUnknown is an unknown hash algorithm.
 	HashTypeUnknown = "<unknown>"
 )
@@ -43,6 +47,8 @@ func (h Hash) Algorithm() string {
 	switch len(h) {
 	case 40:
 		return HashTypeSHA1
+	case 64:
+		return HashTypeSHA256
 	default:
 		return HashTypeUnknown
 	}
@@ -174,6 +180,23 @@ func (t *Tag) String() string {
 	return fmt.Sprintf("%s@%s", t.Name, t.Hash.String())
 }
 
+// Reference is a branch or tag of a remote repository.
+type Reference struct {
+	// Name is the full name of the reference, for example:
+	// 'refs/heads/main' or 'refs/tags/v1.0.0'.
+	Name string
+	// Hash is the hash of the commit the reference points to. For an
+	// annotated tag, it is the hash of the tagged commit.
+	Hash Hash
+}
+
+// String returns a string representation of the Reference, composed out
+// of the Name and Hash. For example:
+// 'refs/heads/main@sha1:a0c14dc8580a23f79bc654faa79c4f62b46c2c22'.
+func (r Reference) String() string {
+	return fmt.Sprintf("%s@%s", r.Name, r.Hash.Digest())
+}
+
 // ErrRepositoryNotFound indicates that the repository (or the ref in
 // question) does not exist at the given URL.
 type ErrRepositoryNotFound struct {
@@ -185,6 +208,36 @@ func (e ErrRepositoryNotFound) Error() string {
 	return fmt.Sprintf("%s: git repository: '%s'", e.Message, e.URL)
 }
 
+// WorktreeStatus describes the changes in a working tree compared to the
+// commit HEAD points to. Paths are relative to the root of the working tree.
+type WorktreeStatus struct {
+	// Modified contains the paths of tracked files with modified content,
+	// including renamed and copied files.
+	Modified []string
+	// Added contains the paths of files staged for addition.
+	Added []string
+	// Deleted contains the paths of tracked files that have been removed.
+	Deleted []string
+	// Untracked contains the paths of files not tracked by Git.
+	Untracked []string
+}
+
+// IsClean returns true if the working tree has no changes.
+func (s *WorktreeStatus) IsClean() bool {
+	return len(s.Modified) == 0 && len(s.Added) == 0 && len(s.Deleted) == 0 && len(s.Untracked) == 0
+}
+
+// Files returns the sorted paths of all the files with changes.
+func (s *WorktreeStatus) Files() []string {
+	var files []string
+	files = append(files, s.Modified...)
+	files = append(files, s.Added...)
+	files = append(files, s.Deleted...)
+	files = append(files, s.Untracked...)
+	sort.Strings(files)
+	return files
+}
+
 var (
 	ErrNoGitRepository = errors.New("no git repository")
 	ErrNoStagedFiles   = errors.New("no staged files")
@@ -133,7 +133,7 @@ func TestHash_Algorithm(t *testing.T) {
 		{
 			name: "SHA-256",
 			hash: Hash("6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8"),
-			want: HashTypeUnknown,
+			want: HashTypeSHA256,
 		},
 		{
 			name: "MD5",
@@ -227,9 +227,9 @@ func TestHash_Digest(t *testing.T) {
 			want: "<unknown>:dba535cd50b291777a055338572e4a4b",
 		},
 		{
-			name: "With an unknown (SHA-256) hash",
+			name: "With a SHA-256 hash",
 			hash: Hash("6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8"),
-			want: "<unknown>:6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8",
+			want: "sha256:6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8",
 		},
 		{
 			name: "With a nil hash",
@@ -0,0 +1,327 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+const (
+	// bundleV2Signature is the first line of the v2 Git bundles.
+	bundleV2Signature = "# v2 git bundle"
+	// bundleV3Signature is the first line of the v3 Git bundles, which
+	// may declare capabilities.
+	bundleV3Signature = "# v3 git bundle"
+	// bundlePackWindow is the size of the delta compression window of the
+	// packfile of the bundles.
+	bundlePackWindow = 10
+)
+
+// WriteBundle writes the repository to w in the `git bundle` format, with
+// the branches, the tags and the HEAD of the repository. The bundle can be
+// cloned with CloneBundle, or with `git clone <file>`.
+//
+// The bundle of a shallow repository declares the parents of the shallow
+// commits as prerequisites, which `git clone` requires to be present.
+// CloneBundle instead records the commits as shallow.
+func (g *Client) WriteBundle(w io.Writer) error {
+	if g.repository == nil {
+		return git.ErrNoGitRepository
+	}
+	repo := g.repository
+
+	var refs []*plumbing.Reference
+	if head, err := repo.Head(); err == nil {
+		refs = append(refs, plumbing.NewHashReference(plumbing.HEAD, head.Hash()))
+	} else if !errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return fmt.Errorf("unable to resolve HEAD: %w", err)
+	}
+	iter, err := repo.References()
+	if err != nil {
+		return fmt.Errorf("unable to list references: %w", err)
+	}
+	var named []*plumbing.Reference
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && (ref.Name().IsBranch() || ref.Name().IsTag()) {
+			named = append(named, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list references: %w", err)
+	}
+	sort.Slice(named, func(i, j int) bool {
+		return named[i].Name() < named[j].Name()
+	})
+	refs = append(refs, named...)
+	if len(refs) == 0 {
+		return errors.New("unable to create bundle of a repository without references")
+	}
+
+	shallows, err := repo.Storer.Shallow()
+	if err != nil {
+		return fmt.Errorf("unable to read shallow commits: %w", err)
+	}
+	var prerequisites []plumbing.Hash
+	for _, h := range shallows {
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			return fmt.Errorf("unable to resolve shallow commit '%s': %w", h, err)
+		}
+		prerequisites = append(prerequisites, c.ParentHashes...)
+	}
+
+	var hashes []plumbing.Hash
+	objects, err := repo.Storer.IterEncodedObjects(plumbing.AnyObject)
+	if err != nil {
+		return fmt.Errorf("unable to list objects: %w", err)
+	}
+	err = objects.ForEach(func(obj plumbing.EncodedObject) error {
+		hashes = append(hashes, obj.Hash())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list objects: %w", err)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, bundleV2Signature)
+	for _, h := range prerequisites {
+		fmt.Fprintf(bw, "-%s\n", h)
+	}
+	for _, ref := range refs {
+		fmt.Fprintf(bw, "%s %s\n", ref.Hash(), ref.Name())
+	}
+	fmt.Fprintln(bw)
+	if _, err := packfile.NewEncoder(bw, repo.Storer, false).Encode(hashes, bundlePackWindow); err != nil {
+		return fmt.Errorf("unable to write bundle packfile: %w", err)
+	}
+	return bw.Flush()
+}
+
+// CloneBundle clones the repository from the bundle read from r, as
+// written by WriteBundle or `git bundle create`. It checks out the
+// branch, tag or commit of the config, or the HEAD of the bundle otherwise,
+// and returns the checked out commit.
+//
+// The commits of the bundle referencing prerequisites which are not in the
+// bundle are recorded as shallow, like the commits of a shallow clone.
+func (g *Client) CloneBundle(r io.Reader, cfg repository.CloneConfig) (*git.Commit, error) {
+	br := bufio.NewReader(r)
+	prerequisites, refs, err := readBundleHeader(br)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := extgogit.Init(g.storer, g.worktreeFS)
+	if err != nil {
+		return nil, fmt.Errorf("unable to init repository: %w", err)
+	}
+	if err = g.setObjectFormat(repo); err != nil {
+		return nil, err
+	}
+	if err := packfile.UpdateObjectStorage(repo.Storer, br); err != nil {
+		return nil, fmt.Errorf("unable to read bundle packfile: %w", err)
+	}
+	if err := setBundleShallows(repo, prerequisites); err != nil {
+		return nil, err
+	}
+
+	var head *plumbing.Reference
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD {
+			head = ref
+			continue
+		}
+		if err := repo.Storer.SetReference(ref); err != nil {
+			return nil, fmt.Errorf("unable to set reference '%s': %w", ref.Name(), err)
+		}
+	}
+
+	checkout := &extgogit.CheckoutOptions{
+		Force:                     true,
+		SparseCheckoutDirectories: cfg.SparseCheckoutDirectories,
+	}
+	var refName plumbing.ReferenceName
+	var tag *object.Tag
+	switch {
+	case cfg.Commit != "":
+		if err := validateCommitHash(cfg.Commit); err != nil {
+			return nil, err
+		}
+		checkout.Hash = plumbing.NewHash(cfg.Commit)
+		refName = plumbing.ReferenceName(cfg.RefName)
+	case cfg.Tag != "":
+		refName = plumbing.NewTagReferenceName(cfg.Tag)
+		ref, err := repo.Reference(refName, false)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find tag '%s' in bundle: %w", cfg.Tag, err)
+		}
+		checkout.Hash = ref.Hash()
+		if t, err := repo.TagObject(ref.Hash()); err == nil {
+			c, err := t.Commit()
+			if err != nil {
+				return nil, fmt.Errorf("unable to resolve commit of tag '%s': %w", cfg.Tag, err)
+			}
+			tag, checkout.Hash = t, c.Hash
+		}
+	case cfg.Branch != "":
+		refName = plumbing.NewBranchReferenceName(cfg.Branch)
+		if _, err := repo.Reference(refName, false); err != nil {
+			return nil, fmt.Errorf("unable to find branch '%s' in bundle: %w", cfg.Branch, err)
+		}
+		checkout.Branch = refName
+	case head != nil:
+		checkout.Hash = head.Hash()
+		// Like `git clone`, check out the branch the HEAD points to.
+		for _, ref := range refs {
+			if ref.Name().IsBranch() && ref.Hash() == head.Hash() {
+				refName = ref.Name()
+				checkout.Hash, checkout.Branch = plumbing.ZeroHash, refName
+				break
+			}
+		}
+	default:
+		return nil, errors.New("unable to determine the commit to check out: the bundle has no HEAD")
+	}
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repo worktree: %w", err)
+	}
+	if err := wt.Checkout(checkout); err != nil {
+		return nil, fmt.Errorf("unable to checkout bundle: %w", err)
+	}
+	h, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve HEAD: %w", err)
+	}
+	cc, err := repo.CommitObject(h.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve commit object for HEAD '%s': %w", h.Hash(), err)
+	}
+	g.repository = repo
+	return buildCommitWithRef(cc, tag, refName)
+}
+
+// readBundleHeader reads the header of a v2 or v3 Git bundle, and returns
+// its prerequisites and references. The reader is left at the start of the
+// packfile.
+func readBundleHeader(r *bufio.Reader) ([]plumbing.Hash, []*plumbing.Reference, error) {
+	signature, err := r.ReadString('\n')
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read bundle header: %w", err)
+	}
+	signature = strings.TrimSuffix(signature, "\n")
+	if signature != bundleV2Signature && signature != bundleV3Signature {
+		return nil, nil, fmt.Errorf("unsupported bundle signature '%s'", signature)
+	}
+
+	var prerequisites []plumbing.Hash
+	var refs []*plumbing.Reference
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read bundle header: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if len(refs) == 0 {
+				return nil, nil, errors.New("invalid bundle: no references")
+			}
+			return prerequisites, refs, nil
+		case signature == bundleV3Signature && strings.HasPrefix(line, "@"):
+			key, value, _ := strings.Cut(line[1:], "=")
+			switch key {
+			case "object-format":
+				if value != ObjectFormat() {
+					return nil, nil, fmt.Errorf("bundle object format '%s' is not supported by this build, which supports '%s'", value, ObjectFormat())
+				}
+			case "filter":
+				return nil, nil, errors.New("partial bundles are not supported")
+			default:
+				return nil, nil, fmt.Errorf("unsupported bundle capability '%s'", key)
+			}
+		case strings.HasPrefix(line, "-"):
+			oid, _, _ := strings.Cut(line[1:], " ")
+			if !plumbing.IsHash(oid) {
+				return nil, nil, fmt.Errorf("invalid bundle prerequisite '%s'", line)
+			}
+			prerequisites = append(prerequisites, plumbing.NewHash(oid))
+		default:
+			oid, name, ok := strings.Cut(line, " ")
+			if !ok || !plumbing.IsHash(oid) || name == "" {
+				return nil, nil, fmt.Errorf("invalid bundle reference '%s'", line)
+			}
+			refs = append(refs, plumbing.NewHashReference(plumbing.ReferenceName(name), plumbing.NewHash(oid)))
+		}
+	}
+}
+
+// setBundleShallows records the commits referencing the prerequisites of
+// the bundle which are missing from the repository as shallow.
+func setBundleShallows(repo *extgogit.Repository, prerequisites []plumbing.Hash) error {
+	missing := make(map[plumbing.Hash]bool)
+	for _, h := range prerequisites {
+		if err := repo.Storer.HasEncodedObject(h); err != nil {
+			missing[h] = true
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var shallows []plumbing.Hash
+	iter, err := repo.Storer.IterEncodedObjects(plumbing.CommitObject)
+	if err != nil {
+		return fmt.Errorf("unable to list commits: %w", err)
+	}
+	err = iter.ForEach(func(obj plumbing.EncodedObject) error {
+		c, err := object.DecodeCommit(repo.Storer, obj)
+		if err != nil {
+			return err
+		}
+		for _, p := range c.ParentHashes {
+			if missing[p] {
+				shallows = append(shallows, c.Hash)
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to list commits: %w", err)
+	}
+	sort.Slice(shallows, func(i, j int) bool {
+		return shallows[i].String() < shallows[j].String()
+	})
+	return repo.Storer.SetShallow(shallows)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestCloneBundle(t *testing.T) {
+	repo, repoPath, err := initRepo(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var commits []plumbing.Hash
+	for i := 0; i < 3; i++ {
+		cc, err := commitFile(repo, "file", fmt.Sprintf("commit %d", i), time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, cc)
+	}
+	if _, err = tag(repo, commits[1], true, "v1.0.0", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		cloneCfg    repository.CloneConfig
+		bundleCfg   repository.CloneConfig
+		wantCommit  plumbing.Hash
+		wantRef     string
+		wantContent string
+		wantShallow bool
+	}{
+		{
+			name:        "HEAD of branch",
+			cloneCfg:    repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"}},
+			wantCommit:  commits[2],
+			wantRef:     "refs/heads/master",
+			wantContent: "commit 2",
+		},
+		{
+			name:        "branch",
+			cloneCfg:    repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"}},
+			bundleCfg:   repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"}},
+			wantCommit:  commits[2],
+			wantRef:     "refs/heads/master",
+			wantContent: "commit 2",
+		},
+		{
+			name:        "annotated tag",
+			cloneCfg:    repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Tag: "v1.0.0"}},
+			bundleCfg:   repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Tag: "v1.0.0"}},
+			wantCommit:  commits[1],
+			wantRef:     "refs/tags/v1.0.0",
+			wantContent: "commit 1",
+		},
+		{
+			name:        "commit",
+			cloneCfg:    repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"}},
+			bundleCfg:   repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Commit: commits[0].String()}},
+			wantCommit:  commits[0],
+			wantContent: "commit 0",
+		},
+		{
+			name:        "shallow clone",
+			cloneCfg:    repository.CloneConfig{CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"}, ShallowClone: true},
+			wantCommit:  commits[2],
+			wantRef:     "refs/heads/master",
+			wantContent: "commit 2",
+			wantShallow: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			src, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+			g.Expect(err).ToNot(HaveOccurred())
+			_, err = src.Clone(context.TODO(), repoPath, tt.cloneCfg)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var bundle bytes.Buffer
+			g.Expect(src.WriteBundle(&bundle)).To(Succeed())
+
+			dst, err := NewClient(t.TempDir(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			cc, err := dst.CloneBundle(&bundle, tt.bundleCfg)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cc.Hash.String()).To(Equal(tt.wantCommit.String()))
+			g.Expect(cc.Reference).To(Equal(tt.wantRef))
+
+			content, err := os.ReadFile(filepath.Join(dst.Path(), "file"))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(content)).To(Equal(tt.wantContent))
+
+			clean, err := dst.IsClean()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(clean).To(BeTrue())
+
+			shallows, err := dst.repository.Storer.Shallow()
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.wantShallow {
+				g.Expect(shallows).To(Equal([]plumbing.Hash{commits[2]}))
+			} else {
+				g.Expect(shallows).To(BeEmpty())
+			}
+		})
+	}
+}
+
+func TestWriteBundle_gitClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found in PATH")
+	}
+	g := NewWithT(t)
+
+	repo, repoPath, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	cc, err := commitFile(repo, "file", "content", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	src, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = src.Clone(context.TODO(), repoPath, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	bundlePath := filepath.Join(t.TempDir(), "repo.bundle")
+	f, err := os.Create(bundlePath)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(src.WriteBundle(f)).To(Succeed())
+	g.Expect(f.Close()).To(Succeed())
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	out, err := exec.Command("git", "clone", bundlePath, clonePath).CombinedOutput()
+	g.Expect(err).ToNot(HaveOccurred(), string(out))
+	out, err = exec.Command("git", "-C", clonePath, "rev-parse", "HEAD").CombinedOutput()
+	g.Expect(err).ToNot(HaveOccurred(), string(out))
+	g.Expect(strings.TrimSpace(string(out))).To(Equal(cc.String()))
+
+	// Bundles created by git can be cloned.
+	gitBundlePath := filepath.Join(t.TempDir(), "git.bundle")
+	out, err = exec.Command("git", "-C", clonePath, "bundle", "create", gitBundlePath, "--all").CombinedOutput()
+	g.Expect(err).ToNot(HaveOccurred(), string(out))
+	f, err = os.Open(gitBundlePath)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+
+	dst, err := NewClient(t.TempDir(), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	commit, err := dst.CloneBundle(f, repository.CloneConfig{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(commit.Hash.String()).To(Equal(cc.String()))
+	content, err := util.ReadFile(dst.worktreeFS, "file")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(content)).To(Equal("content"))
+}
+
+func Test_readBundleHeader(t *testing.T) {
+	hash := strings.Repeat("a", 40)
+	tests := []struct {
+		name              string
+		header            string
+		wantPrerequisites int
+		wantRefs          int
+		wantErr           string
+	}{
+		{
+			name:     "v2 bundle",
+			header:   "# v2 git bundle\n" + hash + " HEAD\n" + hash + " refs/heads/main\n\n",
+			wantRefs: 2,
+		},
+		{
+			name:              "v3 bundle with prerequisites",
+			header:            "# v3 git bundle\n@object-format=sha1\n-" + hash + " parent commit\n" + hash + " refs/heads/main\n\n",
+			wantPrerequisites: 1,
+			wantRefs:          1,
+		},
+		{
+			name:    "unsupported signature",
+			header:  "# v4 git bundle\n",
+			wantErr: "unsupported bundle signature",
+		},
+		{
+			name:    "unsupported object format",
+			header:  "# v3 git bundle\n@object-format=sha256\n",
+			wantErr: "bundle object format 'sha256' is not supported",
+		},
+		{
+			name:    "partial bundle",
+			header:  "# v3 git bundle\n@filter=blob:none\n",
+			wantErr: "partial bundles are not supported",
+		},
+		{
+			name:    "invalid reference",
+			header:  "# v2 git bundle\nrefs/heads/main\n\n",
+			wantErr: "invalid bundle reference",
+		},
+		{
+			name:    "no references",
+			header:  "# v2 git bundle\n\n",
+			wantErr: "no references",
+		},
+		{
+			name:    "truncated header",
+			header:  "# v2 git bundle\n" + hash + " refs/heads/main\n",
+			wantErr: "unable to read bundle header",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			prerequisites, refs, err := readBundleHeader(bufio.NewReader(strings.NewReader(tt.header)))
+			if tt.wantErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(prerequisites).To(HaveLen(tt.wantPrerequisites))
+			g.Expect(refs).To(HaveLen(tt.wantRefs))
+		})
+	}
+}
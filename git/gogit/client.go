@@ -22,7 +22,10 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-git/go-billy/v5"
@@ -80,6 +83,21 @@ type Client struct {
 	useDefaultKnownHosts bool
 	singleBranch         bool
 	proxy                transport.ProxyOptions
+	proxyConfig          *git.ProxyConfig
+	noProxy              string
+	diskStorage          bool
+	ownStorage           bool
+	cloneCache           *CloneCache
+	objectFormat         string
+	lfs                  bool
+	lfsMaxObjectSize     int64
+	cloneTimeout         time.Duration
+	pushTimeout          time.Duration
+	listRemoteTimeout    time.Duration
+	progressWriter       io.Writer
+	progressFunc         ProgressFunc
+	retry                *RetryOptions
+	retries              int
 }
 
 var _ repository.Client = &Client{}
@@ -97,7 +115,8 @@ func NewClient(path string, authOpts *git.AuthOptions, clientOpts ...ClientOptio
 		path:     securePath,
 		authOpts: authOpts,
 		// Default to single branch as it is the most performant option.
-		singleBranch: true,
+		singleBranch:     true,
+		lfsMaxObjectSize: DefaultLFSMaxObjectSize,
 	}
 
 	if len(clientOpts) == 0 {
@@ -125,6 +144,8 @@ func NewClient(path string, authOpts *git.AuthOptions, clientOpts ...ClientOptio
 func WithStorer(s storage.Storer) ClientOption {
 	return func(c *Client) error {
 		c.storer = s
+		c.diskStorage = false
+		c.ownStorage = false
 		return nil
 	}
 }
@@ -134,6 +155,8 @@ func WithStorer(s storage.Storer) ClientOption {
 func WithWorkTreeFS(wt billy.Filesystem) ClientOption {
 	return func(c *Client) error {
 		c.worktreeFS = wt
+		c.diskStorage = false
+		c.ownStorage = false
 		return nil
 	}
 }
@@ -166,6 +189,8 @@ func WithDiskStorage() ClientOption {
 
 		c.storer = filesystem.NewStorage(dot, cache.NewObjectLRUDefault())
 		c.worktreeFS = wt
+		c.diskStorage = true
+		c.ownStorage = true
 		return nil
 	}
 }
@@ -176,6 +201,8 @@ func WithMemoryStorage() ClientOption {
 	return func(c *Client) error {
 		c.storer = memory.NewStorage()
 		c.worktreeFS = memfs.New()
+		c.diskStorage = false
+		c.ownStorage = true
 		return nil
 	}
 }
@@ -220,6 +247,9 @@ func (g *Client) Init(ctx context.Context, url, branch string) error {
 	if err != nil {
 		return err
 	}
+	if err = g.setObjectFormat(r); err != nil {
+		return err
+	}
 
 	if _, err = r.CreateRemote(&config.RemoteConfig{
 		Name: extgogit.DefaultRemoteName,
@@ -253,11 +283,140 @@ func (g *Client) Clone(ctx context.Context, url string, cfg repository.CloneConf
 		return nil, err
 	}
 
+	parent := ctx
+	ctx, cancel := operationContext(ctx, g.cloneTimeout)
+	defer cancel()
+	if !g.canResetStorage() {
+		commit, err := g.cloneWithLFS(ctx, url, cfg)
+		return commit, operationError(parent, ctx, "clone", g.cloneTimeout, err)
+	}
+
+	var commit *git.Commit
+	attempted := false
+	err := g.withRetry(ctx, "clone", func() error {
+		if attempted {
+			if err := g.resetStorage(); err != nil {
+				return fmt.Errorf("failed to reset storage: %w", err)
+			}
+		}
+		attempted = true
+		var err error
+		commit, err = g.cloneWithLFS(ctx, url, cfg)
+		return err
+	})
+	return commit, operationError(parent, ctx, "clone", g.cloneTimeout, err)
+}
+
+// cloneWithLFS clones the repository, and resolves the LFS pointers of the
+// worktree if LFS is enabled.
+func (g *Client) cloneWithLFS(ctx context.Context, url string, cfg repository.CloneConfig) (*git.Commit, error) {
 	if err := g.providerAuth(ctx); err != nil {
 		return nil, err
 	}
 
-	return g.clone(ctx, url, cfg)
+	var commit *git.Commit
+	var err error
+	if g.cloneCache != nil && g.diskStorage {
+		commit, err = g.cloneCache.clone(ctx, g, url, cfg)
+	} else {
+		commit, err = g.clone(ctx, url, cfg)
+	}
+	if err != nil || commit == nil || !g.lfs || !git.IsConcreteCommit(*commit) {
+		return commit, err
+	}
+
+	if err := g.resolveLFSPointers(ctx, url); err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+// ListRemote lists the branches and tags of the repository at the given
+// url, sorted by name, without cloning it.
+func (g *Client) ListRemote(ctx context.Context, url string, patterns ...string) ([]git.Reference, error) {
+	if err := g.validateUrlAndAuthOptions(url); err != nil {
+		return nil, err
+	}
+
+	parent := ctx
+	ctx, cancel := operationContext(ctx, g.listRemoteTimeout)
+	defer cancel()
+
+	if err := g.providerAuth(ctx); err != nil {
+		return nil, operationError(parent, ctx, "ls-remote", g.listRemoteTimeout, err)
+	}
+
+	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+
+	var refs []*plumbing.Reference
+	err = g.withRetry(ctx, "ls-remote", func() error {
+		refs, err = g.listRemoteRefs(ctx, url, authMethod)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return nil, nil
+		}
+		return nil, operationError(parent, ctx, "ls-remote", g.listRemoteTimeout, err)
+	}
+
+	// The peeled references of the annotated tags point to the tagged
+	// commits, and take precedence over the references to the tag objects.
+	hashes := make(map[string]string)
+	for _, ref := range refs {
+		name := ref.Name().String()
+		if peeled, ok := strings.CutSuffix(name, tagDereferenceSuffix); ok {
+			hashes[peeled] = ref.Hash().String()
+			continue
+		}
+		if !ref.Name().IsBranch() && !ref.Name().IsTag() {
+			continue
+		}
+		if _, ok := hashes[name]; !ok {
+			hashes[name] = ref.Hash().String()
+		}
+	}
+
+	var result []git.Reference
+	for name, hash := range hashes {
+		if !matchRefPatterns(name, patterns) {
+			continue
+		}
+		result = append(result, git.Reference{
+			Name: name,
+			Hash: git.Hash(hash),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// matchRefPatterns returns whether the given reference name, or its tail
+// after a slash, matches one of the patterns. It returns true if there are
+// no patterns.
+func matchRefPatterns(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		tail := name
+		for {
+			if ok, _ := path.Match(pattern, tail); ok {
+				return true
+			}
+			i := strings.Index(tail, "/")
+			if i == -1 {
+				break
+			}
+			tail = tail[i+1:]
+		}
+	}
+	return false
 }
 
 func (g *Client) clone(ctx context.Context, url string, cfg repository.CloneConfig) (*git.Commit, error) {
@@ -267,6 +426,8 @@ func (g *Client) clone(ctx context.Context, url string, cfg repository.CloneConf
 		return g.cloneCommit(ctx, url, checkoutStrat.Commit, cfg)
 	case checkoutStrat.RefName != "":
 		return g.cloneRefName(ctx, url, checkoutStrat.RefName, cfg)
+	case checkoutStrat.Change != "":
+		return g.cloneChange(ctx, url, checkoutStrat.Change, cfg)
 	case checkoutStrat.Tag != "":
 		return g.cloneTag(ctx, url, checkoutStrat.Tag, cfg)
 	case checkoutStrat.SemVer != "":
@@ -346,6 +507,8 @@ func (g *Client) providerAuth(ctx context.Context) error {
 				g.authOpts.ProviderOpts.AzureOpts = append(g.authOpts.ProviderOpts.AzureOpts, azure.WithProxyURL(proxyURL))
 			case git.ProviderGitHub:
 				g.authOpts.ProviderOpts.GitHubOpts = append(g.authOpts.ProviderOpts.GitHubOpts, github.WithProxyURL(proxyURL))
+			case git.ProviderDev:
+				// The dev provider does not perform any requests.
 			default:
 				return fmt.Errorf("invalid provider")
 			}
@@ -426,9 +589,15 @@ func (g *Client) Commit(info git.Commit, commitOpts ...repository.CommitOption)
 		},
 	}
 
+	if options.Signer != nil && options.SSHSigner != nil {
+		return "", errors.New("unable to sign commit with both an OpenPGP and an SSH signer")
+	}
 	if options.Signer != nil {
 		opts.SignKey = options.Signer
 	}
+	if options.SSHSigner != nil {
+		opts.Signer = &sshSigner{signer: options.SSHSigner}
+	}
 
 	commit, err := wt.Commit(info.Message, opts)
 	if err != nil {
@@ -442,6 +611,17 @@ func (g *Client) Push(ctx context.Context, cfg repository.PushConfig) error {
 		return git.ErrNoGitRepository
 	}
 
+	parent := ctx
+	ctx, cancel := operationContext(ctx, g.pushTimeout)
+	defer cancel()
+	err := g.withRetry(ctx, "push", func() error {
+		return g.push(ctx, cfg)
+	})
+	return operationError(parent, ctx, "push", g.pushTimeout, err)
+}
+
+// push pushes the refspecs of the configuration to the remote.
+func (g *Client) push(ctx context.Context, cfg repository.PushConfig) error {
 	if err := g.providerAuth(ctx); err != nil {
 		return err
 	}
@@ -451,33 +631,28 @@ func (g *Client) Push(ctx context.Context, cfg repository.PushConfig) error {
 		return fmt.Errorf("failed to construct auth method with options: %w", err)
 	}
 
-	var refspecs []config.RefSpec
-	for _, ref := range cfg.Refspecs {
-		refspecs = append(refspecs, config.RefSpec(ref))
+	refspecs, err := g.pushRefspecs(cfg.Refspecs)
+	if err != nil {
+		return err
 	}
 
-	// If no refspecs were provided, we need to push the current ref HEAD points to.
-	// The format of a refspec for a Git push is generally something like
-	// "refs/heads/branch:refs/heads/branch".
-	if len(refspecs) == 0 {
-		head, err := g.repository.Head()
-		if err != nil {
+	var lease *extgogit.ForceWithLease
+	if cfg.ForceWithLease {
+		if lease, err = pushLease(refspecs, cfg.Leases); err != nil {
 			return err
 		}
-
-		headRefspec := config.RefSpec(fmt.Sprintf("%s:%[1]s", head.Name()))
-		refspecs = append(refspecs, headRefspec)
 	}
 
 	err = g.repository.PushContext(ctx, &extgogit.PushOptions{
-		RefSpecs:     refspecs,
-		Force:        cfg.Force,
-		RemoteName:   extgogit.DefaultRemoteName,
-		Auth:         authMethod,
-		Progress:     nil,
-		CABundle:     caBundle(g.authOpts),
-		ProxyOptions: g.proxy,
-		Options:      cfg.Options,
+		RefSpecs:       refspecs,
+		Force:          cfg.Force,
+		ForceWithLease: lease,
+		RemoteName:     extgogit.DefaultRemoteName,
+		Auth:           authMethod,
+		Progress:       g.progress(ProgressOperationPush),
+		CABundle:       caBundle(g.authOpts),
+		ProxyOptions:   g.proxyOptions(g.remoteURL()),
+		Options:        cfg.Options,
 	})
 	if err != nil {
 		return fmt.Errorf("failed to push to remote: %w", err)
@@ -486,6 +661,132 @@ func (g *Client) Push(ctx context.Context, cfg repository.PushConfig) error {
 	return nil
 }
 
+// pushRefspecs returns the refspecs of the push, or the refspec pushing the
+// ref HEAD points to if none are given. The HEAD and short names sources
+// are resolved to the local refs, and the short names destinations to the
+// full ref names, as go-git silently ignores the refspecs it can't match.
+func (g *Client) pushRefspecs(specs []string) ([]config.RefSpec, error) {
+	// If no refspecs were provided, we need to push the current ref HEAD points to.
+	// The format of a refspec for a Git push is generally something like
+	// "refs/heads/branch:refs/heads/branch".
+	if len(specs) == 0 {
+		head, err := g.repository.Head()
+		if err != nil {
+			return nil, err
+		}
+		return []config.RefSpec{config.RefSpec(fmt.Sprintf("%s:%[1]s", head.Name()))}, nil
+	}
+
+	var refspecs []config.RefSpec
+	for _, spec := range specs {
+		prefix := ""
+		if strings.HasPrefix(spec, "+") {
+			prefix, spec = "+", spec[1:]
+		}
+		src, dst, ok := strings.Cut(spec, ":")
+		if !ok {
+			dst = src
+		}
+
+		if src != "" && !strings.Contains(src, "*") {
+			resolved, err := g.resolvePushSource(src)
+			if err != nil {
+				return nil, fmt.Errorf("invalid refspec '%s': %w", prefix+spec, err)
+			}
+			src = resolved
+			if !ok {
+				if plumbing.IsHash(src) {
+					return nil, fmt.Errorf("invalid refspec '%s': a destination is required to push a commit", prefix+spec)
+				}
+				dst = src
+			}
+		}
+		if dst != "" && !strings.HasPrefix(dst, "refs/") {
+			if plumbing.ReferenceName(src).IsTag() {
+				dst = plumbing.NewTagReferenceName(dst).String()
+			} else {
+				dst = plumbing.NewBranchReferenceName(dst).String()
+			}
+		}
+
+		rs := config.RefSpec(prefix + src + ":" + dst)
+		if err := rs.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid refspec '%s': %w", prefix+spec, err)
+		}
+		refspecs = append(refspecs, rs)
+	}
+	return refspecs, nil
+}
+
+// resolvePushSource returns the full name of the local ref, or the commit
+// hash of a detached HEAD, matching the source of a push refspec.
+func (g *Client) resolvePushSource(src string) (string, error) {
+	if src == plumbing.HEAD.String() {
+		head, err := g.repository.Reference(plumbing.HEAD, false)
+		if err != nil {
+			return "", fmt.Errorf("unable to resolve HEAD: %w", err)
+		}
+		if head.Type() == plumbing.SymbolicReference {
+			return head.Target().String(), nil
+		}
+		return head.Hash().String(), nil
+	}
+	if plumbing.IsHash(src) {
+		if _, err := g.repository.CommitObject(plumbing.NewHash(src)); err != nil {
+			return "", fmt.Errorf("commit '%s' not found: %w", src, err)
+		}
+		return src, nil
+	}
+
+	candidates := []plumbing.ReferenceName{plumbing.ReferenceName(src)}
+	if !strings.HasPrefix(src, "refs/") {
+		candidates = []plumbing.ReferenceName{
+			plumbing.NewBranchReferenceName(src),
+			plumbing.NewTagReferenceName(src),
+		}
+	}
+	for _, name := range candidates {
+		if _, err := g.repository.Reference(name, false); err == nil {
+			return name.String(), nil
+		}
+	}
+	return "", fmt.Errorf("source ref '%s' not found", src)
+}
+
+// pushLease returns the lease of a force-with-lease push of the refspecs.
+// The lease is checked by go-git against the refs advertised by the remote
+// in the push session, and the remote rejects the update of a ref which has
+// changed since. Without leases, the remote refs are expected to be at the
+// commits of the remote-tracking refs of the pushed local branches.
+func pushLease(refspecs []config.RefSpec, leases map[string]string) (*extgogit.ForceWithLease, error) {
+	for _, rs := range refspecs {
+		if !rs.IsDelete() && plumbing.IsHash(rs.Src()) {
+			return nil, fmt.Errorf("force-with-lease is not supported with the commit refspec '%s'", rs)
+		}
+	}
+	if len(leases) == 0 {
+		return &extgogit.ForceWithLease{}, nil
+	}
+	if len(leases) > 1 || len(refspecs) != 1 || refspecs[0].IsWildcard() {
+		return nil, errors.New("force-with-lease supports a single lease for a single non-wildcard refspec")
+	}
+
+	var lease *extgogit.ForceWithLease
+	for name, hash := range leases {
+		if dst := refspecs[0].Dst(""); dst.String() != name {
+			return nil, fmt.Errorf("lease of '%s' does not match the destination of the refspec '%s'", name, refspecs[0])
+		}
+		if !plumbing.IsHash(hash) {
+			return nil, fmt.Errorf("invalid lease of '%s': '%s' is not a commit hash", name, hash)
+		}
+		lease = &extgogit.ForceWithLease{
+			RefName: plumbing.ReferenceName(name),
+			Hash:    plumbing.NewHash(hash),
+		}
+	}
+	return lease, nil
+}
+
 // SwitchBranch switches the current branch to the given branch name.
 //
 // No new references are fetched from the remote during the process,
@@ -582,6 +883,42 @@ func (g *Client) IsClean() (bool, error) {
 	return status.IsClean(), nil
 }
 
+// Status returns the modified, added, deleted and untracked files in the
+// working tree, including staged changes. It can be used before Commit to
+// determine which files a commit would include.
+func (g *Client) Status() (*git.WorktreeStatus, error) {
+	if g.repository == nil {
+		return nil, git.ErrNoGitRepository
+	}
+	wt, err := g.repository.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := wt.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &git.WorktreeStatus{}
+	for path, fs := range status {
+		switch {
+		case fs.Worktree == extgogit.Untracked:
+			result.Untracked = append(result.Untracked, path)
+		case fs.Staging == extgogit.Deleted || fs.Worktree == extgogit.Deleted:
+			result.Deleted = append(result.Deleted, path)
+		case fs.Staging == extgogit.Added:
+			result.Added = append(result.Added, path)
+		case fs.Staging != extgogit.Unmodified || fs.Worktree != extgogit.Unmodified:
+			result.Modified = append(result.Modified, path)
+		}
+	}
+	sort.Strings(result.Modified)
+	sort.Strings(result.Added)
+	sort.Strings(result.Deleted)
+	sort.Strings(result.Untracked)
+	return result, nil
+}
+
 func (g *Client) Head() (string, error) {
 	if g.repository == nil {
 		return "", git.ErrNoGitRepository
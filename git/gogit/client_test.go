@@ -312,6 +312,30 @@ func TestPush_pushConfig_refspecs(t *testing.T) {
 	})
 	g.Expect(err).ToNot(HaveOccurred())
 
+	// HEAD and short names are resolved.
+	err = ggc.Push(context.TODO(), repository.PushConfig{
+		Refspecs: []string{
+			"HEAD:head/refspecs",
+			"feature/refspecs:to-delete",
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = ggc.Push(context.TODO(), repository.PushConfig{
+		Refspecs: []string{
+			":refs/heads/to-delete",
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	err = ggc.Push(context.TODO(), repository.PushConfig{
+		Refspecs: []string{
+			"refs/heads/missing:refs/heads/missing",
+		},
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("source ref 'refs/heads/missing' not found"))
+
 	repo, err = extgogit.PlainClone(t.TempDir(), false, &extgogit.CloneOptions{
 		URL: repoURL,
 	})
@@ -333,6 +357,91 @@ func TestPush_pushConfig_refspecs(t *testing.T) {
 
 	_, err = repo.Reference(plumbing.NewTagReferenceName("v0.2.0"), true)
 	g.Expect(err).To(HaveOccurred())
+
+	remRefName = plumbing.NewRemoteReferenceName(extgogit.DefaultRemoteName, "head/refspecs")
+	remRef, err = repo.Reference(remRefName, true)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remRef.Hash().String()).To(Equal(headOnFeature.String()))
+
+	_, err = repo.Reference(plumbing.NewRemoteReferenceName(extgogit.DefaultRemoteName, "to-delete"), true)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPush_forceWithLease(t *testing.T) {
+	g := NewWithT(t)
+
+	server, repoURL, err := setupGitServer(false)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+	defer server.StopHTTP()
+
+	var clients []*Client
+	var repos []*extgogit.Repository
+	for i := 0; i < 2; i++ {
+		tmp := t.TempDir()
+		repo, err := extgogit.PlainClone(tmp, false, &extgogit.CloneOptions{
+			URL:        repoURL,
+			RemoteName: git.DefaultRemote,
+			Tags:       extgogit.NoTags,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		ggc, err := NewClient(tmp, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		ggc.repository = repo
+		clients = append(clients, ggc)
+		repos = append(repos, repo)
+	}
+	head, err := repos[0].Head()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cc1, err := commitFile(repos[0], "test", "push from first clone", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clients[0].Push(context.TODO(), repository.PushConfig{ForceWithLease: true})).To(Succeed())
+
+	// The remote ref was updated since the second clone fetched it.
+	cc2, err := commitFile(repos[1], "test", "push from second clone", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	err = clients[1].Push(context.TODO(), repository.PushConfig{ForceWithLease: true})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("non-fast-forward update: %s", head.Name()))
+
+	// The lease expects the commit of the first clone.
+	err = clients[1].Push(context.TODO(), repository.PushConfig{
+		ForceWithLease: true,
+		Leases:         map[string]string{head.Name().String(): cc1.String()},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The lease of the first clone is now stale.
+	err = clients[0].Push(context.TODO(), repository.PushConfig{
+		Refspecs:       []string{head.Name().String()},
+		ForceWithLease: true,
+		Leases:         map[string]string{head.Name().String(): cc1.String()},
+	})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("non-fast-forward update: %s", head.Name()))
+
+	// A lease must match the single refspec of the push.
+	err = clients[0].Push(context.TODO(), repository.PushConfig{
+		Refspecs:       []string{"HEAD:refs/heads/target"},
+		ForceWithLease: true,
+		Leases:         map[string]string{head.Name().String(): cc2.String()},
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("lease of '%s' does not match the destination", head.Name())))
+	err = clients[0].Push(context.TODO(), repository.PushConfig{
+		ForceWithLease: true,
+		Leases: map[string]string{
+			head.Name().String(): cc2.String(),
+			"refs/heads/target":  cc2.String(),
+		},
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("force-with-lease supports a single lease")))
+
+	refs, err := clients[0].ListRemote(context.TODO(), repoURL)
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, ref := range refs {
+		g.Expect(ref.Hash.String()).To(Equal(cc2.String()))
+	}
 }
 
 func TestForcePush(t *testing.T) {
@@ -623,6 +732,50 @@ func TestIsClean(t *testing.T) {
 	g.Expect(clean).To(BeFalse())
 }
 
+func TestStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(path)
+
+	_, err = commitFile(repo, "modified", "testing gogit status", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = commitFile(repo, "deleted", "testing gogit status", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ggc, err := NewClient(path, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ggc.repository = repo
+
+	status, err := ggc.Status()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status.IsClean()).To(BeTrue())
+	g.Expect(status.Files()).To(BeEmpty())
+
+	wt, err := repo.Worktree()
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, name := range []string{"modified", "added", "untracked"} {
+		f, err := wt.Filesystem.Create(name)
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = f.Write([]byte("changed"))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(f.Close()).To(Succeed())
+	}
+	g.Expect(wt.Filesystem.Remove("deleted")).To(Succeed())
+	_, err = wt.Add("added")
+	g.Expect(err).ToNot(HaveOccurred())
+
+	status, err = ggc.Status()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status.IsClean()).To(BeFalse())
+	g.Expect(status.Modified).To(Equal([]string{"modified"}))
+	g.Expect(status.Added).To(Equal([]string{"added"}))
+	g.Expect(status.Deleted).To(Equal([]string{"deleted"}))
+	g.Expect(status.Untracked).To(Equal([]string{"untracked"}))
+	g.Expect(status.Files()).To(Equal([]string{"added", "deleted", "modified", "untracked"}))
+}
+
 func TestHead(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1022,3 +1175,51 @@ func setupGitServer(auth bool) (*gittestserver.GitServer, string, error) {
 	repoURL := server.HTTPAddressWithCredentials() + "/" + "test.git"
 	return server, repoURL, nil
 }
+
+func TestListRemote(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	first, err := commitFile(repo, "test", "first", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = tag(repo, first, false, "v0.1.0", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(createBranch(repo, "feature/list")).To(Succeed())
+	second, err := commitFile(repo, "test", "second", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	annotated, err := tag(repo, second, true, "v0.2.0", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(annotated.Hash()).ToNot(Equal(second))
+
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	refs, err := ggc.ListRemote(context.TODO(), path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(refs).To(Equal([]git.Reference{
+		{Name: "refs/heads/feature/list", Hash: git.Hash(second.String())},
+		{Name: "refs/heads/master", Hash: git.Hash(first.String())},
+		{Name: "refs/tags/v0.1.0", Hash: git.Hash(first.String())},
+		{Name: "refs/tags/v0.2.0", Hash: git.Hash(second.String())},
+	}))
+
+	refs, err = ggc.ListRemote(context.TODO(), path, "list", "v0.2.*")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(refs).To(Equal([]git.Reference{
+		{Name: "refs/heads/feature/list", Hash: git.Hash(second.String())},
+		{Name: "refs/tags/v0.2.0", Hash: git.Hash(second.String())},
+	}))
+
+	refs, err = ggc.ListRemote(context.TODO(), path, "refs/tags/*")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(refs).To(HaveLen(2))
+	g.Expect(refs[0].String()).To(Equal("refs/tags/v0.1.0@" + git.Hash(first.String()).Digest()))
+
+	_, emptyPath, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	refs, err = ggc.ListRemote(context.TODO(), emptyPath)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(refs).To(BeEmpty())
+}
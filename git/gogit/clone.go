@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"time"
@@ -68,10 +70,6 @@ func (g *Client) cloneBranch(ctx context.Context, url, branch string, opts repos
 		}
 	}
 
-	var depth int
-	if opts.ShallowClone {
-		depth = 1
-	}
 	cloneOpts := &extgogit.CloneOptions{
 		URL:               url,
 		Auth:              authMethod,
@@ -79,12 +77,12 @@ func (g *Client) cloneBranch(ctx context.Context, url, branch string, opts repos
 		ReferenceName:     plumbing.NewBranchReferenceName(branch),
 		SingleBranch:      g.singleBranch,
 		NoCheckout:        len(opts.SparseCheckoutDirectories) != 0,
-		Depth:             depth,
+		Depth:             cloneDepth(opts),
 		RecurseSubmodules: recurseSubmodules(opts.RecurseSubmodules),
-		Progress:          nil,
+		Progress:          g.progress(ProgressOperationClone),
 		Tags:              extgogit.NoTags,
 		CABundle:          caBundle(g.authOpts),
-		ProxyOptions:      g.proxy,
+		ProxyOptions:      g.proxyOptions(url),
 	}
 
 	repo, err := extgogit.CloneContext(ctx, g.storer, g.worktreeFS, cloneOpts)
@@ -109,6 +107,9 @@ func (g *Client) cloneBranch(ctx context.Context, url, branch string, opts repos
 			return nil, fmt.Errorf("unable to clone '%s': %w", url, err)
 		}
 	}
+	if err := deepenSince(ctx, repo, cloneOpts, opts.ShallowSince); err != nil {
+		return nil, err
+	}
 
 	if len(opts.SparseCheckoutDirectories) != 0 {
 		w, err := repo.Worktree()
@@ -165,10 +166,6 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 		}
 	}
 
-	var depth int
-	if opts.ShallowClone {
-		depth = 1
-	}
 	cloneOpts := &extgogit.CloneOptions{
 		URL:               url,
 		Auth:              authMethod,
@@ -176,13 +173,13 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 		ReferenceName:     plumbing.NewTagReferenceName(tag),
 		SingleBranch:      g.singleBranch,
 		NoCheckout:        len(opts.SparseCheckoutDirectories) != 0,
-		Depth:             depth,
+		Depth:             cloneDepth(opts),
 		RecurseSubmodules: recurseSubmodules(opts.RecurseSubmodules),
-		Progress:          nil,
+		Progress:          g.progress(ProgressOperationClone),
 		// Ask for the tag object that points to the commit to be sent as well.
 		Tags:         extgogit.TagFollowing,
 		CABundle:     caBundle(g.authOpts),
-		ProxyOptions: g.proxy,
+		ProxyOptions: g.proxyOptions(url),
 	}
 
 	repo, err := extgogit.CloneContext(ctx, g.storer, g.worktreeFS, cloneOpts)
@@ -195,6 +192,9 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 		}
 		return nil, fmt.Errorf("unable to clone '%s': %w", url, err)
 	}
+	if err := deepenSince(ctx, repo, cloneOpts, opts.ShallowSince); err != nil {
+		return nil, err
+	}
 
 	if len(opts.SparseCheckoutDirectories) != 0 {
 		w, err := repo.Worktree()
@@ -234,6 +234,9 @@ func (g *Client) cloneTag(ctx context.Context, url, tag string, opts repository.
 }
 
 func (g *Client) cloneCommit(ctx context.Context, url, commit string, opts repository.CloneConfig) (*git.Commit, error) {
+	if err := validateCommitHash(commit); err != nil {
+		return nil, err
+	}
 	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
 	if err != nil {
 		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
@@ -252,10 +255,10 @@ func (g *Client) cloneCommit(ctx context.Context, url, commit string, opts repos
 		SingleBranch:      false,
 		NoCheckout:        len(opts.SparseCheckoutDirectories) != 0,
 		RecurseSubmodules: recurseSubmodules(opts.RecurseSubmodules),
-		Progress:          nil,
+		Progress:          g.progress(ProgressOperationClone),
 		Tags:              tagStrategy,
 		CABundle:          caBundle(g.authOpts),
-		ProxyOptions:      g.proxy,
+		ProxyOptions:      g.proxyOptions(url),
 	}
 	if opts.Branch != "" {
 		cloneOpts.SingleBranch = g.singleBranch
@@ -304,6 +307,12 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 	if err != nil {
 		return nil, fmt.Errorf("semver parse error: %w", err)
 	}
+	var filter *regexp.Regexp
+	if opts.SemVerFilter != "" {
+		if filter, err = regexp.Compile(opts.SemVerFilter); err != nil {
+			return nil, fmt.Errorf("semver filter parse error: %w", err)
+		}
+	}
 
 	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
 	if err != nil {
@@ -320,10 +329,10 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 		NoCheckout:        len(opts.SparseCheckoutDirectories) != 0,
 		Depth:             depth,
 		RecurseSubmodules: recurseSubmodules(opts.RecurseSubmodules),
-		Progress:          nil,
+		Progress:          g.progress(ProgressOperationClone),
 		Tags:              extgogit.AllTags,
 		CABundle:          caBundle(g.authOpts),
-		ProxyOptions:      g.proxy,
+		ProxyOptions:      g.proxyOptions(url),
 	}
 
 	repo, err := extgogit.CloneContext(ctx, g.storer, g.worktreeFS, cloneOpts)
@@ -362,16 +371,24 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 		return nil, err
 	}
 
-	var matchedVersions semver.Collection
+	type taggedVersion struct {
+		tag     string
+		version *semver.Version
+	}
+	var matchedVersions []taggedVersion
 	for tag := range tags {
-		v, err := version.ParseVersion(tag)
+		ver, ok := semVerTagVersion(tag, opts.TagPrefix, filter)
+		if !ok {
+			continue
+		}
+		v, err := version.ParseVersion(ver)
 		if err != nil {
 			continue
 		}
 		if !verConstraint.Check(v) {
 			continue
 		}
-		matchedVersions = append(matchedVersions, v)
+		matchedVersions = append(matchedVersions, taggedVersion{tag: tag, version: v})
 	}
 	if len(matchedVersions) == 0 {
 		return nil, fmt.Errorf("no match found for semver: %s", semverTag)
@@ -382,18 +399,20 @@ func (g *Client) cloneSemVer(ctx context.Context, url, semverTag string, opts re
 		left := matchedVersions[i]
 		right := matchedVersions[j]
 
-		if !left.Equal(right) {
-			return left.LessThan(right)
+		if !left.version.Equal(right.version) {
+			return left.version.LessThan(right.version)
 		}
 
 		// Having tag target timestamps at our disposal, we further try to sort
 		// versions into a chronological order. This is especially important for
 		// versions that differ only by build metadata, because it is not considered
 		// a part of the comparable version in Semver
-		return tagTimestamps[left.Original()].Before(tagTimestamps[right.Original()])
+		if !tagTimestamps[left.tag].Equal(tagTimestamps[right.tag]) {
+			return tagTimestamps[left.tag].Before(tagTimestamps[right.tag])
+		}
+		return left.tag < right.tag
 	})
-	v := matchedVersions[len(matchedVersions)-1]
-	t := v.Original()
+	t := matchedVersions[len(matchedVersions)-1].tag
 
 	w, err := repo.Worktree()
 	if err != nil {
@@ -477,9 +496,34 @@ func (g *Client) cloneRefName(ctx context.Context, url string, refName string, c
 		return commit, nil
 	}
 
+	if isChangeRef(ref) {
+		return g.cloneChangeRef(ctx, url, ref, hash, cloneOpts)
+	}
+
 	return g.cloneCommit(ctx, url, hash.String(), cloneOpts)
 }
 
+// semVerTagVersion returns the version of the tag to parse as semver, and
+// false if the tag is filtered out by the prefix or the filter.
+func semVerTagVersion(tag, prefix string, filter *regexp.Regexp) (string, bool) {
+	ver, ok := strings.CutPrefix(tag, prefix)
+	if !ok {
+		return "", false
+	}
+	if filter == nil {
+		return ver, true
+	}
+	m := filter.FindStringSubmatch(tag)
+	switch {
+	case m == nil:
+		return "", false
+	case len(m) > 1:
+		return m[1], true
+	default:
+		return ver, true
+	}
+}
+
 func recurseSubmodules(recurse bool) extgogit.SubmoduleRescursivity {
 	if recurse {
 		return extgogit.DefaultSubmoduleRecursionDepth
@@ -487,6 +531,113 @@ func recurseSubmodules(recurse bool) extgogit.SubmoduleRescursivity {
 	return extgogit.NoRecurseSubmodules
 }
 
+// cloneDepth returns the depth of the clone of a branch or tag with the given
+// configuration, or 0 for a clone of the full history.
+func cloneDepth(opts repository.CloneConfig) int {
+	switch {
+	case opts.Depth > 0:
+		return opts.Depth
+	case opts.ShallowClone || !opts.ShallowSince.IsZero():
+		return 1
+	default:
+		return 0
+	}
+}
+
+// deepenSince deepens the history of a shallow clone until it contains all
+// the commits committed after the given time, as go-git does not support
+// fetching with the --shallow-since option of git. The depth is doubled on
+// each fetch, the history may thus contain older commits.
+func deepenSince(ctx context.Context, repo *extgogit.Repository, cloneOpts *extgogit.CloneOptions, since time.Time) error {
+	if since.IsZero() {
+		return nil
+	}
+
+	ref := cloneOpts.ReferenceName
+	refSpec := config.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))
+	if ref.IsBranch() {
+		refSpec = config.RefSpec(fmt.Sprintf("+%s:%s", ref,
+			plumbing.NewRemoteReferenceName(cloneOpts.RemoteName, ref.Short())))
+	}
+
+	depth := cloneOpts.Depth
+	var previous []plumbing.Hash
+	for {
+		shallows, err := pruneShallows(repo)
+		if err != nil {
+			return err
+		}
+		// Stop if the previous fetch did not deepen the history.
+		if previous != nil && slices.Equal(shallows, previous) {
+			return nil
+		}
+		previous = shallows
+
+		deep := true
+		for _, h := range shallows {
+			c, err := repo.CommitObject(h)
+			if err != nil {
+				return fmt.Errorf("unable to resolve shallow commit '%s': %w", h, err)
+			}
+			if c.Committer.When.After(since) {
+				deep = false
+				break
+			}
+		}
+		if deep {
+			return nil
+		}
+
+		depth *= 2
+		err = repo.FetchContext(ctx, &extgogit.FetchOptions{
+			RemoteName:   cloneOpts.RemoteName,
+			RefSpecs:     []config.RefSpec{refSpec},
+			Depth:        depth,
+			Auth:         cloneOpts.Auth,
+			Progress:     cloneOpts.Progress,
+			Tags:         cloneOpts.Tags,
+			CABundle:     cloneOpts.CABundle,
+			ProxyOptions: cloneOpts.ProxyOptions,
+		})
+		if err != nil && err != extgogit.NoErrAlreadyUpToDate {
+			return fmt.Errorf("unable to deepen shallow clone since '%s': %w", since.Format(time.RFC3339), err)
+		}
+	}
+}
+
+// pruneShallows removes the commits of which all the parents were fetched
+// from the shallow commits of the repository, as go-git only adds the new
+// shallow commits when deepening a clone. It returns the remaining shallow
+// commits, sorted.
+func pruneShallows(repo *extgogit.Repository) ([]plumbing.Hash, error) {
+	shallows, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("unable to read shallow commits: %w", err)
+	}
+	var pruned []plumbing.Hash
+	for _, h := range shallows {
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve shallow commit '%s': %w", h, err)
+		}
+		for _, p := range c.ParentHashes {
+			if _, err := repo.Storer.EncodedObject(plumbing.CommitObject, p); err != nil {
+				pruned = append(pruned, h)
+				break
+			}
+		}
+	}
+	if len(pruned) != len(shallows) {
+		if err := repo.Storer.SetShallow(pruned); err != nil {
+			return nil, fmt.Errorf("unable to write shallow commits: %w", err)
+		}
+	}
+	slices.SortFunc(pruned, func(a, b plumbing.Hash) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return pruned, nil
+}
+
 func (g *Client) getRemoteHEAD(ctx context.Context, url string, ref plumbing.ReferenceName,
 	authMethod transport.AuthMethod,
 ) (string, error) {
@@ -495,6 +646,18 @@ func (g *Client) getRemoteHEAD(ctx context.Context, url string, ref plumbing.Ref
 		return "", fmt.Errorf("ref %s is invalid; Git refs cannot begin or end with a slash '/'", ref.String())
 	}
 
+	refs, err := g.listRemoteRefs(ctx, url, authMethod)
+	if err != nil {
+		return "", err
+	}
+
+	head := filterRefs(refs, ref)
+	return head, nil
+}
+
+// listRemoteRefs lists the references of the remote at the given url,
+// including the peeled references of the annotated tags.
+func (g *Client) listRemoteRefs(ctx context.Context, url string, authMethod transport.AuthMethod) ([]*plumbing.Reference, error) {
 	remoteCfg := &config.RemoteConfig{
 		Name: git.DefaultRemote,
 		URLs: []string{url},
@@ -504,15 +667,13 @@ func (g *Client) getRemoteHEAD(ctx context.Context, url string, ref plumbing.Ref
 		Auth:          authMethod,
 		CABundle:      caBundle(g.authOpts),
 		PeelingOption: extgogit.AppendPeeled,
-		ProxyOptions:  g.proxy,
+		ProxyOptions:  g.proxyOptions(url),
 	}
 	refs, err := remote.ListContext(ctx, listOpts)
 	if err != nil {
-		return "", fmt.Errorf("unable to list remote for '%s': %w", url, err)
+		return nil, fmt.Errorf("unable to list remote for '%s': %w", url, err)
 	}
-
-	head := filterRefs(refs, ref)
-	return head, nil
+	return refs, nil
 }
 
 // filterRefs searches through the provided list of refs to find a matching ref
@@ -521,6 +521,93 @@ func TestClone_cloneSemVer(t *testing.T) {
 	}
 }
 
+func TestClone_cloneSemVer_filter(t *testing.T) {
+	repo, path, err := initRepo(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refs := make(map[string]string)
+	for _, tt := range []string{"component-a/v1.0.0", "component-a/v1.2.0", "component-b/v2.0.0", "v3.0.0"} {
+		ref, err := commitFile(repo, "tag", tt, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = tag(repo, ref, false, tt, time.Now()); err != nil {
+			t.Fatal(err)
+		}
+		refs[tt] = ref.String()
+	}
+
+	tests := []struct {
+		name       string
+		constraint string
+		prefix     string
+		filter     string
+		expectErr  string
+		expectTag  string
+	}{
+		{
+			name:       "Matches all tags without prefix",
+			constraint: ">=1.0.0",
+			expectTag:  "v3.0.0",
+		},
+		{
+			name:       "Filters tags by prefix",
+			constraint: ">=1.0.0",
+			prefix:     "component-a/",
+			expectTag:  "component-a/v1.2.0",
+		},
+		{
+			name:       "Filters tags by regex with capturing group",
+			constraint: ">=1.0.0",
+			filter:     "^component-b/(v.*)$",
+			expectTag:  "component-b/v2.0.0",
+		},
+		{
+			name:       "Filters tags by prefix and regex",
+			constraint: ">=1.0.0",
+			prefix:     "component-a/",
+			filter:     `^component-a/v1\.0\.`,
+			expectTag:  "component-a/v1.0.0",
+		},
+		{
+			name:       "Errors without match",
+			constraint: ">=2.0.0",
+			prefix:     "component-a/",
+			expectErr:  "no match found for semver: >=2.0.0",
+		},
+		{
+			name:       "Errors on invalid filter",
+			constraint: ">=1.0.0",
+			filter:     "(",
+			expectErr:  "semver filter parse error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ggc, err := NewClient(t.TempDir(), nil)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cc, err := ggc.Clone(context.TODO(), path, repository.CloneConfig{
+				CheckoutStrategy: repository.CheckoutStrategy{
+					SemVer:       tt.constraint,
+					TagPrefix:    tt.prefix,
+					SemVerFilter: tt.filter,
+				},
+			})
+			if tt.expectErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.expectErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cc.String()).To(Equal(tt.expectTag + "@" + git.HashTypeSHA1 + ":" + refs[tt.expectTag]))
+		})
+	}
+}
+
 func TestClone_cloneRefName(t *testing.T) {
 	g := NewWithT(t)
 
@@ -1967,3 +2054,97 @@ func setupHTTPProxy(proxy *goproxy.ProxyHttpServer, proxiedRequests *int32) {
 	}
 	proxy.OnRequest().Do(proxyHandler)
 }
+
+func TestClone_shallowHistory(t *testing.T) {
+	repo, repoPath, err := initRepo(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now().Add(-10 * time.Hour)
+	var commits []plumbing.Hash
+	for i := 0; i < 8; i++ {
+		cc, err := commitFile(repo, "file", fmt.Sprintf("commit %d", i), start.Add(time.Duration(i)*time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		commits = append(commits, cc)
+	}
+	if _, err = tag(repo, commits[7], true, "v1.0.0", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		strategy     repository.CheckoutStrategy
+		depth        int
+		shallowSince time.Time
+		wantCommits  int
+	}{
+		{
+			name:        "branch with depth",
+			strategy:    repository.CheckoutStrategy{Branch: "master"},
+			depth:       3,
+			wantCommits: 3,
+		},
+		{
+			name:        "tag with depth",
+			strategy:    repository.CheckoutStrategy{Tag: "v1.0.0"},
+			depth:       2,
+			wantCommits: 2,
+		},
+		{
+			name:        "refname with depth",
+			strategy:    repository.CheckoutStrategy{RefName: "refs/heads/master"},
+			depth:       5,
+			wantCommits: 5,
+		},
+		{
+			name:         "branch since time",
+			strategy:     repository.CheckoutStrategy{Branch: "master"},
+			shallowSince: start.Add(4*time.Hour + time.Minute),
+			// The depth is doubled until the history contains the 3
+			// commits after the time.
+			wantCommits: 4,
+		},
+		{
+			name:         "tag since time",
+			strategy:     repository.CheckoutStrategy{Tag: "v1.0.0"},
+			depth:        2,
+			shallowSince: start.Add(time.Hour + time.Minute),
+			wantCommits:  8,
+		},
+		{
+			name:         "since time older than the history",
+			strategy:     repository.CheckoutStrategy{Branch: "master"},
+			shallowSince: start.Add(-time.Hour),
+			wantCommits:  8,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			cc, err := ggc.Clone(context.TODO(), repoPath, repository.CloneConfig{
+				CheckoutStrategy: tt.strategy,
+				Depth:            tt.depth,
+				ShallowSince:     tt.shallowSince,
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cc.Hash.String()).To(Equal(commits[7].String()))
+
+			iter, err := ggc.repository.Storer.IterEncodedObjects(plumbing.CommitObject)
+			g.Expect(err).ToNot(HaveOccurred())
+			var count int
+			g.Expect(iter.ForEach(func(plumbing.EncodedObject) error {
+				count++
+				return nil
+			})).To(Succeed())
+			g.Expect(count).To(Equal(tt.wantCommits))
+		})
+	}
+}
@@ -0,0 +1,393 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+const (
+	// DefaultCloneCacheSize is the default maximum number of clones kept
+	// in a CloneCache.
+	DefaultCloneCacheSize = 10
+	// DefaultCloneCacheTTL is the default duration after which a clone kept
+	// in a CloneCache is considered stale and cloned again.
+	DefaultCloneCacheTTL = 10 * time.Minute
+)
+
+// CloneCache keeps on disk the N most recent clones of the repositories,
+// keyed by URL, revision and credentials, so that repeated clones of the
+// same source skip the network operations entirely. It is safe for concurrent use and can be
+// shared by the clients of all the reconciles of a controller.
+//
+// The revision is the checkout strategy of the clone configuration. A clone
+// of a branch, tag, SemVer range or reference name is considered stale after
+// the TTL of the cache, and is cloned again on the next use. A clone of a
+// commit is immutable, the TTL only bounds how long it is kept on disk.
+//
+// A cached clone is only served to the clients authenticating with the same
+// credentials, and host verification settings, as the client which cloned
+// it. Clients with other credentials clone the repository again, so that
+// they cannot read a private repository they are not authorized to access.
+//
+// Clients handed a CloneCache with WithCloneCache get a private copy of the
+// cached clone, which they can modify without affecting the cache.
+type CloneCache struct {
+	dir     string
+	maxSize int
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cloneCacheEntry
+}
+
+// cloneCacheEntry is a clone kept in a CloneCache. The mutex of the entry
+// is held while the clone is created or copied, so that it is never removed
+// or replaced while in use.
+type cloneCacheEntry struct {
+	mu       sync.Mutex
+	path     string
+	commit   *git.Commit
+	created  time.Time
+	lastUsed time.Time
+}
+
+// CloneCacheOption configures a CloneCache.
+type CloneCacheOption func(*CloneCache)
+
+// WithCloneCacheSize sets the maximum number of clones kept in the cache.
+// When the maximum is reached, the least recently used clone is removed.
+func WithCloneCacheSize(size int) CloneCacheOption {
+	return func(c *CloneCache) {
+		c.maxSize = size
+	}
+}
+
+// WithCloneCacheTTL sets the duration after which a clone kept in the cache
+// is considered stale.
+func WithCloneCacheTTL(ttl time.Duration) CloneCacheOption {
+	return func(c *CloneCache) {
+		c.ttl = ttl
+	}
+}
+
+// NewCloneCache returns a new CloneCache storing the clones in the given
+// directory, which is created if it does not exist.
+func NewCloneCache(dir string, opts ...CloneCacheOption) (*CloneCache, error) {
+	c := &CloneCache{
+		dir:     dir,
+		maxSize: DefaultCloneCacheSize,
+		ttl:     DefaultCloneCacheTTL,
+		now:     time.Now,
+		entries: make(map[string]*cloneCacheEntry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.maxSize < 1 {
+		return nil, fmt.Errorf("invalid clone cache size %d: must be at least 1", c.maxSize)
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create clone cache directory: %w", err)
+	}
+	return c, nil
+}
+
+// Len returns the number of clones in the cache.
+func (c *CloneCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Purge removes all the clones from the cache.
+func (c *CloneCache) Purge() error {
+	c.mu.Lock()
+	entries := c.entries
+	c.entries = make(map[string]*cloneCacheEntry)
+	c.mu.Unlock()
+
+	var errs []error
+	for _, e := range entries {
+		e.mu.Lock()
+		if err := os.RemoveAll(e.path); err != nil {
+			errs = append(errs, err)
+		}
+		e.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// WithCloneCache configures the client to clone the repositories through
+// the given CloneCache. The cache is only used by clients storing the
+// repository on disk with WithDiskStorage.
+func WithCloneCache(cache *CloneCache) ClientOption {
+	return func(c *Client) error {
+		c.cloneCache = cache
+		return nil
+	}
+}
+
+// clone checks out the repository at the given URL in the path of the
+// client, from the cached clone of the URL and revision if there is a fresh
+// one, or from a new clone added to the cache otherwise.
+func (c *CloneCache) clone(ctx context.Context, g *Client, url string, cfg repository.CloneConfig) (*git.Commit, error) {
+	key := cloneCacheKey(url, g.authOpts, cfg)
+	e, evicted := c.entry(key)
+	for _, old := range evicted {
+		old.mu.Lock()
+		_ = os.RemoveAll(old.path)
+		old.mu.Unlock()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	now := c.now()
+	if e.commit == nil || (cfg.Commit == "" && now.Sub(e.created) > c.ttl) {
+		commit, err := c.fill(ctx, g, key, e, url, cfg)
+		if err != nil {
+			c.remove(key, e)
+			return nil, err
+		}
+		e.commit, e.created = commit, now
+	}
+	e.lastUsed = now
+
+	// Construct a non-concrete commit if the cached commit is the last
+	// observed one, like a clone skipping an unchanged revision.
+	if lastObserved := git.TransformRevision(cfg.LastObservedCommit); lastObserved != "" &&
+		(lastObserved == e.commit.String() || lastObserved == e.commit.AbsoluteReference()) {
+		return &git.Commit{
+			Hash:      e.commit.Hash,
+			Reference: e.commit.Reference,
+		}, nil
+	}
+
+	if err := copyDir(e.path, g.path); err != nil {
+		return nil, fmt.Errorf("failed to checkout cached clone: %w", err)
+	}
+	r, err := extgogit.Open(g.storer, g.worktreeFS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cached clone: %w", err)
+	}
+	g.repository = r
+
+	commit := *e.commit
+	return &commit, nil
+}
+
+// fill clones the repository in a new directory of the cache, and replaces
+// the previous clone of the entry with it.
+func (c *CloneCache) fill(ctx context.Context, g *Client, key string, e *cloneCacheEntry, url string, cfg repository.CloneConfig) (*git.Commit, error) {
+	path, err := os.MkdirTemp(c.dir, fmt.Sprintf("%x-", sha256.Sum256([]byte(key))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clone cache entry: %w", err)
+	}
+
+	// Clone with the transport settings of the client, in the directory of
+	// the cache.
+	cc := *g
+	cc.path = path
+	cc.repository = nil
+	cc.cloneCache = nil
+	if err := WithDiskStorage()(&cc); err != nil {
+		_ = os.RemoveAll(path)
+		return nil, err
+	}
+	cfg.LastObservedCommit = ""
+	commit, err := cc.clone(ctx, url, cfg)
+	if err != nil {
+		_ = os.RemoveAll(path)
+		return nil, err
+	}
+
+	if e.path != "" {
+		_ = os.RemoveAll(e.path)
+	}
+	e.path = path
+	return commit, nil
+}
+
+// entry returns the entry of the given key, adding it to the cache if it
+// does not exist. The least recently used entries are evicted to keep the
+// cache within its maximum size, and returned for their clones to be
+// removed.
+func (c *CloneCache) entry(key string) (*cloneCacheEntry, []*cloneCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		return e, nil
+	}
+	var evicted []*cloneCacheEntry
+	for len(c.entries) >= c.maxSize {
+		var oldestKey string
+		var oldest *cloneCacheEntry
+		for k, e := range c.entries {
+			if oldest == nil || e.lastUsed.Before(oldest.lastUsed) {
+				oldestKey, oldest = k, e
+			}
+		}
+		delete(c.entries, oldestKey)
+		evicted = append(evicted, oldest)
+	}
+	e := &cloneCacheEntry{lastUsed: c.now()}
+	c.entries[key] = e
+	return e, evicted
+}
+
+// remove removes the given entry of the key from the cache, if it was not
+// replaced in the meantime.
+func (c *CloneCache) remove(key string, e *cloneCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == e {
+		delete(c.entries, key)
+	}
+}
+
+// cloneCacheKey returns the key of a clone of the given URL with the auth
+// options and configuration. It is composed of the URL, a hash of the auth
+// options, the checkout strategy and the options changing the content of
+// the checkout.
+func cloneCacheKey(url string, authOpts *git.AuthOptions, cfg repository.CloneConfig) string {
+	strategy := cfg.CheckoutStrategy
+	var revision string
+	switch {
+	case strategy.Commit != "":
+		revision = "commit:" + strategy.Commit
+	case strategy.RefName != "":
+		revision = "ref:" + strategy.RefName
+	case strategy.Change != "":
+		revision = "change:" + strategy.Change
+	case strategy.Tag != "":
+		revision = "tag:" + strategy.Tag
+	case strategy.SemVer != "":
+		revision = "semver:" + strategy.SemVer + ":" + strategy.TagPrefix + ":" + strategy.SemVerFilter
+	default:
+		branch := strategy.Branch
+		if branch == "" {
+			branch = git.DefaultBranch
+		}
+		revision = "branch:" + branch
+	}
+	return strings.Join([]string{
+		url,
+		"auth:" + cloneCacheAuthKey(authOpts),
+		revision,
+		fmt.Sprintf("submodules:%t", cfg.RecurseSubmodules),
+		fmt.Sprintf("shallow:%t", cfg.ShallowClone),
+		fmt.Sprintf("depth:%d", cfg.Depth),
+		"shallow-since:" + cfg.ShallowSince.UTC().Format(time.RFC3339),
+		"sparse:" + strings.Join(cfg.SparseCheckoutDirectories, ","),
+	}, "\n")
+}
+
+// cloneCacheAuthKey returns a hash of the credentials and host verification
+// settings of the given auth options. The credentials of a provider are
+// resolved before cloning, and are part of the hash.
+func cloneCacheAuthKey(authOpts *git.AuthOptions) string {
+	if authOpts == nil {
+		return ""
+	}
+	h := sha256.New()
+	for _, v := range [][]byte{
+		[]byte(authOpts.Transport),
+		[]byte(authOpts.Host),
+		[]byte(authOpts.Username),
+		[]byte(authOpts.Password),
+		[]byte(authOpts.BearerToken),
+		authOpts.Identity,
+		authOpts.KnownHosts,
+		authOpts.CAFile,
+		[]byte(strings.Join(authOpts.HostKeyFingerprints, ",")),
+	} {
+		// Prefix each value with its length, so that the values cannot be
+		// shifted between fields to produce the same hash.
+		fmt.Fprintf(h, "%d:", len(v))
+		h.Write(v)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// copyDir copies the files, directories and symlinks of src to dst.
+func copyDir(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		default:
+			return nil
+		}
+	})
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestCloneCache_Branch(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, repoPath, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	firstCommit, err := commitFile(repo, "branch", "init", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	now := time.Now()
+	cache, err := NewCloneCache(t.TempDir(), WithCloneCacheTTL(time.Minute))
+	g.Expect(err).ToNot(HaveOccurred())
+	cache.now = func() time.Time { return now }
+
+	cfg := repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"},
+	}
+	clone := func() (*git.Commit, string) {
+		dir := t.TempDir()
+		ggc, err := NewClient(dir, &git.AuthOptions{Transport: git.HTTP}, WithDiskStorage(), WithCloneCache(cache))
+		g.Expect(err).ToNot(HaveOccurred())
+		cc, err := ggc.Clone(context.TODO(), repoPath, cfg)
+		g.Expect(err).ToNot(HaveOccurred())
+		return cc, dir
+	}
+
+	cc, dir := clone()
+	g.Expect(cc.Hash.String()).To(Equal(firstCommit.String()))
+	g.Expect(git.IsConcreteCommit(*cc)).To(BeTrue())
+	g.Expect(os.ReadFile(filepath.Join(dir, "branch"))).To(BeEquivalentTo("init"))
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// Changes to a checkout do not affect the cached clone.
+	g.Expect(os.WriteFile(filepath.Join(dir, "branch"), []byte("changed"), 0o600)).To(Succeed())
+
+	// A new commit upstream is not observed until the clone is stale.
+	secondCommit, err := commitFile(repo, "branch", "second", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cc, dir = clone()
+	g.Expect(cc.Hash.String()).To(Equal(firstCommit.String()))
+	g.Expect(os.ReadFile(filepath.Join(dir, "branch"))).To(BeEquivalentTo("init"))
+
+	now = now.Add(2 * time.Minute)
+	cc, dir = clone()
+	g.Expect(cc.Hash.String()).To(Equal(secondCommit.String()))
+	g.Expect(os.ReadFile(filepath.Join(dir, "branch"))).To(BeEquivalentTo("second"))
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// The last observed commit is not checked out.
+	cfg.LastObservedCommit = cc.String()
+	cc, dir = clone()
+	g.Expect(cc.Hash.String()).To(Equal(secondCommit.String()))
+	g.Expect(git.IsConcreteCommit(*cc)).To(BeFalse())
+	g.Expect(filepath.Join(dir, "branch")).ToNot(BeAnExistingFile())
+}
+
+func TestCloneCache_Commit(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, repoPath, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	commit, err := commitFile(repo, "commit", "init", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cache, err := NewCloneCache(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cfg := repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Commit: commit.String()},
+	}
+	ggc, err := NewClient(t.TempDir(), nil, WithDiskStorage(), WithCloneCache(cache))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Clone(context.TODO(), repoPath, cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The clone of a commit is served from the cache without the upstream
+	// repository.
+	g.Expect(os.RemoveAll(repoPath)).To(Succeed())
+
+	dir := t.TempDir()
+	ggc, err = NewClient(dir, nil, WithDiskStorage(), WithCloneCache(cache))
+	g.Expect(err).ToNot(HaveOccurred())
+	cc, err := ggc.Clone(context.TODO(), repoPath, cfg)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cc.Hash.String()).To(Equal(commit.String()))
+	g.Expect(os.ReadFile(filepath.Join(dir, "commit"))).To(BeEquivalentTo("init"))
+
+	// The checkout is a usable repository.
+	head, err := ggc.Head()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(head).To(ContainSubstring(commit.String()))
+}
+
+func TestCloneCache_Credentials(t *testing.T) {
+	g := NewWithT(t)
+
+	server, _, err := setupGitServer(true)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+	defer server.StopHTTP()
+	repoURL := server.HTTPAddress() + "/test.git"
+
+	cache, err := NewCloneCache(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	clone := func(authOpts *git.AuthOptions) error {
+		ggc, err := NewClient(t.TempDir(), authOpts, WithDiskStorage(), WithCloneCache(cache),
+			WithInsecureCredentialsOverHTTP())
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), repoURL, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+		})
+		return err
+	}
+
+	g.Expect(clone(&git.AuthOptions{
+		Transport: git.HTTP,
+		Username:  "test-user",
+		Password:  "test-pass",
+	})).To(Succeed())
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// The cached clone of the private repository is not served to the
+	// clients with other credentials, or without credentials.
+	g.Expect(clone(&git.AuthOptions{
+		Transport: git.HTTP,
+		Username:  "test-user",
+		Password:  "wrong-pass",
+	})).ToNot(Succeed())
+	g.Expect(clone(&git.AuthOptions{Transport: git.HTTP})).ToNot(Succeed())
+	g.Expect(clone(nil)).ToNot(Succeed())
+	g.Expect(cache.Len()).To(Equal(1))
+
+	// The cached clone is served to the clients with the same credentials.
+	g.Expect(clone(&git.AuthOptions{
+		Transport: git.HTTP,
+		Username:  "test-user",
+		Password:  "test-pass",
+	})).To(Succeed())
+	g.Expect(cache.Len()).To(Equal(1))
+}
+
+func TestCloneCache_Eviction(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, repoPath, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	first, err := commitFile(repo, "file", "first", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	second, err := commitFile(repo, "file", "second", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	cacheDir := t.TempDir()
+	cache, err := NewCloneCache(cacheDir, WithCloneCacheSize(1))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for _, commit := range []string{first.String(), second.String()} {
+		ggc, err := NewClient(t.TempDir(), nil, WithDiskStorage(), WithCloneCache(cache))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), repoPath, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{Commit: commit},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(cache.Len()).To(Equal(1))
+	entries, err := os.ReadDir(cacheDir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(HaveLen(1))
+
+	g.Expect(cache.Purge()).To(Succeed())
+	g.Expect(cache.Len()).To(BeZero())
+	entries, err = os.ReadDir(cacheDir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(BeEmpty())
+}
+
+func TestCloneCache_Error(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewCloneCache(t.TempDir(), WithCloneCacheSize(0))
+	g.Expect(err).To(MatchError("invalid clone cache size 0: must be at least 1"))
+
+	cacheDir := t.TempDir()
+	cache, err := NewCloneCache(cacheDir)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, WithDiskStorage(), WithCloneCache(cache))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Clone(context.TODO(), filepath.Join(t.TempDir(), "missing"), repository.CloneConfig{})
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(cache.Len()).To(BeZero())
+	entries, err := os.ReadDir(cacheDir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(BeEmpty())
+}
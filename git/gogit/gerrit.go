@@ -0,0 +1,378 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+// changeRefPrefix is the prefix of the refs of the patchsets of the Gerrit
+// changes.
+const changeRefPrefix = "refs/changes/"
+
+// ChangeRefName returns the name of the ref of the patchset of the Gerrit
+// change, e.g. "refs/changes/34/1234/2" for the patchset 2 of the change
+// 1234.
+func ChangeRefName(change, patchset int) string {
+	return fmt.Sprintf("%s%02d/%d/%d", changeRefPrefix, change%100, change, patchset)
+}
+
+// ListPatchSets lists the refs of the patchsets of the Gerrit change of the
+// repository at the given url, sorted by patchset number.
+func (g *Client) ListPatchSets(ctx context.Context, url string, change int) ([]git.Reference, error) {
+	if change <= 0 {
+		return nil, fmt.Errorf("invalid Gerrit change '%d'", change)
+	}
+	if err := g.validateUrlAndAuthOptions(url); err != nil {
+		return nil, err
+	}
+
+	parent := ctx
+	ctx, cancel := operationContext(ctx, g.listRemoteTimeout)
+	defer cancel()
+
+	if err := g.providerAuth(ctx); err != nil {
+		return nil, operationError(parent, ctx, "ls-remote", g.listRemoteTimeout, err)
+	}
+
+	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+
+	refs, err := g.listRemoteRefs(ctx, url, authMethod)
+	if err != nil {
+		if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+			return nil, nil
+		}
+		return nil, operationError(parent, ctx, "ls-remote", g.listRemoteTimeout, err)
+	}
+
+	var result []git.Reference
+	for _, ps := range changePatchSets(refs, change) {
+		result = append(result, git.Reference{
+			Name: ps.ref.Name().String(),
+			Hash: git.Hash(ps.ref.Hash().String()),
+		})
+	}
+	return result, nil
+}
+
+type patchSet struct {
+	number int
+	ref    *plumbing.Reference
+}
+
+// changePatchSets returns the patchsets of the change among the refs, sorted
+// by number. The refs of the change which are not patchsets, e.g. the meta
+// ref of NoteDb, are ignored.
+func changePatchSets(refs []*plumbing.Reference, change int) []patchSet {
+	prefix := fmt.Sprintf("%s%02d/%d/", changeRefPrefix, change%100, change)
+	var patchSets []patchSet
+	for _, ref := range refs {
+		suffix, ok := strings.CutPrefix(ref.Name().String(), prefix)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(suffix)
+		if err != nil || n <= 0 {
+			continue
+		}
+		patchSets = append(patchSets, patchSet{number: n, ref: ref})
+	}
+	sort.Slice(patchSets, func(i, j int) bool {
+		return patchSets[i].number < patchSets[j].number
+	})
+	return patchSets
+}
+
+// parseChange parses a change of the "<change>" or "<change>/<patchset>"
+// form. The patchset is zero when not specified.
+func parseChange(s string) (change, patchset int, err error) {
+	c, p, hasPatchSet := strings.Cut(s, "/")
+	if change, err = strconv.Atoi(c); err != nil || change <= 0 {
+		return 0, 0, fmt.Errorf("invalid Gerrit change '%s'", s)
+	}
+	if hasPatchSet {
+		if patchset, err = strconv.Atoi(p); err != nil || patchset <= 0 {
+			return 0, 0, fmt.Errorf("invalid Gerrit change '%s': invalid patchset", s)
+		}
+	}
+	return change, patchset, nil
+}
+
+// isChangeRef returns true if the ref is the ref of a patchset of a Gerrit
+// change.
+func isChangeRef(ref plumbing.ReferenceName) bool {
+	return strings.HasPrefix(ref.String(), changeRefPrefix)
+}
+
+func (g *Client) cloneChange(ctx context.Context, url, change string, opts repository.CloneConfig) (*git.Commit, error) {
+	number, patchset, err := parseChange(change)
+	if err != nil {
+		return nil, err
+	}
+	if g.authOpts == nil {
+		return nil, fmt.Errorf("unable to checkout repo with an empty set of auth options")
+	}
+	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+
+	refs, err := g.listRemoteRefs(ctx, url, authMethod)
+	if err != nil {
+		return nil, err
+	}
+	patchSets := changePatchSets(refs, number)
+	if len(patchSets) == 0 {
+		return nil, fmt.Errorf("unable to find Gerrit change '%d'", number)
+	}
+	ps := patchSets[len(patchSets)-1]
+	if patchset != 0 {
+		i := sort.Search(len(patchSets), func(i int) bool {
+			return patchSets[i].number >= patchset
+		})
+		if i == len(patchSets) || patchSets[i].number != patchset {
+			return nil, fmt.Errorf("unable to find patchset '%d' of Gerrit change '%d'", patchset, number)
+		}
+		ps = patchSets[i]
+	}
+
+	ref, hash := ps.ref.Name(), git.Hash(ps.ref.Hash().String())
+	// check if previous revision has changed before attempting to clone
+	if lastObserved := git.TransformRevision(opts.LastObservedCommit); lastObserved != "" {
+		if fmt.Sprintf("%s@%s", ref, hash.Digest()) == lastObserved {
+			// Construct a non-concrete commit with the existing information.
+			c := &git.Commit{
+				Reference: ref.String(),
+				Hash:      hash,
+			}
+			return c, nil
+		}
+	}
+
+	return g.cloneChangeRef(ctx, url, ref, hash, opts)
+}
+
+// cloneChangeRef fetches the ref of the patchset of a Gerrit change, and
+// checks out the commit it points to. The ref is fetched explicitly as the
+// patchsets are not reachable from the branches until they are merged.
+func (g *Client) cloneChangeRef(ctx context.Context, url string, ref plumbing.ReferenceName, hash git.Hash,
+	opts repository.CloneConfig,
+) (*git.Commit, error) {
+	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+
+	repo, err := extgogit.Init(g.storer, g.worktreeFS)
+	if err != nil {
+		return nil, fmt.Errorf("unable to init repository: %w", err)
+	}
+	if err = g.setObjectFormat(repo); err != nil {
+		return nil, err
+	}
+	if _, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemote,
+		URLs: []string{url},
+	}); err != nil {
+		return nil, err
+	}
+
+	cloneOpts := &extgogit.CloneOptions{
+		URL:           url,
+		Auth:          authMethod,
+		RemoteName:    git.DefaultRemote,
+		ReferenceName: ref,
+		Depth:         cloneDepth(opts),
+		Progress:      g.progress(ProgressOperationClone),
+		Tags:          extgogit.NoTags,
+		CABundle:      caBundle(g.authOpts),
+		ProxyOptions:  g.proxyOptions(url),
+	}
+	err = repo.FetchContext(ctx, &extgogit.FetchOptions{
+		RemoteName:   cloneOpts.RemoteName,
+		RefSpecs:     []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))},
+		Depth:        cloneOpts.Depth,
+		Auth:         cloneOpts.Auth,
+		Progress:     cloneOpts.Progress,
+		Tags:         cloneOpts.Tags,
+		CABundle:     cloneOpts.CABundle,
+		ProxyOptions: cloneOpts.ProxyOptions,
+	})
+	if err != nil {
+		if err == transport.ErrRepositoryNotFound || isRemoteBranchNotFoundErr(err, ref.String()) {
+			return nil, git.ErrRepositoryNotFound{
+				Message: fmt.Sprintf("unable to clone: %s", err),
+				URL:     url,
+			}
+		}
+		return nil, fmt.Errorf("unable to fetch '%s' from '%s': %w", ref, url, err)
+	}
+	if err := deepenSince(ctx, repo, cloneOpts, opts.ShallowSince); err != nil {
+		return nil, err
+	}
+
+	fetched, err := repo.Reference(ref, false)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve ref '%s': %w", ref, err)
+	}
+	if fetched.Hash().String() != hash.String() {
+		return nil, fmt.Errorf("ref '%s' changed during clone: expected '%s', got '%s'", ref, hash, fetched.Hash())
+	}
+	cc, err := repo.CommitObject(fetched.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve commit object for '%s': %w", ref, err)
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("unable to open repo worktree: %w", err)
+	}
+	err = w.Checkout(&extgogit.CheckoutOptions{
+		Hash:                      cc.Hash,
+		Force:                     true,
+		SparseCheckoutDirectories: opts.SparseCheckoutDirectories,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to checkout ref '%s': %w", ref, err)
+	}
+	if opts.RecurseSubmodules {
+		subs, err := w.Submodules()
+		if err != nil {
+			return nil, fmt.Errorf("unable to read submodules: %w", err)
+		}
+		err = subs.UpdateContext(ctx, &extgogit.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: extgogit.DefaultSubmoduleRecursionDepth,
+			Auth:              authMethod,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to update submodules: %w", err)
+		}
+	}
+
+	g.repository = repo
+	return buildCommitWithRef(cc, nil, ref)
+}
+
+// FetchNotes fetches the notes ref from the origin remote of the repository,
+// e.g. "refs/notes/review" as written by the reviewnotes plugin of Gerrit,
+// or "refs/notes/commits".
+func (g *Client) FetchNotes(ctx context.Context, notesRef string) error {
+	if g.repository == nil {
+		return git.ErrNoGitRepository
+	}
+	ref := plumbing.ReferenceName(notesRef)
+	if !strings.HasPrefix(notesRef, "refs/notes/") {
+		return fmt.Errorf("invalid notes ref '%s'", notesRef)
+	}
+	if err := ref.Validate(); err != nil {
+		return fmt.Errorf("invalid notes ref '%s': %w", notesRef, err)
+	}
+
+	if err := g.providerAuth(ctx); err != nil {
+		return err
+	}
+	authMethod, err := transportAuth(g.authOpts, g.useDefaultKnownHosts)
+	if err != nil {
+		return fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+
+	err = g.repository.FetchContext(ctx, &extgogit.FetchOptions{
+		RemoteName:   git.DefaultRemote,
+		RefSpecs:     []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", ref, ref))},
+		Auth:         authMethod,
+		Progress:     g.progress(ProgressOperationFetch),
+		Tags:         extgogit.NoTags,
+		CABundle:     caBundle(g.authOpts),
+		ProxyOptions: g.proxyOptions(g.remoteURL()),
+	})
+	if err != nil && err != extgogit.NoErrAlreadyUpToDate {
+		return fmt.Errorf("unable to fetch notes ref '%s': %w", notesRef, err)
+	}
+	return nil
+}
+
+// Note returns the note of the commit in the notes ref of the repository,
+// or an empty string if the commit has no note. The notes ref must have been
+// fetched, see FetchNotes.
+func (g *Client) Note(notesRef, commit string) (string, error) {
+	if g.repository == nil {
+		return "", git.ErrNoGitRepository
+	}
+	if err := validateCommitHash(commit); err != nil {
+		return "", err
+	}
+
+	ref, err := g.repository.Reference(plumbing.ReferenceName(notesRef), true)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve notes ref '%s': %w", notesRef, err)
+	}
+	notes, err := g.repository.CommitObject(ref.Hash())
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve commit of notes ref '%s': %w", notesRef, err)
+	}
+	tree, err := notes.Tree()
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve tree of notes ref '%s': %w", notesRef, err)
+	}
+
+	// The notes are named after the annotated commits, and may be fanned
+	// out in directories named after the leading bytes of the hashes.
+	for fanout := 0; fanout < len(commit)/2; fanout++ {
+		var path strings.Builder
+		for i := 0; i < fanout; i++ {
+			path.WriteString(commit[2*i:2*i+2] + "/")
+		}
+		path.WriteString(commit[2*fanout:])
+		f, err := tree.File(path.String())
+		if err != nil {
+			if errors.Is(err, object.ErrFileNotFound) || errors.Is(err, object.ErrDirectoryNotFound) {
+				continue
+			}
+			return "", fmt.Errorf("unable to read note of '%s': %w", commit, err)
+		}
+		r, err := f.Reader()
+		if err != nil {
+			return "", fmt.Errorf("unable to read note of '%s': %w", commit, err)
+		}
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return "", fmt.Errorf("unable to read note of '%s': %w", commit, err)
+		}
+		return string(b), nil
+	}
+	return "", nil
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestChangeRefName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(ChangeRefName(1234, 2)).To(Equal("refs/changes/34/1234/2"))
+	g.Expect(ChangeRefName(5, 1)).To(Equal("refs/changes/05/5/1"))
+}
+
+func Test_parseChange(t *testing.T) {
+	tests := []struct {
+		change       string
+		wantChange   int
+		wantPatchSet int
+		wantErr      string
+	}{
+		{change: "1234", wantChange: 1234},
+		{change: "1234/2", wantChange: 1234, wantPatchSet: 2},
+		{change: "abc", wantErr: "invalid Gerrit change 'abc'"},
+		{change: "-1", wantErr: "invalid Gerrit change '-1'"},
+		{change: "1234/meta", wantErr: "invalid Gerrit change '1234/meta': invalid patchset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.change, func(t *testing.T) {
+			g := NewWithT(t)
+
+			change, patchset, err := parseChange(tt.change)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(change).To(Equal(tt.wantChange))
+			g.Expect(patchset).To(Equal(tt.wantPatchSet))
+		})
+	}
+}
+
+func TestClone_change(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	base, err := commitFile(repo, "file", "base", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The patchsets are only reachable from the change refs.
+	g.Expect(createBranch(repo, "review")).To(Succeed())
+	ps1, err := commitFile(repo, "file", "patchset 1", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	ps2, err := commitFile(repo, "file", "patchset 2", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	for name, h := range map[string]plumbing.Hash{
+		ChangeRefName(1234, 1):       ps1,
+		ChangeRefName(1234, 2):       ps2,
+		"refs/changes/34/1234/meta":  base,
+		ChangeRefName(234, 1):        base,
+		"refs/changes/34/11234/1":    base,
+		"refs/changes/34/1234/10abc": base,
+	} {
+		g.Expect(repo.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), h))).To(Succeed())
+	}
+	wt, err := repo.Worktree()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(wt.Checkout(&extgogit.CheckoutOptions{Branch: plumbing.Master})).To(Succeed())
+	g.Expect(repo.Storer.RemoveReference(plumbing.NewBranchReferenceName("review"))).To(Succeed())
+
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+	patchSets, err := ggc.ListPatchSets(context.TODO(), path, 1234)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(patchSets).To(Equal([]git.Reference{
+		{Name: "refs/changes/34/1234/1", Hash: git.Hash(ps1.String())},
+		{Name: "refs/changes/34/1234/2", Hash: git.Hash(ps2.String())},
+	}))
+
+	tests := []struct {
+		name     string
+		strategy repository.CheckoutStrategy
+		wantHash plumbing.Hash
+		wantRef  string
+		wantErr  string
+	}{
+		{
+			name:     "latest patchset",
+			strategy: repository.CheckoutStrategy{Change: "1234"},
+			wantHash: ps2,
+			wantRef:  "refs/changes/34/1234/2",
+		},
+		{
+			name:     "patchset",
+			strategy: repository.CheckoutStrategy{Change: "1234/1"},
+			wantHash: ps1,
+			wantRef:  "refs/changes/34/1234/1",
+		},
+		{
+			name:     "change ref name",
+			strategy: repository.CheckoutStrategy{RefName: "refs/changes/34/1234/1"},
+			wantHash: ps1,
+			wantRef:  "refs/changes/34/1234/1",
+		},
+		{
+			name:     "missing patchset",
+			strategy: repository.CheckoutStrategy{Change: "1234/3"},
+			wantErr:  "unable to find patchset '3' of Gerrit change '1234'",
+		},
+		{
+			name:     "missing change",
+			strategy: repository.CheckoutStrategy{Change: "4321"},
+			wantErr:  "unable to find Gerrit change '4321'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+			g.Expect(err).ToNot(HaveOccurred())
+			cc, err := ggc.Clone(context.TODO(), path, repository.CloneConfig{
+				CheckoutStrategy: tt.strategy,
+				ShallowClone:     true,
+			})
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cc.Hash.String()).To(Equal(tt.wantHash.String()))
+			g.Expect(cc.Reference).To(Equal(tt.wantRef))
+			g.Expect(git.IsConcreteCommit(*cc)).To(BeTrue())
+			head, err := ggc.Head()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(head).To(Equal(tt.wantHash.String()))
+
+			// Skip the clone if the patchset is the last observed one.
+			cc, err = ggc.Clone(context.TODO(), path, repository.CloneConfig{
+				CheckoutStrategy:   tt.strategy,
+				LastObservedCommit: cc.AbsoluteReference(),
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(git.IsConcreteCommit(*cc)).To(BeFalse())
+		})
+	}
+}
+
+func TestClient_Note(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	first, err := commitFile(repo, "file", "first", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	second, err := commitFile(repo, "file", "second", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	third, err := commitFile(repo, "file", "third", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The note of the second commit is fanned out, as written by git when
+	// there are many notes.
+	firstNote := writeNoteBlob(t, repo, "Code-Review+2\n")
+	secondNote := writeNoteBlob(t, repo, "Verified+1\n")
+	s := second.String()
+	fanout := writeTree(t, repo, []object.TreeEntry{
+		{Name: s[2:], Mode: filemode.Regular, Hash: secondNote},
+	})
+	root := writeTree(t, repo, []object.TreeEntry{
+		{Name: first.String(), Mode: filemode.Regular, Hash: firstNote},
+		{Name: s[:2], Mode: filemode.Dir, Hash: fanout},
+	})
+	notes := &object.Commit{
+		Author:    *mockSignature(time.Now()),
+		Committer: *mockSignature(time.Now()),
+		Message:   "Update notes",
+		TreeHash:  root,
+	}
+	obj := repo.Storer.NewEncodedObject()
+	g.Expect(notes.Encode(obj)).To(Succeed())
+	notesHash, err := repo.Storer.SetEncodedObject(obj)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(repo.Storer.SetReference(plumbing.NewHashReference("refs/notes/review", notesHash))).To(Succeed())
+
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Clone(context.TODO(), path, repository.CloneConfig{})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = ggc.Note("refs/notes/review", first.String())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(ggc.FetchNotes(context.TODO(), "refs/heads/master")).To(MatchError("invalid notes ref 'refs/heads/master'"))
+	g.Expect(ggc.FetchNotes(context.TODO(), "refs/notes/review")).To(Succeed())
+
+	note, err := ggc.Note("refs/notes/review", first.String())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(note).To(Equal("Code-Review+2\n"))
+	note, err = ggc.Note("refs/notes/review", second.String())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(note).To(Equal("Verified+1\n"))
+	note, err = ggc.Note("refs/notes/review", third.String())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(note).To(BeEmpty())
+}
+
+func writeNoteBlob(t *testing.T, repo *extgogit.Repository, content string) plumbing.Hash {
+	t.Helper()
+	obj := repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	w, err := obj.Writer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	h, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func writeTree(t *testing.T, repo *extgogit.Repository, entries []object.TreeEntry) plumbing.Hash {
+	t.Helper()
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	obj := repo.Storer.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+	h, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
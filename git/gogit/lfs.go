@@ -0,0 +1,435 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/util"
+	formatcfg "github.com/go-git/go-git/v5/plumbing/format/config"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const (
+	// lfsPointerVersion is the version line of the Git LFS pointer files.
+	lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+	// lfsPointerMaxSize is the maximum size of a Git LFS pointer file.
+	lfsPointerMaxSize = 1024
+	// lfsMediaType is the media type of the Git LFS batch API.
+	lfsMediaType = "application/vnd.git-lfs+json"
+	// lfsBatchSize is the maximum number of objects requested in a single
+	// call to the Git LFS batch API.
+	lfsBatchSize = 100
+	// lfsConfigFile is the file configuring Git LFS in the repository.
+	lfsConfigFile = ".lfsconfig"
+)
+
+// DefaultLFSMaxObjectSize is the default maximum size of a Git LFS object
+// downloaded by the client.
+const DefaultLFSMaxObjectSize int64 = 1 << 30
+
+// WithLFS configures the client to replace the Git LFS pointer files of the
+// worktree with the contents of the objects after a clone. The objects are
+// downloaded over the Git LFS batch API with the auth options of the client.
+// The LFS server is the `lfs.url` of the .lfsconfig file of the repository
+// if set, or the server of the HTTP(S) remote otherwise. As the .lfsconfig
+// file is controlled by the repository, the credentials are only sent to
+// an LFS server with the same scheme and host as the remote.
+//
+// The objects larger than DefaultLFSMaxObjectSize, or the size set with
+// WithLFSMaxObjectSize, are not downloaded and fail the clone.
+//
+// Note that the files replaced by their contents are reported as modified
+// by the status of the worktree.
+func WithLFS() ClientOption {
+	return func(c *Client) error {
+		c.lfs = true
+		return nil
+	}
+}
+
+// WithLFSMaxObjectSize sets the maximum size of a Git LFS object downloaded
+// by the client, as declared by its pointer file.
+func WithLFSMaxObjectSize(size int64) ClientOption {
+	return func(c *Client) error {
+		if size < 1 {
+			return fmt.Errorf("invalid LFS object maximum size %d: must be at least 1", size)
+		}
+		c.lfsMaxObjectSize = size
+		return nil
+	}
+}
+
+// lfsPointer is a Git LFS pointer file.
+type lfsPointer struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+// lfsBatchRequest is a request to the Git LFS batch API.
+type lfsBatchRequest struct {
+	Operation string       `json:"operation"`
+	Transfers []string     `json:"transfers"`
+	Objects   []lfsPointer `json:"objects"`
+}
+
+// lfsBatchResponse is a response of the Git LFS batch API.
+type lfsBatchResponse struct {
+	Objects []struct {
+		lfsPointer
+		Actions struct {
+			Download *struct {
+				Href   string            `json:"href"`
+				Header map[string]string `json:"header"`
+			} `json:"download"`
+		} `json:"actions"`
+		Error *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"objects"`
+}
+
+// parseLFSPointer parses the Git LFS pointer file content, and returns nil
+// if the content is not a pointer.
+func parseLFSPointer(data []byte) *lfsPointer {
+	if len(data) > lfsPointerMaxSize || !bytes.HasPrefix(data, []byte(lfsPointerVersion+"\n")) {
+		return nil
+	}
+	var p lfsPointer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, _ := strings.Cut(scanner.Text(), " ")
+		switch key {
+		case "oid":
+			p.OID, _ = strings.CutPrefix(value, "sha256:")
+		case "size":
+			p.Size, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	if len(p.OID) != sha256.Size*2 || p.Size < 0 {
+		return nil
+	}
+	if _, err := hex.DecodeString(p.OID); err != nil {
+		return nil
+	}
+	return &p
+}
+
+// resolveLFSPointers replaces the Git LFS pointer files of the worktree with
+// the contents of the objects downloaded from the LFS server of the
+// repository at the given url.
+func (g *Client) resolveLFSPointers(ctx context.Context, url string) error {
+	pointers := make(map[lfsPointer][]string)
+	var objects []lfsPointer
+	err := util.Walk(g.worktreeFS, "", func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || info.Size() > lfsPointerMaxSize {
+			return nil
+		}
+		data, err := util.ReadFile(g.worktreeFS, path)
+		if err != nil {
+			return err
+		}
+		if p := parseLFSPointer(data); p != nil {
+			if _, ok := pointers[*p]; !ok {
+				objects = append(objects, *p)
+			}
+			pointers[*p] = append(pointers[*p], path)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("unable to find LFS pointers: %w", err)
+	}
+	if len(objects) == 0 {
+		return nil
+	}
+	for _, obj := range objects {
+		if obj.Size > g.lfsMaxObjectSize {
+			return fmt.Errorf("LFS object '%s' of %d bytes exceeds the maximum size of %d bytes",
+				obj.OID, obj.Size, g.lfsMaxObjectSize)
+		}
+	}
+
+	endpoint, err := g.lfsEndpoint(url)
+	if err != nil {
+		return err
+	}
+	// The credentials are only sent to the server of the remote, and not to
+	// a server set by the repository in its .lfsconfig file.
+	auth := sameOrigin(url, endpoint)
+	client, err := g.lfsHTTPClient()
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(objects); i += lfsBatchSize {
+		batch := objects[i:min(i+lfsBatchSize, len(objects))]
+		resp, err := g.lfsBatch(ctx, client, endpoint, auth, batch)
+		if err != nil {
+			return err
+		}
+		for _, obj := range resp.Objects {
+			if obj.Error != nil {
+				return fmt.Errorf("unable to download LFS object '%s': %s (%d)", obj.OID, obj.Error.Message, obj.Error.Code)
+			}
+			paths, ok := pointers[obj.lfsPointer]
+			if !ok {
+				continue
+			}
+			if obj.Actions.Download == nil {
+				return fmt.Errorf("unable to download LFS object '%s': no download action", obj.OID)
+			}
+			if err := g.lfsDownload(ctx, client, endpoint, auth, obj.lfsPointer,
+				obj.Actions.Download.Href, obj.Actions.Download.Header, paths); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lfsEndpoint returns the URL of the Git LFS server of the repository.
+func (g *Client) lfsEndpoint(url string) (string, error) {
+	if f, err := g.worktreeFS.Open(lfsConfigFile); err == nil {
+		defer f.Close()
+		cfg := formatcfg.New()
+		if err := formatcfg.NewDecoder(f).Decode(cfg); err != nil {
+			return "", fmt.Errorf("unable to parse %s: %w", lfsConfigFile, err)
+		}
+		if lfsURL := cfg.Section("lfs").Option("url"); lfsURL != "" {
+			return strings.TrimSuffix(lfsURL, "/"), nil
+		}
+	}
+
+	u, err := neturl.Parse(url)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("unable to determine the LFS server of '%s': only HTTP(S) remotes are supported", url)
+	}
+	endpoint := strings.TrimSuffix(url, "/")
+	if !strings.HasSuffix(endpoint, ".git") {
+		endpoint += ".git"
+	}
+	return endpoint + "/info/lfs", nil
+}
+
+// sameOrigin returns true if the given URLs are HTTP(S) URLs with the same
+// scheme and host.
+func sameOrigin(a, b string) bool {
+	u, err := neturl.Parse(a)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	v, err := neturl.Parse(b)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == v.Scheme && u.Host == v.Host
+}
+
+// lfsHTTPClient returns an HTTP client with the CA bundle and the proxy of
+// the client.
+func (g *Client) lfsHTTPClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if ca := caBundle(g.authOpts); len(ca) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("unable to append the CA bundle to the certificate pool")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	if g.proxy.URL != "" || g.proxyConfig != nil {
+		transport.Proxy = func(req *http.Request) (*neturl.URL, error) {
+			opts := g.proxyOptions(req.URL.String())
+			if opts.URL == "" {
+				return nil, nil
+			}
+			proxyURL, err := opts.FullURL()
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse the proxy URL: %w", err)
+			}
+			return proxyURL, nil
+		}
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// setLFSAuth sets the credentials of the auth options of the client on the
+// request. Like for the clones, the credentials are only sent over HTTP with
+// WithInsecureCredentialsOverHTTP.
+func (g *Client) setLFSAuth(req *http.Request) error {
+	authMethod, err := transportAuth(g.authOpts, false)
+	if err != nil {
+		return fmt.Errorf("unable to construct auth method with options: %w", err)
+	}
+	am, ok := authMethod.(githttp.AuthMethod)
+	if !ok {
+		return nil
+	}
+	if req.URL.Scheme == "http" && !g.credentialsOverHTTP {
+		return errors.New("unable to send credentials to the LFS server over HTTP")
+	}
+	am.SetAuth(req)
+	return nil
+}
+
+// lfsBatch requests the download actions of the objects to the Git LFS
+// batch API, with the credentials of the client if auth is true.
+func (g *Client) lfsBatch(ctx context.Context, client *http.Client, endpoint string, auth bool,
+	objects []lfsPointer) (*lfsBatchResponse, error) {
+	body, err := json.Marshal(lfsBatchRequest{
+		Operation: "download",
+		Transfers: []string{"basic"},
+		Objects:   objects,
+	})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/objects/batch", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	if auth {
+		if err := g.setLFSAuth(req); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("LFS batch request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("LFS batch request failed with status code %d", resp.StatusCode)
+	}
+	var batch lfsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batch); err != nil {
+		return nil, fmt.Errorf("unable to decode LFS batch response: %w", err)
+	}
+	return &batch, nil
+}
+
+// lfsDownload downloads the object from the given href, and writes its
+// contents to the given paths of the worktree. If auth is true, the
+// credentials of the client are sent to the LFS server, but not to a
+// separate storage.
+func (g *Client) lfsDownload(ctx context.Context, client *http.Client, endpoint string, auth bool, obj lfsPointer,
+	href string, header map[string]string, paths []string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, href, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	if auth && sameOrigin(endpoint, href) && req.Header.Get("Authorization") == "" {
+		if err := g.setLFSAuth(req); err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to download LFS object '%s': %w", obj.OID, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unable to download LFS object '%s': status code %d", obj.OID, resp.StatusCode)
+	}
+
+	// Download to a temporary file, which replaces the pointer once the
+	// contents are verified.
+	tmp, err := util.TempFile(g.worktreeFS, filepath.Dir(paths[0]), ".lfs-")
+	if err != nil {
+		return err
+	}
+	defer g.worktreeFS.Remove(tmp.Name())
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(resp.Body, obj.Size+1))
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("unable to download LFS object '%s': %w", obj.OID, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if n != obj.Size || hex.EncodeToString(h.Sum(nil)) != obj.OID {
+		return fmt.Errorf("LFS object '%s' does not match its size or checksum", obj.OID)
+	}
+
+	for _, path := range paths {
+		if err := g.replaceLFSPointer(tmp.Name(), path); err != nil {
+			return fmt.Errorf("unable to replace LFS pointer '%s': %w", path, err)
+		}
+	}
+	return nil
+}
+
+// replaceLFSPointer replaces the pointer file at path with a copy of the
+// object contents at src, preserving its mode.
+func (g *Client) replaceLFSPointer(src, path string) error {
+	info, err := g.worktreeFS.Lstat(path)
+	if err != nil {
+		return err
+	}
+	in, err := g.worktreeFS.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := g.worktreeFS.OpenFile(path, os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,321 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/gitkit"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestClone_WithLFS(t *testing.T) {
+	objects := map[string]string{
+		"large.bin":     strings.Repeat("large file contents\n", 100),
+		"dir/large.txt": "another large file\n",
+	}
+	oids := make(map[string]string)
+	pointers := make(map[string]string)
+	for path, content := range objects {
+		sum := sha256.Sum256([]byte(content))
+		oid := hex.EncodeToString(sum[:])
+		oids[oid] = content
+		pointers[path] = fmt.Sprintf("%s\noid sha256:%s\nsize %d\n", lfsPointerVersion, oid, len(content))
+	}
+
+	tests := []struct {
+		name          string
+		corrupt       bool
+		username      string
+		password      string
+		withLFS       bool
+		otherLFSHost  bool
+		maxObjectSize int64
+		expectedErr   string
+	}{
+		{
+			name:     "resolves LFS pointers",
+			username: "user",
+			password: "pass",
+			withLFS:  true,
+		},
+		{
+			name:    "keeps LFS pointers without WithLFS",
+			withLFS: false,
+		},
+		{
+			name:        "fails on unauthorized",
+			username:    "user",
+			password:    "wrong",
+			withLFS:     true,
+			expectedErr: "LFS batch request failed with status code 401",
+		},
+		{
+			name:        "fails on checksum mismatch",
+			username:    "user",
+			password:    "pass",
+			corrupt:     true,
+			withLFS:     true,
+			expectedErr: "does not match its size or checksum",
+		},
+		{
+			name:         "does not send credentials to another LFS server",
+			username:     "user",
+			password:     "pass",
+			withLFS:      true,
+			otherLFSHost: true,
+			expectedErr:  "LFS batch request failed with status code 401",
+		},
+		{
+			name:          "fails on objects exceeding the maximum size",
+			username:      "user",
+			password:      "pass",
+			withLFS:       true,
+			maxObjectSize: 100,
+			expectedErr:   "exceeds the maximum size of 100 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			// The repository is served over HTTP by the same server as the
+			// LFS API, unless the .lfsconfig file points to another server.
+			root := t.TempDir()
+			repo, _, err := initRepo(filepath.Join(root, "repo.git"))
+			g.Expect(err).ToNot(HaveOccurred())
+			gitService := gitkit.New(gitkit.Config{Dir: root})
+			g.Expect(gitService.Setup()).To(Succeed())
+
+			var lfsRequests, authorizedRequests atomic.Int32
+			lfsHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				lfsRequests.Add(1)
+				u, p, ok := r.BasicAuth()
+				if ok {
+					authorizedRequests.Add(1)
+				}
+				if !ok || u != "user" || p != "pass" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/repo.git/info/lfs/objects/batch":
+					var req lfsBatchRequest
+					if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Operation != "download" {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					var resp struct {
+						Objects []map[string]any `json:"objects"`
+					}
+					for _, obj := range req.Objects {
+						resp.Objects = append(resp.Objects, map[string]any{
+							"oid":  obj.OID,
+							"size": obj.Size,
+							"actions": map[string]any{
+								"download": map[string]any{
+									"href": "http://" + r.Host + "/objects/" + obj.OID,
+								},
+							},
+						})
+					}
+					w.Header().Set("Content-Type", lfsMediaType)
+					_ = json.NewEncoder(w).Encode(resp)
+				case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/objects/"):
+					content, ok := oids[strings.TrimPrefix(r.URL.Path, "/objects/")]
+					if !ok {
+						w.WriteHeader(http.StatusNotFound)
+						return
+					}
+					if tt.corrupt {
+						content = strings.ToUpper(content)
+					}
+					_, _ = w.Write([]byte(content))
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasPrefix(r.URL.Path, "/repo.git/info/lfs/") || strings.HasPrefix(r.URL.Path, "/objects/") {
+					lfsHandler.ServeHTTP(w, r)
+					return
+				}
+				gitService.ServeHTTP(w, r)
+			}))
+			defer server.Close()
+			lfsURL := server.URL
+			if tt.otherLFSHost {
+				other := httptest.NewServer(lfsHandler)
+				defer other.Close()
+				lfsURL = other.URL
+			}
+
+			files := map[string]string{
+				"README.md":   "not an LFS pointer",
+				lfsConfigFile: fmt.Sprintf("[lfs]\n\turl = %s/repo.git/info/lfs\n", lfsURL),
+			}
+			for path, pointer := range pointers {
+				files[path] = pointer
+			}
+			_, err = commitFiles(repo, files, time.Now())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			opts := []ClientOption{WithDiskStorage(), WithInsecureCredentialsOverHTTP()}
+			if tt.withLFS {
+				opts = append(opts, WithLFS())
+			}
+			if tt.maxObjectSize > 0 {
+				opts = append(opts, WithLFSMaxObjectSize(tt.maxObjectSize))
+			}
+			tmpDir := t.TempDir()
+			ggc, err := NewClient(tmpDir, &git.AuthOptions{
+				Transport: git.HTTP,
+				Username:  tt.username,
+				Password:  tt.password,
+			}, opts...)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			_, err = ggc.Clone(context.TODO(), server.URL+"/repo.git", repository.CloneConfig{
+				CheckoutStrategy: repository.CheckoutStrategy{Branch: "master"},
+			})
+			if tt.otherLFSHost {
+				g.Expect(authorizedRequests.Load()).To(BeZero())
+			}
+			if tt.maxObjectSize > 0 {
+				g.Expect(lfsRequests.Load()).To(BeZero())
+			}
+			if tt.expectedErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(os.ReadFile(filepath.Join(tmpDir, "README.md"))).To(BeEquivalentTo("not an LFS pointer"))
+			for path, content := range objects {
+				if !tt.withLFS {
+					content = pointers[path]
+				}
+				g.Expect(os.ReadFile(filepath.Join(tmpDir, path))).To(BeEquivalentTo(content))
+			}
+			entries, err := os.ReadDir(tmpDir)
+			g.Expect(err).ToNot(HaveOccurred())
+			for _, e := range entries {
+				g.Expect(e.Name()).ToNot(HavePrefix(".lfs-"))
+			}
+		})
+	}
+}
+
+func Test_parseLFSPointer(t *testing.T) {
+	oid := strings.Repeat("ab", sha256.Size)
+	tests := []struct {
+		name    string
+		data    string
+		pointer *lfsPointer
+	}{
+		{
+			name:    "pointer",
+			data:    lfsPointerVersion + "\noid sha256:" + oid + "\nsize 12\n",
+			pointer: &lfsPointer{OID: oid, Size: 12},
+		},
+		{
+			name: "invalid oid",
+			data: lfsPointerVersion + "\noid sha256:1234\nsize 12\n",
+		},
+		{
+			name: "negative size",
+			data: lfsPointerVersion + "\noid sha256:" + oid + "\nsize -1\n",
+		},
+		{
+			name: "no version",
+			data: "oid sha256:" + oid + "\nsize 12\n",
+		},
+		{
+			name: "regular file",
+			data: "hello world",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(parseLFSPointer([]byte(tt.data))).To(Equal(tt.pointer))
+		})
+	}
+}
+
+func Test_lfsEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	ggc, err := NewClient(t.TempDir(), nil, WithMemoryStorage())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	endpoint, err := ggc.lfsEndpoint("https://example.com/org/repo")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(endpoint).To(Equal("https://example.com/org/repo.git/info/lfs"))
+
+	endpoint, err = ggc.lfsEndpoint("https://example.com/org/repo.git/")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(endpoint).To(Equal("https://example.com/org/repo.git/info/lfs"))
+
+	_, err = ggc.lfsEndpoint("ssh://git@example.com/org/repo")
+	g.Expect(err).To(MatchError(ContainSubstring("only HTTP(S) remotes are supported")))
+}
+
+func Test_sameOrigin(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(sameOrigin("https://example.com/org/repo", "https://example.com/org/repo.git/info/lfs")).To(BeTrue())
+	g.Expect(sameOrigin("https://example.com/org/repo", "https://lfs.example.com/org/repo.git/info/lfs")).To(BeFalse())
+	g.Expect(sameOrigin("https://example.com/org/repo", "http://example.com/org/repo.git/info/lfs")).To(BeFalse())
+	g.Expect(sameOrigin("https://example.com/org/repo", "https://example.com:8443/org/repo.git/info/lfs")).To(BeFalse())
+	g.Expect(sameOrigin("ssh://git@example.com/org/repo", "https://example.com/org/repo.git/info/lfs")).To(BeFalse())
+}
+
+func Test_setLFSAuth(t *testing.T) {
+	g := NewWithT(t)
+
+	authOpts := &git.AuthOptions{Transport: git.HTTPS, Username: "user", Password: "pass"}
+	ggc, err := NewClient(t.TempDir(), authOpts, WithMemoryStorage())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/info/lfs", nil)
+	g.Expect(ggc.setLFSAuth(req)).To(Succeed())
+	u, p, ok := req.BasicAuth()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(u).To(Equal("user"))
+	g.Expect(p).To(Equal("pass"))
+
+	req = httptest.NewRequest(http.MethodGet, "http://example.com/info/lfs", nil)
+	g.Expect(ggc.setLFSAuth(req)).To(MatchError("unable to send credentials to the LFS server over HTTP"))
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"crypto"
+	"fmt"
+
+	extgogit "github.com/go-git/go-git/v5"
+	formatcfg "github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/hash"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// ObjectFormat returns the object format, i.e. the hash algorithm, of the
+// repositories supported by the client. go-git supports a single object
+// format per build: git.HashTypeSHA1 by default, and git.HashTypeSHA256
+// when built with the "sha256" build tag.
+func ObjectFormat() string {
+	if hash.CryptoType == crypto.SHA256 {
+		return git.HashTypeSHA256
+	}
+	return git.HashTypeSHA1
+}
+
+// WithObjectFormat configures the object format of the repositories
+// initialized by the client, either git.HashTypeSHA1 or git.HashTypeSHA256.
+// It must match the ObjectFormat of the build.
+func WithObjectFormat(format string) ClientOption {
+	return func(c *Client) error {
+		switch format {
+		case git.HashTypeSHA1, git.HashTypeSHA256:
+		default:
+			return fmt.Errorf("unsupported object format '%s'", format)
+		}
+		if format != ObjectFormat() {
+			err := fmt.Errorf("object format '%s' is not supported by this build, which supports '%s'", format, ObjectFormat())
+			if format == git.HashTypeSHA256 {
+				err = fmt.Errorf("%w: %w", err, extgogit.ErrSHA256NotSupported)
+			}
+			return err
+		}
+		c.objectFormat = format
+		return nil
+	}
+}
+
+// setObjectFormat records the object format of the client in the
+// configuration of a newly initialized repository. The SHA1 object format
+// is the default and is not recorded.
+func (g *Client) setObjectFormat(r *extgogit.Repository) error {
+	if g.objectFormat != git.HashTypeSHA256 {
+		return nil
+	}
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	cfg.Core.RepositoryFormatVersion = formatcfg.Version_1
+	cfg.Extensions.ObjectFormat = formatcfg.SHA256
+	return r.Storer.SetConfig(cfg)
+}
+
+// validateCommitHash returns an error if the given commit is a full hash
+// of another object format than the one of the build, as it could not be
+// resolved.
+func validateCommitHash(commit string) error {
+	if algo := git.Hash(commit).Algorithm(); algo != git.HashTypeUnknown && algo != ObjectFormat() {
+		return fmt.Errorf("commit '%s' is a %s hash, but the %s object format is supported", commit, algo, ObjectFormat())
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"testing"
+
+	extgogit "github.com/go-git/go-git/v5"
+	formatcfg "github.com/go-git/go-git/v5/plumbing/format/config"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+const (
+	sha1Commit   = "5394cb7f48332b2de7c17dd8b8384bbc84b7e738"
+	sha256Commit = "6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8"
+)
+
+func TestWithObjectFormat(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewClient(t.TempDir(), nil, WithDiskStorage(), WithObjectFormat("md5"))
+	g.Expect(err).To(MatchError("unsupported object format 'md5'"))
+
+	ggc, err := NewClient(t.TempDir(), nil, WithDiskStorage(), WithObjectFormat(ObjectFormat()))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ggc.objectFormat).To(Equal(ObjectFormat()))
+
+	if ObjectFormat() == git.HashTypeSHA1 {
+		_, err = NewClient(t.TempDir(), nil, WithDiskStorage(), WithObjectFormat(git.HashTypeSHA256))
+		g.Expect(err).To(MatchError(extgogit.ErrSHA256NotSupported))
+	}
+}
+
+func TestClient_Init_ObjectFormat(t *testing.T) {
+	if ObjectFormat() != git.HashTypeSHA256 {
+		t.Skip("requires the sha256 build tag")
+	}
+	g := NewWithT(t)
+
+	ggc, err := NewClient(t.TempDir(), nil, WithDiskStorage(), WithObjectFormat(git.HashTypeSHA256))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ggc.Init(context.TODO(), "https://example.com/repo.git", "main")).To(Succeed())
+
+	cfg, err := ggc.repository.Config()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cfg.Raw.Section("extensions").Option("objectformat")).To(Equal(string(formatcfg.SHA256)))
+}
+
+func Test_validateCommitHash(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(validateCommitHash("a-random-invalid-commit")).To(Succeed())
+
+	supported, unsupported := sha1Commit, sha256Commit
+	if ObjectFormat() == git.HashTypeSHA256 {
+		supported, unsupported = sha256Commit, sha1Commit
+	}
+	g.Expect(validateCommitHash(supported)).To(Succeed())
+	g.Expect(validateCommitHash(unsupported)).To(MatchError(ContainSubstring("object format is supported")))
+}
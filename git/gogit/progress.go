@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bytes"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/sideband"
+)
+
+const (
+	// ProgressOperationClone is the operation of the progress of a clone.
+	ProgressOperationClone = "clone"
+	// ProgressOperationFetch is the operation of the progress of a fetch,
+	// e.g. of the notes or to deepen a shallow clone.
+	ProgressOperationFetch = "fetch"
+	// ProgressOperationPush is the operation of the progress of a push.
+	ProgressOperationPush = "push"
+)
+
+// Progress is the progress of a phase of a remote operation, as reported by
+// the Git server.
+type Progress struct {
+	// Operation is the operation in progress, e.g. ProgressOperationClone.
+	Operation string
+	// Phase is the phase of the operation, e.g. "Counting objects" or
+	// "Compressing objects". It is empty for the messages of the server
+	// which don't report progress.
+	Phase string
+	// Current is the number of objects processed in the phase.
+	Current int64
+	// Total is the total number of objects of the phase, or zero if
+	// unknown.
+	Total int64
+	// Bytes is the number of bytes transferred in the phase, or zero if not
+	// reported.
+	Bytes int64
+	// Done is true if the phase is complete.
+	Done bool
+	// Message is the message of the server the progress was parsed from.
+	Message string
+}
+
+// ProgressFunc is called with the progress of the remote operations.
+type ProgressFunc func(Progress)
+
+// WithProgress configures the client to write the progress messages of the
+// Git server to w during the remote operations, like the git CLI does.
+func WithProgress(w io.Writer) ClientOption {
+	return func(c *Client) error {
+		c.progressWriter = w
+		return nil
+	}
+}
+
+// WithProgressFunc configures the client to call fn with the progress of
+// the remote operations, parsed from the progress messages of the Git
+// server, e.g. to emit events or metrics about long-running operations.
+func WithProgressFunc(fn ProgressFunc) ClientOption {
+	return func(c *Client) error {
+		c.progressFunc = fn
+		return nil
+	}
+}
+
+// progress returns the sideband progress writer of the given operation, or
+// nil if no progress is configured.
+func (g *Client) progress(operation string) sideband.Progress {
+	if g.progressWriter == nil && g.progressFunc == nil {
+		return nil
+	}
+	return &progressWriter{
+		operation: operation,
+		w:         g.progressWriter,
+		fn:        g.progressFunc,
+	}
+}
+
+// progressWriter writes the progress messages to the writer, and calls the
+// function with each line of the messages. The lines are terminated by a
+// carriage return when updating the progress of a phase.
+type progressWriter struct {
+	operation string
+	w         io.Writer
+	fn        ProgressFunc
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	if p.w != nil {
+		if _, err := p.w.Write(b); err != nil {
+			return 0, err
+		}
+	}
+	if p.fn == nil {
+		return len(b), nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.buf = append(p.buf, b...)
+	for {
+		i := bytes.IndexAny(p.buf, "\r\n")
+		if i < 0 {
+			break
+		}
+		line := strings.TrimSpace(string(p.buf[:i]))
+		p.buf = p.buf[i+1:]
+		if line != "" {
+			p.fn(parseProgress(p.operation, line))
+		}
+	}
+	return len(b), nil
+}
+
+var (
+	progressPercentRegexp = regexp.MustCompile(`^\d+%\s+\((\d+)/(\d+)\)`)
+	progressCountRegexp   = regexp.MustCompile(`^(\d+)\b`)
+	progressBytesRegexp   = regexp.MustCompile(`(\d+(?:\.\d+)?) (bytes|KiB|MiB|GiB)`)
+	progressPhaseRegexp   = regexp.MustCompile(`^[A-Za-z][A-Za-z ]*$`)
+)
+
+// parseProgress parses a progress line of the Git server, e.g.
+// "Counting objects:  50% (5/10)" or "Receiving objects: 100% (10/10),
+// 1.50 MiB | 2.00 MiB/s, done.".
+func parseProgress(operation, line string) Progress {
+	p := Progress{Operation: operation, Message: line}
+	msg := strings.TrimPrefix(line, "remote: ")
+	phase, rest, ok := strings.Cut(msg, ":")
+	if !ok || !progressPhaseRegexp.MatchString(phase) {
+		return p
+	}
+	rest = strings.TrimSpace(rest)
+	if m := progressPercentRegexp.FindStringSubmatch(rest); m != nil {
+		p.Current, _ = strconv.ParseInt(m[1], 10, 64)
+		p.Total, _ = strconv.ParseInt(m[2], 10, 64)
+	} else if m := progressCountRegexp.FindStringSubmatch(rest); m != nil {
+		p.Current, _ = strconv.ParseInt(m[1], 10, 64)
+	} else {
+		return p
+	}
+	if m := progressBytesRegexp.FindStringSubmatch(rest); m != nil {
+		p.Bytes = parseProgressBytes(m[1], m[2])
+	}
+	p.Phase = phase
+	p.Done = strings.HasSuffix(rest, "done.")
+	return p
+}
+
+func parseProgressBytes(value, unit string) int64 {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0
+	}
+	switch unit {
+	case "KiB":
+		v *= 1 << 10
+	case "MiB":
+		v *= 1 << 20
+	case "GiB":
+		v *= 1 << 30
+	}
+	return int64(v)
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func Test_parseProgress(t *testing.T) {
+	tests := []struct {
+		line string
+		want Progress
+	}{
+		{
+			line: "Counting objects:  50% (5/10)",
+			want: Progress{Phase: "Counting objects", Current: 5, Total: 10},
+		},
+		{
+			line: "remote: Compressing objects: 100% (3/3), done.",
+			want: Progress{Phase: "Compressing objects", Current: 3, Total: 3, Done: true},
+		},
+		{
+			line: "Enumerating objects: 12, done.",
+			want: Progress{Phase: "Enumerating objects", Current: 12, Done: true},
+		},
+		{
+			line: "Receiving objects: 100% (10/10), 1.50 MiB | 2.00 MiB/s, done.",
+			want: Progress{Phase: "Receiving objects", Current: 10, Total: 10, Bytes: 1572864, Done: true},
+		},
+		{
+			line: "Total 12 (delta 2), reused 0 (delta 0), pack-reused 0",
+			want: Progress{},
+		},
+		{
+			line: "warning: unable to access 'foo': Permission denied",
+			want: Progress{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			g := NewWithT(t)
+
+			tt.want.Operation = ProgressOperationClone
+			tt.want.Message = tt.line
+			g.Expect(parseProgress(ProgressOperationClone, tt.line)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_progressWriter(t *testing.T) {
+	g := NewWithT(t)
+
+	var raw bytes.Buffer
+	var got []Progress
+	ggc, err := NewClient(t.TempDir(), nil, WithDiskStorage(), WithProgress(&raw), WithProgressFunc(func(p Progress) {
+		got = append(got, p)
+	}))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The progress lines may be split across writes.
+	w := ggc.progress(ProgressOperationPush)
+	for _, msg := range []string{"Counting obj", "ects:  50% (1/2)\rCounting objects: 100% (2/2)", ", done.\nTotal 2 (delta 0)\n"} {
+		n, err := w.Write([]byte(msg))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(n).To(Equal(len(msg)))
+	}
+	g.Expect(raw.String()).To(Equal("Counting objects:  50% (1/2)\rCounting objects: 100% (2/2), done.\nTotal 2 (delta 0)\n"))
+	g.Expect(got).To(HaveLen(3))
+	g.Expect(got[0]).To(Equal(Progress{
+		Operation: ProgressOperationPush, Phase: "Counting objects", Current: 1, Total: 2,
+		Message: "Counting objects:  50% (1/2)",
+	}))
+	g.Expect(got[1].Done).To(BeTrue())
+	g.Expect(got[2].Phase).To(BeEmpty())
+	g.Expect(got[2].Message).To(Equal("Total 2 (delta 0)"))
+
+	ggc, err = NewClient(t.TempDir(), nil, WithDiskStorage())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ggc.progress(ProgressOperationClone)).To(BeNil())
+}
+
+func TestClone_progress(t *testing.T) {
+	g := NewWithT(t)
+
+	server, repoURL, err := setupGitServer(false)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+	defer server.StopHTTP()
+
+	var phases []string
+	ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, WithDiskStorage(), WithProgressFunc(func(p Progress) {
+		g.Expect(p.Operation).To(Equal(ProgressOperationClone))
+		if p.Done {
+			phases = append(phases, p.Phase)
+		}
+	}))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Clone(context.TODO(), repoURL, repository.CloneConfig{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phases).To(ContainElement("Counting objects"))
+}
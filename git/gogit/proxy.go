@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"golang.org/x/net/proxy"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// directProxyURL is the URL of the proxy dialer connecting directly to the
+// SSH servers, which prevents go-git from selecting a proxy from the
+// ALL_PROXY and NO_PROXY environment variables with other semantics.
+const directProxyURL = "direct://"
+
+func init() {
+	// go-git dials the SSH servers through the dialers of x/net/proxy,
+	// which only supports SOCKS5 proxies by default.
+	proxy.RegisterDialerType("http", newConnectDialer)
+	proxy.RegisterDialerType("https", newConnectDialer)
+	proxy.RegisterDialerType("direct", func(_ *url.URL, forward proxy.Dialer) (proxy.Dialer, error) {
+		return proxy.Direct, nil
+	})
+}
+
+// WithProxyFromEnvironment configures the client to select the proxy of the
+// remote operations from the HTTP_PROXY, HTTPS_PROXY, ALL_PROXY and
+// NO_PROXY environment variables, with the semantics of the Go standard
+// library for both the HTTP and SSH transports, see git.ProxyConfig. The SSH
+// connections go through the HTTP(S) proxies with HTTP CONNECT. It is
+// ignored if a proxy is configured with WithProxy.
+func WithProxyFromEnvironment() ClientOption {
+	return func(c *Client) error {
+		c.proxyConfig = git.ProxyConfigFromEnvironment()
+		return nil
+	}
+}
+
+// WithNoProxy configures the hosts which are not proxied by the proxy
+// configured with WithProxy, as a comma-separated list with the syntax of
+// the NO_PROXY environment variable, see git.ProxyConfig.
+func WithNoProxy(noProxy string) ClientOption {
+	return func(c *Client) error {
+		c.noProxy = noProxy
+		return nil
+	}
+}
+
+// proxyOptions returns the proxy options of the remote operations on the
+// given repository URL.
+func (g *Client) proxyOptions(repoURL string) transport.ProxyOptions {
+	config := g.proxyConfig
+	if g.proxy.URL != "" {
+		if g.noProxy == "" {
+			return g.proxy
+		}
+		config = &git.ProxyConfig{
+			HTTPProxy:  g.proxy.URL,
+			HTTPSProxy: g.proxy.URL,
+			AllProxy:   g.proxy.URL,
+			NoProxy:    g.noProxy,
+		}
+	}
+	if config == nil {
+		return g.proxy
+	}
+
+	proxyURL, err := config.ProxyForURL(repoURL)
+	if err != nil {
+		// go-git reports the invalid URLs.
+		return g.proxy
+	}
+	if proxyURL == nil {
+		if isSSHURL(repoURL) {
+			return transport.ProxyOptions{URL: directProxyURL}
+		}
+		return transport.ProxyOptions{}
+	}
+	if g.proxy.URL != "" {
+		// Keep the credentials of the proxy.
+		return g.proxy
+	}
+	return transport.ProxyOptions{URL: proxyURL.String()}
+}
+
+// remoteURL returns the URL of the default remote of the repository, or an
+// empty string if it has none.
+func (g *Client) remoteURL() string {
+	remote, err := g.repository.Remote(extgogit.DefaultRemoteName)
+	if err != nil || len(remote.Config().URLs) == 0 {
+		return ""
+	}
+	return remote.Config().URLs[0]
+}
+
+// isSSHURL returns true if the URL is an ssh URL or an SCP-like address.
+func isSSHURL(repoURL string) bool {
+	return strings.HasPrefix(repoURL, "ssh://") || !strings.Contains(repoURL, "://")
+}
+
+// connectDialer dials through an HTTP(S) proxy with HTTP CONNECT.
+type connectDialer struct {
+	proxyURL *url.URL
+	forward  proxy.Dialer
+}
+
+func newConnectDialer(u *url.URL, forward proxy.Dialer) (proxy.Dialer, error) {
+	return &connectDialer{proxyURL: u, forward: forward}, nil
+}
+
+// Dial connects to the address through the proxy.
+func (d *connectDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+// DialContext connects to the address through the proxy.
+func (d *connectDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	proxyAddr := d.proxyURL.Host
+	if d.proxyURL.Port() == "" {
+		port := "80"
+		if d.proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(d.proxyURL.Hostname(), port)
+	}
+
+	var conn net.Conn
+	var err error
+	if cd, ok := d.forward.(proxy.ContextDialer); ok {
+		conn, err = cd.DialContext(ctx, network, proxyAddr)
+	} else {
+		conn, err = d.forward.Dial(network, proxyAddr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to proxy '%s': %w", proxyAddr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	if d.proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: d.proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("TLS handshake with proxy '%s' failed: %w", proxyAddr, err)
+		}
+		conn = tlsConn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u := d.proxyURL.User; u != nil {
+		password, _ := u.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to write CONNECT request to proxy '%s': %w", proxyAddr, err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("unable to read CONNECT response of proxy '%s': %w", proxyAddr, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy '%s' refused to connect to '%s': %s", proxyAddr, addr, resp.Status)
+	}
+	// The server may have sent data, e.g. the SSH banner, which has been
+	// buffered with the response.
+	return &bufferedConn{Conn: conn, r: br}, nil
+}
+
+// bufferedConn is a net.Conn reading from a buffered reader of the
+// connection.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
@@ -0,0 +1,205 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	. "github.com/onsi/gomega"
+	"golang.org/x/net/proxy"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+func TestClient_proxyOptions(t *testing.T) {
+	explicitProxy := transport.ProxyOptions{
+		URL:      "http://proxy:3128",
+		Username: "user",
+		Password: "pass",
+	}
+
+	tests := []struct {
+		name        string
+		proxy       transport.ProxyOptions
+		noProxy     string
+		proxyConfig *git.ProxyConfig
+		repoURL     string
+		want        transport.ProxyOptions
+	}{
+		{
+			name:    "no proxy",
+			repoURL: "https://github.com/org/repo",
+			want:    transport.ProxyOptions{},
+		},
+		{
+			name:    "explicit proxy",
+			proxy:   explicitProxy,
+			repoURL: "https://github.com/org/repo",
+			want:    explicitProxy,
+		},
+		{
+			name:    "explicit proxy and unmatched no proxy",
+			proxy:   explicitProxy,
+			noProxy: "example.com",
+			repoURL: "ssh://git@github.com/org/repo",
+			want:    explicitProxy,
+		},
+		{
+			name:    "explicit proxy and matched no proxy",
+			proxy:   explicitProxy,
+			noProxy: "example.com",
+			repoURL: "https://git.example.com/org/repo",
+			want:    transport.ProxyOptions{},
+		},
+		{
+			name:    "explicit proxy and matched no proxy of ssh URL",
+			proxy:   explicitProxy,
+			noProxy: "example.com:22",
+			repoURL: "git@git.example.com:org/repo",
+			want:    transport.ProxyOptions{URL: directProxyURL},
+		},
+		{
+			name:        "explicit proxy takes precedence over environment",
+			proxy:       explicitProxy,
+			proxyConfig: &git.ProxyConfig{HTTPSProxy: "http://env-proxy:3128"},
+			repoURL:     "https://github.com/org/repo",
+			want:        explicitProxy,
+		},
+		{
+			name:        "environment",
+			proxyConfig: &git.ProxyConfig{HTTPSProxy: "http://env-proxy:3128", NoProxy: "example.com"},
+			repoURL:     "ssh://git@github.com/org/repo",
+			want:        transport.ProxyOptions{URL: "http://env-proxy:3128"},
+		},
+		{
+			name:        "environment and matched no proxy",
+			proxyConfig: &git.ProxyConfig{HTTPSProxy: "http://env-proxy:3128", NoProxy: "example.com"},
+			repoURL:     "ssh://git@git.example.com/org/repo",
+			want:        transport.ProxyOptions{URL: directProxyURL},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &Client{proxy: tt.proxy, noProxy: tt.noProxy, proxyConfig: tt.proxyConfig}
+			g.Expect(c.proxyOptions(tt.repoURL)).To(Equal(tt.want))
+		})
+	}
+}
+
+func Test_connectDialer(t *testing.T) {
+	g := NewWithT(t)
+
+	// The server sends a banner before reading, like an SSH server, and then
+	// echoes what it reads.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer l.Close()
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_, _ = conn.Write([]byte("SSH-2.0-test\r\n"))
+				_, _ = io.Copy(conn, conn)
+			}()
+		}
+	}()
+
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if u, p, ok := parseProxyAuth(r); !ok || u != "user" || p != "pass" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			upstream.Close()
+			return
+		}
+		_, _ = conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		go func() {
+			defer upstream.Close()
+			_, _ = io.Copy(upstream, rw)
+		}()
+		defer conn.Close()
+		_, _ = io.Copy(conn, upstream)
+	}))
+	defer proxyServer.Close()
+
+	t.Run("connects through the proxy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		proxyURL, err := url.Parse(proxyServer.URL)
+		g.Expect(err).ToNot(HaveOccurred())
+		proxyURL.User = url.UserPassword("user", "pass")
+
+		dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+		g.Expect(err).ToNot(HaveOccurred())
+		conn, err := dialer.(proxy.ContextDialer).DialContext(context.Background(), "tcp", l.Addr().String())
+		g.Expect(err).ToNot(HaveOccurred())
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		banner, err := r.ReadString('\n')
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(banner).To(Equal("SSH-2.0-test\r\n"))
+
+		_, err = conn.Write([]byte("ping\n"))
+		g.Expect(err).ToNot(HaveOccurred())
+		echo, err := r.ReadString('\n')
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(echo).To(Equal("ping\n"))
+	})
+
+	t.Run("fails if the proxy refuses to connect", func(t *testing.T) {
+		g := NewWithT(t)
+
+		proxyURL, err := url.Parse(proxyServer.URL)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		dialer, err := proxy.FromURL(proxyURL, proxy.Direct)
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = dialer.Dial("tcp", l.Addr().String())
+		g.Expect(err).To(MatchError(ContainSubstring("407 Proxy Authentication Required")))
+	})
+}
+
+func parseProxyAuth(r *http.Request) (string, string, bool) {
+	req := &http.Request{Header: http.Header{"Authorization": r.Header["Proxy-Authorization"]}}
+	return req.BasicAuth()
+}
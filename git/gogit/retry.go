@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const (
+	defaultRetryMaxRetries      = 3
+	defaultRetryInitialInterval = time.Second
+	defaultRetryMaxInterval     = 30 * time.Second
+)
+
+// RetryOptions is the policy of the retries of the remote operations failing
+// with transient errors, e.g. a connection reset or a 5xx status code
+// returned by the Git server.
+type RetryOptions struct {
+	// MaxRetries is the maximum number of retries of an operation.
+	// Defaults to 3.
+	MaxRetries int
+	// InitialInterval is the interval before the first retry, doubled on
+	// each retry. Defaults to 1s.
+	InitialInterval time.Duration
+	// MaxInterval is the maximum interval between two retries. Defaults
+	// to 30s.
+	MaxInterval time.Duration
+	// OnRetry is called before each retry of an operation with the number
+	// of the retry, starting at 1, and the error of the previous attempt,
+	// e.g. to record metrics.
+	OnRetry func(operation string, retry int, err error)
+}
+
+// WithRetry configures the client to retry Clone, Push and ListRemote when
+// they fail with transient errors, with exponential backoff and jitter.
+// The timeouts of the operations, and the deadline of the context, bound
+// the duration of all the attempts. Clone is only retried when the client
+// created its storage with WithDiskStorage or WithMemoryStorage, as the
+// storage is reset before each retry. Otherwise, the first error of a clone
+// is returned.
+func WithRetry(opts RetryOptions) ClientOption {
+	return func(c *Client) error {
+		if opts.MaxRetries < 0 {
+			return fmt.Errorf("invalid max retries '%d'", opts.MaxRetries)
+		}
+		if opts.InitialInterval < 0 || opts.MaxInterval < 0 {
+			return fmt.Errorf("invalid retry intervals '%s' and '%s'", opts.InitialInterval, opts.MaxInterval)
+		}
+		if opts.MaxRetries == 0 {
+			opts.MaxRetries = defaultRetryMaxRetries
+		}
+		if opts.InitialInterval == 0 {
+			opts.InitialInterval = defaultRetryInitialInterval
+		}
+		if opts.MaxInterval == 0 {
+			opts.MaxInterval = defaultRetryMaxInterval
+		}
+		c.retry = &opts
+		return nil
+	}
+}
+
+// Retries returns the number of retries of the operations of the client.
+func (g *Client) Retries() int {
+	return g.retries
+}
+
+// withRetry calls fn until it succeeds, fails with a permanent error, the
+// retries are exhausted or the context is done.
+func (g *Client) withRetry(ctx context.Context, operation string, fn func() error) error {
+	err := fn()
+	if g.retry == nil {
+		return err
+	}
+	interval := g.retry.InitialInterval
+	for retry := 1; retry <= g.retry.MaxRetries && err != nil && IsTransientError(err); retry++ {
+		// Equal jitter: wait between half and the whole interval.
+		wait := interval/2 + rand.N(interval/2+1)
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		interval = min(2*interval, g.retry.MaxInterval)
+
+		g.retries++
+		if g.retry.OnRetry != nil {
+			g.retry.OnRetry(operation, retry, err)
+		}
+		err = fn()
+	}
+	return err
+}
+
+// IsTransientError returns true if the error of a remote operation is
+// transient, i.e. a network error or a 408, 429 or 5xx status code
+// returned by the Git server, and the operation may succeed if retried.
+func IsTransientError(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	// go-git doesn't unwrap its unexpected errors.
+	var unexpectedErr *plumbing.UnexpectedError
+	if errors.As(err, &unexpectedErr) {
+		return IsTransientError(unexpectedErr.Err)
+	}
+	var httpErr *githttp.Err
+	if errors.As(err, &httpErr) {
+		code := httpErr.StatusCode()
+		return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) || errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	// The errors of the SSH transport are flattened into strings.
+	msg := err.Error()
+	for _, s := range []string{"connection reset by peer", "connection refused", "broken pipe",
+		"unexpected EOF", "i/o timeout", "TLS handshake timeout"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// canResetStorage returns true if the storage of the client can be reset
+// before retrying a clone, i.e. if the client created it on disk or in
+// memory. The storer and worktree filesystem given with WithStorer and
+// WithWorkTreeFS are owned by the caller, and are never reset.
+func (g *Client) canResetStorage() bool {
+	return g.ownStorage
+}
+
+// resetStorage removes the objects and the worktree of a failed clone.
+func (g *Client) resetStorage() error {
+	g.repository = nil
+	if !g.diskStorage {
+		return WithMemoryStorage()(g)
+	}
+
+	entries, err := os.ReadDir(g.path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(g.path, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return WithDiskStorage()(g)
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+// flakyServer proxies the requests to the Git server, after failing the
+// given number of requests with the status code.
+func flakyServer(t *testing.T, target string, failures int32, statusCode int) (string, *atomic.Int32) {
+	t.Helper()
+	u, err := url.Parse(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: u.Scheme, Host: u.Host})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(statusCode)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + u.Path, &requests
+}
+
+func TestClient_retry(t *testing.T) {
+	server, repoURL, err := setupGitServer(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.StopHTTP()
+
+	retryOpts := func(retries *[]string) RetryOptions {
+		return RetryOptions{
+			MaxRetries:      3,
+			InitialInterval: time.Millisecond,
+			OnRetry: func(operation string, retry int, err error) {
+				*retries = append(*retries, fmt.Sprintf("%s %d", operation, retry))
+			},
+		}
+	}
+	authOpts := &git.AuthOptions{Transport: git.HTTP}
+	cloneCfg := repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+	}
+
+	for _, storage := range []ClientOption{WithDiskStorage(), WithMemoryStorage()} {
+		t.Run("clone", func(t *testing.T) {
+			g := NewWithT(t)
+
+			url, _ := flakyServer(t, repoURL, 2, http.StatusServiceUnavailable)
+			var retries []string
+			ggc, err := NewClient(t.TempDir(), authOpts, storage, WithRetry(retryOpts(&retries)))
+			g.Expect(err).ToNot(HaveOccurred())
+			cc, err := ggc.Clone(context.TODO(), url, cloneCfg)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(git.IsConcreteCommit(*cc)).To(BeTrue())
+			g.Expect(retries).To(Equal([]string{"clone 1", "clone 2"}))
+			g.Expect(ggc.Retries()).To(Equal(2))
+		})
+	}
+
+	t.Run("ls-remote", func(t *testing.T) {
+		g := NewWithT(t)
+
+		url, _ := flakyServer(t, repoURL, 1, http.StatusBadGateway)
+		var retries []string
+		ggc, err := NewClient(t.TempDir(), authOpts, WithDiskStorage(), WithRetry(retryOpts(&retries)))
+		g.Expect(err).ToNot(HaveOccurred())
+		refs, err := ggc.ListRemote(context.TODO(), url)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(refs).ToNot(BeEmpty())
+		g.Expect(retries).To(Equal([]string{"ls-remote 1"}))
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		url, requests := flakyServer(t, repoURL, 10, http.StatusServiceUnavailable)
+		var retries []string
+		ggc, err := NewClient(t.TempDir(), authOpts, WithDiskStorage(), WithRetry(retryOpts(&retries)))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), url, cloneCfg)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(retries).To(HaveLen(3))
+		g.Expect(requests.Load()).To(Equal(int32(4)))
+	})
+
+	t.Run("permanent error", func(t *testing.T) {
+		g := NewWithT(t)
+
+		url, requests := flakyServer(t, repoURL, 10, http.StatusForbidden)
+		var retries []string
+		ggc, err := NewClient(t.TempDir(), authOpts, WithDiskStorage(), WithRetry(retryOpts(&retries)))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), url, cloneCfg)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(retries).To(BeEmpty())
+		g.Expect(requests.Load()).To(Equal(int32(1)))
+	})
+
+	t.Run("caller storage", func(t *testing.T) {
+		g := NewWithT(t)
+
+		url, requests := flakyServer(t, repoURL, 1, http.StatusServiceUnavailable)
+		var retries []string
+		storer := memory.NewStorage()
+		wt := memfs.New()
+		g.Expect(util.WriteFile(wt, "keep", []byte("caller file"), 0o600)).To(Succeed())
+		ggc, err := NewClient(t.TempDir(), authOpts, WithStorer(storer), WithWorkTreeFS(wt),
+			WithRetry(retryOpts(&retries)))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), url, cloneCfg)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(retries).To(BeEmpty())
+		g.Expect(requests.Load()).To(Equal(int32(1)))
+
+		// The storage of the caller is neither replaced nor emptied.
+		g.Expect(ggc.storer).To(BeIdenticalTo(storer))
+		g.Expect(ggc.worktreeFS).To(BeIdenticalTo(wt))
+		g.Expect(util.ReadFile(wt, "keep")).To(BeEquivalentTo("caller file"))
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		g := NewWithT(t)
+
+		url, requests := flakyServer(t, repoURL, 1, http.StatusServiceUnavailable)
+		ggc, err := NewClient(t.TempDir(), authOpts, WithDiskStorage())
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), url, cloneCfg)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(requests.Load()).To(Equal(int32(1)))
+	})
+}
+
+func TestWithRetry(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewClient(t.TempDir(), nil, WithRetry(RetryOptions{MaxRetries: -1}))
+	g.Expect(err).To(MatchError("invalid max retries '-1'"))
+
+	ggc, err := NewClient(t.TempDir(), nil, WithDiskStorage(), WithRetry(RetryOptions{}))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(*ggc.retry).To(Equal(RetryOptions{
+		MaxRetries:      3,
+		InitialInterval: time.Second,
+		MaxInterval:     30 * time.Second,
+	}))
+}
+
+func TestIsTransientError(t *testing.T) {
+	httpErr := func(code int) error {
+		return plumbing.NewUnexpectedError(&githttp.Err{Response: &http.Response{StatusCode: code}})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "503", err: httpErr(http.StatusServiceUnavailable), want: true},
+		{name: "429", err: httpErr(http.StatusTooManyRequests), want: true},
+		{name: "400", err: httpErr(http.StatusBadRequest), want: false},
+		{name: "wrapped 502", err: fmt.Errorf("unable to clone: %w", httpErr(http.StatusBadGateway)), want: true},
+		{name: "connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "ssh connection refused", err: errors.New("ssh: dial tcp 127.0.0.1:22: connect: connection refused"), want: true},
+		{name: "authentication", err: transport.ErrAuthenticationRequired, want: false},
+		{name: "not found", err: transport.ErrRepositoryNotFound, want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsTransientError(tt.err)).To(Equal(tt.want))
+		})
+	}
+}
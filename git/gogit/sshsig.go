@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	extgogit "github.com/go-git/go-git/v5"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshSigMagic is the preamble of the SSH signatures.
+	sshSigMagic = "SSHSIG"
+	// sshSigVersion is the version of the SSH signature format.
+	sshSigVersion = 1
+	// sshSigNamespace is the namespace of the SSH signatures of Git
+	// objects.
+	sshSigNamespace = "git"
+	// sshSigHashAlgorithm is the algorithm used to hash the signed
+	// message, as done by ssh-keygen.
+	sshSigHashAlgorithm = "sha512"
+	// sshSigPEMType is the type of the PEM block of an armored SSH
+	// signature.
+	sshSigPEMType = "SSH SIGNATURE"
+)
+
+// sshSigner signs Git objects with an SSH key, in the SSH signature format
+// of ssh-keygen used by Git's `gpg.format ssh`. The signatures can be
+// verified with `git verify-commit` and an allowed signers file.
+//
+// Ref: https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+type sshSigner struct {
+	signer gossh.Signer
+}
+
+var _ extgogit.Signer = &sshSigner{}
+
+// sshSigSignedData is the data signed by the SSH key.
+type sshSigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+// sshSigBlob is the SSH signature, without its magic preamble.
+type sshSigBlob struct {
+	Version       uint32
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+// Sign returns the armored SSH signature of the message.
+func (s *sshSigner) Sign(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, fmt.Errorf("failed to hash message: %w", err)
+	}
+
+	signedData := append([]byte(sshSigMagic), gossh.Marshal(sshSigSignedData{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHashAlgorithm,
+		Hash:          string(h.Sum(nil)),
+	})...)
+
+	var sig *gossh.Signature
+	var err error
+	// RSA keys must sign with SHA-512 rather than the deprecated SHA-1,
+	// as required by the format.
+	if as, ok := s.signer.(gossh.AlgorithmSigner); ok && s.signer.PublicKey().Type() == gossh.KeyAlgoRSA {
+		sig, err = as.SignWithAlgorithm(rand.Reader, signedData, gossh.KeyAlgoRSASHA512)
+	} else {
+		sig, err = s.signer.Sign(rand.Reader, signedData)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign message: %w", err)
+	}
+
+	blob := append([]byte(sshSigMagic), gossh.Marshal(sshSigBlob{
+		Version:       sshSigVersion,
+		PublicKey:     string(s.signer.PublicKey().Marshal()),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: sshSigHashAlgorithm,
+		Signature:     string(gossh.Marshal(sig)),
+	})...)
+
+	return armorSSHSignature(blob), nil
+}
+
+// armorSSHSignature returns the PEM encoded signature, with the lines
+// wrapped at 70 characters like ssh-keygen.
+func armorSSHSignature(blob []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	out := []byte("-----BEGIN " + sshSigPEMType + "-----\n")
+	for len(encoded) > 70 {
+		out = append(out, encoded[:70]+"\n"...)
+		encoded = encoded[70:]
+	}
+	out = append(out, encoded+"\n"...)
+	return append(out, "-----END "+sshSigPEMType+"-----\n"...)
+}
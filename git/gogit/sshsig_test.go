@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha512"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	. "github.com/onsi/gomega"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestCommit_SSHSigner(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		key  any
+	}{
+		{name: "ed25519", key: edKey},
+		{name: "rsa", key: rsaKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			signer, err := gossh.NewSignerFromKey(tt.key)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			tmp := t.TempDir()
+			repo, err := extgogit.PlainInit(tmp, false)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			ggc, err := NewClient(tmp, nil)
+			g.Expect(err).ToNot(HaveOccurred())
+			ggc.repository = repo
+
+			hash, err := ggc.Commit(
+				git.Commit{
+					Author:  git.Signature{Name: "Test User", Email: "test@example.com"},
+					Message: "testing",
+				},
+				repository.WithFiles(map[string]io.Reader{
+					"test": strings.NewReader("testing gogit commit"),
+				}),
+				repository.WithSSHSigner(signer),
+			)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			commit, err := repo.CommitObject(plumbing.NewHash(hash))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(commit.PGPSignature).To(HavePrefix("-----BEGIN SSH SIGNATURE-----\n"))
+
+			encoded := &plumbing.MemoryObject{}
+			g.Expect(commit.EncodeWithoutSignature(encoded)).To(Succeed())
+			r, err := encoded.Reader()
+			g.Expect(err).ToNot(HaveOccurred())
+			payload, err := io.ReadAll(r)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			pub, err := verifySSHSignature([]byte(commit.PGPSignature), payload)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pub.Marshal()).To(Equal(signer.PublicKey().Marshal()))
+
+			// Verify the signature with ssh-keygen, as done by git.
+			if _, err := exec.LookPath("ssh-keygen"); err != nil {
+				return
+			}
+			sigPath := filepath.Join(t.TempDir(), "commit.sig")
+			g.Expect(os.WriteFile(sigPath, []byte(commit.PGPSignature), 0o600)).To(Succeed())
+			cmd := exec.Command("ssh-keygen", "-Y", "check-novalidate", "-n", sshSigNamespace, "-s", sigPath)
+			cmd.Stdin = strings.NewReader(string(payload))
+			out, err := cmd.CombinedOutput()
+			g.Expect(err).ToNot(HaveOccurred(), string(out))
+		})
+	}
+}
+
+func TestCommit_SSHSignerAndSigner(t *testing.T) {
+	g := NewWithT(t)
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	signer, err := gossh.NewSignerFromKey(key)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tmp := t.TempDir()
+	repo, err := extgogit.PlainInit(tmp, false)
+	g.Expect(err).ToNot(HaveOccurred())
+	ggc, err := NewClient(tmp, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ggc.repository = repo
+
+	_, err = ggc.Commit(
+		git.Commit{Author: git.Signature{Name: "Test User", Email: "test@example.com"}},
+		repository.WithFiles(map[string]io.Reader{"test": strings.NewReader("test")}),
+		repository.WithSigner(&openpgp.Entity{}),
+		repository.WithSSHSigner(signer),
+	)
+	g.Expect(err).To(MatchError("unable to sign commit with both an OpenPGP and an SSH signer"))
+}
+
+// verifySSHSignature verifies the armored SSH signature of the message,
+// and returns the public key of the signer.
+func verifySSHSignature(armored []byte, message []byte) (gossh.PublicKey, error) {
+	block, _ := pem.Decode(armored)
+	if block == nil || block.Type != sshSigPEMType {
+		return nil, fmt.Errorf("invalid SSH signature armor")
+	}
+	if len(block.Bytes) < len(sshSigMagic) || string(block.Bytes[:len(sshSigMagic)]) != sshSigMagic {
+		return nil, fmt.Errorf("invalid SSH signature preamble")
+	}
+	var blob sshSigBlob
+	if err := gossh.Unmarshal(block.Bytes[len(sshSigMagic):], &blob); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	if blob.Version != sshSigVersion || blob.Namespace != sshSigNamespace || blob.HashAlgorithm != sshSigHashAlgorithm {
+		return nil, fmt.Errorf("unsupported SSH signature version %d, namespace '%s' or hash algorithm '%s'",
+			blob.Version, blob.Namespace, blob.HashAlgorithm)
+	}
+	pub, err := gossh.ParsePublicKey([]byte(blob.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid SSH signature public key: %w", err)
+	}
+	var sig gossh.Signature
+	if err := gossh.Unmarshal([]byte(blob.Signature), &sig); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %w", err)
+	}
+
+	h := sha512.Sum512(message)
+	signedData := append([]byte(sshSigMagic), gossh.Marshal(sshSigSignedData{
+		Namespace:     blob.Namespace,
+		HashAlgorithm: blob.HashAlgorithm,
+		Hash:          string(h[:]),
+	})...)
+	if err := pub.Verify(signedData, &sig); err != nil {
+		return nil, fmt.Errorf("failed to verify SSH signature: %w", err)
+	}
+	return pub, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+// Tag creates a tag with the provided name pointing to HEAD, or to the
+// target set with repository.WithTagTarget, and returns the hash of the
+// tag. The tag is an annotated tag if a message is set with
+// repository.WithTagMessage, which can be signed, and a lightweight tag
+// otherwise. It is pushed to the origin with repository.WithTagPush.
+func (g *Client) Tag(ctx context.Context, name string, tagOpts ...repository.TagOption) (string, error) {
+	if g.repository == nil {
+		return "", git.ErrNoGitRepository
+	}
+
+	options := &repository.TagOptions{}
+	for _, o := range tagOpts {
+		o(options)
+	}
+
+	if options.Signer != nil && options.SSHSigner != nil {
+		return "", errors.New("unable to sign tag with both an OpenPGP and an SSH signer")
+	}
+	if options.Message == "" && (options.Signer != nil || options.SSHSigner != nil) {
+		return "", errors.New("unable to sign a lightweight tag, a message is required")
+	}
+
+	refName := plumbing.NewTagReferenceName(name)
+	if err := refName.Validate(); err != nil {
+		return "", fmt.Errorf("invalid tag name '%s': %w", name, err)
+	}
+	_, err := g.repository.Reference(refName, false)
+	switch {
+	case err == nil && !options.Force:
+		return "", fmt.Errorf("unable to create tag '%s': %w", name, extgogit.ErrTagExists)
+	case err != nil && !errors.Is(err, plumbing.ErrReferenceNotFound):
+		return "", err
+	}
+
+	target := options.Target
+	if target == "" {
+		target = plumbing.HEAD.String()
+	}
+	commit, err := g.repository.ResolveRevision(plumbing.Revision(target))
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve tag target '%s': %w", target, err)
+	}
+
+	hash := *commit
+	if options.Message != "" {
+		hash, err = g.createTagObject(name, *commit, options)
+		if err != nil {
+			return "", err
+		}
+	}
+	if err := g.repository.Storer.SetReference(plumbing.NewHashReference(refName, hash)); err != nil {
+		return "", err
+	}
+
+	if options.Push {
+		err := g.Push(ctx, repository.PushConfig{
+			Refspecs: []string{fmt.Sprintf("%s:%[1]s", refName)},
+			Force:    options.Force,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return hash.String(), nil
+}
+
+// createTagObject writes the annotated tag of the commit with the tagger and
+// message of the options, signed with their signer if any, and returns its
+// hash.
+func (g *Client) createTagObject(name string, commit plumbing.Hash, options *repository.TagOptions) (plumbing.Hash, error) {
+	if options.Tagger.Name == "" {
+		return plumbing.ZeroHash, errors.New("a tagger name is required for an annotated tag")
+	}
+	when := options.Tagger.When
+	if when.IsZero() {
+		when = time.Now()
+	}
+
+	tag := &object.Tag{
+		Name: name,
+		Tagger: object.Signature{
+			Name:  options.Tagger.Name,
+			Email: options.Tagger.Email,
+			When:  when,
+		},
+		// Canonicalize the message into the format expected by Git, like
+		// go-git does for its own tags.
+		Message:    strings.TrimSpace(options.Message) + "\n",
+		TargetType: plumbing.CommitObject,
+		Target:     commit,
+	}
+
+	if options.Signer != nil || options.SSHSigner != nil {
+		encoded := &plumbing.MemoryObject{}
+		if err := tag.Encode(encoded); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		r, err := encoded.Reader()
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+
+		var sig []byte
+		if options.Signer != nil {
+			var b bytes.Buffer
+			err = openpgp.ArmoredDetachSign(&b, options.Signer, r, nil)
+			sig = b.Bytes()
+		} else {
+			sig, err = (&sshSigner{signer: options.SSHSigner}).Sign(r)
+		}
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("unable to sign tag '%s': %w", name, err)
+		}
+		tag.PGPSignature = string(sig)
+	}
+
+	obj := g.repository.Storer.NewEncodedObject()
+	if err := tag.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return g.repository.Storer.SetEncodedObject(obj)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	. "github.com/onsi/gomega"
+	gossh "golang.org/x/crypto/ssh"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestTag(t *testing.T) {
+	g := NewWithT(t)
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	first, err := commitFile(repo, "test", "first", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("release"), first))).To(Succeed())
+	second, err := commitFile(repo, "test", "second", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ggc, err := NewClient(path, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ggc.repository = repo
+
+	tagger := git.Signature{
+		Name:  "Test User",
+		Email: "test@example.com",
+		When:  time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	// A lightweight tag points to the commit.
+	hash, err := ggc.Tag(context.TODO(), "v0.1.0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hash).To(Equal(second.String()))
+	ref, err := repo.Tag("v0.1.0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ref.Hash()).To(Equal(second))
+
+	// An annotated tag points to a tag object pointing to the commit.
+	hash, err = ggc.Tag(context.TODO(), "v0.2.0", repository.WithTagMessage(tagger, "  Release v0.2.0\n\n"))
+	g.Expect(err).ToNot(HaveOccurred())
+	tag, err := repo.TagObject(plumbing.NewHash(hash))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tag.Name).To(Equal("v0.2.0"))
+	g.Expect(tag.Message).To(Equal("Release v0.2.0\n"))
+	g.Expect(tag.Tagger.Name).To(Equal(tagger.Name))
+	g.Expect(tag.Tagger.Email).To(Equal(tagger.Email))
+	g.Expect(tag.Tagger.When.Equal(tagger.When)).To(BeTrue())
+	g.Expect(tag.Target).To(Equal(second))
+	g.Expect(tag.PGPSignature).To(BeEmpty())
+
+	// The target can be a branch name.
+	hash, err = ggc.Tag(context.TODO(), "v0.0.1", repository.WithTagTarget("release"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hash).To(Equal(first.String()))
+
+	// An existing tag is only replaced with force.
+	_, err = ggc.Tag(context.TODO(), "v0.1.0", repository.WithTagTarget(first.String()))
+	g.Expect(err).To(MatchError(extgogit.ErrTagExists))
+	hash, err = ggc.Tag(context.TODO(), "v0.1.0", repository.WithTagTarget(first.String()), repository.WithTagForce())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hash).To(Equal(first.String()))
+
+	// An annotated tag can be the target, and is peeled to its commit.
+	hash, err = ggc.Tag(context.TODO(), "latest", repository.WithTagTarget("v0.2.0"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(hash).To(Equal(second.String()))
+
+	_, err = ggc.Tag(context.TODO(), "v0.3.0", repository.WithTagTarget("unknown"))
+	g.Expect(err).To(MatchError(ContainSubstring("unable to resolve tag target 'unknown'")))
+
+	_, err = ggc.Tag(context.TODO(), "v0.3.0..")
+	g.Expect(err).To(MatchError(ContainSubstring("invalid tag name")))
+
+	_, err = ggc.Tag(context.TODO(), "v0.3.0", repository.WithTagMessage(git.Signature{}, "Release"))
+	g.Expect(err).To(MatchError("a tagger name is required for an annotated tag"))
+
+	_, err = ggc.Tag(context.TODO(), "v0.3.0", repository.WithTagSSHSigner(nil), repository.WithTagSigner(&openpgp.Entity{}))
+	g.Expect(err).To(MatchError("unable to sign a lightweight tag, a message is required"))
+}
+
+func TestTag_signed(t *testing.T) {
+	g := NewWithT(t)
+
+	entity, err := openpgp.NewEntity("Test User", "", "test@example.com", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	sshSigner, err := gossh.NewSignerFromKey(edKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = commitFile(repo, "test", "first", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ggc, err := NewClient(path, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ggc.repository = repo
+
+	tagger := git.Signature{Name: "Test User", Email: "test@example.com"}
+
+	// OpenPGP signature.
+	hash, err := ggc.Tag(context.TODO(), "v0.1.0",
+		repository.WithTagMessage(tagger, "Release v0.1.0"), repository.WithTagSigner(entity))
+	g.Expect(err).ToNot(HaveOccurred())
+	tag, err := repo.TagObject(plumbing.NewHash(hash))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tag.PGPSignature).To(HavePrefix("-----BEGIN PGP SIGNATURE-----"))
+	payload := encodedTagWithoutSignature(g, repo, hash)
+	_, err = openpgp.CheckArmoredDetachedSignature(openpgp.EntityList{entity},
+		strings.NewReader(string(payload)), strings.NewReader(tag.PGPSignature), nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// SSH signature.
+	hash, err = ggc.Tag(context.TODO(), "v0.2.0",
+		repository.WithTagMessage(tagger, "Release v0.2.0"), repository.WithTagSSHSigner(sshSigner))
+	g.Expect(err).ToNot(HaveOccurred())
+	tag, err = repo.TagObject(plumbing.NewHash(hash))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tag.PGPSignature).To(HavePrefix("-----BEGIN SSH SIGNATURE-----\n"))
+	pub, err := verifySSHSignature([]byte(tag.PGPSignature), encodedTagWithoutSignature(g, repo, hash))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pub.Marshal()).To(Equal(sshSigner.PublicKey().Marshal()))
+
+	_, err = ggc.Tag(context.TODO(), "v0.3.0", repository.WithTagMessage(tagger, "Release v0.3.0"),
+		repository.WithTagSigner(entity), repository.WithTagSSHSigner(sshSigner))
+	g.Expect(err).To(MatchError("unable to sign tag with both an OpenPGP and an SSH signer"))
+}
+
+func TestTag_push(t *testing.T) {
+	g := NewWithT(t)
+
+	server, repoURL, err := setupGitServer(true)
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(server.Root())
+	defer server.StopHTTP()
+
+	tmp := t.TempDir()
+	auth, err := transportAuth(&git.AuthOptions{
+		Transport: git.HTTP,
+		Username:  "test-user",
+		Password:  "test-pass",
+	}, false)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	repo, err := extgogit.PlainClone(tmp, false, &extgogit.CloneOptions{
+		URL:        repoURL,
+		Auth:       auth,
+		RemoteName: git.DefaultRemote,
+		Tags:       extgogit.NoTags,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ggc, err := NewClient(tmp, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	ggc.repository = repo
+
+	head, err := repo.Head()
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tagger := git.Signature{Name: "Test User", Email: "test@example.com"}
+	annotated, err := ggc.Tag(context.TODO(), "v1.0.0",
+		repository.WithTagMessage(tagger, "Release v1.0.0"), repository.WithTagPush())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Tag(context.TODO(), "v1", repository.WithTagPush())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(remoteTags(g, repo, auth)).To(Equal(map[string]string{
+		"v1.0.0": annotated,
+		"v1":     head.Hash().String(),
+	}))
+
+	// The tag object of the annotated tag is pushed.
+	clone, err := extgogit.PlainClone(t.TempDir(), false, &extgogit.CloneOptions{
+		URL:  repoURL,
+		Auth: auth,
+		Tags: extgogit.AllTags,
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	ref, err := clone.Tag("v1.0.0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ref.Hash().String()).To(Equal(annotated))
+	tag, err := clone.TagObject(ref.Hash())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(tag.Message).To(Equal("Release v1.0.0\n"))
+
+	// An existing remote tag is only replaced with force.
+	second, err := commitFile(repo, "test", "second", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Tag(context.TODO(), "v1", repository.WithTagForce(), repository.WithTagPush())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remoteTags(g, repo, auth)).To(HaveKeyWithValue("v1", second.String()))
+}
+
+// remoteTags returns the hashes of the tags of the origin of the
+// repository, keyed by their name.
+func remoteTags(g *WithT, repo *extgogit.Repository, auth transport.AuthMethod) map[string]string {
+	remote, err := repo.Remote(git.DefaultRemote)
+	g.Expect(err).ToNot(HaveOccurred())
+	refs, err := remote.List(&extgogit.ListOptions{Auth: auth})
+	g.Expect(err).ToNot(HaveOccurred())
+	tags := make(map[string]string)
+	for _, ref := range refs {
+		if ref.Name().IsTag() {
+			tags[ref.Name().Short()] = ref.Hash().String()
+		}
+	}
+	return tags
+}
+
+// encodedTagWithoutSignature returns the encoded tag object with the hash,
+// without its signature, which is the payload of the signature.
+func encodedTagWithoutSignature(g *WithT, repo *extgogit.Repository, hash string) []byte {
+	tag, err := repo.TagObject(plumbing.NewHash(hash))
+	g.Expect(err).ToNot(HaveOccurred())
+	tag.PGPSignature = ""
+	encoded := &plumbing.MemoryObject{}
+	g.Expect(tag.Encode(encoded)).To(Succeed())
+	r, err := encoded.Reader()
+	g.Expect(err).ToNot(HaveOccurred())
+	payload, err := io.ReadAll(r)
+	g.Expect(err).ToNot(HaveOccurred())
+	return payload
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WithCloneTimeout configures the maximum duration of Clone, including the
+// resolution of the LFS objects. The deadline of the context passed to
+// Clone still applies when it is earlier.
+func WithCloneTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid clone timeout '%s'", timeout)
+		}
+		c.cloneTimeout = timeout
+		return nil
+	}
+}
+
+// WithPushTimeout configures the maximum duration of Push, including the
+// verification of the leases. The deadline of the context passed to Push
+// still applies when it is earlier.
+func WithPushTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid push timeout '%s'", timeout)
+		}
+		c.pushTimeout = timeout
+		return nil
+	}
+}
+
+// WithListRemoteTimeout configures the maximum duration of ListRemote. The
+// deadline of the context passed to ListRemote still applies when it is
+// earlier.
+func WithListRemoteTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) error {
+		if timeout < 0 {
+			return fmt.Errorf("invalid ls-remote timeout '%s'", timeout)
+		}
+		c.listRemoteTimeout = timeout
+		return nil
+	}
+}
+
+// operationContext returns a context derived from ctx which is done after
+// the timeout of the operation, or ctx itself if the timeout is zero.
+func operationContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// operationError annotates the error of an operation with its timeout, if
+// the operation context expired before the parent context.
+func operationError(parent, ctx context.Context, op string, timeout time.Duration, err error) error {
+	if err == nil || timeout == 0 || parent.Err() != nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%s timed out after %s: %w", op, timeout, err)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	extgogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+func TestClient_operationTimeouts(t *testing.T) {
+	// The server hangs until the request is cancelled.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	url := server.URL + "/repo.git"
+	timeout := 100 * time.Millisecond
+
+	t.Run("clone", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, WithDiskStorage(), WithCloneTimeout(timeout))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.Clone(context.TODO(), url, repository.CloneConfig{
+			CheckoutStrategy: repository.CheckoutStrategy{Branch: "main"},
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("clone timed out after 100ms")))
+	})
+
+	t.Run("ls-remote", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, WithDiskStorage(), WithListRemoteTimeout(timeout))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = ggc.ListRemote(context.TODO(), url)
+		g.Expect(err).To(MatchError(ContainSubstring("ls-remote timed out after 100ms")))
+	})
+
+	t.Run("push", func(t *testing.T) {
+		g := NewWithT(t)
+
+		tmp := t.TempDir()
+		repo, err := extgogit.PlainInit(tmp, false)
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = repo.CreateRemote(&config.RemoteConfig{
+			Name: extgogit.DefaultRemoteName,
+			URLs: []string{url},
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = commitFile(repo, "test", "testing push timeout", time.Now())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		ggc, err := NewClient(tmp, nil, WithDiskStorage(), WithPushTimeout(timeout))
+		g.Expect(err).ToNot(HaveOccurred())
+		ggc.repository = repo
+		err = ggc.Push(context.TODO(), repository.PushConfig{})
+		g.Expect(err).To(MatchError(ContainSubstring("push timed out after 100ms")))
+	})
+
+	t.Run("caller deadline", func(t *testing.T) {
+		g := NewWithT(t)
+
+		ggc, err := NewClient(t.TempDir(), &git.AuthOptions{Transport: git.HTTP}, WithDiskStorage(), WithListRemoteTimeout(time.Minute))
+		g.Expect(err).ToNot(HaveOccurred())
+		ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+		defer cancel()
+		_, err = ggc.ListRemote(ctx, url)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).ToNot(ContainSubstring("timed out after"))
+	})
+}
+
+func TestWithCloneTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewClient(t.TempDir(), nil, WithCloneTimeout(-time.Second))
+	g.Expect(err).To(MatchError("invalid clone timeout '-1s'"))
+}
@@ -18,6 +18,8 @@ package gogit
 
 import (
 	"fmt"
+	"net"
+	"slices"
 
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
@@ -52,7 +54,7 @@ func transportAuth(opts *git.AuthOptions, fallbackToDefaultKnownHosts bool) (tra
 	case git.SSH:
 		// if the custom auth options don't provide a private key and known_hosts, we try
 		// to use the default known_hosts of the machine.
-		if len(opts.Identity)+len(opts.KnownHosts) == 0 && fallbackToDefaultKnownHosts {
+		if len(opts.Identity)+len(opts.KnownHosts)+len(opts.HostKeyFingerprints) == 0 && fallbackToDefaultKnownHosts {
 			authMethod, err := ssh.DefaultAuthBuilder(opts.Username)
 			if err != nil {
 				return nil, err
@@ -71,25 +73,91 @@ func transportAuth(opts *git.AuthOptions, fallbackToDefaultKnownHosts bool) (tra
 		}
 
 		var callback gossh.HostKeyCallback
-		if len(opts.KnownHosts) > 0 {
+		var hostKeyAlgos []string
+		switch {
+		case len(opts.KnownHosts) > 0 && len(opts.HostKeyFingerprints) > 0:
+			return nil, fmt.Errorf("known_hosts and host key fingerprints are mutually exclusive")
+		case len(opts.KnownHosts) > 0:
 			callback, err = knownhosts.New(opts.KnownHosts)
 			if err != nil {
 				return nil, err
 			}
+		case len(opts.HostKeyFingerprints) > 0:
+			callback, hostKeyAlgos, err = fingerprintsCallback(opts.HostKeyFingerprints)
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		customPK := &CustomPublicKeys{
-			pk:       pk,
-			callback: callback,
+			pk:           pk,
+			callback:     callback,
+			hostKeyAlgos: hostKeyAlgos,
 		}
 		return customPK, nil
 	case "":
 		return nil, fmt.Errorf("no transport type set")
 	default:
+		if auth, ok, err := pluginAuth(opts); ok {
+			return auth, err
+		}
 		return nil, fmt.Errorf("unknown transport '%s'", opts.Transport)
 	}
 }
 
+// fingerprintsCallback returns a gossh.HostKeyCallback which accepts the
+// host keys matching one of the given SHA256 fingerprints, and of their key
+// type if set. It also returns the host key algorithms of the pinned keys,
+// or nil if the type of any of the keys is not set.
+func fingerprintsCallback(fingerprints []string) (gossh.HostKeyCallback, []string, error) {
+	type pinnedKey struct {
+		keyType     string
+		fingerprint string
+	}
+	pinned := make([]pinnedKey, 0, len(fingerprints))
+	var algos []string
+	typed := true
+	for _, fp := range fingerprints {
+		keyType, fingerprint, ok := git.ParseHostKeyFingerprint(fp)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid host key fingerprint '%s'", fp)
+		}
+		pinned = append(pinned, pinnedKey{keyType: keyType, fingerprint: fingerprint})
+		if keyType == "" {
+			typed = false
+			continue
+		}
+		for _, algo := range hostKeyAlgorithms(keyType) {
+			if !slices.Contains(algos, algo) {
+				algos = append(algos, algo)
+			}
+		}
+	}
+	if !typed {
+		algos = nil
+	}
+
+	callback := func(hostname string, _ net.Addr, key gossh.PublicKey) error {
+		fingerprint := gossh.FingerprintSHA256(key)
+		for _, p := range pinned {
+			if p.fingerprint == fingerprint && (p.keyType == "" || p.keyType == key.Type()) {
+				return nil
+			}
+		}
+		return fmt.Errorf("host key fingerprint %s for '%s' does not match any of the pinned fingerprints", fingerprint, hostname)
+	}
+	return callback, algos, nil
+}
+
+// hostKeyAlgorithms returns the host key algorithms which can be negotiated
+// for a host key of the given type.
+func hostKeyAlgorithms(keyType string) []string {
+	if keyType == gossh.KeyAlgoRSA {
+		return []string{gossh.KeyAlgoRSASHA512, gossh.KeyAlgoRSASHA256, gossh.KeyAlgoRSA}
+	}
+	return []string{keyType}
+}
+
 // caBundle returns the CA bundle from the given git.AuthOptions.
 func caBundle(opts *git.AuthOptions) []byte {
 	if opts == nil {
@@ -101,8 +169,9 @@ func caBundle(opts *git.AuthOptions) []byte {
 // CustomPublicKeys is a wrapper around ssh.PublicKeys to help us
 // customize the ssh config. It implements ssh.AuthMethod.
 type CustomPublicKeys struct {
-	pk       *ssh.PublicKeys
-	callback gossh.HostKeyCallback
+	pk           *ssh.PublicKeys
+	callback     gossh.HostKeyCallback
+	hostKeyAlgos []string
 }
 
 func (a *CustomPublicKeys) Name() string {
@@ -126,7 +195,9 @@ func (a *CustomPublicKeys) ClientConfig() (*gossh.ClientConfig, error) {
 	if len(git.KexAlgos) > 0 {
 		config.Config.KeyExchanges = git.KexAlgos
 	}
-	if len(git.HostKeyAlgos) > 0 {
+	if len(a.hostKeyAlgos) > 0 {
+		config.HostKeyAlgorithms = a.hostKeyAlgos
+	} else if len(git.HostKeyAlgos) > 0 {
 		config.HostKeyAlgorithms = git.HostKeyAlgos
 	}
 
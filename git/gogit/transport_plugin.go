@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/client"
+	"github.com/go-git/go-git/v5/plumbing/transport/file"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// defaultTransportSchemes are the URL schemes of the pure Go transports of
+// go-git, which can't be replaced by a transport plugin.
+var defaultTransportSchemes = []string{"http", "https", "ssh", "git", "file"}
+
+var (
+	transportPluginsMu sync.RWMutex
+	transportPlugins   = map[string]transport.Transport{}
+)
+
+// TransportAuthBuilder can be implemented by a transport plugin to build
+// the transport.AuthMethod of its URL scheme from the git.AuthOptions of the
+// client. Plugins which don't implement it are given a nil AuthMethod.
+type TransportAuthBuilder interface {
+	AuthMethod(opts *git.AuthOptions) (transport.AuthMethod, error)
+}
+
+// RegisterTransport registers a transport plugin for the given URL scheme,
+// e.g. a transport invoking a local git binary or a custom gRPC transport,
+// for the environments in which the pure Go transports can't be used. The
+// clients use it for the URLs of the scheme, with git.AuthOptions of the
+// same git.TransportType. The default transports can't be replaced.
+//
+// go-git registers the transports globally, hence plugins should be
+// registered during the initialization of the program, before any client
+// is used.
+func RegisterTransport(scheme string, t transport.Transport) error {
+	if scheme == "" || t == nil {
+		return fmt.Errorf("a scheme and a transport are required")
+	}
+	if slices.Contains(defaultTransportSchemes, scheme) {
+		return fmt.Errorf("the transport of scheme '%s' can't be replaced", scheme)
+	}
+
+	transportPluginsMu.Lock()
+	defer transportPluginsMu.Unlock()
+	if _, ok := transportPlugins[scheme]; ok {
+		return fmt.Errorf("a transport is already registered for scheme '%s'", scheme)
+	}
+	transportPlugins[scheme] = t
+	client.InstallProtocol(scheme, t)
+	return nil
+}
+
+// UnregisterTransport removes the transport plugin of the given URL scheme.
+func UnregisterTransport(scheme string) {
+	transportPluginsMu.Lock()
+	defer transportPluginsMu.Unlock()
+	if _, ok := transportPlugins[scheme]; !ok {
+		return
+	}
+	delete(transportPlugins, scheme)
+	client.InstallProtocol(scheme, nil)
+}
+
+// NewExecTransport returns a transport running the given git-upload-pack
+// and git-receive-pack binaries with the path of the URL as argument, and
+// speaking the Git protocol over their stdin and stdout, e.g. to interop
+// with bundled server binaries. The binaries are looked up in the PATH and
+// in the exec path of git if they are not absolute paths.
+func NewExecTransport(uploadPackBin, receivePackBin string) transport.Transport {
+	return file.NewClient(uploadPackBin, receivePackBin)
+}
+
+// pluginAuth returns the transport.AuthMethod of the transport plugin of
+// the git.TransportType of the options, and false if there's no plugin.
+func pluginAuth(opts *git.AuthOptions) (transport.AuthMethod, bool, error) {
+	transportPluginsMu.RLock()
+	t, ok := transportPlugins[string(opts.Transport)]
+	transportPluginsMu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	if b, ok := t.(TransportAuthBuilder); ok {
+		auth, err := b.AuthMethod(opts)
+		return auth, true, err
+	}
+	return nil, true, nil
+}
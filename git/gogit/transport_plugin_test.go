@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gogit
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/git"
+	"github.com/fluxcd/pkg/git/repository"
+)
+
+type authTransport struct {
+	transport.Transport
+	authOpts []*git.AuthOptions
+}
+
+func (t *authTransport) AuthMethod(opts *git.AuthOptions) (transport.AuthMethod, error) {
+	t.authOpts = append(t.authOpts, opts)
+	return &http.BasicAuth{Username: opts.Username}, nil
+}
+
+func TestRegisterTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	et := NewExecTransport("git-upload-pack", "git-receive-pack")
+	g.Expect(RegisterTransport("https", et)).To(MatchError("the transport of scheme 'https' can't be replaced"))
+	g.Expect(RegisterTransport("", et)).To(HaveOccurred())
+
+	g.Expect(RegisterTransport("test", et)).To(Succeed())
+	defer UnregisterTransport("test")
+	g.Expect(RegisterTransport("test", et)).To(MatchError("a transport is already registered for scheme 'test'"))
+
+	UnregisterTransport("test")
+	g.Expect(RegisterTransport("test", et)).To(Succeed())
+}
+
+func TestClone_execTransport(t *testing.T) {
+	if _, err := exec.LookPath("git-upload-pack"); err != nil {
+		t.Skip("git-upload-pack not found")
+	}
+	g := NewWithT(t)
+
+	repo, path, err := initRepo(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	hash, err := commitFile(repo, "file", "content", time.Now())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	plugin := &authTransport{Transport: NewExecTransport("git-upload-pack", "git-receive-pack")}
+	g.Expect(RegisterTransport("exec", plugin)).To(Succeed())
+	defer UnregisterTransport("exec")
+
+	authOpts := &git.AuthOptions{Transport: "exec", Username: "flux"}
+	ggc, err := NewClient(t.TempDir(), authOpts)
+	g.Expect(err).ToNot(HaveOccurred())
+	cc, err := ggc.Clone(context.TODO(), "exec://"+path, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cc.Hash.String()).To(Equal(hash.String()))
+	g.Expect(plugin.authOpts).To(ContainElement(authOpts))
+
+	// Without the plugin, the scheme is unknown.
+	UnregisterTransport("exec")
+	ggc, err = NewClient(t.TempDir(), authOpts)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = ggc.Clone(context.TODO(), "exec://"+path, repository.CloneConfig{
+		CheckoutStrategy: repository.CheckoutStrategy{Branch: git.DefaultBranch},
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("unknown transport 'exec'")))
+}
@@ -21,6 +21,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/go-git/go-git/v5/plumbing/transport"
@@ -237,6 +238,87 @@ func Test_transportAuth(t *testing.T) {
 			},
 			wantErr: errors.New("knownhosts: knownhosts: missing host pattern"),
 		},
+		{
+			name: "SSH private key with host key fingerprints",
+			opts: &git.AuthOptions{
+				Transport:           git.SSH,
+				Username:            "example",
+				Identity:            []byte(privateKeyFixture),
+				HostKeyFingerprints: []string{"SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s", "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"},
+			},
+			wantFunc: func(g *WithT, t transport.AuthMethod, opts *git.AuthOptions) {
+				tt, ok := t.(*CustomPublicKeys)
+				g.Expect(ok).To(BeTrue())
+				g.Expect(tt.pk.User).To(Equal(opts.Username))
+				g.Expect(tt.callback).ToNot(BeNil())
+				g.Expect(tt.hostKeyAlgos).To(BeNil())
+
+				key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(strings.TrimPrefix(knownHostsFixture, "github.com ")))
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(tt.callback("github.com:22", nil, key)).To(Succeed())
+
+				signer, err := gossh.ParsePrivateKey([]byte(privateKeyFixture))
+				g.Expect(err).ToNot(HaveOccurred())
+				err = tt.callback("github.com:22", nil, signer.PublicKey())
+				g.Expect(err).To(MatchError(ContainSubstring("does not match any of the pinned fingerprints")))
+			},
+		},
+		{
+			name: "SSH private key with typed host key fingerprints",
+			opts: &git.AuthOptions{
+				Transport: git.SSH,
+				Username:  "example",
+				Identity:  []byte(privateKeyFixture),
+				HostKeyFingerprints: []string{
+					"ssh-ed25519 SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU",
+					"ssh-rsa SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8",
+				},
+			},
+			wantFunc: func(g *WithT, t transport.AuthMethod, opts *git.AuthOptions) {
+				tt, ok := t.(*CustomPublicKeys)
+				g.Expect(ok).To(BeTrue())
+				g.Expect(tt.hostKeyAlgos).To(Equal([]string{
+					gossh.KeyAlgoED25519, gossh.KeyAlgoRSASHA512, gossh.KeyAlgoRSASHA256, gossh.KeyAlgoRSA,
+				}))
+
+				config, err := tt.ClientConfig()
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(config.HostKeyAlgorithms).To(Equal(tt.hostKeyAlgos))
+
+				key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(strings.TrimPrefix(knownHostsFixture, "github.com ")))
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(tt.callback("github.com:22", nil, key)).To(Succeed())
+			},
+		},
+		{
+			name: "SSH private key with host key fingerprint of another key type",
+			opts: &git.AuthOptions{
+				Transport:           git.SSH,
+				Username:            "example",
+				Identity:            []byte(privateKeyFixture),
+				HostKeyFingerprints: []string{"ssh-ed25519 SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"},
+			},
+			wantFunc: func(g *WithT, t transport.AuthMethod, opts *git.AuthOptions) {
+				tt, ok := t.(*CustomPublicKeys)
+				g.Expect(ok).To(BeTrue())
+
+				key, _, _, _, err := gossh.ParseAuthorizedKey([]byte(strings.TrimPrefix(knownHostsFixture, "github.com ")))
+				g.Expect(err).ToNot(HaveOccurred())
+				err = tt.callback("github.com:22", nil, key)
+				g.Expect(err).To(MatchError(ContainSubstring("does not match any of the pinned fingerprints")))
+			},
+		},
+		{
+			name: "SSH private key with known_hosts and host key fingerprints",
+			opts: &git.AuthOptions{
+				Transport:           git.SSH,
+				Username:            "example",
+				Identity:            []byte(privateKeyFixture),
+				KnownHosts:          []byte(knownHostsFixture),
+				HostKeyFingerprints: []string{"SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"},
+			},
+			wantErr: errors.New("known_hosts and host key fingerprints are mutually exclusive"),
+		},
 		{
 			name: "SSH private key without known_hosts",
 			opts: &git.AuthOptions{
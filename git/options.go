@@ -19,8 +19,10 @@ package git
 import (
 	"fmt"
 	"net/url"
+	"strings"
 
 	"github.com/fluxcd/pkg/auth/azure"
+	"github.com/fluxcd/pkg/auth/dev"
 	"github.com/fluxcd/pkg/auth/github"
 )
 
@@ -50,6 +52,13 @@ type AuthOptions struct {
 	KnownHosts   []byte
 	CAFile       []byte
 	ProviderOpts *ProviderOptions
+	// HostKeyFingerprints are the SHA256 fingerprints of the host keys
+	// accepted for SSH connections, in the 'SHA256:<base64>' format
+	// printed by 'ssh-keygen -lf', optionally prefixed by the type of the
+	// key, e.g. 'ssh-ed25519 SHA256:<base64>'. When the type of all the
+	// keys is set, only their algorithms are advertised to the server.
+	// They can be used instead of KnownHosts.
+	HostKeyFingerprints []string
 }
 
 // ProviderOptions contains options to configure various authentication
@@ -58,8 +67,12 @@ type ProviderOptions struct {
 	Name       string
 	AzureOpts  []azure.OptFunc
 	GitHubOpts []github.OptFunc
+	DevOpts    []dev.OptFunc
 }
 
+// fingerprintPrefix is the prefix of the SHA256 host key fingerprints.
+const fingerprintPrefix = "SHA256:"
+
 // KexAlgos hosts the key exchange algorithms to be used for SSH connections.
 // If empty, Go's default is used instead.
 var KexAlgos []string
@@ -82,9 +95,17 @@ func (o AuthOptions) Validate() error {
 		if len(o.Identity) == 0 {
 			return fmt.Errorf("invalid '%s' auth option: 'identity' is required", o.Transport)
 		}
-		if len(o.KnownHosts) == 0 {
+		if len(o.KnownHosts) == 0 && len(o.HostKeyFingerprints) == 0 {
 			return fmt.Errorf("invalid '%s' auth option: 'known_hosts' is required", o.Transport)
 		}
+		if len(o.KnownHosts) > 0 && len(o.HostKeyFingerprints) > 0 {
+			return fmt.Errorf("invalid '%s' auth option: 'known_hosts' and 'known_hosts_fingerprints' are mutually exclusive", o.Transport)
+		}
+		for _, fp := range o.HostKeyFingerprints {
+			if _, _, ok := ParseHostKeyFingerprint(fp); !ok {
+				return fmt.Errorf("invalid '%s' auth option: host key fingerprint '%s' must be in the format '[<key type> ]SHA256:<fingerprint>'", o.Transport, fp)
+			}
+		}
 	case "":
 		return fmt.Errorf("no transport type set")
 	default:
@@ -107,6 +128,7 @@ func NewAuthOptions(u url.URL, data map[string][]byte) (*AuthOptions, error) {
 		if opts.Transport == SSH {
 			opts.Identity = data["identity"]
 			opts.KnownHosts = data["known_hosts"]
+			opts.HostKeyFingerprints = parseFingerprints(data["known_hosts_fingerprints"])
 			opts.Username = u.User.Username()
 			opts.Password = string(data["password"])
 			// We fallback to using "git" as the username when cloning Git
@@ -150,3 +172,36 @@ func newAuthOptions(u url.URL) *AuthOptions {
 
 	return opts
 }
+
+// ParseHostKeyFingerprint returns the key type, empty if not set, and the
+// SHA256 fingerprint of the given host key fingerprint in the
+// '[<key type> ]SHA256:<fingerprint>' format, and whether it is valid.
+func ParseHostKeyFingerprint(s string) (keyType, fingerprint string, ok bool) {
+	fields := strings.Fields(s)
+	switch len(fields) {
+	case 1:
+		fingerprint = fields[0]
+	case 2:
+		keyType, fingerprint = fields[0], fields[1]
+	default:
+		return "", "", false
+	}
+	if !strings.HasPrefix(fingerprint, fingerprintPrefix) || len(fingerprint) == len(fingerprintPrefix) {
+		return "", "", false
+	}
+	return keyType, fingerprint, true
+}
+
+// parseFingerprints returns the host key fingerprints from the given data,
+// separated by newlines or commas.
+func parseFingerprints(data []byte) []string {
+	var fingerprints []string
+	for _, fp := range strings.FieldsFunc(string(data), func(r rune) bool {
+		return r == '\n' || r == '\r' || r == ','
+	}) {
+		if fp = strings.TrimSpace(fp); fp != "" {
+			fingerprints = append(fingerprints, fp)
+		}
+	}
+	return fingerprints
+}
@@ -145,6 +145,45 @@ func TestAuthOptions_Validate(t *testing.T) {
 				KnownHosts: []byte(knownHostsFixture),
 			},
 		},
+		{
+			name: "Valid SSH transport with host key fingerprints",
+			opts: AuthOptions{
+				Host:                "github.com:22",
+				Transport:           SSH,
+				Identity:            []byte(privateKeyFixture),
+				HostKeyFingerprints: []string{"SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s"},
+			},
+		},
+		{
+			name: "SSH transport requires SHA256 host key fingerprints",
+			opts: AuthOptions{
+				Host:                "github.com:22",
+				Transport:           SSH,
+				Identity:            []byte(privateKeyFixture),
+				HostKeyFingerprints: []string{"MD5:16:27:ac:a5:76:28:2d:36:63:1b:56:4d:eb:df:a6:48"},
+			},
+			wantErr: "invalid 'ssh' auth option: host key fingerprint 'MD5:16:27:ac:a5:76:28:2d:36:63:1b:56:4d:eb:df:a6:48' must be in the format '[<key type> ]SHA256:<fingerprint>'",
+		},
+		{
+			name: "Valid SSH transport with typed host key fingerprints",
+			opts: AuthOptions{
+				Host:                "github.com:22",
+				Transport:           SSH,
+				Identity:            []byte(privateKeyFixture),
+				HostKeyFingerprints: []string{"ssh-ed25519 SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU"},
+			},
+		},
+		{
+			name: "SSH transport does not allow both known_hosts and host key fingerprints",
+			opts: AuthOptions{
+				Host:                "github.com:22",
+				Transport:           SSH,
+				Identity:            []byte(privateKeyFixture),
+				KnownHosts:          []byte(knownHostsFixture),
+				HostKeyFingerprints: []string{"SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s"},
+			},
+			wantErr: "invalid 'ssh' auth option: 'known_hosts' and 'known_hosts_fingerprints' are mutually exclusive",
+		},
 		{
 			name:    "No transport",
 			opts:    AuthOptions{},
@@ -337,6 +376,23 @@ func TestAuthOptionsFromData(t *testing.T) {
 				g.Expect(opts.Password).To(Equal("pass"))
 			},
 		},
+		{
+			name: "Sets host key fingerprints from Secret for SSH",
+			URL:  "ssh://example.com",
+			data: map[string][]byte{
+				"identity": []byte(privateKeyFixture),
+				"known_hosts_fingerprints": []byte("SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s\n" +
+					"SHA256:p2QAMXNIC1TJYWeIOttrVc98/R1BUFWu3/LiyKgUfQM, SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU\n"),
+			},
+			wantFunc: func(g *WithT, opts *AuthOptions) {
+				g.Expect(opts.KnownHosts).To(BeNil())
+				g.Expect(opts.HostKeyFingerprints).To(Equal([]string{
+					"SHA256:uNiVztksCsDhcc0u9e8BujQXVUpKZIDTMczCvj3tD2s",
+					"SHA256:p2QAMXNIC1TJYWeIOttrVc98/R1BUFWu3/LiyKgUfQM",
+					"SHA256:+DiY3wvvV6TuJJhbpZisF/zLDA0zPMSvHdkr4UvCOqU",
+				}))
+			},
+		},
 		{
 			name: "Validates options",
 			URL:  "ssh://example.com",
@@ -0,0 +1,395 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	uploadPackService           = "git-upload-pack"
+	uploadPackAdvertisementType = "application/x-git-upload-pack-advertisement"
+	uploadPackRequestType       = "application/x-git-upload-pack-request"
+	uploadPackResultType        = "application/x-git-upload-pack-result"
+)
+
+// ErrAuthenticationFailed indicates that the Git server rejected the
+// request, because the credentials are missing, invalid or lack access to
+// the repository.
+type ErrAuthenticationFailed struct {
+	StatusCode int
+	URL        string
+}
+
+func (e ErrAuthenticationFailed) Error() string {
+	return fmt.Sprintf("authentication failed with status code %d: git repository: '%s'", e.StatusCode, e.URL)
+}
+
+// ErrUnsupportedFeature indicates that the Git server does not support a
+// protocol or a feature required by the caller.
+type ErrUnsupportedFeature struct {
+	Features []string
+	URL      string
+}
+
+func (e ErrUnsupportedFeature) Error() string {
+	return fmt.Sprintf("unsupported features '%s': git repository: '%s'", strings.Join(e.Features, "', '"), e.URL)
+}
+
+// ProbeResult is the result of probing a remote repository.
+type ProbeResult struct {
+	// ProtocolVersion is the version of the Git wire protocol spoken by the
+	// server, 2 if the server supports the protocol v2 and 0 otherwise.
+	ProtocolVersion int
+	// Capabilities are the capabilities advertised by the server, mapped to
+	// their value, e.g. 'fetch' to 'shallow wait-for-done filter' for the
+	// protocol v2, or 'filter' to '' for the protocol v0.
+	Capabilities map[string]string
+	// Head is the reference HEAD points to, nil if the repository is empty.
+	Head *Reference
+	// DefaultBranch is the branch HEAD points to, e.g. 'refs/heads/main',
+	// empty if not advertised by the server.
+	DefaultBranch string
+}
+
+// Supports returns true if the server supports the feature, advertised
+// either as a capability or, for the protocol v2, as a feature of the
+// fetch command, e.g. 'shallow' or 'filter'.
+func (r *ProbeResult) Supports(feature string) bool {
+	if _, ok := r.Capabilities[feature]; ok {
+		return true
+	}
+	if r.ProtocolVersion == 2 {
+		for _, f := range strings.Fields(r.Capabilities["fetch"]) {
+			if f == feature {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ProbeOption configures the probe of a remote repository.
+type ProbeOption func(*probeOptions)
+
+type probeOptions struct {
+	proxy    func(*http.Request) (*url.URL, error)
+	features []string
+}
+
+// WithProbeProxy sets the URL of the proxy the probe goes through. Defaults
+// to the proxy configured in the environment, see http.ProxyFromEnvironment.
+func WithProbeProxy(proxyURL *url.URL) ProbeOption {
+	return func(o *probeOptions) {
+		o.proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithRequiredFeatures sets the features the server must support, see
+// ProbeResult.Supports. The probe returns an ErrUnsupportedFeature along
+// with the result if any of them is not supported.
+func WithRequiredFeatures(features ...string) ProbeOption {
+	return func(o *probeOptions) {
+		o.features = append(o.features, features...)
+	}
+}
+
+// ProbeRemote probes the remote repository at the given HTTP(S) URL with the
+// smart HTTP protocol, without cloning it. It asks for the protocol v2 and
+// lists the HEAD reference with the ls-refs command, or reads it from the
+// advertisement of a server which only speaks the protocol v0.
+//
+// It allows callers to cheaply tell apart the authentication failures, see
+// ErrAuthenticationFailed, the missing repositories, see
+// ErrRepositoryNotFound, and the unsupported protocols and features, see
+// ErrUnsupportedFeature, before attempting a clone.
+func ProbeRemote(ctx context.Context, repoURL string, authOpts *AuthOptions, opts ...ProbeOption) (*ProbeResult, error) {
+	o := probeOptions{
+		proxy: http.ProxyFromEnvironment,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL '%s': %w", repoURL, err)
+	}
+	if u.Scheme != string(HTTP) && u.Scheme != string(HTTPS) {
+		return nil, fmt.Errorf("unsupported transport '%s': only HTTP(S) remotes can be probed", u.Scheme)
+	}
+	if authOpts == nil {
+		authOpts = newAuthOptions(*u)
+		authOpts.Username = u.User.Username()
+		authOpts.Password, _ = u.User.Password()
+	}
+	u.User = nil
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	// The URL is redacted in the errors.
+	remote := u.String()
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = o.proxy
+	if len(authOpts.CAFile) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(authOpts.CAFile) {
+			return nil, fmt.Errorf("failed to parse CA certificates")
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	p := &prober{
+		client:   &http.Client{Transport: transport},
+		url:      remote,
+		authOpts: authOpts,
+	}
+	defer transport.CloseIdleConnections()
+
+	result, err := p.probe(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsupported []string
+	for _, f := range o.features {
+		if !result.Supports(f) {
+			unsupported = append(unsupported, f)
+		}
+	}
+	if len(unsupported) > 0 {
+		return result, ErrUnsupportedFeature{Features: unsupported, URL: remote}
+	}
+	return result, nil
+}
+
+type prober struct {
+	client   *http.Client
+	url      string
+	authOpts *AuthOptions
+}
+
+func (p *prober) probe(ctx context.Context) (*ProbeResult, error) {
+	req, err := p.newRequest(ctx, http.MethodGet, "/info/refs?service="+uploadPackService, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := p.do(req, uploadPackAdvertisementType)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	r := bufio.NewReader(res.Body)
+	// The smart HTTP advertisement starts with the service, followed by a
+	// flush packet.
+	line, err := readPktLine(r)
+	if err != nil {
+		return nil, p.protocolError(err)
+	}
+	if string(line) != "# service="+uploadPackService {
+		return nil, p.protocolError(fmt.Errorf("unexpected service line '%s'", line))
+	}
+	if line, err = readPktLine(r); err != nil || line != nil {
+		return nil, p.protocolError(errors.New("missing flush packet after the service line"))
+	}
+
+	line, err = readPktLine(r)
+	if err != nil {
+		return nil, p.protocolError(err)
+	}
+	if string(line) == "version 2" {
+		return p.probeV2(ctx, r)
+	}
+	return parseV0Advertisement(line), nil
+}
+
+// probeV2 reads the capabilities advertised by the server, and lists the
+// HEAD reference with the ls-refs command.
+func (p *prober) probeV2(ctx context.Context, r *bufio.Reader) (*ProbeResult, error) {
+	result := &ProbeResult{
+		ProtocolVersion: 2,
+		Capabilities:    make(map[string]string),
+	}
+	for {
+		line, err := readPktLine(r)
+		if err != nil {
+			return nil, p.protocolError(err)
+		}
+		if line == nil {
+			break
+		}
+		key, value, _ := strings.Cut(string(line), "=")
+		result.Capabilities[key] = value
+	}
+	if _, ok := result.Capabilities["ls-refs"]; !ok {
+		return nil, ErrUnsupportedFeature{Features: []string{"ls-refs"}, URL: p.url}
+	}
+
+	var body bytes.Buffer
+	writePktLine(&body, "command=ls-refs")
+	if format, ok := result.Capabilities["object-format"]; ok {
+		writePktLine(&body, "object-format="+format)
+	}
+	body.WriteString("0001")
+	writePktLine(&body, "symrefs")
+	writePktLine(&body, "ref-prefix HEAD")
+	body.WriteString("0000")
+
+	req, err := p.newRequest(ctx, http.MethodPost, "/"+uploadPackService, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", uploadPackRequestType)
+	res, err := p.do(req, uploadPackResultType)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	lr := bufio.NewReader(res.Body)
+	for {
+		line, err := readPktLine(lr)
+		if err != nil {
+			return nil, p.protocolError(err)
+		}
+		if line == nil {
+			break
+		}
+		// <oid> <ref> [symref-target:<target>] [peeled:<oid>]
+		fields := strings.Fields(string(line))
+		if len(fields) < 2 || fields[1] != "HEAD" {
+			continue
+		}
+		result.Head = &Reference{Name: fields[1], Hash: Hash(fields[0])}
+		for _, attr := range fields[2:] {
+			if target, ok := strings.CutPrefix(attr, "symref-target:"); ok {
+				result.DefaultBranch = target
+			}
+		}
+	}
+	return result, nil
+}
+
+// parseV0Advertisement parses the first line of the protocol v0
+// advertisement, composed of the first reference, usually HEAD, and the
+// capabilities of the server.
+func parseV0Advertisement(line []byte) *ProbeResult {
+	result := &ProbeResult{
+		Capabilities: make(map[string]string),
+	}
+	ref, caps, _ := bytes.Cut(line, []byte{0})
+	for _, c := range strings.Fields(string(caps)) {
+		key, value, _ := strings.Cut(c, "=")
+		if key == "symref" {
+			if src, target, ok := strings.Cut(value, ":"); ok && src == "HEAD" {
+				result.DefaultBranch = target
+			}
+		}
+		result.Capabilities[key] = value
+	}
+	// An empty repository advertises a 'capabilities^{}' reference.
+	if hash, name, ok := strings.Cut(string(ref), " "); ok && name == "HEAD" {
+		result.Head = &Reference{Name: name, Hash: Hash(hash)}
+	}
+	return result
+}
+
+func (p *prober) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.url+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Git-Protocol", "version=2")
+	switch {
+	case p.authOpts.BearerToken != "":
+		req.Header.Set("Authorization", "Bearer "+p.authOpts.BearerToken)
+	case p.authOpts.Username != "" || p.authOpts.Password != "":
+		req.SetBasicAuth(p.authOpts.Username, p.authOpts.Password)
+	}
+	return req, nil
+}
+
+// do sends the request, and maps the status code of the response to the
+// structured errors.
+func (p *prober) do(req *http.Request, contentType string) (*http.Response, error) {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe git repository '%s': %w", p.url, err)
+	}
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		res.Body.Close()
+		return nil, ErrAuthenticationFailed{StatusCode: res.StatusCode, URL: p.url}
+	case http.StatusNotFound:
+		res.Body.Close()
+		return nil, ErrRepositoryNotFound{Message: "repository not found", URL: p.url}
+	default:
+		res.Body.Close()
+		return nil, fmt.Errorf("failed to probe git repository '%s': unexpected status code %d", p.url, res.StatusCode)
+	}
+	// Dumb HTTP servers serve the files of the repository as is.
+	if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, contentType) {
+		res.Body.Close()
+		return nil, ErrUnsupportedFeature{Features: []string{"smart-http"}, URL: p.url}
+	}
+	return res, nil
+}
+
+func (p *prober) protocolError(err error) error {
+	return fmt.Errorf("failed to probe git repository '%s': %w", p.url, err)
+}
+
+// readPktLine reads a pkt-line, and returns its payload without the
+// trailing new line, or nil for a flush, delimiter or response end
+// packet. An error packet is returned as an error.
+func readPktLine(r *bufio.Reader) ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, fmt.Errorf("failed to read pkt-line: %w", err)
+	}
+	n, err := strconv.ParseUint(string(size[:]), 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pkt-line length '%s'", size)
+	}
+	if n < 4 {
+		return nil, nil
+	}
+	line := make([]byte, n-4)
+	if _, err := io.ReadFull(r, line); err != nil {
+		return nil, fmt.Errorf("failed to read pkt-line: %w", err)
+	}
+	line = bytes.TrimSuffix(line, []byte("\n"))
+	if msg, ok := bytes.CutPrefix(line, []byte("ERR ")); ok {
+		return nil, fmt.Errorf("remote error: %s", msg)
+	}
+	return line, nil
+}
+
+func writePktLine(w *bytes.Buffer, line string) {
+	fmt.Fprintf(w, "%04x%s\n", len(line)+5, line)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const probeHash = "a0c14dc8580a23f79bc654faa79c4f62b46c2c22"
+
+// gitServer is a fake smart HTTP Git server, speaking the protocol v2 when
+// asked for by the client unless v0 is set.
+type gitServer struct {
+	v0           bool
+	dumb         bool
+	username     string
+	password     string
+	lsRefsBodies []string
+}
+
+func (s *gitServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.username != "" {
+		if u, p, ok := r.BasicAuth(); !ok || u != s.username || p != s.password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	if !strings.HasPrefix(r.URL.Path, "/repo.git/") {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	var b bytes.Buffer
+	switch {
+	case s.dumb:
+		w.Header().Set("Content-Type", "text/plain")
+		b.WriteString(probeHash + "\trefs/heads/main\n")
+	case r.Method == http.MethodGet && r.URL.Path == "/repo.git/info/refs":
+		w.Header().Set("Content-Type", uploadPackAdvertisementType)
+		writePktLine(&b, "# service=git-upload-pack")
+		b.WriteString("0000")
+		if !s.v0 && r.Header.Get("Git-Protocol") == "version=2" {
+			writePktLine(&b, "version 2")
+			writePktLine(&b, "agent=git/2.47.0")
+			writePktLine(&b, "ls-refs=unborn")
+			writePktLine(&b, "fetch=shallow wait-for-done")
+			writePktLine(&b, "object-format=sha1")
+		} else {
+			writePktLine(&b, probeHash+" HEAD\x00multi_ack shallow symref=HEAD:refs/heads/main agent=git/2.47.0")
+			writePktLine(&b, probeHash+" refs/heads/main")
+		}
+		b.WriteString("0000")
+	case r.Method == http.MethodPost && r.URL.Path == "/repo.git/git-upload-pack":
+		body, _ := io.ReadAll(r.Body)
+		s.lsRefsBodies = append(s.lsRefsBodies, string(body))
+		w.Header().Set("Content-Type", uploadPackResultType)
+		writePktLine(&b, probeHash+" HEAD symref-target:refs/heads/main")
+		b.WriteString("0000")
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	_, _ = w.Write(b.Bytes())
+}
+
+func TestProbeRemote(t *testing.T) {
+	head := &Reference{Name: "HEAD", Hash: Hash(probeHash)}
+
+	t.Run("protocol v2", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &gitServer{}
+		server := httptest.NewServer(s)
+		defer server.Close()
+
+		result, err := ProbeRemote(context.TODO(), server.URL+"/repo.git/", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.ProtocolVersion).To(Equal(2))
+		g.Expect(result.Head).To(Equal(head))
+		g.Expect(result.DefaultBranch).To(Equal("refs/heads/main"))
+		g.Expect(result.Supports("shallow")).To(BeTrue())
+		g.Expect(result.Supports("filter")).To(BeFalse())
+		g.Expect(s.lsRefsBodies).To(Equal([]string{
+			"0014command=ls-refs\n0017object-format=sha1\n0001000csymrefs\n0014ref-prefix HEAD\n0000",
+		}))
+	})
+
+	t.Run("protocol v0", func(t *testing.T) {
+		g := NewWithT(t)
+
+		s := &gitServer{v0: true}
+		server := httptest.NewServer(s)
+		defer server.Close()
+
+		result, err := ProbeRemote(context.TODO(), server.URL+"/repo.git", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.ProtocolVersion).To(Equal(0))
+		g.Expect(result.Head).To(Equal(head))
+		g.Expect(result.DefaultBranch).To(Equal("refs/heads/main"))
+		g.Expect(result.Supports("shallow")).To(BeTrue())
+		g.Expect(s.lsRefsBodies).To(BeEmpty())
+	})
+
+	t.Run("required features", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(&gitServer{})
+		defer server.Close()
+
+		result, err := ProbeRemote(context.TODO(), server.URL+"/repo.git", nil, WithRequiredFeatures("shallow", "filter"))
+		g.Expect(result).ToNot(BeNil())
+		var unsupported ErrUnsupportedFeature
+		g.Expect(errors.As(err, &unsupported)).To(BeTrue())
+		g.Expect(unsupported.Features).To(Equal([]string{"filter"}))
+	})
+
+	t.Run("dumb HTTP", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(&gitServer{dumb: true})
+		defer server.Close()
+
+		_, err := ProbeRemote(context.TODO(), server.URL+"/repo.git", nil)
+		var unsupported ErrUnsupportedFeature
+		g.Expect(errors.As(err, &unsupported)).To(BeTrue())
+		g.Expect(unsupported.Features).To(Equal([]string{"smart-http"}))
+	})
+
+	t.Run("authentication", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(&gitServer{username: "user", password: "pass"})
+		defer server.Close()
+
+		_, err := ProbeRemote(context.TODO(), server.URL+"/repo.git", nil)
+		var authErr ErrAuthenticationFailed
+		g.Expect(errors.As(err, &authErr)).To(BeTrue())
+		g.Expect(authErr.StatusCode).To(Equal(http.StatusUnauthorized))
+
+		_, err = ProbeRemote(context.TODO(), server.URL+"/repo.git", &AuthOptions{
+			Transport: HTTP,
+			Username:  "user",
+			Password:  "pass",
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		// The credentials of the URL are used, and redacted in the errors.
+		u, err := url.Parse(server.URL)
+		g.Expect(err).ToNot(HaveOccurred())
+		u.User = url.UserPassword("user", "pass")
+		_, err = ProbeRemote(context.TODO(), u.String()+"/repo.git", nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		u.User = url.UserPassword("user", "wrong")
+		_, err = ProbeRemote(context.TODO(), u.String()+"/repo.git", nil)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(err.Error()).ToNot(ContainSubstring("wrong"))
+	})
+
+	t.Run("repository not found", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(&gitServer{})
+		defer server.Close()
+
+		_, err := ProbeRemote(context.TODO(), server.URL+"/missing.git", nil)
+		var notFound ErrRepositoryNotFound
+		g.Expect(errors.As(err, &notFound)).To(BeTrue())
+		g.Expect(notFound.URL).To(Equal(server.URL + "/missing.git"))
+	})
+
+	t.Run("proxy", func(t *testing.T) {
+		g := NewWithT(t)
+
+		server := httptest.NewServer(&gitServer{})
+		defer server.Close()
+		target, err := url.Parse(server.URL)
+		g.Expect(err).ToNot(HaveOccurred())
+		var proxied atomic.Int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Add(1)
+			httputil.NewSingleHostReverseProxy(target).ServeHTTP(w, r)
+		}))
+		defer proxy.Close()
+		proxyURL, err := url.Parse(proxy.URL)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		result, err := ProbeRemote(context.TODO(), server.URL+"/repo.git", nil, WithProbeProxy(proxyURL))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.Head).To(Equal(head))
+		g.Expect(proxied.Load()).To(Equal(int32(2)))
+	})
+
+	t.Run("unsupported transport", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := ProbeRemote(context.TODO(), "ssh://git@example.com/repo.git", nil)
+		g.Expect(err).To(MatchError("unsupported transport 'ssh': only HTTP(S) remotes can be probed"))
+	})
+}
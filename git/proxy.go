@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// defaultSSHPort is the port of the SSH URLs without a port.
+const defaultSSHPort = "22"
+
+// ProxyConfig is the configuration of the proxies of the remote operations,
+// e.g. from the HTTP_PROXY, HTTPS_PROXY, ALL_PROXY and NO_PROXY environment
+// variables. The proxy of a URL is selected with the semantics of the Go
+// standard library, for all the transports.
+type ProxyConfig struct {
+	// HTTPProxy is the URL of the proxy of the http URLs.
+	HTTPProxy string
+	// HTTPSProxy is the URL of the proxy of the https URLs, and of the ssh
+	// URLs if AllProxy is empty, through HTTP CONNECT.
+	HTTPSProxy string
+	// AllProxy is the URL of the proxy of the ssh URLs, e.g. a SOCKS5
+	// proxy.
+	AllProxy string
+	// NoProxy is the comma-separated list of the hosts which are not
+	// proxied. An entry is either an IP address, a CIDR range, a domain
+	// name matching the domain and its subdomains, a domain name with a
+	// leading dot matching only the subdomains, or "*" matching all the
+	// hosts. An entry with a port only matches the URLs with this port.
+	// The loopback addresses are never proxied.
+	NoProxy string
+}
+
+// ProxyConfigFromEnvironment returns the ProxyConfig of the HTTP_PROXY,
+// HTTPS_PROXY, ALL_PROXY and NO_PROXY environment variables, or their
+// lowercase versions.
+func ProxyConfigFromEnvironment() *ProxyConfig {
+	env := httpproxy.FromEnvironment()
+	return &ProxyConfig{
+		HTTPProxy:  env.HTTPProxy,
+		HTTPSProxy: env.HTTPSProxy,
+		AllProxy:   getEnvAny("ALL_PROXY", "all_proxy"),
+		NoProxy:    env.NoProxy,
+	}
+}
+
+// ProxyForURL returns the URL of the proxy of the given repository URL, or
+// nil if it's not proxied. The repository URL can be an http, https or ssh
+// URL, or an SCP-like address, e.g. git@github.com:org/repo.
+func (c *ProxyConfig) ProxyForURL(repoURL string) (*url.URL, error) {
+	u, err := proxyRequestURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	config := &httpproxy.Config{
+		HTTPProxy:  c.HTTPProxy,
+		HTTPSProxy: c.HTTPSProxy,
+		NoProxy:    c.NoProxy,
+	}
+	if u.Scheme == "ssh" {
+		// The ssh URLs are matched as https URLs with the port of the SSH
+		// server, so that the entries of NoProxy with a port apply.
+		u.Scheme = "https"
+		if c.AllProxy != "" {
+			config.HTTPSProxy = c.AllProxy
+		}
+	}
+	return config.ProxyFunc()(u)
+}
+
+// proxyRequestURL returns the URL of the given repository URL the proxy is
+// selected with.
+func proxyRequestURL(repoURL string) (*url.URL, error) {
+	u, err := ParseURL(repoURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https":
+		return &url.URL{Scheme: u.Scheme, Host: u.Host}, nil
+	case "ssh":
+		port := u.Port()
+		if port == "" {
+			port = defaultSSHPort
+		}
+		return &url.URL{Scheme: "ssh", Host: net.JoinHostPort(u.Hostname(), port)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported scheme '%s' of repository URL '%s'", u.Scheme, repoURL)
+	}
+}
+
+func getEnvAny(names ...string) string {
+	for _, n := range names {
+		if v := os.Getenv(n); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestProxyConfig_ProxyForURL(t *testing.T) {
+	config := &ProxyConfig{
+		HTTPProxy:  "http://http-proxy:3128",
+		HTTPSProxy: "http://https-proxy:3128",
+		NoProxy:    "10.0.0.0/8,192.168.1.1,example.com,.internal.org,git.corp.com:8443,ssh.corp.com:2222",
+	}
+
+	tests := []struct {
+		name    string
+		config  *ProxyConfig
+		repoURL string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "http URL",
+			config:  config,
+			repoURL: "http://github.com/org/repo",
+			want:    "http://http-proxy:3128",
+		},
+		{
+			name:    "https URL",
+			config:  config,
+			repoURL: "https://github.com/org/repo",
+			want:    "http://https-proxy:3128",
+		},
+		{
+			name:    "IP in CIDR range",
+			config:  config,
+			repoURL: "https://10.1.2.3/org/repo",
+		},
+		{
+			name:    "IP not in CIDR range",
+			config:  config,
+			repoURL: "https://11.1.2.3/org/repo",
+			want:    "http://https-proxy:3128",
+		},
+		{
+			name:    "IP",
+			config:  config,
+			repoURL: "https://192.168.1.1/org/repo",
+		},
+		{
+			name:    "domain",
+			config:  config,
+			repoURL: "https://example.com/org/repo",
+		},
+		{
+			name:    "subdomain of domain",
+			config:  config,
+			repoURL: "https://git.example.com/org/repo",
+		},
+		{
+			name:    "domain with leading dot",
+			config:  config,
+			repoURL: "https://internal.org/org/repo",
+			want:    "http://https-proxy:3128",
+		},
+		{
+			name:    "subdomain of domain with leading dot",
+			config:  config,
+			repoURL: "https://git.internal.org/org/repo",
+		},
+		{
+			name:    "domain and port",
+			config:  config,
+			repoURL: "https://git.corp.com:8443/org/repo",
+		},
+		{
+			name:    "domain and other port",
+			config:  config,
+			repoURL: "https://git.corp.com/org/repo",
+			want:    "http://https-proxy:3128",
+		},
+		{
+			name:    "loopback",
+			config:  config,
+			repoURL: "https://localhost/org/repo",
+		},
+		{
+			name:    "ssh URL",
+			config:  config,
+			repoURL: "ssh://git@github.com/org/repo",
+			want:    "http://https-proxy:3128",
+		},
+		{
+			name:    "ssh URL and port",
+			config:  config,
+			repoURL: "ssh://git@ssh.corp.com:2222/org/repo",
+		},
+		{
+			name:    "ssh URL and default port",
+			config:  config,
+			repoURL: "ssh://git@ssh.corp.com/org/repo",
+			want:    "http://https-proxy:3128",
+		},
+		{
+			name:    "SCP-like address",
+			config:  config,
+			repoURL: "git@git.example.com:org/repo",
+		},
+		{
+			name: "ssh URL with all proxy",
+			config: &ProxyConfig{
+				HTTPSProxy: "http://https-proxy:3128",
+				AllProxy:   "socks5://socks-proxy:1080",
+			},
+			repoURL: "git@github.com:org/repo",
+			want:    "socks5://socks-proxy:1080",
+		},
+		{
+			name:    "wildcard",
+			config:  &ProxyConfig{HTTPSProxy: "http://https-proxy:3128", NoProxy: "*"},
+			repoURL: "https://github.com/org/repo",
+		},
+		{
+			name:    "no proxy",
+			config:  &ProxyConfig{},
+			repoURL: "https://github.com/org/repo",
+		},
+		{
+			name:    "unsupported scheme",
+			config:  config,
+			repoURL: "file:///tmp/repo",
+			wantErr: "unsupported scheme 'file'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := tt.config.ProxyForURL(tt.repoURL)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			if tt.want == "" {
+				g.Expect(got).To(BeNil())
+				return
+			}
+			g.Expect(got).ToNot(BeNil())
+			g.Expect(got.String()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestProxyConfigFromEnvironment(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv("HTTPS_PROXY", "http://https-proxy:3128")
+	t.Setenv("all_proxy", "socks5://socks-proxy:1080")
+	t.Setenv("NO_PROXY", "example.com")
+
+	config := ProxyConfigFromEnvironment()
+	g.Expect(config.HTTPSProxy).To(Equal("http://https-proxy:3128"))
+	g.Expect(config.AllProxy).To(Equal("socks5://socks-proxy:1080"))
+	g.Expect(config.NoProxy).To(Equal("example.com"))
+}
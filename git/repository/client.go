@@ -29,8 +29,16 @@ type Reader interface {
 	// It returns a Commit object describing the Git commit that the repository
 	// HEAD points to. If the repository is empty, it returns a nil Commit.
 	Clone(ctx context.Context, url string, cfg CloneConfig) (*git.Commit, error)
+	// ListRemote lists the branches and tags of the repository at the
+	// provided url, without cloning it. When patterns are provided, only
+	// the references whose name, or the tail of it, matches one of the
+	// patterns are listed, like with `git ls-remote`. For example, "main"
+	// matches "refs/heads/main", and "v1.*" matches "refs/tags/v1.0.0".
+	ListRemote(ctx context.Context, url string, patterns ...string) ([]git.Reference, error)
 	// IsClean returns whether the working tree is clean.
 	IsClean() (bool, error)
+	// Status returns the changes in the working tree compared to HEAD.
+	Status() (*git.WorktreeStatus, error)
 	// Head returns the hash of the current HEAD of the repo.
 	Head() (string, error)
 	// Path returns the path of the repository.
@@ -54,6 +62,11 @@ type Writer interface {
 	// Commit commits any changes made to the repository. commitOpts is an
 	// optional argument which can be provided to configure the commit.
 	Commit(info git.Commit, commitOpts ...CommitOption) (string, error)
+	// Tag creates a tag with the provided name pointing to HEAD, and
+	// returns the hash of the tag, which is the hash of the commit for
+	// a lightweight tag. tagOpts is an optional argument which can be
+	// provided to make an annotated or signed tag, or to push the tag.
+	Tag(ctx context.Context, name string, tagOpts ...TagOption) (string, error)
 	Closer
 }
 
@@ -18,8 +18,12 @@ package repository
 
 import (
 	"io"
+	"time"
 
 	"github.com/ProtonMail/go-crypto/openpgp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/fluxcd/pkg/git"
 )
 
 const (
@@ -52,6 +56,18 @@ type CloneConfig struct {
 	// not supported by all implementations
 	ShallowClone bool
 
+	// Depth limits the history of the clone of a branch, tag or reference
+	// name to the given number of commits. It takes precedence over
+	// ShallowClone, which is equivalent to a Depth of 1. Not supported by all
+	// implementations.
+	Depth int
+
+	// ShallowSince limits the history of the clone of a branch, tag or
+	// reference name to the commits committed after the given time. The
+	// history may contain older commits, but contains at least all the
+	// commits after the time. Not supported by all implementations.
+	ShallowSince time.Time
+
 	// SparseCheckoutDirectories defines a list of directories to sparse-checkout
 	// when cloning the repository. If provided, only listed directories are checked out.
 	SparseCheckoutDirectories []string
@@ -62,11 +78,31 @@ type PushConfig struct {
 	// Refspecs is a list of refspecs to use for the push operation.
 	// For details about Git Refspecs, please see:
 	// https://git-scm.com/book/en/v2/Git-Internals-The-Refspec
+	// The source can be HEAD, e.g. "HEAD:refs/heads/target", and the
+	// destination can be omitted to delete a remote ref, e.g.
+	// ":refs/heads/target". A destination which is not a full ref name
+	// is a branch, or a tag if the source is a tag.
 	Refspecs []string
 
 	// Force, if set to true, will result in a force push.
 	Force bool
 
+	// ForceWithLease, if set to true, will result in a force push of the
+	// refs which is rejected if a remote ref is not at its expected commit,
+	// e.g. because it was updated since it was last fetched. The lease is
+	// checked against the refs advertised by the remote in the push session.
+	// The expected commit of a remote ref is the one of Leases if set, or
+	// the one of the remote-tracking ref of the pushed local branch
+	// otherwise, which must exist. The sources of the refspecs must be
+	// branches or tags, and not commit hashes.
+	ForceWithLease bool
+
+	// Leases maps a remote ref name, e.g. "refs/heads/main", to the commit
+	// hash it is expected to be at when pushing with ForceWithLease. At most
+	// one lease can be set, in which case the push must have a single
+	// refspec updating the ref of the lease.
+	Leases map[string]string
+
 	// Options is a map specifying the push options that are sent
 	// to the Git server when performing a push option. For details, see:
 	// https://git-scm.com/docs/git-push#Documentation/git-push.txt---push-optionltoptiongt
@@ -85,13 +121,30 @@ type CheckoutStrategy struct {
 	// SemVer tag expression to checkout, takes precedence over Branch and Tag.
 	SemVer string `json:"semver,omitempty"`
 
+	// TagPrefix restricts the tags matched against SemVer to the tags with
+	// the prefix, which is trimmed before parsing the version.
+	// Example: "component-a/" to match "component-a/v1.2.0" as "v1.2.0".
+	TagPrefix string `json:"tagPrefix,omitempty"`
+
+	// SemVerFilter is a regular expression restricting the tags matched
+	// against SemVer to the tags it matches. If it has a capturing group, the
+	// version is parsed from the first group rather than the tag name.
+	// Example: "^component-a/(v.*)$"
+	SemVerFilter string `json:"semverFilter,omitempty"`
+
 	// RefName is the reference to checkout to. It must conform to the
 	// Git reference format: https://git-scm.com/book/en/v2/Git-Internals-Git-References
 	// Examples: "refs/heads/main", "refs/pull/420/head", "refs/tags/v0.1.0"
-	// It takes precedence over Branch, Tag and SemVer.
+	// It takes precedence over Branch, Tag, SemVer and Change.
 	RefName string
 
-	// Commit SHA1 to checkout, takes precedence over all the other options.
+	// Change is the Gerrit change to checkout, either as "<change>" for its
+	// latest patchset, or as "<change>/<patchset>".
+	// Examples: "1234", "1234/2"
+	// It takes precedence over Branch, Tag and SemVer.
+	Change string
+
+	// Commit SHA1 or SHA256 to checkout, takes precedence over all the other options.
 	// If supported by the client, it can be combined with Branch.
 	Commit string
 }
@@ -100,6 +153,10 @@ type CheckoutStrategy struct {
 type CommitOptions struct {
 	// Signer can be used to sign a commit using OpenPGP.
 	Signer *openpgp.Entity
+	// SSHSigner can be used to sign a commit using an SSH key, in the
+	// format of Git's `gpg.format ssh`. It is mutually exclusive with
+	// Signer.
+	SSHSigner ssh.Signer
 	// Files contains file names mapped to the file's content.
 	// Its used to write files which are then included in the commit.
 	Files map[string]io.Reader
@@ -116,6 +173,14 @@ func WithSigner(signer *openpgp.Entity) CommitOption {
 	}
 }
 
+// WithSSHSigner allows for the commit to be signed using the provided
+// SSH signer.
+func WithSSHSigner(signer ssh.Signer) CommitOption {
+	return func(co *CommitOptions) {
+		co.SSHSigner = signer
+	}
+}
+
 // WithFiles instructs the Git client to write the provided files and include
 // them in the commit.
 // files contains file names as its key and the content of the file as the
@@ -125,3 +190,79 @@ func WithFiles(files map[string]io.Reader) CommitOption {
 		co.Files = files
 	}
 }
+
+// TagOptions provides options to configure a Git tag operation.
+type TagOptions struct {
+	// Target is the commit hash, or the branch, tag or reference name of
+	// the commit to tag. Defaults to HEAD.
+	Target string
+	// Tagger is the signature of the creator of an annotated tag. Its
+	// When defaults to the current time.
+	Tagger git.Signature
+	// Message is the message of an annotated tag. The tag is an annotated
+	// tag if it's set, and a lightweight tag otherwise.
+	Message string
+	// Signer can be used to sign an annotated tag using OpenPGP.
+	Signer *openpgp.Entity
+	// SSHSigner can be used to sign an annotated tag using an SSH key, in
+	// the format of Git's `gpg.format ssh`. It is mutually exclusive with
+	// Signer.
+	SSHSigner ssh.Signer
+	// Force replaces the tag if it already exists, locally and, when
+	// pushed, at the origin.
+	Force bool
+	// Push pushes the tag to the origin after creating it.
+	Push bool
+}
+
+// TagOption defines an option for a tag operation.
+type TagOption func(*TagOptions)
+
+// WithTagTarget sets the commit hash, or the branch, tag or reference name of
+// the commit to tag, instead of HEAD.
+func WithTagTarget(target string) TagOption {
+	return func(to *TagOptions) {
+		to.Target = target
+	}
+}
+
+// WithTagMessage makes the tag an annotated tag with the provided tagger and
+// message.
+func WithTagMessage(tagger git.Signature, message string) TagOption {
+	return func(to *TagOptions) {
+		to.Tagger = tagger
+		to.Message = message
+	}
+}
+
+// WithTagSigner allows for the annotated tag to be signed using the provided
+// OpenPGP signer.
+func WithTagSigner(signer *openpgp.Entity) TagOption {
+	return func(to *TagOptions) {
+		to.Signer = signer
+	}
+}
+
+// WithTagSSHSigner allows for the annotated tag to be signed using the
+// provided SSH signer.
+func WithTagSSHSigner(signer ssh.Signer) TagOption {
+	return func(to *TagOptions) {
+		to.SSHSigner = signer
+	}
+}
+
+// WithTagForce instructs the Git client to replace the tag if it already
+// exists.
+func WithTagForce() TagOption {
+	return func(to *TagOptions) {
+		to.Force = true
+	}
+}
+
+// WithTagPush instructs the Git client to push the tag to the origin after
+// creating it.
+func WithTagPush() TagOption {
+	return func(to *TagOptions) {
+		to.Push = true
+	}
+}
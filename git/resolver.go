@@ -0,0 +1,210 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// CredentialsResolver resolves the credentials to use for a Git repository
+// URL, e.g. from a configuration shared by all the objects of a controller.
+// It returns nil credentials if it has none for the URL.
+type CredentialsResolver interface {
+	ResolveCredentials(ctx context.Context, u url.URL) (*Credentials, error)
+}
+
+// CredentialsResolverFunc is a function implementing CredentialsResolver.
+type CredentialsResolverFunc func(ctx context.Context, u url.URL) (*Credentials, error)
+
+// ResolveCredentials calls the function.
+func (f CredentialsResolverFunc) ResolveCredentials(ctx context.Context, u url.URL) (*Credentials, error) {
+	return f(ctx, u)
+}
+
+// ResolveAuthOptions constructs an AuthOptions object like NewAuthOptions.
+// For HTTP and HTTPS URLs, if neither the data nor the URL contain any
+// credentials, the credentials are resolved with the given resolver.
+// Repositories for which the resolver has no credentials are accessed
+// anonymously.
+func ResolveAuthOptions(ctx context.Context, u url.URL, data map[string][]byte, resolver CredentialsResolver) (*AuthOptions, error) {
+	opts, err := NewAuthOptions(u, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolver == nil || opts.Transport == SSH ||
+		opts.Username != "" || opts.Password != "" || opts.BearerToken != "" {
+		return opts, nil
+	}
+
+	creds, err := resolver.ResolveCredentials(ctx, u)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials for '%s': %w", u.Host, err)
+	}
+	if creds == nil {
+		return opts, nil
+	}
+	opts.Username = creds.Username
+	opts.Password = creds.Password
+	opts.BearerToken = creds.BearerToken
+
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+// NetrcResolver is a CredentialsResolver reading the credentials from
+// .netrc files, e.g. mounted from a Secret. The files are read on each
+// resolution, so that rotated credentials are picked up without a restart.
+type NetrcResolver struct {
+	paths []string
+}
+
+// NewNetrcResolver returns a NetrcResolver reading the given .netrc files.
+// The entries of the first files take precedence.
+func NewNetrcResolver(paths ...string) *NetrcResolver {
+	return &NetrcResolver{paths: paths}
+}
+
+// ResolveCredentials returns the login and password of the machine entry
+// matching the host of the URL, with or without its port, or of the default
+// entry. Files which do not exist are ignored.
+func (r *NetrcResolver) ResolveCredentials(_ context.Context, u url.URL) (*Credentials, error) {
+	var fallback *netrcEntry
+	for _, path := range r.paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		entries, err := parseNetrc(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid netrc file '%s': %w", path, err)
+		}
+
+		for _, host := range []string{u.Host, u.Hostname()} {
+			for i := range entries {
+				if entries[i].machine == host {
+					return entries[i].credentials(), nil
+				}
+			}
+		}
+		if fallback == nil {
+			for i := range entries {
+				if entries[i].isDefault {
+					fallback = &entries[i]
+					break
+				}
+			}
+		}
+	}
+	if fallback != nil {
+		return fallback.credentials(), nil
+	}
+	return nil, nil
+}
+
+// netrcEntry is a machine or default entry of a .netrc file.
+type netrcEntry struct {
+	machine   string
+	isDefault bool
+	login     string
+	password  string
+}
+
+func (e *netrcEntry) credentials() *Credentials {
+	return &Credentials{
+		Username: e.login,
+		Password: e.password,
+	}
+}
+
+// parseNetrc parses the entries of a .netrc file. The account and macdef
+// tokens are ignored.
+func parseNetrc(data []byte) ([]netrcEntry, error) {
+	var entries []netrcEntry
+	var current *netrcEntry
+
+	var tokens []string
+	inMacro := false
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// A macro definition ends with an empty line.
+		if inMacro {
+			if strings.TrimSpace(line) == "" {
+				inMacro = false
+			}
+			continue
+		}
+		line, _, _ = strings.Cut(line, "#")
+		for _, field := range strings.Fields(line) {
+			if field == "macdef" {
+				inMacro = true
+				break
+			}
+			tokens = append(tokens, field)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "default":
+			entries = append(entries, netrcEntry{isDefault: true})
+			current = &entries[len(entries)-1]
+			continue
+		case "machine", "login", "password", "account":
+		default:
+			return nil, fmt.Errorf("unexpected token '%s'", tokens[i])
+		}
+
+		if i+1 >= len(tokens) {
+			return nil, fmt.Errorf("missing value for '%s'", tokens[i])
+		}
+		key, value := tokens[i], tokens[i+1]
+		i++
+
+		if key == "machine" {
+			entries = append(entries, netrcEntry{machine: value})
+			current = &entries[len(entries)-1]
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("'%s' outside of a machine entry", key)
+		}
+		switch key {
+		case "login":
+			current.login = value
+		case "password":
+			current.password = value
+		}
+	}
+	return entries, nil
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package git
 
 import (
+	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
@@ -83,6 +85,9 @@ func TransformRevision(rev string) string {
 //   - HEAD/5394cb7f48332b2de7c17dd8b8384bbc84b7e738
 //   - 5394cb7f48332b2de7c17dd8b8384bbc84b7e738
 //
+// The hash can also be a SHA256 hash, e.g.
+// main@sha256:6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8.
+//
 // If the revision string does not contain a named pointer, the returned
 // string will be empty.
 func SplitRevision(rev string) (string, Hash) {
@@ -133,3 +138,85 @@ func ExtractHashFromRevision(rev string) Hash {
 	}
 	return Hash(rev)
 }
+
+// ParseURL parses the URL of a Git repository. The SCP-like addresses of the
+// "[user@]host:path" form are returned as ssh URLs, e.g.
+// "git@github.com:org/repo.git" as "ssh://git@github.com/org/repo.git".
+func ParseURL(repoURL string) (*url.URL, error) {
+	if strings.Contains(repoURL, "://") {
+		u, err := url.Parse(repoURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository URL '%s': %w", repoURL, err)
+		}
+		return u, nil
+	}
+
+	var user string
+	rest := repoURL
+	if i := strings.Index(rest, "@"); i >= 0 && !strings.ContainsAny(rest[:i], ":/") {
+		user, rest = rest[:i], rest[i+1:]
+	}
+
+	var host, path string
+	if strings.HasPrefix(rest, "[") {
+		end := strings.Index(rest, "]")
+		if end < 0 || !strings.HasPrefix(rest[end+1:], ":") {
+			return nil, fmt.Errorf("invalid repository URL '%s'", repoURL)
+		}
+		host, path = rest[:end+1], rest[end+2:]
+	} else {
+		var ok bool
+		if host, path, ok = strings.Cut(rest, ":"); !ok {
+			return nil, fmt.Errorf("invalid repository URL '%s'", repoURL)
+		}
+	}
+	if host == "" || strings.Contains(host, "/") {
+		return nil, fmt.Errorf("invalid repository URL '%s'", repoURL)
+	}
+
+	u := &url.URL{Scheme: "ssh", Host: host, Path: "/" + strings.TrimPrefix(path, "/")}
+	if user != "" {
+		u.User = url.User(user)
+	}
+	// The URL is parsed again to validate the host.
+	parsed, err := url.Parse(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL '%s': %w", repoURL, err)
+	}
+	return parsed, nil
+}
+
+// ValidateRefName returns an error if the name isn't a valid Git reference
+// name according to the rules of git-check-ref-format, e.g.
+// "refs/heads/main".
+func ValidateRefName(name string) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("invalid reference name '%s': %s", name, reason)
+	}
+
+	switch {
+	case name == "", name == "@":
+		return invalid("reserved or empty name")
+	case strings.HasPrefix(name, "/") || strings.HasSuffix(name, "/"):
+		return invalid("cannot begin or end with a slash")
+	case strings.HasSuffix(name, "."):
+		return invalid("cannot end with a dot")
+	case strings.Contains(name, ".."):
+		return invalid("cannot contain '..'")
+	case strings.Contains(name, "@{"):
+		return invalid("cannot contain '@{'")
+	case strings.Contains(name, "//"):
+		return invalid("cannot contain consecutive slashes")
+	}
+	for _, r := range name {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(" ~^:?*[\\", r) {
+			return invalid(fmt.Sprintf("cannot contain %q", r))
+		}
+	}
+	for _, component := range strings.Split(name, "/") {
+		if strings.HasPrefix(component, ".") || strings.HasSuffix(component, ".lock") {
+			return invalid("components cannot begin with a dot or end with '.lock'")
+		}
+	}
+	return nil
+}
@@ -87,6 +87,16 @@ func TestTransformRevision(t *testing.T) {
 			rev:  "HEAD/5394cb7f48332b2de7c17dd8b8384bbc84b7e738",
 			want: "sha1:5394cb7f48332b2de7c17dd8b8384bbc84b7e738",
 		},
+		{
+			name: "revision with branch and SHA256 digest",
+			rev:  "main@sha256:6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8",
+			want: "main@sha256:6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8",
+		},
+		{
+			name: "legacy revision with branch and SHA256 hash",
+			rev:  "main/6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8",
+			want: "main@sha256:6ee9a7ade2ca791bc1bf9d133ef6ddaa9097cf521e6a19be92dbcc3f2e82f6d8",
+		},
 		{
 			name: "empty revision",
 			rev:  "",
@@ -268,3 +278,115 @@ func TestExtractHashFromRevision(t *testing.T) {
 		})
 	}
 }
+
+func TestParseURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "https URL",
+			url:  "https://github.com/fluxcd/flux2",
+			want: "https://github.com/fluxcd/flux2",
+		},
+		{
+			name: "ssh URL",
+			url:  "ssh://git@github.com:22/fluxcd/flux2.git",
+			want: "ssh://git@github.com:22/fluxcd/flux2.git",
+		},
+		{
+			name: "SCP-like address",
+			url:  "git@github.com:fluxcd/flux2.git",
+			want: "ssh://git@github.com/fluxcd/flux2.git",
+		},
+		{
+			name: "SCP-like address without user",
+			url:  "github.com:/fluxcd/flux2.git",
+			want: "ssh://github.com/fluxcd/flux2.git",
+		},
+		{
+			name: "SCP-like address with IPv6 host",
+			url:  "git@[::1]:fluxcd/flux2.git",
+			want: "ssh://git@[::1]/fluxcd/flux2.git",
+		},
+		{
+			name: "SCP-like address of Azure DevOps",
+			url:  "git@ssh.dev.azure.com:v3/org/project/repo",
+			want: "ssh://git@ssh.dev.azure.com/v3/org/project/repo",
+		},
+		{
+			name:    "local path",
+			url:     "./repo:name",
+			wantErr: "invalid repository URL './repo:name'",
+		},
+		{
+			name:    "no host",
+			url:     "git@:fluxcd/flux2.git",
+			wantErr: "invalid repository URL 'git@:fluxcd/flux2.git'",
+		},
+		{
+			name:    "no path separator",
+			url:     "github.com",
+			wantErr: "invalid repository URL 'github.com'",
+		},
+		{
+			name:    "unterminated IPv6 host",
+			url:     "git@[::1:fluxcd/flux2.git",
+			wantErr: "invalid repository URL 'git@[::1:fluxcd/flux2.git'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			u, err := ParseURL(tt.url)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(u.String()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestValidateRefName(t *testing.T) {
+	tests := []struct {
+		name    string
+		ref     string
+		wantErr bool
+	}{
+		{name: "branch", ref: "refs/heads/main"},
+		{name: "short name with slash", ref: "feature/branch"},
+		{name: "tag", ref: "refs/tags/v1.0.0"},
+		{name: "at sign in component", ref: "refs/heads/@"},
+		{name: "empty", ref: "", wantErr: true},
+		{name: "at sign", ref: "@", wantErr: true},
+		{name: "leading slash", ref: "/main", wantErr: true},
+		{name: "trailing slash", ref: "main/", wantErr: true},
+		{name: "trailing dot", ref: "main.", wantErr: true},
+		{name: "double dot", ref: "main..dev", wantErr: true},
+		{name: "reflog syntax", ref: "main@{1}", wantErr: true},
+		{name: "consecutive slashes", ref: "feature//branch", wantErr: true},
+		{name: "space", ref: "feature branch", wantErr: true},
+		{name: "control character", ref: "main\x00", wantErr: true},
+		{name: "glob", ref: "refs/heads/*", wantErr: true},
+		{name: "colon", ref: "main:dev", wantErr: true},
+		{name: "component beginning with a dot", ref: "refs/heads/.hidden", wantErr: true},
+		{name: "lock file", ref: "refs/heads/main.lock", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := ValidateRefName(tt.ref)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+// WithOpenPGPKeyRings configures the armored OpenPGP key rings trusted to
+// sign the objects.
+func WithOpenPGPKeyRings(keyRings ...string) Option {
+	return func(v *Verifier) error {
+		for _, r := range keyRings {
+			if _, err := openpgp.ReadArmoredKeyRing(strings.NewReader(r)); err != nil {
+				return fmt.Errorf("unable to read armored key ring: %w", err)
+			}
+		}
+		v.openPGPKeyRings = append(v.openPGPKeyRings, keyRings...)
+		return nil
+	}
+}
+
+// verifyOpenPGP verifies the armored OpenPGP signature of the payload with
+// the first key ring containing the signing key.
+func verifyOpenPGP(keyRings []string, sig string, payload []byte) (*Result, error) {
+	for _, r := range keyRings {
+		keyRing, err := openpgp.ReadArmoredKeyRing(strings.NewReader(r))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read armored key ring: %w", err)
+		}
+		signer, err := openpgp.CheckArmoredDetachedSignature(keyRing, bytes.NewReader(payload), strings.NewReader(sig), nil)
+		if err != nil {
+			continue
+		}
+		var identities []string
+		for name := range signer.Identities {
+			identities = append(identities, name)
+		}
+		sort.Strings(identities)
+		return &Result{
+			Type:       SignatureTypeOpenPGP,
+			KeyID:      signer.PrimaryKey.KeyIdString(),
+			Identities: identities,
+		}, nil
+	}
+	return nil, errors.New("unable to verify the OpenPGP signature with any of the given key rings")
+}
@@ -0,0 +1,279 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// sshSigMagic is the preamble of the SSH signatures.
+	sshSigMagic = "SSHSIG"
+	// sshSigVersion is the version of the SSH signature format.
+	sshSigVersion = 1
+	// sshSigNamespace is the namespace of the SSH signatures of Git
+	// objects.
+	sshSigNamespace = "git"
+	// sshSigPEMType is the type of the PEM block of an armored SSH
+	// signature.
+	sshSigPEMType = "SSH SIGNATURE"
+)
+
+// AllowedSigner is an entry of an SSH allowed signers file, as used by
+// Git's `gpg.ssh.allowedSignersFile`.
+//
+// Ref: https://man.openbsd.org/ssh-keygen#ALLOWED_SIGNERS
+type AllowedSigner struct {
+	// Principals are the principals of the key, e.g. email addresses.
+	Principals []string
+	// Key is the public key of the signer.
+	Key ssh.PublicKey
+	// Namespaces are the namespaces the key is allowed to sign for, or
+	// empty for all the namespaces.
+	Namespaces []string
+	// ValidAfter is the time after which the key is valid, if set.
+	ValidAfter time.Time
+	// ValidBefore is the time before which the key is valid, if set.
+	ValidBefore time.Time
+}
+
+// WithSSHAllowedSigners configures the SSH keys trusted to sign the objects,
+// in the format of an SSH allowed signers file.
+func WithSSHAllowedSigners(allowedSigners string) Option {
+	return func(v *Verifier) error {
+		signers, err := ParseAllowedSigners(allowedSigners)
+		if err != nil {
+			return err
+		}
+		v.sshSigners = append(v.sshSigners, signers...)
+		return nil
+	}
+}
+
+// ParseAllowedSigners parses the entries of an SSH allowed signers file.
+// The certificate authority entries are not supported.
+func ParseAllowedSigners(data string) ([]AllowedSigner, error) {
+	var signers []AllowedSigner
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		signer, err := parseAllowedSigner(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed signer on line %d: %w", n, err)
+		}
+		signers = append(signers, *signer)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(signers) == 0 {
+		return nil, errors.New("no allowed signers found")
+	}
+	return signers, nil
+}
+
+func parseAllowedSigner(line string) (*AllowedSigner, error) {
+	principals, rest, ok := cutPrincipals(line)
+	if !ok {
+		return nil, errors.New("missing key")
+	}
+	key, _, options, _, err := ssh.ParseAuthorizedKey([]byte(rest))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse key: %w", err)
+	}
+
+	signer := &AllowedSigner{
+		Principals: strings.Split(principals, ","),
+		Key:        key,
+	}
+	for _, opt := range options {
+		name, value, _ := strings.Cut(opt, "=")
+		value = strings.Trim(value, `"`)
+		switch strings.ToLower(name) {
+		case "cert-authority":
+			return nil, errors.New("cert-authority entries are not supported")
+		case "namespaces":
+			signer.Namespaces = strings.Split(value, ",")
+		case "valid-after":
+			if signer.ValidAfter, err = parseAllowedSignerTime(value); err != nil {
+				return nil, err
+			}
+		case "valid-before":
+			if signer.ValidBefore, err = parseAllowedSignerTime(value); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported option '%s'", name)
+		}
+	}
+	return signer, nil
+}
+
+// cutPrincipals cuts the principals, which may be quoted, from the rest of
+// an allowed signers line.
+func cutPrincipals(line string) (principals, rest string, ok bool) {
+	if strings.HasPrefix(line, `"`) {
+		end := strings.Index(line[1:], `"`)
+		if end < 0 {
+			return "", "", false
+		}
+		principals, rest = line[1:end+1], strings.TrimSpace(line[end+2:])
+		return principals, rest, rest != ""
+	}
+	principals, rest, ok = strings.Cut(line, " ")
+	return principals, strings.TrimSpace(rest), ok
+}
+
+// parseAllowedSignerTime parses a time of the YYYYMMDD[HHMM[SS]][Z] format,
+// in the local time zone unless suffixed with Z.
+func parseAllowedSignerTime(value string) (time.Time, error) {
+	loc := time.Local
+	if strings.HasSuffix(value, "Z") {
+		loc, value = time.UTC, strings.TrimSuffix(value, "Z")
+	}
+	for _, layout := range []string{"20060102", "200601021504", "20060102150405"} {
+		if len(value) == len(layout) {
+			return time.ParseInLocation(layout, value, loc)
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time '%s'", value)
+}
+
+// allows returns true if the signer is allowed to sign Git objects at the
+// given time.
+func (s AllowedSigner) allows(signedAt time.Time) bool {
+	if len(s.Namespaces) > 0 && !slices.Contains(s.Namespaces, sshSigNamespace) {
+		return false
+	}
+	if !s.ValidAfter.IsZero() && signedAt.Before(s.ValidAfter) {
+		return false
+	}
+	if !s.ValidBefore.IsZero() && signedAt.After(s.ValidBefore) {
+		return false
+	}
+	return true
+}
+
+// sshSigBlob is the SSH signature, without its magic preamble.
+type sshSigBlob struct {
+	Version       uint32
+	PublicKey     string
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     string
+}
+
+// sshSigSignedData is the data signed by the SSH key.
+type sshSigSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          string
+}
+
+// verifySSH verifies the armored SSH signature of the payload, and that its
+// key is one of the allowed signers at the time of the signature.
+//
+// Ref: https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+func verifySSH(signers []AllowedSigner, sig string, payload []byte, signedAt time.Time) (*Result, error) {
+	block, _ := pem.Decode([]byte(sig))
+	if block == nil || block.Type != sshSigPEMType {
+		return nil, errors.New("unable to decode the SSH signature")
+	}
+	data, ok := bytes.CutPrefix(block.Bytes, []byte(sshSigMagic))
+	if !ok {
+		return nil, errors.New("invalid SSH signature preamble")
+	}
+	var blob sshSigBlob
+	if err := ssh.Unmarshal(data, &blob); err != nil {
+		return nil, fmt.Errorf("unable to parse the SSH signature: %w", err)
+	}
+	if blob.Version != sshSigVersion {
+		return nil, fmt.Errorf("unsupported SSH signature version %d", blob.Version)
+	}
+	if blob.Namespace != sshSigNamespace {
+		return nil, fmt.Errorf("invalid SSH signature namespace '%s'", blob.Namespace)
+	}
+
+	var h hash.Hash
+	switch blob.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return nil, fmt.Errorf("unsupported SSH signature hash algorithm '%s'", blob.HashAlgorithm)
+	}
+	h.Write(payload)
+
+	key, err := ssh.ParsePublicKey([]byte(blob.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the SSH signature public key: %w", err)
+	}
+	if _, ok := key.(*ssh.Certificate); ok {
+		return nil, errors.New("SSH certificates are not supported")
+	}
+	var signature ssh.Signature
+	if err := ssh.Unmarshal([]byte(blob.Signature), &signature); err != nil {
+		return nil, fmt.Errorf("unable to parse the SSH signature: %w", err)
+	}
+	// The RSA signatures must use SHA-512 or SHA-256 rather than SHA-1.
+	if signature.Format == ssh.KeyAlgoRSA {
+		return nil, errors.New("SSH RSA signatures with SHA-1 are not supported")
+	}
+	signedData := append([]byte(sshSigMagic), ssh.Marshal(sshSigSignedData{
+		Namespace:     blob.Namespace,
+		Reserved:      blob.Reserved,
+		HashAlgorithm: blob.HashAlgorithm,
+		Hash:          string(h.Sum(nil)),
+	})...)
+	if err := key.Verify(signedData, &signature); err != nil {
+		return nil, fmt.Errorf("invalid SSH signature: %w", err)
+	}
+
+	fingerprint := ssh.FingerprintSHA256(key)
+	var identities []string
+	for _, s := range signers {
+		if bytes.Equal(s.Key.Marshal(), key.Marshal()) && s.allows(signedAt) {
+			identities = append(identities, s.Principals...)
+		}
+	}
+	if len(identities) == 0 {
+		return nil, fmt.Errorf("SSH key '%s' is not an allowed signer", fingerprint)
+	}
+	return &Result{
+		Type:       SignatureTypeSSH,
+		KeyID:      fingerprint,
+		Identities: identities,
+	}, nil
+}
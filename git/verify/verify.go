@@ -0,0 +1,153 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package verify provides the verification of the OpenPGP, SSH and x509
+// (e.g. gitsign) signatures of Git commits and tags against trust anchors.
+//
+//	verifier, err := verify.NewVerifier(
+//		verify.WithOpenPGPKeyRings(keyRing),
+//		verify.WithSSHAllowedSigners(allowedSigners),
+//	)
+//	...
+//	result, err := verifier.VerifyCommit(commit)
+//	if err != nil {
+//		return fmt.Errorf("commit %s is not trusted: %w", commit, err)
+//	}
+//	log.Info("commit verified", "type", result.Type, "key", result.KeyID, "identities", result.Identities)
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+// SignatureType is the type of the signature of a Git object.
+type SignatureType string
+
+const (
+	// SignatureTypeOpenPGP is the type of the OpenPGP signatures, e.g.
+	// created with `gpg.format openpgp`.
+	SignatureTypeOpenPGP SignatureType = "openpgp"
+	// SignatureTypeSSH is the type of the SSH signatures, e.g. created
+	// with `gpg.format ssh`.
+	SignatureTypeSSH SignatureType = "ssh"
+	// SignatureTypeX509 is the type of the x509 CMS signatures, e.g.
+	// created with `gpg.format x509` by gitsign or smimesign.
+	SignatureTypeX509 SignatureType = "x509"
+)
+
+const (
+	openPGPSignatureHeader = "-----BEGIN PGP SIGNATURE-----"
+	sshSignatureHeader     = "-----BEGIN SSH SIGNATURE-----"
+	x509SignatureHeader    = "-----BEGIN SIGNED MESSAGE-----"
+)
+
+// ErrNoSignature is returned when verifying an object without signature.
+var ErrNoSignature = errors.New("no signature")
+
+// Result is the result of a successful verification.
+type Result struct {
+	// Type is the type of the signature.
+	Type SignatureType
+	// KeyID identifies the key the signature was verified with: the key ID
+	// of the OpenPGP key, the SHA256 fingerprint of the SSH key, or the
+	// SHA256 fingerprint of the x509 certificate.
+	KeyID string
+	// Identities are the identities the key belongs to: the user IDs of the
+	// OpenPGP key, the principals of the SSH allowed signer, or the email
+	// addresses and URIs of the x509 certificate.
+	Identities []string
+	// Issuer is the OIDC issuer of the identity of a keyless x509
+	// certificate, e.g. issued by Fulcio, or the subject of the issuer of
+	// the certificate otherwise. It is empty for the other types.
+	Issuer string
+}
+
+// Verifier verifies the signatures of Git objects against the configured
+// trust anchors. A signature type without trust anchors can't be verified.
+type Verifier struct {
+	openPGPKeyRings []string
+	sshSigners      []AllowedSigner
+	x509            *x509Verifier
+}
+
+// Option configures a Verifier.
+type Option func(*Verifier) error
+
+// NewVerifier returns a Verifier configured with the given options.
+func NewVerifier(opts ...Option) (*Verifier, error) {
+	v := &Verifier{}
+	for _, opt := range opts {
+		if err := opt(v); err != nil {
+			return nil, err
+		}
+	}
+	if len(v.openPGPKeyRings) == 0 && len(v.sshSigners) == 0 && v.x509 == nil {
+		return nil, errors.New("no trust anchors configured")
+	}
+	return v, nil
+}
+
+// VerifyCommit verifies the signature of the commit. The validity of the
+// SSH allowed signers is checked at the commit time of the commit.
+func (v *Verifier) VerifyCommit(c *git.Commit) (*Result, error) {
+	res, err := v.verify(c.Signature, c.Encoded, c.Committer.When)
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify Git commit '%s': %w", c.Hash.String(), err)
+	}
+	return res, nil
+}
+
+// VerifyTag verifies the signature of the annotated tag. The validity of
+// the SSH allowed signers is checked at the time the tag was created.
+func (v *Verifier) VerifyTag(t *git.Tag) (*Result, error) {
+	res, err := v.verify(t.Signature, t.Encoded, t.Author.When)
+	if err != nil {
+		return nil, fmt.Errorf("unable to verify Git tag '%s': %w", t.Name, err)
+	}
+	return res, nil
+}
+
+// verify verifies the signature of the payload, according to the type of
+// the signature.
+func (v *Verifier) verify(sig string, payload []byte, signedAt time.Time) (*Result, error) {
+	sig = strings.TrimSpace(sig)
+	switch {
+	case sig == "":
+		return nil, ErrNoSignature
+	case strings.HasPrefix(sig, openPGPSignatureHeader):
+		if len(v.openPGPKeyRings) == 0 {
+			return nil, fmt.Errorf("no OpenPGP key rings configured to verify the %s signature", SignatureTypeOpenPGP)
+		}
+		return verifyOpenPGP(v.openPGPKeyRings, sig, payload)
+	case strings.HasPrefix(sig, sshSignatureHeader):
+		if len(v.sshSigners) == 0 {
+			return nil, fmt.Errorf("no SSH allowed signers configured to verify the %s signature", SignatureTypeSSH)
+		}
+		return verifySSH(v.sshSigners, sig, payload, signedAt)
+	case strings.HasPrefix(sig, x509SignatureHeader):
+		if v.x509 == nil {
+			return nil, fmt.Errorf("no x509 roots configured to verify the %s signature", SignatureTypeX509)
+		}
+		return v.x509.verify(sig, payload)
+	default:
+		return nil, errors.New("unknown signature type")
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	. "github.com/onsi/gomega"
+	"go.mozilla.org/pkcs7"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/fluxcd/pkg/git"
+)
+
+const testPayload = "tree 4b825dc642cb6eb9a060e54bf8d69288fbbf4904\nauthor Jane Doe <jane@example.com> 1700000000 +0000\ncommitter Jane Doe <jane@example.com> 1700000000 +0000\n\ntesting\n"
+
+func TestVerifier_VerifyCommit_OpenPGP(t *testing.T) {
+	g := NewWithT(t)
+
+	entity, err := openpgp.NewEntity("Jane Doe", "", "jane@example.com", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	other, err := openpgp.NewEntity("John Doe", "", "john@example.com", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var sig bytes.Buffer
+	g.Expect(openpgp.ArmoredDetachSign(&sig, entity, strings.NewReader(testPayload), nil)).To(Succeed())
+	commit := &git.Commit{
+		Hash:      git.Hash("a"),
+		Signature: sig.String(),
+		Encoded:   []byte(testPayload),
+	}
+
+	verifier, err := NewVerifier(WithOpenPGPKeyRings(armoredKeyRing(t, other), armoredKeyRing(t, entity)))
+	g.Expect(err).ToNot(HaveOccurred())
+	res, err := verifier.VerifyCommit(commit)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.Type).To(Equal(SignatureTypeOpenPGP))
+	g.Expect(res.KeyID).To(Equal(entity.PrimaryKey.KeyIdString()))
+	g.Expect(res.Identities).To(Equal([]string{"Jane Doe <jane@example.com>"}))
+
+	verifier, err = NewVerifier(WithOpenPGPKeyRings(armoredKeyRing(t, other)))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = verifier.VerifyCommit(commit)
+	g.Expect(err).To(MatchError(ContainSubstring("unable to verify the OpenPGP signature with any of the given key rings")))
+
+	commit.Encoded = []byte("tampered")
+	verifier, err = NewVerifier(WithOpenPGPKeyRings(armoredKeyRing(t, entity)))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = verifier.VerifyCommit(commit)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestVerifier_VerifyCommit_SSH(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := string(ssh.MarshalAuthorizedKey(signer.PublicKey()))
+	when := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		allowedSigners string
+		wantIdentities []string
+		wantErr        string
+	}{
+		{
+			name:           "allowed signer",
+			allowedSigners: "jane@example.com,jane@example.org " + pub,
+			wantIdentities: []string{"jane@example.com", "jane@example.org"},
+		},
+		{
+			name:           "allowed signer with options",
+			allowedSigners: `"jane@example.com" namespaces="file,git",valid-after="20250101",valid-before="20260101Z" ` + pub,
+			wantIdentities: []string{"jane@example.com"},
+		},
+		{
+			name:           "signer not allowed for git",
+			allowedSigners: `jane@example.com namespaces="file" ` + pub,
+			wantErr:        "is not an allowed signer",
+		},
+		{
+			name:           "signer expired",
+			allowedSigners: `jane@example.com valid-before="20250101" ` + pub,
+			wantErr:        "is not an allowed signer",
+		},
+		{
+			name:           "other key",
+			allowedSigners: "john@example.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGPfc8wtmlVMCSBbXOJ2xKKh5xrJ0xcJpzzhUUVaW1tZ",
+			wantErr:        "is not an allowed signer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			verifier, err := NewVerifier(WithSSHAllowedSigners(tt.allowedSigners))
+			g.Expect(err).ToNot(HaveOccurred())
+			res, err := verifier.VerifyCommit(&git.Commit{
+				Hash:      git.Hash("a"),
+				Committer: git.Signature{When: when},
+				Signature: sshSign(t, signer, []byte(testPayload)),
+				Encoded:   []byte(testPayload),
+			})
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(res.Type).To(Equal(SignatureTypeSSH))
+			g.Expect(res.KeyID).To(Equal(ssh.FingerprintSHA256(signer.PublicKey())))
+			g.Expect(res.Identities).To(Equal(tt.wantIdentities))
+		})
+	}
+
+	t.Run("tampered payload", func(t *testing.T) {
+		g := NewWithT(t)
+
+		verifier, err := NewVerifier(WithSSHAllowedSigners("jane@example.com " + pub))
+		g.Expect(err).ToNot(HaveOccurred())
+		_, err = verifier.VerifyCommit(&git.Commit{
+			Signature: sshSign(t, signer, []byte(testPayload)),
+			Encoded:   []byte("tampered"),
+		})
+		g.Expect(err).To(MatchError(ContainSubstring("invalid SSH signature")))
+	})
+}
+
+func TestParseAllowedSigners(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := ParseAllowedSigners("# no signers\n")
+	g.Expect(err).To(MatchError("no allowed signers found"))
+
+	_, err = ParseAllowedSigners("jane@example.com\n")
+	g.Expect(err).To(MatchError("invalid allowed signer on line 1: missing key"))
+
+	_, err = ParseAllowedSigners("*@example.com cert-authority ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGPfc8wtmlVMCSBbXOJ2xKKh5xrJ0xcJpzzhUUVaW1tZ\n")
+	g.Expect(err).To(MatchError("invalid allowed signer on line 1: cert-authority entries are not supported"))
+
+	signers, err := ParseAllowedSigners("# comment\n\njane@example.com valid-after=\"202501021504Z\" ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGPfc8wtmlVMCSBbXOJ2xKKh5xrJ0xcJpzzhUUVaW1tZ jane\n")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(signers).To(HaveLen(1))
+	g.Expect(signers[0].Principals).To(Equal([]string{"jane@example.com"}))
+	g.Expect(signers[0].ValidAfter).To(Equal(time.Date(2025, 1, 2, 15, 4, 0, 0, time.UTC)))
+}
+
+func TestVerifier_VerifyTag_X509(t *testing.T) {
+	g := NewWithT(t)
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	ca, err := x509.ParseCertificate(caDER)
+	g.Expect(err).ToNot(HaveOccurred())
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+
+	issuerExt, err := asn1.MarshalWithParams("https://token.actions.githubusercontent.com", "utf8")
+	g.Expect(err).ToNot(HaveOccurred())
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	leafTemplate := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Minute),
+		NotAfter:        time.Now().Add(10 * time.Minute),
+		EmailAddresses:  []string{"jane@example.com"},
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+		ExtraExtensions: []pkix.Extension{{Id: oidFulcioIssuerV2, Value: issuerExt}},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, ca, &leafKey.PublicKey, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	leaf, err := x509.ParseCertificate(leafDER)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	sd, err := pkcs7.NewSignedData([]byte(testPayload))
+	g.Expect(err).ToNot(HaveOccurred())
+	sd.SetDigestAlgorithm(pkcs7.OIDDigestAlgorithmSHA256)
+	g.Expect(sd.AddSigner(leaf, leafKey, pkcs7.SignerInfoConfig{})).To(Succeed())
+	sd.Detach()
+	der, err := sd.Finish()
+	g.Expect(err).ToNot(HaveOccurred())
+	tag := &git.Tag{
+		Name:      "v1.0.0",
+		Signature: string(pem.EncodeToMemory(&pem.Block{Type: x509SigPEMType, Bytes: der})),
+		Encoded:   []byte(testPayload),
+	}
+
+	verifier, err := NewVerifier(WithX509Roots(caPEM, X509Identity{
+		Subject: ".*@example.com",
+		Issuer:  "https://token.actions.githubusercontent.com",
+	}))
+	g.Expect(err).ToNot(HaveOccurred())
+	res, err := verifier.VerifyTag(tag)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.Type).To(Equal(SignatureTypeX509))
+	g.Expect(res.Identities).To(Equal([]string{"jane@example.com"}))
+	g.Expect(res.Issuer).To(Equal("https://token.actions.githubusercontent.com"))
+
+	verifier, err = NewVerifier(WithX509Roots(caPEM, X509Identity{Subject: "john@example.com"}))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = verifier.VerifyTag(tag)
+	g.Expect(err).To(MatchError(ContainSubstring("are not trusted")))
+
+	// The signature must chain to the roots.
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	otherDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &otherKey.PublicKey, otherKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	verifier, err = NewVerifier(WithX509Roots(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: otherDER})))
+	g.Expect(err).ToNot(HaveOccurred())
+	_, err = verifier.VerifyTag(tag)
+	g.Expect(err).To(MatchError(ContainSubstring("invalid x509 signature")))
+}
+
+func TestVerifier_unconfiguredType(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewVerifier()
+	g.Expect(err).To(MatchError("no trust anchors configured"))
+
+	entity, err := openpgp.NewEntity("Jane Doe", "", "jane@example.com", nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	verifier, err := NewVerifier(WithOpenPGPKeyRings(armoredKeyRing(t, entity)))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, err = verifier.VerifyCommit(&git.Commit{Hash: git.Hash("a")})
+	g.Expect(err).To(MatchError(ErrNoSignature))
+
+	_, err = verifier.VerifyCommit(&git.Commit{Signature: "-----BEGIN SSH SIGNATURE-----\n-----END SSH SIGNATURE-----\n"})
+	g.Expect(err).To(MatchError(ContainSubstring("no SSH allowed signers configured")))
+}
+
+func armoredKeyRing(t *testing.T, entity *openpgp.Entity) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+// sshSign returns the armored SSH signature of the payload, as created by
+// `ssh-keygen -Y sign -n git`.
+func sshSign(t *testing.T, signer ssh.Signer, payload []byte) string {
+	t.Helper()
+	h := sha512.Sum512(payload)
+	signedData := append([]byte(sshSigMagic), ssh.Marshal(sshSigSignedData{
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          string(h[:]),
+	})...)
+	sig, err := signer.Sign(rand.Reader, signedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := append([]byte(sshSigMagic), ssh.Marshal(sshSigBlob{
+		Version:       sshSigVersion,
+		PublicKey:     string(signer.PublicKey().Marshal()),
+		Namespace:     sshSigNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     string(ssh.Marshal(sig)),
+	})...)
+	return string(pem.EncodeToMemory(&pem.Block{Type: sshSigPEMType, Bytes: blob}))
+}
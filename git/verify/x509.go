@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package verify
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"go.mozilla.org/pkcs7"
+)
+
+const x509SigPEMType = "SIGNED MESSAGE"
+
+var (
+	// oidFulcioIssuer is the deprecated extension of the Fulcio certificates
+	// containing the raw OIDC issuer.
+	oidFulcioIssuer = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	// oidFulcioIssuerV2 is the extension of the Fulcio certificates
+	// containing the DER encoded OIDC issuer.
+	oidFulcioIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// X509Identity is an identity trusted to sign the objects with an x509
+// certificate.
+type X509Identity struct {
+	// Subject is a regular expression matching the whole of an email
+	// address or URI of the certificate.
+	Subject string
+	// Issuer is a regular expression matching the whole OIDC issuer of a
+	// keyless certificate, e.g. issued by Fulcio for gitsign, or the
+	// subject of the issuer of the certificate otherwise. It matches any
+	// issuer when empty.
+	Issuer string
+}
+
+// x509Verifier verifies the x509 CMS signatures.
+type x509Verifier struct {
+	roots      *x509.CertPool
+	identities []x509IdentityMatcher
+}
+
+type x509IdentityMatcher struct {
+	subject *regexp.Regexp
+	issuer  *regexp.Regexp
+}
+
+// WithX509Roots configures the PEM encoded root certificates trusted to
+// issue the signing certificates, e.g. the Fulcio root for gitsign. When
+// identities are given, the signing certificates must match one of them.
+func WithX509Roots(rootsPEM []byte, identities ...X509Identity) Option {
+	return func(v *Verifier) error {
+		if v.x509 == nil {
+			v.x509 = &x509Verifier{roots: x509.NewCertPool()}
+		}
+		if !v.x509.roots.AppendCertsFromPEM(rootsPEM) {
+			return errors.New("unable to append the x509 roots to the certificate pool")
+		}
+		for _, id := range identities {
+			var m x509IdentityMatcher
+			var err error
+			if m.subject, err = regexp.Compile("^(?:" + id.Subject + ")$"); err != nil {
+				return fmt.Errorf("invalid x509 identity subject '%s': %w", id.Subject, err)
+			}
+			if id.Issuer != "" {
+				if m.issuer, err = regexp.Compile("^(?:" + id.Issuer + ")$"); err != nil {
+					return fmt.Errorf("invalid x509 identity issuer '%s': %w", id.Issuer, err)
+				}
+			}
+			v.x509.identities = append(v.x509.identities, m)
+		}
+		return nil
+	}
+}
+
+// verify verifies the armored detached CMS signature of the payload, and
+// that its certificate chains to the roots at the signing time, and matches
+// one of the identities.
+func (v *x509Verifier) verify(sig string, payload []byte) (*Result, error) {
+	block, _ := pem.Decode([]byte(sig))
+	if block == nil || block.Type != x509SigPEMType {
+		return nil, errors.New("unable to decode the x509 signature")
+	}
+	p7, err := pkcs7.Parse(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the x509 signature: %w", err)
+	}
+	cert := p7.GetOnlySigner()
+	if cert == nil {
+		return nil, errors.New("the x509 signature must have a single signer")
+	}
+	p7.Content = payload
+	if err := p7.VerifyWithChain(v.roots); err != nil {
+		return nil, fmt.Errorf("invalid x509 signature: %w", err)
+	}
+
+	var identities []string
+	identities = append(identities, cert.EmailAddresses...)
+	for _, u := range cert.URIs {
+		identities = append(identities, u.String())
+	}
+	issuer, err := certificateIssuer(cert)
+	if err != nil {
+		return nil, err
+	}
+	if !v.matches(identities, issuer) {
+		return nil, fmt.Errorf("x509 certificate identities %v issued by '%s' are not trusted", identities, issuer)
+	}
+
+	fingerprint := sha256.Sum256(cert.Raw)
+	return &Result{
+		Type:       SignatureTypeX509,
+		KeyID:      "SHA256:" + hex.EncodeToString(fingerprint[:]),
+		Identities: identities,
+		Issuer:     issuer,
+	}, nil
+}
+
+// matches returns true if no identities are configured, or if one of the
+// identities of the certificate matches one of the configured identities.
+func (v *x509Verifier) matches(identities []string, issuer string) bool {
+	if len(v.identities) == 0 {
+		return true
+	}
+	for _, m := range v.identities {
+		if m.issuer != nil && !m.issuer.MatchString(issuer) {
+			continue
+		}
+		for _, id := range identities {
+			if m.subject.MatchString(id) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// certificateIssuer returns the OIDC issuer of a Fulcio certificate, or the
+// subject of the issuer of the certificate otherwise.
+func certificateIssuer(cert *x509.Certificate) (string, error) {
+	var legacy string
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidFulcioIssuerV2):
+			var issuer string
+			if _, err := asn1.UnmarshalWithParams(ext.Value, &issuer, "utf8"); err != nil {
+				return "", fmt.Errorf("unable to parse the OIDC issuer of the x509 certificate: %w", err)
+			}
+			return issuer, nil
+		case ext.Id.Equal(oidFulcioIssuer):
+			legacy = string(ext.Value)
+		}
+	}
+	if legacy != "" {
+		return legacy, nil
+	}
+	return cert.Issuer.String(), nil
+}
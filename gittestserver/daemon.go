@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittestserver
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"strings"
+
+	securefilepath "github.com/cyphar/filepath-securejoin"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+)
+
+const (
+	uploadPackService  = "git-upload-pack"
+	receivePackService = "git-receive-pack"
+)
+
+// StartGitDaemon starts a git daemon server, serving the repositories over
+// the unauthenticated git:// protocol. Like the git daemon, it runs the git
+// binary for each request. Pushes are accepted unless the server is
+// read-only. The repositories with an authentication policy are only served
+// if it allows anonymous reads, and never accept pushes.
+func (s *GitServer) StartGitDaemon() error {
+	s.StopGitDaemon()
+
+	// 127.0.0.1 forces the lowest common denominator of TCPv4 on
+	// localhost, like the SSH server.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	s.daemonListener = l
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.handleDaemonConn(conn)
+		}
+	}()
+	return nil
+}
+
+// StopGitDaemon stops the git daemon server.
+func (s *GitServer) StopGitDaemon() {
+	if s.daemonListener != nil {
+		s.daemonListener.Close()
+		s.daemonListener = nil
+	}
+}
+
+// GitDaemonAddress returns the address of the git daemon server as a URL.
+func (s *GitServer) GitDaemonAddress() string {
+	if s.daemonListener != nil {
+		return "git://" + s.daemonListener.Addr().String()
+	}
+	return ""
+}
+
+// handleDaemonConn reads the request of a git daemon connection, and serves
+// it with the git binary.
+func (s *GitServer) handleDaemonConn(conn net.Conn) {
+	defer conn.Close()
+
+	scanner := pktline.NewScanner(conn)
+	if !scanner.Scan() {
+		return
+	}
+	service, repoPath, protocol, err := parseDaemonRequest(scanner.Bytes())
+	if err != nil {
+		writeDaemonError(conn, err.Error())
+		return
+	}
+	if auth, ok := s.repoAuth(repoPath); ok && (!auth.AnonymousRead || service == receivePackService) {
+		writeDaemonError(conn, fmt.Sprintf("access to repository '%s' denied", repoPath))
+		return
+	}
+	if service == receivePackService && s.config.ReadOnly {
+		writeDaemonError(conn, "push is not allowed on a read-only server")
+		return
+	}
+
+	repo, err := securefilepath.SecureJoin(s.Root(), repoPath)
+	if err != nil {
+		writeDaemonError(conn, err.Error())
+		return
+	}
+	if _, err := os.Stat(repo); err != nil {
+		writeDaemonError(conn, fmt.Sprintf("repository '%s' not found", repoPath))
+		return
+	}
+
+	args := []string{strings.TrimPrefix(service, "git-")}
+	if service == uploadPackService {
+		args = append(args, "--strict")
+	}
+	args = append(args, repo)
+
+	cmd := exec.Command("git", args...)
+	cmd.Env = os.Environ()
+	if protocol != "" {
+		cmd.Env = append(cmd.Env, "GIT_PROTOCOL="+protocol)
+	}
+	cmd.Stdin = conn
+	cmd.Stdout = conn
+	_ = cmd.Run()
+}
+
+// parseDaemonRequest parses the initial request of the git daemon protocol,
+// e.g. "git-upload-pack /repo.git\0host=localhost\0\0version=2\0", and returns
+// the requested service, the repository path and the extra parameters
+// formatted for the GIT_PROTOCOL environment variable.
+func parseDaemonRequest(req []byte) (string, string, string, error) {
+	req = bytes.TrimSuffix(req, []byte("\n"))
+	parts := bytes.Split(req, []byte{0})
+
+	service, repoPath, ok := strings.Cut(string(parts[0]), " ")
+	if !ok || repoPath == "" {
+		return "", "", "", errors.New("invalid request")
+	}
+	if service != uploadPackService && service != receivePackService {
+		return "", "", "", fmt.Errorf("service '%s' not supported", service)
+	}
+
+	// The extra parameters follow the host parameter and an empty string.
+	var extra []string
+	for i := 1; i < len(parts); i++ {
+		p := string(parts[i])
+		if p == "" || strings.HasPrefix(p, "host=") {
+			continue
+		}
+		extra = append(extra, p)
+	}
+	return service, repoPath, strings.Join(extra, ":"), nil
+}
+
+// writeDaemonError reports an error to the git daemon client.
+func writeDaemonError(conn net.Conn, msg string) {
+	e := &pktline.ErrorLine{Text: msg}
+	_ = e.Encode(conn)
+}
@@ -0,0 +1,139 @@
+package gittestserver
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+func TestGitDaemon(t *testing.T) {
+	repoPath := "bar/test-reponame"
+
+	srv, err := NewTempGitServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srv.Root())
+	if err := srv.InitRepo("testdata/git/repo1", "master", repoPath); err != nil {
+		t.Fatalf("failed to initialize repo: %v", err)
+	}
+
+	if err := srv.StartGitDaemon(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.StopGitDaemon()
+
+	addr := srv.GitDaemonAddress()
+	if !strings.HasPrefix(addr, "git://") {
+		t.Fatalf("URL given for git daemon doesn't start with git://, got: %s", addr)
+	}
+
+	cloneDir := t.TempDir()
+	repo, err := gogit.PlainClone(cloneDir, false, &gogit.CloneOptions{
+		URL: addr + "/" + repoPath,
+	})
+	if err != nil {
+		t.Fatalf("failed to clone repo: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(cloneDir, "foo.txt")); os.IsNotExist(err) {
+		t.Error("expected foo.txt to exist")
+	}
+
+	// Push a new commit.
+	commit(t, repo, cloneDir)
+	if err := repo.Push(&gogit.PushOptions{}); err != nil {
+		t.Fatalf("failed to push: %v", err)
+	}
+
+	// Pushing to a read-only server fails.
+	srv.ReadOnly(true)
+	commit(t, repo, cloneDir)
+	if err := repo.Push(&gogit.PushOptions{}); err == nil || !strings.Contains(err.Error(), "read-only") {
+		t.Errorf("expected push to a read-only server to fail, got: %v", err)
+	}
+
+	// Cloning a non-existing repository fails.
+	_, err = gogit.PlainClone(t.TempDir(), false, &gogit.CloneOptions{
+		URL: addr + "/non-existing",
+	})
+	if err == nil {
+		t.Error("expected clone of a non-existing repository to fail")
+	}
+}
+
+func TestAllowAnonymousRead(t *testing.T) {
+	repoPath := "bar/test-reponame"
+
+	srv, err := NewTempGitServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srv.Root())
+	srv.Auth("test-user", "test-pswd").AllowAnonymousRead()
+	if err := srv.StartHTTP(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.StopHTTP()
+	if err := srv.InitRepo("testdata/git/repo1", "master", repoPath); err != nil {
+		t.Fatalf("failed to initialize repo: %v", err)
+	}
+
+	// Clone without credentials.
+	cloneDir := t.TempDir()
+	repo, err := gogit.PlainClone(cloneDir, false, &gogit.CloneOptions{
+		URL: srv.HTTPAddress() + "/" + repoPath,
+	})
+	if err != nil {
+		t.Fatalf("failed to clone repo anonymously: %v", err)
+	}
+
+	// Push without credentials fails.
+	commit(t, repo, cloneDir)
+	if err := repo.Push(&gogit.PushOptions{}); err == nil {
+		t.Error("expected anonymous push to fail")
+	}
+
+	// Push with wrong credentials fails.
+	err = repo.Push(&gogit.PushOptions{
+		Auth: &http.BasicAuth{Username: "test-user", Password: "wrong"},
+	})
+	if err == nil {
+		t.Error("expected push with wrong credentials to fail")
+	}
+
+	// Push with credentials succeeds.
+	err = repo.Push(&gogit.PushOptions{
+		Auth: &http.BasicAuth{Username: "test-user", Password: "test-pswd"},
+	})
+	if err != nil {
+		t.Fatalf("failed to push with credentials: %v", err)
+	}
+}
+
+func commit(t *testing.T, repo *gogit.Repository, dir string) {
+	t.Helper()
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.CreateTemp(dir, "file-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := wt.Add(filepath.Base(f.Name())); err != nil {
+		t.Fatal(err)
+	}
+	_, err = wt.Commit("new file", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "test", Email: "test@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,332 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittestserver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	securefilepath "github.com/cyphar/filepath-securejoin"
+	"github.com/fluxcd/gitkit"
+	"golang.org/x/crypto/ssh"
+)
+
+// RepoAuth is the authentication policy of a repository, which overrides
+// the server-wide authentication configured with Auth and
+// AllowAnonymousRead. A request is allowed if it satisfies any of the
+// configured methods, a zero RepoAuth denying all requests.
+type RepoAuth struct {
+	// AnonymousRead allows cloning and fetching the repository without
+	// credentials over HTTP and the git daemon protocol.
+	AnonymousRead bool
+
+	// Username and Password are the HTTP basic auth credentials of the
+	// repository.
+	Username string
+	Password string
+
+	// Token is the access token of the repository, accepted over HTTP
+	// either as a bearer token, or as the password of any username like
+	// the personal access tokens of Git hosting providers.
+	Token string
+
+	// SSHKeys are the public keys allowed to access the repository over
+	// SSH, in the authorized_keys format.
+	SSHKeys []string
+}
+
+// WithRepoAuth sets the authentication policy of the repository at
+// repoPath, e.g. "org/repo.git", so that a single server can serve
+// repositories with distinct authentication requirements. Use before
+// starting the servers.
+//
+// As the SSH server of gitkit authenticates the clients before the
+// repository is known, the SSH server started by ListenSSH handles the
+// connections itself once a policy is set, using the SSH configuration of
+// the GitServer, and an ephemeral host key if none is configured.
+func (s *GitServer) WithRepoAuth(repoPath string, auth RepoAuth) *GitServer {
+	if s.repoAuths == nil {
+		s.repoAuths = make(map[string]RepoAuth)
+	}
+	s.repoAuths[normalizeRepoPath(repoPath)] = auth
+	return s
+}
+
+// repoAuth returns the authentication policy of the given repository, if
+// any.
+func (s *GitServer) repoAuth(repoPath string) (RepoAuth, bool) {
+	auth, ok := s.repoAuths[normalizeRepoPath(repoPath)]
+	return auth, ok
+}
+
+// normalizeRepoPath returns the repository path without leading and
+// trailing slashes.
+func normalizeRepoPath(repoPath string) string {
+	return strings.Trim(repoPath, "/")
+}
+
+// allowHTTP returns true if the HTTP request satisfies the policy.
+func (a RepoAuth) allowHTTP(r *http.Request) bool {
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		return a.AnonymousRead && isUploadPackRequest(r)
+	}
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return a.Token != "" && secureEqual(token, a.Token)
+	}
+	username, password, ok := r.BasicAuth()
+	if !ok {
+		return false
+	}
+	if a.Username != "" && secureEqual(username, a.Username) && secureEqual(password, a.Password) {
+		return true
+	}
+	return a.Token != "" && secureEqual(password, a.Token)
+}
+
+// allowSSHKey returns true if the authorized key is allowed by the policy.
+func (a RepoAuth) allowSSHKey(authorizedKey string) bool {
+	return authorizedKey != "" && slices.ContainsFunc(a.SSHKeys, func(k string) bool {
+		pk, _, _, _, err := ssh.ParseAuthorizedKey([]byte(k))
+		return err == nil && strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pk))) == authorizedKey
+	})
+}
+
+func secureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// repoAuthHandler returns an HTTP handler serving the repositories with an
+// authentication policy with the open service once the request satisfies
+// their policy, and the other repositories with the next handler.
+func (s *GitServer) repoAuthHandler(open, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, ok := s.repoAuth(httpRepoPath(r.URL.Path))
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !auth.allowHTTP(r) {
+			w.Header().Set("WWW-Authenticate", `Basic realm=""`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		open.ServeHTTP(w, r)
+	})
+}
+
+// httpRepoPath returns the repository path of a smart HTTP request path,
+// e.g. "org/repo.git" for "/org/repo.git/info/refs".
+func httpRepoPath(path string) string {
+	for _, suffix := range []string{"/info/refs", "/" + uploadPackService, "/" + receivePackService} {
+		if p, ok := strings.CutSuffix(path, suffix); ok {
+			return p
+		}
+	}
+	return path
+}
+
+// repoSSHServer is an SSH git server enforcing the authentication policies
+// of the repositories. It authenticates the clients by public key, and
+// authorizes their git commands against the policy of the repository, or
+// against the public key lookup function if the repository has no policy
+// and the server-wide authentication is on.
+type repoSSHServer struct {
+	server   *GitServer
+	config   *ssh.ServerConfig
+	listener net.Listener
+}
+
+// newRepoSSHServer returns an SSH git server enforcing the authentication
+// policies of the repositories of the given GitServer.
+func newRepoSSHServer(s *GitServer) (*repoSSHServer, error) {
+	config := s.sshServerConfig
+	if config == nil {
+		config = &ssh.ServerConfig{}
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		signer, err := ssh.NewSignerFromKey(key)
+		if err != nil {
+			return nil, err
+		}
+		config.AddHostKey(signer)
+	}
+	// The authorization happens once the repository is known.
+	config.PublicKeyCallback = func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+		return &ssh.Permissions{Extensions: map[string]string{
+			"authorized-key": strings.TrimSpace(string(ssh.MarshalAuthorizedKey(key))),
+		}}, nil
+	}
+	return &repoSSHServer{server: s, config: config}, nil
+}
+
+func (r *repoSSHServer) Listen(bind string) error {
+	l, err := net.Listen("tcp", bind)
+	if err != nil {
+		return err
+	}
+	r.listener = l
+	return nil
+}
+
+func (r *repoSSHServer) Serve() error {
+	if r.listener == nil {
+		return gitkit.ErrNoListener
+	}
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			return err
+		}
+		go r.handleConn(conn)
+	}
+}
+
+func (r *repoSSHServer) Stop() error {
+	if r.listener == nil {
+		return nil
+	}
+	return r.listener.Close()
+}
+
+func (r *repoSSHServer) Address() string {
+	return r.listener.Addr().String()
+}
+
+// handleConn serves the exec requests of the sessions of an SSH connection
+// with the git binary.
+func (r *repoSSHServer) handleConn(conn net.Conn) {
+	sConn, chans, reqs, err := ssh.NewServerConn(conn, r.config)
+	if err != nil {
+		return
+	}
+	defer sConn.Close()
+	go ssh.DiscardRequests(reqs)
+
+	authorizedKey := sConn.Permissions.Extensions["authorized-key"]
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			_ = newChan.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, chReqs, err := newChan.Accept()
+		if err != nil {
+			continue
+		}
+		go r.handleSession(authorizedKey, ch, chReqs)
+	}
+}
+
+// handleSession runs the git command of the first exec request of a
+// session, if the repository allows the public key of the client.
+func (r *repoSSHServer) handleSession(authorizedKey string, ch ssh.Channel, reqs <-chan *ssh.Request) {
+	defer ch.Close()
+
+	for req := range reqs {
+		if req.Type != "exec" || len(req.Payload) < 4 {
+			_ = req.Reply(false, nil)
+			continue
+		}
+		// The payload of an exec request is the length-prefixed command.
+		cmd, err := gitkit.ParseGitCommand(string(req.Payload[4:]))
+		if err != nil {
+			_ = req.Reply(false, nil)
+			return
+		}
+		_ = req.Reply(true, nil)
+
+		status := r.runGitCommand(authorizedKey, strings.Replace(cmd.Command, " ", "-", 1), cmd.Repo, ch)
+		_, _ = ch.SendRequest("exit-status", false, binary.BigEndian.AppendUint32(nil, status))
+		return
+	}
+}
+
+// runGitCommand authorizes and runs the git service on the repository with
+// the session streams, and returns its exit status.
+func (r *repoSSHServer) runGitCommand(authorizedKey, service, repoPath string, ch ssh.Channel) uint32 {
+	s := r.server
+	if !r.allow(authorizedKey, repoPath) {
+		fmt.Fprintf(ch.Stderr(), "access to repository '%s' denied\n", repoPath)
+		return 1
+	}
+	if service == receivePackService && s.config.ReadOnly {
+		fmt.Fprintln(ch.Stderr(), "push is not allowed on a read-only server")
+		return 1
+	}
+	if service != uploadPackService && service != receivePackService {
+		fmt.Fprintf(ch.Stderr(), "service '%s' not supported\n", service)
+		return 1
+	}
+
+	repo, err := securefilepath.SecureJoin(s.Root(), repoPath)
+	if err != nil {
+		fmt.Fprintln(ch.Stderr(), err)
+		return 1
+	}
+	if _, err := os.Stat(repo); err != nil {
+		fmt.Fprintf(ch.Stderr(), "repository '%s' not found\n", repoPath)
+		return 1
+	}
+
+	cmd := exec.Command("git", strings.TrimPrefix(service, "git-"), repo)
+	cmd.Env = os.Environ()
+	cmd.Stdout = ch
+	cmd.Stderr = ch.Stderr()
+	// The client may not close its side of the session once the command
+	// has completed, hence the input is not waited for.
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		fmt.Fprintln(ch.Stderr(), err)
+		return 1
+	}
+	go func() {
+		_, _ = io.Copy(stdin, ch)
+		stdin.Close()
+	}()
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return uint32(exitErr.ExitCode())
+		}
+		_, _ = io.WriteString(ch.Stderr(), err.Error()+"\n")
+		return 1
+	}
+	return 0
+}
+
+// allow returns true if the public key is allowed to access the repository.
+func (r *repoSSHServer) allow(authorizedKey, repoPath string) bool {
+	if auth, ok := r.server.repoAuth(repoPath); ok {
+		return auth.allowSSHKey(authorizedKey)
+	}
+	if !r.server.config.Auth {
+		return true
+	}
+	_, err := publicKeyLookupFunc(authorizedKey)
+	return err == nil
+}
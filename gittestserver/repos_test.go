@@ -0,0 +1,219 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gittestserver
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"strings"
+	"testing"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestWithRepoAuth_HTTP(t *testing.T) {
+	srv, err := NewTempGitServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srv.Root())
+	srv.Auth("test-user", "test-pswd").
+		WithRepoAuth("org/public.git", RepoAuth{AnonymousRead: true, Token: "public-token"}).
+		WithRepoAuth("org/basic.git", RepoAuth{Username: "basic-user", Password: "basic-pswd"}).
+		WithRepoAuth("/org/token.git/", RepoAuth{Token: "repo-token"})
+	if err := srv.StartHTTP(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.StopHTTP()
+	for _, repoPath := range []string{"org/public.git", "org/basic.git", "org/token.git", "org/default.git"} {
+		if err := srv.InitRepo("testdata/git/repo1", "master", repoPath); err != nil {
+			t.Fatalf("failed to initialize repo: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		repoPath string
+		auth     transport.AuthMethod
+		wantErr  bool
+	}{
+		{name: "anonymous read of public repository", repoPath: "org/public.git"},
+		{name: "anonymous read of private repository", repoPath: "org/basic.git", wantErr: true},
+		{name: "basic auth", repoPath: "org/basic.git", auth: &http.BasicAuth{Username: "basic-user", Password: "basic-pswd"}},
+		{name: "server credentials for repository with policy", repoPath: "org/basic.git",
+			auth: &http.BasicAuth{Username: "test-user", Password: "test-pswd"}, wantErr: true},
+		{name: "bearer token", repoPath: "org/token.git", auth: &http.TokenAuth{Token: "repo-token"}},
+		{name: "token as password", repoPath: "org/token.git", auth: &http.BasicAuth{Username: "x-access-token", Password: "repo-token"}},
+		{name: "token of another repository", repoPath: "org/token.git", auth: &http.TokenAuth{Token: "public-token"}, wantErr: true},
+		{name: "server credentials for repository without policy", repoPath: "org/default.git",
+			auth: &http.BasicAuth{Username: "test-user", Password: "test-pswd"}},
+		{name: "repository credentials for repository without policy", repoPath: "org/default.git",
+			auth: &http.BasicAuth{Username: "basic-user", Password: "basic-pswd"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := gogit.PlainClone(t.TempDir(), false, &gogit.CloneOptions{
+				URL:  srv.HTTPAddress() + "/" + tt.repoPath,
+				Auth: tt.auth,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("unexpected clone error: %v, wantErr: %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("anonymous push to public repository", func(t *testing.T) {
+		cloneDir := t.TempDir()
+		repo, err := gogit.PlainClone(cloneDir, false, &gogit.CloneOptions{
+			URL: srv.HTTPAddress() + "/org/public.git",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit(t, repo, cloneDir)
+		if err := repo.Push(&gogit.PushOptions{}); err == nil {
+			t.Error("expected anonymous push to fail")
+		}
+		if err := repo.Push(&gogit.PushOptions{Auth: &http.TokenAuth{Token: "public-token"}}); err != nil {
+			t.Errorf("failed to push with token: %v", err)
+		}
+	})
+}
+
+func TestWithRepoAuth_GitDaemon(t *testing.T) {
+	srv, err := NewTempGitServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srv.Root())
+	srv.WithRepoAuth("org/public.git", RepoAuth{AnonymousRead: true}).
+		WithRepoAuth("org/private.git", RepoAuth{Token: "repo-token"})
+	if err := srv.StartGitDaemon(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.StopGitDaemon()
+	for _, repoPath := range []string{"org/public.git", "org/private.git"} {
+		if err := srv.InitRepo("testdata/git/repo1", "master", repoPath); err != nil {
+			t.Fatalf("failed to initialize repo: %v", err)
+		}
+	}
+
+	cloneDir := t.TempDir()
+	repo, err := gogit.PlainClone(cloneDir, false, &gogit.CloneOptions{
+		URL: srv.GitDaemonAddress() + "/org/public.git",
+	})
+	if err != nil {
+		t.Fatalf("failed to clone public repo: %v", err)
+	}
+	commit(t, repo, cloneDir)
+	if err := repo.Push(&gogit.PushOptions{}); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected push to a repository with a policy to be denied, got: %v", err)
+	}
+
+	_, err = gogit.PlainClone(t.TempDir(), false, &gogit.CloneOptions{
+		URL: srv.GitDaemonAddress() + "/org/private.git",
+	})
+	if err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("expected clone of a private repository to be denied, got: %v", err)
+	}
+}
+
+func TestWithRepoAuth_SSH(t *testing.T) {
+	allowedKey := newSSHKey(t)
+	otherKey := newSSHKey(t)
+
+	srv, err := NewTempGitServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(srv.Root())
+	srv.Auth("test-user", "test-pswd").
+		WithRepoAuth("org/deploy.git", RepoAuth{
+			SSHKeys: []string{string(ssh.MarshalAuthorizedKey(allowedKey.Signer.PublicKey()))},
+		}).
+		WithRepoAuth("org/http-only.git", RepoAuth{Token: "repo-token"})
+	if err := srv.ListenSSH(); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		_ = srv.StartSSH()
+	}()
+	defer srv.StopSSH()
+	for _, repoPath := range []string{"org/deploy.git", "org/http-only.git", "org/default.git"} {
+		if err := srv.InitRepo("testdata/git/repo1", "master", repoPath); err != nil {
+			t.Fatalf("failed to initialize repo: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		repoPath string
+		key      *gitssh.PublicKeys
+		wantErr  bool
+	}{
+		{name: "allowed key", repoPath: "org/deploy.git", key: allowedKey},
+		{name: "other key", repoPath: "org/deploy.git", key: otherKey, wantErr: true},
+		{name: "repository without SSH keys", repoPath: "org/http-only.git", key: allowedKey, wantErr: true},
+		{name: "repository without policy", repoPath: "org/default.git", key: otherKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cloneDir := t.TempDir()
+			repo, err := gogit.PlainClone(cloneDir, false, &gogit.CloneOptions{
+				URL:  srv.SSHAddress() + "/" + tt.repoPath,
+				Auth: tt.key,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected clone error: %v, wantErr: %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			commit(t, repo, cloneDir)
+			if err := repo.Push(&gogit.PushOptions{Auth: tt.key}); err != nil {
+				t.Errorf("failed to push: %v", err)
+			}
+		})
+	}
+}
+
+func newSSHKey(t *testing.T) *gitssh.PublicKeys {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &gitssh.PublicKeys{
+		User:   "git",
+		Signer: signer,
+		HostKeyCallbackHelper: gitssh.HostKeyCallbackHelper{
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		},
+	}
+}
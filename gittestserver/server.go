@@ -20,12 +20,14 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -85,15 +87,26 @@ func (g *GitServer) WithSSHConfig(cfg *ssh.ServerConfig) *GitServer {
 type HTTPMiddleware func(http.Handler) http.Handler
 
 // GitServer is a git server for testing purposes.
-// It can serve git repositories over HTTP and SSH.
+// It can serve git repositories over HTTP, SSH and the git daemon protocol.
 type GitServer struct {
 	config          gitkit.Config
 	sshServerConfig *ssh.ServerConfig
 	httpServer      *httptest.Server
-	sshServer       *gitkit.SSH
+	sshServer       sshServer
 	// Set these to configure HTTP auth
 	username, password string
+	anonymousRead      bool
+	repoAuths          map[string]RepoAuth
 	httpMiddlewares    []HTTPMiddleware
+	daemonListener     net.Listener
+}
+
+// sshServer is an SSH git server.
+type sshServer interface {
+	Listen(bind string) error
+	Serve() error
+	Stop() error
+	Address() string
 }
 
 // AddHTTPMiddlewares adds http middlewares to the git server.
@@ -145,19 +158,23 @@ func (s *GitServer) Auth(username, password string) *GitServer {
 	return s
 }
 
+// AllowAnonymousRead allows cloning and fetching over HTTP without
+// credentials when authentication is switched on, while pushing still
+// requires them. This simulates a public repository on a Git hosting
+// provider. Requests carrying an Authorization header are always
+// authenticated.
+func (s *GitServer) AllowAnonymousRead() *GitServer {
+	s.anonymousRead = true
+	return s
+}
+
 // StartHTTP starts a new HTTP git server with the current configuration.
 func (s *GitServer) StartHTTP() error {
 	s.StopHTTP()
-	service := gitkit.New(s.config)
-	if s.config.Auth {
-		service.AuthFunc = func(cred gitkit.Credential, _ *gitkit.Request) (bool, error) {
-			return cred.Username == s.username && cred.Password == s.password, nil
-		}
-	}
-	if err := service.Setup(); err != nil {
+	handler, err := s.newHTTPHandler()
+	if err != nil {
 		return err
 	}
-	handler := buildHTTPHandler(service, s.httpMiddlewares...)
 	s.httpServer = httptest.NewServer(handler)
 	return nil
 }
@@ -165,16 +182,10 @@ func (s *GitServer) StartHTTP() error {
 // StartHTTPS starts the TLS HTTPServer with the given TLS configuration.
 func (s *GitServer) StartHTTPS(cert, key, ca []byte, serverName string) error {
 	s.StopHTTP()
-	service := gitkit.New(s.config)
-	if s.config.Auth {
-		service.AuthFunc = func(cred gitkit.Credential, _ *gitkit.Request) (bool, error) {
-			return cred.Username == s.username && cred.Password == s.password, nil
-		}
-	}
-	if err := service.Setup(); err != nil {
+	handler, err := s.newHTTPHandler()
+	if err != nil {
 		return err
 	}
-	handler := buildHTTPHandler(service, s.httpMiddlewares...)
 	s.httpServer = httptest.NewUnstartedServer(handler)
 
 	config := tls.Config{}
@@ -196,6 +207,54 @@ func (s *GitServer) StartHTTPS(cert, key, ca []byte, serverName string) error {
 	return nil
 }
 
+// newHTTPHandler returns the HTTP handler of the git server with the current
+// configuration.
+func (s *GitServer) newHTTPHandler() (http.Handler, error) {
+	service := gitkit.New(s.config)
+	if s.config.Auth {
+		service.AuthFunc = func(cred gitkit.Credential, _ *gitkit.Request) (bool, error) {
+			return cred.Username == s.username && cred.Password == s.password, nil
+		}
+	}
+	if err := service.Setup(); err != nil {
+		return nil, err
+	}
+
+	var handler http.Handler = service
+	if s.config.Auth && (s.anonymousRead || len(s.repoAuths) > 0) {
+		anonymousConfig := s.config
+		anonymousConfig.Auth = false
+		anonymousService := gitkit.New(anonymousConfig)
+		if err := anonymousService.Setup(); err != nil {
+			return nil, err
+		}
+		if s.anonymousRead {
+			handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") == "" && isUploadPackRequest(r) {
+					anonymousService.ServeHTTP(w, r)
+					return
+				}
+				service.ServeHTTP(w, r)
+			})
+		}
+		if len(s.repoAuths) > 0 {
+			handler = s.repoAuthHandler(anonymousService, handler)
+		}
+	} else if len(s.repoAuths) > 0 {
+		handler = s.repoAuthHandler(service, handler)
+	}
+	return buildHTTPHandler(handler, s.httpMiddlewares...), nil
+}
+
+// isUploadPackRequest returns true if the request is part of a clone or a
+// fetch.
+func isUploadPackRequest(r *http.Request) bool {
+	if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/info/refs") {
+		return r.URL.Query().Get("service") == uploadPackService
+	}
+	return r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/"+uploadPackService)
+}
+
 // StopHTTP stops the HTTP git server.
 func (s *GitServer) StopHTTP() {
 	if s.httpServer != nil {
@@ -226,15 +285,24 @@ func (s *GitServer) ListenSSH() error {
 	if sshServer == nil {
 		m.Lock()
 		defer m.Unlock()
-		s.sshServer = gitkit.NewSSH(s.config)
-
-		if s.sshServerConfig != nil {
-			s.sshServer.SetSSHConfig(s.sshServerConfig)
+		if len(s.repoAuths) > 0 {
+			repoServer, err := newRepoSSHServer(s)
+			if err != nil {
+				return err
+			}
+			s.sshServer = repoServer
+		} else {
+			gitkitServer := gitkit.NewSSH(s.config)
+
+			if s.sshServerConfig != nil {
+				gitkitServer.SetSSHConfig(s.sshServerConfig)
+			}
+
+			// This is where authentication would happen, when needed.
+			gitkitServer.PublicKeyLookupFunc = publicKeyLookupFunc
+			s.sshServer = gitkitServer
 		}
 
-		// This is where authentication would happen, when needed.
-		s.sshServer.PublicKeyLookupFunc = publicKeyLookupFunc
-
 		// :0 should result in an OS assigned free port; 127.0.0.1
 		// forces the lowest common denominator of TCPv4 on localhost.
 		return s.sshServer.Listen("127.0.0.1:0")
@@ -32,6 +32,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-git/go-billy/v5"
 	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/opencontainers/go-digest"
@@ -166,7 +167,62 @@ func (r *ArchiveFetcher) Fetch(archiveURL, digest, dir string) error {
 }
 
 // FetchWithContext is the same as Fetch but accepts a context.
-func (r *ArchiveFetcher) FetchWithContext(ctx context.Context, archiveURL, digest, dir string) (err error) {
+func (r *ArchiveFetcher) FetchWithContext(ctx context.Context, archiveURL, digest, dir string) error {
+	return r.fetch(ctx, archiveURL, digest, &dirTarget{dir: dir})
+}
+
+// FetchToFS is the same as FetchWithContext but writes the archive, or
+// extracts its content, into the root of the given filesystem instead of a
+// directory. A memfs.Memory filesystem can be used to fetch the content of
+// an archive in memory.
+func (r *ArchiveFetcher) FetchToFS(ctx context.Context, archiveURL, digest string, fsys billy.Filesystem) error {
+	return r.fetch(ctx, archiveURL, digest, &fsTarget{fs: fsys})
+}
+
+// file is a file the archive is downloaded to.
+type file interface {
+	io.ReadWriteSeeker
+	io.Closer
+}
+
+// fetchTarget is the destination of a fetched archive.
+type fetchTarget interface {
+	// create creates the file the archive is written to when it is not
+	// extracted.
+	create(name string, mode fs.FileMode) (file, error)
+	// untar extracts the archive.
+	untar(r io.Reader, opts ...tar.TarOption) error
+}
+
+// dirTarget writes the fetched archive into a directory.
+type dirTarget struct {
+	dir string
+}
+
+func (t *dirTarget) create(name string, mode fs.FileMode) (file, error) {
+	return os.OpenFile(filepath.Join(t.dir, name), os.O_RDWR|os.O_CREATE|os.O_EXCL, mode)
+}
+
+func (t *dirTarget) untar(r io.Reader, opts ...tar.TarOption) error {
+	return tar.Untar(r, t.dir, opts...)
+}
+
+// fsTarget writes the fetched archive into a billy.Filesystem.
+type fsTarget struct {
+	fs billy.Filesystem
+}
+
+func (t *fsTarget) create(name string, mode fs.FileMode) (file, error) {
+	return t.fs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, mode)
+}
+
+func (t *fsTarget) untar(r io.Reader, opts ...tar.TarOption) error {
+	return tar.UntarFS(r, t.fs, opts...)
+}
+
+// fetch downloads and verifies the archive, and writes it or extracts its
+// content into the target.
+func (r *ArchiveFetcher) fetch(ctx context.Context, archiveURL, digest string, target fetchTarget) (err error) {
 	if r.hostnameOverwrite != "" {
 		u, err := url.Parse(archiveURL)
 		if err != nil {
@@ -195,20 +251,20 @@ func (r *ArchiveFetcher) FetchWithContext(ctx context.Context, archiveURL, diges
 	}
 
 	// Create a file for storing the archive.
-	var f *os.File
+	var f file
 	if r.untarOpts != nil {
-		f, err = os.CreateTemp("", "fetch.*.tmp")
+		tmp, err := os.CreateTemp("", "fetch.*.tmp")
 		if err != nil {
 			return fmt.Errorf("failed to create temp file: %w", err)
 		}
-		defer os.Remove(f.Name())
+		defer os.Remove(tmp.Name())
+		f = tmp
 	} else {
 		fn := r.filename
 		if fn == "" {
 			fn = path.Base(archiveURL)
 		}
-		p := filepath.Join(dir, fn)
-		f, err = os.OpenFile(p, os.O_RDWR|os.O_CREATE|os.O_EXCL, r.fileMode)
+		f, err = target.create(fn, r.fileMode)
 		if err != nil {
 			return fmt.Errorf("failed to create target file: %w", err)
 		}
@@ -267,7 +323,7 @@ func (r *ArchiveFetcher) FetchWithContext(ctx context.Context, archiveURL, diges
 
 		// Extracts the tar file.
 		opts := append(r.untarOpts, tar.WithSkipSymlinks())
-		if err = tar.Untar(f, dir, opts...); err != nil {
+		if err = target.untar(f, opts...); err != nil {
 			return fmt.Errorf("failed to extract archive (check whether file size exceeds max download size): %w", err)
 		}
 	}
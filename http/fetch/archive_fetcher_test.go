@@ -21,8 +21,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
 	. "github.com/onsi/gomega"
 
 	"github.com/fluxcd/pkg/tar"
@@ -295,3 +298,68 @@ func TestArchiveFetcher_Fetch(t *testing.T) {
 		})
 	}
 }
+
+func TestArchiveFetcher_FetchToFS(t *testing.T) {
+	g := NewWithT(t)
+
+	testServer, err := testserver.NewTempArtifactServer()
+	g.Expect(err).NotTo(HaveOccurred(), "failed to create the test server")
+	testServer.Start()
+
+	manifestsFileName := "testdata/manifests.yaml"
+	artifactName := "manifests.tgz"
+	artifactURL := fmt.Sprintf("%s/%s", testServer.URL(), artifactName)
+	artifactChecksum, err := testServer.ArtifactFromDir("testdata", artifactName)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	tests := []struct {
+		name               string
+		digest             string
+		originContentPath  string
+		fetchedContentPath string
+		opts               []Option
+		wantErr            bool
+	}{
+		{
+			name:               "extracts the archive",
+			digest:             "sha256:" + artifactChecksum,
+			originContentPath:  manifestsFileName,
+			fetchedContentPath: manifestsFileName,
+			opts:               []Option{WithUntar()},
+		},
+		{
+			name:               "writes the archive",
+			digest:             "sha256:" + artifactChecksum,
+			originContentPath:  filepath.Join(testServer.Root(), artifactName),
+			fetchedContentPath: artifactName,
+		},
+		{
+			name:    "fails to verify the digest",
+			digest:  "sha256:" + strings.Repeat("0", 64),
+			opts:    []Option{WithUntar()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fsys := memfs.New()
+			err := New(tt.opts...).FetchToFS(context.Background(), artifactURL, tt.digest, fsys)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			originContent, err := os.ReadFile(tt.originContentPath)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			fetchedContent, err := util.ReadFile(fsys, tt.fetchedContentPath)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(string(fetchedContent)).To(BeIdenticalTo(string(originContent)))
+		})
+	}
+}
@@ -14,6 +14,7 @@ replace github.com/opencontainers/go-digest => github.com/opencontainers/go-dige
 require (
 	github.com/fluxcd/pkg/tar v0.10.0
 	github.com/fluxcd/pkg/testserver v0.9.0
+	github.com/go-git/go-billy/v5 v5.6.2
 	github.com/go-logr/logr v1.4.2
 	github.com/hashicorp/go-retryablehttp v0.7.7
 	github.com/onsi/gomega v1.36.2
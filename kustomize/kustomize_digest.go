@@ -0,0 +1,130 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/resmap"
+)
+
+// digestAlgorithm is the algorithm prefixing the digests of the build outputs.
+const digestAlgorithm = "sha256"
+
+// BuildOutput holds the resources of a build in canonical order, and the
+// digest of their content.
+type BuildOutput struct {
+	// Objects are the resources of the build in canonical order.
+	Objects []*unstructured.Unstructured
+
+	// Digest is the digest of the content of the resources, in the format
+	// '<algorithm>:<checksum>'. It does not depend on the order of the
+	// resources in the build, nor on the order of their fields.
+	Digest string
+}
+
+// NewBuildOutput returns the resources of the given build, e.g. after the
+// variables have been substituted, in canonical order along with their
+// digest. Controllers can compare the digest with the one of the last
+// applied build to skip the reconciliation when nothing changed, or record
+// it in the status of the object.
+func NewBuildOutput(resources resmap.ResMap) (*BuildOutput, error) {
+	objects := make([]*unstructured.Unstructured, 0, resources.Size())
+	for _, res := range resources.Resources() {
+		data, err := res.MarshalJSON()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode resource '%s': %w", res.CurId(), err)
+		}
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to decode resource '%s': %w", res.CurId(), err)
+		}
+		objects = append(objects, obj)
+	}
+
+	SortObjects(objects)
+	digest, err := digestSorted(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BuildOutput{
+		Objects: objects,
+		Digest:  digest,
+	}, nil
+}
+
+// SortObjects sorts the given objects in canonical order, by API group,
+// kind, namespace, name and API version.
+func SortObjects(objects []*unstructured.Unstructured) {
+	slices.SortStableFunc(objects, compareObjects)
+}
+
+// Digest returns the digest of the content of the given objects, in the
+// format '<algorithm>:<checksum>'. The objects are not modified, and their
+// order does not affect the digest.
+func Digest(objects []*unstructured.Unstructured) (string, error) {
+	sorted := slices.Clone(objects)
+	SortObjects(sorted)
+	return digestSorted(sorted)
+}
+
+// digestSorted returns the digest of the given objects, which must be in
+// canonical order. The objects are encoded as JSON, which sorts the keys of
+// the maps, so that the order of the fields does not affect the digest.
+func digestSorted(objects []*unstructured.Unstructured) (string, error) {
+	h := sha256.New()
+	for _, obj := range objects {
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode %s '%s': %w", obj.GetKind(), objectRef(obj), err)
+		}
+		h.Write(data)
+		h.Write([]byte{'\n'})
+	}
+	return digestAlgorithm + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func compareObjects(a, b *unstructured.Unstructured) int {
+	ga, gb := a.GroupVersionKind(), b.GroupVersionKind()
+	if c := strings.Compare(ga.Group, gb.Group); c != 0 {
+		return c
+	}
+	if c := strings.Compare(ga.Kind, gb.Kind); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.GetNamespace(), b.GetNamespace()); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.GetName(), b.GetName()); c != 0 {
+		return c
+	}
+	return strings.Compare(ga.Version, gb.Version)
+}
+
+func objectRef(obj *unstructured.Unstructured) string {
+	if ns := obj.GetNamespace(); ns != "" {
+		return ns + "/" + obj.GetName()
+	}
+	return obj.GetName()
+}
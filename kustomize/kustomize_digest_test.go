@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kustomize_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	"github.com/fluxcd/pkg/kustomize"
+)
+
+const (
+	digestConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+  namespace: apps
+data:
+  key: value
+  other: value
+`
+	digestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: apps
+spec:
+  selector:
+    matchLabels:
+      app: app
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: ghcr.io/org/app:v1
+`
+	digestNamespace = `apiVersion: v1
+kind: Namespace
+metadata:
+  name: apps
+`
+)
+
+func TestNewBuildOutput(t *testing.T) {
+	build := func(g *WithT, resources ...string) *kustomize.BuildOutput {
+		fs := filesys.MakeFsInMemory()
+		var names []string
+		for i, res := range resources {
+			name := string(rune('a'+i)) + ".yaml"
+			g.Expect(fs.WriteFile("/app/"+name, []byte(res))).To(Succeed())
+			names = append(names, name)
+		}
+		ks := "apiVersion: kustomize.config.k8s.io/v1beta1\nkind: Kustomization\nresources:\n- " +
+			strings.Join(names, "\n- ") + "\n"
+		g.Expect(fs.WriteFile("/app/kustomization.yaml", []byte(ks))).To(Succeed())
+
+		resMap, err := kustomize.Build(fs, "/app")
+		g.Expect(err).ToNot(HaveOccurred())
+		out, err := kustomize.NewBuildOutput(resMap)
+		g.Expect(err).ToNot(HaveOccurred())
+		return out
+	}
+
+	t.Run("sorts the objects in canonical order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		out := build(g, digestNamespace, digestDeployment, digestConfigMap)
+		var kinds []string
+		for _, obj := range out.Objects {
+			kinds = append(kinds, obj.GetKind())
+		}
+		g.Expect(kinds).To(Equal([]string{"ConfigMap", "Namespace", "Deployment"}))
+		g.Expect(out.Digest).To(HavePrefix("sha256:"))
+	})
+
+	t.Run("digest does not depend on the order", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reordered := strings.Replace(digestConfigMap, "  key: value\n  other: value\n", "  other: value\n  key: value\n", 1)
+		a := build(g, digestNamespace, digestDeployment, digestConfigMap)
+		b := build(g, reordered, digestDeployment, digestNamespace)
+		g.Expect(b.Digest).To(Equal(a.Digest))
+
+		digest, err := kustomize.Digest([]*unstructured.Unstructured{b.Objects[2], b.Objects[0], b.Objects[1]})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(digest).To(Equal(a.Digest))
+	})
+
+	t.Run("digest changes with the content", func(t *testing.T) {
+		g := NewWithT(t)
+
+		a := build(g, digestNamespace, digestDeployment)
+		b := build(g, digestNamespace, strings.Replace(digestDeployment, "app:v1", "app:v2", 1))
+		g.Expect(b.Digest).ToNot(Equal(a.Digest))
+	})
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package masktoken
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// redacted is the replacement for any redacted token.
+const redacted = "*****"
+
+// secretGroup is the name of the subexpression which, when present in a
+// Pattern, limits the redaction to the text it matches. This allows
+// patterns to anchor on a key name (e.g. "AccountKey=") without redacting
+// the key name itself.
+const secretGroup = "secret"
+
+// Confidence expresses how likely it is that a match of a Pattern is an
+// actual secret rather than a false positive.
+type Confidence int
+
+const (
+	// ConfidenceLow is for patterns matching generic shapes, e.g. a string
+	// of a certain length and alphabet without a distinguishing prefix.
+	ConfidenceLow Confidence = iota
+	// ConfidenceMedium is for patterns matching well-known but generic
+	// formats, e.g. JSON Web Tokens.
+	ConfidenceMedium
+	// ConfidenceHigh is for patterns matching a vendor specific prefix or
+	// structure, e.g. GitHub personal access tokens.
+	ConfidenceHigh
+)
+
+// String returns the name of the confidence level.
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("Confidence(%d)", int(c))
+	}
+}
+
+// Pattern is a named regular expression matching a token format.
+type Pattern struct {
+	// Name uniquely identifies the pattern within a Registry.
+	Name string
+	// Regexp matches the token. If it contains a subexpression named
+	// "secret", only the text matched by it is redacted.
+	Regexp *regexp.Regexp
+	// Confidence is the likelihood of a match being an actual secret.
+	Confidence Confidence
+	// Keywords are lowercase literals of which at least one must be
+	// contained in a string (compared case-insensitively) for Regexp to be
+	// evaluated. They allow cheaply skipping patterns on the vast majority
+	// of strings that do not contain a token.
+	Keywords []string
+}
+
+// NewPattern compiles the given expression into a Pattern, with optional
+// keywords used to pre-filter strings before evaluating the expression.
+func NewPattern(name, expr string, confidence Confidence, keywords ...string) (Pattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("invalid expression for pattern '%s': %w", name, err)
+	}
+	lower := make([]string, len(keywords))
+	for i, k := range keywords {
+		lower[i] = strings.ToLower(k)
+	}
+	return Pattern{Name: name, Regexp: re, Confidence: confidence, Keywords: lower}, nil
+}
+
+// MustNewPattern is like NewPattern but panics if the expression cannot be
+// compiled. It simplifies the initialization of global variables holding
+// patterns.
+func MustNewPattern(name, expr string, confidence Confidence, keywords ...string) Pattern {
+	p, err := NewPattern(name, expr, confidence, keywords...)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// BuiltinPatterns returns the patterns for well-known token formats of Git
+// forges and cloud providers. Patterns with ConfidenceLow are included for
+// callers opting in using RedactWithConfidence, but are not applied by
+// Redact or MaskPatternsFromString.
+func BuiltinPatterns() []Pattern {
+	return []Pattern{
+		// GitHub personal access, OAuth, user-to-server, server-to-server
+		// and refresh tokens.
+		MustNewPattern("github-token", `\bgh[pousr]_[0-9A-Za-z]{36,251}\b`, ConfidenceHigh,
+			"ghp_", "gho_", "ghu_", "ghs_", "ghr_"),
+		// GitHub fine-grained personal access tokens.
+		MustNewPattern("github-fine-grained-token", `\bgithub_pat_[0-9A-Za-z_]{82}\b`, ConfidenceHigh, "github_pat_"),
+		// GitLab personal, project, group, deploy, runner and CI/CD job tokens.
+		MustNewPattern("gitlab-token", `\bgl(?:pat|dt|rt|ptt|cbt|soat)-[0-9A-Za-z_\-]{20,}`, ConfidenceHigh,
+			"glpat-", "gldt-", "glrt-", "glptt-", "glcbt-", "glsoat-"),
+		// Bitbucket app passwords.
+		MustNewPattern("bitbucket-app-password", `\bATBB[0-9A-Za-z_=.\-]{32,}`, ConfidenceHigh, "atbb"),
+		// Atlassian API tokens, also used for Bitbucket Cloud.
+		MustNewPattern("atlassian-api-token", `\bATATT3[0-9A-Za-z_=\-]{180,}`, ConfidenceHigh, "atatt3"),
+		// Azure DevOps personal access tokens in the current format, which
+		// contain a fixed "AZDO" signature.
+		MustNewPattern("azure-devops-token", `\b[0-9A-Za-z]{76}AZDO[0-9A-Za-z]{4}\b`, ConfidenceHigh, "azdo"),
+		// Azure DevOps personal access tokens in the legacy format, which
+		// are a base32 encoded random string without a prefix.
+		MustNewPattern("azure-devops-legacy-token", `\b[a-z2-7]{52}\b`, ConfidenceLow),
+		// AWS access key IDs.
+		MustNewPattern("aws-access-key-id", `\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`, ConfidenceHigh,
+			"akia", "asia", "abia", "acca"),
+		// AWS secret access keys assigned to a well-known key name.
+		MustNewPattern("aws-secret-access-key",
+			`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?(?P<secret>[0-9A-Za-z/+]{40})\b`, ConfidenceHigh, "secret"),
+		// GCP API keys.
+		MustNewPattern("gcp-api-key", `\bAIza[0-9A-Za-z_\-]{35}\b`, ConfidenceHigh, "aiza"),
+		// GCP OAuth 2.0 access tokens.
+		MustNewPattern("gcp-access-token", `\bya29\.[0-9A-Za-z_\-]{20,}`, ConfidenceHigh, "ya29."),
+		// Azure Entra ID client secrets.
+		MustNewPattern("azure-client-secret", `\b[0-9A-Za-z_~.]{3}\dQ~[0-9A-Za-z_~.\-]{31,34}`, ConfidenceHigh, "q~"),
+		// Azure Storage account keys in connection strings.
+		MustNewPattern("azure-storage-account-key", `(?i)AccountKey=(?P<secret>[0-9A-Za-z+/]{86}==)`, ConfidenceHigh, "accountkey="),
+		// Azure shared access signatures.
+		MustNewPattern("azure-sas-signature", `\bsig=(?P<secret>[0-9A-Za-z%+/]{43,}(?:=|%3D){0,2})`, ConfidenceMedium, "sig="),
+		// JSON Web Tokens, e.g. OIDC ID tokens or Kubernetes service account
+		// tokens.
+		MustNewPattern("jwt", `\beyJ[0-9A-Za-z_\-]+\.eyJ[0-9A-Za-z_\-]+\.[0-9A-Za-z_\-]+`, ConfidenceMedium, "eyj"),
+		// PEM encoded private keys.
+		MustNewPattern("private-key",
+			`-----BEGIN[ A-Z]*PRIVATE KEY-----[\s\S]*?-----END[ A-Z]*PRIVATE KEY-----`, ConfidenceHigh, "private key"),
+	}
+}
+
+// Registry holds a set of patterns and redacts their matches from strings.
+// It is safe for concurrent use.
+type Registry struct {
+	patterns []Pattern
+	mu       sync.RWMutex
+}
+
+// NewRegistry returns a Registry with the given patterns registered.
+// It panics if the patterns contain duplicate names.
+func NewRegistry(patterns ...Pattern) *Registry {
+	r := &Registry{}
+	for _, p := range patterns {
+		if err := r.Register(p); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds the given pattern to the registry. An error is returned if
+// the pattern has no name or expression, or if a pattern with the same name
+// has already been registered.
+func (r *Registry) Register(p Pattern) error {
+	if p.Name == "" {
+		return fmt.Errorf("pattern name must not be empty")
+	}
+	if p.Regexp == nil {
+		return fmt.Errorf("pattern '%s' has no expression", p.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.patterns {
+		if existing.Name == p.Name {
+			return fmt.Errorf("pattern '%s' is already registered", p.Name)
+		}
+	}
+	r.patterns = append(r.patterns, p)
+	return nil
+}
+
+// Patterns returns a copy of the registered patterns in registration order.
+func (r *Registry) Patterns() []Pattern {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	patterns := make([]Pattern, len(r.patterns))
+	copy(patterns, r.patterns)
+	return patterns
+}
+
+// DefaultConfidence is the minimum confidence of the patterns applied by
+// Redact. Patterns with a lower confidence are likely to match non-secret
+// text, and are only applied when explicitly requested.
+const DefaultConfidence = ConfidenceMedium
+
+// Redact replaces all matches of the registered patterns with at least the
+// DefaultConfidence in the given string with "*****".
+func (r *Registry) Redact(s string) string {
+	return r.RedactWithConfidence(s, DefaultConfidence)
+}
+
+// RedactWithConfidence replaces all matches of the registered patterns with
+// at least the given confidence in the given string with "*****".
+func (r *Registry) RedactWithConfidence(s string, min Confidence) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// lower is computed once and only when a pattern has keywords.
+	var lower string
+	for _, p := range r.patterns {
+		if p.Confidence < min {
+			continue
+		}
+		if len(p.Keywords) > 0 {
+			if lower == "" {
+				lower = strings.ToLower(s)
+			}
+			if !containsAny(lower, p.Keywords) {
+				continue
+			}
+		}
+		if r := redactPattern(s, p.Regexp); r != s {
+			s = r
+			lower = ""
+		}
+	}
+	return s
+}
+
+// containsAny returns true if s contains any of the given substrings.
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactPattern replaces the matches of re in s. If re has a subexpression
+// named "secret", only the text matched by it is replaced.
+func redactPattern(s string, re *regexp.Regexp) string {
+	group := re.SubexpIndex(secretGroup)
+	if group < 0 {
+		return re.ReplaceAllLiteralString(s, redacted)
+	}
+
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	last := 0
+	for _, m := range matches {
+		start, end := m[2*group], m[2*group+1]
+		if start < 0 {
+			continue
+		}
+		b.WriteString(s[last:start])
+		b.WriteString(redacted)
+		last = end
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// defaultRegistry is the registry used by the package level functions.
+var defaultRegistry = NewRegistry(BuiltinPatterns()...)
+
+// DefaultRegistry returns the registry used by the package level functions,
+// which contains the BuiltinPatterns and any pattern registered using
+// RegisterPattern.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// RegisterPattern compiles the given expression and adds it to the default
+// registry. It is intended to be called at controller startup to add
+// patterns for custom token formats. The optional keywords are used to
+// pre-filter strings before evaluating the expression, and should be
+// provided whenever the token format has a literal prefix or marker.
+func RegisterPattern(name, expr string, confidence Confidence, keywords ...string) error {
+	p, err := NewPattern(name, expr, confidence, keywords...)
+	if err != nil {
+		return err
+	}
+	return defaultRegistry.Register(p)
+}
+
+// MaskPatternsFromString redacts all matches of the patterns in the default
+// registry with at least the DefaultConfidence from the provided string,
+// replacing them with "*****".
+// This can for example be used to remove sensitive information from error
+// messages without knowing the token value upfront.
+func MaskPatternsFromString(s string) string {
+	return defaultRegistry.Redact(s)
+}
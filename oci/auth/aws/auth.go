@@ -31,6 +31,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/ecr"
+	"github.com/aws/aws-sdk-go-v2/service/ecrpublic"
 	"github.com/go-logr/logr"
 	"github.com/google/go-containerregistry/pkg/authn"
 
@@ -38,12 +39,33 @@ import (
 )
 
 // This regex is sourced from the AWS ECR Credential Helper (https://github.com/awslabs/amazon-ecr-credential-helper).
-// It covers both public AWS partitions like amazonaws.com, China partitions like amazonaws.com.cn, and non-public partitions.
-var registryPartRe = regexp.MustCompile(`([0-9+]*).dkr.ecr(?:-fips)?\.([^/.]*)\.(amazonaws\.com[.cn]*|sc2s\.sgov\.gov|c2s\.ic\.gov|cloud\.adc-e\.uk|csp\.hci\.ic\.gov)`)
+// It covers both public AWS partitions like amazonaws.com, China partitions like amazonaws.com.cn, GovCloud partitions
+// like amazonaws-us-gov.com, and non-public partitions.
+var registryPartRe = regexp.MustCompile(`([0-9+]*).dkr.ecr(?:-fips)?\.([^/.]*)\.(amazonaws\.com[.cn]*|amazonaws-us-gov\.com|sc2s\.sgov\.gov|c2s\.ic\.gov|cloud\.adc-e\.uk|csp\.hci\.ic\.gov)`)
+
+const (
+	// PublicRegistry is the host of the ECR Public gallery.
+	PublicRegistry = "public.ecr.aws"
+
+	// PublicRegistryRegion is the region of the ECR Public API, which is
+	// only available in us-east-1.
+	PublicRegistryRegion = "us-east-1"
+)
+
+// IsPublicRegistry returns true if the image registry/repository is hosted in
+// the ECR Public gallery.
+func IsPublicRegistry(registry string) bool {
+	if i := strings.Index(registry, "://"); i >= 0 {
+		registry = registry[i+3:]
+	}
+	host, _, _ := strings.Cut(registry, "/")
+	return host == PublicRegistry
+}
 
 // ParseRegistry returns the AWS account ID and region and `true` if
 // the image registry/repository is hosted in AWS's Elastic Container Registry,
-// otherwise empty strings and `false`.
+// otherwise empty strings and `false`. The ECR Public gallery is not matched,
+// see IsPublicRegistry.
 func ParseRegistry(registry string) (accountId, awsEcrRegion string, ok bool) {
 	registryParts := registryPartRe.FindAllStringSubmatch(registry, -1)
 	if len(registryParts) < 1 || len(registryParts[0]) < 3 {
@@ -52,6 +74,16 @@ func ParseRegistry(registry string) (accountId, awsEcrRegion string, ok bool) {
 	return registryParts[0][1], registryParts[0][2], true
 }
 
+// parseLoginRegistry returns the region of the ECR API to request the
+// authorization token from and whether the registry is the ECR Public gallery.
+func parseLoginRegistry(registry string) (awsEcrRegion string, public, ok bool) {
+	if IsPublicRegistry(registry) {
+		return PublicRegistryRegion, true, true
+	}
+	_, awsEcrRegion, ok = ParseRegistry(registry)
+	return awsEcrRegion, false, ok
+}
+
 // Client is a AWS ECR client which can log into the registry and return
 // authorization information.
 type Client struct {
@@ -92,59 +124,94 @@ func (c *Client) WithConfig(cfg *aws.Config) {
 	}
 }
 
-// getLoginAuth obtains authentication for ECR given the
-// region (taken from the image). This assumes that the pod has
-// IAM permissions to get an authentication token, which will usually
-// be the case if it's running in EKS, and may need additional setup
-// otherwise (visit https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/
-// as a starting point).
-func (c *Client) getLoginAuth(ctx context.Context, awsEcrRegion string) (authn.AuthConfig, time.Time, error) {
-	var authConfig authn.AuthConfig
-	var cfg aws.Config
-
+// loadConfig returns a copy of the configuration of the client for the given
+// region, loading the default configuration if it's uninitialized.
+func (c *Client) loadConfig(ctx context.Context, region string) (aws.Config, error) {
 	c.mu.Lock()
-	if c.config != nil {
-		cfg = c.config.Copy()
-	} else {
+	defer c.mu.Unlock()
+
+	if c.config == nil {
 		var confOpts []func(*config.LoadOptions) error
-		confOpts = append(confOpts, config.WithRegion(awsEcrRegion))
+		confOpts = append(confOpts, config.WithRegion(region))
 		if c.proxyURL != nil {
 			transport := http.DefaultTransport.(*http.Transport).Clone()
 			transport.Proxy = http.ProxyURL(c.proxyURL)
 			confOpts = append(confOpts, config.WithHTTPClient(&http.Client{Transport: transport}))
 		}
 
-		var err error
-		cfg, err = config.LoadDefaultConfig(ctx, confOpts...)
+		cfg, err := config.LoadDefaultConfig(ctx, confOpts...)
 		if err != nil {
-			c.mu.Unlock()
-			return authConfig, time.Time{}, fmt.Errorf("failed to load default configuration: %w", err)
+			return aws.Config{}, fmt.Errorf("failed to load default configuration: %w", err)
 		}
 		c.config = &cfg
 	}
-	c.mu.Unlock()
 
-	ecrService := ecr.NewFromConfig(cfg)
-	// NOTE: ecr.GetAuthorizationTokenInput has deprecated RegistryIds. Hence,
-	// pass nil input.
-	ecrToken, err := ecrService.GetAuthorizationToken(ctx, nil)
+	// The tokens must be requested in the region of the registry or
+	// repository, which determines its partition, e.g. China or GovCloud,
+	// while the configuration is loaded for the region of the first one.
+	cfg := c.config.Copy()
+	cfg.Region = region
+	return cfg, nil
+}
+
+// getLoginAuth obtains authentication for ECR given the
+// region (taken from the image). This assumes that the pod has
+// IAM permissions to get an authentication token, which will usually
+// be the case if it's running in EKS, and may need additional setup
+// otherwise (visit https://aws.github.io/aws-sdk-go-v2/docs/configuring-sdk/
+// as a starting point).
+func (c *Client) getLoginAuth(ctx context.Context, awsEcrRegion string) (authn.AuthConfig, time.Time, error) {
+	return c.getRegistryLoginAuth(ctx, awsEcrRegion, false)
+}
+
+// getRegistryLoginAuth obtains authentication for a private ECR registry in
+// the given region, or for the ECR Public gallery if public is true.
+func (c *Client) getRegistryLoginAuth(ctx context.Context, awsEcrRegion string, public bool) (authn.AuthConfig, time.Time, error) {
+	var authConfig authn.AuthConfig
+
+	cfg, err := c.loadConfig(ctx, awsEcrRegion)
 	if err != nil {
 		return authConfig, time.Time{}, err
 	}
 
-	// Validate the authorization data.
-	if len(ecrToken.AuthorizationData) == 0 {
-		return authConfig, time.Time{}, errors.New("no authorization data")
+	var token *string
+	var expiresAt *time.Time
+	if public {
+		ecrPublicService := ecrpublic.NewFromConfig(cfg)
+		ecrToken, err := ecrPublicService.GetAuthorizationToken(ctx, &ecrpublic.GetAuthorizationTokenInput{})
+		if err != nil {
+			return authConfig, time.Time{}, err
+		}
+		if ecrToken.AuthorizationData == nil {
+			return authConfig, time.Time{}, errors.New("no authorization data")
+		}
+		token = ecrToken.AuthorizationData.AuthorizationToken
+		expiresAt = ecrToken.AuthorizationData.ExpiresAt
+	} else {
+		ecrService := ecr.NewFromConfig(cfg)
+		// NOTE: ecr.GetAuthorizationTokenInput has deprecated RegistryIds. Hence,
+		// pass nil input.
+		ecrToken, err := ecrService.GetAuthorizationToken(ctx, nil)
+		if err != nil {
+			return authConfig, time.Time{}, err
+		}
+		// Validate the authorization data.
+		if len(ecrToken.AuthorizationData) == 0 {
+			return authConfig, time.Time{}, errors.New("no authorization data")
+		}
+		token = ecrToken.AuthorizationData[0].AuthorizationToken
+		expiresAt = ecrToken.AuthorizationData[0].ExpiresAt
 	}
-	if ecrToken.AuthorizationData[0].AuthorizationToken == nil {
+
+	if token == nil {
 		return authConfig, time.Time{}, fmt.Errorf("no authorization token")
 	}
-	token, err := base64.StdEncoding.DecodeString(*ecrToken.AuthorizationData[0].AuthorizationToken)
+	decodedToken, err := base64.StdEncoding.DecodeString(*token)
 	if err != nil {
 		return authConfig, time.Time{}, err
 	}
 
-	tokenSplit := strings.Split(string(token), ":")
+	tokenSplit := strings.Split(string(decodedToken), ":")
 	// Validate the tokens.
 	if len(tokenSplit) != 2 {
 		return authConfig, time.Time{}, fmt.Errorf("invalid authorization token, expected the token to have two parts separated by ':', got %d parts", len(tokenSplit))
@@ -153,7 +220,6 @@ func (c *Client) getLoginAuth(ctx context.Context, awsEcrRegion string) (authn.A
 		Username: tokenSplit[0],
 		Password: tokenSplit[1],
 	}
-	expiresAt := ecrToken.AuthorizationData[0].ExpiresAt
 	if expiresAt == nil {
 		expiresAt = &time.Time{}
 	}
@@ -165,12 +231,12 @@ func (c *Client) getLoginAuth(ctx context.Context, awsEcrRegion string) (authn.A
 func (c *Client) LoginWithExpiry(ctx context.Context, autoLogin bool, image string) (authn.Authenticator, time.Time, error) {
 	if autoLogin {
 		logr.FromContextOrDiscard(ctx).Info("logging in to AWS ECR for " + image)
-		_, awsEcrRegion, ok := ParseRegistry(image)
+		awsEcrRegion, public, ok := parseLoginRegistry(image)
 		if !ok {
 			return nil, time.Time{}, errors.New("failed to parse AWS ECR image, invalid ECR image")
 		}
 
-		authConfig, expiresAt, err := c.getLoginAuth(ctx, awsEcrRegion)
+		authConfig, expiresAt, err := c.getRegistryLoginAuth(ctx, awsEcrRegion, public)
 		if err != nil {
 			return nil, time.Time{}, err
 		}
@@ -191,12 +257,12 @@ func (c *Client) Login(ctx context.Context, autoLogin bool, image string) (authn
 //
 // Deprecated: Use LoginWithExpiry instead.
 func (c *Client) OIDCLogin(ctx context.Context, registryURL string) (authn.Authenticator, error) {
-	_, awsEcrRegion, ok := ParseRegistry(registryURL)
+	awsEcrRegion, public, ok := parseLoginRegistry(registryURL)
 	if !ok {
 		return nil, errors.New("failed to parse AWS ECR image, invalid ECR image")
 	}
 
-	authConfig, _, err := c.getLoginAuth(ctx, awsEcrRegion)
+	authConfig, _, err := c.getRegistryLoginAuth(ctx, awsEcrRegion, public)
 	if err != nil {
 		return nil, err
 	}
@@ -101,6 +101,16 @@ func TestParseRegistry(t *testing.T) {
 			wantRegion:    "us-ts-region",
 			wantOK:        true,
 		},
+		{
+			registry:      "012345678901.dkr.ecr.us-gov-west-1.amazonaws-us-gov.com/foo",
+			wantAccountID: "012345678901",
+			wantRegion:    "us-gov-west-1",
+			wantOK:        true,
+		},
+		{
+			registry: "public.ecr.aws/org/app:v1",
+			wantOK:   false,
+		},
 		// TODO: Fix: this invalid registry is allowed by the regex.
 		// {
 		// 	registry: ".dkr.ecr.error.amazonaws.com",
@@ -124,6 +134,27 @@ func TestParseRegistry(t *testing.T) {
 	}
 }
 
+func TestIsPublicRegistry(t *testing.T) {
+	tests := []struct {
+		registry string
+		want     bool
+	}{
+		{registry: "public.ecr.aws", want: true},
+		{registry: "public.ecr.aws/org/app:v1", want: true},
+		{registry: "oci://public.ecr.aws/org/chart", want: true},
+		{registry: "012345678901.dkr.ecr.us-east-1.amazonaws.com/foo", want: false},
+		{registry: "public.ecr.aws.example.com/org/app", want: false},
+		{registry: "example.com/public.ecr.aws/app", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsPublicRegistry(tt.registry)).To(Equal(tt.want))
+		})
+	}
+}
+
 func TestGetLoginAuth(t *testing.T) {
 	authorizationData := fmt.Sprintf(`{"authorizationData": [{"authorizationToken": "c29tZS1rZXk6c29tZS1zZWNyZXQ=","expiresAt": %d}]}`, time.Now().Add(1*time.Hour).Unix())
 	tests := []struct {
@@ -216,6 +247,32 @@ func TestGetLoginAuth(t *testing.T) {
 	}
 }
 
+func TestGetLoginAuth_public(t *testing.T) {
+	g := NewWithT(t)
+
+	var target string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("X-Amz-Target")
+		fmt.Fprintf(w, `{"authorizationData": {"authorizationToken": "c29tZS1rZXk6c29tZS1zZWNyZXQ=","expiresAt": %d}}`,
+			time.Now().Add(1*time.Hour).Unix())
+	}))
+	t.Cleanup(srv.Close)
+
+	ec := NewClient()
+	cfg := aws.NewConfig()
+	cfg.EndpointResolverWithOptions = aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+		return aws.Endpoint{URL: srv.URL}, nil
+	})
+	cfg.Credentials = credentials.NewStaticCredentialsProvider("x", "y", "z")
+	ec.WithConfig(cfg)
+
+	a, expiresAt, err := ec.getRegistryLoginAuth(context.TODO(), PublicRegistryRegion, true)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(target).To(Equal("SpencerFrontendService.GetAuthorizationToken"))
+	g.Expect(a).To(Equal(authn.AuthConfig{Username: "some-key", Password: "some-secret"}))
+	g.Expect(expiresAt).To(BeTemporally("~", time.Now().Add(1*time.Hour), time.Second))
+}
+
 func TestLogin(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/codeartifact"
+
+	"github.com/fluxcd/pkg/oci"
+)
+
+// codeArtifactRepositoryRe matches the endpoints of the AWS CodeArtifact
+// repositories, e.g.
+// https://my-domain-111122223333.d.codeartifact.us-west-2.amazonaws.com/maven/my-repo/.
+var codeArtifactRepositoryRe = regexp.MustCompile(`^(?:https?://)?([a-z0-9][a-z0-9-]*)-([0-9]{12})\.d\.codeartifact\.([a-z0-9-]+)\.(amazonaws\.com(?:\.cn)?)(?:/([a-z]+)(?:/([^/?#]+))?)?`)
+
+// CodeArtifactUsername is the username of the credentials of the
+// CodeArtifact repositories.
+const CodeArtifactUsername = "aws"
+
+// CodeArtifactRepository is the endpoint of an AWS CodeArtifact repository.
+type CodeArtifactRepository struct {
+	// Domain is the CodeArtifact domain of the repository.
+	Domain string
+	// DomainOwner is the AWS account ID owning the domain.
+	DomainOwner string
+	// Region is the AWS region of the domain.
+	Region string
+	// Format is the package format of the endpoint, e.g. "maven", "npm",
+	// "pypi" or "generic", if any.
+	Format string
+	// Repository is the name of the repository, if any.
+	Repository string
+}
+
+// ParseCodeArtifactRepository returns the CodeArtifact repository and `true`
+// if the URL is the endpoint of an AWS CodeArtifact repository, otherwise nil
+// and `false`.
+func ParseCodeArtifactRepository(repositoryURL string) (*CodeArtifactRepository, bool) {
+	parts := codeArtifactRepositoryRe.FindStringSubmatch(repositoryURL)
+	if parts == nil {
+		return nil, false
+	}
+	return &CodeArtifactRepository{
+		Domain:      parts[1],
+		DomainOwner: parts[2],
+		Region:      parts[3],
+		Format:      parts[5],
+		Repository:  parts[6],
+	}, true
+}
+
+// GetCodeArtifactCredentials obtains credentials for the AWS CodeArtifact
+// repository at the given URL, for any of its package formats, e.g. Maven,
+// npm or generic packages. This assumes that the pod has IAM permissions to
+// get an authorization token for the domain of the repository, i.e. the
+// codeartifact:GetAuthorizationToken and sts:GetServiceBearerToken actions.
+// The token is valid for the default duration of CodeArtifact, 12 hours,
+// unless the role session expires sooner.
+func (c *Client) GetCodeArtifactCredentials(ctx context.Context, repositoryURL string) (*oci.ArtifactCredentials, error) {
+	repo, ok := ParseCodeArtifactRepository(repositoryURL)
+	if !ok {
+		return nil, fmt.Errorf("invalid AWS CodeArtifact repository URL '%s'", repositoryURL)
+	}
+
+	cfg, err := c.loadConfig(ctx, repo.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := codeartifact.NewFromConfig(cfg).GetAuthorizationToken(ctx, &codeartifact.GetAuthorizationTokenInput{
+		Domain:      aws.String(repo.Domain),
+		DomainOwner: aws.String(repo.DomainOwner),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get a CodeArtifact authorization token: %w", err)
+	}
+	if aws.ToString(token.AuthorizationToken) == "" {
+		return nil, errors.New("no authorization token")
+	}
+
+	var expiresAt time.Time
+	if token.Expiration != nil {
+		expiresAt = *token.Expiration
+	}
+	return &oci.ArtifactCredentials{
+		Username:  CodeArtifactUsername,
+		Password:  *token.AuthorizationToken,
+		ExpiresAt: expiresAt,
+	}, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	. "github.com/onsi/gomega"
+)
+
+const testCodeArtifactRepository = "https://my-domain-111122223333.d.codeartifact.us-west-2.amazonaws.com/maven/my-repo/"
+
+func TestParseCodeArtifactRepository(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   *CodeArtifactRepository
+		wantOK bool
+	}{
+		{
+			url: testCodeArtifactRepository,
+			want: &CodeArtifactRepository{
+				Domain:      "my-domain",
+				DomainOwner: "111122223333",
+				Region:      "us-west-2",
+				Format:      "maven",
+				Repository:  "my-repo",
+			},
+			wantOK: true,
+		},
+		{
+			url: "my-domain-111122223333.d.codeartifact.us-west-2.amazonaws.com/npm/my-repo",
+			want: &CodeArtifactRepository{
+				Domain:      "my-domain",
+				DomainOwner: "111122223333",
+				Region:      "us-west-2",
+				Format:      "npm",
+				Repository:  "my-repo",
+			},
+			wantOK: true,
+		},
+		{
+			url: "https://domain-111122223333.d.codeartifact.cn-north-1.amazonaws.com.cn",
+			want: &CodeArtifactRepository{
+				Domain:      "domain",
+				DomainOwner: "111122223333",
+				Region:      "cn-north-1",
+			},
+			wantOK: true,
+		},
+		{
+			url:    "https://111122223333.d.codeartifact.us-west-2.amazonaws.com/maven/my-repo/",
+			wantOK: false,
+		},
+		{
+			url:    "012345678901.dkr.ecr.us-east-1.amazonaws.com/foo:v1",
+			wantOK: false,
+		},
+		{
+			url:    "https://repo.maven.apache.org/maven2",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			g := NewWithT(t)
+
+			repo, ok := ParseCodeArtifactRepository(tt.url)
+			g.Expect(ok).To(Equal(tt.wantOK))
+			g.Expect(repo).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetCodeArtifactCredentials(t *testing.T) {
+	expiration := time.Now().Add(12 * time.Hour).Truncate(time.Second)
+
+	tests := []struct {
+		name         string
+		url          string
+		statusCode   int
+		responseBody string
+		wantErr      string
+	}{
+		{
+			name:         "success",
+			url:          testCodeArtifactRepository,
+			statusCode:   http.StatusOK,
+			responseBody: fmt.Sprintf(`{"authorizationToken": "some-token", "expiration": %d}`, expiration.Unix()),
+		},
+		{
+			name:    "invalid URL",
+			url:     "https://repo.maven.apache.org/maven2",
+			wantErr: "invalid AWS CodeArtifact repository URL",
+		},
+		{
+			name:         "access denied",
+			url:          testCodeArtifactRepository,
+			statusCode:   http.StatusForbidden,
+			responseBody: `{"message": "not authorized"}`,
+			wantErr:      "StatusCode: 403",
+		},
+		{
+			name:         "no token",
+			url:          testCodeArtifactRepository,
+			statusCode:   http.StatusOK,
+			responseBody: `{}`,
+			wantErr:      "no authorization token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var req *http.Request
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				req = r
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.responseBody))
+			}))
+			t.Cleanup(srv.Close)
+
+			client := NewClient()
+			cfg := aws.NewConfig()
+			cfg.EndpointResolverWithOptions = aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+				return aws.Endpoint{URL: srv.URL}, nil
+			})
+			cfg.Credentials = credentials.NewStaticCredentialsProvider("x", "y", "z")
+			client.WithConfig(cfg)
+
+			creds, err := client.GetCodeArtifactCredentials(context.TODO(), tt.url)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(creds.Username).To(Equal(CodeArtifactUsername))
+			g.Expect(creds.Password).To(Equal("some-token"))
+			g.Expect(creds.ExpiresAt).To(BeTemporally("==", expiration))
+
+			g.Expect(req.Method).To(Equal(http.MethodPost))
+			g.Expect(req.URL.Path).To(Equal("/v1/authorization-token"))
+			g.Expect(req.URL.Query().Get("domain")).To(Equal("my-domain"))
+			g.Expect(req.URL.Query().Get("domain-owner")).To(Equal("111122223333"))
+			g.Expect(req.Header.Get("Authorization")).To(HavePrefix("AWS4-HMAC-SHA256 Credential=x/"))
+			g.Expect(req.Header.Get("Authorization")).To(ContainSubstring("/us-west-2/codeartifact/aws4_request"))
+			g.Expect(req.Header.Get("X-Amz-Security-Token")).To(Equal("z"))
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+
+	"github.com/fluxcd/pkg/oci"
+)
+
+const (
+	// AzureDevOpsScope is the scope of the Microsoft Entra tokens accepted
+	// by Azure DevOps, including the Azure Artifacts feeds.
+	AzureDevOpsScope = "499b84ac-1321-427f-aa17-267ca6975798/.default"
+
+	// ArtifactsFeedUsername is the username of the credentials of the Azure
+	// Artifacts feeds, which ignore the username of the basic authentication
+	// with a Microsoft Entra token.
+	ArtifactsFeedUsername = "AzureDevOps"
+
+	// artifactsFeedHost is the host of the Azure Artifacts feeds, and
+	// artifactsFeedLegacySuffix the DNS suffix of the legacy feed URLs.
+	artifactsFeedHost         = "pkgs.dev.azure.com"
+	artifactsFeedLegacySuffix = ".pkgs.visualstudio.com"
+)
+
+// IsArtifactsFeed returns true if the URL is the endpoint of an Azure
+// Artifacts feed, e.g.
+// https://pkgs.dev.azure.com/my-org/my-project/_packaging/my-feed/maven/v1 or
+// https://my-org.pkgs.visualstudio.com/_packaging/my-feed/npm/registry/.
+func IsArtifactsFeed(feedURL string) bool {
+	if !strings.Contains(feedURL, "://") {
+		feedURL = "https://" + feedURL
+	}
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return false
+	}
+	host := u.Hostname()
+	if host != artifactsFeedHost && !strings.HasSuffix(host, artifactsFeedLegacySuffix) {
+		return false
+	}
+	return strings.Contains(u.Path+"/", "/_packaging/")
+}
+
+// GetArtifactsFeedCredentials obtains credentials for the Azure Artifacts
+// feed at the given URL, for any of its package types, e.g. Maven, npm or
+// NuGet packages, with a Microsoft Entra token of the token credential of
+// the client, or the default credential, e.g. a workload identity. The
+// identity must be a member of the Azure DevOps organization of the feed
+// with access to the feed. Azure DevOps is only available in the public
+// cloud, whose authority is used unless a cloud is set with WithCloud.
+func (c *Client) GetArtifactsFeedCredentials(ctx context.Context, feedURL string) (*oci.ArtifactCredentials, error) {
+	if !IsArtifactsFeed(feedURL) {
+		return nil, fmt.Errorf("invalid Azure Artifacts feed URL '%s'", feedURL)
+	}
+
+	cfg := cloud.AzurePublic
+	if c.cloud != nil {
+		cfg = *c.cloud
+	}
+	credential, err := c.tokenCredential(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := credential.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{AzureDevOpsScope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get an Azure DevOps token: %w", err)
+	}
+
+	return &oci.ArtifactCredentials{
+		Username:  ArtifactsFeedUsername,
+		Password:  token.Token,
+		ExpiresAt: token.ExpiresOn,
+	}, nil
+}
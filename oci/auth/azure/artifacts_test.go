@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestIsArtifactsFeed(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://pkgs.dev.azure.com/my-org/my-project/_packaging/my-feed/maven/v1", want: true},
+		{url: "https://pkgs.dev.azure.com/my-org/_packaging/my-feed/npm/registry/", want: true},
+		{url: "pkgs.dev.azure.com/my-org/_packaging/my-feed/nuget/v3/index.json", want: true},
+		{url: "https://my-org.pkgs.visualstudio.com/_packaging/my-feed/npm/registry/", want: true},
+		{url: "https://pkgs.dev.azure.com/my-org/my-project/_git/my-repo", want: false},
+		{url: "https://dev.azure.com/my-org/_packaging/my-feed/maven/v1", want: false},
+		{url: "https://pkgs.dev.azure.com.example.com/my-org/_packaging/my-feed", want: false},
+		{url: "myregistry.azurecr.io/app:v1", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(IsArtifactsFeed(tt.url)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestGetArtifactsFeedCredentials(t *testing.T) {
+	expiresOn := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		url      string
+		tokenErr error
+		wantErr  string
+	}{
+		{
+			name: "success",
+			url:  "https://pkgs.dev.azure.com/my-org/my-project/_packaging/my-feed/maven/v1",
+		},
+		{
+			name:    "invalid URL",
+			url:     "https://repo.maven.apache.org/maven2",
+			wantErr: "invalid Azure Artifacts feed URL",
+		},
+		{
+			name:     "token error",
+			url:      "https://pkgs.dev.azure.com/my-org/my-project/_packaging/my-feed/maven/v1",
+			tokenErr: errors.New("no identity"),
+			wantErr:  "failed to get an Azure DevOps token: no identity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			credential := &FakeTokenCredential{Token: "entra-token", ExpiresOn: expiresOn, Err: tt.tokenErr}
+			client := NewClient().WithTokenCredential(credential)
+
+			creds, err := client.GetArtifactsFeedCredentials(context.TODO(), tt.url)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(credential.Scopes).To(Equal([]string{AzureDevOpsScope}))
+			g.Expect(creds.Username).To(Equal(ArtifactsFeedUsername))
+			g.Expect(creds.Password).To(Equal("entra-token"))
+			g.Expect(creds.ExpiresAt).To(Equal(expiresOn))
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
@@ -44,9 +45,16 @@ const defaultCacheExpirationInSeconds = 600
 // Client is an Azure ACR client which can log into the registry and return
 // authorization information.
 type Client struct {
-	credential azcore.TokenCredential
-	scheme     string
-	proxyURL   *url.URL
+	credential       azcore.TokenCredential
+	scheme           string
+	proxyURL         *url.URL
+	cloud            *cloud.Configuration
+	registrySuffixes []string
+
+	// defaultCredentials are the default credentials of the clouds,
+	// keyed by their Microsoft Entra authority host.
+	defaultCredentials   map[string]azcore.TokenCredential
+	defaultCredentialsMu sync.Mutex
 }
 
 // Option is a functional option for configuring the client.
@@ -59,6 +67,28 @@ func WithProxyURL(proxyURL *url.URL) Option {
 	}
 }
 
+// WithCloud sets the Azure cloud of the registries, e.g. cloud.AzureChina,
+// overriding the cloud detected from the DNS suffix of the registries and
+// the EnvironmentEnvVar environment variable. The cloud determines the
+// Microsoft Entra authority and the Resource Manager audience of the
+// tokens. Use it for sovereign or private clouds with a custom
+// configuration.
+func WithCloud(cfg cloud.Configuration) Option {
+	return func(c *Client) {
+		c.cloud = &cfg
+	}
+}
+
+// WithRegistrySuffixes sets additional DNS suffixes of ACR, e.g.
+// ".azurecr.example.com", for the registries of private clouds. The
+// registries with a custom suffix use the cloud set with WithCloud or the
+// EnvironmentEnvVar environment variable, and the public cloud otherwise.
+func WithRegistrySuffixes(suffixes ...string) Option {
+	return func(c *Client) {
+		c.registrySuffixes = append(c.registrySuffixes, suffixes...)
+	}
+}
+
 // NewClient creates a new ACR client with default configurations.
 func NewClient(opts ...Option) *Client {
 	client := &Client{scheme: "https"}
@@ -86,27 +116,14 @@ func (c *Client) WithScheme(scheme string) *Client {
 func (c *Client) getLoginAuth(ctx context.Context, registryURL string) (authn.AuthConfig, time.Time, error) {
 	var authConfig authn.AuthConfig
 
-	// Use default credentials if no token credential is provided.
-	// NOTE: NewDefaultAzureCredential() performs a lot of environment lookup
-	// for creating default token credential. Load it only when it's needed.
-	if c.credential == nil {
-		opts := &azidentity.DefaultAzureCredentialOptions{}
-		if c.proxyURL != nil {
-			transport := http.DefaultTransport.(*http.Transport).Clone()
-			transport.Proxy = http.ProxyURL(c.proxyURL)
-			opts.Transport = &http.Client{Transport: transport}
-		}
-
-		cred, err := azidentity.NewDefaultAzureCredential(opts)
-		if err != nil {
-			return authConfig, time.Time{}, err
-		}
-		c.credential = cred
+	configurationEnvironment := c.cloudConfiguration(registryURL)
+	credential, err := c.tokenCredential(configurationEnvironment)
+	if err != nil {
+		return authConfig, time.Time{}, err
 	}
 
-	configurationEnvironment := getCloudConfiguration(registryURL)
 	// Obtain access token using the token credential.
-	armToken, err := c.credential.GetToken(ctx, policy.TokenRequestOptions{
+	armToken, err := credential.GetToken(ctx, policy.TokenRequestOptions{
 		Scopes: []string{configurationEnvironment.Services[cloud.ResourceManager].Endpoint + "/" + ".default"},
 	})
 	if err != nil {
@@ -130,23 +147,83 @@ func (c *Client) getLoginAuth(ctx context.Context, registryURL string) (authn.Au
 	}, expiresAt, nil
 }
 
+// tokenCredential returns the token credential of the client if set, or the
+// default credential of the given cloud. The default credentials are
+// created on first use, as NewDefaultAzureCredential() performs a lot of
+// environment lookup.
+func (c *Client) tokenCredential(cfg cloud.Configuration) (azcore.TokenCredential, error) {
+	if c.credential != nil {
+		return c.credential, nil
+	}
+
+	c.defaultCredentialsMu.Lock()
+	defer c.defaultCredentialsMu.Unlock()
+	if cred, ok := c.defaultCredentials[cfg.ActiveDirectoryAuthorityHost]; ok {
+		return cred, nil
+	}
+
+	opts := &azidentity.DefaultAzureCredentialOptions{}
+	opts.Cloud = cfg
+	if c.proxyURL != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(c.proxyURL)
+		opts.Transport = &http.Client{Transport: transport}
+	}
+	cred, err := azidentity.NewDefaultAzureCredential(opts)
+	if err != nil {
+		return nil, err
+	}
+	if c.defaultCredentials == nil {
+		c.defaultCredentials = make(map[string]azcore.TokenCredential)
+	}
+	c.defaultCredentials[cfg.ActiveDirectoryAuthorityHost] = cred
+	return cred, nil
+}
+
+// cloudConfiguration returns the cloud configuration of the registry, which
+// is the cloud set with WithCloud if any.
+func (c *Client) cloudConfiguration(registryURL string) cloud.Configuration {
+	if c.cloud != nil {
+		return *c.cloud
+	}
+	return getCloudConfiguration(registryURL)
+}
+
 // getCloudConfiguration returns the cloud configuration based on the registry URL.
-// List from https://github.com/Azure/azure-sdk-for-go/blob/main/sdk/containers/azcontainerregistry/cloud_config.go#L16
+// The registries which are not identified by their DNS suffix use the cloud
+// selected with the EnvironmentEnvVar environment variable, if any, and the
+// public cloud otherwise.
 func getCloudConfiguration(url string) cloud.Configuration {
-	switch {
-	case strings.HasSuffix(url, ".azurecr.cn"):
-		return cloud.AzureChina
-	case strings.HasSuffix(url, ".azurecr.us"):
-		return cloud.AzureGovernment
-	default:
-		return cloud.AzurePublic
+	host := trimRegistryHost(url)
+	for _, v := range registrySuffixes {
+		if strings.HasSuffix(host, v.suffix) {
+			return v.cloud
+		}
+	}
+	if cfg, ok := cloudFromEnvironment(); ok {
+		return cfg
 	}
+	return cloud.AzurePublic
 }
 
 // ValidHost returns if a given host is a Azure container registry.
 // List from https://github.com/kubernetes/kubernetes/blob/v1.23.1/pkg/credentialprovider/azure/azure_credentials.go#L55
 func ValidHost(host string) bool {
-	for _, v := range []string{".azurecr.io", ".azurecr.cn", ".azurecr.de", ".azurecr.us"} {
+	for _, v := range registrySuffixes {
+		if strings.HasSuffix(host, v.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidHost returns if a given host is a Azure container registry, including
+// the registries with a DNS suffix set with WithRegistrySuffixes.
+func (c *Client) ValidHost(host string) bool {
+	if ValidHost(host) {
+		return true
+	}
+	for _, v := range c.registrySuffixes {
 		if strings.HasSuffix(host, v) {
 			return true
 		}
@@ -156,7 +233,8 @@ func ValidHost(host string) bool {
 
 // LoginWithExpiry attempts to get the authentication material for ACR.
 // It returns the authentication material and the expiry time of the token.
-// The caller can ensure that the passed image is a valid ACR image using ValidHost().
+// The caller can ensure that the passed image is a valid ACR image using ValidHost(),
+// or the ValidHost method of the client for custom DNS suffixes.
 func (c *Client) LoginWithExpiry(ctx context.Context, autoLogin bool, image string, ref name.Reference) (authn.Authenticator, time.Time, error) {
 	if autoLogin {
 		logr.FromContextOrDiscard(ctx).Info("logging in to Azure ACR for " + image)
@@ -127,6 +127,8 @@ func TestGetCloudConfiguration(t *testing.T) {
 		{"foo.azurecr.cn", cloud.AzureChina},
 		{"foo.azurecr.de", cloud.AzurePublic},
 		{"foo.azurecr.us", cloud.AzureGovernment},
+		{"https://foo.azurecr.us", cloud.AzureGovernment},
+		{"foo.azurecr.example.com", cloud.AzurePublic},
 	}
 
 	for _, tt := range tests {
@@ -137,6 +139,73 @@ func TestGetCloudConfiguration(t *testing.T) {
 	}
 }
 
+func TestGetCloudConfiguration_environment(t *testing.T) {
+	tests := []struct {
+		env    string
+		host   string
+		result cloud.Configuration
+	}{
+		{"AzureChinaCloud", "foo.azurecr.example.cn", cloud.AzureChina},
+		{"azureusgovernment", "foo.azurecr.example.us", cloud.AzureGovernment},
+		{"AzureChinaCloud", "foo.azurecr.us", cloud.AzureGovernment},
+		{"AzureMoonCloud", "foo.azurecr.example.com", cloud.AzurePublic},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+"/"+tt.host, func(t *testing.T) {
+			g := NewWithT(t)
+			t.Setenv(EnvironmentEnvVar, tt.env)
+			g.Expect(getCloudConfiguration(tt.host)).To(Equal(tt.result))
+		})
+	}
+}
+
+func TestClient_cloud(t *testing.T) {
+	g := NewWithT(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"refresh_token": "bbbbb"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	cred := &FakeTokenCredential{Token: "foo"}
+	c := NewClient(WithCloud(cloud.AzureChina), WithRegistrySuffixes(".azurecr.example.cn")).
+		WithTokenCredential(cred).
+		WithScheme("http")
+
+	g.Expect(c.ValidHost("foo.azurecr.example.cn")).To(BeTrue())
+	g.Expect(c.ValidHost("foo.azurecr.io")).To(BeTrue())
+	g.Expect(c.ValidHost("foo.example.cn")).To(BeFalse())
+	g.Expect(ValidHost("foo.azurecr.example.cn")).To(BeFalse())
+
+	_, _, err := c.getLoginAuth(context.TODO(), srv.URL)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cred.Scopes).To(Equal([]string{cloud.AzureChina.Services[cloud.ResourceManager].Endpoint + "/" + ".default"}))
+}
+
+func TestCloudFromEnvironmentName(t *testing.T) {
+	tests := []struct {
+		name    string
+		result  cloud.Configuration
+		wantErr bool
+	}{
+		{"AzureCloud", cloud.AzurePublic, false},
+		{"AzurePublicCloud", cloud.AzurePublic, false},
+		{"AzureChinaCloud", cloud.AzureChina, false},
+		{"AZUREUSGOVERNMENTCLOUD", cloud.AzureGovernment, false},
+		{"AzureGermanCloud", cloud.Configuration{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cfg, err := CloudFromEnvironmentName(tt.name)
+			g.Expect(err != nil).To(Equal(tt.wantErr))
+			g.Expect(cfg).To(Equal(tt.result))
+		})
+	}
+}
+
 func TestLogin(t *testing.T) {
 	tests := []struct {
 		name       string
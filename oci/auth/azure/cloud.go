@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+)
+
+// EnvironmentEnvVar is the environment variable selecting the Azure cloud
+// of the registries which are not identified by their DNS suffix, e.g.
+// "AzureChinaCloud". It uses the environment names of the Azure CLI and
+// the Azure SDKs, unknown names being ignored.
+const EnvironmentEnvVar = "AZURE_ENVIRONMENT"
+
+// registrySuffixes maps the DNS suffixes of ACR to their Azure cloud.
+// List from https://github.com/Azure/azure-sdk-for-go/blob/main/sdk/containers/azcontainerregistry/cloud_config.go#L16
+var registrySuffixes = []struct {
+	suffix string
+	cloud  cloud.Configuration
+}{
+	{".azurecr.io", cloud.AzurePublic},
+	{".azurecr.cn", cloud.AzureChina},
+	// Azure Germany has been retired, its registries are served by the
+	// public cloud.
+	{".azurecr.de", cloud.AzurePublic},
+	{".azurecr.us", cloud.AzureGovernment},
+}
+
+// CloudFromEnvironmentName returns the cloud configuration of the given Azure
+// environment name, e.g. "AzureUSGovernment" or "AzureUSGovernmentCloud".
+// The name is case-insensitive.
+func CloudFromEnvironmentName(name string) (cloud.Configuration, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "azurecloud", "azurepubliccloud", "azurepublic":
+		return cloud.AzurePublic, nil
+	case "azurechinacloud", "azurechina":
+		return cloud.AzureChina, nil
+	case "azureusgovernmentcloud", "azureusgovernment", "azuregovernment":
+		return cloud.AzureGovernment, nil
+	}
+	return cloud.Configuration{}, fmt.Errorf("unknown Azure environment '%s'", name)
+}
+
+// cloudFromEnvironment returns the cloud configuration selected with the
+// EnvironmentEnvVar environment variable, if any.
+func cloudFromEnvironment() (cloud.Configuration, bool) {
+	name := os.Getenv(EnvironmentEnvVar)
+	if name == "" {
+		return cloud.Configuration{}, false
+	}
+	cfg, err := CloudFromEnvironmentName(name)
+	if err != nil {
+		return cloud.Configuration{}, false
+	}
+	return cfg, true
+}
+
+// trimRegistryHost returns the host of the given registry URL or address.
+func trimRegistryHost(registry string) string {
+	if _, host, ok := strings.Cut(registry, "://"); ok {
+		registry = host
+	}
+	host, _, _ := strings.Cut(registry, "/")
+	return host
+}
@@ -28,9 +28,13 @@ type FakeTokenCredential struct {
 	Token     string
 	ExpiresOn time.Time
 	Err       error
+
+	// Scopes are the scopes of the last token request.
+	Scopes []string
 }
 
 func (tc *FakeTokenCredential) GetToken(ctx context.Context, options policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	tc.Scopes = options.Scopes
 	if tc.Err != nil {
 		return azcore.AccessToken{}, tc.Err
 	}
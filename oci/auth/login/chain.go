@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package login
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"github.com/fluxcd/pkg/oci"
+)
+
+// CredentialSource is the source of the credentials returned by
+// Manager.LoginWithFallback.
+type CredentialSource string
+
+const (
+	// CredentialSourceProvider means the credentials were obtained by
+	// logging in to the cloud provider of the registry.
+	CredentialSourceProvider CredentialSource = "Provider"
+	// CredentialSourcePullSecret means the credentials were found in an
+	// imagePullSecret.
+	CredentialSourcePullSecret CredentialSource = "ImagePullSecret"
+	// CredentialSourceDockerConfig means the credentials were found in a
+	// mounted Docker config file.
+	CredentialSourceDockerConfig CredentialSource = "DockerConfig"
+	// CredentialSourceAnonymous means no credentials were found, and the
+	// registry is accessed anonymously.
+	CredentialSourceAnonymous CredentialSource = "Anonymous"
+)
+
+// FallbackOptions contains the options of Manager.LoginWithFallback.
+type FallbackOptions struct {
+	// ProviderOptions configures the login to the cloud provider of the
+	// registry, which is attempted first.
+	ProviderOptions
+
+	// PullSecrets are the Docker config data of the imagePullSecrets, i.e.
+	// the value of their .dockerconfigjson key, consulted in order if the
+	// cloud provider login is not configured.
+	PullSecrets [][]byte
+
+	// DockerConfigPath is the path of a mounted Docker config file,
+	// consulted if none of the PullSecrets holds credentials for the
+	// registry. A missing file is skipped.
+	DockerConfigPath string
+}
+
+// Credentials are the credentials returned by Manager.LoginWithFallback.
+type Credentials struct {
+	// Authenticator is the Authenticator of the registry, authn.Anonymous
+	// if no credentials were found.
+	Authenticator authn.Authenticator
+	// ExpiresAt is the expiry of the credentials obtained from the cloud
+	// provider, or zero if they do not expire.
+	ExpiresAt time.Time
+	// Source is the source of the credentials.
+	Source CredentialSource
+	// Reason describes why the source was selected, e.g. which
+	// imagePullSecret holds the credentials of the registry.
+	Reason string
+}
+
+// LoginWithFallback returns the best available credentials for the registry
+// of the given image, consulting the following sources in order:
+//
+//  1. the cloud provider of the registry, if its auto login is enabled
+//  2. the PullSecrets
+//  3. the Docker config file at DockerConfigPath
+//  4. anonymous access
+//
+// The first source holding credentials for the registry is selected. A
+// failed cloud provider login or an invalid Docker config is returned as an
+// error rather than falling back to the next source, so that the registry
+// is not accessed with unexpected credentials.
+func (m *Manager) LoginWithFallback(ctx context.Context, url string, ref name.Reference, opts FallbackOptions) (*Credentials, error) {
+	provider := m.registryProvider(url, ref, opts.ProviderOptions)
+	if provider != oci.ProviderGeneric {
+		auth, expiresAt, err := m.LoginWithExpiry(ctx, url, ref, opts.ProviderOptions)
+		switch {
+		case err == nil && auth != nil:
+			return &Credentials{
+				Authenticator: auth,
+				ExpiresAt:     expiresAt,
+				Source:        CredentialSourceProvider,
+				Reason:        fmt.Sprintf("logged in with the %s provider", providerName(provider)),
+			}, nil
+		case err != nil && !errors.Is(err, oci.ErrUnconfiguredProvider):
+			return nil, err
+		}
+	}
+
+	registry := normalizeRegistry(registryHost(url, ref))
+	for i, data := range opts.PullSecrets {
+		auth, ok, err := dockerConfigAuthenticator(data, registry)
+		if err != nil {
+			return nil, fmt.Errorf("invalid imagePullSecret at index %d: %w", i, err)
+		}
+		if ok {
+			return &Credentials{
+				Authenticator: auth,
+				Source:        CredentialSourcePullSecret,
+				Reason:        fmt.Sprintf("found credentials for '%s' in the imagePullSecret at index %d", registry, i),
+			}, nil
+		}
+	}
+
+	if opts.DockerConfigPath != "" {
+		data, err := os.ReadFile(opts.DockerConfigPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read Docker config: %w", err)
+		}
+		if err == nil {
+			auth, ok, err := dockerConfigAuthenticator(data, registry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid Docker config '%s': %w", opts.DockerConfigPath, err)
+			}
+			if ok {
+				return &Credentials{
+					Authenticator: auth,
+					Source:        CredentialSourceDockerConfig,
+					Reason:        fmt.Sprintf("found credentials for '%s' in '%s'", registry, opts.DockerConfigPath),
+				}, nil
+			}
+		}
+	}
+
+	return &Credentials{
+		Authenticator: authn.Anonymous,
+		Source:        CredentialSourceAnonymous,
+		Reason:        fmt.Sprintf("no credentials found for '%s'", registry),
+	}, nil
+}
+
+// providerName returns the name of the cloud provider.
+func providerName(provider oci.Provider) string {
+	switch provider {
+	case oci.ProviderAWS:
+		return "AWS"
+	case oci.ProviderGCP:
+		return "GCP"
+	case oci.ProviderAzure:
+		return "Azure"
+	case oci.ProviderDev:
+		return "dev"
+	}
+	return "generic"
+}
+
+// normalizeRegistry returns the canonical host of the registry, e.g.
+// index.docker.io for docker.io.
+func normalizeRegistry(registry string) string {
+	reg, err := name.NewRegistry(registry)
+	if err != nil {
+		return registry
+	}
+	return reg.RegistryStr()
+}
+
+// dockerConfigAuthenticator returns the Authenticator of the registry in
+// the given Docker config data, and whether the config holds credentials
+// for the registry.
+func dockerConfigAuthenticator(data []byte, registry string) (authn.Authenticator, bool, error) {
+	var cfg dockerConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, false, err
+	}
+	// The keys are sorted for a deterministic result when several keys
+	// match the registry, e.g. docker.io and https://index.docker.io/v1/.
+	for _, key := range slices.Sorted(maps.Keys(cfg.Auths)) {
+		if dockerConfigRegistry(key) != registry {
+			continue
+		}
+		entry := cfg.Auths[key]
+		ac := authn.AuthConfig{
+			Username:      entry.Username,
+			Password:      entry.Password,
+			IdentityToken: entry.IdentityToken,
+			RegistryToken: entry.RegistryToken,
+		}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid auth of '%s': %w", key, err)
+			}
+			username, password, ok := strings.Cut(string(decoded), ":")
+			if !ok {
+				return nil, false, fmt.Errorf("invalid auth of '%s': expected username:password", key)
+			}
+			ac.Username, ac.Password = username, password
+		}
+		return authn.FromConfig(ac), true, nil
+	}
+	return nil, false, nil
+}
+
+// dockerConfigRegistry returns the registry host of a key of the auths of a
+// Docker config, e.g. "ghcr.io" for "https://ghcr.io/v1/", and the default
+// registry for the Docker Hub keys.
+func dockerConfigRegistry(key string) string {
+	if key == dockerHubConfigKey {
+		return name.DefaultRegistry
+	}
+	if _, host, ok := strings.Cut(key, "://"); ok {
+		key = host
+	}
+	host, _, _ := strings.Cut(key, "/")
+	return normalizeRegistry(host)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package login
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/gomega"
+
+	"github.com/fluxcd/pkg/oci/auth/gcp"
+)
+
+func TestManager_LoginWithFallback(t *testing.T) {
+	dir := t.TempDir()
+	dockerConfigPath := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(dockerConfigPath, []byte(`{"auths": {
+		"https://index.docker.io/v1/": {"auth": "aHViLXVzZXI6aHViLXBhc3M="},
+		"gcr.io": {"username": "_json_key", "password": "key"},
+		"registry.example.com": {"identitytoken": "refresh-token"}
+	}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	pullSecrets := [][]byte{
+		[]byte(`{"auths": {"https://ghcr.io/v1/": {"auth": "Z2gtdXNlcjpnaC1wYXNz"}}}`),
+		[]byte(`{"auths": {"ghcr.io": {"username": "other", "password": "other"}, "docker.io": {"username": "secret-user", "password": "secret-pass"}}}`),
+	}
+
+	tests := []struct {
+		name       string
+		image      string
+		opts       FallbackOptions
+		wantSource CredentialSource
+		wantReason string
+		wantAuth   authn.AuthConfig
+		wantErr    string
+	}{
+		{
+			name:       "cloud provider",
+			image:      "gcr.io/foo/bar:v1",
+			opts:       FallbackOptions{ProviderOptions: ProviderOptions{GcpAutoLogin: true}, DockerConfigPath: dockerConfigPath},
+			wantSource: CredentialSourceProvider,
+			wantReason: "logged in with the GCP provider",
+			wantAuth:   authn.AuthConfig{Username: "oauth2accesstoken", Password: "some-token"},
+		},
+		{
+			name:       "unconfigured cloud provider",
+			image:      "gcr.io/foo/bar:v1",
+			opts:       FallbackOptions{DockerConfigPath: dockerConfigPath},
+			wantSource: CredentialSourceDockerConfig,
+			wantReason: "found credentials for 'gcr.io' in '" + dockerConfigPath + "'",
+			wantAuth:   authn.AuthConfig{Username: "_json_key", Password: "key"},
+		},
+		{
+			name:       "first matching imagePullSecret",
+			image:      "ghcr.io/org/app:v1",
+			opts:       FallbackOptions{PullSecrets: pullSecrets, DockerConfigPath: dockerConfigPath},
+			wantSource: CredentialSourcePullSecret,
+			wantReason: "found credentials for 'ghcr.io' in the imagePullSecret at index 0",
+			wantAuth:   authn.AuthConfig{Username: "gh-user", Password: "gh-pass"},
+		},
+		{
+			name:       "imagePullSecret before Docker config",
+			image:      "org/app:v1",
+			opts:       FallbackOptions{PullSecrets: pullSecrets, DockerConfigPath: dockerConfigPath},
+			wantSource: CredentialSourcePullSecret,
+			wantReason: "found credentials for 'index.docker.io' in the imagePullSecret at index 1",
+			wantAuth:   authn.AuthConfig{Username: "secret-user", Password: "secret-pass"},
+		},
+		{
+			name:       "Docker config",
+			image:      "org/app:v1",
+			opts:       FallbackOptions{DockerConfigPath: dockerConfigPath},
+			wantSource: CredentialSourceDockerConfig,
+			wantAuth:   authn.AuthConfig{Username: "hub-user", Password: "hub-pass"},
+		},
+		{
+			name:       "Docker config identity token",
+			image:      "registry.example.com",
+			opts:       FallbackOptions{DockerConfigPath: dockerConfigPath},
+			wantSource: CredentialSourceDockerConfig,
+			wantAuth:   authn.AuthConfig{IdentityToken: "refresh-token"},
+		},
+		{
+			name:       "anonymous",
+			image:      "quay.io/org/app:v1",
+			opts:       FallbackOptions{PullSecrets: pullSecrets, DockerConfigPath: filepath.Join(dir, "missing.json")},
+			wantSource: CredentialSourceAnonymous,
+			wantReason: "no credentials found for 'quay.io'",
+			wantAuth:   authn.AuthConfig{},
+		},
+		{
+			name:    "invalid imagePullSecret",
+			image:   "ghcr.io/org/app:v1",
+			opts:    FallbackOptions{PullSecrets: [][]byte{[]byte(`{"auths": {"ghcr.io": {"auth": "invalid"}}}`)}},
+			wantErr: "invalid imagePullSecret at index 0: invalid auth of 'ghcr.io'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"access_token": "some-token","expires_in": 10, "token_type": "foo"}`))
+			}))
+			t.Cleanup(srv.Close)
+			mgr := NewManager().WithGCRClient(gcp.NewClient().WithTokenURL(srv.URL))
+
+			ref, err := name.ParseReference(tt.image)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			creds, err := mgr.LoginWithFallback(context.TODO(), tt.image, ref, tt.opts)
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(creds.Source).To(Equal(tt.wantSource))
+			if tt.wantReason != "" {
+				g.Expect(creds.Reason).To(Equal(tt.wantReason))
+			}
+			ac, err := creds.Authenticator.Authorization()
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(*ac).To(Equal(tt.wantAuth))
+		})
+	}
+}
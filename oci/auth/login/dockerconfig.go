@@ -0,0 +1,148 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package login
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+)
+
+const (
+	// dockerConfigFileName is the name of the Docker config file in the
+	// directory pointed to by the DOCKER_CONFIG environment variable.
+	dockerConfigFileName = "config.json"
+	// dockerHubConfigKey is the key of the Docker Hub credentials in a
+	// Docker config file.
+	dockerHubConfigKey = "https://index.docker.io/v1/"
+)
+
+// Keychain is an authn.Keychain holding the Authenticators returned by
+// Manager.Login, indexed by registry host. It can be handed to the libraries
+// accepting an authn.Keychain, or rendered in the Docker config.json format
+// for the libraries and tools requiring that shape, e.g. ORAS or Helm OCI.
+type Keychain map[string]authn.Authenticator
+
+// Resolve returns the Authenticator of the registry of the given resource,
+// or authn.Anonymous if the registry has no credentials.
+func (k Keychain) Resolve(target authn.Resource) (authn.Authenticator, error) {
+	if auth, ok := k[target.RegistryStr()]; ok && auth != nil {
+		return auth, nil
+	}
+	return authn.Anonymous, nil
+}
+
+// dockerConfig is the subset of the Docker config file format holding the
+// registry credentials.
+type dockerConfig struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+type dockerConfigEntry struct {
+	Username      string `json:"username,omitempty"`
+	Password      string `json:"password,omitempty"`
+	Auth          string `json:"auth,omitempty"`
+	IdentityToken string `json:"identitytoken,omitempty"`
+	RegistryToken string `json:"registrytoken,omitempty"`
+}
+
+// DockerConfigJSON renders the credentials of the Keychain in the Docker
+// config.json format.
+func (k Keychain) DockerConfigJSON() ([]byte, error) {
+	cfg := dockerConfig{
+		Auths: make(map[string]dockerConfigEntry, len(k)),
+	}
+	for registry, auth := range k {
+		if auth == nil || auth == authn.Anonymous {
+			continue
+		}
+		ac, err := auth.Authorization()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get authorization for '%s': %w", registry, err)
+		}
+		entry := dockerConfigEntry{
+			Auth:          ac.Auth,
+			IdentityToken: ac.IdentityToken,
+			RegistryToken: ac.RegistryToken,
+		}
+		if ac.Username != "" || ac.Password != "" {
+			entry.Auth = base64.StdEncoding.EncodeToString([]byte(ac.Username + ":" + ac.Password))
+		}
+		if registry == name.DefaultRegistry {
+			registry = dockerHubConfigKey
+		}
+		cfg.Auths[registry] = entry
+	}
+	return json.Marshal(cfg)
+}
+
+// DockerConfig is a Docker config file written to a private temporary
+// directory. The directory can be set as the DOCKER_CONFIG environment
+// variable of the tools reading the credentials from a Docker config file.
+// It must be removed with Cleanup when no longer used.
+type DockerConfig struct {
+	// Dir is the path to the directory holding the config file.
+	Dir string
+	// Path is the path to the config file.
+	Path string
+}
+
+// Cleanup removes the directory of the config file.
+func (c *DockerConfig) Cleanup() error {
+	return os.RemoveAll(c.Dir)
+}
+
+// WriteDockerConfig writes the credentials of the Keychain to a Docker
+// config.json file in a new temporary directory created in tmpDir, or in the
+// default directory for temporary files if tmpDir is empty. The directory
+// and the file are only accessible by the current user.
+func (k Keychain) WriteDockerConfig(tmpDir string) (*DockerConfig, error) {
+	data, err := k.DockerConfigJSON()
+	if err != nil {
+		return nil, err
+	}
+	dir, err := os.MkdirTemp(tmpDir, "docker-config-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker config directory: %w", err)
+	}
+	cfg := &DockerConfig{
+		Dir:  dir,
+		Path: filepath.Join(dir, dockerConfigFileName),
+	}
+	if err := os.WriteFile(cfg.Path, data, 0o600); err != nil {
+		_ = cfg.Cleanup()
+		return nil, fmt.Errorf("failed to write Docker config: %w", err)
+	}
+	return cfg, nil
+}
+
+// WithDockerConfig writes the credentials of the Keychain to a Docker config
+// file with WriteDockerConfig, and calls fn with the DockerConfig. The
+// config file is removed when fn returns.
+func (k Keychain) WithDockerConfig(tmpDir string, fn func(cfg *DockerConfig) error) error {
+	cfg, err := k.WriteDockerConfig(tmpDir)
+	if err != nil {
+		return err
+	}
+	defer cfg.Cleanup()
+	return fn(cfg)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package login
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	. "github.com/onsi/gomega"
+)
+
+func TestKeychain_Resolve(t *testing.T) {
+	g := NewWithT(t)
+
+	auth := &authn.Basic{Username: "user", Password: "pass"}
+	keychain := Keychain{"012345678901.dkr.ecr.us-east-1.amazonaws.com": auth}
+
+	ref, err := name.ParseReference("012345678901.dkr.ecr.us-east-1.amazonaws.com/foo:v1")
+	g.Expect(err).ToNot(HaveOccurred())
+	got, err := keychain.Resolve(ref.Context())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(auth))
+
+	ref, err = name.ParseReference("ghcr.io/foo:v1")
+	g.Expect(err).ToNot(HaveOccurred())
+	got, err = keychain.Resolve(ref.Context())
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(authn.Anonymous))
+}
+
+func TestKeychain_DockerConfigJSON(t *testing.T) {
+	g := NewWithT(t)
+
+	keychain := Keychain{
+		"012345678901.dkr.ecr.us-east-1.amazonaws.com": &authn.Basic{Username: "AWS", Password: "token"},
+		"foo.azurecr.io":      authn.FromConfig(authn.AuthConfig{IdentityToken: "refresh-token"}),
+		name.DefaultRegistry:  &authn.Basic{Username: "user", Password: "pass"},
+		"public.example.com":  authn.Anonymous,
+		"preencoded.example":  authn.FromConfig(authn.AuthConfig{Auth: "dXNlcjpwYXNz"}),
+		"registrytoken.local": &authn.Bearer{Token: "bearer"},
+	}
+
+	data, err := keychain.DockerConfigJSON()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(MatchJSON(`{
+		"auths": {
+			"012345678901.dkr.ecr.us-east-1.amazonaws.com": {"auth": "QVdTOnRva2Vu"},
+			"foo.azurecr.io": {"identitytoken": "refresh-token"},
+			"https://index.docker.io/v1/": {"auth": "dXNlcjpwYXNz"},
+			"preencoded.example": {"auth": "dXNlcjpwYXNz"},
+			"registrytoken.local": {"registrytoken": "bearer"}
+		}
+	}`))
+}
+
+func TestKeychain_WriteDockerConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	keychain := Keychain{"ghcr.io": &authn.Basic{Username: "user", Password: "pass"}}
+
+	cfg, err := keychain.WriteDockerConfig(t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	info, err := os.Stat(cfg.Dir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o700)))
+	info, err = os.Stat(cfg.Path)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+	// The config file can be read by the libraries using the Docker config.
+	t.Setenv("DOCKER_CONFIG", cfg.Dir)
+	ref, err := name.ParseReference("ghcr.io/foo:v1")
+	g.Expect(err).ToNot(HaveOccurred())
+	auth, err := authn.DefaultKeychain.Resolve(ref.Context())
+	g.Expect(err).ToNot(HaveOccurred())
+	ac, err := auth.Authorization()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ac.Username).To(Equal("user"))
+	g.Expect(ac.Password).To(Equal("pass"))
+
+	g.Expect(cfg.Cleanup()).To(Succeed())
+	g.Expect(cfg.Dir).ToNot(BeADirectory())
+}
+
+func TestKeychain_WithDockerConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	keychain := Keychain{"ghcr.io": &authn.Basic{Username: "user", Password: "pass"}}
+
+	var dir string
+	fnErr := errors.New("failed")
+	err := keychain.WithDockerConfig(t.TempDir(), func(cfg *DockerConfig) error {
+		dir = cfg.Dir
+		g.Expect(cfg.Path).To(BeARegularFile())
+		return fnErr
+	})
+	g.Expect(err).To(MatchError(fnErr))
+	g.Expect(dir).ToNot(BeEmpty())
+	g.Expect(dir).ToNot(BeADirectory())
+}
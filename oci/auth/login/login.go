@@ -27,6 +27,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
 
+	"github.com/fluxcd/pkg/auth/dev"
 	"github.com/fluxcd/pkg/oci"
 	"github.com/fluxcd/pkg/oci/auth/aws"
 	"github.com/fluxcd/pkg/oci/auth/azure"
@@ -36,15 +37,7 @@ import (
 // ImageRegistryProvider analyzes the provided registry and returns the identified
 // container image registry provider.
 func ImageRegistryProvider(url string, ref name.Reference) oci.Provider {
-	// If the url is a repository root address, use it to analyze. Else, derive
-	// the registry from the name reference.
-	// NOTE: This is because name.Reference of a repository root assumes that
-	// the reference is an image name and defaults to using index.docker.io as
-	// the registry host.
-	addr := strings.TrimSuffix(url, "/")
-	if strings.ContainsRune(addr, '/') {
-		addr = ref.Context().RegistryStr()
-	}
+	addr := registryHost(url, ref)
 
 	_, _, ok := aws.ParseRegistry(addr)
 	if ok {
@@ -59,17 +52,74 @@ func ImageRegistryProvider(url string, ref name.Reference) oci.Provider {
 	return oci.ProviderGeneric
 }
 
+// registryProvider returns the container image registry provider of the
+// provided registry, identifying the registries with a custom DNS suffix
+// configured on the ACR client. The dev provider is returned for any
+// registry if its auto login is enabled. The ECR Public gallery is accessed
+// anonymously as a generic registry, unless its auto login is enabled.
+func (m *Manager) registryProvider(url string, ref name.Reference, opts ProviderOptions) oci.Provider {
+	if opts.DevAutoLogin {
+		return oci.ProviderDev
+	}
+	provider := ImageRegistryProvider(url, ref)
+	if provider == oci.ProviderGeneric {
+		addr := registryHost(url, ref)
+		if opts.AwsPublicAutoLogin && aws.IsPublicRegistry(addr) {
+			return oci.ProviderAWS
+		}
+		if m.acr.ValidHost(addr) {
+			return oci.ProviderAzure
+		}
+	}
+	return provider
+}
+
+// awsAutoLogin returns whether the automatic login is enabled for the given
+// ECR registry, which is either a private registry or the ECR Public gallery.
+func awsAutoLogin(registry string, opts ProviderOptions) bool {
+	if aws.IsPublicRegistry(registry) {
+		return opts.AwsPublicAutoLogin
+	}
+	return opts.AwsAutoLogin
+}
+
+// registryHost returns the registry host of the given image. If the url is
+// a repository root address, it is the registry host. Otherwise, the
+// registry is derived from the name reference.
+// NOTE: This is because name.Reference of a repository root assumes that
+// the reference is an image name and defaults to using index.docker.io as
+// the registry host.
+func registryHost(url string, ref name.Reference) string {
+	addr := strings.TrimSuffix(url, "/")
+	if strings.ContainsRune(addr, '/') && ref != nil {
+		addr = ref.Context().RegistryStr()
+	}
+	return addr
+}
+
 // ProviderOptions contains options for registry provider login.
 type ProviderOptions struct {
 	// AwsAutoLogin enables automatic attempt to get credentials for images in
 	// ECR.
 	AwsAutoLogin bool
+	// AwsPublicAutoLogin enables automatic attempt to get credentials for
+	// images in the ECR Public gallery, which are pulled anonymously
+	// otherwise.
+	AwsPublicAutoLogin bool
 	// GcpAutoLogin enables automatic attempt to get credentials for images in
 	// GCP.
 	GcpAutoLogin bool
 	// AzureAutoLogin enables automatic attempt to get credentials for images in
 	// ACR.
 	AzureAutoLogin bool
+	// DevAutoLogin enables getting fake credentials from the dev
+	// authentication provider for images in any registry, e.g. for the
+	// registries of local development and e2e tests. The provider must be
+	// enabled with dev.WithEnabled in DevOpts as well.
+	DevAutoLogin bool
+	// DevOpts are the options of the dev authentication provider. The
+	// subject of the tokens defaults to the registry host.
+	DevOpts []dev.OptFunc
 }
 
 // Manager is a login manager for various registry providers.
@@ -147,18 +197,38 @@ func (m *Manager) Login(ctx context.Context, url string, ref name.Reference, opt
 // Authenticator along with the auth expiry time.
 // For generic registry provider, it is no-op.
 func (m *Manager) LoginWithExpiry(ctx context.Context, url string, ref name.Reference, opts ProviderOptions) (authn.Authenticator, time.Time, error) {
-	provider := ImageRegistryProvider(url, ref)
+	provider := m.registryProvider(url, ref, opts)
 	switch provider {
 	case oci.ProviderAWS:
-		return m.ecr.LoginWithExpiry(ctx, opts.AwsAutoLogin, url)
+		return m.ecr.LoginWithExpiry(ctx, awsAutoLogin(registryHost(url, ref), opts), url)
 	case oci.ProviderGCP:
 		return m.gcr.LoginWithExpiry(ctx, opts.GcpAutoLogin, url, ref)
 	case oci.ProviderAzure:
 		return m.acr.LoginWithExpiry(ctx, opts.AzureAutoLogin, url, ref)
+	case oci.ProviderDev:
+		return devLogin(ctx, registryHost(url, ref), opts.DevOpts)
 	}
 	return nil, time.Time{}, nil
 }
 
+// devLogin returns the fake credentials minted by the dev authentication
+// provider for the registry.
+func devLogin(ctx context.Context, registry string, opts []dev.OptFunc) (authn.Authenticator, time.Time, error) {
+	client, err := dev.New(append([]dev.OptFunc{dev.WithSubject(registry)}, opts...)...)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("dev authentication failed: %w", err)
+	}
+	logr.FromContextOrDiscard(ctx).Info("logging in with the dev provider for " + registry)
+	token, err := client.GetToken(ctx)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("dev authentication failed: %w", err)
+	}
+	return authn.FromConfig(authn.AuthConfig{
+		Username: token.Username,
+		Password: token.Token,
+	}), token.ExpiresAt, nil
+}
+
 // OIDCLogin attempts to get an Authenticator for the provided URL endpoint.
 //
 // If you want to construct an Authenticator based on an image reference,
@@ -170,10 +240,10 @@ func (m *Manager) OIDCLogin(ctx context.Context, registryURL string, opts Provid
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse registry url: %w", err)
 	}
-	provider := ImageRegistryProvider(u.Host, nil)
+	provider := m.registryProvider(u.Host, nil, opts)
 	switch provider {
 	case oci.ProviderAWS:
-		if !opts.AwsAutoLogin {
+		if !awsAutoLogin(u.Host, opts) {
 			return nil, fmt.Errorf("ECR authentication failed: %w", oci.ErrUnconfiguredProvider)
 		}
 		logr.FromContextOrDiscard(ctx).Info("logging in to AWS ECR for " + u.Host)
@@ -190,6 +260,9 @@ func (m *Manager) OIDCLogin(ctx context.Context, registryURL string, opts Provid
 		}
 		logr.FromContextOrDiscard(ctx).Info("logging in to Azure ACR for " + u.Host)
 		return m.acr.OIDCLogin(ctx, fmt.Sprintf("%s://%s", u.Scheme, u.Host))
+	case oci.ProviderDev:
+		auth, _, err := devLogin(ctx, u.Host, opts.DevOpts)
+		return auth, err
 	}
 	return nil, nil
 }
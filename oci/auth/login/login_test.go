@@ -18,16 +18,19 @@ package login
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/google/go-containerregistry/pkg/name"
 	. "github.com/onsi/gomega"
 
+	"github.com/fluxcd/pkg/auth/dev"
 	"github.com/fluxcd/pkg/oci"
 	"github.com/fluxcd/pkg/oci/auth/aws"
 	"github.com/fluxcd/pkg/oci/auth/azure"
@@ -43,6 +46,7 @@ func TestImageRegistryProvider(t *testing.T) {
 		{"ecr", "012345678901.dkr.ecr.us-east-1.amazonaws.com/foo:v1", oci.ProviderAWS},
 		{"ecr-root", "012345678901.dkr.ecr.us-east-1.amazonaws.com", oci.ProviderAWS},
 		{"ecr-root with slash", "012345678901.dkr.ecr.us-east-1.amazonaws.com/", oci.ProviderAWS},
+		{"ecr-public", "public.ecr.aws/org/app:v1", oci.ProviderGeneric},
 		{"gcr", "gcr.io/foo/bar:v1", oci.ProviderGCP},
 		{"gcr-root", "gcr.io", oci.ProviderGCP},
 		{"acr", "foo.azurecr.io/bar:v1", oci.ProviderAzure},
@@ -65,6 +69,105 @@ func TestImageRegistryProvider(t *testing.T) {
 	}
 }
 
+func TestManager_registryProvider(t *testing.T) {
+	g := NewWithT(t)
+
+	mgr := NewManager().WithACRClient(azure.NewClient(azure.WithRegistrySuffixes(".azurecr.example.com")))
+
+	ref, err := name.ParseReference("foo.azurecr.example.com/bar:v1")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(ImageRegistryProvider("foo.azurecr.example.com/bar:v1", ref)).To(Equal(oci.ProviderGeneric))
+	g.Expect(mgr.registryProvider("foo.azurecr.example.com/bar:v1", ref, ProviderOptions{})).To(Equal(oci.ProviderAzure))
+	g.Expect(mgr.registryProvider("foo.azurecr.cn", nil, ProviderOptions{})).To(Equal(oci.ProviderAzure))
+	g.Expect(mgr.registryProvider("ghcr.io/foo/bar:v1", nil, ProviderOptions{})).To(Equal(oci.ProviderGeneric))
+	g.Expect(mgr.registryProvider("foo.azurecr.cn", nil, ProviderOptions{DevAutoLogin: true})).To(Equal(oci.ProviderDev))
+	g.Expect(mgr.registryProvider("public.ecr.aws/org/app:v1", nil, ProviderOptions{AwsAutoLogin: true})).To(Equal(oci.ProviderGeneric))
+	g.Expect(mgr.registryProvider("public.ecr.aws/org/app:v1", nil, ProviderOptions{AwsPublicAutoLogin: true})).To(Equal(oci.ProviderAWS))
+}
+
+func TestLogin_dev(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	image := "kind-registry:5000/foo/bar:v1"
+	ref, err := name.ParseReference(image)
+	g.Expect(err).ToNot(HaveOccurred())
+	mgr := NewManager()
+
+	_, _, err = mgr.LoginWithExpiry(ctx, image, ref, ProviderOptions{DevAutoLogin: true})
+	g.Expect(err).To(MatchError(dev.ErrNotEnabled))
+
+	auth, expiresAt, err := mgr.LoginWithExpiry(ctx, image, ref, ProviderOptions{
+		DevAutoLogin: true,
+		DevOpts:      []dev.OptFunc{dev.WithEnabled(true)},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(expiresAt).To(BeTemporally(">", time.Now()))
+	authConfig, err := auth.Authorization()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(authConfig.Username).To(Equal(dev.DefaultUsername))
+	g.Expect(authConfig.Password).To(Equal(dev.TokenFor(dev.DefaultUsername, "kind-registry:5000")))
+
+	creds, err := mgr.LoginWithFallback(ctx, image, ref, FallbackOptions{
+		ProviderOptions: ProviderOptions{
+			DevAutoLogin: true,
+			DevOpts:      []dev.OptFunc{dev.WithEnabled(true), dev.WithUsername("e2e")},
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(creds.Source).To(Equal(CredentialSourceProvider))
+	g.Expect(creds.Reason).To(Equal("logged in with the dev provider"))
+	authConfig, err = creds.Authenticator.Authorization()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(authConfig.Username).To(Equal("e2e"))
+
+	// The dev provider is not used without auto login.
+	auth, _, err = mgr.LoginWithExpiry(ctx, image, ref, ProviderOptions{
+		DevOpts: []dev.OptFunc{dev.WithEnabled(true)},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(auth).To(BeNil())
+}
+
+func TestLogin_ecrPublic(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"authorizationData": {"authorizationToken": "c29tZS1rZXk6c29tZS1zZWNyZXQ="}}`)
+	}))
+	t.Cleanup(srv.Close)
+
+	ecrClient := aws.NewClient()
+	cfg := awssdk.NewConfig()
+	cfg.EndpointResolverWithOptions = awssdk.EndpointResolverWithOptionsFunc(
+		func(service, region string, options ...interface{}) (awssdk.Endpoint, error) {
+			return awssdk.Endpoint{URL: srv.URL}, nil
+		})
+	cfg.Credentials = credentials.NewStaticCredentialsProvider("x", "y", "z")
+	ecrClient.WithConfig(cfg)
+
+	mgr := NewManager()
+	mgr.WithECRClient(ecrClient)
+
+	image := "public.ecr.aws/org/app:v1"
+	ref, err := name.ParseReference(image)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// The gallery is accessed anonymously without its auto login.
+	auth, _, err := mgr.LoginWithExpiry(ctx, image, ref, ProviderOptions{AwsAutoLogin: true})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(auth).To(BeNil())
+	g.Expect(requests).To(BeZero())
+
+	auth, _, err = mgr.LoginWithExpiry(ctx, image, ref, ProviderOptions{AwsPublicAutoLogin: true})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(auth).ToNot(BeNil())
+	g.Expect(requests).To(Equal(1))
+}
+
 func TestLogin(t *testing.T) {
 	tests := []struct {
 		name         string
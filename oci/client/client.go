@@ -28,6 +28,13 @@ import (
 // Client holds the options for accessing remote OCI registries.
 type Client struct {
 	options []crane.Option
+
+	// insecureRegistries holds the registries configured with
+	// ConfigureTransport, and whether they allow plain HTTP.
+	insecureRegistries map[string]bool
+	// skipTLSVerifyRegistries holds the registries configured with
+	// ConfigureTransport, and whether they skip the TLS verification.
+	skipTLSVerifyRegistries map[string]bool
 }
 
 // NewClient returns an OCI client configured with the given crane options.
@@ -31,5 +31,5 @@ func (c *Client) Delete(ctx context.Context, url string) error {
 		return fmt.Errorf("invalid URL: %w", err)
 	}
 
-	return crane.Delete(url, c.optionsWithContext(ctx)...)
+	return c.insecureError(url, crane.Delete(url, c.optionsForURL(ctx, url)...))
 }
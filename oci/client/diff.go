@@ -65,9 +65,9 @@ func (c *Client) Diff(ctx context.Context, url, dir string, ignorePaths []string
 		return fmt.Errorf("calculating artifact hash failed: %w", err)
 	}
 
-	img, err := crane.Pull(url, c.optionsWithContext(ctx)...)
+	img, err := crane.Pull(url, c.optionsForURL(ctx, url)...)
 	if err != nil {
-		return err
+		return c.insecureError(url, err)
 	}
 
 	layers, err := img.Layers()
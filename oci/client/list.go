@@ -45,9 +45,9 @@ type ListOptions struct {
 // List fetches the tags and their manifests for a given OCI repository.
 func (c *Client) List(ctx context.Context, url string, opts ListOptions) ([]Metadata, error) {
 	metas := make([]Metadata, 0)
-	tags, err := crane.ListTags(url, c.options...)
+	tags, err := crane.ListTags(url, c.optionsForURL(ctx, url)...)
 	if err != nil {
-		return nil, fmt.Errorf("listing tags failed: %w", err)
+		return nil, c.insecureError(url, fmt.Errorf("listing tags failed: %w", err))
 	}
 
 	sort.Slice(tags, func(i, j int) bool { return tags[i] > tags[j] })
@@ -94,9 +94,9 @@ func (c *Client) List(ctx context.Context, url string, opts ListOptions) ([]Meta
 			URL: fmt.Sprintf("%s:%s", url, tag),
 		}
 
-		manifestJSON, err := crane.Manifest(meta.URL, c.optionsWithContext(ctx)...)
+		manifestJSON, err := crane.Manifest(meta.URL, c.optionsForURL(ctx, url)...)
 		if err != nil {
-			return nil, fmt.Errorf("fetching manifest failed: %w", err)
+			return nil, c.insecureError(url, fmt.Errorf("fetching manifest failed: %w", err))
 		}
 
 		manifest, err := gcrv1.ParseManifest(bytes.NewReader(manifestJSON))
@@ -109,9 +109,9 @@ func (c *Client) List(ctx context.Context, url string, opts ListOptions) ([]Meta
 		meta.Source = manifestMetadata.Source
 		meta.Created = manifestMetadata.Created
 
-		digest, err := crane.Digest(meta.URL, c.optionsWithContext(ctx)...)
+		digest, err := crane.Digest(meta.URL, c.optionsForURL(ctx, url)...)
 		if err != nil {
-			return nil, fmt.Errorf("fetching digest failed: %w", err)
+			return nil, c.insecureError(url, fmt.Errorf("fetching digest failed: %w", err))
 		}
 		meta.Digest = digest
 
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+
+	"github.com/fluxcd/pkg/version"
+)
+
+// PruneOptions contains the retention rules for pruning the tags of an OCI
+// repository. A tag is kept if it matches any of the rules, and at least one
+// rule must be set.
+type PruneOptions struct {
+	// KeepLastSemver is the number of tags with the highest semver
+	// versions to keep.
+	KeepLastSemver int
+	// KeepRegex contains a regex matching the tags to keep.
+	KeepRegex string
+	// MinAge is the minimum age of the tags to prune, based on the created
+	// annotation of their manifest. The tags without a valid created
+	// annotation are kept when set.
+	MinAge time.Duration
+	// DryRun can be used to compute the stale tags without deleting them.
+	DryRun bool
+}
+
+// Prune computes the stale tags of the given OCI repository according to the
+// retention rules, and deletes them along with the cosign attestation,
+// signature and SBOM tags of their manifests. The stale tags pointing to the
+// same manifest as a kept tag are not deleted, as some registries delete the
+// manifest of a deleted tag. For the same reason, a stale tag which is not
+// found anymore is considered deleted. It returns the deleted tags, or the
+// tags that would be deleted in dry-run mode.
+func (c *Client) Prune(ctx context.Context, url string, opts PruneOptions) ([]Metadata, error) {
+	repo, err := name.NewRepository(url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if opts.KeepLastSemver <= 0 && opts.KeepRegex == "" && opts.MinAge <= 0 {
+		return nil, errors.New("no retention rule set: at least one of KeepLastSemver, KeepRegex or MinAge is required")
+	}
+
+	var keepRe *regexp.Regexp
+	if opts.KeepRegex != "" {
+		keepRe, err = regexp.Compile(opts.KeepRegex)
+		if err != nil {
+			return nil, fmt.Errorf("regex '%s' parse error: %w", opts.KeepRegex, err)
+		}
+	}
+
+	all, err := c.List(ctx, url, ListOptions{IncludeCosignArtifacts: true})
+	if err != nil {
+		return nil, err
+	}
+	var metas []Metadata
+	cosignTags := make(map[string]bool)
+	for _, meta := range all {
+		tag := strings.TrimPrefix(meta.URL, url+":")
+		if IsCosignArtifact(tag) {
+			cosignTags[tag] = true
+			continue
+		}
+		metas = append(metas, meta)
+	}
+
+	keep := make(map[string]bool, len(metas))
+	type semverTag struct {
+		tag string
+		v   *semver.Version
+	}
+	var semverTags []semverTag
+	now := time.Now()
+	for _, meta := range metas {
+		tag := strings.TrimPrefix(meta.URL, url+":")
+		if keepRe != nil && keepRe.MatchString(tag) {
+			keep[tag] = true
+		}
+		if opts.MinAge > 0 {
+			created, err := time.Parse(time.RFC3339, meta.Created)
+			if err != nil || now.Sub(created) < opts.MinAge {
+				keep[tag] = true
+			}
+		}
+		if v, err := version.ParseVersion(tag); err == nil {
+			semverTags = append(semverTags, semverTag{tag: tag, v: v})
+		}
+	}
+	sort.SliceStable(semverTags, func(i, j int) bool {
+		return semverTags[i].v.GreaterThan(semverTags[j].v)
+	})
+	for i := 0; i < opts.KeepLastSemver && i < len(semverTags); i++ {
+		keep[semverTags[i].tag] = true
+	}
+
+	keptDigests := make(map[string]bool)
+	for _, meta := range metas {
+		if keep[strings.TrimPrefix(meta.URL, url+":")] {
+			keptDigests[meta.Digest] = true
+		}
+	}
+
+	var pruned []Metadata
+	prunedDigests := make(map[string]bool)
+	for _, meta := range metas {
+		if keep[strings.TrimPrefix(meta.URL, url+":")] || keptDigests[meta.Digest] {
+			continue
+		}
+		pruned = append(pruned, meta)
+		if opts.DryRun {
+			continue
+		}
+		if err := c.Delete(ctx, meta.URL); err != nil && !isNotFound(err) {
+			return pruned, fmt.Errorf("deleting '%s' failed: %w", meta.URL, err)
+		}
+		prunedDigests[meta.Digest] = true
+	}
+
+	// Delete the cosign artifacts of the pruned manifests.
+	for digest := range prunedDigests {
+		prefix := strings.Replace(digest, ":", "-", 1)
+		for _, suffix := range []string{".att", ".sbom", ".sig"} {
+			if !cosignTags[prefix+suffix] {
+				continue
+			}
+			ref := repo.Tag(prefix + suffix).Name()
+			if err := c.Delete(ctx, ref); err != nil && !isNotFound(err) {
+				return pruned, fmt.Errorf("deleting '%s' failed: %w", ref, err)
+			}
+		}
+	}
+
+	return pruned, nil
+}
+
+// isNotFound returns true if the registry reported that the manifest or the
+// tag does not exist.
+func isNotFound(err error) bool {
+	var terr *transport.Error
+	return errors.As(err, &terr) && terr.StatusCode == http.StatusNotFound
+}
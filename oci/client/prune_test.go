@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	gcrv1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	. "github.com/onsi/gomega"
+)
+
+func Test_Prune(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         PruneOptions
+		expectPruned []string
+	}{
+		{
+			name:         "keep last semver",
+			opts:         PruneOptions{KeepLastSemver: 2},
+			expectPruned: []string{"v0.1.0", "v0.2.0", "staging-fb3355b"},
+		},
+		{
+			name:         "keep last semver and regex",
+			opts:         PruneOptions{KeepLastSemver: 1, KeepRegex: "^(latest|staging-.*)$"},
+			expectPruned: []string{"v0.1.0", "v0.2.0", "v0.3.0"},
+		},
+		{
+			name:         "min age",
+			opts:         PruneOptions{MinAge: 24 * time.Hour},
+			expectPruned: []string{"v0.1.0"},
+		},
+		{
+			name:         "dry-run",
+			opts:         PruneOptions{KeepLastSemver: 2, DryRun: true},
+			expectPruned: []string{"v0.1.0", "v0.2.0", "staging-fb3355b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+			c := NewClient(DefaultOptions())
+			repo := fmt.Sprintf("%s/test-prune%s", dockerReg, randStringRunes(5))
+
+			// The tags and the age of their artifact. The latest tag points
+			// to the same manifest as the highest semver.
+			tags := map[string]time.Duration{
+				"v0.1.0":          72 * time.Hour,
+				"v0.2.0":          time.Hour,
+				"v0.3.0":          time.Hour,
+				"v1.0.0":          time.Hour,
+				"staging-fb3355b": time.Hour,
+			}
+			digests := make(map[string]string)
+			for tag, age := range tags {
+				img, err := random.Image(1024, 1)
+				g.Expect(err).ToNot(HaveOccurred())
+				img = mutate.Annotations(img, (&Metadata{
+					Created: time.Now().Add(-age).Format(time.RFC3339),
+				}).ToAnnotations()).(gcrv1.Image)
+				g.Expect(crane.Push(img, repo+":"+tag, c.options...)).To(Succeed())
+				digest, err := img.Digest()
+				g.Expect(err).ToNot(HaveOccurred())
+				digests[tag] = digest.String()
+			}
+			_, err := c.Tag(ctx, repo+":v1.0.0", "latest")
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// Sign the oldest artifact.
+			sig, err := random.Image(256, 1)
+			g.Expect(err).ToNot(HaveOccurred())
+			sigTag := strings.Replace(digests["v0.1.0"], ":", "-", 1) + ".sig"
+			g.Expect(crane.Push(sig, repo+":"+sigTag, c.options...)).To(Succeed())
+
+			pruned, err := c.Prune(ctx, repo, tt.opts)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var prunedTags []string
+			for _, meta := range pruned {
+				prunedTags = append(prunedTags, strings.TrimPrefix(meta.URL, repo+":"))
+			}
+			g.Expect(prunedTags).To(ConsistOf(tt.expectPruned))
+
+			remaining, err := crane.ListTags(repo, c.options...)
+			g.Expect(err).ToNot(HaveOccurred())
+			expectRemaining := []string{"latest", sigTag}
+			for tag := range tags {
+				expectRemaining = append(expectRemaining, tag)
+			}
+			if !tt.opts.DryRun {
+				expectRemaining = remove(expectRemaining, tt.expectPruned...)
+				if contains(tt.expectPruned, "v0.1.0") {
+					expectRemaining = remove(expectRemaining, sigTag)
+				}
+			}
+			g.Expect(remaining).To(ConsistOf(expectRemaining))
+		})
+	}
+}
+
+func Test_Prune_Error(t *testing.T) {
+	g := NewWithT(t)
+	c := NewClient(DefaultOptions())
+
+	_, err := c.Prune(context.Background(), dockerReg+"/test-prune", PruneOptions{KeepRegex: "("})
+	g.Expect(err).To(MatchError(ContainSubstring("regex '(' parse error")))
+
+	_, err = c.Prune(context.Background(), dockerReg+"/test-prune", PruneOptions{DryRun: true})
+	g.Expect(err).To(MatchError(ContainSubstring("no retention rule set")))
+}
+
+func Test_Prune_ManifestDeleted(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+	c := NewClient(DefaultOptions())
+	repo := fmt.Sprintf("%s/test-prune%s", dockerReg, randStringRunes(5))
+
+	img, err := random.Image(1024, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	for _, tag := range []string{"stale-1", "stale-2"} {
+		g.Expect(crane.Push(img, repo+":"+tag, c.options...)).To(Succeed())
+	}
+	kept, err := random.Image(1024, 1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(crane.Push(kept, repo+":keep", c.options...)).To(Succeed())
+
+	// Emulate a registry deleting the manifest of a deleted tag, along with
+	// the other tags of the manifest.
+	var deletes int
+	c.options = append(c.options, crane.WithTransport(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method == http.MethodDelete {
+			deletes++
+			if deletes > 1 {
+				return &http.Response{
+					StatusCode: http.StatusNotFound,
+					Body:       io.NopCloser(strings.NewReader(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`)),
+					Request:    req,
+				}, nil
+			}
+		}
+		return http.DefaultTransport.RoundTrip(req)
+	})))
+
+	pruned, err := c.Prune(ctx, repo, PruneOptions{KeepRegex: "^keep$"})
+	g.Expect(err).ToNot(HaveOccurred())
+	var prunedTags []string
+	for _, meta := range pruned {
+		prunedTags = append(prunedTags, strings.TrimPrefix(meta.URL, repo+":"))
+	}
+	g.Expect(prunedTags).To(ConsistOf("stale-1", "stale-2"))
+	g.Expect(deletes).To(Equal(2))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func remove(list []string, items ...string) []string {
+	var out []string
+	for _, s := range list {
+		if !contains(items, s) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
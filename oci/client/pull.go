@@ -75,9 +75,9 @@ func (c *Client) Pull(ctx context.Context, url, outPath string, opts ...PullOpti
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
 
-	img, err := crane.Pull(url, c.optionsWithContext(ctx)...)
+	img, err := crane.Pull(url, c.optionsForURL(ctx, url)...)
 	if err != nil {
-		return nil, err
+		return nil, c.insecureError(url, err)
 	}
 
 	digest, err := img.Digest()
@@ -128,8 +128,8 @@ func (c *Client) Push(ctx context.Context, url, sourcePath string, opts ...PushO
 		return "", fmt.Errorf("appeding content to artifact failed: %w", err)
 	}
 
-	if err := crane.Push(img, url, c.optionsWithContext(ctx)...); err != nil {
-		return "", fmt.Errorf("pushing artifact failed: %w", err)
+	if err := crane.Push(img, url, c.optionsForURL(ctx, url)...); err != nil {
+		return "", c.insecureError(url, fmt.Errorf("pushing artifact failed: %w", err))
 	}
 
 	digest, err := img.Digest()
@@ -31,8 +31,8 @@ func (c *Client) Tag(ctx context.Context, url, tag string) (string, error) {
 		return "", fmt.Errorf("invalid URL: %w", err)
 	}
 
-	if err := crane.Tag(url, tag, c.optionsWithContext(ctx)...); err != nil {
-		return "", err
+	if err := crane.Tag(url, tag, c.optionsForURL(ctx, url)...); err != nil {
+		return "", c.insecureError(url, err)
 	}
 
 	dst := ref.Context().Tag(tag)
@@ -0,0 +1,263 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+)
+
+// TransportOptions holds the options for the connections to OCI registries.
+type TransportOptions struct {
+	// ProxyURL is the URL of the proxy used for the connections. When nil,
+	// the proxy is configured from the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	// environment variables.
+	ProxyURL *url.URL
+
+	// CAData is a PEM-encoded bundle of certificate authorities trusted in
+	// addition to the system certificate pool.
+	CAData []byte
+
+	// Insecure allows connecting to the registry over plain HTTP. It can
+	// only be set in the options of a registry in Registries, so that plain
+	// HTTP is never allowed for all the registries.
+	Insecure bool
+
+	// InsecureSkipTLSVerify allows connecting to the registry without
+	// verifying its TLS certificate, e.g. a self-signed certificate of a
+	// registry in an air-gapped lab. It can only be set in the options of a
+	// registry in Registries. Prefer CAData whenever the CA of the registry
+	// is known.
+	InsecureSkipTLSVerify bool
+
+	// Registries holds the options for specific registries, keyed by their
+	// host, e.g. "ghcr.io" or "registry.local:5000". The options of a
+	// registry replace the top-level options entirely, and their own
+	// Registries are ignored. The registries with Insecure or
+	// InsecureSkipTLSVerify set are the allow list of the registries to which
+	// insecure connections are allowed.
+	//
+	// The options of a registry only apply to the requests sent to its
+	// host, while the requests to a token service on a different host use
+	// the top-level options.
+	Registries map[string]TransportOptions
+}
+
+// ErrInsecureRegistryNotAllowed is returned by the registry operations
+// failing because the registry serves plain HTTP or a certificate which
+// can't be verified, and insecure connections are not allowed for it.
+var ErrInsecureRegistryNotAllowed = errors.New("insecure connections are not allowed for the registry")
+
+// errPlainHTTPNotAllowed is returned by the registry transport for the plain
+// HTTP requests to the hosts not configured as insecure.
+var errPlainHTTPNotAllowed = errors.New("plain HTTP is not allowed")
+
+// Validate returns an error if the options are invalid, e.g. insecure
+// connections are allowed for all the registries. It can be called when
+// parsing the options, before configuring a client with them.
+func (o TransportOptions) Validate() error {
+	if o.Insecure || o.InsecureSkipTLSVerify {
+		return errors.New("insecure connections can only be allowed for the registries listed in the registry options")
+	}
+	for host := range o.Registries {
+		if err := validateRegistryHost(host); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateRegistryHost returns an error if the host is not the host of a
+// registry, with an optional port.
+func validateRegistryHost(host string) error {
+	if host == "" || strings.ContainsAny(host, "/*") {
+		return fmt.Errorf("invalid registry host '%s': must be a host name with an optional port, e.g. registry.local:5000", host)
+	}
+	if _, err := name.NewRegistry(host, name.StrictValidation); err != nil {
+		return fmt.Errorf("invalid registry host '%s': %w", host, err)
+	}
+	return nil
+}
+
+// ConfigureTransport configures the client to connect to the registries with
+// the given options, for all the registry operations. The options are
+// validated with TransportOptions.Validate.
+func (c *Client) ConfigureTransport(opts TransportOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	defaultTransport, err := newTransport(opts)
+	if err != nil {
+		return err
+	}
+
+	rt := &registryTransport{
+		defaultTransport: defaultTransport,
+		registries:       make(map[string]http.RoundTripper, len(opts.Registries)),
+	}
+	insecure := make(map[string]bool, len(opts.Registries))
+	skipTLSVerify := make(map[string]bool, len(opts.Registries))
+	for host, registryOpts := range opts.Registries {
+		t, err := newTransport(registryOpts)
+		if err != nil {
+			return fmt.Errorf("invalid transport options for registry '%s': %w", host, err)
+		}
+		rt.registries[host] = t
+		insecure[host] = registryOpts.Insecure
+		skipTLSVerify[host] = registryOpts.InsecureSkipTLSVerify
+	}
+
+	rt.insecure = insecure
+
+	c.options = append(c.options, crane.WithTransport(rt))
+	c.insecureRegistries = insecure
+	c.skipTLSVerifyRegistries = skipTLSVerify
+	return nil
+}
+
+// newTransport returns an HTTP transport configured with the given options.
+func newTransport(opts TransportOptions) (*http.Transport, error) {
+	t := remote.DefaultTransport.(*http.Transport).Clone()
+	if opts.ProxyURL != nil {
+		t.Proxy = http.ProxyURL(opts.ProxyURL)
+	}
+
+	if len(opts.CAData) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(opts.CAData) {
+			return nil, errors.New("failed to parse CA certificates")
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.RootCAs = pool
+	}
+	if opts.InsecureSkipTLSVerify {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.InsecureSkipVerify = true
+	}
+	return t, nil
+}
+
+// registryTransport is an http.RoundTripper sending the requests through the
+// transport configured for the registry host, or the default transport. The
+// plain HTTP requests are only sent to the hosts configured as insecure,
+// including the local hosts to which go-containerregistry falls back to
+// plain HTTP on its own.
+type registryTransport struct {
+	defaultTransport http.RoundTripper
+	registries       map[string]http.RoundTripper
+	insecure         map[string]bool
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *registryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme == "http" && !t.insecure[req.URL.Host] {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, errPlainHTTPNotAllowed
+	}
+	if rt, ok := t.registries[req.URL.Host]; ok {
+		return rt.RoundTrip(req)
+	}
+	return t.defaultTransport.RoundTrip(req)
+}
+
+// optionsForURL returns the crane options for the given context and the
+// registry of the given URL, allowing plain HTTP connections if the registry
+// is configured as insecure.
+func (c *Client) optionsForURL(ctx context.Context, artifactURL string) []crane.Option {
+	options := c.optionsWithContext(ctx)
+	if len(c.insecureRegistries) == 0 {
+		return options
+	}
+
+	if c.insecureRegistries[registryForURL(artifactURL)] {
+		options = append(options, crane.Insecure)
+	}
+	return options
+}
+
+// registryForURL returns the registry of the given URL, or an empty string
+// if the URL is invalid.
+func registryForURL(artifactURL string) string {
+	if ref, err := name.ParseReference(artifactURL); err == nil {
+		return ref.Context().RegistryStr()
+	}
+	if repo, err := name.NewRepository(artifactURL); err == nil {
+		return repo.RegistryStr()
+	}
+	return ""
+}
+
+// insecureError wraps the error of an operation on the given URL with
+// ErrInsecureRegistryNotAllowed, if the registry of the URL serves plain
+// HTTP or a certificate which can't be verified, and insecure connections
+// are not allowed for it.
+func (c *Client) insecureError(artifactURL string, err error) error {
+	if err == nil {
+		return nil
+	}
+	registry := registryForURL(artifactURL)
+	switch {
+	case isCertificateError(err) && !c.skipTLSVerifyRegistries[registry]:
+		return fmt.Errorf("%w: the certificate of '%s' can't be verified, and the registry is not configured "+
+			"with its CA or to skip the TLS verification in the registry options: %w",
+			ErrInsecureRegistryNotAllowed, registry, err)
+	case isPlainHTTPError(err) && !c.insecureRegistries[registry]:
+		return fmt.Errorf("%w: '%s' serves plain HTTP, and the registry is not configured as insecure "+
+			"in the registry options: %w", ErrInsecureRegistryNotAllowed, registry, err)
+	default:
+		return err
+	}
+}
+
+// isCertificateError returns true if the error is caused by a certificate
+// which can't be verified.
+func isCertificateError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &verificationErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
+
+// isPlainHTTPError returns true if the error is caused by a server
+// responding with plain HTTP to a TLS handshake.
+func isPlainHTTPError(err error) bool {
+	var recordHeaderErr tls.RecordHeaderError
+	return errors.Is(err, http.ErrSchemeMismatch) || errors.As(err, &recordHeaderErr)
+}
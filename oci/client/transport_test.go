@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/crane"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigureTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer tlsServer.Close()
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: tlsServer.Certificate().Raw})
+
+	tlsHost := tlsServer.Listener.Addr().String()
+	proxyURL, _ := url.Parse("http://proxy.example.com:3128")
+
+	c := NewClient(DefaultOptions())
+	err := c.ConfigureTransport(TransportOptions{
+		ProxyURL: proxyURL,
+		Registries: map[string]TransportOptions{
+			tlsHost:               {CAData: caData},
+			"registry.local:5000": {Insecure: true},
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.insecureRegistries).To(HaveKeyWithValue("registry.local:5000", true))
+
+	rt, ok := crane.GetOptions(c.GetOptions()...).Transport.(*registryTransport)
+	g.Expect(ok).To(BeTrue())
+
+	// the registry transport trusts the custom CA
+	req, _ := http.NewRequest(http.MethodGet, tlsServer.URL+"/v2/", nil)
+	resp, err := rt.RoundTrip(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	resp.Body.Close()
+
+	// the default transport uses the proxy
+	defaultTransport := rt.defaultTransport.(*http.Transport)
+	req, _ = http.NewRequest(http.MethodGet, "https://ghcr.io/v2/", nil)
+	got, err := defaultTransport.Proxy(req)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(proxyURL))
+
+	// the default transport does not trust the custom CA
+	noProxy := defaultTransport.Clone()
+	noProxy.Proxy = nil
+	req, _ = http.NewRequest(http.MethodGet, tlsServer.URL+"/v2/", nil)
+	_, err = noProxy.RoundTrip(req)
+	g.Expect(err).To(MatchError(ContainSubstring("certificate")))
+
+	// plain HTTP is only allowed for the insecure registry
+	g.Expect(c.optionsForURL(context.TODO(), "registry.local:5000/org/app:v1")).To(HaveLen(len(c.GetOptions()) + 2))
+	g.Expect(c.optionsForURL(context.TODO(), "ghcr.io/org/app:v1")).To(HaveLen(len(c.GetOptions()) + 1))
+}
+
+func TestConfigureTransport_InvalidCA(t *testing.T) {
+	g := NewWithT(t)
+
+	c := NewClient(DefaultOptions())
+	err := c.ConfigureTransport(TransportOptions{
+		Registries: map[string]TransportOptions{
+			"ghcr.io": {CAData: []byte("invalid")},
+		},
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid transport options for registry 'ghcr.io'")))
+}
+
+func TestTransportOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    TransportOptions
+		wantErr string
+	}{
+		{
+			name: "insecure registries",
+			opts: TransportOptions{Registries: map[string]TransportOptions{
+				"registry.local:5000": {Insecure: true},
+				"10.0.0.1":            {InsecureSkipTLSVerify: true},
+			}},
+		},
+		{
+			name:    "insecure for all the registries",
+			opts:    TransportOptions{Insecure: true},
+			wantErr: "insecure connections can only be allowed for the registries listed in the registry options",
+		},
+		{
+			name:    "TLS verification skipped for all the registries",
+			opts:    TransportOptions{InsecureSkipTLSVerify: true},
+			wantErr: "insecure connections can only be allowed for the registries listed in the registry options",
+		},
+		{
+			name: "registry URL",
+			opts: TransportOptions{Registries: map[string]TransportOptions{
+				"http://registry.local:5000": {Insecure: true},
+			}},
+			wantErr: "invalid registry host 'http://registry.local:5000'",
+		},
+		{
+			name: "wildcard registry",
+			opts: TransportOptions{Registries: map[string]TransportOptions{
+				"*.local": {Insecure: true},
+			}},
+			wantErr: "invalid registry host '*.local'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tt.opts.Validate()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+				g.Expect(NewClient(DefaultOptions()).ConfigureTransport(tt.opts)).To(MatchError(err))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
+
+func TestConfigureTransport_InsecureRegistries(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.TODO()
+
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer tlsServer.Close()
+	tlsHost := tlsServer.Listener.Addr().String()
+
+	// the registries serving plain HTTP or a self-signed certificate are
+	// blocked unless they are configured as insecure, including the local
+	// registries
+	c := NewClient(DefaultOptions())
+	g.Expect(c.ConfigureTransport(TransportOptions{})).To(Succeed())
+	_, err := c.Pull(ctx, dockerReg+"/app:v1", t.TempDir())
+	g.Expect(err).To(MatchError(ErrInsecureRegistryNotAllowed))
+	g.Expect(err).To(MatchError(ContainSubstring("'%s' serves plain HTTP", dockerReg)))
+	_, err = c.Pull(ctx, tlsHost+"/app:v1", t.TempDir())
+	g.Expect(err).To(MatchError(ErrInsecureRegistryNotAllowed))
+	g.Expect(err).To(MatchError(ContainSubstring("the certificate of '%s' can't be verified", tlsHost)))
+
+	// all the registry operations are blocked alike
+	for _, host := range []string{dockerReg, tlsHost} {
+		_, err = c.Push(ctx, host+"/app:v1", "testdata/artifact")
+		g.Expect(err).To(MatchError(ErrInsecureRegistryNotAllowed))
+		_, err = c.List(ctx, host+"/app", ListOptions{})
+		g.Expect(err).To(MatchError(ErrInsecureRegistryNotAllowed))
+		_, err = c.Tag(ctx, host+"/app:v1", "v2")
+		g.Expect(err).To(MatchError(ErrInsecureRegistryNotAllowed))
+		err = c.Delete(ctx, host+"/app:v1")
+		g.Expect(err).To(MatchError(ErrInsecureRegistryNotAllowed))
+		err = c.Diff(ctx, host+"/app:v1", "testdata/artifact", nil)
+		g.Expect(err).To(MatchError(ErrInsecureRegistryNotAllowed))
+	}
+
+	c = NewClient(DefaultOptions())
+	g.Expect(c.ConfigureTransport(TransportOptions{
+		Registries: map[string]TransportOptions{
+			dockerReg: {Insecure: true},
+			tlsHost:   {InsecureSkipTLSVerify: true},
+		},
+	})).To(Succeed())
+	_, err = c.Pull(ctx, dockerReg+"/app:v1", t.TempDir())
+	g.Expect(err).To(MatchError(ContainSubstring("MANIFEST_UNKNOWN")))
+	g.Expect(err).ToNot(MatchError(ErrInsecureRegistryNotAllowed))
+	_, err = c.Pull(ctx, tlsHost+"/app:v1", t.TempDir())
+	g.Expect(err).To(MatchError(ContainSubstring("404 Not Found")))
+	g.Expect(err).ToNot(MatchError(ErrInsecureRegistryNotAllowed))
+}
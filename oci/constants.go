@@ -27,6 +27,10 @@ const (
 	ProviderAWS
 	ProviderGCP
 	ProviderAzure
+	// ProviderDev is used to categorize the registries of which the
+	// credentials are minted by the dev authentication provider, for local
+	// development and e2e tests.
+	ProviderDev
 )
 
 // Registry TLS transport config.
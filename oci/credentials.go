@@ -0,0 +1,38 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import "time"
+
+// ArtifactCredentials are the credentials of a repository of non-container
+// artifacts, e.g. a Maven, npm or generic package repository, obtained with
+// the native authentication mechanism of a cloud provider. They are used
+// with HTTP basic authentication, or the Password alone as a bearer token.
+type ArtifactCredentials struct {
+	// Username is the username of the credentials.
+	Username string
+	// Password is the password of the credentials, which is a token
+	// issued by the cloud provider.
+	Password string
+	// ExpiresAt is the expiry of the credentials.
+	ExpiresAt time.Time
+}
+
+// GetDuration returns the remaining validity of the credentials.
+func (c *ArtifactCredentials) GetDuration() time.Duration {
+	return time.Until(c.ExpiresAt)
+}
@@ -18,6 +18,7 @@ package acl
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,8 +42,12 @@ func accessDeniedErrorf(f string, args ...interface{}) error {
 }
 
 // IsAccessDenied returns true if the supplied error is an access denied error; e.g., as returned by
-// HasAccessToRef.
+// HasAccessToRef or HasTenancyAccessToRef.
 func IsAccessDenied(e error) bool {
+	var denial *Denial
+	if errors.As(e, &denial) {
+		return true
+	}
 	_, ok := e.(AccessDeniedError)
 	return ok
 }
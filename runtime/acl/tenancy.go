@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acl
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AllowFromLabel is the label of a namespace allowing the objects of the
+// namespace set as its value to reference the objects of the namespace,
+// e.g. `tenants.fluxcd.io/allow-from: flux-system`.
+const AllowFromLabel = "tenants.fluxcd.io/allow-from"
+
+// DenialReason is the reason of a Denial.
+type DenialReason string
+
+const (
+	// DenialReasonNamespaceNotFound is the reason of a denial when the
+	// namespace of the reference does not exist.
+	DenialReasonNamespaceNotFound DenialReason = "NamespaceNotFound"
+	// DenialReasonMissingAllowFrom is the reason of a denial when the
+	// namespace of the reference does not have the AllowFromLabel.
+	DenialReasonMissingAllowFrom DenialReason = "MissingAllowFromLabel"
+	// DenialReasonAllowFromMismatch is the reason of a denial when the
+	// AllowFromLabel of the namespace of the reference does not match the
+	// namespace of the referencing object.
+	DenialReasonAllowFromMismatch DenialReason = "AllowFromLabelMismatch"
+)
+
+// Denial is the structured denial of a cross-namespace reference by the
+// tenancy labels. It is an access denied error, see IsAccessDenied.
+type Denial struct {
+	// Reason is the reason access is denied.
+	Reason DenialReason
+	// SourceNamespace is the namespace of the referencing object.
+	SourceNamespace string
+	// Reference is the denied reference.
+	Reference types.NamespacedName
+}
+
+func (d *Denial) Error() string {
+	switch d.Reason {
+	case DenialReasonNamespaceNotFound:
+		return fmt.Sprintf("'%s/%s' can't be accessed from namespace '%s' as namespace '%s' does not exist",
+			d.Reference.Namespace, d.Reference.Name, d.SourceNamespace, d.Reference.Namespace)
+	case DenialReasonMissingAllowFrom:
+		return fmt.Sprintf("'%s/%s' can't be accessed from namespace '%s' due to missing '%s' label on namespace '%s'",
+			d.Reference.Namespace, d.Reference.Name, d.SourceNamespace, AllowFromLabel, d.Reference.Namespace)
+	default:
+		return fmt.Sprintf("'%s/%s' can't be accessed from namespace '%s' due to '%s' label mismatch on namespace '%s'",
+			d.Reference.Namespace, d.Reference.Name, d.SourceNamespace, AllowFromLabel, d.Reference.Namespace)
+	}
+}
+
+// CheckTenancy checks if an object in the source namespace may reference an
+// object in another namespace, given the labels of the namespace of the
+// reference. It returns nil if access is granted, or the Denial otherwise.
+// References within the source namespace are always granted.
+func CheckTenancy(sourceNamespace string, reference types.NamespacedName, namespaceLabels map[string]string) *Denial {
+	if reference.Namespace == "" || reference.Namespace == sourceNamespace {
+		return nil
+	}
+	allowFrom, ok := namespaceLabels[AllowFromLabel]
+	switch {
+	case !ok:
+		return &Denial{Reason: DenialReasonMissingAllowFrom, SourceNamespace: sourceNamespace, Reference: reference}
+	case allowFrom != sourceNamespace:
+		return &Denial{Reason: DenialReasonAllowFromMismatch, SourceNamespace: sourceNamespace, Reference: reference}
+	default:
+		return nil
+	}
+}
+
+// HasTenancyAccessToRef checks if a namespaced object has access to a
+// cross-namespace reference based on the AllowFromLabel of the namespace of
+// the reference. It returns `nil` if access is possible, or a *Denial if it
+// is not possible; any other kind of error indicates that the check could
+// not be completed.
+func (a *Authorization) HasTenancyAccessToRef(ctx context.Context, object client.Object, reference types.NamespacedName) error {
+	denial, err := checkTenancy(ctx, a.client, object.GetNamespace(), reference)
+	if err != nil {
+		return err
+	}
+	if denial != nil {
+		return denial
+	}
+	return nil
+}
+
+// TenancyReference is a cross-namespace reference of an object being
+// admitted, along with the path of the field holding it.
+type TenancyReference struct {
+	// Path is the path of the field holding the reference, e.g.
+	// field.NewPath("spec", "sourceRef").
+	Path *field.Path
+	// Reference is the namespaced name of the referenced object.
+	Reference types.NamespacedName
+}
+
+// ValidateTenancy validates that the references of an object in the given
+// namespace comply with the tenancy labels, and returns the violations as a
+// list of field errors, e.g. to deny the admission of the object in a
+// validating webhook:
+//
+//	errs := acl.ValidateTenancy(ctx, reader, obj.Namespace,
+//		acl.TenancyReference{Path: field.NewPath("spec", "sourceRef"), Reference: ref})
+//	if len(errs) > 0 {
+//		return nil, apierrors.NewInvalid(gvk.GroupKind(), obj.Name, errs)
+//	}
+func ValidateTenancy(ctx context.Context, reader client.Reader, namespace string, references ...TenancyReference) field.ErrorList {
+	var errs field.ErrorList
+	for _, ref := range references {
+		denial, err := checkTenancy(ctx, reader, namespace, ref.Reference)
+		switch {
+		case err != nil:
+			errs = append(errs, field.InternalError(ref.Path, err))
+		case denial != nil:
+			errs = append(errs, field.Forbidden(ref.Path, denial.Error()))
+		}
+	}
+	return errs
+}
+
+// checkTenancy gets the namespace of the reference and checks the access
+// to the reference with CheckTenancy.
+func checkTenancy(ctx context.Context, reader client.Reader, sourceNamespace string,
+	reference types.NamespacedName) (*Denial, error) {
+	if reference.Namespace == "" || reference.Namespace == sourceNamespace {
+		return nil, nil
+	}
+	var ns corev1.Namespace
+	if err := reader.Get(ctx, types.NamespacedName{Name: reference.Namespace}, &ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return &Denial{Reason: DenialReasonNamespaceNotFound, SourceNamespace: sourceNamespace, Reference: reference}, nil
+		}
+		return nil, err
+	}
+	return CheckTenancy(sourceNamespace, reference, ns.GetLabels()), nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package acl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestCheckTenancy(t *testing.T) {
+	tests := []struct {
+		name       string
+		reference  types.NamespacedName
+		labels     map[string]string
+		wantReason DenialReason
+	}{
+		{
+			name:      "same namespace",
+			reference: getReference("app", "tenant-a"),
+		},
+		{
+			name:      "empty namespace",
+			reference: getReference("app", ""),
+		},
+		{
+			name:      "allowed",
+			reference: getReference("app", "shared"),
+			labels:    map[string]string{AllowFromLabel: "tenant-a"},
+		},
+		{
+			name:       "missing label",
+			reference:  getReference("app", "shared"),
+			labels:     map[string]string{"tenant": "a"},
+			wantReason: DenialReasonMissingAllowFrom,
+		},
+		{
+			name:       "label mismatch",
+			reference:  getReference("app", "shared"),
+			labels:     map[string]string{AllowFromLabel: "tenant-b"},
+			wantReason: DenialReasonAllowFromMismatch,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			denial := CheckTenancy("tenant-a", tt.reference, tt.labels)
+			if tt.wantReason == "" {
+				g.Expect(denial).To(BeNil())
+				return
+			}
+			g.Expect(denial).ToNot(BeNil())
+			g.Expect(denial.Reason).To(Equal(tt.wantReason))
+			g.Expect(denial.SourceNamespace).To(Equal("tenant-a"))
+			g.Expect(denial.Reference).To(Equal(tt.reference))
+			g.Expect(IsAccessDenied(denial)).To(BeTrue())
+		})
+	}
+}
+
+func TestAuthorization_HasTenancyAccessToRef(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		getNamespaceWithLabels("shared", map[string]string{AllowFromLabel: "tenant-a"}),
+		getNamespaceWithLabels("private", nil),
+	).Build()
+	aclAuth := NewAuthorization(kubeClient)
+
+	object := getObject("app", "tenant-a")
+	g.Expect(aclAuth.HasTenancyAccessToRef(context.TODO(), object, getReference("app", "tenant-a"))).To(Succeed())
+	g.Expect(aclAuth.HasTenancyAccessToRef(context.TODO(), object, getReference("app", "shared"))).To(Succeed())
+
+	err := aclAuth.HasTenancyAccessToRef(context.TODO(), object, getReference("app", "private"))
+	g.Expect(IsAccessDenied(err)).To(BeTrue())
+	g.Expect(err).To(MatchError("'private/app' can't be accessed from namespace 'tenant-a' due to missing 'tenants.fluxcd.io/allow-from' label on namespace 'private'"))
+
+	err = aclAuth.HasTenancyAccessToRef(context.TODO(), object, getReference("app", "missing"))
+	var denial *Denial
+	g.Expect(errors.As(err, &denial)).To(BeTrue())
+	g.Expect(denial.Reason).To(Equal(DenialReasonNamespaceNotFound))
+}
+
+func TestValidateTenancy(t *testing.T) {
+	g := NewWithT(t)
+
+	kubeClient := fake.NewClientBuilder().WithObjects(
+		getNamespaceWithLabels("shared", map[string]string{AllowFromLabel: "tenant-a"}),
+		getNamespaceWithLabels("other", map[string]string{AllowFromLabel: "tenant-b"}),
+	).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if key.Name == "broken" {
+				return errors.New("connection refused")
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+
+	errs := ValidateTenancy(context.TODO(), kubeClient, "tenant-a",
+		TenancyReference{Path: field.NewPath("spec", "sourceRef"), Reference: getReference("app", "shared")},
+		TenancyReference{Path: field.NewPath("spec", "dependsOn").Index(0), Reference: getReference("app", "tenant-a")},
+	)
+	g.Expect(errs).To(BeEmpty())
+
+	errs = ValidateTenancy(context.TODO(), kubeClient, "tenant-a",
+		TenancyReference{Path: field.NewPath("spec", "sourceRef"), Reference: getReference("app", "other")},
+		TenancyReference{Path: field.NewPath("spec", "dependsOn").Index(0), Reference: getReference("app", "broken")},
+	)
+	g.Expect(errs).To(HaveLen(2))
+	g.Expect(errs[0].Type).To(Equal(field.ErrorTypeForbidden))
+	g.Expect(errs[0].Field).To(Equal("spec.sourceRef"))
+	g.Expect(errs[0].Detail).To(ContainSubstring("label mismatch on namespace 'other'"))
+	g.Expect(errs[1].Type).To(Equal(field.ErrorTypeInternal))
+	g.Expect(errs[1].Field).To(Equal("spec.dependsOn[0]"))
+}
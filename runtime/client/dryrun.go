@@ -0,0 +1,192 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DryRunOperation is the kind of a mutating call made through a
+// DryRunClient.
+type DryRunOperation string
+
+const (
+	// DryRunCreate is the operation of a Create call.
+	DryRunCreate DryRunOperation = "Create"
+	// DryRunUpdate is the operation of an Update call.
+	DryRunUpdate DryRunOperation = "Update"
+	// DryRunPatch is the operation of a Patch call.
+	DryRunPatch DryRunOperation = "Patch"
+	// DryRunDelete is the operation of a Delete call.
+	DryRunDelete DryRunOperation = "Delete"
+	// DryRunDeleteAllOf is the operation of a DeleteAllOf call.
+	DryRunDeleteAllOf DryRunOperation = "DeleteAllOf"
+)
+
+// DryRunResult is the would-be result of a mutating call made through a
+// DryRunClient.
+type DryRunResult struct {
+	// Operation is the kind of the call.
+	Operation DryRunOperation
+	// SubResource is the subresource the call was made for, e.g. "status",
+	// or empty for the object itself.
+	SubResource string
+	// Object is a copy of the object as returned by the API server, i.e.
+	// the object as it would have been persisted.
+	Object client.Object
+	// Error is the error returned by the API server, e.g. a validation
+	// or admission error.
+	Error error
+}
+
+// DryRunClient is a client.Client running all the mutating calls as
+// server-side dry-runs, and recording their results. The read calls are
+// made through the wrapped client.
+//
+// It allows running the code paths of a controller against a live cluster
+// without any risk of mutation, e.g. to validate the changes a
+// reconciliation would make in a CI workflow:
+//
+//	dryRun := client.NewDryRunClient(kubeClient)
+//	_, err := reconciler.reconcile(ctx, dryRun, obj)
+//	for _, r := range dryRun.Results() {
+//		...
+//	}
+//
+// Note that a dry-run does not persist the objects, the objects created in
+// a dry-run can't be read by subsequent calls.
+type DryRunClient struct {
+	client.Client
+
+	mu      sync.Mutex
+	results []DryRunResult
+}
+
+var _ client.Client = &DryRunClient{}
+
+// NewDryRunClient returns a DryRunClient wrapping the given client.
+func NewDryRunClient(c client.Client) *DryRunClient {
+	return &DryRunClient{Client: c}
+}
+
+// Results returns the results of the mutating calls made through the
+// client, in the order of the calls.
+func (c *DryRunClient) Results() []DryRunResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.results)
+}
+
+// Reset removes the recorded results.
+func (c *DryRunClient) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = nil
+}
+
+// Create runs the creation of the object as a dry-run.
+func (c *DryRunClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	err := c.Client.Create(ctx, obj, append(opts, client.DryRunAll)...)
+	c.record(DryRunCreate, "", obj, err)
+	return err
+}
+
+// Update runs the update of the object as a dry-run.
+func (c *DryRunClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	err := c.Client.Update(ctx, obj, append(opts, client.DryRunAll)...)
+	c.record(DryRunUpdate, "", obj, err)
+	return err
+}
+
+// Patch runs the patch of the object as a dry-run.
+func (c *DryRunClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	err := c.Client.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+	c.record(DryRunPatch, "", obj, err)
+	return err
+}
+
+// Delete runs the deletion of the object as a dry-run.
+func (c *DryRunClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, append(opts, client.DryRunAll)...)
+	c.record(DryRunDelete, "", obj, err)
+	return err
+}
+
+// DeleteAllOf runs the deletion of the objects as a dry-run.
+func (c *DryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	err := c.Client.DeleteAllOf(ctx, obj, append(opts, client.DryRunAll)...)
+	c.record(DryRunDeleteAllOf, "", obj, err)
+	return err
+}
+
+// Status returns a client for the status subresource running the mutating
+// calls as dry-runs.
+func (c *DryRunClient) Status() client.SubResourceWriter {
+	return c.SubResource("status")
+}
+
+// SubResource returns a client for the given subresource running the
+// mutating calls as dry-runs.
+func (c *DryRunClient) SubResource(subResource string) client.SubResourceClient {
+	return &dryRunSubResourceClient{
+		SubResourceClient: c.Client.SubResource(subResource),
+		parent:            c,
+		subResource:       subResource,
+	}
+}
+
+func (c *DryRunClient) record(op DryRunOperation, subResource string, obj client.Object, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = append(c.results, DryRunResult{
+		Operation:   op,
+		SubResource: subResource,
+		Object:      obj.DeepCopyObject().(client.Object),
+		Error:       err,
+	})
+}
+
+// dryRunSubResourceClient is a client.SubResourceClient running the
+// mutating calls as dry-runs, and recording their results in the parent
+// DryRunClient.
+type dryRunSubResourceClient struct {
+	client.SubResourceClient
+	parent      *DryRunClient
+	subResource string
+}
+
+func (c *dryRunSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	err := c.SubResourceClient.Create(ctx, obj, subResource, append(opts, client.DryRunAll)...)
+	c.parent.record(DryRunCreate, c.subResource, subResource, err)
+	return err
+}
+
+func (c *dryRunSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	err := c.SubResourceClient.Update(ctx, obj, append(opts, client.DryRunAll)...)
+	c.parent.record(DryRunUpdate, c.subResource, obj, err)
+	return err
+}
+
+func (c *dryRunSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	err := c.SubResourceClient.Patch(ctx, obj, patch, append(opts, client.DryRunAll)...)
+	c.parent.record(DryRunPatch, c.subResource, obj, err)
+	return err
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDryRunClient(t *testing.T) {
+	g := NewWithT(t)
+
+	existing := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "existing", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}
+
+	var dryRuns []bool
+	kubeClient := fake.NewClientBuilder().
+		WithObjects(existing).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.CreateOption) error {
+				o := &ctrlclient.CreateOptions{}
+				o.ApplyOptions(opts)
+				dryRuns = append(dryRuns, len(o.DryRun) > 0)
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.UpdateOption) error {
+				o := &ctrlclient.UpdateOptions{}
+				o.ApplyOptions(opts)
+				dryRuns = append(dryRuns, len(o.DryRun) > 0)
+				return c.Update(ctx, obj, opts...)
+			},
+			Delete: func(ctx context.Context, c ctrlclient.WithWatch, obj ctrlclient.Object, opts ...ctrlclient.DeleteOption) error {
+				o := &ctrlclient.DeleteOptions{}
+				o.ApplyOptions(opts)
+				dryRuns = append(dryRuns, len(o.DryRun) > 0)
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	dryRun := NewDryRunClient(kubeClient)
+	ctx := context.Background()
+
+	created := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "created", Namespace: "default"},
+	}
+	g.Expect(dryRun.Create(ctx, created)).To(Succeed())
+
+	updated := existing.DeepCopy()
+	g.Expect(dryRun.Get(ctx, ctrlclient.ObjectKeyFromObject(existing), updated)).To(Succeed())
+	updated.Data["key"] = "changed"
+	g.Expect(dryRun.Update(ctx, updated)).To(Succeed())
+
+	g.Expect(dryRun.Delete(ctx, existing.DeepCopy())).To(Succeed())
+
+	g.Expect(dryRuns).To(Equal([]bool{true, true, true}))
+
+	// The cluster was not mutated.
+	got := &corev1.ConfigMap{}
+	g.Expect(kubeClient.Get(ctx, ctrlclient.ObjectKeyFromObject(existing), got)).To(Succeed())
+	g.Expect(got.Data).To(Equal(map[string]string{"key": "value"}))
+	g.Expect(kubeClient.Get(ctx, ctrlclient.ObjectKeyFromObject(created), &corev1.ConfigMap{})).ToNot(Succeed())
+
+	results := dryRun.Results()
+	g.Expect(results).To(HaveLen(3))
+	g.Expect(results[0].Operation).To(Equal(DryRunCreate))
+	g.Expect(results[0].Object.GetName()).To(Equal("created"))
+	g.Expect(results[1].Operation).To(Equal(DryRunUpdate))
+	g.Expect(results[1].Object.(*corev1.ConfigMap).Data).To(Equal(map[string]string{"key": "changed"}))
+	g.Expect(results[2].Operation).To(Equal(DryRunDelete))
+	for _, r := range results {
+		g.Expect(r.SubResource).To(BeEmpty())
+		g.Expect(r.Error).ToNot(HaveOccurred())
+	}
+
+	dryRun.Reset()
+	g.Expect(dryRun.Results()).To(BeEmpty())
+}
+
+func TestDryRunClient_Status(t *testing.T) {
+	g := NewWithT(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "default"},
+	}
+	var dryRun bool
+	kubeClient := fake.NewClientBuilder().
+		WithObjects(pod).
+		WithStatusSubresource(pod).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c ctrlclient.Client, subResourceName string, obj ctrlclient.Object, opts ...ctrlclient.SubResourceUpdateOption) error {
+				o := &ctrlclient.SubResourceUpdateOptions{}
+				o.ApplyOptions(opts)
+				dryRun = len(o.DryRun) > 0
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	c := NewDryRunClient(kubeClient)
+	updated := pod.DeepCopy()
+	updated.Status.Phase = corev1.PodRunning
+	g.Expect(c.Status().Update(context.Background(), updated)).To(Succeed())
+	g.Expect(dryRun).To(BeTrue())
+
+	results := c.Results()
+	g.Expect(results).To(HaveLen(1))
+	g.Expect(results[0].Operation).To(Equal(DryRunUpdate))
+	g.Expect(results[0].SubResource).To(Equal("status"))
+	g.Expect(results[0].Object.(*corev1.Pod).Status.Phase).To(Equal(corev1.PodRunning))
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldMetadataName is the field selector key matching the name of an object.
+const fieldMetadataName = "metadata.name"
+
+// Scope restricts the objects of a kind that are listed and watched by the
+// cache of a controller. The restrictions are enforced by the Kubernetes API
+// server, which means objects outside the scope are never transferred to or
+// held in memory by the controller.
+//
+// Scopes are intended for multi-tenant deployments, where a controller only
+// needs to read objects of a kind (e.g. Secrets) in the namespaces of its
+// tenants:
+//
+//	cacheOpts := ctrlcache.Options{}
+//	client.ApplyScopes(&cacheOpts, map[ctrlclient.Object]client.Scope{
+//		&corev1.Secret{}: {
+//			Namespaces: tenantNamespaces,
+//			Labels:     labels.Set{"toolkit.fluxcd.io/tenant": "true"},
+//		},
+//	})
+//	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{Cache: cacheOpts})
+//
+// Field selectors only support exact matches, and the supported fields
+// depend on the kind. Objects identified by a naming convention (e.g. Secrets
+// suffixed with "-auth") should be labeled, and selected using Labels.
+type Scope struct {
+	// Namespaces restricts the objects to the given namespaces. When empty,
+	// the objects are listed in the namespaces configured as default for
+	// the cache.
+	Namespaces []string
+
+	// Labels restricts the objects to those with all the given labels.
+	Labels labels.Set
+
+	// Fields restricts the objects to those with all the given field values,
+	// e.g. "type" for Secrets.
+	Fields fields.Set
+}
+
+// NameScope returns a Scope restricting the objects to those with the given
+// name in the given namespaces.
+func NameScope(name string, namespaces ...string) Scope {
+	return Scope{
+		Namespaces: namespaces,
+		Fields:     fields.Set{fieldMetadataName: name},
+	}
+}
+
+// ByObject returns the controller-runtime cache configuration for the
+// scope.
+func (s Scope) ByObject() cache.ByObject {
+	b := cache.ByObject{}
+	if len(s.Labels) > 0 {
+		b.Label = labels.SelectorFromSet(s.Labels)
+	}
+	if len(s.Fields) > 0 {
+		b.Field = fields.SelectorFromSet(s.Fields)
+	}
+	if len(s.Namespaces) > 0 {
+		b.Namespaces = make(map[string]cache.Config, len(s.Namespaces))
+		for _, ns := range s.Namespaces {
+			b.Namespaces[ns] = cache.Config{}
+		}
+	}
+	return b
+}
+
+// ApplyScopes configures the given cache options to list and watch the
+// objects of each kind restricted to its scope. The options must not
+// already contain a configuration for any of the kinds.
+func ApplyScopes(opts *cache.Options, scopes map[client.Object]Scope) {
+	if opts.ByObject == nil {
+		opts.ByObject = make(map[client.Object]cache.ByObject, len(scopes))
+	}
+	for obj, scope := range scopes {
+		opts.ByObject[obj] = scope.ByObject()
+	}
+}
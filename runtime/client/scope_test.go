@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestScope_ByObject(t *testing.T) {
+	tests := []struct {
+		description string
+		scope       Scope
+		labels      string
+		fields      string
+		namespaces  []string
+	}{
+		{
+			description: "empty scope",
+			scope:       Scope{},
+		},
+		{
+			description: "namespaces and labels",
+			scope: Scope{
+				Namespaces: []string{"tenant-a", "tenant-b"},
+				Labels:     labels.Set{"toolkit.fluxcd.io/tenant": "true"},
+			},
+			labels:     "toolkit.fluxcd.io/tenant=true",
+			namespaces: []string{"tenant-a", "tenant-b"},
+		},
+		{
+			description: "fields",
+			scope: Scope{
+				Fields: fields.Set{"type": string(corev1.SecretTypeDockerConfigJson)},
+			},
+			fields: "type=kubernetes.io/dockerconfigjson",
+		},
+		{
+			description: "name scope",
+			scope:       NameScope("git-auth", "tenant-a"),
+			fields:      "metadata.name=git-auth",
+			namespaces:  []string{"tenant-a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			b := tt.scope.ByObject()
+
+			if tt.labels == "" && b.Label != nil {
+				t.Errorf("expected no label selector, got %s", b.Label)
+			}
+			if tt.labels != "" && (b.Label == nil || b.Label.String() != tt.labels) {
+				t.Errorf("expected label selector %s, got %v", tt.labels, b.Label)
+			}
+			if tt.fields == "" && b.Field != nil {
+				t.Errorf("expected no field selector, got %s", b.Field)
+			}
+			if tt.fields != "" && (b.Field == nil || b.Field.String() != tt.fields) {
+				t.Errorf("expected field selector %s, got %v", tt.fields, b.Field)
+			}
+			if len(b.Namespaces) != len(tt.namespaces) {
+				t.Fatalf("expected namespaces %v, got %v", tt.namespaces, b.Namespaces)
+			}
+			for _, ns := range tt.namespaces {
+				if _, ok := b.Namespaces[ns]; !ok {
+					t.Errorf("expected namespace %s to be configured", ns)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyScopes(t *testing.T) {
+	secret := &corev1.Secret{}
+	configMap := &corev1.ConfigMap{}
+
+	opts := cache.Options{}
+	ApplyScopes(&opts, map[ctrlclient.Object]Scope{
+		secret:    NameScope("git-auth", "tenant-a"),
+		configMap: {Namespaces: []string{"tenant-a"}},
+	})
+
+	if len(opts.ByObject) != 2 {
+		t.Fatalf("expected 2 objects to be configured, got %d", len(opts.ByObject))
+	}
+	if got := opts.ByObject[secret].Field.String(); got != "metadata.name=git-auth" {
+		t.Errorf("unexpected field selector for secrets: %s", got)
+	}
+	if _, ok := opts.ByObject[configMap].Namespaces["tenant-a"]; !ok {
+		t.Errorf("expected config maps to be scoped to namespace tenant-a")
+	}
+}
@@ -82,6 +82,7 @@ func NewChecker(cli client.Client, condns *Conditions) *Checker {
 		check_FAIL0007,
 		check_FAIL0008,
 		check_FAIL0009,
+		check_FAIL0012,
 	}
 	return &Checker{
 		requireConditions: true,
@@ -24,6 +24,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
 )
 
 // Conditions contain the list of status conditions supported by a controller
@@ -35,6 +37,10 @@ type Conditions struct {
 	// PositivePolarity conditions are conditions that have normal-true nature.
 	// (Optional)
 	PositivePolarity []string `json:"positivePolarity"`
+	// Reasons are the reasons allowed for each condition type. The reasons
+	// of the condition types not listed are not checked.
+	// (Optional)
+	Reasons conditions.ReasonRegistry `json:"reasons,omitempty"`
 }
 
 // ParseConditions parses a given byte slice input into a Conditions object.
@@ -32,11 +32,16 @@ negativePolarity:
 positivePolarity:
 - aaa
 - bbb
+reasons:
+  Ready:
+  - Succeeded
+  - Failed
 `)
 	c, err := ParseConditions(input)
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(len(c.NegativePolarity)).To(Equal(2))
 	g.Expect(len(c.PositivePolarity)).To(Equal(2))
+	g.Expect(c.Reasons).To(HaveKeyWithValue("Ready", []string{"Succeeded", "Failed"}))
 }
 
 func TestHighestNegativePriorityCondition(t *testing.T) {
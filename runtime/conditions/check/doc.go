@@ -73,4 +73,10 @@ limitations under the License.
 // object.
 //
 //	checker.DisableFetch = true
+//
+// In integration tests, the checker can wait for the object to reach the
+// kstatus Current status before checking it. The failures include the history
+// of the statuses and conditions observed while waiting.
+//
+//	checker.WithT(g).WaitAndCheckErr(ctx, obj, check.DefaultWaitOptions())
 package check
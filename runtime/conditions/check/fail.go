@@ -198,3 +198,15 @@ func check_FAIL0011(ctx context.Context, obj conditions.Getter, condns *Conditio
 	}
 	return nil
 }
+
+// The status conditions' Reasons must be allowed for their condition type.
+func check_FAIL0012(ctx context.Context, obj conditions.Getter, condns *Conditions) error {
+	// Return if no reasons context is provided.
+	if len(condns.Reasons) == 0 {
+		return nil
+	}
+	if err := condns.Reasons.ValidateObject(obj); err != nil {
+		return fmt.Errorf("The status conditions' Reasons must be allowed for their condition type: %w", err)
+	}
+	return nil
+}
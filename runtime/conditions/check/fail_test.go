@@ -614,3 +614,64 @@ func Test_check_FAIL0011(t *testing.T) {
 		})
 	}
 }
+
+func Test_check_FAIL0012(t *testing.T) {
+	reasons := conditions.ReasonRegistry{
+		meta.ReadyCondition: {meta.SucceededReason, meta.FailedReason},
+	}
+
+	tests := []struct {
+		name          string
+		reasons       conditions.ReasonRegistry
+		addConditions func(obj conditions.Setter)
+		wantErr       string
+	}{
+		{
+			name: "no reasons context",
+			addConditions: func(obj conditions.Setter) {
+				conditions.MarkTrue(obj, meta.ReadyCondition, "Foo1", "Bar1")
+			},
+		},
+		{
+			name:    "allowed reason",
+			reasons: reasons,
+			addConditions: func(obj conditions.Setter) {
+				conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "Bar1")
+			},
+		},
+		{
+			name:    "condition type not in the reasons context",
+			reasons: reasons,
+			addConditions: func(obj conditions.Setter) {
+				conditions.MarkTrue(obj, meta.ReconcilingCondition, "Foo1", "Bar1")
+			},
+		},
+		{
+			name:    "misspelled reason",
+			reasons: reasons,
+			addConditions: func(obj conditions.Setter) {
+				conditions.MarkTrue(obj, meta.ReadyCondition, "Suceeded", "Bar1")
+			},
+			wantErr: "unknown reason 'Suceeded' for Ready condition, did you mean 'Succeeded'?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &testdata.Fake{}
+
+			if tt.addConditions != nil {
+				tt.addConditions(obj)
+			}
+
+			err := check_FAIL0012(context.TODO(), obj, &Conditions{Reasons: tt.reasons})
+			if tt.wantErr == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
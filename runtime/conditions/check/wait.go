@@ -0,0 +1,204 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/event"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/onsi/gomega"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/fluxcd/pkg/runtime/conditions"
+)
+
+// WaitOptions contains the options of a wait for an object to reach the
+// Current status.
+type WaitOptions struct {
+	// Interval is how often the object is polled.
+	Interval time.Duration
+	// Timeout is the duration after which the wait gives up on the object
+	// reaching the Current status.
+	Timeout time.Duration
+	// PollingOptions are the options of the kstatus status poller, e.g.
+	// custom status readers.
+	PollingOptions polling.Options
+}
+
+// DefaultWaitOptions returns the default wait options, polling every second
+// for up to 30 seconds.
+func DefaultWaitOptions() WaitOptions {
+	return WaitOptions{
+		Interval: time.Second,
+		Timeout:  30 * time.Second,
+	}
+}
+
+// StatusRecord is the kstatus status and the conditions of an object
+// observed while waiting for it to reach the Current status.
+type StatusRecord struct {
+	// Elapsed is the duration since the start of the wait.
+	Elapsed time.Duration
+	// Status is the kstatus status of the object.
+	Status status.Status
+	// Message is the kstatus message of the object.
+	Message string
+	// Conditions are the status conditions of the object.
+	Conditions []metav1.Condition
+}
+
+// StatusHistory is the list of the distinct statuses observed while waiting
+// for an object.
+type StatusHistory []StatusRecord
+
+// String returns the history with one line per status, followed by the
+// conditions of the object at the time.
+func (h StatusHistory) String() string {
+	var b strings.Builder
+	for _, r := range h {
+		fmt.Fprintf(&b, "[%s] %s", r.Elapsed.Round(time.Millisecond), r.Status)
+		if r.Message != "" {
+			fmt.Fprintf(&b, ": %s", r.Message)
+		}
+		b.WriteString("\n")
+		for _, c := range r.Conditions {
+			fmt.Fprintf(&b, "  %s=%s (%s): %s\n", c.Type, c.Status, c.Reason, c.Message)
+		}
+	}
+	return b.String()
+}
+
+// WaitAndCheck polls the object with kstatus until it reaches the Current
+// status, and then performs all the warn and fail checks on its latest
+// version. It fails if the object doesn't reach the Current status within
+// the timeout, or reaches the Failed status. The failures include the
+// history of the statuses and conditions observed while waiting.
+func (c Checker) WaitAndCheck(ctx context.Context, obj conditions.Getter, opts WaitOptions) (fail, warn error) {
+	history, err := c.waitForCurrent(ctx, obj, opts)
+	if err != nil {
+		return fmt.Errorf("%w\nStatus history:\n%s", err, history), nil
+	}
+	fail, warn = c.Check(ctx, obj)
+	if fail != nil {
+		fail = fmt.Errorf("%w\nStatus history:\n%s", fail, history)
+	}
+	return fail, warn
+}
+
+// WaitAndCheckErr performs WaitAndCheck and prints the results to stdout and
+// stderr, and exits. When the checker is configured with WithT, the failure
+// is asserted with gomega instead. This is to be used in tests and CLI.
+func (c Checker) WaitAndCheckErr(ctx context.Context, obj conditions.Getter, opts WaitOptions) {
+	if c.g != nil {
+		c.g.THelper()
+	}
+	fail, warn := c.WaitAndCheck(ctx, obj, opts)
+	if warn != nil {
+		fmt.Fprintf(c.Stdout, "[Check-WARN]: %v\nObserved conditions: %v", warn, obj.GetConditions())
+	}
+	if fail != nil {
+		if c.g == nil {
+			fmt.Fprintf(c.Stderr, "[Check-FAIL]: %v\nObserved conditions: %v", fail, obj.GetConditions())
+			os.Exit(1)
+		}
+		c.g.Expect(fail).ToNot(gomega.HaveOccurred(), fmt.Sprintf("[Check-FAIL]: %v\nObserved conditions: %v", fail, obj.GetConditions()))
+	}
+}
+
+// waitForCurrent polls the object until it reaches the Current status, and
+// returns the history of the statuses reported by kstatus, i.e. a record is
+// added when the status, its message or the generation of the object change.
+func (c Checker) waitForCurrent(ctx context.Context, obj conditions.Getter, opts WaitOptions) (StatusHistory, error) {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the GroupVersionKind of the object: %w", err)
+	}
+	id := object.ObjMetadata{
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		GroupKind: gvk.GroupKind(),
+	}
+	ref := fmt.Sprintf("%s/%s/%s", gvk.Kind, id.Namespace, id.Name)
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	poller := polling.NewStatusPoller(c.Client, c.RESTMapper(), opts.PollingOptions)
+	events := poller.Poll(ctx, object.ObjMetadataSet{id}, polling.PollOptions{PollInterval: opts.Interval})
+
+	var history StatusHistory
+	var last *event.ResourceStatus
+	var pollErr error
+	for e := range events {
+		switch e.Type {
+		case event.ErrorEvent:
+			pollErr = e.Error
+			cancel()
+		case event.ResourceUpdateEvent:
+			rs := e.Resource
+			// kstatus reports a DeadlineExceeded error for the resource when
+			// the wait times out, which is not an observed status.
+			if rs == nil || errors.Is(rs.Error, context.DeadlineExceeded) {
+				continue
+			}
+			last = rs
+			record := StatusRecord{
+				Elapsed: time.Since(start),
+				Status:  rs.Status,
+				Message: rs.Message,
+			}
+			if rs.Error != nil {
+				record.Message = rs.Error.Error()
+			}
+			if rs.Resource != nil {
+				record.Conditions = conditions.UnstructuredGetter(rs.Resource).GetConditions()
+			}
+			if n := len(history); n == 0 || history[n-1].Status != record.Status ||
+				history[n-1].Message != record.Message ||
+				!apiequality.Semantic.DeepEqual(history[n-1].Conditions, record.Conditions) {
+				history = append(history, record)
+			}
+			if rs.Status == status.CurrentStatus || rs.Status == status.FailedStatus {
+				cancel()
+			}
+		}
+	}
+
+	switch {
+	case pollErr != nil:
+		return history, fmt.Errorf("failed to poll %s: %w", ref, pollErr)
+	case last == nil:
+		return history, fmt.Errorf("timeout waiting for %s: can't determine status", ref)
+	case last.Status == status.FailedStatus:
+		return history, fmt.Errorf("%s status: '%s': %s", ref, last.Status, history[len(history)-1].Message)
+	case last.Status != status.CurrentStatus:
+		return history, fmt.Errorf("timeout waiting for %s to reach status '%s': last status '%s': %s",
+			ref, status.CurrentStatus, last.Status, history[len(history)-1].Message)
+	}
+	return history, nil
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package check
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/clusterreader"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/engine"
+	. "github.com/onsi/gomega"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+func TestWaitAndCheck(t *testing.T) {
+	tests := []struct {
+		name        string
+		update      func(obj *testdata.Fake)
+		wantFail    []string
+		wantNoFail  bool
+		wantWarn    bool
+		wantTimeout bool
+	}{
+		{
+			name: "reaches current status",
+			update: func(obj *testdata.Fake) {
+				obj.Status.ObservedGeneration = obj.Generation
+				conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "Applied")
+			},
+			wantNoFail: true,
+		},
+		{
+			name: "reaches current status with check failures",
+			update: func(obj *testdata.Fake) {
+				obj.Status.ObservedGeneration = obj.Generation
+				conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "Applied")
+				conditions.MarkTrue(obj, "TestCondition1", "Rsn", "Msg")
+			},
+			wantFail: []string{
+				"Negative polarity condition cannot be True when Ready condition is True",
+				"Status history:",
+				"Ready=True (Succeeded): Applied",
+			},
+			wantWarn: true,
+		},
+		{
+			name: "timeout",
+			update: func(obj *testdata.Fake) {
+				obj.Status.ObservedGeneration = obj.Generation
+				conditions.MarkFalse(obj, meta.ReadyCondition, meta.ProgressingReason, "Still progressing")
+			},
+			wantFail: []string{
+				"timeout waiting for Fake/TestNS/TestObj to reach status 'Current': last status 'InProgress'",
+				"Status history:",
+				"InProgress: Fake generation is 2, but latest observed generation is 1",
+				"Ready=False (Progressing): Still progressing",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			obj := &testdata.Fake{}
+			obj.Name = "TestObj"
+			obj.Namespace = "TestNS"
+			obj.Generation = 2
+			obj.Status.ObservedGeneration = 1
+
+			scheme := runtime.NewScheme()
+			g.Expect(testdata.AddFakeToScheme(scheme)).To(Succeed())
+			mapper := apimeta.NewDefaultRESTMapper([]schema.GroupVersion{testdata.FakeGroupVersion})
+			mapper.Add(testdata.FakeGroupVersion.WithKind("Fake"), apimeta.RESTScopeNamespace)
+			c := fakeclient.NewClientBuilder().
+				WithScheme(scheme).
+				WithRESTMapper(mapper).
+				WithObjects(obj).
+				WithStatusSubresource(obj).
+				Build()
+
+			// Update the status of the object while polling.
+			go func() {
+				time.Sleep(100 * time.Millisecond)
+				latest := &testdata.Fake{}
+				if err := c.Get(context.TODO(), client.ObjectKeyFromObject(obj), latest); err != nil {
+					return
+				}
+				tt.update(latest)
+				_ = c.Status().Update(context.TODO(), latest)
+			}()
+
+			conditions := &Conditions{NegativePolarity: []string{"TestCondition1"}}
+			checker := NewChecker(c, conditions)
+			fail, warn := checker.WaitAndCheck(context.TODO(), obj, WaitOptions{
+				Interval: 50 * time.Millisecond,
+				Timeout:  time.Second,
+				// The fake client doesn't support the field selectors of
+				// the default caching cluster reader.
+				PollingOptions: polling.Options{
+					ClusterReaderFactory: engine.ClusterReaderFactoryFunc(clusterreader.NewDirectClusterReader),
+				},
+			})
+			if tt.wantNoFail {
+				g.Expect(fail).ToNot(HaveOccurred())
+			}
+			for _, msg := range tt.wantFail {
+				g.Expect(fail).To(MatchError(ContainSubstring(msg)))
+			}
+			g.Expect(warn != nil).To(Equal(tt.wantWarn))
+		})
+	}
+}
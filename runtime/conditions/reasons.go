@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+)
+
+// maxSuggestionDistance is the maximum edit distance between an unknown
+// reason and a registered one for the latter to be suggested.
+const maxSuggestionDistance = 3
+
+// ReasonRegistry holds the reasons allowed for each condition type. The
+// reasons of the condition types which are not in the registry are not
+// validated.
+//
+// A registry can be declared with the reason constants of a controller, so
+// that a misspelled constant fails to compile:
+//
+//	var reasons = conditions.GenericReasons().Register(meta.ReadyCondition,
+//		sourcev1.GitOperationFailedReason,
+//		sourcev1.StorageOperationFailedReason,
+//	)
+//
+// It can also be parsed from YAML, e.g. as part of the check.Conditions of a
+// controller.
+type ReasonRegistry map[string][]string
+
+// GenericReasons returns a ReasonRegistry with the generic reasons of the
+// meta API package for the Ready, Stalled and Reconciling conditions.
+// Controllers register their specific reasons on top of it.
+func GenericReasons() ReasonRegistry {
+	generic := []string{
+		meta.SucceededReason,
+		meta.FailedReason,
+		meta.ProgressingReason,
+		meta.SuspendedReason,
+		meta.ProgressingWithRetryReason,
+		meta.DependencyNotReadyReason,
+		meta.InvalidPathReason,
+		meta.InvalidURLReason,
+		meta.InsecureConnectionsDisallowedReason,
+		meta.UnsupportedConnectionTypeReason,
+		meta.PruneFailedReason,
+		meta.ArtifactFailedReason,
+		meta.BuildFailedReason,
+		meta.HealthCheckFailedReason,
+		meta.ReconciliationSucceededReason,
+		meta.ReconciliationFailedReason,
+		meta.InvalidCELExpressionReason,
+	}
+	return ReasonRegistry{
+		meta.ReadyCondition:       slices.Clone(generic),
+		meta.StalledCondition:     slices.Clone(generic),
+		meta.ReconcilingCondition: {meta.ProgressingReason, meta.ProgressingWithRetryReason},
+	}
+}
+
+// Register adds the given reasons to the ones allowed for the condition
+// type, and returns the registry.
+func (r ReasonRegistry) Register(conditionType string, reasons ...string) ReasonRegistry {
+	for _, reason := range reasons {
+		if !slices.Contains(r[conditionType], reason) {
+			r[conditionType] = append(r[conditionType], reason)
+		}
+	}
+	return r
+}
+
+// Merge adds the reasons of the given registry to the ones of the registry,
+// and returns the registry.
+func (r ReasonRegistry) Merge(other ReasonRegistry) ReasonRegistry {
+	for conditionType, reasons := range other {
+		r.Register(conditionType, reasons...)
+	}
+	return r
+}
+
+// Validate returns an error if the reason of the given condition is not
+// allowed for its type. The error suggests the closest allowed reason, if
+// any, to help spotting misspelled reasons.
+func (r ReasonRegistry) Validate(condition metav1.Condition) error {
+	allowed, ok := r[condition.Type]
+	if !ok || slices.Contains(allowed, condition.Reason) {
+		return nil
+	}
+	err := fmt.Sprintf("unknown reason '%s' for %s condition", condition.Reason, condition.Type)
+	if suggestion := closestReason(condition.Reason, allowed); suggestion != "" {
+		err += fmt.Sprintf(", did you mean '%s'?", suggestion)
+	}
+	return errors.New(err)
+}
+
+// ValidateObject validates the reasons of all the conditions of the given
+// object, and returns the errors joined.
+func (r ReasonRegistry) ValidateObject(from Getter) error {
+	var errs []error
+	for _, c := range from.GetConditions() {
+		if err := r.Validate(c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closestReason returns the allowed reason the closest to the given one,
+// or an empty string if none is close enough.
+func closestReason(reason string, allowed []string) string {
+	var closest string
+	best := maxSuggestionDistance + 1
+	for _, a := range allowed {
+		if strings.EqualFold(a, reason) {
+			return a
+		}
+		if d := editDistance(strings.ToLower(a), strings.ToLower(reason)); d < best {
+			closest, best = a, d
+		}
+	}
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
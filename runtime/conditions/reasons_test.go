@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conditions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+func TestReasonRegistry_Validate(t *testing.T) {
+	reasons := GenericReasons().Register(meta.ReadyCondition, "GitOperationFailed")
+
+	tests := []struct {
+		name      string
+		condition metav1.Condition
+		wantErr   string
+	}{
+		{
+			name:      "generic reason",
+			condition: metav1.Condition{Type: meta.ReadyCondition, Reason: meta.SucceededReason},
+		},
+		{
+			name:      "registered reason",
+			condition: metav1.Condition{Type: meta.ReadyCondition, Reason: "GitOperationFailed"},
+		},
+		{
+			name:      "condition type not in the registry",
+			condition: metav1.Condition{Type: "ArtifactInStorage", Reason: "Anything"},
+		},
+		{
+			name:      "reason registered for another condition type",
+			condition: metav1.Condition{Type: meta.ReconcilingCondition, Reason: "GitOperationFailed"},
+			wantErr:   "unknown reason 'GitOperationFailed' for Reconciling condition",
+		},
+		{
+			name:      "misspelled reason",
+			condition: metav1.Condition{Type: meta.ReadyCondition, Reason: "GitOperationFaild"},
+			wantErr:   "unknown reason 'GitOperationFaild' for Ready condition, did you mean 'GitOperationFailed'?",
+		},
+		{
+			name:      "wrong case",
+			condition: metav1.Condition{Type: meta.ReconcilingCondition, Reason: "progressing"},
+			wantErr:   "unknown reason 'progressing' for Reconciling condition, did you mean 'Progressing'?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := reasons.Validate(tt.condition)
+			if tt.wantErr == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+			g.Expect(err).To(MatchError(tt.wantErr))
+		})
+	}
+}
+
+func TestReasonRegistry_ValidateObject(t *testing.T) {
+	g := NewWithT(t)
+
+	reasons := ReasonRegistry{}.
+		Register(meta.ReadyCondition, meta.SucceededReason, meta.FailedReason).
+		Merge(ReasonRegistry{meta.StalledCondition: {meta.InvalidURLReason}})
+	g.Expect(reasons).To(Equal(ReasonRegistry{
+		meta.ReadyCondition:   {meta.SucceededReason, meta.FailedReason},
+		meta.StalledCondition: {meta.InvalidURLReason},
+	}))
+
+	obj := &testdata.Fake{}
+	MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "ok")
+	g.Expect(reasons.ValidateObject(obj)).To(Succeed())
+
+	MarkFalse(obj, meta.ReadyCondition, "Faild", "failed")
+	MarkStalled(obj, "InvalidUrl", "invalid")
+	err := reasons.ValidateObject(obj)
+	g.Expect(err).To(MatchError(ContainSubstring("unknown reason 'Faild' for Ready condition, did you mean 'Failed'?")))
+	g.Expect(err).To(MatchError(ContainSubstring("unknown reason 'InvalidUrl' for Stalled condition, did you mean 'InvalidURL'?")))
+}
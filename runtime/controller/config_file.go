@@ -0,0 +1,369 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	flagConfigFile = "config"
+
+	// ConfigFileSchemaVersion is the URI of the JSON schema draft of the
+	// schema returned by ConfigFileSchema.
+	ConfigFileSchemaVersion = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// ConfigFileOptions defines the configurable options for loading the
+// configuration of a controller from a YAML file, instead of from a long
+// list of command line arguments.
+//
+// The file is a YAML object with a key per flag of the controller, e.g.:
+//
+//	concurrent: 10
+//	log-level: debug
+//	watch-all-namespaces: false
+//	feature-gates:
+//	  CacheSecretsAndConfigMaps: true
+//
+// A flag is set from, in order of precedence: the command line, its
+// environment variable, the configuration file, and its default value.
+type ConfigFileOptions struct {
+	// Path is the path of the YAML configuration file. When empty, the
+	// configuration is not loaded from a file.
+	Path string
+
+	// EnvPrefix is the prefix of the environment variables setting the
+	// flags, e.g. with the prefix "SOURCE_CONTROLLER" the flag
+	// "log-level" is set with the SOURCE_CONTROLLER_LOG_LEVEL environment
+	// variable. When empty, the flags are not set from the environment.
+	EnvPrefix string
+}
+
+// BindFlags will parse the given pflag.FlagSet for the controller and
+// set the ConfigFileOptions accordingly.
+func (o *ConfigFileOptions) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Path, flagConfigFile, "",
+		"The path of the YAML file holding the configuration of the controller. The command line arguments take precedence over the file.")
+}
+
+// Load sets the flags of the given pflag.FlagSet which have not been set on
+// the command line, from the environment variables and the configuration
+// file. It must be called after the pflag.FlagSet has been parsed. The
+// configuration file is validated against the JSON schema returned by
+// ConfigFileSchema before any flag is set.
+func (o *ConfigFileOptions) Load(fs *pflag.FlagSet) error {
+	if !fs.Parsed() {
+		return errors.New("the flags must be parsed before loading the configuration")
+	}
+
+	var fileValues map[string]any
+	if o.Path != "" {
+		data, err := os.ReadFile(o.Path)
+		if err != nil {
+			return fmt.Errorf("failed to read config file: %w", err)
+		}
+		fileValues, err = parseConfigFile(fs, data)
+		if err != nil {
+			return fmt.Errorf("invalid config file '%s': %w", o.Path, err)
+		}
+	}
+
+	var errs []error
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Changed || isConfigFileFlag(f) {
+			return
+		}
+		if o.EnvPrefix != "" {
+			name := envVarName(o.EnvPrefix, f.Name)
+			if value, ok := os.LookupEnv(name); ok {
+				if err := fs.Set(f.Name, value); err != nil {
+					errs = append(errs, fmt.Errorf("invalid value of environment variable %s: %w", name, err))
+				}
+				return
+			}
+		}
+		if value, ok := fileValues[f.Name]; ok {
+			if err := setFlag(fs, f, value); err != nil {
+				errs = append(errs, fmt.Errorf("invalid value of '%s' in config file: %w", f.Name, err))
+			}
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// ConfigFileSchema returns the JSON schema of the configuration file of the
+// flags of the given pflag.FlagSet, to be published with the controller for
+// validating the configuration files, e.g. in CI.
+func ConfigFileSchema(fs *pflag.FlagSet) ([]byte, error) {
+	schema := configFileSchema(fs)
+	schema.Schema = ConfigFileSchemaVersion
+	return json.MarshalIndent(schema, "", "  ")
+}
+
+// jsonSchema is the subset of the JSON schema describing the configuration
+// file.
+type jsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Type                 string                 `json:"type"`
+	Description          string                 `json:"description,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties any                    `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+}
+
+// configFileSchema returns the schema of the configuration file of the
+// flags of the given pflag.FlagSet.
+func configFileSchema(fs *pflag.FlagSet) *jsonSchema {
+	schema := &jsonSchema{
+		Type:                 "object",
+		Properties:           make(map[string]*jsonSchema),
+		AdditionalProperties: false,
+	}
+	fs.VisitAll(func(f *pflag.Flag) {
+		if isConfigFileFlag(f) {
+			return
+		}
+		s := flagSchema(f.Value.Type())
+		s.Description = f.Usage
+		schema.Properties[f.Name] = s
+	})
+	return schema
+}
+
+// flagSchema returns the schema of the values of the given pflag.Value
+// type.
+func flagSchema(valueType string) *jsonSchema {
+	switch valueType {
+	case "bool":
+		return &jsonSchema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "int64",
+		"uint", "uint8", "uint16", "uint32", "uint64", "count":
+		return &jsonSchema{Type: "integer"}
+	case "float32", "float64":
+		return &jsonSchema{Type: "number"}
+	case "stringToString", "mapStringString":
+		return &jsonSchema{Type: "object", AdditionalProperties: &jsonSchema{Type: "string"}}
+	case "stringToInt", "stringToInt64":
+		return &jsonSchema{Type: "object", AdditionalProperties: &jsonSchema{Type: "integer"}}
+	case "mapStringBool":
+		return &jsonSchema{Type: "object", AdditionalProperties: &jsonSchema{Type: "boolean"}}
+	}
+	if item, ok := strings.CutSuffix(valueType, "Slice"); ok {
+		return &jsonSchema{Type: "array", Items: flagSchema(item)}
+	}
+	if item, ok := strings.CutSuffix(valueType, "Array"); ok {
+		return &jsonSchema{Type: "array", Items: flagSchema(item)}
+	}
+	return &jsonSchema{Type: "string"}
+}
+
+// validate returns an error if the value does not match the schema. The
+// numbers of the value must be decoded as json.Number.
+func (s *jsonSchema) validate(path string, value any) error {
+	switch s.Type {
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeError(path, s.Type, value)
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return typeError(path, s.Type, value)
+		}
+		if _, err := n.Int64(); err != nil {
+			return typeError(path, s.Type, value)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return typeError(path, s.Type, value)
+		}
+	case "string":
+		if _, ok := value.(string); !ok {
+			return typeError(path, s.Type, value)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return typeError(path, s.Type, value)
+		}
+		var errs []error
+		for i, item := range items {
+			errs = append(errs, s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item))
+		}
+		return errors.Join(errs...)
+	case "object":
+		m, ok := value.(map[string]any)
+		if !ok {
+			return typeError(path, s.Type, value)
+		}
+		var errs []error
+		for _, key := range sortedKeys(m) {
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			if p, ok := s.Properties[key]; ok {
+				errs = append(errs, p.validate(keyPath, m[key]))
+				continue
+			}
+			switch additional := s.AdditionalProperties.(type) {
+			case *jsonSchema:
+				errs = append(errs, additional.validate(keyPath, m[key]))
+			case bool:
+				if !additional {
+					errs = append(errs, fmt.Errorf("%s: unknown field", keyPath))
+				}
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func typeError(path, want string, value any) error {
+	if path == "" {
+		path = "<root>"
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, want, jsonType(value))
+}
+
+// jsonType returns the JSON type of the decoded value.
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// parseConfigFile decodes the YAML configuration file and validates it
+// against the schema of the flags.
+func parseConfigFile(fs *pflag.FlagSet, data []byte) (map[string]any, error) {
+	jsonData, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	dec := json.NewDecoder(bytes.NewReader(jsonData))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	if value == nil {
+		// Empty file.
+		return nil, nil
+	}
+	if err := configFileSchema(fs).validate("", value); err != nil {
+		return nil, err
+	}
+	return value.(map[string]any), nil
+}
+
+// setFlag sets the flag from the value of the configuration file, validated
+// against the schema of the flag.
+func setFlag(fs *pflag.FlagSet, f *pflag.Flag, value any) error {
+	switch v := value.(type) {
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			items = append(items, formatScalar(item))
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			if err := sv.Replace(items); err != nil {
+				return err
+			}
+			f.Changed = true
+			return nil
+		}
+		for _, item := range items {
+			if err := fs.Set(f.Name, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		pairs := make([]string, 0, len(v))
+		for _, key := range sortedKeys(v) {
+			pairs = append(pairs, key+"="+formatScalar(v[key]))
+		}
+		return fs.Set(f.Name, strings.Join(pairs, ","))
+	default:
+		return fs.Set(f.Name, formatScalar(v))
+	}
+}
+
+// formatScalar returns the flag value of the scalar of the configuration
+// file.
+func formatScalar(value any) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// envVarName returns the name of the environment variable of the flag,
+// e.g. PREFIX_LOG_LEVEL for the flag "log-level".
+func envVarName(prefix, name string) string {
+	name = strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+	return strings.TrimSuffix(prefix, "_") + "_" + name
+}
+
+// isConfigFileFlag returns true for the flag of the path of the
+// configuration file, which cannot be set from the file itself.
+func isConfigFileFlag(f *pflag.Flag) bool {
+	return f.Name == flagConfigFile
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,239 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/spf13/pflag"
+	cliflag "k8s.io/component-base/cli/flag"
+)
+
+type testConfig struct {
+	ConfigFile   ConfigFileOptions
+	Concurrent   int
+	LogLevel     string
+	Interval     time.Duration
+	Ratio        float64
+	WatchAll     bool
+	Namespaces   []string
+	FeatureGates map[string]bool
+}
+
+func newTestFlagSet(c *testConfig) *pflag.FlagSet {
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c.ConfigFile.BindFlags(fs)
+	fs.IntVar(&c.Concurrent, "concurrent", 4, "The number of concurrent reconciles.")
+	fs.StringVar(&c.LogLevel, "log-level", "info", "The log level.")
+	fs.DurationVar(&c.Interval, "interval", time.Minute, "The interval.")
+	fs.Float64Var(&c.Ratio, "ratio", 0.5, "The ratio.")
+	fs.BoolVar(&c.WatchAll, "watch-all-namespaces", true, "Watch all the namespaces.")
+	fs.StringSliceVar(&c.Namespaces, "namespaces", []string{"default"}, "The namespaces.")
+	fs.Var(cliflag.NewMapStringBool(&c.FeatureGates), "feature-gates", "The feature gates.")
+	return fs
+}
+
+func writeConfigFile(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigFileOptions_Load(t *testing.T) {
+	g := NewWithT(t)
+
+	path := writeConfigFile(t, `
+concurrent: 10
+log-level: debug
+interval: 5m
+ratio: 0.9
+watch-all-namespaces: false
+namespaces:
+  - flux-system
+  - apps
+feature-gates:
+  OOMWatch: true
+  CacheSecretsAndConfigMaps: false
+`)
+
+	var c testConfig
+	fs := newTestFlagSet(&c)
+	g.Expect(fs.Parse([]string{"--config=" + path})).To(Succeed())
+	g.Expect(c.ConfigFile.Load(fs)).To(Succeed())
+
+	g.Expect(c.Concurrent).To(Equal(10))
+	g.Expect(c.LogLevel).To(Equal("debug"))
+	g.Expect(c.Interval).To(Equal(5 * time.Minute))
+	g.Expect(c.Ratio).To(Equal(0.9))
+	g.Expect(c.WatchAll).To(BeFalse())
+	g.Expect(c.Namespaces).To(Equal([]string{"flux-system", "apps"}))
+	g.Expect(c.FeatureGates).To(Equal(map[string]bool{
+		"OOMWatch":                  true,
+		"CacheSecretsAndConfigMaps": false,
+	}))
+	g.Expect(fs.Changed("concurrent")).To(BeTrue())
+}
+
+func TestConfigFileOptions_Load_precedence(t *testing.T) {
+	g := NewWithT(t)
+
+	path := writeConfigFile(t, `
+concurrent: 10
+log-level: debug
+interval: 5m
+`)
+	t.Setenv("TEST_CONTROLLER_LOG_LEVEL", "error")
+	t.Setenv("TEST_CONTROLLER_CONCURRENT", "20")
+
+	c := testConfig{ConfigFile: ConfigFileOptions{EnvPrefix: "TEST_CONTROLLER"}}
+	fs := newTestFlagSet(&c)
+	g.Expect(fs.Parse([]string{"--config", path, "--concurrent=30"})).To(Succeed())
+	g.Expect(c.ConfigFile.Load(fs)).To(Succeed())
+
+	// Command line over environment over file over default.
+	g.Expect(c.Concurrent).To(Equal(30))
+	g.Expect(c.LogLevel).To(Equal("error"))
+	g.Expect(c.Interval).To(Equal(5 * time.Minute))
+	g.Expect(c.Ratio).To(Equal(0.5))
+}
+
+func TestConfigFileOptions_Load_noFile(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv("TEST_CONTROLLER_NAMESPACES", "a,b")
+
+	c := testConfig{ConfigFile: ConfigFileOptions{EnvPrefix: "TEST_CONTROLLER"}}
+	fs := newTestFlagSet(&c)
+	g.Expect(fs.Parse(nil)).To(Succeed())
+	g.Expect(c.ConfigFile.Load(fs)).To(Succeed())
+
+	g.Expect(c.Namespaces).To(Equal([]string{"a", "b"}))
+	g.Expect(c.Concurrent).To(Equal(4))
+}
+
+func TestConfigFileOptions_Load_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		env     map[string]string
+		wantErr []string
+	}{
+		{
+			name:    "unknown field",
+			data:    "concurrency: 10\n",
+			wantErr: []string{"concurrency: unknown field"},
+		},
+		{
+			name:    "invalid type",
+			data:    "concurrent: \"10\"\nwatch-all-namespaces: yes please\n",
+			wantErr: []string{"concurrent: expected integer, got string", "watch-all-namespaces: expected boolean, got string"},
+		},
+		{
+			name:    "invalid integer",
+			data:    "concurrent: 1.5\n",
+			wantErr: []string{"concurrent: expected integer, got number"},
+		},
+		{
+			name:    "invalid item",
+			data:    "namespaces: [a, 1]\nfeature-gates:\n  OOMWatch: enabled\n",
+			wantErr: []string{"namespaces[1]: expected string, got integer", "feature-gates.OOMWatch: expected boolean, got string"},
+		},
+		{
+			name:    "not an object",
+			data:    "- concurrent\n",
+			wantErr: []string{"<root>: expected object, got array"},
+		},
+		{
+			name:    "config flag",
+			data:    "config: other.yaml\n",
+			wantErr: []string{"config: unknown field"},
+		},
+		{
+			name:    "invalid value",
+			data:    "interval: forever\n",
+			wantErr: []string{"invalid value of 'interval' in config file"},
+		},
+		{
+			name:    "invalid environment variable",
+			data:    "concurrent: 10\n",
+			env:     map[string]string{"TEST_CONTROLLER_CONCURRENT": "many"},
+			wantErr: []string{"invalid value of environment variable TEST_CONTROLLER_CONCURRENT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			path := writeConfigFile(t, tt.data)
+
+			c := testConfig{ConfigFile: ConfigFileOptions{EnvPrefix: "TEST_CONTROLLER"}}
+			fs := newTestFlagSet(&c)
+			g.Expect(fs.Parse([]string{"--config=" + path})).To(Succeed())
+
+			err := c.ConfigFile.Load(fs)
+			g.Expect(err).To(HaveOccurred())
+			for _, want := range tt.wantErr {
+				g.Expect(err.Error()).To(ContainSubstring(want))
+			}
+		})
+	}
+}
+
+func TestConfigFileOptions_Load_notParsed(t *testing.T) {
+	g := NewWithT(t)
+
+	var c testConfig
+	fs := newTestFlagSet(&c)
+	g.Expect(c.ConfigFile.Load(fs)).To(MatchError(ContainSubstring("must be parsed")))
+}
+
+func TestConfigFileSchema(t *testing.T) {
+	g := NewWithT(t)
+
+	var c testConfig
+	data, err := ConfigFileSchema(newTestFlagSet(&c))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var schema map[string]any
+	g.Expect(json.Unmarshal(data, &schema)).To(Succeed())
+	g.Expect(schema).To(HaveKeyWithValue("$schema", ConfigFileSchemaVersion))
+	g.Expect(schema).To(HaveKeyWithValue("type", "object"))
+	g.Expect(schema).To(HaveKeyWithValue("additionalProperties", false))
+
+	properties := schema["properties"].(map[string]any)
+	g.Expect(properties).ToNot(HaveKey("config"))
+	g.Expect(properties["concurrent"]).To(Equal(map[string]any{
+		"type":        "integer",
+		"description": "The number of concurrent reconciles.",
+	}))
+	g.Expect(properties["interval"]).To(HaveKeyWithValue("type", "string"))
+	g.Expect(properties["ratio"]).To(HaveKeyWithValue("type", "number"))
+	g.Expect(properties["watch-all-namespaces"]).To(HaveKeyWithValue("type", "boolean"))
+	g.Expect(properties["namespaces"]).To(HaveKeyWithValue("items", map[string]any{"type": "string"}))
+	g.Expect(properties["feature-gates"]).To(HaveKeyWithValue("additionalProperties", map[string]any{"type": "boolean"}))
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+	uberzap "go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	cliflag "k8s.io/component-base/cli/flag"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/fluxcd/pkg/runtime/features"
+	"github.com/fluxcd/pkg/runtime/logger"
+)
+
+const (
+	flagRuntimeConfigMap = "runtime-config-map"
+
+	// RuntimeConfigLogLevelKey is the key of the runtime configuration
+	// ConfigMap holding the log level, e.g. "debug".
+	RuntimeConfigLogLevelKey = "log-level"
+	// RuntimeConfigFeatureGatesKey is the key of the runtime configuration
+	// ConfigMap holding a comma separated list of key=value pairs defining
+	// the state of the features, e.g. "CacheSecretsAndConfigMaps=true".
+	RuntimeConfigFeatureGatesKey = "feature-gates"
+)
+
+// RuntimeConfigOptions defines the configurable options for reloading the
+// configuration of a controller at runtime.
+type RuntimeConfigOptions struct {
+	// ConfigMapName is the name of the ConfigMap in the runtime namespace
+	// holding the runtime configuration. When empty, the configuration is
+	// not reloaded at runtime.
+	ConfigMapName string
+}
+
+// BindFlags will parse the given pflag.FlagSet for the controller and
+// set the RuntimeConfigOptions accordingly.
+func (o *RuntimeConfigOptions) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.ConfigMapName, flagRuntimeConfigMap, "",
+		"The name of the ConfigMap in the runtime namespace used to change the log level and feature gates at runtime.")
+}
+
+// RuntimeConfigStatus holds the currently effective runtime configuration.
+type RuntimeConfigStatus struct {
+	// LogLevel is the effective log level.
+	LogLevel string
+	// FeatureGates is the effective state of the features that can be
+	// changed at runtime.
+	FeatureGates map[string]bool
+	// ObservedResourceVersion is the resource version of the ConfigMap
+	// last observed, empty if it does not exist.
+	ObservedResourceVersion string
+	// LastAppliedTime is the time at which the configuration was last
+	// applied.
+	LastAppliedTime time.Time
+	// Error is the validation error of the last observed ConfigMap, in
+	// which case the previous configuration is kept in effect.
+	Error error
+}
+
+// RuntimeConfigWatcher reconciles a ConfigMap holding the log level and the
+// state of selected feature gates, and applies them without restarting the
+// controller. The configuration is validated as a whole, and only applied if
+// it is entirely valid. When a key is removed from the ConfigMap, or the
+// ConfigMap is deleted, the defaults are restored.
+//
+// The manager cache should be restricted to the ConfigMap, e.g. with
+// client.NameScope, to avoid caching all the ConfigMaps of the cluster.
+type RuntimeConfigWatcher struct {
+	// Reader is used to get the ConfigMap.
+	Reader client.Reader
+	// ConfigMap is the namespace and name of the ConfigMap.
+	ConfigMap types.NamespacedName
+	// Level is the atomic level of the controller logger, set with
+	// logger.Options.Level.
+	Level *uberzap.AtomicLevel
+	// DefaultLogLevel is the log level in effect when none is specified in
+	// the ConfigMap, usually the value of the --log-level flag.
+	DefaultLogLevel string
+	// DynamicFeatures are the features that can be changed at runtime. Their
+	// state at the time of the first reconciliation is used as default.
+	DynamicFeatures []string
+
+	mu              sync.Mutex
+	defaultFeatures map[string]bool
+	status          RuntimeConfigStatus
+}
+
+// SetupWithManager sets up the watcher with the given manager, to reconcile
+// the ConfigMap on changes.
+func (w *RuntimeConfigWatcher) SetupWithManager(mgr ctrl.Manager) error {
+	if w.Reader == nil {
+		w.Reader = mgr.GetClient()
+	}
+	isConfigMap := predicate.NewPredicateFuncs(func(obj client.Object) bool {
+		return obj.GetNamespace() == w.ConfigMap.Namespace && obj.GetName() == w.ConfigMap.Name
+	})
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("runtime-config").
+		For(&corev1.ConfigMap{}, builder.WithPredicates(isConfigMap)).
+		Complete(w)
+}
+
+// Reconcile applies the configuration of the ConfigMap. Validation errors
+// are reported in the status, and not returned, as retrying cannot fix them.
+func (w *RuntimeConfigWatcher) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	cm := &corev1.ConfigMap{}
+	if err := w.Reader.Get(ctx, w.ConfigMap, cm); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		cm = nil
+	}
+
+	status, err := w.Apply(cm)
+	if err != nil {
+		log.Error(err, "invalid runtime configuration, keeping the previous configuration",
+			"configMap", w.ConfigMap.String())
+		return ctrl.Result{}, nil
+	}
+	log.Info("runtime configuration applied",
+		"logLevel", status.LogLevel, "featureGates", status.FeatureGates)
+	return ctrl.Result{}, nil
+}
+
+// Apply validates and applies the configuration of the given ConfigMap, and
+// returns the effective configuration. A nil ConfigMap restores the
+// defaults.
+func (w *RuntimeConfigWatcher) Apply(cm *corev1.ConfigMap) (RuntimeConfigStatus, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.defaultFeatures == nil {
+		all, err := features.All()
+		if err != nil && len(w.DynamicFeatures) > 0 {
+			return w.status, err
+		}
+		w.defaultFeatures = make(map[string]bool, len(w.DynamicFeatures))
+		for _, f := range w.DynamicFeatures {
+			enabled, ok := all[f]
+			if !ok {
+				return w.status, fmt.Errorf("feature-gate '%s' not supported", f)
+			}
+			w.defaultFeatures[f] = enabled
+		}
+	}
+
+	var data map[string]string
+	resourceVersion := ""
+	if cm != nil {
+		data = cm.Data
+		resourceVersion = cm.ResourceVersion
+	}
+
+	level, featureGates, err := w.parse(data)
+	w.status.ObservedResourceVersion = resourceVersion
+	w.status.Error = err
+	if err != nil {
+		return w.status, err
+	}
+
+	if w.Level != nil {
+		if err := logger.SetLevel(w.Level, level); err != nil {
+			w.status.Error = err
+			return w.status, err
+		}
+	}
+	for f, enabled := range featureGates {
+		if err := features.Set(f, enabled); err != nil {
+			w.status.Error = err
+			return w.status, err
+		}
+	}
+
+	w.status.LogLevel = level
+	w.status.FeatureGates = featureGates
+	w.status.LastAppliedTime = time.Now()
+	return w.status, nil
+}
+
+// Status returns the currently effective runtime configuration.
+func (w *RuntimeConfigWatcher) Status() RuntimeConfigStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	status := w.status
+	status.FeatureGates = maps.Clone(w.status.FeatureGates)
+	return status
+}
+
+// parse validates the given ConfigMap data, and returns the log level and
+// the state of all the dynamic features, with the defaults of the omitted
+// values.
+func (w *RuntimeConfigWatcher) parse(data map[string]string) (string, map[string]bool, error) {
+	level := w.DefaultLogLevel
+	if v, ok := data[RuntimeConfigLogLevelKey]; ok {
+		level = v
+	}
+	if _, err := logger.ParseLevel(level); err != nil {
+		return "", nil, err
+	}
+
+	featureGates := maps.Clone(w.defaultFeatures)
+	if v, ok := data[RuntimeConfigFeatureGatesKey]; ok {
+		gates := map[string]bool{}
+		if err := cliflag.NewMapStringBool(&gates).Set(v); err != nil {
+			return "", nil, fmt.Errorf("invalid %s: %w", RuntimeConfigFeatureGatesKey, err)
+		}
+		for f, enabled := range gates {
+			if !slices.Contains(w.DynamicFeatures, f) {
+				return "", nil, fmt.Errorf("feature-gate '%s' cannot be changed at runtime", f)
+			}
+			featureGates[f] = enabled
+		}
+	}
+	return level, featureGates, nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	uberzap "go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/runtime/features"
+)
+
+func TestRuntimeConfigWatcher(t *testing.T) {
+	g := NewWithT(t)
+
+	fg := features.FeatureGates{}
+	g.Expect(fg.SupportedFeatures(map[string]bool{
+		"DetailedEvents":              false,
+		"ObjectLevelWorkloadIdentity": false,
+	})).To(Succeed())
+
+	level := uberzap.NewAtomicLevelAt(zapcore.InfoLevel)
+	key := types.NamespacedName{Namespace: "flux-system", Name: "runtime-config"}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Data: map[string]string{
+			RuntimeConfigLogLevelKey:     "debug",
+			RuntimeConfigFeatureGatesKey: "DetailedEvents=true",
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(cm).Build()
+
+	w := &RuntimeConfigWatcher{
+		Reader:          c,
+		ConfigMap:       key,
+		Level:           &level,
+		DefaultLogLevel: "info",
+		DynamicFeatures: []string{"DetailedEvents"},
+	}
+
+	_, err := w.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(level.Level()).To(Equal(zapcore.DebugLevel))
+	enabled, err := features.Enabled("DetailedEvents")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(enabled).To(BeTrue())
+
+	status := w.Status()
+	g.Expect(status.Error).ToNot(HaveOccurred())
+	g.Expect(status.LogLevel).To(Equal("debug"))
+	g.Expect(status.FeatureGates).To(Equal(map[string]bool{"DetailedEvents": true}))
+	g.Expect(status.ObservedResourceVersion).ToNot(BeEmpty())
+
+	// an invalid configuration is not applied
+	for _, data := range []map[string]string{
+		{RuntimeConfigLogLevelKey: "verbose"},
+		{RuntimeConfigFeatureGatesKey: "DetailedEvents=maybe"},
+		{RuntimeConfigFeatureGatesKey: "ObjectLevelWorkloadIdentity=true"},
+		{RuntimeConfigLogLevelKey: "error", RuntimeConfigFeatureGatesKey: "Unknown=true"},
+	} {
+		cm.Data = data
+		g.Expect(c.Update(context.TODO(), cm)).To(Succeed())
+		_, err = w.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+		g.Expect(err).ToNot(HaveOccurred())
+
+		status = w.Status()
+		g.Expect(status.Error).To(HaveOccurred())
+		g.Expect(status.LogLevel).To(Equal("debug"))
+		g.Expect(level.Level()).To(Equal(zapcore.DebugLevel))
+	}
+	enabled, err = features.Enabled("ObjectLevelWorkloadIdentity")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(enabled).To(BeFalse())
+
+	// the defaults are restored when the ConfigMap is deleted
+	g.Expect(c.Delete(context.TODO(), cm)).To(Succeed())
+	_, err = w.Reconcile(context.TODO(), ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(level.Level()).To(Equal(zapcore.InfoLevel))
+	enabled, err = features.Enabled("DetailedEvents")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(enabled).To(BeFalse())
+
+	status = w.Status()
+	g.Expect(status.Error).ToNot(HaveOccurred())
+	g.Expect(status.ObservedResourceVersion).To(BeEmpty())
+}
@@ -15,5 +15,5 @@ limitations under the License.
 */
 
 // Package dependency contains an utility for sorting a set of Kubernetes resource objects that implement the
-// Dependent interface.
+// Dependent interface, and for reporting which of their dependencies are missing or not ready.
 package dependency
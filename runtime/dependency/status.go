@@ -0,0 +1,205 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/object"
+)
+
+// Status is the readiness status of a dependency.
+type Status string
+
+const (
+	// StatusReady indicates the dependency is ready at its current generation.
+	StatusReady Status = "Ready"
+	// StatusNotReady indicates the dependency exists but is not ready, or its
+	// current generation has not been reconciled yet.
+	StatusNotReady Status = "NotReady"
+	// StatusMissing indicates the dependency does not exist.
+	StatusMissing Status = "Missing"
+)
+
+// DependencyStatus holds the readiness status of a single dependency.
+type DependencyStatus struct {
+	// Reference is the reference to the dependency, with the namespace
+	// defaulted to the one of the dependent object.
+	Reference meta.NamespacedObjectReference
+
+	// Status is the readiness status of the dependency.
+	Status Status
+
+	// Reason is the reason of the Ready condition of the dependency, if any.
+	Reason string
+
+	// Message describes why the dependency is not ready, empty if it is.
+	Message string
+}
+
+// String returns a human-readable description of the dependency status.
+func (s DependencyStatus) String() string {
+	ref := namespacedNameObjRef(s.Reference)
+	switch s.Status {
+	case StatusReady:
+		return fmt.Sprintf("dependency '%s' is ready", ref)
+	case StatusMissing:
+		return fmt.Sprintf("dependency '%s' not found", ref)
+	default:
+		if s.Reason != "" {
+			return fmt.Sprintf("dependency '%s' is not ready (%s): %s", ref, s.Reason, s.Message)
+		}
+		return fmt.Sprintf("dependency '%s' is not ready: %s", ref, s.Message)
+	}
+}
+
+// Result holds the readiness status of the dependencies of an object, in the
+// order they are listed in the object.
+type Result struct {
+	Dependencies []DependencyStatus
+}
+
+// Ready returns true if all the dependencies are ready.
+func (r *Result) Ready() bool {
+	return len(r.Blocking()) == 0
+}
+
+// Blocking returns the status of the dependencies which are missing or not
+// ready.
+func (r *Result) Blocking() []DependencyStatus {
+	var blocking []DependencyStatus
+	for _, s := range r.Dependencies {
+		if s.Status != StatusReady {
+			blocking = append(blocking, s)
+		}
+	}
+	return blocking
+}
+
+// Message returns a message listing the blocking dependencies, suitable for
+// a condition or an event. It is empty if all the dependencies are ready.
+func (r *Result) Message() string {
+	blocking := r.Blocking()
+	if len(blocking) == 0 {
+		return ""
+	}
+	msgs := make([]string, 0, len(blocking))
+	for _, s := range blocking {
+		msgs = append(msgs, s.String())
+	}
+	return fmt.Sprintf("%d of %d dependencies not ready: %s",
+		len(blocking), len(r.Dependencies), strings.Join(msgs, "; "))
+}
+
+// Check returns the readiness status of the dependencies of the given object.
+// The dependencies are fetched with the reader into the objects returned by
+// newObject, which must be of the same kind as the dependencies.
+//
+// A dependency is ready if its Ready condition is true, and its current
+// generation has been observed by its controller. An error is returned if a
+// dependency cannot be fetched for reasons other than not being found.
+func Check(ctx context.Context, reader client.Reader, obj Dependent, newObject func() conditions.Getter) (*Result, error) {
+	deps := obj.GetDependsOn()
+	result := &Result{
+		Dependencies: make([]DependencyStatus, 0, len(deps)),
+	}
+	for _, ref := range deps {
+		if ref.Namespace == "" {
+			ref.Namespace = obj.GetNamespace()
+		}
+		status, err := checkDependency(ctx, reader, ref, newObject())
+		if err != nil {
+			return nil, err
+		}
+		result.Dependencies = append(result.Dependencies, status)
+	}
+	return result, nil
+}
+
+// Wait polls the readiness status of the dependencies of the given object at
+// the given interval, until they are all ready or the timeout expires. It
+// returns the last observed status, along with an error if the dependencies
+// did not become ready in time.
+func Wait(ctx context.Context, reader client.Reader, obj Dependent, newObject func() conditions.Getter,
+	interval, timeout time.Duration) (*Result, error) {
+	var result *Result
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(ctx context.Context) (bool, error) {
+		r, err := Check(ctx, reader, obj, newObject)
+		if err != nil {
+			return false, err
+		}
+		result = r
+		return r.Ready(), nil
+	})
+	if err != nil {
+		if result != nil && wait.Interrupted(err) {
+			return result, errors.New(result.Message())
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+func checkDependency(ctx context.Context, reader client.Reader, ref meta.NamespacedObjectReference,
+	dep conditions.Getter) (DependencyStatus, error) {
+	status := DependencyStatus{Reference: ref}
+
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if err := reader.Get(ctx, key, dep); err != nil {
+		if apierrors.IsNotFound(err) {
+			status.Status = StatusMissing
+			status.Message = "not found"
+			return status, nil
+		}
+		return status, fmt.Errorf("unable to get dependency '%s': %w", namespacedNameObjRef(ref), err)
+	}
+
+	status.Reason = conditions.GetReason(dep, meta.ReadyCondition)
+
+	observedGeneration, err := object.GetStatusObservedGeneration(dep)
+	if err != nil {
+		observedGeneration = conditions.GetObservedGeneration(dep, meta.ReadyCondition)
+	}
+	switch {
+	case observedGeneration != dep.GetGeneration():
+		status.Status = StatusNotReady
+		status.Message = fmt.Sprintf("generation %d has not been reconciled yet", dep.GetGeneration())
+	case !conditions.Has(dep, meta.ReadyCondition):
+		status.Status = StatusNotReady
+		status.Message = "Ready condition not found"
+	case !conditions.IsReady(dep):
+		status.Status = StatusNotReady
+		status.Message = conditions.GetMessage(dep, meta.ReadyCondition)
+		if status.Message == "" {
+			status.Message = fmt.Sprintf("Ready condition is %s", conditions.Get(dep, meta.ReadyCondition).Status)
+		}
+	default:
+		status.Status = StatusReady
+	}
+	return status, nil
+}
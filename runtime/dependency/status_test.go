@@ -0,0 +1,177 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dependency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+func newFake(name string, generation, observedGeneration int64, ready *metav1.Condition) *testdata.Fake {
+	obj := &testdata.Fake{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       name,
+			Generation: generation,
+		},
+	}
+	obj.Status.ObservedGeneration = observedGeneration
+	if ready != nil {
+		obj.Status.Conditions = []metav1.Condition{*ready}
+	}
+	return obj
+}
+
+func newFakeGetter() conditions.Getter {
+	return &testdata.Fake{}
+}
+
+func TestCheck(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(testdata.AddFakeToScheme(scheme)).To(Succeed())
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newFake("ready", 2, 2, &metav1.Condition{
+			Type:   meta.ReadyCondition,
+			Status: metav1.ConditionTrue,
+			Reason: meta.SucceededReason,
+		}),
+		newFake("failed", 1, 1, &metav1.Condition{
+			Type:    meta.ReadyCondition,
+			Status:  metav1.ConditionFalse,
+			Reason:  meta.FailedReason,
+			Message: "build failed",
+		}),
+		newFake("stale", 3, 2, &metav1.Condition{
+			Type:   meta.ReadyCondition,
+			Status: metav1.ConditionTrue,
+			Reason: meta.SucceededReason,
+		}),
+		newFake("unknown", 1, 1, nil),
+	).Build()
+
+	obj := &MockDependent{
+		Node: corev1.Node{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}},
+		DependsOn: []meta.NamespacedObjectReference{
+			{Name: "ready"},
+			{Name: "failed"},
+			{Name: "stale"},
+			{Name: "unknown"},
+			{Namespace: "other", Name: "ready"},
+		},
+	}
+
+	result, err := Check(context.TODO(), c, obj, newFakeGetter)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Ready()).To(BeFalse())
+	g.Expect(result.Dependencies).To(Equal([]DependencyStatus{
+		{
+			Reference: meta.NamespacedObjectReference{Namespace: "default", Name: "ready"},
+			Status:    StatusReady,
+			Reason:    meta.SucceededReason,
+		},
+		{
+			Reference: meta.NamespacedObjectReference{Namespace: "default", Name: "failed"},
+			Status:    StatusNotReady,
+			Reason:    meta.FailedReason,
+			Message:   "build failed",
+		},
+		{
+			Reference: meta.NamespacedObjectReference{Namespace: "default", Name: "stale"},
+			Status:    StatusNotReady,
+			Reason:    meta.SucceededReason,
+			Message:   "generation 3 has not been reconciled yet",
+		},
+		{
+			Reference: meta.NamespacedObjectReference{Namespace: "default", Name: "unknown"},
+			Status:    StatusNotReady,
+			Message:   "Ready condition not found",
+		},
+		{
+			Reference: meta.NamespacedObjectReference{Namespace: "other", Name: "ready"},
+			Status:    StatusMissing,
+			Message:   "not found",
+		},
+	}))
+	g.Expect(result.Blocking()).To(HaveLen(4))
+	g.Expect(result.Message()).To(Equal("4 of 5 dependencies not ready: " +
+		"dependency 'default/failed' is not ready (Failed): build failed; " +
+		"dependency 'default/stale' is not ready (Succeeded): generation 3 has not been reconciled yet; " +
+		"dependency 'default/unknown' is not ready: Ready condition not found; " +
+		"dependency 'other/ready' not found"))
+}
+
+func TestCheck_NoDependencies(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &MockDependent{
+		Node: corev1.Node{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}},
+	}
+	result, err := Check(context.TODO(), fake.NewClientBuilder().Build(), obj, newFakeGetter)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Ready()).To(BeTrue())
+	g.Expect(result.Message()).To(BeEmpty())
+}
+
+func TestWait(t *testing.T) {
+	g := NewWithT(t)
+
+	scheme := runtime.NewScheme()
+	g.Expect(testdata.AddFakeToScheme(scheme)).To(Succeed())
+
+	dep := newFake("backend", 1, 1, &metav1.Condition{
+		Type:   meta.ReadyCondition,
+		Status: metav1.ConditionFalse,
+		Reason: meta.ProgressingReason,
+	})
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(dep).Build()
+
+	obj := &MockDependent{
+		Node:      corev1.Node{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"}},
+		DependsOn: []meta.NamespacedObjectReference{{Name: "backend"}},
+	}
+
+	result, err := Wait(context.TODO(), c, obj, newFakeGetter, 10*time.Millisecond, 50*time.Millisecond)
+	g.Expect(err).To(MatchError("1 of 1 dependencies not ready: dependency 'default/backend' is not ready (Progressing): Ready condition is False"))
+	g.Expect(result.Ready()).To(BeFalse())
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		obj := &testdata.Fake{}
+		_ = c.Get(context.TODO(), client.ObjectKeyFromObject(dep), obj)
+		conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "ready")
+		_ = c.Update(context.TODO(), obj)
+	}()
+
+	result, err = Wait(context.TODO(), c, obj, newFakeGetter, 10*time.Millisecond, time.Second)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.Ready()).To(BeTrue())
+}
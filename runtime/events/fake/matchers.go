@@ -0,0 +1,189 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+)
+
+// EventFilter reports whether an Event matches a criterion.
+type EventFilter struct {
+	description string
+	match       func(Event) bool
+}
+
+// String returns the description of the filter.
+func (f EventFilter) String() string {
+	return f.description
+}
+
+// WithReason matches events with the given reason.
+func WithReason(reason string) EventFilter {
+	return EventFilter{
+		description: fmt.Sprintf("reason=%s", reason),
+		match:       func(e Event) bool { return e.Reason == reason },
+	}
+}
+
+// WithType matches events with the given Kubernetes event type.
+func WithType(eventtype string) EventFilter {
+	return EventFilter{
+		description: fmt.Sprintf("type=%s", eventtype),
+		match:       func(e Event) bool { return e.Type == eventtype },
+	}
+}
+
+// WithSeverity matches events with the given severity, e.g.
+// eventv1.EventSeverityError.
+func WithSeverity(severity string) EventFilter {
+	return EventFilter{
+		description: fmt.Sprintf("severity=%s", severity),
+		match:       func(e Event) bool { return e.Severity == severity },
+	}
+}
+
+// WithMessage matches events of which the message contains the given
+// substring.
+func WithMessage(substr string) EventFilter {
+	return EventFilter{
+		description: fmt.Sprintf("message~=%q", substr),
+		match:       func(e Event) bool { return strings.Contains(e.Message, substr) },
+	}
+}
+
+// WithAnnotation matches events with an annotation of the given key and
+// value.
+func WithAnnotation(key, value string) EventFilter {
+	return EventFilter{
+		description: fmt.Sprintf("annotation[%s]=%s", key, value),
+		match: func(e Event) bool {
+			v, ok := e.Annotations[key]
+			return ok && v == value
+		},
+	}
+}
+
+// WithAnnotationKey matches events with an annotation of the given key,
+// regardless of its value.
+func WithAnnotationKey(key string) EventFilter {
+	return EventFilter{
+		description: fmt.Sprintf("annotation[%s]", key),
+		match: func(e Event) bool {
+			_, ok := e.Annotations[key]
+			return ok
+		},
+	}
+}
+
+func filterEvents(events []Event, filters []EventFilter) []Event {
+	var result []Event
+	for _, e := range events {
+		matched := true
+		for _, f := range filters {
+			if !f.match(e) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// HaveEvent returns a matcher which succeeds if at least one event matches
+// all the given filters. The actual value must be a *Recorder or an []Event.
+func HaveEvent(filters ...EventFilter) types.GomegaMatcher {
+	return &EventMatcher{Filters: filters, Count: -1}
+}
+
+// HaveEventCount returns a matcher which succeeds if exactly count events
+// match all the given filters. The actual value must be a *Recorder or an
+// []Event.
+func HaveEventCount(count int, filters ...EventFilter) types.GomegaMatcher {
+	return &EventMatcher{Filters: filters, Count: count}
+}
+
+// EventMatcher matches the events captured by a Recorder.
+type EventMatcher struct {
+	// Filters which an event must all match.
+	Filters []EventFilter
+	// Count is the exact number of events which must match. A negative
+	// value requires at least one event to match.
+	Count int
+}
+
+func (matcher *EventMatcher) Match(actual interface{}) (success bool, err error) {
+	events, err := toEvents(actual)
+	if err != nil {
+		return false, err
+	}
+	matched := len(filterEvents(events, matcher.Filters))
+	if matcher.Count < 0 {
+		return matched > 0, nil
+	}
+	return matched == matcher.Count, nil
+}
+
+func (matcher *EventMatcher) FailureMessage(actual interface{}) (message string) {
+	return format.Message(describeEvents(actual), "to contain "+matcher.describe())
+}
+
+func (matcher *EventMatcher) NegatedFailureMessage(actual interface{}) (message string) {
+	return format.Message(describeEvents(actual), "not to contain "+matcher.describe())
+}
+
+func (matcher *EventMatcher) describe() string {
+	filters := make([]string, len(matcher.Filters))
+	for i, f := range matcher.Filters {
+		filters[i] = f.String()
+	}
+	if matcher.Count < 0 {
+		return fmt.Sprintf("an event matching [%s]", strings.Join(filters, ", "))
+	}
+	return fmt.Sprintf("%d event(s) matching [%s]", matcher.Count, strings.Join(filters, ", "))
+}
+
+func toEvents(actual interface{}) ([]Event, error) {
+	switch v := actual.(type) {
+	case *Recorder:
+		return v.Events(), nil
+	case []Event:
+		return v, nil
+	default:
+		return nil, fmt.Errorf("expected a *Recorder or []Event, got %T", actual)
+	}
+}
+
+// describeEvents returns the events as strings, for readable failure
+// messages.
+func describeEvents(actual interface{}) interface{} {
+	events, err := toEvents(actual)
+	if err != nil {
+		return actual
+	}
+	result := make([]string, len(events))
+	for i, e := range events {
+		result[i] = e.String()
+	}
+	return result
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+// maxMessageLength is the maximum length of the message of an event
+// accepted by the notification-controller.
+const maxMessageLength = 39000
+
+// Receiver is an HTTP server implementing the event webhook contract of the
+// notification-controller, for testing the delivery of the events posted by
+// an events.Recorder end-to-end:
+//
+//   - the events are POSTed as JSON, and must match the eventv1.Event schema,
+//     otherwise the request is rejected with 400 Bad Request
+//   - the requests must carry the bearer token set with WithReceiverToken,
+//     if any, otherwise they are rejected with 401 Unauthorized
+//   - the events repeated within the interval set with WithReceiverRateLimit
+//     are rejected with 429 Too Many Requests
+//   - the accepted events are answered with 202 Accepted
+//
+// The status codes of the next requests can be scripted with
+// RespondWith, e.g. to test the retries of the Recorder.
+type Receiver struct {
+	server    *httptest.Server
+	token     string
+	rateLimit time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	requests  int
+	responses []int
+	events    []eventv1.Event
+	errs      []error
+	lastSeen  map[string]time.Time
+}
+
+// ReceiverOption configures a Receiver.
+type ReceiverOption func(*Receiver)
+
+// WithReceiverToken requires the requests to carry the given token in an
+// "Authorization: Bearer" header.
+func WithReceiverToken(token string) ReceiverOption {
+	return func(r *Receiver) {
+		r.token = token
+	}
+}
+
+// WithReceiverRateLimit rejects the events with the same involved object,
+// severity, reason and message as an event accepted within the given
+// interval, like the rate limit of the notification-controller.
+func WithReceiverRateLimit(interval time.Duration) ReceiverOption {
+	return func(r *Receiver) {
+		r.rateLimit = interval
+	}
+}
+
+// NewReceiver starts and returns a new Receiver. The caller must call Close
+// when finished, e.g. with t.Cleanup.
+func NewReceiver(opts ...ReceiverOption) *Receiver {
+	r := &Receiver{
+		now:      time.Now,
+		lastSeen: make(map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.server = httptest.NewServer(http.HandlerFunc(r.serveHTTP))
+	return r
+}
+
+// URL returns the webhook address of the Receiver.
+func (r *Receiver) URL() string {
+	return r.server.URL
+}
+
+// Close shuts down the Receiver.
+func (r *Receiver) Close() {
+	r.server.Close()
+}
+
+// RespondWith makes the Receiver answer its next requests with the given
+// status codes, in order, before handling the requests normally again. The
+// events of these requests are not recorded.
+func (r *Receiver) RespondWith(statusCodes ...int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.responses = append(r.responses, statusCodes...)
+}
+
+// Events returns the events accepted by the Receiver, in order.
+func (r *Receiver) Events() []eventv1.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]eventv1.Event(nil), r.events...)
+}
+
+// Requests returns the number of requests received by the Receiver.
+func (r *Receiver) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests
+}
+
+// Errors returns the errors of the requests rejected by the Receiver
+// because they are unauthorized or invalid, in order.
+func (r *Receiver) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+func (r *Receiver) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests++
+	if len(r.responses) > 0 {
+		status := r.responses[0]
+		r.responses = r.responses[1:]
+		w.WriteHeader(status)
+		return
+	}
+
+	if r.token != "" {
+		token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(r.token)) != 1 {
+			r.reject(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+			return
+		}
+	}
+
+	if req.Method != http.MethodPost {
+		r.reject(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", req.Method))
+		return
+	}
+
+	event, err := decodeEvent(req)
+	if err != nil {
+		r.reject(w, http.StatusBadRequest, err)
+		return
+	}
+
+	if r.rateLimit > 0 {
+		key := eventKey(event)
+		now := r.now()
+		if last, ok := r.lastSeen[key]; ok && now.Sub(last) < r.rateLimit {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		r.lastSeen[key] = now
+	}
+
+	r.events = append(r.events, *event)
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// reject records the error of the request and answers it with the status
+// code.
+func (r *Receiver) reject(w http.ResponseWriter, status int, err error) {
+	r.errs = append(r.errs, err)
+	http.Error(w, err.Error(), status)
+}
+
+// decodeEvent decodes the event of the request and validates it against the
+// schema of the notification-controller.
+func decodeEvent(req *http.Request) (*eventv1.Event, error) {
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		return nil, fmt.Errorf("invalid content type '%s'", req.Header.Get("Content-Type"))
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the request body: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	var event eventv1.Event
+	if err := decoder.Decode(&event); err != nil {
+		return nil, fmt.Errorf("failed to decode the event: %w", err)
+	}
+
+	var errs []error
+	obj := event.InvolvedObject
+	if obj.Kind == "" || obj.Name == "" || obj.Namespace == "" {
+		errs = append(errs, errors.New("involvedObject kind, name and namespace are required"))
+	}
+	switch event.Severity {
+	case eventv1.EventSeverityInfo, eventv1.EventSeverityError:
+	case eventv1.EventSeverityTrace:
+		errs = append(errs, errors.New("trace events must not be posted to the webhook"))
+	default:
+		errs = append(errs, fmt.Errorf("invalid severity '%s'", event.Severity))
+	}
+	if event.Timestamp.IsZero() {
+		errs = append(errs, errors.New("timestamp is required"))
+	}
+	if event.Message == "" {
+		errs = append(errs, errors.New("message is required"))
+	} else if len(event.Message) > maxMessageLength {
+		errs = append(errs, fmt.Errorf("message must be at most %d characters", maxMessageLength))
+	}
+	if event.Reason == "" {
+		errs = append(errs, errors.New("reason is required"))
+	}
+	if event.ReportingController == "" {
+		errs = append(errs, errors.New("reportingController is required"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, fmt.Errorf("invalid event: %w", err)
+	}
+	return &event, nil
+}
+
+// eventKey returns the rate limit key of the event.
+func eventKey(event *eventv1.Event) string {
+	obj := event.InvolvedObject
+	return strings.Join([]string{obj.Kind, obj.Namespace, obj.Name,
+		event.Severity, event.Reason, event.Message}, "/")
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kuberecorder "k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+	"github.com/fluxcd/pkg/runtime/events"
+)
+
+// bearerTransport adds a bearer token to the requests.
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestRecorder(t *testing.T, webhook string) *events.Recorder {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	recorder, err := events.NewRecorderForScheme(scheme, kuberecorder.NewFakeRecorder(10),
+		logr.New(log.NullLogSink{}), webhook, "test-controller")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Client.RetryMax = 2
+	recorder.Client.RetryWaitMin = time.Millisecond
+	recorder.Client.RetryWaitMax = time.Millisecond
+	return recorder
+}
+
+func newTestObject() *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "webapp",
+			Namespace: "gitops-system",
+			Annotations: map[string]string{
+				eventv1.Group + "/revision": "main@sha1:abc",
+			},
+		},
+	}
+}
+
+func TestReceiver(t *testing.T) {
+	g := NewWithT(t)
+
+	receiver := NewReceiver()
+	t.Cleanup(receiver.Close)
+	recorder := newTestRecorder(t, receiver.URL())
+
+	recorder.AnnotatedEventf(newTestObject(), map[string]string{"summary": "prod"},
+		corev1.EventTypeWarning, "ReconciliationFailed", "failed to reconcile %s", "webapp")
+
+	// An accepted event is posted once.
+	g.Expect(receiver.Requests()).To(Equal(1))
+	g.Expect(receiver.Errors()).To(BeEmpty())
+	received := receiver.Events()
+	g.Expect(received).To(HaveLen(1))
+	g.Expect(received[0].InvolvedObject.Kind).To(Equal("ConfigMap"))
+	g.Expect(received[0].InvolvedObject.Name).To(Equal("webapp"))
+	g.Expect(received[0].Severity).To(Equal(eventv1.EventSeverityError))
+	g.Expect(received[0].Reason).To(Equal("ReconciliationFailed"))
+	g.Expect(received[0].Message).To(Equal("failed to reconcile webapp"))
+	g.Expect(received[0].ReportingController).To(Equal("test-controller"))
+	g.Expect(received[0].Metadata).To(Equal(map[string]string{
+		"summary":                   "prod",
+		eventv1.Group + "/revision": "main@sha1:abc",
+	}))
+}
+
+func TestReceiver_RespondWith(t *testing.T) {
+	g := NewWithT(t)
+
+	receiver := NewReceiver()
+	t.Cleanup(receiver.Close)
+	recorder := newTestRecorder(t, receiver.URL())
+
+	// The server errors are retried until the event is accepted.
+	receiver.RespondWith(http.StatusInternalServerError, http.StatusBadGateway)
+	recorder.Event(newTestObject(), corev1.EventTypeNormal, "Progressing", "reconciling")
+
+	g.Expect(receiver.Requests()).To(Equal(3))
+	g.Expect(receiver.Events()).To(HaveLen(1))
+}
+
+func TestReceiver_RateLimit(t *testing.T) {
+	g := NewWithT(t)
+
+	receiver := NewReceiver(WithReceiverRateLimit(time.Minute))
+	t.Cleanup(receiver.Close)
+	recorder := newTestRecorder(t, receiver.URL())
+
+	// The rate limited events are not retried.
+	for range 3 {
+		recorder.Event(newTestObject(), corev1.EventTypeNormal, "Progressing", "reconciling")
+	}
+	recorder.Event(newTestObject(), corev1.EventTypeNormal, "Succeeded", "reconciled")
+
+	g.Expect(receiver.Requests()).To(Equal(4))
+	g.Expect(receiver.Events()).To(HaveLen(2))
+}
+
+func TestReceiver_Token(t *testing.T) {
+	g := NewWithT(t)
+
+	receiver := NewReceiver(WithReceiverToken("s3cr3t"))
+	t.Cleanup(receiver.Close)
+	recorder := newTestRecorder(t, receiver.URL())
+
+	recorder.Event(newTestObject(), corev1.EventTypeNormal, "Progressing", "reconciling")
+	g.Expect(receiver.Events()).To(BeEmpty())
+	g.Expect(receiver.Errors()).ToNot(BeEmpty())
+	g.Expect(receiver.Errors()[0]).To(MatchError("missing or invalid bearer token"))
+
+	recorder.Client.HTTPClient.Transport = bearerTransport{token: "s3cr3t"}
+	recorder.Event(newTestObject(), corev1.EventTypeNormal, "Progressing", "reconciling")
+	g.Expect(receiver.Events()).To(HaveLen(1))
+}
+
+func TestReceiver_invalidEvent(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     string
+	}{
+		{
+			name:        "invalid content type",
+			contentType: "text/plain",
+			body:        `{}`,
+			wantErr:     "invalid content type 'text/plain'",
+		},
+		{
+			name:        "unknown field",
+			contentType: "application/json",
+			body:        `{"involvedObject": {"kind": "ConfigMap"}, "level": "info"}`,
+			wantErr:     `unknown field "level"`,
+		},
+		{
+			name:        "missing fields",
+			contentType: "application/json; charset=utf-8",
+			body:        `{"involvedObject": {"kind": "ConfigMap", "name": "webapp"}, "severity": "trace"}`,
+			wantErr: "invalid event: involvedObject kind, name and namespace are required\n" +
+				"trace events must not be posted to the webhook\n" +
+				"timestamp is required\n" +
+				"message is required\n" +
+				"reason is required\n" +
+				"reportingController is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			receiver := NewReceiver()
+			t.Cleanup(receiver.Close)
+
+			resp, err := http.Post(receiver.URL(), tt.contentType, strings.NewReader(tt.body))
+			g.Expect(err).ToNot(HaveOccurred())
+			resp.Body.Close()
+			g.Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			g.Expect(receiver.Events()).To(BeEmpty())
+			g.Expect(receiver.Errors()).To(HaveLen(1))
+			g.Expect(receiver.Errors()[0]).To(MatchError(ContainSubstring(tt.wantErr)))
+		})
+	}
+}
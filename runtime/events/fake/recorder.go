@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake provides a test double for the events.Recorder, which captures
+// the recorded events in memory and offers Gomega matchers to assert on them.
+package fake
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kuberecorder "k8s.io/client-go/tools/record"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+// Event is an event captured by the Recorder.
+type Event struct {
+	// Object is the object the event was recorded for.
+	Object runtime.Object
+	// Type is the Kubernetes event type, e.g. corev1.EventTypeNormal.
+	Type string
+	// Severity is the severity the event would have been posted with to the
+	// notification-controller, e.g. eventv1.EventSeverityInfo.
+	Severity string
+	// Reason is the reason of the event.
+	Reason string
+	// Message is the formatted message of the event.
+	Message string
+	// Annotations are the annotations of the event, including the event
+	// annotations of the object.
+	Annotations map[string]string
+}
+
+// String returns a human-readable representation of the event.
+func (e Event) String() string {
+	return fmt.Sprintf("%s %s: %s %v", e.Severity, e.Reason, e.Message, e.Annotations)
+}
+
+// Recorder implements the kuberecorder.EventRecorder interface and captures
+// all events in memory, instead of posting them to the Kubernetes API and the
+// notification-controller. It is safe for concurrent use.
+//
+// Use it in place of the events.Recorder in the reconciler under test:
+//
+//	recorder := fake.NewRecorder()
+//	r := &MyTypeReconciler{
+//		EventRecorder: recorder,
+//	}
+//	...
+//	g.Expect(recorder).To(fake.HaveEvent(
+//		fake.WithReason("ReconciliationSucceeded"),
+//		fake.WithSeverity(eventv1.EventSeverityInfo),
+//	))
+type Recorder struct {
+	events []Event
+	mu     sync.Mutex
+}
+
+var _ kuberecorder.EventRecorder = &Recorder{}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Event records an event with the given message.
+func (r *Recorder) Event(object runtime.Object, eventtype, reason, message string) {
+	r.record(object, nil, eventtype, reason, message)
+}
+
+// Eventf records an event with the message formatted according to the
+// format specifier.
+func (r *Recorder) Eventf(object runtime.Object, eventtype, reason, messageFmt string, args ...interface{}) {
+	r.record(object, nil, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// AnnotatedEventf records an event with the given annotations and the
+// message formatted according to the format specifier. Like the
+// events.Recorder, annotations of the object prefixed with the event API
+// group are added to the annotations of the event.
+func (r *Recorder) AnnotatedEventf(object runtime.Object, annotations map[string]string,
+	eventtype, reason, messageFmt string, args ...interface{}) {
+	r.record(object, annotations, eventtype, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *Recorder) record(object runtime.Object, inputAnnotations map[string]string, eventtype, reason, message string) {
+	annotations := maps.Clone(inputAnnotations)
+	if annotatedObject, ok := object.(interface{ GetAnnotations() map[string]string }); ok {
+		for k, v := range annotatedObject.GetAnnotations() {
+			if strings.HasPrefix(k, eventv1.Group+"/") {
+				if annotations == nil {
+					annotations = map[string]string{}
+				}
+				annotations[k] = v
+			}
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, Event{
+		Object:      object,
+		Type:        eventtype,
+		Severity:    eventTypeToSeverity(eventtype),
+		Reason:      reason,
+		Message:     message,
+		Annotations: annotations,
+	})
+}
+
+// Events returns a copy of the events recorded so far, in the order they
+// were recorded.
+func (r *Recorder) Events() []Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := make([]Event, len(r.events))
+	copy(events, r.events)
+	return events
+}
+
+// Find returns the recorded events matching all the given filters.
+func (r *Recorder) Find(filters ...EventFilter) []Event {
+	return filterEvents(r.Events(), filters)
+}
+
+// Reset removes all the recorded events.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
+
+// eventTypeToSeverity converts a Kubernetes event type to the severity
+// used by the events.Recorder.
+func eventTypeToSeverity(eventType string) string {
+	switch eventType {
+	case corev1.EventTypeWarning:
+		return eventv1.EventSeverityError
+	case eventv1.EventTypeTrace:
+		return eventv1.EventSeverityTrace
+	default:
+		return eventv1.EventSeverityInfo
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+func TestRecorder(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "default",
+			Annotations: map[string]string{
+				eventv1.Group + "/token": "abc",
+				"unrelated":              "value",
+			},
+		},
+	}
+
+	recorder := NewRecorder()
+	recorder.Event(obj, corev1.EventTypeNormal, "Progressing", "reconciliation in progress")
+	recorder.Eventf(obj, corev1.EventTypeWarning, "BuildFailed", "build failed: %s", "timeout")
+	recorder.AnnotatedEventf(obj, map[string]string{eventv1.MetaRevisionKey: "main@sha1:abc"},
+		eventv1.EventTypeTrace, "Progressing", "applied %d objects", 3)
+
+	events := recorder.Events()
+	g.Expect(events).To(HaveLen(3))
+	g.Expect(events[0].Object).To(Equal(obj))
+	g.Expect(events[0].Severity).To(Equal(eventv1.EventSeverityInfo))
+	g.Expect(events[0].Annotations).To(Equal(map[string]string{eventv1.Group + "/token": "abc"}))
+	g.Expect(events[1].Severity).To(Equal(eventv1.EventSeverityError))
+	g.Expect(events[1].Message).To(Equal("build failed: timeout"))
+	g.Expect(events[2].Severity).To(Equal(eventv1.EventSeverityTrace))
+	g.Expect(events[2].Annotations).To(HaveKeyWithValue(eventv1.MetaRevisionKey, "main@sha1:abc"))
+
+	g.Expect(recorder.Find(WithReason("Progressing"))).To(HaveLen(2))
+	g.Expect(recorder.Find(WithReason("Progressing"), WithType(corev1.EventTypeNormal))).To(HaveLen(1))
+
+	recorder.Reset()
+	g.Expect(recorder.Events()).To(BeEmpty())
+}
+
+func TestHaveEvent(t *testing.T) {
+	g := NewWithT(t)
+
+	recorder := NewRecorder()
+	obj := &corev1.ConfigMap{}
+	recorder.AnnotatedEventf(obj, map[string]string{eventv1.MetaRevisionKey: "v1"},
+		corev1.EventTypeNormal, "ReconciliationSucceeded", "applied revision %s", "v1")
+	recorder.Event(obj, corev1.EventTypeWarning, "HealthCheckFailed", "timeout waiting for rollout")
+
+	g.Expect(recorder).To(HaveEvent(WithReason("ReconciliationSucceeded")))
+	g.Expect(recorder).To(HaveEvent(
+		WithSeverity(eventv1.EventSeverityInfo),
+		WithAnnotation(eventv1.MetaRevisionKey, "v1"),
+		WithMessage("applied revision"),
+	))
+	g.Expect(recorder).ToNot(HaveEvent(WithSeverity(eventv1.EventSeverityError), WithAnnotationKey(eventv1.MetaRevisionKey)))
+	g.Expect(recorder).ToNot(HaveEvent(WithAnnotation(eventv1.MetaRevisionKey, "v2")))
+	g.Expect(recorder.Events()).To(HaveEventCount(2))
+	g.Expect(recorder).To(HaveEventCount(1, WithType(corev1.EventTypeWarning)))
+
+	matcher := HaveEvent(WithReason("Missing"))
+	success, err := matcher.Match(recorder)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(success).To(BeFalse())
+	g.Expect(matcher.FailureMessage(recorder)).To(ContainSubstring("an event matching [reason=Missing]"))
+	g.Expect(matcher.FailureMessage(recorder)).To(ContainSubstring("error HealthCheckFailed"))
+
+	_, err = matcher.Match("not a recorder")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/pflag"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+const (
+	flagEventsMinSeverity    = "events-min-severity"
+	flagEventsExcludeReasons = "events-exclude-reasons"
+)
+
+// severityLevels orders the severities of the events forwarded to the
+// webhook.
+var severityLevels = map[string]int{
+	eventv1.EventSeverityInfo:  0,
+	eventv1.EventSeverityError: 1,
+}
+
+// FilterOptions contains the configuration of the events forwarded to the
+// webhook by the Recorder. The events are always recorded as Kubernetes
+// events.
+//
+// The struct can be used in the main.go file of your controller by binding
+// it to the main flag set, and then utilizing the configured options later:
+//
+//	func main() {
+//		var (
+//			// other controller specific configuration variables
+//			eventsFilterOptions events.FilterOptions
+//		)
+//
+//		// Bind the options to the main flag set, and parse it
+//		eventsFilterOptions.BindFlags(flag.CommandLine)
+//		flag.Parse()
+//
+//		filter, err := events.NewFilter(eventsFilterOptions)
+//		if err != nil {
+//			// handle error
+//		}
+//		metrics.Registry.MustRegister(filter.Collectors()...)
+//		eventRecorder.Filter = filter
+//	}
+type FilterOptions struct {
+	// MinSeverity is the minimum severity of the events forwarded to the
+	// webhook, either "info" or "error". Defaults to "info".
+	MinSeverity string
+
+	// ExcludeReasons are the reasons of the events which are not forwarded
+	// to the webhook, in the format 'reason' for all the severities, or
+	// 'severity:reason' for a single severity, e.g. 'info:Progressing'.
+	ExcludeReasons []string
+}
+
+// BindFlags will parse the given pflag.FlagSet and load the filter options
+// accordingly.
+func (o *FilterOptions) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.MinSeverity, flagEventsMinSeverity, eventv1.EventSeverityInfo,
+		"The minimum severity of the events forwarded to the events webhook, one of: info, error.")
+	fs.StringSliceVar(&o.ExcludeReasons, flagEventsExcludeReasons, nil,
+		"A comma separated list of event reasons which are not forwarded to the events webhook, "+
+			"in the format 'reason' or 'severity:reason', e.g. 'info:Progressing'.")
+}
+
+// Filter decides which events are forwarded to the webhook, and counts the
+// suppressed events.
+//
+// Use NewFilter to initialise it from FilterOptions.
+type Filter struct {
+	minLevel       int
+	excludeReasons map[string]map[string]bool
+	suppressed     *prometheus.CounterVec
+}
+
+// NewFilter returns a Filter for the given options, or an error if the
+// options are invalid.
+func NewFilter(opts FilterOptions) (*Filter, error) {
+	f := &Filter{
+		excludeReasons: make(map[string]map[string]bool),
+		suppressed: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotk_suppressed_events_total",
+				Help: "Total number of events not forwarded to the events webhook partitioned by severity and reason.",
+			},
+			[]string{"severity", "reason"},
+		),
+	}
+
+	if opts.MinSeverity != "" {
+		level, ok := severityLevels[opts.MinSeverity]
+		if !ok {
+			return nil, fmt.Errorf("invalid --%s value '%s', must be one of: info, error",
+				flagEventsMinSeverity, opts.MinSeverity)
+		}
+		f.minLevel = level
+	}
+
+	for _, r := range opts.ExcludeReasons {
+		severity, reason, ok := strings.Cut(strings.TrimSpace(r), ":")
+		if !ok {
+			severity, reason = "", severity
+		} else if _, valid := severityLevels[severity]; !valid {
+			return nil, fmt.Errorf("invalid --%s value '%s', unknown severity '%s'",
+				flagEventsExcludeReasons, r, severity)
+		}
+		if reason == "" {
+			return nil, fmt.Errorf("invalid --%s value '%s', reason must not be empty",
+				flagEventsExcludeReasons, r)
+		}
+		if f.excludeReasons[reason] == nil {
+			f.excludeReasons[reason] = make(map[string]bool)
+		}
+		f.excludeReasons[reason][severity] = true
+	}
+
+	return f, nil
+}
+
+// Allow returns true if an event with the given severity and reason must be
+// forwarded to the webhook. Otherwise, it counts the event as suppressed.
+func (f *Filter) Allow(severity, reason string) bool {
+	allow := severityLevels[severity] >= f.minLevel
+	if severities, ok := f.excludeReasons[reason]; ok && (severities[""] || severities[severity]) {
+		allow = false
+	}
+	if !allow {
+		f.suppressed.WithLabelValues(severity, reason).Inc()
+	}
+	return allow
+}
+
+// Collectors returns a slice of Prometheus collectors, which can be used to
+// register them in a metrics registry.
+func (f *Filter) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		f.suppressed,
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/pflag"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	kuberecorder "k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	eventv1 "github.com/fluxcd/pkg/apis/event/v1beta1"
+)
+
+func TestFilterOptions_BindFlags(t *testing.T) {
+	g := NewWithT(t)
+
+	var opts FilterOptions
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.BindFlags(f)
+	g.Expect(f.Parse([]string{
+		"--events-min-severity=error",
+		"--events-exclude-reasons=info:Progressing,DependencyNotReady",
+	})).To(Succeed())
+	g.Expect(opts.MinSeverity).To(Equal(eventv1.EventSeverityError))
+	g.Expect(opts.ExcludeReasons).To(Equal([]string{"info:Progressing", "DependencyNotReady"}))
+}
+
+func TestFilter_Allow(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     FilterOptions
+		severity string
+		reason   string
+		want     bool
+	}{
+		{
+			name:     "allows all by default",
+			severity: eventv1.EventSeverityInfo,
+			reason:   "Progressing",
+			want:     true,
+		},
+		{
+			name:     "drops info events below the minimum severity",
+			opts:     FilterOptions{MinSeverity: eventv1.EventSeverityError},
+			severity: eventv1.EventSeverityInfo,
+			reason:   "ReconciliationSucceeded",
+			want:     false,
+		},
+		{
+			name:     "allows error events with the minimum severity",
+			opts:     FilterOptions{MinSeverity: eventv1.EventSeverityError},
+			severity: eventv1.EventSeverityError,
+			reason:   "ReconciliationFailed",
+			want:     true,
+		},
+		{
+			name:     "drops reason for the given severity",
+			opts:     FilterOptions{ExcludeReasons: []string{"info:Progressing"}},
+			severity: eventv1.EventSeverityInfo,
+			reason:   "Progressing",
+			want:     false,
+		},
+		{
+			name:     "allows reason for other severities",
+			opts:     FilterOptions{ExcludeReasons: []string{"info:Progressing"}},
+			severity: eventv1.EventSeverityError,
+			reason:   "Progressing",
+			want:     true,
+		},
+		{
+			name:     "drops reason for all severities",
+			opts:     FilterOptions{ExcludeReasons: []string{"Progressing"}},
+			severity: eventv1.EventSeverityError,
+			reason:   "Progressing",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			f, err := NewFilter(tt.opts)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(f.Allow(tt.severity, tt.reason)).To(Equal(tt.want))
+
+			var suppressed float64
+			if !tt.want {
+				suppressed = 1
+			}
+			g.Expect(testutil.ToFloat64(f.suppressed.WithLabelValues(tt.severity, tt.reason))).To(Equal(suppressed))
+		})
+	}
+}
+
+func TestNewFilter_Invalid(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := NewFilter(FilterOptions{MinSeverity: "trace"})
+	g.Expect(err).To(MatchError(ContainSubstring("invalid --events-min-severity value 'trace'")))
+	_, err = NewFilter(FilterOptions{ExcludeReasons: []string{"warning:Progressing"}})
+	g.Expect(err).To(MatchError(ContainSubstring("unknown severity 'warning'")))
+	_, err = NewFilter(FilterOptions{ExcludeReasons: []string{"info:"}})
+	g.Expect(err).To(MatchError(ContainSubstring("reason must not be empty")))
+}
+
+func TestEventRecorder_AnnotatedEventf_Filter(t *testing.T) {
+	g := NewWithT(t)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	scheme := runtime.NewScheme()
+	utilruntime.Must(corev1.AddToScheme(scheme))
+	kubeRecorder := kuberecorder.NewFakeRecorder(10)
+	eventRecorder, err := NewRecorderForScheme(scheme, kubeRecorder, ctrl.Log, ts.URL, "test-controller")
+	g.Expect(err).ToNot(HaveOccurred())
+	eventRecorder.Filter, err = NewFilter(FilterOptions{ExcludeReasons: []string{"info:Progressing"}})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var requests int
+	eventRecorder.Client.HTTPClient.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+
+	obj := &corev1.ConfigMap{}
+	obj.Namespace = "gitops-system"
+	obj.Name = "webapp"
+
+	eventRecorder.AnnotatedEventf(obj, nil, corev1.EventTypeNormal, "Progressing", "progressing")
+	g.Expect(requests).To(BeZero())
+	// The suppressed events are recorded as Kubernetes events.
+	g.Expect(kubeRecorder.Events).To(HaveLen(1))
+
+	eventRecorder.AnnotatedEventf(obj, nil, corev1.EventTypeWarning, "Progressing", "failed")
+	g.Expect(requests).ToNot(BeZero())
+	g.Expect(kubeRecorder.Events).To(HaveLen(2))
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,394 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/pflag"
+)
+
+const (
+	flagEventsRetryQueueSize     = "events-retry-queue-size"
+	flagEventsRetryMaxAge        = "events-retry-max-age"
+	flagEventsRetryQueueSpillDir = "events-retry-queue-spill-dir"
+
+	defaultRetryQueueSize        = 1000
+	defaultRetryMaxAge           = time.Hour
+	defaultRetryInitialInterval  = 5 * time.Second
+	defaultRetryMaxInterval      = 5 * time.Minute
+	defaultRetryQueueMaxSpillLen = 10000
+
+	spillFileExt = ".json"
+)
+
+const (
+	// RetryQueueResultQueued is the result of an event queued for retry.
+	RetryQueueResultQueued = "queued"
+	// RetryQueueResultDelivered is the result of a queued event delivered
+	// to the webhook.
+	RetryQueueResultDelivered = "delivered"
+	// RetryQueueResultDropped is the result of an event dropped because the
+	// queue is full, or because it could not be delivered before MaxAge.
+	RetryQueueResultDropped = "dropped"
+)
+
+// RetryQueueOptions contains the configuration of the queue of the events
+// which failed to be delivered to the webhook.
+//
+// The struct can be used in the main.go file of your controller by binding
+// it to the main flag set, and then utilizing the configured options later:
+//
+//	func main() {
+//		var (
+//			// other controller specific configuration variables
+//			eventsRetryQueueOptions events.RetryQueueOptions
+//		)
+//
+//		// Bind the options to the main flag set, and parse it
+//		eventsRetryQueueOptions.BindFlags(flag.CommandLine)
+//		flag.Parse()
+//
+//		queue, err := events.NewRetryQueue(eventsRetryQueueOptions, eventRecorder.Deliver)
+//		if err != nil {
+//			// handle error
+//		}
+//		metrics.Registry.MustRegister(queue.Collectors()...)
+//		eventRecorder.Queue = queue
+//		if err := mgr.Add(queue); err != nil {
+//			// handle error
+//		}
+//	}
+type RetryQueueOptions struct {
+	// MaxSize is the maximum number of events held in memory. Defaults to
+	// 1000.
+	MaxSize int
+
+	// MaxAge is the maximum duration after which an undelivered event is
+	// dropped. Defaults to 1h.
+	MaxAge time.Duration
+
+	// InitialInterval is the interval before the first retry, doubled on
+	// each failed delivery. Defaults to 5s.
+	InitialInterval time.Duration
+
+	// MaxInterval is the maximum interval between two retries. Defaults to
+	// 5m.
+	MaxInterval time.Duration
+
+	// SpillDir is the directory the events are written to when the memory
+	// queue is full, and when the queue is stopped, for them to be
+	// delivered after a restart. The events are only held in memory if
+	// empty.
+	SpillDir string
+
+	// MaxSpillLen is the maximum number of events written to SpillDir.
+	// Defaults to 10000.
+	MaxSpillLen int
+}
+
+// BindFlags will parse the given pflag.FlagSet and load the retry queue
+// options accordingly.
+func (o *RetryQueueOptions) BindFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.MaxSize, flagEventsRetryQueueSize, defaultRetryQueueSize,
+		"The maximum number of events held in memory for retrying their delivery to the events webhook.")
+	fs.DurationVar(&o.MaxAge, flagEventsRetryMaxAge, defaultRetryMaxAge,
+		"The maximum duration after which an event which could not be delivered to the events webhook is dropped.")
+	fs.StringVar(&o.SpillDir, flagEventsRetryQueueSpillDir, "",
+		"The directory the events which could not be delivered to the events webhook are written to "+
+			"when the memory queue is full or the controller stops.")
+}
+
+// DeliverFunc delivers a JSON encoded event to the webhook.
+type DeliverFunc func(ctx context.Context, body []byte) error
+
+// RetryQueue retries the delivery of the events to the webhook with
+// exponential backoff, in the order they were queued, until they are
+// delivered or dropped after the maximum age. It counts the queued,
+// delivered and dropped events.
+//
+// Use NewRetryQueue to initialise it from RetryQueueOptions, and add it to
+// the controller manager to start it.
+type RetryQueue struct {
+	opts    RetryQueueOptions
+	deliver DeliverFunc
+
+	mu      sync.Mutex
+	items   []queuedEvent
+	spilled []string
+	seq     uint64
+	wake    chan struct{}
+
+	events *prometheus.CounterVec
+	length prometheus.Gauge
+}
+
+type queuedEvent struct {
+	queuedAt time.Time
+	body     []byte
+}
+
+// NewRetryQueue returns a RetryQueue delivering the events with the given
+// function, or an error if the options are invalid. The events written to
+// SpillDir by a previous instance are queued for delivery.
+func NewRetryQueue(opts RetryQueueOptions, deliver DeliverFunc) (*RetryQueue, error) {
+	if deliver == nil {
+		return nil, fmt.Errorf("a deliver function is required")
+	}
+	if opts.MaxSize < 0 {
+		return nil, fmt.Errorf("invalid --%s value '%d', must not be negative", flagEventsRetryQueueSize, opts.MaxSize)
+	}
+	if opts.MaxAge < 0 {
+		return nil, fmt.Errorf("invalid --%s value '%s', must not be negative", flagEventsRetryMaxAge, opts.MaxAge)
+	}
+	if opts.MaxSize == 0 {
+		opts.MaxSize = defaultRetryQueueSize
+	}
+	if opts.MaxAge == 0 {
+		opts.MaxAge = defaultRetryMaxAge
+	}
+	if opts.InitialInterval <= 0 {
+		opts.InitialInterval = defaultRetryInitialInterval
+	}
+	if opts.MaxInterval <= 0 {
+		opts.MaxInterval = defaultRetryMaxInterval
+	}
+	if opts.MaxSpillLen <= 0 {
+		opts.MaxSpillLen = defaultRetryQueueMaxSpillLen
+	}
+
+	q := &RetryQueue{
+		opts:    opts,
+		deliver: deliver,
+		wake:    make(chan struct{}, 1),
+		events: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotk_event_retry_queue_events_total",
+				Help: "Total number of events which failed to be delivered to the events webhook partitioned by result.",
+			},
+			[]string{"result"},
+		),
+		length: prometheus.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "gotk_event_retry_queue_length",
+				Help: "The number of events waiting to be delivered to the events webhook.",
+			},
+		),
+	}
+
+	if opts.SpillDir != "" {
+		if err := os.MkdirAll(opts.SpillDir, 0o700); err != nil {
+			return nil, fmt.Errorf("failed to create spill directory: %w", err)
+		}
+		entries, err := os.ReadDir(opts.SpillDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read spill directory: %w", err)
+		}
+		for _, e := range entries {
+			if !e.IsDir() && strings.HasSuffix(e.Name(), spillFileExt) {
+				q.spilled = append(q.spilled, e.Name())
+			}
+		}
+		// The names of the files sort in the order the events were queued.
+		slices.Sort(q.spilled)
+		q.refill()
+		q.updateLength()
+	}
+	return q, nil
+}
+
+// Collectors returns a slice of Prometheus collectors, which can be used to
+// register them in a metrics registry.
+func (q *RetryQueue) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		q.events,
+		q.length,
+	}
+}
+
+// Len returns the number of events waiting to be delivered.
+func (q *RetryQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.items) + len(q.spilled)
+}
+
+// Enqueue queues the JSON encoded event for delivery. The event is dropped
+// if the queue is full.
+func (q *RetryQueue) Enqueue(body []byte) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	event := queuedEvent{queuedAt: time.Now(), body: body}
+	switch {
+	// The spilled events are delivered first to preserve the order.
+	case len(q.items) < q.opts.MaxSize && len(q.spilled) == 0:
+		q.items = append(q.items, event)
+	case q.opts.SpillDir != "" && len(q.spilled) < q.opts.MaxSpillLen:
+		if err := q.spill(event); err != nil {
+			q.events.WithLabelValues(RetryQueueResultDropped).Inc()
+			return
+		}
+	default:
+		q.events.WithLabelValues(RetryQueueResultDropped).Inc()
+		return
+	}
+	q.events.WithLabelValues(RetryQueueResultQueued).Inc()
+	q.updateLength()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start delivers the queued events until the context is done, and then
+// writes the remaining events to SpillDir if set. It implements the
+// manager.Runnable interface of controller-runtime.
+func (q *RetryQueue) Start(ctx context.Context) error {
+	interval := q.opts.InitialInterval
+	var wait <-chan time.Time
+	for {
+		// Keep backing off while the webhook is unavailable.
+		for wait == nil {
+			event, ok := q.peek()
+			if !ok {
+				break
+			}
+			if err := q.deliver(ctx, event.body); err != nil {
+				if ctx.Err() != nil {
+					return q.flush()
+				}
+				// Equal jitter: wait between half and the whole interval.
+				wait = time.After(interval/2 + rand.N(interval/2+1))
+				interval = min(2*interval, q.opts.MaxInterval)
+				break
+			}
+			interval = q.opts.InitialInterval
+			q.pop(RetryQueueResultDelivered)
+		}
+
+		select {
+		case <-ctx.Done():
+			return q.flush()
+		case <-q.wake:
+		case <-wait:
+			wait = nil
+		}
+	}
+}
+
+// peek returns the oldest event of the queue, after dropping the events
+// older than MaxAge.
+func (q *RetryQueue) peek() (queuedEvent, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) > 0 {
+		if time.Since(q.items[0].queuedAt) <= q.opts.MaxAge {
+			return q.items[0], true
+		}
+		q.removeHead(RetryQueueResultDropped)
+	}
+	return queuedEvent{}, false
+}
+
+func (q *RetryQueue) pop(result string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.items) > 0 {
+		q.removeHead(result)
+	}
+}
+
+func (q *RetryQueue) removeHead(result string) {
+	q.items[0] = queuedEvent{}
+	q.items = q.items[1:]
+	q.events.WithLabelValues(result).Inc()
+	q.refill()
+	q.updateLength()
+}
+
+// refill loads the oldest spilled events into the memory queue.
+func (q *RetryQueue) refill() {
+	for len(q.items) < q.opts.MaxSize && len(q.spilled) > 0 {
+		name := q.spilled[0]
+		q.spilled = q.spilled[1:]
+		path := filepath.Join(q.opts.SpillDir, name)
+		body, err := os.ReadFile(path)
+		_ = os.Remove(path)
+		queuedAt, ok := parseSpillFileName(name)
+		if err != nil || !ok {
+			q.events.WithLabelValues(RetryQueueResultDropped).Inc()
+			continue
+		}
+		q.items = append(q.items, queuedEvent{queuedAt: queuedAt, body: body})
+	}
+}
+
+// spill writes the event to SpillDir.
+func (q *RetryQueue) spill(event queuedEvent) error {
+	q.seq++
+	name := fmt.Sprintf("%020d-%010d%s", event.queuedAt.UnixNano(), q.seq, spillFileExt)
+	if err := os.WriteFile(filepath.Join(q.opts.SpillDir, name), event.body, 0o600); err != nil {
+		return err
+	}
+	q.spilled = append(q.spilled, name)
+	slices.Sort(q.spilled)
+	return nil
+}
+
+// flush writes the events held in memory to SpillDir, if set.
+func (q *RetryQueue) flush() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.opts.SpillDir == "" {
+		return nil
+	}
+	for _, event := range q.items {
+		if err := q.spill(event); err != nil {
+			return fmt.Errorf("failed to write event to spill directory: %w", err)
+		}
+	}
+	q.items = nil
+	return nil
+}
+
+func (q *RetryQueue) updateLength() {
+	q.length.Set(float64(len(q.items) + len(q.spilled)))
+}
+
+func parseSpillFileName(name string) (time.Time, bool) {
+	ts, _, ok := strings.Cut(name, "-")
+	if !ok {
+		return time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, nanos), true
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/spf13/pflag"
+)
+
+// fakeWebhook fails the given number of deliveries, and records the
+// delivered events.
+type fakeWebhook struct {
+	mu        sync.Mutex
+	failures  int
+	delivered []string
+}
+
+func (w *fakeWebhook) deliver(_ context.Context, body []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.failures > 0 {
+		w.failures--
+		return errors.New("unavailable")
+	}
+	w.delivered = append(w.delivered, string(body))
+	return nil
+}
+
+func (w *fakeWebhook) events() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.delivered...)
+}
+
+func TestRetryQueueOptions_BindFlags(t *testing.T) {
+	g := NewWithT(t)
+
+	var opts RetryQueueOptions
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	opts.BindFlags(f)
+	g.Expect(f.Parse([]string{
+		"--events-retry-queue-size=10",
+		"--events-retry-max-age=5m",
+		"--events-retry-queue-spill-dir=/tmp/events",
+	})).To(Succeed())
+	g.Expect(opts).To(Equal(RetryQueueOptions{
+		MaxSize:  10,
+		MaxAge:   5 * time.Minute,
+		SpillDir: "/tmp/events",
+	}))
+}
+
+func TestRetryQueue(t *testing.T) {
+	g := NewWithT(t)
+
+	webhook := &fakeWebhook{failures: 3}
+	q, err := NewRetryQueue(RetryQueueOptions{
+		InitialInterval: time.Millisecond,
+		MaxInterval:     10 * time.Millisecond,
+	}, webhook.deliver)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	q.Enqueue([]byte("a"))
+	q.Enqueue([]byte("b"))
+	g.Expect(q.Len()).To(Equal(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Start(ctx)
+
+	q.Enqueue([]byte("c"))
+	g.Eventually(webhook.events).Should(Equal([]string{"a", "b", "c"}))
+	g.Eventually(q.Len).Should(BeZero())
+	g.Expect(testutil.ToFloat64(q.events.WithLabelValues(RetryQueueResultQueued))).To(Equal(float64(3)))
+	g.Expect(testutil.ToFloat64(q.events.WithLabelValues(RetryQueueResultDelivered))).To(Equal(float64(3)))
+	g.Expect(testutil.ToFloat64(q.length)).To(BeZero())
+}
+
+func TestRetryQueue_drop(t *testing.T) {
+	t.Run("full", func(t *testing.T) {
+		g := NewWithT(t)
+
+		webhook := &fakeWebhook{}
+		q, err := NewRetryQueue(RetryQueueOptions{MaxSize: 2}, webhook.deliver)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		q.Enqueue([]byte("a"))
+		q.Enqueue([]byte("b"))
+		q.Enqueue([]byte("c"))
+		g.Expect(q.Len()).To(Equal(2))
+		g.Expect(testutil.ToFloat64(q.events.WithLabelValues(RetryQueueResultDropped))).To(Equal(float64(1)))
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		g := NewWithT(t)
+
+		webhook := &fakeWebhook{}
+		q, err := NewRetryQueue(RetryQueueOptions{MaxAge: time.Millisecond}, webhook.deliver)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		q.Enqueue([]byte("a"))
+		time.Sleep(5 * time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go q.Start(ctx)
+
+		g.Eventually(q.Len).Should(BeZero())
+		g.Expect(webhook.events()).To(BeEmpty())
+		g.Expect(testutil.ToFloat64(q.events.WithLabelValues(RetryQueueResultDropped))).To(Equal(float64(1)))
+	})
+}
+
+func TestRetryQueue_spill(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	webhook := &fakeWebhook{failures: 1}
+	opts := RetryQueueOptions{
+		MaxSize:         1,
+		InitialInterval: time.Hour,
+		SpillDir:        dir,
+	}
+	q, err := NewRetryQueue(opts, webhook.deliver)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- q.Start(ctx)
+	}()
+	for _, e := range []string{"a", "b", "c"} {
+		q.Enqueue([]byte(e))
+	}
+	g.Expect(q.Len()).To(Equal(3))
+
+	// The events held in memory are written to disk on stop.
+	cancel()
+	g.Expect(<-done).To(Succeed())
+	entries, err := os.ReadDir(dir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(HaveLen(3))
+
+	// The events are delivered in order after a restart.
+	q, err = NewRetryQueue(opts, webhook.deliver)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(q.Len()).To(Equal(3))
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go q.Start(ctx)
+
+	g.Eventually(webhook.events).Should(Equal([]string{"a", "b", "c"}))
+	entries, err = os.ReadDir(dir)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(BeEmpty())
+}
+
+func TestRecorder_Deliver(t *testing.T) {
+	g := NewWithT(t)
+
+	status := http.StatusServiceUnavailable
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	r := &Recorder{
+		Webhook: ts.URL,
+		Client:  retryablehttp.NewClient(),
+	}
+	g.Expect(r.Deliver(context.TODO(), []byte("{}"))).To(MatchError("webhook responded with status code 503"))
+	status = http.StatusAccepted
+	g.Expect(r.Deliver(context.TODO(), []byte("{}"))).To(Succeed())
+}
@@ -18,6 +18,7 @@ package events
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -77,6 +78,14 @@ type Recorder struct {
 
 	// Log is the recorder logger.
 	Log logr.Logger
+
+	// Filter decides which events are forwarded to the webhook. If nil, all
+	// the events are forwarded.
+	Filter *Filter
+
+	// Queue retries the delivery of the events which failed to be posted to
+	// the webhook. If nil, these events are dropped.
+	Queue *RetryQueue
 }
 
 var _ kuberecorder.EventRecorder = &Recorder{}
@@ -158,6 +167,9 @@ func (r *Recorder) AnnotatedEventf(
 
 	// Add object annotations to the annotations.
 	annotations := maps.Clone(inputAnnotations)
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
 	if annotatedObject, ok := object.(interface{ GetAnnotations() map[string]string }); ok {
 		for k, v := range annotatedObject.GetAnnotations() {
 			if strings.HasPrefix(k, eventv1.Group+"/") {
@@ -198,6 +210,11 @@ func (r *Recorder) AnnotatedEventf(
 		return
 	}
 
+	// Skip the events suppressed by the configuration of the controller.
+	if r.Filter != nil && !r.Filter.Allow(severity, reason) {
+		return
+	}
+
 	if r.Client == nil {
 		err := fmt.Errorf("retryable HTTP client has not been initialized")
 		log.Error(err, "unable to record event")
@@ -254,11 +271,39 @@ func (r *Recorder) AnnotatedEventf(
 	}
 
 	if _, err := r.Client.Post(r.Webhook, "application/json", body); err != nil {
+		if r.Queue != nil {
+			log.V(logger.DebugLevel).Info("queueing event for retry", "error", err.Error())
+			r.Queue.Enqueue(body)
+			return
+		}
 		log.Error(err, "unable to record event")
 		return
 	}
 }
 
+// Deliver posts the JSON encoded event to the webhook once, and returns an
+// error if the webhook does not accept it. It is the DeliverFunc of the
+// RetryQueue of the Recorder.
+func (r *Recorder) Deliver(ctx context.Context, body []byte) error {
+	if r.Client == nil {
+		return fmt.Errorf("retryable HTTP client has not been initialized")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.Webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := r.Client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status code %d", res.StatusCode)
+	}
+	return nil
+}
+
 // eventTypeToSeverity maps the given eventType string to a GOTK event severity
 // type.
 func eventTypeToSeverity(eventType string) string {
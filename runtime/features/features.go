@@ -18,6 +18,8 @@ package features
 
 import (
 	"fmt"
+	"maps"
+	"sync"
 
 	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
@@ -30,6 +32,7 @@ const (
 
 var featureGates map[string]bool
 var loaded bool
+var mu sync.RWMutex
 
 // FeatureGates is a helper to manage feature switches.
 //
@@ -51,6 +54,9 @@ func (o *FeatureGates) WithLogger(l logr.Logger) *FeatureGates {
 
 // SupportedFeatures sets the supported features and their default values.
 func (o *FeatureGates) SupportedFeatures(features map[string]bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+
 	loaded = true
 	featureGates = features
 
@@ -69,6 +75,9 @@ func (o *FeatureGates) SupportedFeatures(features map[string]bool) error {
 
 // Enabled verifies whether the feature is enabled or not.
 func Enabled(feature string) (bool, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
 	if !loaded {
 		return false, fmt.Errorf("supported features not set")
 	}
@@ -78,6 +87,38 @@ func Enabled(feature string) (bool, error) {
 	return false, fmt.Errorf("feature-gate '%s' not supported", feature)
 }
 
+// Set enables or disables a supported feature at runtime, e.g. when the
+// configuration of the controller is reloaded without a restart.
+func Set(feature string, enabled bool) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	if !loaded {
+		return fmt.Errorf("supported features not set")
+	}
+	if _, ok := featureGates[feature]; !ok {
+		return fmt.Errorf("feature-gate '%s' not supported", feature)
+	}
+	// The map may be the one given to SupportedFeatures, which is not
+	// modified.
+	gates := maps.Clone(featureGates)
+	gates[feature] = enabled
+	featureGates = gates
+	return nil
+}
+
+// All returns a copy of the supported features and whether they are
+// enabled.
+func All() (map[string]bool, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if !loaded {
+		return nil, fmt.Errorf("supported features not set")
+	}
+	return maps.Clone(featureGates), nil
+}
+
 // BindFlags will parse the given pflag.FlagSet and load feature gates accordingly.
 func (o *FeatureGates) BindFlags(fs *pflag.FlagSet) {
 	fs.Var(cliflag.NewMapStringBool(&o.cliFeatures), flagFeatureGates,
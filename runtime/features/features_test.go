@@ -151,3 +151,33 @@ func TestEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestSet(t *testing.T) {
+	g := NewWithT(t)
+
+	loaded = false
+	g.Expect(Set("time-travel", true)).To(MatchError("supported features not set"))
+
+	defaults := map[string]bool{"time-travel": false}
+	features := FeatureGates{}
+	g.Expect(features.SupportedFeatures(defaults)).To(Succeed())
+
+	g.Expect(Set("time-travel", true)).To(Succeed())
+	enabled, err := Enabled("time-travel")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(enabled).To(BeTrue())
+	// the map of the supported features is not modified
+	g.Expect(defaults).To(Equal(map[string]bool{"time-travel": false}))
+
+	g.Expect(Set("invisible-messages", true)).To(MatchError("feature-gate 'invisible-messages' not supported"))
+
+	all, err := All()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(all).To(Equal(map[string]bool{"time-travel": true}))
+
+	// the returned map is a copy
+	all["time-travel"] = false
+	enabled, err = Enabled("time-travel")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(enabled).To(BeTrue())
+}
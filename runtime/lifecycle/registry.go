@@ -0,0 +1,210 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// DefaultTimeout is the default time a hook is given to complete.
+const DefaultTimeout = 30 * time.Second
+
+// Hook is a function run at the startup or the shutdown of a controller. It
+// must return once the given context is done.
+type Hook func(ctx context.Context) error
+
+// Option configures the registration of hooks.
+type Option func(*registration)
+
+// WithOrder sets the order in which the hooks of a subsystem are run
+// relative to the other subsystems. The startup hooks are run in ascending
+// order, and the shutdown hooks in the reverse order. Subsystems with the
+// same order are run in the order of registration. Defaults to 0.
+func WithOrder(order int) Option {
+	return func(r *registration) {
+		r.order = order
+	}
+}
+
+// WithTimeout sets the time the hooks of a subsystem are given to complete.
+// Defaults to DefaultTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *registration) {
+		r.timeout = timeout
+	}
+}
+
+// registration holds the hooks of a subsystem.
+type registration struct {
+	name    string
+	start   Hook
+	stop    Hook
+	order   int
+	timeout time.Duration
+}
+
+// Registry holds the startup and shutdown hooks of the subsystems of a
+// controller, e.g. caches, artifact servers or token refreshers. It
+// implements manager.Runnable, and should be added to the manager to run
+// the hooks when the manager starts and stops:
+//
+//	hooks := lifecycle.NewRegistry()
+//	if err := hooks.Register("artifact-server", srv.Start, srv.Shutdown, lifecycle.WithOrder(10)); err != nil {
+//		return err
+//	}
+//	if err := mgr.Add(hooks); err != nil {
+//		return err
+//	}
+//
+// The startup hooks run once the manager starts, and the shutdown hooks once
+// its context is cancelled. If a startup hook fails, the shutdown hooks of
+// the subsystems already started are run, and the error is returned to the
+// manager.
+type Registry struct {
+	mu            sync.Mutex
+	registrations []*registration
+	started       bool
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds the startup and shutdown hooks of a subsystem with the given
+// name. Either hook can be nil. Hooks can only be registered before the
+// registry is started.
+func (r *Registry) Register(name string, start, stop Hook, opts ...Option) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.started {
+		return fmt.Errorf("cannot register hooks for '%s', the registry has already started", name)
+	}
+	if start == nil && stop == nil {
+		return fmt.Errorf("no hooks provided for '%s'", name)
+	}
+	for _, reg := range r.registrations {
+		if reg.name == name {
+			return fmt.Errorf("hooks for '%s' are already registered", name)
+		}
+	}
+
+	reg := &registration{
+		name:    name,
+		start:   start,
+		stop:    stop,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(reg)
+	}
+	if reg.timeout <= 0 {
+		return fmt.Errorf("invalid timeout for '%s', must be greater than zero", name)
+	}
+	r.registrations = append(r.registrations, reg)
+	return nil
+}
+
+// Start runs the startup hooks, blocks until the given context is done,
+// then runs the shutdown hooks. It implements manager.Runnable.
+func (r *Registry) Start(ctx context.Context) error {
+	r.mu.Lock()
+	if r.started {
+		r.mu.Unlock()
+		return errors.New("the registry has already started")
+	}
+	r.started = true
+	registrations := slices.Clone(r.registrations)
+	r.mu.Unlock()
+
+	slices.SortStableFunc(registrations, func(a, b *registration) int {
+		return a.order - b.order
+	})
+
+	log := ctrl.LoggerFrom(ctx).WithName("lifecycle")
+
+	var started []*registration
+	for _, reg := range registrations {
+		if reg.start != nil {
+			log.V(1).Info("running startup hook", "name", reg.name)
+			if err := run(ctx, reg.start, reg.timeout); err != nil {
+				startErr := fmt.Errorf("startup hook for '%s' failed: %w", reg.name, err)
+				return kerrors.NewAggregate(append([]error{startErr}, r.stop(log, started)...))
+			}
+		}
+		started = append(started, reg)
+	}
+
+	<-ctx.Done()
+	return kerrors.NewAggregate(r.stop(log, started))
+}
+
+// NeedLeaderElection returns false, the hooks run on all the replicas of
+// the controller. It implements manager.LeaderElectionRunnable.
+func (r *Registry) NeedLeaderElection() bool {
+	return false
+}
+
+// stop runs the shutdown hooks of the given registrations in reverse order,
+// and returns their errors.
+func (r *Registry) stop(log logr.Logger, registrations []*registration) []error {
+	var errs []error
+	for i := len(registrations) - 1; i >= 0; i-- {
+		reg := registrations[i]
+		if reg.stop == nil {
+			continue
+		}
+		log.Info("running shutdown hook", "name", reg.name)
+		// The context of the manager is already done at this point.
+		if err := run(context.Background(), reg.stop, reg.timeout); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook for '%s' failed: %w", reg.name, err))
+		}
+	}
+	return errs
+}
+
+// run calls the hook with a context that times out after the given timeout,
+// and returns an error if the hook does not return in time.
+func run(ctx context.Context, hook Hook, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- hook(ctx)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s", timeout)
+		}
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) hook(name string, err error) Hook {
+	return func(ctx context.Context) error {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.calls = append(r.calls, name)
+		return err
+	}
+}
+
+func (r *recorder) get() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func TestRegistry_Start(t *testing.T) {
+	g := NewWithT(t)
+
+	rec := &recorder{}
+	r := NewRegistry()
+	g.Expect(r.Register("server", rec.hook("start server", nil), rec.hook("stop server", nil), WithOrder(10))).To(Succeed())
+	g.Expect(r.Register("cache", rec.hook("start cache", nil), rec.hook("stop cache", nil))).To(Succeed())
+	g.Expect(r.Register("refresher", nil, rec.hook("stop refresher", nil), WithOrder(10))).To(Succeed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error)
+	go func() {
+		errCh <- r.Start(ctx)
+	}()
+
+	g.Eventually(rec.get).Should(Equal([]string{"start cache", "start server"}))
+	g.Expect(r.Register("late", rec.hook("start late", nil), nil)).To(MatchError(ContainSubstring("already started")))
+
+	cancel()
+	g.Eventually(errCh).Should(Receive(BeNil()))
+	g.Expect(rec.get()).To(Equal([]string{
+		"start cache", "start server",
+		"stop refresher", "stop server", "stop cache",
+	}))
+}
+
+func TestRegistry_StartFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	rec := &recorder{}
+	r := NewRegistry()
+	g.Expect(r.Register("cache", rec.hook("start cache", nil), rec.hook("stop cache", nil))).To(Succeed())
+	g.Expect(r.Register("server", rec.hook("start server", errors.New("port in use")), rec.hook("stop server", nil), WithOrder(1))).To(Succeed())
+	g.Expect(r.Register("refresher", rec.hook("start refresher", nil), rec.hook("stop refresher", nil), WithOrder(2))).To(Succeed())
+
+	err := r.Start(context.Background())
+	g.Expect(err).To(MatchError(ContainSubstring("startup hook for 'server' failed: port in use")))
+	g.Expect(rec.get()).To(Equal([]string{"start cache", "start server", "stop cache"}))
+}
+
+func TestRegistry_Timeout(t *testing.T) {
+	g := NewWithT(t)
+
+	r := NewRegistry()
+	blocking := func(ctx context.Context) error {
+		<-make(chan struct{})
+		return nil
+	}
+	g.Expect(r.Register("stuck", nil, blocking, WithTimeout(10*time.Millisecond))).To(Succeed())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := r.Start(ctx)
+	g.Expect(err).To(MatchError(ContainSubstring("shutdown hook for 'stuck' failed: timed out after 10ms")))
+}
+
+func TestRegistry_Register(t *testing.T) {
+	g := NewWithT(t)
+
+	r := NewRegistry()
+	noop := func(ctx context.Context) error { return nil }
+	g.Expect(r.Register("cache", noop, nil)).To(Succeed())
+	g.Expect(r.Register("cache", noop, nil)).To(MatchError(ContainSubstring("already registered")))
+	g.Expect(r.Register("server", nil, nil)).To(MatchError(ContainSubstring("no hooks provided")))
+	g.Expect(r.Register("server", noop, nil, WithTimeout(0))).To(MatchError(ContainSubstring("invalid timeout")))
+}
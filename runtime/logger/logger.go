@@ -17,8 +17,11 @@ limitations under the License.
 package logger
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 	"github.com/spf13/pflag"
+	uberzap "go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/klog/v2"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -77,6 +80,11 @@ var stackLevelStrings = map[string]zapcore.Level{
 type Options struct {
 	LogEncoding string
 	LogLevel    string
+
+	// Level, if set, controls the level of the logger instead of LogLevel,
+	// allowing it to be changed at runtime with SetLevel. It is initialized
+	// to LogLevel by NewLogger.
+	Level *uberzap.AtomicLevel
 }
 
 // BindFlags will parse the given pflag.FlagSet for logger option flags and set the Options accordingly.
@@ -112,6 +120,12 @@ func NewLogger(opts Options) logr.Logger {
 
 	if l, ok := levelStrings[opts.LogLevel]; ok {
 		zapOpts.Level = l
+		if opts.Level != nil {
+			opts.Level.SetLevel(l)
+		}
+	}
+	if opts.Level != nil {
+		zapOpts.Level = opts.Level
 	}
 
 	if l, ok := stackLevelStrings[opts.LogLevel]; ok {
@@ -121,6 +135,27 @@ func NewLogger(opts Options) logr.Logger {
 	return zap.New(zap.UseFlagOptions(&zapOpts))
 }
 
+// ParseLevel returns the zap level for the given log level string, which can
+// be one of 'trace', 'debug', 'info', 'error'.
+func ParseLevel(level string) (zapcore.Level, error) {
+	l, ok := levelStrings[level]
+	if !ok {
+		return 0, fmt.Errorf("invalid log level '%s', must be one of 'trace', 'debug', 'info', 'error'", level)
+	}
+	return l, nil
+}
+
+// SetLevel changes the level of the loggers created with the given atomic
+// level to the given log level string.
+func SetLevel(atomicLevel *uberzap.AtomicLevel, level string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	atomicLevel.SetLevel(l)
+	return nil
+}
+
 // SetLogger sets the logger for the controller-runtime and klog packages to the given logger.
 // It is not thread-safe, and should be called as early as possible in the program's execution.
 func SetLogger(logger logr.Logger) {
@@ -0,0 +1,293 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// SamplingOptions contains the configuration options for sampling
+// repetitive error logs.
+type SamplingOptions struct {
+	// First is the number of occurrences of an error logged in each period.
+	First int
+	// Thereafter is the interval at which the occurrences of an error are
+	// logged after the First ones in each period, i.e. 1 in Thereafter.
+	// When zero, no further occurrence is logged in the period.
+	Thereafter int
+	// Period is the interval at which the sampling of an error is reset,
+	// after logging a summary of its suppressed occurrences.
+	Period time.Duration
+	// IgnoredKeys are the keys of the values excluded from the fingerprint
+	// of the errors, e.g. the ID of the reconciliation which changes on
+	// every occurrence.
+	IgnoredKeys []string
+}
+
+// DefaultSamplingOptions returns the default sampling options, logging the
+// first 3 occurrences of an error every 10 minutes, then 1 in 10.
+func DefaultSamplingOptions() SamplingOptions {
+	return SamplingOptions{
+		First:       3,
+		Thereafter:  10,
+		Period:      10 * time.Minute,
+		IgnoredKeys: []string{"reconcileID"},
+	}
+}
+
+// Sampler samples the errors logged with the logger it wraps, so that an
+// object failing on every reconciliation does not flood the logs. The
+// errors are identified by their fingerprint, made of the name and values
+// of the logger, the message, the error and the values of the log entry.
+// The info logs are not sampled.
+//
+// A summary of the suppressed occurrences of an error is logged on its
+// first occurrence after the end of a period, and by Flush. The Sampler
+// implements the controller-runtime manager.Runnable interface, and flushes
+// the summaries at the end of every period when started:
+//
+//	sampler := logger.NewSampler(logger.NewLogger(logOptions), logger.DefaultSamplingOptions())
+//	logger.SetLogger(sampler.Logger())
+//	...
+//	if err := mgr.Add(sampler); err != nil {
+//		...
+//	}
+type Sampler struct {
+	logger logr.Logger
+	opts   SamplingOptions
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[uint64]*samplingEntry
+	evicted time.Time
+}
+
+// samplingEntry holds the occurrences of an error in the current period.
+type samplingEntry struct {
+	sink       logr.LogSink
+	err        error
+	msg        string
+	start      time.Time
+	count      int
+	suppressed int
+}
+
+// NewSampler returns a Sampler of the errors logged with the given logger.
+func NewSampler(logger logr.Logger, opts SamplingOptions) *Sampler {
+	return &Sampler{
+		logger:  logger,
+		opts:    opts,
+		now:     time.Now,
+		entries: make(map[uint64]*samplingEntry),
+	}
+}
+
+// Logger returns the logger sampling the errors.
+func (s *Sampler) Logger() logr.Logger {
+	sink := s.logger.GetSink()
+	if sink == nil {
+		return s.logger
+	}
+	if cd, ok := sink.(logr.CallDepthLogSink); ok {
+		// Skip the frame of the sampling sink.
+		sink = cd.WithCallDepth(1)
+	}
+	return logr.New(&samplingSink{LogSink: sink, sampler: s})
+}
+
+// Start flushes the summaries of the suppressed errors at the end of every
+// period, until the context is done.
+func (s *Sampler) Start(ctx context.Context) error {
+	if s.opts.Period <= 0 {
+		<-ctx.Done()
+		return nil
+	}
+	ticker := time.NewTicker(s.opts.Period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.Flush()
+			return nil
+		case <-ticker.C:
+			s.Flush()
+		}
+	}
+}
+
+// Flush logs the summaries of the suppressed errors, and resets the
+// sampling of all the errors.
+func (s *Sampler) Flush() {
+	s.mu.Lock()
+	entries := s.entries
+	s.entries = make(map[uint64]*samplingEntry)
+	s.mu.Unlock()
+
+	for _, e := range entries {
+		s.logSummary(e)
+	}
+}
+
+// sample records an occurrence of the error, and returns true if it must be
+// logged. The errors whose period has ended are evicted at most once per
+// period, after logging their summary, so that the errors which do not
+// occur anymore are not retained until the next Flush.
+func (s *Sampler) sample(fingerprint uint64, sink logr.LogSink, err error, msg string) bool {
+	now := s.now()
+
+	s.mu.Lock()
+	var expired []*samplingEntry
+	if s.opts.Period > 0 {
+		if now.Sub(s.evicted) >= s.opts.Period {
+			for fp, e := range s.entries {
+				if now.Sub(e.start) >= s.opts.Period {
+					expired = append(expired, e)
+					delete(s.entries, fp)
+				}
+			}
+			s.evicted = now
+		} else if e, ok := s.entries[fingerprint]; ok && now.Sub(e.start) >= s.opts.Period {
+			expired = append(expired, e)
+			delete(s.entries, fingerprint)
+		}
+	}
+	e, ok := s.entries[fingerprint]
+	if !ok {
+		e = &samplingEntry{sink: sink, err: err, msg: msg, start: now}
+		s.entries[fingerprint] = e
+	}
+	e.count++
+	logged := e.count <= s.opts.First ||
+		(s.opts.Thereafter > 0 && (e.count-s.opts.First)%s.opts.Thereafter == 0)
+	if !logged {
+		e.suppressed++
+	}
+	s.mu.Unlock()
+
+	for _, e := range expired {
+		s.logSummary(e)
+	}
+	return logged
+}
+
+// logSummary logs the number of suppressed occurrences of the error, if
+// any.
+func (s *Sampler) logSummary(e *samplingEntry) {
+	if e.suppressed == 0 {
+		return
+	}
+	e.sink.Error(e.err, e.msg,
+		"suppressedCount", e.suppressed,
+		"suppressedSince", e.start.UTC().Format(time.RFC3339))
+}
+
+// fingerprint returns the fingerprint of an error log entry.
+func (s *Sampler) fingerprint(name string, values []any, err error, msg string, keysAndValues []any) uint64 {
+	h := fnv.New64a()
+	write := func(v any) {
+		_, _ = fmt.Fprint(h, v)
+		_, _ = h.Write([]byte{0})
+	}
+	writeValues := func(kvs []any) {
+		for i := 0; i < len(kvs); i += 2 {
+			if k, ok := kvs[i].(string); ok && slices.Contains(s.opts.IgnoredKeys, k) {
+				continue
+			}
+			write(kvs[i])
+			if i+1 < len(kvs) {
+				write(kvs[i+1])
+			}
+		}
+	}
+
+	write(name)
+	writeValues(values)
+	write(msg)
+	if err != nil {
+		write(err.Error())
+	}
+	writeValues(keysAndValues)
+	return h.Sum64()
+}
+
+// samplingSink is a logr.LogSink sampling the errors with a Sampler.
+type samplingSink struct {
+	logr.LogSink
+	sampler *Sampler
+	name    string
+	values  []any
+}
+
+var _ logr.CallDepthLogSink = &samplingSink{}
+
+// Init does nothing, as the wrapped sink has already been initialized with
+// the call depth of the logger of the Sampler.
+func (s *samplingSink) Init(logr.RuntimeInfo) {}
+
+// Error logs the error if it is sampled.
+func (s *samplingSink) Error(err error, msg string, keysAndValues ...any) {
+	fingerprint := s.sampler.fingerprint(s.name, s.values, err, msg, keysAndValues)
+	if s.sampler.sample(fingerprint, s.LogSink, err, msg) {
+		s.LogSink.Error(err, msg, keysAndValues...)
+	}
+}
+
+// WithValues returns a sampling sink with additional key/value pairs.
+func (s *samplingSink) WithValues(keysAndValues ...any) logr.LogSink {
+	return &samplingSink{
+		LogSink: s.LogSink.WithValues(keysAndValues...),
+		sampler: s.sampler,
+		name:    s.name,
+		values:  append(slices.Clip(s.values), keysAndValues...),
+	}
+}
+
+// WithName returns a sampling sink with the name appended.
+func (s *samplingSink) WithName(name string) logr.LogSink {
+	fullName := name
+	if s.name != "" {
+		fullName = s.name + "/" + name
+	}
+	return &samplingSink{
+		LogSink: s.LogSink.WithName(name),
+		sampler: s.sampler,
+		name:    fullName,
+		values:  s.values,
+	}
+}
+
+// WithCallDepth returns a sampling sink skipping the given number of
+// additional frames, if the wrapped sink supports it.
+func (s *samplingSink) WithCallDepth(depth int) logr.LogSink {
+	cd, ok := s.LogSink.(logr.CallDepthLogSink)
+	if !ok {
+		return s
+	}
+	return &samplingSink{
+		LogSink: cd.WithCallDepth(depth),
+		sampler: s.sampler,
+		name:    s.name,
+		values:  s.values,
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/logr/funcr"
+	. "github.com/onsi/gomega"
+)
+
+type capturedLogs struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (c *capturedLogs) logger() logr.Logger {
+	return funcr.New(func(prefix, args string) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.lines = append(c.lines, prefix+" "+args)
+	}, funcr.Options{})
+}
+
+func (c *capturedLogs) get() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	lines := c.lines
+	c.lines = nil
+	return lines
+}
+
+func TestSampler(t *testing.T) {
+	g := NewWithT(t)
+
+	var logs capturedLogs
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := NewSampler(logs.logger(), SamplingOptions{
+		First:       2,
+		Thereafter:  3,
+		Period:      time.Minute,
+		IgnoredKeys: []string{"reconcileID"},
+	})
+	sampler.now = func() time.Time { return now }
+
+	log := sampler.Logger().WithName("kustomization").WithValues("name", "app", "namespace", "default")
+	err := errors.New("kustomize build failed")
+
+	for i := range 8 {
+		log.WithValues("reconcileID", i).Error(err, "Reconciliation failed")
+	}
+	lines := logs.get()
+	// The occurrences 1, 2, 5 and 8 are logged.
+	g.Expect(lines).To(HaveLen(4))
+	for _, line := range lines {
+		g.Expect(line).To(ContainSubstring(`"msg"="Reconciliation failed"`))
+		g.Expect(line).To(ContainSubstring(`"name"="app"`))
+	}
+	g.Expect(lines[0]).To(ContainSubstring(`"reconcileID"=0`))
+	g.Expect(lines[2]).To(ContainSubstring(`"reconcileID"=4`))
+	g.Expect(lines[3]).To(ContainSubstring(`"reconcileID"=7`))
+
+	// The errors of other objects, and the other errors, are sampled
+	// separately, and the info logs are not sampled.
+	sampler.Logger().WithValues("name", "other").Error(err, "Reconciliation failed")
+	log.Error(errors.New("other"), "Reconciliation failed")
+	log.Error(err, "Health check failed")
+	log.Info("Reconciliation finished")
+	log.Info("Reconciliation finished")
+	g.Expect(logs.get()).To(HaveLen(5))
+
+	// The summary is logged on the first occurrence after the period.
+	now = now.Add(time.Minute)
+	log.Error(err, "Reconciliation failed")
+	lines = logs.get()
+	g.Expect(lines).To(HaveLen(2))
+	g.Expect(lines[0]).To(ContainSubstring(`"suppressedCount"=4`))
+	g.Expect(lines[0]).To(ContainSubstring(`"suppressedSince"="2026-01-01T00:00:00Z"`))
+	g.Expect(lines[0]).To(ContainSubstring(`"name"="app"`))
+	g.Expect(lines[1]).ToNot(ContainSubstring("suppressedCount"))
+
+	// Flush logs the summaries and resets the sampling.
+	log.Error(err, "Reconciliation failed")
+	log.Error(err, "Reconciliation failed")
+	g.Expect(logs.get()).To(HaveLen(1))
+	sampler.Flush()
+	lines = logs.get()
+	g.Expect(lines).To(HaveLen(1))
+	g.Expect(lines[0]).To(ContainSubstring(`"suppressedCount"=1`))
+	log.Error(err, "Reconciliation failed")
+	g.Expect(logs.get()).To(HaveLen(1))
+	sampler.Flush()
+	g.Expect(logs.get()).To(BeEmpty())
+}
+
+func TestSampler_noThereafter(t *testing.T) {
+	g := NewWithT(t)
+
+	var logs capturedLogs
+	sampler := NewSampler(logs.logger(), SamplingOptions{First: 1, Period: time.Hour})
+	log := sampler.Logger()
+
+	for range 5 {
+		log.Error(errors.New("boom"), "failed")
+	}
+	g.Expect(logs.get()).To(HaveLen(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	g.Expect(sampler.Start(ctx)).To(Succeed())
+	lines := logs.get()
+	g.Expect(lines).To(HaveLen(1))
+	g.Expect(lines[0]).To(ContainSubstring(`"suppressedCount"=4`))
+}
+
+func TestSampler_evictsExpiredErrors(t *testing.T) {
+	g := NewWithT(t)
+
+	var logs capturedLogs
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := NewSampler(logs.logger(), SamplingOptions{First: 1, Period: time.Minute})
+	sampler.now = func() time.Time { return now }
+	log := sampler.Logger()
+
+	for i := range 10 {
+		log.WithValues("name", i).Error(errors.New("boom"), "failed")
+		log.WithValues("name", i).Error(errors.New("boom"), "failed")
+	}
+	g.Expect(logs.get()).To(HaveLen(10))
+	g.Expect(sampler.entries).To(HaveLen(10))
+
+	// The errors which do not occur anymore are evicted by the next error
+	// after the period, without waiting for Flush.
+	now = now.Add(time.Minute)
+	log.Error(errors.New("other"), "failed")
+	lines := logs.get()
+	g.Expect(lines).To(HaveLen(11))
+	g.Expect(lines[:10]).To(HaveEach(ContainSubstring(`"suppressedCount"=1`)))
+	g.Expect(sampler.entries).To(HaveLen(1))
+}
+
+func TestSampler_callDepth(t *testing.T) {
+	g := NewWithT(t)
+
+	var lines []string
+	sampler := NewSampler(funcr.New(func(prefix, args string) {
+		lines = append(lines, args)
+	}, funcr.Options{LogCaller: funcr.Error}), SamplingOptions{First: 1})
+
+	logFailure := func(log logr.Logger) {
+		log.WithCallDepth(1).Error(errors.New("boom"), "failed")
+	}
+	_, _, line, _ := runtime.Caller(0)
+	logFailure(sampler.Logger())
+
+	g.Expect(lines).To(HaveLen(1))
+	g.Expect(lines[0]).To(ContainSubstring(fmt.Sprintf(`"caller"={"file"="sampling_test.go" "line"=%d}`, line+1)))
+}
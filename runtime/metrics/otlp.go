@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/pflag"
+	promexporter "go.opentelemetry.io/contrib/bridges/prometheus"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"google.golang.org/grpc/credentials"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	flagOTLPEndpoint = "metrics-otlp-endpoint"
+	flagOTLPProtocol = "metrics-otlp-protocol"
+	flagOTLPInterval = "metrics-otlp-interval"
+	flagOTLPInsecure = "metrics-otlp-insecure"
+	flagOTLPCAFile   = "metrics-otlp-ca-file"
+	flagOTLPCertFile = "metrics-otlp-cert-file"
+	flagOTLPKeyFile  = "metrics-otlp-key-file"
+
+	// OTLPProtocolGRPC is the OTLP over gRPC protocol.
+	OTLPProtocolGRPC = "grpc"
+	// OTLPProtocolHTTP is the OTLP over HTTP with protobuf payloads protocol.
+	OTLPProtocolHTTP = "http/protobuf"
+
+	// otlpShutdownTimeout is the timeout of the last export on shutdown.
+	otlpShutdownTimeout = 10 * time.Second
+)
+
+// OTLPOptions contains the configuration for pushing the metrics of a
+// controller to an OpenTelemetry collector over OTLP, in addition to
+// exposing them for Prometheus scraping.
+//
+// The struct can be used in the main.go file of your controller by binding
+// it to the main flag set, and then adding the exporter to the manager:
+//
+//	func main() {
+//		var (
+//			// other controller specific configuration variables
+//			otlpOptions metrics.OTLPOptions
+//		)
+//
+//		// Bind the options to the main flag set, and parse it
+//		otlpOptions.BindFlags(flag.CommandLine)
+//		flag.Parse()
+//
+//		// Export the metrics of the controller-runtime registry
+//		if otlpOptions.Enabled() {
+//			exporter, err := metrics.NewOTLPExporter(ctx, otlpOptions, crtlmetrics.Registry)
+//			...
+//			mgr.Add(exporter)
+//		}
+//	}
+type OTLPOptions struct {
+	// Endpoint is the address of the OTLP collector, either as host:port or
+	// as a URL. The export is disabled when empty.
+	Endpoint string
+
+	// Protocol is the OTLP protocol, either OTLPProtocolGRPC or
+	// OTLPProtocolHTTP. Defaults to OTLPProtocolGRPC.
+	Protocol string
+
+	// Interval is the interval between two exports.
+	Interval time.Duration
+
+	// Insecure disables the transport security of the connection to the
+	// collector.
+	Insecure bool
+
+	// CAFile is the path of the PEM encoded CA certificates used to verify
+	// the certificate of the collector.
+	CAFile string
+
+	// CertFile is the path of the PEM encoded client certificate used for
+	// mutual TLS with the collector.
+	CertFile string
+
+	// KeyFile is the path of the PEM encoded private key of the client
+	// certificate.
+	KeyFile string
+}
+
+// BindFlags will parse the given pflag.FlagSet for OTLP metrics option flags
+// and set the OTLPOptions accordingly.
+func (o *OTLPOptions) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Endpoint, flagOTLPEndpoint, "",
+		"The address of the OpenTelemetry collector to push the metrics to over OTLP, as host:port or URL. Disabled when empty.")
+	fs.StringVar(&o.Protocol, flagOTLPProtocol, OTLPProtocolGRPC,
+		fmt.Sprintf("The OTLP protocol used to push the metrics, one of '%s' or '%s'.", OTLPProtocolGRPC, OTLPProtocolHTTP))
+	fs.DurationVar(&o.Interval, flagOTLPInterval, time.Minute,
+		"The interval at which the metrics are pushed to the OpenTelemetry collector (duration string).")
+	fs.BoolVar(&o.Insecure, flagOTLPInsecure, false,
+		"Disable the transport security of the connection to the OpenTelemetry collector.")
+	fs.StringVar(&o.CAFile, flagOTLPCAFile, "",
+		"The path of the CA certificates used to verify the certificate of the OpenTelemetry collector.")
+	fs.StringVar(&o.CertFile, flagOTLPCertFile, "",
+		"The path of the client certificate used for mutual TLS with the OpenTelemetry collector.")
+	fs.StringVar(&o.KeyFile, flagOTLPKeyFile, "",
+		"The path of the private key of the client certificate used for mutual TLS with the OpenTelemetry collector.")
+}
+
+// Enabled returns true if an OTLP endpoint is configured.
+func (o OTLPOptions) Enabled() bool {
+	return o.Endpoint != ""
+}
+
+// OTLPExporter periodically pushes the metrics of a Prometheus gatherer to
+// an OpenTelemetry collector over OTLP. It implements manager.Runnable, and
+// pushes the metrics one last time when the manager stops.
+type OTLPExporter struct {
+	provider *sdkmetric.MeterProvider
+}
+
+var _ manager.Runnable = &OTLPExporter{}
+
+// NewOTLPExporter returns an OTLPExporter pushing the metrics of the given
+// gatherer, e.g. the controller-runtime metrics registry, with the given
+// options.
+func NewOTLPExporter(ctx context.Context, opts OTLPOptions, gatherer prometheus.Gatherer) (*OTLPExporter, error) {
+	if !opts.Enabled() {
+		return nil, fmt.Errorf("no OTLP endpoint configured")
+	}
+	if opts.Interval <= 0 {
+		return nil, fmt.Errorf("invalid OTLP export interval '%s'", opts.Interval)
+	}
+
+	var tlsConfig *tls.Config
+	if !opts.Insecure {
+		var err error
+		if tlsConfig, err = opts.tlsConfig(); err != nil {
+			return nil, err
+		}
+	}
+
+	var exporter sdkmetric.Exporter
+	var err error
+	hasScheme := strings.Contains(opts.Endpoint, "://")
+	switch opts.Protocol {
+	case OTLPProtocolGRPC, "":
+		var grpcOpts []otlpmetricgrpc.Option
+		if hasScheme {
+			grpcOpts = append(grpcOpts, otlpmetricgrpc.WithEndpointURL(opts.Endpoint))
+		} else {
+			grpcOpts = append(grpcOpts, otlpmetricgrpc.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			grpcOpts = append(grpcOpts, otlpmetricgrpc.WithInsecure())
+		} else {
+			grpcOpts = append(grpcOpts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		}
+		exporter, err = otlpmetricgrpc.New(ctx, grpcOpts...)
+	case OTLPProtocolHTTP:
+		var httpOpts []otlpmetrichttp.Option
+		if hasScheme {
+			httpOpts = append(httpOpts, otlpmetrichttp.WithEndpointURL(opts.Endpoint))
+		} else {
+			httpOpts = append(httpOpts, otlpmetrichttp.WithEndpoint(opts.Endpoint))
+		}
+		if opts.Insecure {
+			httpOpts = append(httpOpts, otlpmetrichttp.WithInsecure())
+		} else {
+			httpOpts = append(httpOpts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
+		}
+		exporter, err = otlpmetrichttp.New(ctx, httpOpts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol '%s', must be one of '%s' or '%s'",
+			opts.Protocol, OTLPProtocolGRPC, OTLPProtocolHTTP)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	reader := sdkmetric.NewPeriodicReader(exporter,
+		sdkmetric.WithInterval(opts.Interval),
+		sdkmetric.WithProducer(promexporter.NewMetricProducer(promexporter.WithGatherer(gatherer))),
+	)
+	return &OTLPExporter{
+		provider: sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	}, nil
+}
+
+// Start blocks until the context is canceled, while the metrics are pushed
+// in the background. It then pushes the metrics one last time, and shuts
+// the exporter down.
+func (e *OTLPExporter) Start(ctx context.Context) error {
+	<-ctx.Done()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), otlpShutdownTimeout)
+	defer cancel()
+	if err := e.provider.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down OTLP exporter: %w", err)
+	}
+	return nil
+}
+
+// NeedLeaderElection returns false, as the metrics of all the replicas are
+// pushed.
+func (e *OTLPExporter) NeedLeaderElection() bool {
+	return false
+}
+
+// tlsConfig returns the TLS configuration of the connection to the
+// collector, with the CA certificates and the client certificate of the
+// options.
+func (o OTLPOptions) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if o.CAFile != "" {
+		ca, err := os.ReadFile(o.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read OTLP CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("failed to parse OTLP CA file '%s'", o.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	if o.CertFile != "" || o.KeyFile != "" {
+		if o.CertFile == "" || o.KeyFile == "" {
+			return nil, fmt.Errorf("both the OTLP client certificate and key files must be set")
+		}
+		cert, err := tls.LoadX509KeyPair(o.CertFile, o.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OTLP client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
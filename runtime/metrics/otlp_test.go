@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestOTLPExporter(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req colmetricpb.ExportMetricsServiceRequest
+		if err := proto.Unmarshal(body, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		for _, rm := range req.GetResourceMetrics() {
+			for _, sm := range rm.GetScopeMetrics() {
+				for _, m := range sm.GetMetrics() {
+					received = append(received, m.GetName())
+				}
+			}
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	rec := NewRecorder()
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(rec.Collectors()...)
+	rec.RecordSuspend(corev1.ObjectReference{
+		Kind:      "Kustomization",
+		Namespace: "default",
+		Name:      "test",
+	}, true)
+
+	exporter, err := NewOTLPExporter(context.TODO(), OTLPOptions{
+		Endpoint: srv.URL,
+		Protocol: OTLPProtocolHTTP,
+		Interval: time.Hour,
+		Insecure: true,
+	}, reg)
+	require.NoError(t, err)
+	require.False(t, exporter.NeedLeaderElection())
+
+	// The metrics are pushed on shutdown.
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	require.NoError(t, exporter.Start(ctx))
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Contains(t, received, "gotk_suspend_status")
+}
+
+func TestNewOTLPExporter_invalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    OTLPOptions
+		wantErr string
+	}{
+		{
+			name:    "no endpoint",
+			opts:    OTLPOptions{Interval: time.Minute},
+			wantErr: "no OTLP endpoint configured",
+		},
+		{
+			name:    "invalid interval",
+			opts:    OTLPOptions{Endpoint: "localhost:4317"},
+			wantErr: "invalid OTLP export interval",
+		},
+		{
+			name:    "unsupported protocol",
+			opts:    OTLPOptions{Endpoint: "localhost:4317", Interval: time.Minute, Protocol: "http/json", Insecure: true},
+			wantErr: "unsupported OTLP protocol 'http/json'",
+		},
+		{
+			name:    "client certificate without key",
+			opts:    OTLPOptions{Endpoint: "localhost:4317", Interval: time.Minute, CertFile: "tls.crt"},
+			wantErr: "both the OTLP client certificate and key files must be set",
+		},
+		{
+			name:    "missing CA file",
+			opts:    OTLPOptions{Endpoint: "localhost:4317", Interval: time.Minute, CAFile: "testdata/missing.pem"},
+			wantErr: "failed to read OTLP CA file",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOTLPExporter(context.TODO(), tt.opts, prometheus.NewRegistry())
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
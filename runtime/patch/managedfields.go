@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// statusSubresource is the name of the status subresource in the managed fields.
+	statusSubresource = "status"
+	// fieldsV1 is the type of the managed fields recorded by the API server.
+	fieldsV1 = "FieldsV1"
+)
+
+// MigrateConditionsFieldOwner transfers the ownership of the status conditions recorded in the given managed
+// fields from the legacy field managers to the field owner, e.g. when a new version of a controller uses a
+// different field manager name than the previous ones.
+//
+// The conditions fields of the legacy managers are merged into the status subresource entry of the field
+// owner, which is created if it does not exist, and removed from the entries of the legacy managers, dropping
+// the entries which are left empty. The other fields of the legacy managers are kept as is.
+//
+// It returns the migrated managed fields, and true if any of the entries changed.
+func MigrateConditionsFieldOwner(entries []metav1.ManagedFieldsEntry, fieldOwner string, legacyOwners []string) ([]metav1.ManagedFieldsEntry, bool, error) {
+	if fieldOwner == "" || len(legacyOwners) == 0 {
+		return entries, false, nil
+	}
+
+	var (
+		result   []metav1.ManagedFieldsEntry
+		migrated map[string]any
+		template *metav1.ManagedFieldsEntry
+	)
+	for _, entry := range entries {
+		if entry.Manager == fieldOwner || !slices.Contains(legacyOwners, entry.Manager) {
+			result = append(result, entry)
+			continue
+		}
+
+		fields, err := decodeFieldsV1(entry.FieldsV1)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to decode the managed fields of '%s': %w", entry.Manager, err)
+		}
+		conditions, ok := removeConditionsFields(fields)
+		if !ok {
+			result = append(result, entry)
+			continue
+		}
+		if migrated == nil {
+			migrated = make(map[string]any)
+			template = entry.DeepCopy()
+		}
+		mergeFields(migrated, conditions)
+
+		if len(fields) == 0 {
+			continue
+		}
+		raw, err := json.Marshal(fields)
+		if err != nil {
+			return nil, false, err
+		}
+		entry.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+		result = append(result, entry)
+	}
+
+	if migrated == nil {
+		return entries, false, nil
+	}
+
+	ownerIndex := slices.IndexFunc(result, func(e metav1.ManagedFieldsEntry) bool {
+		return e.Manager == fieldOwner &&
+			e.Operation == metav1.ManagedFieldsOperationUpdate &&
+			e.Subresource == statusSubresource
+	})
+	if ownerIndex < 0 {
+		result = append(result, metav1.ManagedFieldsEntry{
+			Manager:     fieldOwner,
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: statusSubresource,
+			APIVersion:  template.APIVersion,
+			Time:        template.Time,
+			FieldsType:  fieldsV1,
+		})
+		ownerIndex = len(result) - 1
+	}
+
+	owner := &result[ownerIndex]
+	fields, err := decodeFieldsV1(owner.FieldsV1)
+	if err != nil {
+		return nil, false, fmt.Errorf("unable to decode the managed fields of '%s': %w", owner.Manager, err)
+	}
+	mergeFields(fields, map[string]any{"f:status": map[string]any{"f:conditions": migrated}})
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false, err
+	}
+	owner.FieldsV1 = &metav1.FieldsV1{Raw: raw}
+
+	return result, true, nil
+}
+
+// migrateConditionsFieldOwner transfers the ownership of the status conditions of the object from the legacy
+// field managers to the field owner, by patching the managed fields of the object. The given object is not
+// modified, so that the changes made to it by the controller are preserved.
+func (h *Helper) migrateConditionsFieldOwner(ctx context.Context, obj client.Object, fieldOwner string, legacyOwners []string) error {
+	entries, changed, err := MigrateConditionsFieldOwner(obj.GetManagedFields(), fieldOwner, legacyOwners)
+	if err != nil || !changed {
+		return err
+	}
+
+	// The resourceVersion is tested to avoid overwriting the managed fields recorded by concurrent updates,
+	// in which case the patch is rejected, and Patch carries on without migrating the conditions field owner.
+	patch := []map[string]any{
+		{"op": "test", "path": "/metadata/resourceVersion", "value": obj.GetResourceVersion()},
+		{"op": "replace", "path": "/metadata/managedFields", "value": entries},
+	}
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	latest := obj.DeepCopyObject().(client.Object)
+	if err := h.client.Patch(ctx, latest, client.RawPatch(types.JSONPatchType, data)); err != nil {
+		return fmt.Errorf("unable to migrate the conditions field owner: %w", err)
+	}
+	return nil
+}
+
+// decodeFieldsV1 returns the fields set of the given managed fields.
+func decodeFieldsV1(f *metav1.FieldsV1) (map[string]any, error) {
+	fields := make(map[string]any)
+	if f == nil || len(f.Raw) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(f.Raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// removeConditionsFields removes the status conditions from the given fields set, and returns them.
+// The status field is removed as well if it holds no other fields.
+func removeConditionsFields(fields map[string]any) (map[string]any, bool) {
+	status, ok := fields["f:status"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	conditions, ok := status["f:conditions"].(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	delete(status, "f:conditions")
+	if _, ok := status["."]; ok && len(status) == 1 {
+		delete(status, ".")
+	}
+	if len(status) == 0 {
+		delete(fields, "f:status")
+	}
+	return conditions, true
+}
+
+// mergeFields merges the src fields set into dst.
+func mergeFields(dst, src map[string]any) {
+	for k, v := range src {
+		srcChild, ok := v.(map[string]any)
+		if !ok {
+			dst[k] = v
+			continue
+		}
+		dstChild, ok := dst[k].(map[string]any)
+		if !ok {
+			dstChild = make(map[string]any)
+			dst[k] = dstChild
+		}
+		mergeFields(dstChild, srcChild)
+	}
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+func managedFieldsEntry(manager, subresource, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   metav1.ManagedFieldsOperationUpdate,
+		APIVersion:  "v1",
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+		Subresource: subresource,
+	}
+}
+
+func TestMigrateConditionsFieldOwner(t *testing.T) {
+	tests := []struct {
+		name        string
+		entries     []metav1.ManagedFieldsEntry
+		want        []metav1.ManagedFieldsEntry
+		wantChanged bool
+	}{
+		{
+			name: "no legacy owner",
+			entries: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("kubectl", "", `{"f:spec":{"f:value":{}}}`),
+				managedFieldsEntry("controller-v2", "status", `{"f:status":{"f:conditions":{}}}`),
+			},
+			want: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("kubectl", "", `{"f:spec":{"f:value":{}}}`),
+				managedFieldsEntry("controller-v2", "status", `{"f:status":{"f:conditions":{}}}`),
+			},
+		},
+		{
+			name: "legacy owner without conditions",
+			entries: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("controller-v1", "", `{"f:metadata":{"f:finalizers":{}}}`),
+			},
+			want: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("controller-v1", "", `{"f:metadata":{"f:finalizers":{}}}`),
+			},
+		},
+		{
+			name: "creates the entry of the field owner",
+			entries: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("controller-v1", "", `{"f:metadata":{"f:finalizers":{}}}`),
+				managedFieldsEntry("controller-v1", "status",
+					`{"f:status":{".":{},"f:conditions":{".":{},"k:{\"type\":\"Ready\"}":{".":{},"f:status":{}}}}}`),
+			},
+			want: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("controller-v1", "", `{"f:metadata":{"f:finalizers":{}}}`),
+				managedFieldsEntry("controller-v2", "status",
+					`{"f:status":{"f:conditions":{".":{},"k:{\"type\":\"Ready\"}":{".":{},"f:status":{}}}}}`),
+			},
+			wantChanged: true,
+		},
+		{
+			name: "merges into the entry of the field owner",
+			entries: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("controller-v2", "status",
+					`{"f:status":{"f:conditions":{"k:{\"type\":\"Ready\"}":{".":{},"f:status":{}}}}}`),
+				managedFieldsEntry("controller-v1", "status",
+					`{"f:status":{"f:conditions":{"k:{\"type\":\"Stalled\"}":{".":{},"f:status":{}}},"f:observedGeneration":{}}}`),
+				managedFieldsEntry("controller-v0", "status",
+					`{"f:status":{"f:conditions":{"k:{\"type\":\"Reconciling\"}":{".":{}}}}}`),
+			},
+			want: []metav1.ManagedFieldsEntry{
+				managedFieldsEntry("controller-v2", "status",
+					`{"f:status":{"f:conditions":{"k:{\"type\":\"Ready\"}":{".":{},"f:status":{}},"k:{\"type\":\"Reconciling\"}":{".":{}},"k:{\"type\":\"Stalled\"}":{".":{},"f:status":{}}}}}`),
+				managedFieldsEntry("controller-v1", "status", `{"f:status":{"f:observedGeneration":{}}}`),
+			},
+			wantChanged: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, changed, err := MigrateConditionsFieldOwner(tt.entries, "controller-v2", []string{"controller-v0", "controller-v1"})
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(changed).To(Equal(tt.wantChanged))
+			g.Expect(got).To(HaveLen(len(tt.want)))
+			for i := range tt.want {
+				g.Expect(got[i].Manager).To(Equal(tt.want[i].Manager))
+				g.Expect(got[i].Subresource).To(Equal(tt.want[i].Subresource))
+				g.Expect(string(got[i].FieldsV1.Raw)).To(MatchJSON(string(tt.want[i].FieldsV1.Raw)))
+			}
+		})
+	}
+}
+
+func TestHelperPatch_WithLegacyFieldOwners(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &testdata.Fake{}
+	obj.GenerateName = "test-"
+	obj.Namespace = "default"
+
+	g.Expect(env.Create(ctx, obj)).To(Succeed())
+	defer func() {
+		g.Expect(env.Delete(ctx, obj)).To(Succeed())
+	}()
+	key := client.ObjectKeyFromObject(obj)
+
+	t.Log("Marking Ready=False with the legacy field owner")
+	patcher, err := NewHelper(obj, env)
+	g.Expect(err).ToNot(HaveOccurred())
+	conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, "failed")
+	g.Expect(patcher.Patch(ctx, obj, WithFieldOwner("controller-v1"))).To(Succeed())
+
+	t.Log("Marking Ready=True with the new field owner")
+	g.Expect(env.Get(ctx, key, obj)).To(Succeed())
+	patcher, err = NewHelper(obj, env)
+	g.Expect(err).ToNot(HaveOccurred())
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "succeeded")
+	g.Expect(patcher.Patch(ctx, obj, WithFieldOwner("controller-v2"), WithLegacyFieldOwners{"controller-v1"})).To(Succeed())
+
+	t.Log("Validating the conditions are owned by the new field owner only")
+	g.Eventually(func() []string {
+		objAfter := obj.DeepCopy()
+		if err := env.Get(ctx, key, objAfter); err != nil {
+			return nil
+		}
+		var managers []string
+		for _, entry := range objAfter.ManagedFields {
+			if entry.Subresource == "status" {
+				managers = append(managers, entry.Manager)
+			}
+		}
+		return managers
+	}, timeout).Should(Equal([]string{"controller-v2"}))
+
+	g.Expect(env.Get(ctx, key, obj)).To(Succeed())
+	g.Expect(conditions.IsTrue(obj, meta.ReadyCondition)).To(BeTrue())
+}
+
+func TestHelperPatch_WithLegacyFieldOwners_StaleResourceVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &testdata.Fake{}
+	obj.GenerateName = "test-"
+	obj.Namespace = "default"
+
+	g.Expect(env.Create(ctx, obj)).To(Succeed())
+	defer func() {
+		g.Expect(env.Delete(ctx, obj)).To(Succeed())
+	}()
+	key := client.ObjectKeyFromObject(obj)
+
+	t.Log("Marking Ready=False with the legacy field owner")
+	patcher, err := NewHelper(obj, env)
+	g.Expect(err).ToNot(HaveOccurred())
+	conditions.MarkFalse(obj, meta.ReadyCondition, meta.FailedReason, "failed")
+	g.Expect(patcher.Patch(ctx, obj, WithFieldOwner("controller-v1"))).To(Succeed())
+
+	g.Expect(env.Get(ctx, key, obj)).To(Succeed())
+	patcher, err = NewHelper(obj, env)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	t.Log("Updating the object concurrently to make its resourceVersion stale")
+	concurrent := obj.DeepCopy()
+	concurrent.Labels = map[string]string{"concurrent": "update"}
+	g.Expect(env.Update(ctx, concurrent)).To(Succeed())
+
+	t.Log("Marking Ready=True with the new field owner")
+	conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "succeeded")
+	obj.Annotations = map[string]string{"patched": "true"}
+	g.Expect(patcher.Patch(ctx, obj, WithFieldOwner("controller-v2"), WithLegacyFieldOwners{"controller-v1"})).To(Succeed())
+
+	t.Log("Validating the changes are patched regardless of the failed migration")
+	g.Eventually(func() bool {
+		objAfter := obj.DeepCopy()
+		if err := env.Get(ctx, key, objAfter); err != nil {
+			return false
+		}
+		return conditions.IsTrue(objAfter, meta.ReadyCondition) &&
+			objAfter.Annotations["patched"] == "true" &&
+			objAfter.Labels["concurrent"] == "update"
+	}, timeout).Should(BeTrue())
+}
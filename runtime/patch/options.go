@@ -21,6 +21,8 @@ and initially adapted to work with the `conditions` package and `metav1.Conditio
 
 package patch
 
+import "k8s.io/apimachinery/pkg/runtime/schema"
+
 // Option is some configuration that modifies options for a patch request.
 type Option interface {
 	// ApplyToHelper applies this configuration to the given Helper options.
@@ -44,6 +46,18 @@ type HelperOptions struct {
 
 	// FieldOwner defines the field owner configuration for Kubernetes patch operations.
 	FieldOwner string
+
+	// LegacyFieldOwners defines the field managers previously used by the controller, of which the ownership
+	// of the status conditions is transferred to the FieldOwner.
+	LegacyFieldOwners []string
+
+	// StatusSubresourceFallback allows the patch helper to patch the status of objects of which the
+	// CustomResourceDefinition does not enable the status subresource through the main resource.
+	StatusSubresourceFallback bool
+
+	// StatusSubresourceWarning is called with the GroupVersionKind of the object every time the
+	// StatusSubresourceFallback is used.
+	StatusSubresourceWarning func(gvk schema.GroupVersionKind)
 }
 
 // WithForceOverwriteConditions allows the patch helper to overwrite conditions in case of conflicts.
@@ -82,3 +96,30 @@ type WithFieldOwner string
 func (w WithFieldOwner) ApplyToHelper(in *HelperOptions) {
 	in.FieldOwner = string(w)
 }
+
+// WithLegacyFieldOwners transfers the ownership of the status conditions from the field managers previously
+// used by the controller, e.g. before a version upgrade renamed its field manager, to the field owner set with
+// WithFieldOwner. The managed fields of the object are migrated on the first patch, so that the conditions are
+// not owned by both managers.
+type WithLegacyFieldOwners []string
+
+// ApplyToHelper applies this configuration to the given HelperOptions.
+func (w WithLegacyFieldOwners) ApplyToHelper(in *HelperOptions) {
+	in.LegacyFieldOwners = w
+}
+
+// WithStatusSubresourceFallback allows the patch helper to patch the status of objects of which the
+// CustomResourceDefinition does not enable the status subresource through the main resource, instead of
+// returning ErrStatusSubresourceNotFound.
+// Warn, if set, is called with the GroupVersionKind of the object every time the fallback is used, so that
+// the misconfiguration can be discovered, e.g. by failing a test. Otherwise, a warning is logged with the
+// logger from the context.
+type WithStatusSubresourceFallback struct {
+	Warn func(gvk schema.GroupVersionKind)
+}
+
+// ApplyToHelper applies this configuration to the given HelperOptions.
+func (w WithStatusSubresourceFallback) ApplyToHelper(in *HelperOptions) {
+	in.StatusSubresourceFallback = true
+	in.StatusSubresourceWarning = w.Warn
+}
@@ -24,6 +24,7 @@ package patch
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/pkg/errors"
@@ -35,10 +36,16 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/fluxcd/pkg/runtime/conditions"
 )
 
+// ErrStatusSubresourceNotFound signals that the status of an object could not be patched, because its
+// CustomResourceDefinition does not enable the status subresource. The status of such objects can be
+// patched through the main resource with WithStatusSubresourceFallback.
+var ErrStatusSubresourceNotFound = errors.New("status subresource not found")
+
 // Helper is a utility for ensuring the proper patching of objects.
 //
 // The Helper MUST be initialised before a set of modifications within the scope of an envisioned patch are made
@@ -132,6 +139,7 @@ type Helper struct {
 	before       *unstructured.Unstructured
 	after        *unstructured.Unstructured
 	changes      map[string]bool
+	options      *HelperOptions
 
 	isConditionsSetter bool
 }
@@ -179,6 +187,7 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 	for _, opt := range opts {
 		opt.ApplyToHelper(options)
 	}
+	h.options = options
 
 	// Convert the object to unstructured to compare against our before copy.
 	h.after, err = ToUnstructured(obj)
@@ -220,6 +229,20 @@ func (h *Helper) Patch(ctx context.Context, obj client.Object, opts ...Option) e
 		}
 	}
 
+	// Transfer the ownership of the conditions from the legacy field owners, before the conditions are patched
+	// by the field owner.
+	if options.FieldOwner != "" && len(options.LegacyFieldOwners) > 0 {
+		if err := h.migrateConditionsFieldOwner(ctx, obj, options.FieldOwner, options.LegacyFieldOwners); err != nil {
+			// A conflict, or a failed test of a stale resourceVersion, must not prevent the changes from being
+			// patched. The migration is retried on the next patch.
+			if !apierrors.IsConflict(err) && !apierrors.IsInvalid(err) {
+				return err
+			}
+			log.FromContext(ctx).Info("warning: unable to migrate the conditions field owner, retrying on the next patch",
+				"error", err.Error())
+		}
+	}
+
 	// Issue patches and return errors in an aggregate.
 	return kerrors.NewAggregate([]error{
 		// Patch the conditions first.
@@ -256,7 +279,38 @@ func (h *Helper) patchStatus(ctx context.Context, obj client.Object, opts ...cli
 	if err != nil {
 		return err
 	}
-	return h.client.Status().Patch(ctx, afterObject, client.MergeFrom(beforeObject), opts...)
+	return h.patchStatusSubresource(ctx, afterObject, client.MergeFrom(beforeObject), opts...)
+}
+
+// patchStatusSubresource issues a patch for the status subresource. If the status subresource is not
+// enabled for the object, it falls back to patching the main resource when StatusSubresourceFallback is
+// set, or returns ErrStatusSubresourceNotFound.
+func (h *Helper) patchStatusSubresource(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	err := h.client.Status().Patch(ctx, obj, patch, opts...)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	// The API server returns a not found error both when the object does not exist and when the status
+	// subresource is not enabled, tell them apart by getting the object.
+	if getErr := h.client.Get(ctx, client.ObjectKeyFromObject(obj), obj.DeepCopyObject().(client.Object)); getErr != nil {
+		return err
+	}
+	if h.options == nil || !h.options.StatusSubresourceFallback {
+		return fmt.Errorf("%w for %s, the CustomResourceDefinition must enable the status subresource: %w",
+			ErrStatusSubresourceNotFound, h.gvk.Kind, err)
+	}
+
+	if h.options.StatusSubresourceWarning != nil {
+		h.options.StatusSubresourceWarning(h.gvk)
+	} else {
+		log.FromContext(ctx).Info("warning: patching the status through the main resource, as the status subresource is not enabled",
+			"gvk", h.gvk.String())
+	}
+
+	subResourceOpts := &client.SubResourcePatchOptions{}
+	subResourceOpts.ApplyOptions(opts)
+	return h.client.Patch(ctx, obj, patch, &subResourceOpts.PatchOptions)
 }
 
 // patchStatusConditions issues a patch if there are any changes to the conditions slice under the status subresource.
@@ -330,7 +384,7 @@ func (h *Helper) patchStatusConditions(ctx context.Context, obj client.Object, f
 		}
 
 		// Issue the patch.
-		err := h.client.Status().Patch(ctx, latest, conditionsPatch, opts...)
+		err := h.patchStatusSubresource(ctx, latest, conditionsPatch, opts...)
 		switch {
 		case apierrors.IsConflict(err):
 			// Requeue.
@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package patch
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+)
+
+func TestHelper_StatusSubresourceFallback(t *testing.T) {
+	scheme := runtime.NewScheme()
+	utilruntime.Must(testdata.AddFakeToScheme(scheme))
+
+	newObject := func(g *WithT, c client.Client) *testdata.Fake {
+		obj := &testdata.Fake{
+			ObjectMeta: metav1.ObjectMeta{
+				GenerateName: "test-",
+				Namespace:    "default",
+			},
+		}
+		g.Expect(c.Create(context.TODO(), obj)).To(Succeed())
+		return obj
+	}
+
+	t.Run("returns an error without the fallback", func(t *testing.T) {
+		g := NewWithT(t)
+
+		// The fake client has no status subresource for the kinds not
+		// registered with WithStatusSubresource.
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		obj := newObject(g, c)
+
+		patcher, err := NewHelper(obj, c)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		obj.Status.ObservedValue = "foo"
+		err = patcher.Patch(context.TODO(), obj)
+		g.Expect(err).To(MatchError(ErrStatusSubresourceNotFound))
+	})
+
+	t.Run("patches the status through the main resource", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		obj := newObject(g, c)
+
+		patcher, err := NewHelper(obj, c)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		var warnings []schema.GroupVersionKind
+		obj.Spec.Value = "foo"
+		obj.Status.ObservedValue = "foo"
+		conditions.MarkTrue(obj, meta.ReadyCondition, meta.SucceededReason, "ready")
+		g.Expect(patcher.Patch(context.TODO(), obj, WithStatusSubresourceFallback{
+			Warn: func(gvk schema.GroupVersionKind) {
+				warnings = append(warnings, gvk)
+			},
+		})).To(Succeed())
+		g.Expect(warnings).ToNot(BeEmpty())
+		g.Expect(warnings[0].Kind).To(Equal("Fake"))
+
+		got := &testdata.Fake{}
+		g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(obj), got)).To(Succeed())
+		g.Expect(got.Spec.Value).To(Equal("foo"))
+		g.Expect(got.Status.ObservedValue).To(Equal("foo"))
+		g.Expect(conditions.IsReady(got)).To(BeTrue())
+	})
+
+	t.Run("does not use the fallback with the status subresource", func(t *testing.T) {
+		g := NewWithT(t)
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&testdata.Fake{}).Build()
+		obj := newObject(g, c)
+
+		patcher, err := NewHelper(obj, c)
+		g.Expect(err).ToNot(HaveOccurred())
+
+		obj.Status.ObservedValue = "foo"
+		g.Expect(patcher.Patch(context.TODO(), obj, WithStatusSubresourceFallback{
+			Warn: func(gvk schema.GroupVersionKind) {
+				t.Errorf("unexpected fallback for %s", gvk)
+			},
+		})).To(Succeed())
+
+		got := &testdata.Fake{}
+		g.Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(obj), got)).To(Succeed())
+		g.Expect(got.Status.ObservedValue).To(Equal("foo"))
+	})
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	// StartupCheckName is the name under which the startup check is
+	// registered on the manager. The check can be probed individually
+	// on the /readyz/startup path of the health probe endpoint.
+	StartupCheckName = "startup"
+
+	// defaultStartupCheckTimeout is the default maximum duration of a
+	// single startup check evaluation. It is kept well below the default
+	// timeout of a Kubernetes probe (1s), so that probes receive a failure
+	// instead of timing out.
+	defaultStartupCheckTimeout = 500 * time.Millisecond
+
+	// cacheSyncCheckTimeout is the maximum duration the informer cache sync
+	// check waits for the caches to be synced. The check is evaluated on
+	// every probe, so it only needs to observe the current state.
+	cacheSyncCheckTimeout = 100 * time.Millisecond
+)
+
+// StartupCheckFunc is a condition which must be met before the controller
+// is considered started. It returns an error for as long as the condition
+// is not met.
+type StartupCheckFunc func(ctx context.Context) error
+
+// CacheSyncWaiter waits for the informer caches to be synced. It is
+// implemented by the controller-runtime cache.Cache.
+type CacheSyncWaiter interface {
+	WaitForCacheSync(ctx context.Context) bool
+}
+
+// StartupOption configures a StartupCheck.
+type StartupOption func(*StartupCheck)
+
+// WithStartupCheck adds a named condition to the StartupCheck, for example
+// to verify that a token cache has been warmed up.
+func WithStartupCheck(name string, check StartupCheckFunc) StartupOption {
+	return func(s *StartupCheck) {
+		s.checks = append(s.checks, namedCheck{name: name, check: check})
+	}
+}
+
+// WithCacheSync adds a condition to the StartupCheck which is met once the
+// informer caches of the given waiter have been synced. The condition does
+// not block until the caches are synced, but only briefly waits for them.
+func WithCacheSync(waiter CacheSyncWaiter) StartupOption {
+	return WithStartupCheck("informer-cache", func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, cacheSyncCheckTimeout)
+		defer cancel()
+		if !waiter.WaitForCacheSync(ctx) {
+			return errors.New("informer caches have not been synced")
+		}
+		return nil
+	})
+}
+
+// WithWritableDir adds a condition to the StartupCheck which is met once
+// the given directory exists and is writable, for example the artifact
+// storage path. The condition is named after the path.
+func WithWritableDir(path string) StartupOption {
+	return WithStartupCheck("writable-dir:"+path, func(_ context.Context) error {
+		return checkWritableDir(path)
+	})
+}
+
+// WithStartupCheckTimeout sets the maximum duration of a single evaluation
+// of all the startup conditions. Defaults to 500 milliseconds.
+func WithStartupCheckTimeout(timeout time.Duration) StartupOption {
+	return func(s *StartupCheck) {
+		s.timeout = timeout
+	}
+}
+
+// StartupCheck is a health checker that reports success only once all its
+// conditions have been met. Once they have, the result is latched and the
+// conditions are no longer evaluated, as a controller does not return to a
+// "starting" state.
+type StartupCheck struct {
+	checks  []namedCheck
+	timeout time.Duration
+
+	started atomic.Bool
+}
+
+type namedCheck struct {
+	name  string
+	check StartupCheckFunc
+}
+
+// NewStartupCheck returns a StartupCheck configured with the given options.
+func NewStartupCheck(opts ...StartupOption) *StartupCheck {
+	s := &StartupCheck{
+		timeout: defaultStartupCheckTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Check implements the controller-runtime healthz.Checker function
+// signature. It returns an error describing the conditions that have not
+// been met yet. Concurrent calls evaluate the conditions independently.
+func (s *StartupCheck) Check(req *http.Request) error {
+	if s.started.Load() {
+		return nil
+	}
+
+	ctx := req.Context()
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+
+	var errs []error
+	for _, c := range s.checks {
+		if err := c.check(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	s.started.Store(true)
+	return nil
+}
+
+// Started returns true if all the startup conditions have been met.
+func (s *StartupCheck) Started() bool {
+	return s.started.Load()
+}
+
+// SetupStartupCheck configures a startup check on the given mgr, which
+// reports success once the informer caches of the manager have been synced
+// and all conditions configured using the options have been met.
+//
+// The check is registered as a ready check named "startup", which means the
+// controller is only considered ready (and receives traffic) once it has
+// started. The check can be used as the target of a Kubernetes startup probe
+// on the /readyz/startup path.
+//
+// The check gates readiness only. It does not delay leader election, which
+// the manager performs independently of its health probes.
+//
+// Example:
+//
+//	func main() {
+//		mgr, err := ctrl.NewManager(cfg, ctrl.Options{})
+//		if err != nil {
+//			log.Error(err, "unable to start manager")
+//			os.Exit(1)
+//		}
+//		probes.SetupChecks(mgr, log)
+//		probes.SetupStartupCheck(mgr, log, probes.WithWritableDir(storagePath))
+//	}
+func SetupStartupCheck(mgr ctrl.Manager, log logr.Logger, opts ...StartupOption) *StartupCheck {
+	opts = append([]StartupOption{WithCacheSync(mgr.GetCache())}, opts...)
+	check := NewStartupCheck(opts...)
+	if err := mgr.AddReadyzCheck(StartupCheckName, check.Check); err != nil {
+		log.Error(err, "unable to create startup check")
+		os.Exit(1)
+	}
+	return check
+}
+
+// checkWritableDir verifies that the given path is a directory in which
+// files can be created.
+func checkWritableDir(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("'%s' is not a directory", path)
+	}
+	f, err := os.CreateTemp(path, ".startup-check-")
+	if err != nil {
+		return fmt.Errorf("'%s' is not writable: %w", path, err)
+	}
+	name := f.Name()
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package probes
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/gomega"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+type fakeCacheSyncWaiter struct {
+	synced bool
+}
+
+func (f *fakeCacheSyncWaiter) WaitForCacheSync(_ context.Context) bool {
+	return f.synced
+}
+
+// blockingCacheSyncWaiter mimics the controller-runtime cache, which blocks
+// until the caches are synced or the context is done.
+type blockingCacheSyncWaiter struct{}
+
+func (blockingCacheSyncWaiter) WaitForCacheSync(ctx context.Context) bool {
+	<-ctx.Done()
+	return false
+}
+
+type fakeCache struct {
+	cache.Cache
+	fakeCacheSyncWaiter
+}
+
+func (f *fakeCache) WaitForCacheSync(ctx context.Context) bool {
+	return f.fakeCacheSyncWaiter.WaitForCacheSync(ctx)
+}
+
+type fakeManager struct {
+	manager.Manager
+	cache       *fakeCache
+	readyChecks map[string]healthz.Checker
+}
+
+func (m *fakeManager) GetCache() cache.Cache {
+	return m.cache
+}
+
+func (m *fakeManager) AddReadyzCheck(name string, check healthz.Checker) error {
+	m.readyChecks[name] = check
+	return nil
+}
+
+func TestStartupCheck_Check(t *testing.T) {
+	g := NewWithT(t)
+
+	waiter := &fakeCacheSyncWaiter{}
+	warmedUp := false
+	check := NewStartupCheck(
+		WithCacheSync(waiter),
+		WithStartupCheck("token-cache", func(_ context.Context) error {
+			if !warmedUp {
+				return errors.New("not warmed up")
+			}
+			return nil
+		}),
+	)
+
+	req := httptest.NewRequest("GET", "/readyz/startup", nil)
+
+	err := check.Check(req)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("informer-cache: informer caches have not been synced"))
+	g.Expect(err.Error()).To(ContainSubstring("token-cache: not warmed up"))
+	g.Expect(check.Started()).To(BeFalse())
+
+	waiter.synced = true
+	err = check.Check(req)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).ToNot(ContainSubstring("informer-cache"))
+
+	warmedUp = true
+	g.Expect(check.Check(req)).To(Succeed())
+	g.Expect(check.Started()).To(BeTrue())
+
+	// The result is latched once all conditions have been met.
+	waiter.synced = false
+	g.Expect(check.Check(req)).To(Succeed())
+}
+
+func TestStartupCheck_CheckDoesNotBlock(t *testing.T) {
+	g := NewWithT(t)
+
+	check := NewStartupCheck(WithCacheSync(blockingCacheSyncWaiter{}))
+	req := httptest.NewRequest("GET", "/readyz/startup", nil)
+
+	start := time.Now()
+	g.Expect(check.Check(req)).ToNot(Succeed())
+	g.Expect(time.Since(start)).To(BeNumerically("<", defaultStartupCheckTimeout))
+}
+
+func TestSetupStartupCheck(t *testing.T) {
+	g := NewWithT(t)
+
+	mgr := &fakeManager{
+		cache:       &fakeCache{},
+		readyChecks: map[string]healthz.Checker{},
+	}
+	dir := t.TempDir()
+	check := SetupStartupCheck(mgr, logr.Discard(), WithWritableDir(dir))
+
+	g.Expect(mgr.readyChecks).To(HaveKey(StartupCheckName))
+	req := httptest.NewRequest("GET", "/readyz/startup", nil)
+
+	// The cache sync check is prepended to the configured checks.
+	err := mgr.readyChecks[StartupCheckName](req)
+	g.Expect(err).To(MatchError(ContainSubstring("informer-cache")))
+	g.Expect(check.Started()).To(BeFalse())
+
+	mgr.cache.synced = true
+	g.Expect(mgr.readyChecks[StartupCheckName](req)).To(Succeed())
+	g.Expect(check.Started()).To(BeTrue())
+}
+
+func TestWithWritableDir(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	req := httptest.NewRequest("GET", "/readyz/startup", nil)
+
+	check := NewStartupCheck(WithWritableDir(filepath.Join(dir, "storage")))
+	g.Expect(check.Check(req)).To(MatchError(ContainSubstring("writable-dir:" + filepath.Join(dir, "storage"))))
+
+	g.Expect(os.Mkdir(filepath.Join(dir, "storage"), 0o700)).To(Succeed())
+	g.Expect(check.Check(req)).To(Succeed())
+
+	entries, err := os.ReadDir(filepath.Join(dir, "storage"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(BeEmpty())
+
+	file := filepath.Join(dir, "file")
+	g.Expect(os.WriteFile(file, nil, 0o600)).To(Succeed())
+	err = NewStartupCheck(WithWritableDir(file)).Check(req)
+	g.Expect(err).To(MatchError(ContainSubstring("is not a directory")))
+}
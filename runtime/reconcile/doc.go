@@ -17,5 +17,6 @@ limitations under the License.
 // Package reconcile provides helpers for the reconciliation. They help finalize
 // the results of a reconciliation and also create patch helper options based
 // on the finalized results that can be used with the patch helper during the
-// reconciliation.
+// reconciliation. The progress of long reconciliations can be reported in the
+// Reconciling condition with a ProgressReporter.
 package reconcile
@@ -0,0 +1,122 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/patch"
+)
+
+// DefaultProgressInterval is the default minimum interval between the status
+// patches of a ProgressReporter.
+const DefaultProgressInterval = 5 * time.Second
+
+// ProgressReporter reports the progress of a long reconciliation in the
+// Reconciling condition of an object, e.g. "building (60%)", so that users
+// can follow it. The condition is updated on every report, but the status is
+// patched at most once per interval, to not flood the API server with
+// patches. The last progress reported since the last patch is patched with
+// the object at the end of the reconciliation.
+//
+//	progress := reconcile.NewProgressReporter(obj, serialPatcher,
+//		reconcile.WithProgressPatchOptions(patch.WithOwnedConditions{Conditions: ownedConditions}))
+//	if err := progress.Report(ctx, "fetching source", 30); err != nil {
+//		...
+//	}
+type ProgressReporter struct {
+	obj          conditions.Setter
+	patcher      *patch.SerialPatcher
+	interval     time.Duration
+	reason       string
+	patchOptions []patch.Option
+	now          func() time.Time
+
+	lastPatch   time.Time
+	lastMessage string
+}
+
+// ProgressOption configures a ProgressReporter.
+type ProgressOption func(*ProgressReporter)
+
+// WithProgressInterval sets the minimum interval between the status patches,
+// DefaultProgressInterval by default. A zero interval patches the status on
+// every report.
+func WithProgressInterval(interval time.Duration) ProgressOption {
+	return func(p *ProgressReporter) {
+		p.interval = interval
+	}
+}
+
+// WithProgressReason sets the reason of the Reconciling condition,
+// meta.ProgressingReason by default.
+func WithProgressReason(reason string) ProgressOption {
+	return func(p *ProgressReporter) {
+		p.reason = reason
+	}
+}
+
+// WithProgressPatchOptions sets the options of the status patches, which
+// should include the owned conditions and the field owner of the controller,
+// as for the patch at the end of the reconciliation.
+func WithProgressPatchOptions(opts ...patch.Option) ProgressOption {
+	return func(p *ProgressReporter) {
+		p.patchOptions = opts
+	}
+}
+
+// NewProgressReporter returns a ProgressReporter of the object, patching its
+// status with the given SerialPatcher.
+func NewProgressReporter(obj conditions.Setter, patcher *patch.SerialPatcher, opts ...ProgressOption) *ProgressReporter {
+	p := &ProgressReporter{
+		obj:      obj,
+		patcher:  patcher,
+		interval: DefaultProgressInterval,
+		reason:   meta.ProgressingReason,
+		now:      time.Now,
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// Report marks the object as Reconciling with the phase and percentage of
+// the reconciliation, and patches the status if the interval since the last
+// patch has elapsed. The percentage is clamped between 0 and 100. A report
+// not changing the condition message is not patched.
+func (p *ProgressReporter) Report(ctx context.Context, phase string, percent int) error {
+	percent = min(max(percent, 0), 100)
+	message := fmt.Sprintf("%s (%d%%)", phase, percent)
+	conditions.MarkReconciling(p.obj, p.reason, "%s", message)
+
+	now := p.now()
+	if message == p.lastMessage ||
+		(!p.lastPatch.IsZero() && now.Sub(p.lastPatch) < p.interval) {
+		return nil
+	}
+	if err := p.patcher.Patch(ctx, p.obj, p.patchOptions...); err != nil {
+		return fmt.Errorf("failed to patch the reconciliation progress: %w", err)
+	}
+	p.lastPatch = now
+	p.lastMessage = message
+	return nil
+}
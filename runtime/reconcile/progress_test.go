@@ -0,0 +1,114 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/apis/meta"
+	"github.com/fluxcd/pkg/runtime/conditions"
+	"github.com/fluxcd/pkg/runtime/conditions/testdata"
+	"github.com/fluxcd/pkg/runtime/patch"
+)
+
+func TestProgressReporter_Report(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	scheme := runtime.NewScheme()
+	g.Expect(testdata.AddFakeToScheme(scheme)).To(Succeed())
+	obj := &testdata.Fake{
+		ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "default"},
+	}
+	// The patch helper may patch the conditions and the rest of the status
+	// separately, hence whether the status was patched is recorded instead
+	// of the number of patches.
+	var patched bool
+	var patchErr error
+	c := fakeclient.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(obj).
+		WithStatusSubresource(obj).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string,
+				obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				if patchErr != nil {
+					return patchErr
+				}
+				patched = true
+				return c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...)
+			},
+		}).
+		Build()
+
+	now := time.Now()
+	progress := NewProgressReporter(obj, patch.NewSerialPatcher(obj, c),
+		WithProgressInterval(time.Minute),
+		WithProgressPatchOptions(patch.WithOwnedConditions{Conditions: []string{meta.ReconcilingCondition}}))
+	progress.now = func() time.Time { return now }
+
+	// The first report is patched.
+	patched = false
+	g.Expect(progress.Report(ctx, "fetching source", 30)).To(Succeed())
+	g.Expect(patched).To(BeTrue())
+	got := &testdata.Fake{}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), got)).To(Succeed())
+	g.Expect(got.Status.Conditions).To(conditions.MatchConditions([]metav1.Condition{
+		*conditions.TrueCondition(meta.ReconcilingCondition, meta.ProgressingReason, "%s", "fetching source (30%)"),
+	}))
+
+	// The reports within the interval update the condition only.
+	now = now.Add(30 * time.Second)
+	patched = false
+	g.Expect(progress.Report(ctx, "building", 60)).To(Succeed())
+	g.Expect(patched).To(BeFalse())
+	g.Expect(conditions.GetMessage(obj, meta.ReconcilingCondition)).To(Equal("building (60%)"))
+
+	// The report after the interval is patched, with the percentage clamped.
+	now = now.Add(30 * time.Second)
+	patched = false
+	g.Expect(progress.Report(ctx, "applying", 120)).To(Succeed())
+	g.Expect(patched).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(obj), got)).To(Succeed())
+	g.Expect(conditions.GetMessage(got, meta.ReconcilingCondition)).To(Equal("applying (100%)"))
+
+	// The report not changing the message is not patched.
+	now = now.Add(time.Minute)
+	patched = false
+	g.Expect(progress.Report(ctx, "applying", 100)).To(Succeed())
+	g.Expect(patched).To(BeFalse())
+
+	// The patch errors are returned, and the report is patched again on the
+	// next call.
+	patchErr = errors.New("conflict")
+	patched = false
+	g.Expect(progress.Report(ctx, "health checking", 90)).To(MatchError(ContainSubstring("conflict")))
+	patchErr = nil
+	patched = false
+	g.Expect(progress.Report(ctx, "health checking", 90)).To(Succeed())
+	g.Expect(patched).To(BeTrue())
+}
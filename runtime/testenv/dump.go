@@ -0,0 +1,293 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testenv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// artifactsDirEnvVar is the environment variable of the directory the
+// artifacts of the tests are written to in CI.
+const artifactsDirEnvVar = "ARTIFACTS"
+
+// DumpSelector selects the objects dumped on test failure.
+type DumpSelector struct {
+	// GVK is the group, version and kind of the objects.
+	GVK schema.GroupVersionKind
+
+	// Namespace restricts the objects to the given namespace. The objects
+	// of all the namespaces are selected if empty.
+	Namespace string
+
+	// Labels restricts the objects to the ones matching the selector. All
+	// the objects are selected if nil.
+	Labels labels.Selector
+}
+
+func (s DumpSelector) matches(obj client.Object) bool {
+	if s.Namespace != "" && obj.GetNamespace() != s.Namespace {
+		return false
+	}
+	return s.Labels == nil || s.Labels.Matches(labels.Set(obj.GetLabels()))
+}
+
+// ConditionRecord is a change of a condition of an object observed while
+// the test was running.
+type ConditionRecord struct {
+	Time               metav1.MicroTime       `json:"time"`
+	Generation         int64                  `json:"generation"`
+	Type               string                 `json:"type"`
+	Status             metav1.ConditionStatus `json:"status"`
+	Reason             string                 `json:"reason,omitempty"`
+	Message            string                 `json:"message,omitempty"`
+	ObservedGeneration int64                  `json:"observedGeneration,omitempty"`
+}
+
+// ObjectDump is the dump of an object, with its events and the history of
+// its conditions.
+type ObjectDump struct {
+	Object           map[string]interface{} `json:"object"`
+	Events           []EventDump            `json:"events,omitempty"`
+	ConditionHistory []ConditionRecord      `json:"conditionHistory,omitempty"`
+}
+
+// EventDump is an event of a dumped object.
+type EventDump struct {
+	Type          string      `json:"type"`
+	Reason        string      `json:"reason"`
+	Message       string      `json:"message"`
+	Count         int32       `json:"count,omitempty"`
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+}
+
+// DumpOnFailure records the history of the conditions of the selected
+// objects from now on, and registers a cleanup function on t which, if the
+// test failed, dumps the objects, their events and the history of their
+// conditions into a YAML file named after the test in the artifacts
+// directory, see WithArtifactsDir. It must be called after the Environment
+// is started.
+//
+//	func TestReconciler(t *testing.T) {
+//		testEnv.DumpOnFailure(t, testenv.DumpSelector{
+//			GVK:       sourcev1.GroupVersion.WithKind(sourcev1.GitRepositoryKind),
+//			Namespace: ns.Name,
+//		})
+//		...
+//	}
+func (e *Environment) DumpOnFailure(t testing.TB, selectors ...DumpSelector) {
+	t.Helper()
+	ctx := context.Background()
+
+	recorder := &conditionRecorder{
+		history: make(map[types.UID][]ConditionRecord),
+		last:    make(map[types.UID]map[string]ConditionRecord),
+	}
+	var cleanups []func()
+	for _, s := range selectors {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(s.GVK)
+		informer, err := e.Manager.GetCache().GetInformer(ctx, obj)
+		if err != nil {
+			t.Logf("unable to record the conditions of %s: %v", s.GVK, err)
+			continue
+		}
+		handle, err := informer.AddEventHandler(recorder.handler(s))
+		if err != nil {
+			t.Logf("unable to record the conditions of %s: %v", s.GVK, err)
+			continue
+		}
+		cleanups = append(cleanups, func() {
+			_ = informer.RemoveEventHandler(handle)
+		})
+	}
+
+	t.Cleanup(func() {
+		for _, c := range cleanups {
+			c()
+		}
+		if !t.Failed() {
+			return
+		}
+		path, err := e.dump(ctx, t.Name(), recorder, selectors)
+		if err != nil {
+			t.Logf("unable to dump objects: %v", err)
+			return
+		}
+		t.Logf("dumped objects to %s", path)
+	})
+}
+
+// dump writes the selected objects as a multi-document YAML file, and
+// returns the path of the file.
+func (e *Environment) dump(ctx context.Context, name string, recorder *conditionRecorder, selectors []DumpSelector) (string, error) {
+	reader := e.Manager.GetAPIReader()
+	var buf bytes.Buffer
+	eventsByNamespace := make(map[string][]corev1.Event)
+	for _, s := range selectors {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(s.GVK.GroupVersion().WithKind(s.GVK.Kind + "List"))
+		opts := []client.ListOption{client.InNamespace(s.Namespace)}
+		if s.Labels != nil {
+			opts = append(opts, client.MatchingLabelsSelector{Selector: s.Labels})
+		}
+		if err := reader.List(ctx, list, opts...); err != nil {
+			return "", fmt.Errorf("failed to list %s: %w", s.GVK, err)
+		}
+
+		for i := range list.Items {
+			obj := &list.Items[i]
+			obj.SetManagedFields(nil)
+			d := ObjectDump{
+				Object:           obj.Object,
+				ConditionHistory: recorder.get(obj.GetUID()),
+			}
+
+			ns := obj.GetNamespace()
+			events, ok := eventsByNamespace[ns]
+			if !ok {
+				var eventList corev1.EventList
+				if err := reader.List(ctx, &eventList, client.InNamespace(ns)); err != nil {
+					return "", fmt.Errorf("failed to list events: %w", err)
+				}
+				events = eventList.Items
+				eventsByNamespace[ns] = events
+			}
+			for _, ev := range events {
+				if ev.InvolvedObject.UID != obj.GetUID() {
+					continue
+				}
+				d.Events = append(d.Events, EventDump{
+					Type:          ev.Type,
+					Reason:        ev.Reason,
+					Message:       ev.Message,
+					Count:         ev.Count,
+					LastTimestamp: ev.LastTimestamp,
+				})
+			}
+			sort.SliceStable(d.Events, func(i, j int) bool {
+				return d.Events[i].LastTimestamp.Before(&d.Events[j].LastTimestamp)
+			})
+
+			b, err := yaml.Marshal(d)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal %s '%s': %w", s.GVK.Kind, client.ObjectKeyFromObject(obj), err)
+			}
+			buf.WriteString("---\n")
+			buf.Write(b)
+		}
+	}
+
+	if err := os.MkdirAll(e.artifactsDir, 0o755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(e.artifactsDir, dumpFileName(name))
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// dumpFileName returns the name of the dump file of the test.
+func dumpFileName(testName string) string {
+	return strings.NewReplacer("/", "_", " ", "_", ":", "_").Replace(testName) + ".yaml"
+}
+
+// conditionRecorder records the changes of the conditions of the objects.
+type conditionRecorder struct {
+	mu      sync.Mutex
+	history map[types.UID][]ConditionRecord
+	last    map[types.UID]map[string]ConditionRecord
+}
+
+func (r *conditionRecorder) handler(s DumpSelector) toolscache.ResourceEventHandler {
+	return toolscache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			r.observe(s, obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			r.observe(s, obj)
+		},
+	}
+}
+
+// observe records the conditions of the object which changed since the
+// last observation.
+func (r *conditionRecorder) observe(s DumpSelector, o interface{}) {
+	obj, ok := o.(*unstructured.Unstructured)
+	if !ok || !s.matches(obj) {
+		return
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := r.last[obj.GetUID()]
+	if last == nil {
+		last = make(map[string]ConditionRecord)
+		r.last[obj.GetUID()] = last
+	}
+	now := metav1.NewMicroTime(time.Now())
+	for _, c := range conditions {
+		m, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		rec := ConditionRecord{
+			Time:       now,
+			Generation: obj.GetGeneration(),
+		}
+		rec.Type, _, _ = unstructured.NestedString(m, "type")
+		status, _, _ := unstructured.NestedString(m, "status")
+		rec.Status = metav1.ConditionStatus(status)
+		rec.Reason, _, _ = unstructured.NestedString(m, "reason")
+		rec.Message, _, _ = unstructured.NestedString(m, "message")
+		rec.ObservedGeneration, _, _ = unstructured.NestedInt64(m, "observedGeneration")
+
+		prev, ok := last[rec.Type]
+		if ok && prev.Status == rec.Status && prev.Reason == rec.Reason &&
+			prev.Message == rec.Message && prev.ObservedGeneration == rec.ObservedGeneration {
+			continue
+		}
+		last[rec.Type] = rec
+		r.history[obj.GetUID()] = append(r.history[obj.GetUID()], rec)
+	}
+}
+
+func (r *conditionRecorder) get(uid types.UID) []ConditionRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ConditionRecord(nil), r.history[uid]...)
+}
@@ -26,6 +26,8 @@ package testenv
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -76,6 +78,7 @@ type Environment struct {
 	startOnce     sync.Once
 	stopOnce      sync.Once
 	cancelManager context.CancelFunc
+	artifactsDir  string
 }
 
 // options holds the configuration options for the Environment.
@@ -83,6 +86,7 @@ type options struct {
 	scheme                  *runtime.Scheme
 	crdDirectoryPaths       []string
 	maxConcurrentReconciles int
+	artifactsDir            string
 }
 
 // withDefaults sets the default configuration for missing values.
@@ -93,6 +97,12 @@ func (o *options) withDefaults() {
 	if o.maxConcurrentReconciles == 0 {
 		o.maxConcurrentReconciles = 2
 	}
+	if o.artifactsDir == "" {
+		o.artifactsDir = os.Getenv(artifactsDirEnvVar)
+	}
+	if o.artifactsDir == "" {
+		o.artifactsDir = filepath.Join(os.TempDir(), "testenv-artifacts")
+	}
 }
 
 // Option sets a configuration for the Environment.
@@ -120,6 +130,16 @@ func WithMaxConcurrentReconciles(max int) Option {
 	}
 }
 
+// WithArtifactsDir configures the directory the objects are dumped to on
+// test failure, see Environment.DumpOnFailure. Defaults to the value of the
+// ARTIFACTS environment variable, or to a directory in the temporary
+// directory if not set.
+func WithArtifactsDir(dir string) Option {
+	return func(o *options) {
+		o.artifactsDir = dir
+	}
+}
+
 // New creates a new environment spinning up a local api-server.
 //
 // NOTE: This function should be called only once for each package you are running tests within, usually the environment
@@ -183,6 +203,8 @@ func New(o ...Option) *Environment {
 		Client:  mgr.GetClient(),
 		Config:  mgr.GetConfig(),
 		env:     env,
+
+		artifactsDir: opts.artifactsDir,
 	}
 }
 
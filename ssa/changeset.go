@@ -21,6 +21,8 @@ import (
 	"fmt"
 	"strings"
 
+	"k8s.io/client-go/rest"
+
 	"github.com/fluxcd/cli-utils/pkg/object"
 )
 
@@ -41,16 +43,73 @@ const (
 	UnchangedAction Action = "unchanged"
 	// DeletedAction represents the deletion of an object.
 	DeletedAction Action = "deleted"
+	// ReplacedAction represents the deletion of an existing object followed
+	// by the creation of its new version.
+	ReplacedAction Action = "replaced"
 	// SkippedAction represents the fact that no action was performed on an object
 	// due to the object being excluded from the reconciliation.
 	SkippedAction Action = "skipped"
+	// CascadedAction represents the deletion of an object by the Kubernetes
+	// garbage collector, as a dependent of deleted objects.
+	CascadedAction Action = "cascaded"
 	// UnknownAction represents an unknown action.
 	UnknownAction Action = "unknown"
 )
 
+// Attribution identifies on behalf of whom, and on which cluster, the
+// changes of a ChangeSet have been made.
+type Attribution struct {
+	// FieldManager is the field manager of the server-side apply requests.
+	FieldManager string
+
+	// Impersonate is the identity impersonated by the client, in the format
+	// 'system:serviceaccount:<namespace>:<name>' for service accounts.
+	// Empty if the client does not impersonate.
+	Impersonate string
+
+	// Cluster identifies the target cluster, e.g. the API server address
+	// or the reference to the kubeconfig of a remote cluster.
+	Cluster string
+}
+
+// String returns the attribution in the format
+// 'manager=<field manager> impersonate=<identity> cluster=<cluster>',
+// omitting the empty fields.
+func (a Attribution) String() string {
+	var fields []string
+	if a.FieldManager != "" {
+		fields = append(fields, "manager="+a.FieldManager)
+	}
+	if a.Impersonate != "" {
+		fields = append(fields, "impersonate="+a.Impersonate)
+	}
+	if a.Cluster != "" {
+		fields = append(fields, "cluster="+a.Cluster)
+	}
+	return strings.Join(fields, " ")
+}
+
+// AttributionFromConfig returns the Attribution of the changes made with a
+// client created from the given REST config. The impersonated identity is
+// the one of the config, and the cluster defaults to the API server address
+// if empty.
+func AttributionFromConfig(config *rest.Config, cluster string) Attribution {
+	if cluster == "" {
+		cluster = config.Host
+	}
+	return Attribution{
+		Impersonate: config.Impersonate.UserName,
+		Cluster:     cluster,
+	}
+}
+
 // ChangeSet holds the result of the reconciliation of an object collection.
 type ChangeSet struct {
 	Entries []ChangeSetEntry
+
+	// Attribution identifies on behalf of whom, and on which cluster, the
+	// changes have been made.
+	Attribution Attribution
 }
 
 // NewChangeSet returns a ChangeSet will an empty slice of entries.
@@ -105,6 +164,10 @@ type ChangeSetEntry struct {
 
 	// Action represents the action type taken by the reconciler for this object.
 	Action Action
+
+	// Attribution identifies on behalf of whom, and on which cluster, the
+	// action has been taken.
+	Attribution Attribution
 }
 
 func (e ChangeSetEntry) String() string {
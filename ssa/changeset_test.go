@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+func TestAttribution_String(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(Attribution{}.String()).To(BeEmpty())
+	g.Expect(Attribution{FieldManager: "kustomize-controller"}.String()).To(Equal("manager=kustomize-controller"))
+	g.Expect(Attribution{
+		FieldManager: "kustomize-controller",
+		Impersonate:  "system:serviceaccount:apps:deployer",
+		Cluster:      "apps/prod-kubeconfig",
+	}.String()).To(Equal("manager=kustomize-controller impersonate=system:serviceaccount:apps:deployer cluster=apps/prod-kubeconfig"))
+}
+
+func TestAttributionFromConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	config := &rest.Config{
+		Host:        "https://prod.example.com:6443",
+		Impersonate: rest.ImpersonationConfig{UserName: "system:serviceaccount:apps:deployer"},
+	}
+	g.Expect(AttributionFromConfig(config, "")).To(Equal(Attribution{
+		Impersonate: "system:serviceaccount:apps:deployer",
+		Cluster:     "https://prod.example.com:6443",
+	}))
+	g.Expect(AttributionFromConfig(config, "apps/prod-kubeconfig").Cluster).To(Equal("apps/prod-kubeconfig"))
+}
+
+func TestResourceManager_Attribution(t *testing.T) {
+	g := NewWithT(t)
+
+	manager := NewResourceManager(nil, nil, Owner{Field: "kustomize-controller", Group: "kustomize.toolkit.fluxcd.io"})
+	g.Expect(manager.Attribution()).To(Equal(Attribution{FieldManager: "kustomize-controller"}))
+
+	manager.SetAttribution(Attribution{
+		Impersonate: "system:serviceaccount:apps:deployer",
+		Cluster:     "apps/prod-kubeconfig",
+	})
+	want := Attribution{
+		FieldManager: "kustomize-controller",
+		Impersonate:  "system:serviceaccount:apps:deployer",
+		Cluster:      "apps/prod-kubeconfig",
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("apps")
+	obj.SetName("config")
+
+	changeSet := manager.newChangeSet()
+	changeSet.Add(*manager.changeSetEntry(obj, CreatedAction))
+	g.Expect(changeSet.Attribution).To(Equal(want))
+	g.Expect(changeSet.Entries[0].Attribution).To(Equal(want))
+	g.Expect(changeSet.String()).To(Equal("ConfigMap/apps/config created"))
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
+)
+
+// FleetCluster is a target cluster of a FleetManager.
+type FleetCluster struct {
+	// Name identifies the cluster in the results and the errors, and is
+	// recorded as the cluster of the Attribution of its ChangeSet.
+	Name string
+
+	// Config is the REST config of the cluster.
+	Config *rest.Config
+
+	// Objects overrides the objects applied to the cluster when not nil.
+	Objects []*unstructured.Unstructured
+}
+
+// FleetResult is the result of applying the objects to a FleetCluster.
+type FleetResult struct {
+	// Cluster is the name of the cluster.
+	Cluster string
+
+	// ChangeSet is the ChangeSet of the objects applied to the cluster.
+	// When the apply fails, it holds the objects applied before the
+	// failure, e.g. the first stage of ApplyAllStaged, or is nil.
+	ChangeSet *ChangeSet
+
+	// Err is the error of the cluster, nil if the objects were applied.
+	Err error
+}
+
+// ResourceManagerFunc returns the ResourceManager of a FleetCluster.
+type ResourceManagerFunc func(cluster FleetCluster, owner Owner) (*ResourceManager, error)
+
+// FleetOption configures a FleetManager.
+type FleetOption func(*FleetManager)
+
+// WithFleetConcurrency sets how many clusters the objects are applied to
+// concurrently. Defaults to 1.
+func WithFleetConcurrency(c int) FleetOption {
+	return func(f *FleetManager) {
+		if c < 1 {
+			c = 1
+		}
+		f.concurrency = c
+	}
+}
+
+// WithResourceManagerFunc sets the function returning the ResourceManager
+// of a cluster, e.g. to set its concurrency or metrics. Defaults to
+// NewFleetResourceManager.
+func WithResourceManagerFunc(fn ResourceManagerFunc) FleetOption {
+	return func(f *FleetManager) {
+		f.newManager = fn
+	}
+}
+
+// FleetManager applies objects to multiple clusters using server-side
+// apply, with a ResourceManager per cluster.
+type FleetManager struct {
+	owner       Owner
+	concurrency int
+	newManager  ResourceManagerFunc
+
+	mu       sync.Mutex
+	managers map[string]*ResourceManager
+}
+
+// NewFleetManager creates a FleetManager for the given owner.
+func NewFleetManager(owner Owner, opts ...FleetOption) *FleetManager {
+	f := &FleetManager{
+		owner:       owner,
+		concurrency: 1,
+		newManager:  NewFleetResourceManager,
+		managers:    make(map[string]*ResourceManager),
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+// NewFleetResourceManager creates a ResourceManager for the REST config of
+// the cluster, with the name of the cluster set in its Attribution.
+func NewFleetResourceManager(cluster FleetCluster, owner Owner) (*ResourceManager, error) {
+	if cluster.Config == nil {
+		return nil, fmt.Errorf("REST config is nil")
+	}
+	httpClient, err := rest.HTTPClientFor(cluster.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+	restMapper, err := apiutil.NewDynamicRESTMapper(cluster.Config, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create REST mapper: %w", err)
+	}
+	kubeClient, err := client.NewWithWatch(cluster.Config, client.Options{
+		HTTPClient: httpClient,
+		Mapper:     restMapper,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	poller := polling.NewStatusPoller(kubeClient, restMapper, polling.Options{})
+
+	manager := NewResourceManager(kubeClient, poller, owner)
+	manager.SetAttribution(Attribution{Cluster: cluster.Name})
+	return manager, nil
+}
+
+// ApplyAll applies the objects to the clusters with ResourceManager.ApplyAll,
+// see applyFleet.
+func (f *FleetManager) ApplyAll(ctx context.Context, clusters []FleetCluster,
+	objects []*unstructured.Unstructured, opts ApplyOptions) ([]FleetResult, error) {
+	return f.applyFleet(ctx, clusters, objects, func(m *ResourceManager, objects []*unstructured.Unstructured) (*ChangeSet, error) {
+		return m.ApplyAll(ctx, objects, opts)
+	})
+}
+
+// ApplyAllStaged applies the objects to the clusters with
+// ResourceManager.ApplyAllStaged, see applyFleet.
+func (f *FleetManager) ApplyAllStaged(ctx context.Context, clusters []FleetCluster,
+	objects []*unstructured.Unstructured, opts ApplyOptions) ([]FleetResult, error) {
+	return f.applyFleet(ctx, clusters, objects, func(m *ResourceManager, objects []*unstructured.Unstructured) (*ChangeSet, error) {
+		return m.ApplyAllStaged(ctx, objects, opts)
+	})
+}
+
+// applyFleet applies a copy of the objects, or of the objects of the
+// cluster when overridden, to each cluster concurrently. A failure on a
+// cluster does not stop the apply on the other clusters. It returns the
+// results in the order of the clusters, and the errors of the failed
+// clusters joined together.
+func (f *FleetManager) applyFleet(ctx context.Context, clusters []FleetCluster, objects []*unstructured.Unstructured,
+	apply func(*ResourceManager, []*unstructured.Unstructured) (*ChangeSet, error)) ([]FleetResult, error) {
+	results := make([]FleetResult, len(clusters))
+
+	var g errgroup.Group
+	g.SetLimit(f.concurrency)
+	for i, cluster := range clusters {
+		i, cluster := i, cluster
+		results[i].Cluster = cluster.Name
+
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				results[i].Err = err
+				return nil
+			}
+			manager, err := f.manager(cluster)
+			if err != nil {
+				results[i].Err = err
+				return nil
+			}
+			clusterObjects := objects
+			if cluster.Objects != nil {
+				clusterObjects = cluster.Objects
+			}
+			// ApplyAll sorts the objects in place.
+			copies := make([]*unstructured.Unstructured, len(clusterObjects))
+			for j, object := range clusterObjects {
+				copies[j] = object.DeepCopy()
+			}
+			results[i].ChangeSet, results[i].Err = apply(manager, copies)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var errs []error
+	for _, result := range results {
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("cluster '%s': %w", result.Cluster, result.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// Evict removes the ResourceManagers of the clusters with the given names,
+// e.g. when a cluster is removed from the fleet or its REST config changes,
+// so that they are created again on the next apply.
+func (f *FleetManager) Evict(clusters ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, name := range clusters {
+		delete(f.managers, name)
+	}
+}
+
+// manager returns the ResourceManager of the cluster, created once per
+// cluster name until evicted.
+func (f *FleetManager) manager(cluster FleetCluster) (*ResourceManager, error) {
+	f.mu.Lock()
+	manager, ok := f.managers[cluster.Name]
+	f.mu.Unlock()
+	if ok {
+		return manager, nil
+	}
+
+	manager, err := f.newManager(cluster, f.owner)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource manager: %w", err)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if existing, ok := f.managers[cluster.Name]; ok {
+		return existing, nil
+	}
+	f.managers[cluster.Name] = manager
+	return manager, nil
+}
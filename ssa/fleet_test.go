@@ -0,0 +1,152 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestFleetManager_ApplyAll(t *testing.T) {
+	g := NewWithT(t)
+
+	// The fake clients record the applied objects, as they don't support
+	// server-side apply.
+	var mu sync.Mutex
+	applied := make(map[string][]string)
+	var running, maxRunning atomic.Int32
+	newManager := func(cluster FleetCluster, owner Owner) (*ResourceManager, error) {
+		if cluster.Config == nil {
+			return nil, errors.New("REST config is nil")
+		}
+		kubeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				if cluster.Name == "rejecting" {
+					return errors.New("admission denied")
+				}
+				po := &client.PatchOptions{}
+				po.ApplyOptions(opts)
+				if len(po.DryRun) == 0 {
+					mu.Lock()
+					applied[cluster.Name] = append(applied[cluster.Name], obj.GetName())
+					mu.Unlock()
+				}
+				return nil
+			},
+		}).Build()
+		manager := NewResourceManager(kubeClient, nil, owner)
+		manager.SetAttribution(Attribution{Cluster: cluster.Name})
+		return manager, nil
+	}
+
+	configMap := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetNamespace("default")
+		u.SetName(name)
+		return u
+	}
+	objects := []*unstructured.Unstructured{configMap("b"), configMap("a")}
+
+	fleet := NewFleetManager(Owner{Field: "flux", Group: "fleet.fluxcd.io"},
+		WithFleetConcurrency(2), WithResourceManagerFunc(newManager))
+	clusters := []FleetCluster{
+		{Name: "staging", Config: &rest.Config{}},
+		{Name: "production", Config: &rest.Config{}, Objects: []*unstructured.Unstructured{configMap("c")}},
+		{Name: "rejecting", Config: &rest.Config{}},
+		{Name: "unreachable"},
+	}
+	results, err := fleet.ApplyAll(context.TODO(), clusters, objects, DefaultApplyOptions())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("cluster 'rejecting': "))
+	g.Expect(err.Error()).To(ContainSubstring("cluster 'unreachable': failed to create resource manager: REST config is nil"))
+	g.Expect(err.Error()).ToNot(ContainSubstring("staging"))
+
+	g.Expect(results).To(HaveLen(4))
+	g.Expect(results[0].Cluster).To(Equal("staging"))
+	g.Expect(results[0].Err).ToNot(HaveOccurred())
+	g.Expect(results[0].ChangeSet.Entries).To(HaveLen(2))
+	g.Expect(results[0].ChangeSet.Attribution.Cluster).To(Equal("staging"))
+	g.Expect(results[1].Cluster).To(Equal("production"))
+	g.Expect(results[1].Err).ToNot(HaveOccurred())
+	g.Expect(results[1].ChangeSet.Entries).To(HaveLen(1))
+	g.Expect(results[1].ChangeSet.Entries[0].Action).To(Equal(CreatedAction))
+	g.Expect(results[2].Cluster).To(Equal("rejecting"))
+	g.Expect(results[2].Err).To(HaveOccurred())
+	g.Expect(results[2].ChangeSet).To(BeNil())
+	g.Expect(results[3].Cluster).To(Equal("unreachable"))
+	g.Expect(results[3].Err).To(HaveOccurred())
+
+	g.Expect(applied).To(Equal(map[string][]string{
+		"staging":    {"a", "b"},
+		"production": {"c"},
+	}))
+	g.Expect(maxRunning.Load()).To(BeNumerically("<=", 2))
+
+	// The shared objects are not sorted in place.
+	g.Expect(objects[0].GetName()).To(Equal("b"))
+}
+
+func TestFleetManager_manager(t *testing.T) {
+	g := NewWithT(t)
+
+	var created int
+	fleet := NewFleetManager(Owner{Field: "flux"}, WithResourceManagerFunc(func(cluster FleetCluster, owner Owner) (*ResourceManager, error) {
+		created++
+		return NewResourceManager(fake.NewClientBuilder().Build(), nil, owner), nil
+	}))
+
+	config := &rest.Config{Host: "https://staging"}
+	m1, err := fleet.manager(FleetCluster{Name: "staging", Config: config})
+	g.Expect(err).ToNot(HaveOccurred())
+	m2, err := fleet.manager(FleetCluster{Name: "staging", Config: config})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m2).To(BeIdenticalTo(m1))
+	g.Expect(created).To(Equal(1))
+
+	// The manager is cached by the cluster name, regardless of the REST
+	// config, until evicted.
+	m3, err := fleet.manager(FleetCluster{Name: "staging", Config: &rest.Config{Host: "https://staging"}})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m3).To(BeIdenticalTo(m1))
+	g.Expect(created).To(Equal(1))
+
+	fleet.Evict("staging", "unknown")
+	m4, err := fleet.manager(FleetCluster{Name: "staging", Config: config})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(m4).ToNot(BeIdenticalTo(m1))
+	g.Expect(created).To(Equal(2))
+}
@@ -0,0 +1,252 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsondiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wI2L/jsondiff"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// OperationOwner is the owner of the change of an operation of a Diff.
+type OperationOwner string
+
+const (
+	// OperationOwnerUser indicates that the change is a drift from the
+	// desired state, e.g. caused by a manual edit.
+	OperationOwnerUser OperationOwner = "user"
+	// OperationOwnerAutomation indicates that the change is made by a
+	// controller managing the field in the cluster, e.g. a
+	// HorizontalPodAutoscaler scaling a Deployment.
+	OperationOwnerAutomation OperationOwner = "automation"
+)
+
+// AutomationRule attributes the changes of the given paths to the
+// controllers with the given field managers, when the field of the path is
+// owned by one of them in the managed fields of the object in the cluster.
+type AutomationRule struct {
+	// Managers are the field managers of the controllers.
+	Managers []string
+	// Paths are the JSON pointers (RFC 6901) of the fields managed by the
+	// controllers. The changes of the fields below a path are attributed
+	// too. A "*" segment matches any segment, e.g. any list index.
+	Paths []string
+}
+
+// DefaultAutomationRules attribute the changes of the replicas to the
+// HorizontalPodAutoscaler, which scales the workloads on behalf of the
+// kube-controller-manager, and the changes of the resources of the
+// containers to the VerticalPodAutoscaler.
+var DefaultAutomationRules = []AutomationRule{
+	{
+		Managers: []string{"kube-controller-manager"},
+		Paths:    []string{"/spec/replicas"},
+	},
+	{
+		Managers: []string{"vpa-updater", "vpa-admission-controller", "vpa-recommender"},
+		Paths: []string{
+			"/spec/containers/*/resources",
+			"/spec/initContainers/*/resources",
+			"/spec/template/spec/containers/*/resources",
+			"/spec/template/spec/initContainers/*/resources",
+		},
+	},
+}
+
+// AutomationRules sets the rules to classify the operations of the
+// server-side apply diff, see ClassifyPatch.
+type AutomationRules []AutomationRule
+
+// ApplyToResource applies this configuration to the given options.
+func (r AutomationRules) ApplyToResource(opts *ResourceOptions) {
+	opts.AutomationRules = r
+}
+
+// ApplyToList applies this configuration to the given options.
+func (r AutomationRules) ApplyToList(_ *ListOptions) {
+	// no-op
+}
+
+// ClassifyPatch returns the owner of each operation of the patch, computed
+// from the managed fields of the object in the cluster. An operation is
+// owned by automation if its path matches a rule, and its field is owned
+// by one of the managers of the rule. The other operations are owned by the
+// user.
+func ClassifyPatch(cluster *unstructured.Unstructured, patch jsondiff.Patch, rules []AutomationRule) []OperationOwner {
+	owners := make([]OperationOwner, len(patch))
+	var fields []managedFields
+	if cluster != nil {
+		fields = parseManagedFields(cluster)
+	}
+	for i, op := range patch {
+		owners[i] = OperationOwnerUser
+		segments := splitPointer(op.Path)
+		for _, rule := range rules {
+			if !rule.matches(segments) {
+				continue
+			}
+			if slices.ContainsFunc(fields, func(f managedFields) bool {
+				return slices.Contains(rule.Managers, f.manager) && f.owns(cluster.Object, segments)
+			}) {
+				owners[i] = OperationOwnerAutomation
+				break
+			}
+		}
+	}
+	return owners
+}
+
+// matches returns true if the path is at or below one of the paths of the
+// rule.
+func (r AutomationRule) matches(segments []string) bool {
+	for _, p := range r.Paths {
+		pattern := splitPointer(p)
+		if len(pattern) > len(segments) {
+			continue
+		}
+		matched := true
+		for i, s := range pattern {
+			if s != "*" && s != segments[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// managedFields are the fields owned by a manager.
+type managedFields struct {
+	manager string
+	fields  map[string]interface{}
+}
+
+func parseManagedFields(obj *unstructured.Unstructured) []managedFields {
+	var result []managedFields
+	for _, entry := range obj.GetManagedFields() {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		var fields map[string]interface{}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		result = append(result, managedFields{manager: entry.Manager, fields: fields})
+	}
+	return result
+}
+
+// owns returns true if the field at the path of the object is in the set
+// of fields of the manager. The elements of the lists are identified by the
+// keys of the set, i.e. their merge keys, values or indexes.
+func (m managedFields) owns(obj interface{}, segments []string) bool {
+	node, value := m.fields, obj
+	for _, seg := range segments {
+		var next map[string]interface{}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, _ = node["f:"+seg].(map[string]interface{})
+			value = v[seg]
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(v) {
+				return false
+			}
+			next = listElementFields(node, v[i], i)
+			value = v[i]
+		default:
+			return false
+		}
+		if next == nil {
+			return false
+		}
+		node = next
+	}
+	return true
+}
+
+// listElementFields returns the fields of the list element from the fields
+// of the list.
+func listElementFields(node map[string]interface{}, elem interface{}, index int) map[string]interface{} {
+	for key, child := range node {
+		fields, ok := child.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(key, "i:"):
+			if key[2:] == strconv.Itoa(index) {
+				return fields
+			}
+		case strings.HasPrefix(key, "v:"):
+			if jsonEqual([]byte(key[2:]), elem) {
+				return fields
+			}
+		case strings.HasPrefix(key, "k:"):
+			var mergeKeys map[string]json.RawMessage
+			m, ok := elem.(map[string]interface{})
+			if !ok || json.Unmarshal([]byte(key[2:]), &mergeKeys) != nil {
+				continue
+			}
+			matched := true
+			for k, v := range mergeKeys {
+				if !jsonEqual(v, m[k]) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return fields
+			}
+		}
+	}
+	return nil
+}
+
+// jsonEqual returns true if the JSON encoding of the value is equal to the
+// compacted raw JSON.
+func jsonEqual(raw []byte, value interface{}) bool {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, raw); err != nil {
+		return false
+	}
+	return bytes.Equal(compacted.Bytes(), b)
+}
+
+// splitPointer splits a JSON pointer into its unescaped segments.
+func splitPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, s := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+	}
+	return segments
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsondiff
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/wI2L/jsondiff"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newClassifyTestDeployment() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":      "app",
+			"namespace": "default",
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(5),
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "app",
+							"image": "ghcr.io/org/app:v1",
+							"resources": map[string]interface{}{
+								"requests": map[string]interface{}{"cpu": "200m"},
+							},
+						},
+						map[string]interface{}{
+							"name":  "sidecar",
+							"image": "ghcr.io/org/sidecar:v1",
+							"resources": map[string]interface{}{
+								"requests": map[string]interface{}{"cpu": "50m"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:   "kustomize-controller",
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:containers":{` +
+				`"k:{\"name\":\"app\"}":{".":{},"f:image":{},"f:name":{}},` +
+				`"k:{\"name\":\"sidecar\"}":{".":{},"f:image":{},"f:name":{},"f:resources":{"f:requests":{"f:cpu":{}}}}}}}}}`)},
+		},
+		{
+			Manager:     "kube-controller-manager",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "scale",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:replicas":{}}}`)},
+		},
+		{
+			Manager:   "vpa-updater",
+			Operation: metav1.ManagedFieldsOperationUpdate,
+			FieldsV1: &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:template":{"f:spec":{"f:containers":{` +
+				`"k:{\"name\":\"app\"}":{"f:resources":{"f:requests":{"f:cpu":{}}}}}}}}}`)},
+		},
+	})
+	return obj
+}
+
+func TestClassifyPatch(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newClassifyTestDeployment()
+	patch := jsondiff.Patch{
+		{Type: jsondiff.OperationReplace, Path: "/spec/replicas", OldValue: 5, Value: 2},
+		{Type: jsondiff.OperationReplace, Path: "/spec/template/spec/containers/0/resources/requests/cpu", OldValue: "200m", Value: "100m"},
+		{Type: jsondiff.OperationReplace, Path: "/spec/template/spec/containers/1/resources/requests/cpu", OldValue: "50m", Value: "10m"},
+		{Type: jsondiff.OperationReplace, Path: "/spec/template/spec/containers/0/image", OldValue: "ghcr.io/org/app:v1", Value: "ghcr.io/org/app:v2"},
+		{Type: jsondiff.OperationAdd, Path: "/spec/template/spec/containers/2", Value: map[string]interface{}{"name": "new"}},
+	}
+
+	owners := ClassifyPatch(cluster, patch, DefaultAutomationRules)
+	g.Expect(owners).To(Equal([]OperationOwner{
+		OperationOwnerAutomation,
+		OperationOwnerAutomation,
+		OperationOwnerUser,
+		OperationOwnerUser,
+		OperationOwnerUser,
+	}))
+
+	// The fields owned by managers without a matching rule are owned by the
+	// user.
+	owners = ClassifyPatch(cluster, patch, []AutomationRule{
+		{Managers: []string{"keda-operator"}, Paths: []string{"/spec/replicas"}},
+	})
+	g.Expect(owners).To(HaveEach(OperationOwnerUser))
+
+	owners = ClassifyPatch(nil, patch, DefaultAutomationRules)
+	g.Expect(owners).To(HaveEach(OperationOwnerUser))
+}
+
+func TestDiffSet_HasUserDrift(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newClassifyTestDeployment()
+	patch := jsondiff.Patch{
+		{Type: jsondiff.OperationReplace, Path: "/spec/replicas", OldValue: 5, Value: 2},
+	}
+	diff := NewDiffForUnstructured(cluster, cluster, DiffTypeUpdate, patch)
+	g.Expect(DiffSet{diff}.HasUserDrift()).To(BeTrue())
+
+	diff.Owners = ClassifyPatch(cluster, patch, DefaultAutomationRules)
+	g.Expect(diff.UserPatch()).To(BeEmpty())
+	g.Expect(DiffSet{diff}.HasUserDrift()).To(BeFalse())
+	g.Expect(DiffSet{diff}.HasChanges()).To(BeTrue())
+
+	diff.Patch = append(diff.Patch, jsondiff.Operation{
+		Type: jsondiff.OperationAdd, Path: "/metadata/labels/team", Value: "dev",
+	})
+	diff.Owners = ClassifyPatch(cluster, diff.Patch, DefaultAutomationRules)
+	g.Expect(diff.UserPatch()).To(HaveLen(1))
+	g.Expect(DiffSet{diff}.HasUserDrift()).To(BeTrue())
+}
@@ -33,12 +33,20 @@ const (
 	// DiffTypeUpdate indicates that the resource exists and needs
 	// to be updated.
 	DiffTypeUpdate DiffType = "update"
+	// DiffTypeDelete indicates that the resource exists and is to
+	// be deleted, e.g. pruned because it was removed from the desired
+	// state.
+	DiffTypeDelete DiffType = "delete"
 	// DiffTypeExclude indicates that the resource is excluded from
 	// the diff.
 	DiffTypeExclude DiffType = "exclude"
 	// DiffTypeNone indicates that the resource exists and is
 	// identical to the dry-run object.
 	DiffTypeNone DiffType = "none"
+	// DiffTypeUnknown indicates that the resource could not be compared
+	// with the cluster because the request was forbidden, see
+	// TolerateForbidden. The error is set in the Err field of the Diff.
+	DiffTypeUnknown DiffType = "unknown"
 )
 
 // Diff is a change detected by the server-side apply diff operation.
@@ -47,18 +55,29 @@ type Diff struct {
 	Type DiffType
 
 	// DesiredObject is the client.Object that was used as the desired state to
-	// generate the Patch.
+	// generate the Patch. For DiffTypeDelete, it is the object to delete.
 	DesiredObject client.Object
 
 	// ClusterObject is the client.Object in the cluster that was used as the
 	// current state to generate the Patch.
-	// It is nil if the resource does not exist in the cluster or has been
-	// excluded, which can be detected by checking the Type field for the
-	// value DiffTypeCreate or DiffTypeExclude.
+	// For DiffTypeDelete, it is the object to delete as found in the cluster.
+	// It is nil if the resource does not exist in the cluster, has been
+	// excluded or could not be read, which can be detected by checking the
+	// Type field for the value DiffTypeCreate, DiffTypeExclude or
+	// DiffTypeUnknown.
 	ClusterObject client.Object
 
 	// Patch with the changes detected for the resource.
 	Patch jsondiff.Patch
+
+	// Owners is the owner of each operation of the Patch, in the same
+	// order. It is nil if the Diff was generated without AutomationRules,
+	// in which case all the operations are considered owned by the user.
+	Owners []OperationOwner
+
+	// Err is the error which prevented the comparison of the resource with
+	// the cluster. It is only set for the DiffTypeUnknown type.
+	Err error
 }
 
 // GetName returns the name of the resource the Diff applies to.
@@ -77,6 +96,21 @@ func (d *Diff) GroupVersionKind() schema.GroupVersionKind {
 	return d.DesiredObject.GetObjectKind().GroupVersionKind()
 }
 
+// UserPatch returns the operations of the Patch which are not owned by
+// automation, i.e. the drift from the desired state.
+func (d *Diff) UserPatch() jsondiff.Patch {
+	if len(d.Owners) != len(d.Patch) {
+		return d.Patch
+	}
+	var patch jsondiff.Patch
+	for i, op := range d.Patch {
+		if d.Owners[i] != OperationOwnerAutomation {
+			patch = append(patch, op)
+		}
+	}
+	return patch
+}
+
 // NewDiffForUnstructured creates a new Diff for the given unstructured object.
 func NewDiffForUnstructured(desired, cluster client.Object, t DiffType, p jsondiff.Patch) *Diff {
 	return &Diff{
@@ -87,6 +121,17 @@ func NewDiffForUnstructured(desired, cluster client.Object, t DiffType, p jsondi
 	}
 }
 
+// NewUnknownDiffForUnstructured creates a new Diff of type DiffTypeUnknown
+// for the given unstructured object, with the error which prevented its
+// comparison.
+func NewUnknownDiffForUnstructured(desired client.Object, err error) *Diff {
+	return &Diff{
+		Type:          DiffTypeUnknown,
+		DesiredObject: desired,
+		Err:           err,
+	}
+}
+
 // DiffSet is a list of changes.
 type DiffSet []*Diff
 
@@ -101,10 +146,22 @@ func (ds DiffSet) HasType(t DiffType) bool {
 }
 
 // HasChanges returns true if the DiffSet contains a Diff of type
-// DiffTypeCreate or DiffTypeUpdate.
+// DiffTypeCreate, DiffTypeUpdate or DiffTypeDelete.
 func (ds DiffSet) HasChanges() bool {
 	for _, d := range ds {
-		if d.Type == DiffTypeCreate || d.Type == DiffTypeUpdate {
+		if d.Type == DiffTypeCreate || d.Type == DiffTypeUpdate || d.Type == DiffTypeDelete {
+			return true
+		}
+	}
+	return false
+}
+
+// HasUserDrift returns true if the DiffSet contains a Diff of type
+// DiffTypeCreate, or a Diff of type DiffTypeUpdate with operations which
+// are not owned by automation.
+func (ds DiffSet) HasUserDrift() bool {
+	for _, d := range ds {
+		if d.Type == DiffTypeCreate || (d.Type == DiffTypeUpdate && len(d.UserPatch()) > 0) {
 			return true
 		}
 	}
@@ -0,0 +1,99 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsondiff
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestUnstructuredList_TolerateForbidden(t *testing.T) {
+	newObject := func(kind, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind(kind)
+		obj.SetNamespace("default")
+		obj.SetName(name)
+		return obj
+	}
+	forbidden := func(resource, name string) error {
+		return apierrors.NewForbidden(schema.GroupResource{Resource: resource}, name, errors.New("access denied"))
+	}
+
+	// The reads of Secrets and the dry-run applies of ConfigMaps are
+	// forbidden, the ServiceAccounts are diffed.
+	kubeClient := fake.NewClientBuilder().WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if obj.GetObjectKind().GroupVersionKind().Kind == "Secret" {
+				return forbidden("secrets", key.Name)
+			}
+			return apierrors.NewNotFound(schema.GroupResource{}, key.Name)
+		},
+		Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+			if obj.GetObjectKind().GroupVersionKind().Kind == "ConfigMap" {
+				return forbidden("configmaps", obj.GetName())
+			}
+			return nil
+		},
+	}).Build()
+
+	objs := []*unstructured.Unstructured{
+		newObject("ServiceAccount", "app"),
+		newObject("Secret", "app"),
+		newObject("ConfigMap", "app"),
+	}
+
+	t.Run("fails without toleration", func(t *testing.T) {
+		g := NewWithT(t)
+
+		_, err := UnstructuredList(context.Background(), kubeClient, objs, FieldOwner(dummyFieldOwner))
+		g.Expect(apierrors.IsForbidden(err)).To(BeTrue())
+	})
+
+	t.Run("returns unknown diffs with toleration", func(t *testing.T) {
+		g := NewWithT(t)
+
+		set, err := UnstructuredList(context.Background(), kubeClient, objs,
+			FieldOwner(dummyFieldOwner), TolerateForbidden(true))
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(set).To(HaveLen(3))
+
+		g.Expect(set[0].Type).To(Equal(DiffTypeCreate))
+		g.Expect(set[0].Err).ToNot(HaveOccurred())
+
+		for _, d := range set[1:] {
+			g.Expect(d.Type).To(Equal(DiffTypeUnknown))
+			g.Expect(d.ClusterObject).To(BeNil())
+			g.Expect(apierrors.IsForbidden(d.Err)).To(BeTrue())
+		}
+		g.Expect(set[1].Err.Error()).To(ContainSubstring(`secrets "app" is forbidden`))
+		g.Expect(set[2].Err.Error()).To(ContainSubstring("ConfigMap/default/app dry-run failed"))
+
+		g.Expect(set.HasType(DiffTypeUnknown)).To(BeTrue())
+		g.Expect(set.HasChanges()).To(BeTrue())
+		g.Expect(RenderMarkdown(set)).To(HavePrefix("**1 change: 1 created, 0 updated (2 not compared, access forbidden)**\n"))
+	})
+}
@@ -42,6 +42,12 @@ type ResourceOptions struct {
 	MaskSecrets bool
 	// Rationalize enables rationalization of JSON operations in the diff.
 	Rationalize bool
+	// AutomationRules are the rules to classify the operations of the diff
+	// into changes made by the user or by automation.
+	AutomationRules []AutomationRule
+	// TolerateForbidden returns a Diff of type DiffTypeUnknown, instead of
+	// an error, when reading or dry-run applying the resource is forbidden.
+	TolerateForbidden bool
 }
 
 // ApplyOptions applies the given options on these options, and then returns
@@ -152,3 +158,94 @@ type Graceful bool
 func (f Graceful) ApplyToList(opts *ListOptions) {
 	opts.Graceful = bool(f)
 }
+
+// TolerateForbidden enables the toleration of the permission errors of a
+// server-side apply diff operation, e.g. with least-privilege credentials
+// missing the permissions to read some kinds. If enabled, a resource which
+// can't be read or dry-run applied because the request is forbidden results
+// in a Diff of type DiffTypeUnknown with the error, instead of failing the
+// whole operation.
+type TolerateForbidden bool
+
+// ApplyToResource applies this configuration to the given options.
+func (t TolerateForbidden) ApplyToResource(opts *ResourceOptions) {
+	opts.TolerateForbidden = bool(t)
+}
+
+// ApplyToList applies this configuration to the given options.
+func (t TolerateForbidden) ApplyToList(_ *ListOptions) {
+	// no-op
+}
+
+// ApplyToRender applies this configuration to the given options.
+func (m MaskSecrets) ApplyToRender(opts *RenderOptions) {
+	opts.MaskSecrets = bool(m)
+}
+
+// RenderOption is some configuration that modifies the rendering of a
+// DiffSet.
+type RenderOption interface {
+	ApplyToRender(options *RenderOptions)
+}
+
+// RenderOptions holds options for rendering a DiffSet.
+type RenderOptions struct {
+	// MaskSecrets enables masking of the data of Kubernetes Secrets in the
+	// rendered changes, even if the DiffSet was generated without it.
+	// Enabled by default.
+	MaskSecrets bool
+	// MaxObjects is the maximum number of objects rendered, the others are
+	// counted in a note. Zero means no limit.
+	MaxObjects int
+	// MaxOperations is the maximum number of operations rendered per
+	// object, the others are counted in a note. Zero means no limit.
+	MaxOperations int
+	// MaxValueLength is the maximum length of a rendered value, longer
+	// values are truncated. Zero means no limit.
+	MaxValueLength int
+	// Collapsible renders the changes of each object in a collapsible
+	// section. Enabled by default.
+	Collapsible bool
+}
+
+// ApplyOptions applies the given options on these options, and then returns
+// itself (for convenient chaining).
+func (o *RenderOptions) ApplyOptions(opts []RenderOption) *RenderOptions {
+	for _, opt := range opts {
+		opt.ApplyToRender(o)
+	}
+	return o
+}
+
+// MaxObjects sets the maximum number of objects rendered.
+type MaxObjects int
+
+// ApplyToRender applies this configuration to the given options.
+func (m MaxObjects) ApplyToRender(opts *RenderOptions) {
+	opts.MaxObjects = int(m)
+}
+
+// MaxOperations sets the maximum number of operations rendered per object.
+type MaxOperations int
+
+// ApplyToRender applies this configuration to the given options.
+func (m MaxOperations) ApplyToRender(opts *RenderOptions) {
+	opts.MaxOperations = int(m)
+}
+
+// MaxValueLength sets the maximum length of a rendered value.
+type MaxValueLength int
+
+// ApplyToRender applies this configuration to the given options.
+func (m MaxValueLength) ApplyToRender(opts *RenderOptions) {
+	opts.MaxValueLength = int(m)
+}
+
+// Collapsible sets the flag to render the changes of each object in a
+// collapsible section.
+type Collapsible bool
+
+// ApplyToRender applies this configuration to the given options.
+func (c Collapsible) ApplyToRender(opts *RenderOptions) {
+	opts.Collapsible = bool(c)
+}
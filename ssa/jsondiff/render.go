@@ -0,0 +1,300 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsondiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+
+	"github.com/wI2L/jsondiff"
+)
+
+// RenderMarkdown renders the changes of the DiffSet as markdown, e.g. for
+// pull request comments or notifications. The objects which are unchanged
+// or excluded are not rendered.
+func RenderMarkdown(ds DiffSet, opts ...RenderOption) string {
+	r := newRendering(ds, opts)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "**%s**\n", r.summary)
+	for _, obj := range r.objects {
+		b.WriteString("\n")
+		title := fmt.Sprintf("<code>%s</code> %s", html.EscapeString(obj.ref), obj.action)
+		if len(obj.lines) == 0 {
+			fmt.Fprintf(&b, "%s\n", title)
+			continue
+		}
+		if r.options.Collapsible {
+			fmt.Fprintf(&b, "<details>\n<summary>%s</summary>\n\n", title)
+		} else {
+			fmt.Fprintf(&b, "#### `%s` %s\n\n", obj.ref, obj.action)
+		}
+		b.WriteString("```diff\n")
+		for _, l := range obj.lines {
+			fmt.Fprintf(&b, "%s\n", l.text)
+		}
+		b.WriteString("```\n")
+		if r.options.Collapsible {
+			b.WriteString("\n</details>\n")
+		}
+	}
+	if r.omitted > 0 {
+		fmt.Fprintf(&b, "\n_... and %d more objects_\n", r.omitted)
+	}
+	return b.String()
+}
+
+// RenderHTML renders the changes of the DiffSet as minimal HTML, e.g. for
+// dashboards. The lines of the changes are wrapped in spans with the class
+// "add", "remove" or "change" for styling. The objects which are unchanged
+// or excluded are not rendered.
+func RenderHTML(ds DiffSet, opts ...RenderOption) string {
+	r := newRendering(ds, opts)
+
+	var b strings.Builder
+	b.WriteString("<div class=\"diffset\">\n")
+	fmt.Fprintf(&b, "<p>%s</p>\n", html.EscapeString(r.summary))
+	for _, obj := range r.objects {
+		title := fmt.Sprintf("<code>%s</code> %s", html.EscapeString(obj.ref), obj.action)
+		if len(obj.lines) == 0 {
+			fmt.Fprintf(&b, "<p>%s</p>\n", title)
+			continue
+		}
+		if r.options.Collapsible {
+			fmt.Fprintf(&b, "<details>\n<summary>%s</summary>\n", title)
+		} else {
+			fmt.Fprintf(&b, "<h4>%s</h4>\n", title)
+		}
+		b.WriteString("<pre>")
+		for i, l := range obj.lines {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if l.class == "" {
+				b.WriteString(html.EscapeString(l.text))
+				continue
+			}
+			fmt.Fprintf(&b, "<span class=\"%s\">%s</span>", l.class, html.EscapeString(l.text))
+		}
+		b.WriteString("</pre>\n")
+		if r.options.Collapsible {
+			b.WriteString("</details>\n")
+		}
+	}
+	if r.omitted > 0 {
+		fmt.Fprintf(&b, "<p>... and %d more objects</p>\n", r.omitted)
+	}
+	b.WriteString("</div>\n")
+	return b.String()
+}
+
+// rendering holds the changes of a DiffSet in a format independent way.
+type rendering struct {
+	options RenderOptions
+	summary string
+	objects []renderedObject
+	omitted int
+}
+
+// renderedObject holds the changes of a single object.
+type renderedObject struct {
+	ref    string
+	action string
+	lines  []renderedLine
+}
+
+// renderedLine is a single line of the changes of an object, with the class
+// used to style it in HTML.
+type renderedLine struct {
+	class string
+	text  string
+}
+
+func newRendering(ds DiffSet, opts []RenderOption) *rendering {
+	r := &rendering{
+		options: RenderOptions{
+			MaskSecrets: true,
+			Collapsible: true,
+		},
+	}
+	r.options.ApplyOptions(opts)
+
+	var created, updated, deleted, unknown int
+	for _, d := range ds {
+		var action string
+		switch d.Type {
+		case DiffTypeUnknown:
+			unknown++
+			continue
+		case DiffTypeCreate:
+			created++
+			action = "created"
+		case DiffTypeUpdate:
+			updated++
+			action = "updated"
+		case DiffTypeDelete:
+			deleted++
+			action = "deleted"
+		default:
+			continue
+		}
+
+		if r.options.MaxObjects > 0 && len(r.objects) >= r.options.MaxObjects {
+			r.omitted++
+			continue
+		}
+		r.objects = append(r.objects, renderedObject{
+			ref:    diffRef(d),
+			action: action,
+			lines:  r.renderPatch(d),
+		})
+	}
+
+	switch total := created + updated + deleted; total {
+	case 0:
+		r.summary = "No changes"
+	case 1:
+		r.summary = fmt.Sprintf("1 change: %d created, %d updated", created, updated)
+	default:
+		r.summary = fmt.Sprintf("%d changes: %d created, %d updated", total, created, updated)
+	}
+	if deleted > 0 {
+		r.summary += fmt.Sprintf(", %d deleted", deleted)
+	}
+	if unknown > 0 {
+		r.summary += fmt.Sprintf(" (%d not compared, access forbidden)", unknown)
+	}
+	return r
+}
+
+// renderPatch returns the lines of the operations of the patch of the Diff.
+func (r *rendering) renderPatch(d *Diff) []renderedLine {
+	gvk := d.GroupVersionKind()
+	mask := r.options.MaskSecrets && gvk.Group == "" && gvk.Kind == "Secret"
+
+	var lines []renderedLine
+	var rendered, omitted int
+	for _, op := range d.Patch {
+		if op.Type == jsondiff.OperationTest {
+			continue
+		}
+		if r.options.MaxOperations > 0 && rendered >= r.options.MaxOperations {
+			omitted++
+			continue
+		}
+		rendered++
+
+		oldValue, value := op.OldValue, op.Value
+		if mask {
+			oldValue, value = maskValue(op.Path, oldValue), maskValue(op.Path, value)
+		}
+
+		switch op.Type {
+		case jsondiff.OperationAdd:
+			lines = append(lines, renderedLine{"add", fmt.Sprintf("+ %s: %s", op.Path, r.formatValue(value))})
+		case jsondiff.OperationRemove:
+			if oldValue == nil {
+				lines = append(lines, renderedLine{"remove", fmt.Sprintf("- %s", op.Path)})
+				break
+			}
+			lines = append(lines, renderedLine{"remove", fmt.Sprintf("- %s: %s", op.Path, r.formatValue(oldValue))})
+		case jsondiff.OperationReplace:
+			if oldValue != nil {
+				lines = append(lines, renderedLine{"remove", fmt.Sprintf("- %s: %s", op.Path, r.formatValue(oldValue))})
+			}
+			lines = append(lines, renderedLine{"add", fmt.Sprintf("+ %s: %s", op.Path, r.formatValue(value))})
+		case jsondiff.OperationMove:
+			lines = append(lines, renderedLine{"change", fmt.Sprintf("~ %s: moved from %s", op.Path, op.From)})
+		case jsondiff.OperationCopy:
+			lines = append(lines, renderedLine{"change", fmt.Sprintf("~ %s: copied from %s", op.Path, op.From)})
+		}
+	}
+	if omitted > 0 {
+		lines = append(lines, renderedLine{"", fmt.Sprintf("  ... and %d more operations", omitted)})
+	}
+	return lines
+}
+
+// formatValue returns the JSON representation of the value, truncated to
+// the maximum value length.
+func (r *rendering) formatValue(v interface{}) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	s := []rune(strings.TrimSuffix(b.String(), "\n"))
+	if r.options.MaxValueLength > 0 && len(s) > r.options.MaxValueLength {
+		return string(s[:r.options.MaxValueLength]) + "..."
+	}
+	return string(s)
+}
+
+// maskValue returns the value of the given Secret path with the data
+// replaced with a mask value. It does not modify the given value, and keeps
+// the mask values set by MaskSecretPatchData.
+func maskValue(path string, v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	switch {
+	case path == "/data" || path == "/stringData":
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return sensitiveMaskDefault
+		}
+		masked := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			masked[k] = maskScalar(v)
+		}
+		return masked
+	case strings.HasPrefix(path, "/data/") || strings.HasPrefix(path, "/stringData/"):
+		return maskScalar(v)
+	case path == "" || path == "/":
+		// The whole object is replaced.
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		masked := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			masked[k] = maskValue("/"+k, v)
+		}
+		return masked
+	}
+	return v
+}
+
+func maskScalar(v interface{}) interface{} {
+	switch v {
+	case sensitiveMaskDefault, sensitiveMaskBefore, sensitiveMaskAfter:
+		return v
+	}
+	return sensitiveMaskDefault
+}
+
+// diffRef returns the reference of the object of the Diff, in the format
+// 'Kind/namespace/name'.
+func diffRef(d *Diff) string {
+	if ns := d.GetNamespace(); ns != "" {
+		return fmt.Sprintf("%s/%s/%s", d.GroupVersionKind().Kind, ns, d.GetName())
+	}
+	return fmt.Sprintf("%s/%s", d.GroupVersionKind().Kind, d.GetName())
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsondiff
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/wI2L/jsondiff"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newRenderTestDiffSet() DiffSet {
+	newObject := func(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(apiVersion)
+		obj.SetKind(kind)
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		return obj
+	}
+
+	deployment := newObject("apps/v1", "Deployment", "apps", "app")
+	secret := newObject("v1", "Secret", "apps", "app")
+	return DiffSet{
+		NewDiffForUnstructured(newObject("v1", "Namespace", "", "apps"), nil, DiffTypeNone, nil),
+		NewDiffForUnstructured(deployment, deployment, DiffTypeUpdate, jsondiff.Patch{
+			{Type: jsondiff.OperationReplace, Path: "/spec/replicas", OldValue: 1, Value: 2},
+			{Type: jsondiff.OperationAdd, Path: "/metadata/labels/team", Value: "<dev>"},
+			{Type: jsondiff.OperationRemove, Path: "/spec/paused", OldValue: true},
+		}),
+		NewDiffForUnstructured(secret, secret, DiffTypeUpdate, jsondiff.Patch{
+			{Type: jsondiff.OperationReplace, Path: "/data/token", OldValue: "b2xk", Value: "bmV3"},
+			{Type: jsondiff.OperationAdd, Path: "/data", Value: map[string]interface{}{"key": "dmFsdWU="}},
+		}),
+		NewDiffForUnstructured(newObject("v1", "ConfigMap", "apps", "app"), nil, DiffTypeCreate, nil),
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	g := NewWithT(t)
+
+	out := RenderMarkdown(newRenderTestDiffSet())
+	g.Expect(out).To(Equal("**3 changes: 1 created, 2 updated**\n" +
+		"\n<details>\n<summary><code>Deployment/apps/app</code> updated</summary>\n\n" +
+		"```diff\n" +
+		"- /spec/replicas: 1\n" +
+		"+ /spec/replicas: 2\n" +
+		"+ /metadata/labels/team: \"<dev>\"\n" +
+		"- /spec/paused: true\n" +
+		"```\n" +
+		"\n</details>\n" +
+		"\n<details>\n<summary><code>Secret/apps/app</code> updated</summary>\n\n" +
+		"```diff\n" +
+		"- /data/token: \"***\"\n" +
+		"+ /data/token: \"***\"\n" +
+		"+ /data: {\"key\":\"***\"}\n" +
+		"```\n" +
+		"\n</details>\n" +
+		"\n<code>ConfigMap/apps/app</code> created\n"))
+	g.Expect(out).ToNot(ContainSubstring("bmV3"))
+}
+
+func TestRenderMarkdown_Truncation(t *testing.T) {
+	g := NewWithT(t)
+
+	out := RenderMarkdown(newRenderTestDiffSet(),
+		MaxObjects(1), MaxOperations(1), MaxValueLength(2), Collapsible(false))
+	g.Expect(out).To(Equal("**3 changes: 1 created, 2 updated**\n" +
+		"\n#### `Deployment/apps/app` updated\n\n" +
+		"```diff\n" +
+		"- /spec/replicas: 1\n" +
+		"+ /spec/replicas: 2\n" +
+		"  ... and 2 more operations\n" +
+		"```\n" +
+		"\n_... and 2 more objects_\n"))
+}
+
+func TestRenderHTML(t *testing.T) {
+	g := NewWithT(t)
+
+	out := RenderHTML(newRenderTestDiffSet(), MaxObjects(2), MaskSecrets(false))
+	g.Expect(out).To(Equal("<div class=\"diffset\">\n" +
+		"<p>3 changes: 1 created, 2 updated</p>\n" +
+		"<details>\n<summary><code>Deployment/apps/app</code> updated</summary>\n" +
+		"<pre><span class=\"remove\">- /spec/replicas: 1</span>\n" +
+		"<span class=\"add\">+ /spec/replicas: 2</span>\n" +
+		"<span class=\"add\">+ /metadata/labels/team: &#34;&lt;dev&gt;&#34;</span>\n" +
+		"<span class=\"remove\">- /spec/paused: true</span></pre>\n" +
+		"</details>\n" +
+		"<details>\n<summary><code>Secret/apps/app</code> updated</summary>\n" +
+		"<pre><span class=\"remove\">- /data/token: &#34;b2xk&#34;</span>\n" +
+		"<span class=\"add\">+ /data/token: &#34;bmV3&#34;</span>\n" +
+		"<span class=\"add\">+ /data: {&#34;key&#34;:&#34;dmFsdWU=&#34;}</span></pre>\n" +
+		"</details>\n" +
+		"<p>... and 1 more objects</p>\n" +
+		"</div>\n"))
+}
+
+func TestRenderMarkdown_NoChanges(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(RenderMarkdown(DiffSet{})).To(Equal("**No changes**\n"))
+}
+
+func TestRenderMarkdown_Deleted(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("apps")
+	obj.SetName("stale")
+	ds := append(newRenderTestDiffSet(), NewDiffForUnstructured(obj, obj, DiffTypeDelete, nil))
+
+	out := RenderMarkdown(ds)
+	g.Expect(out).To(HavePrefix("**4 changes: 1 created, 2 updated, 1 deleted**\n"))
+	g.Expect(out).To(HaveSuffix("\n<code>ConfigMap/apps/stale</code> deleted\n"))
+	g.Expect(ds.HasChanges()).To(BeTrue())
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/wI2L/jsondiff"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -115,6 +116,14 @@ func UnstructuredList(ctx context.Context, c client.Client, objs []*unstructured
 //
 // When the object is excluded using an ExclusionSelector or an
 // IgnorePathRoot, the DiffType is DiffTypeExclude.
+//
+// When TolerateForbidden is passed as an option, and reading or dry-run
+// applying the object is forbidden, the DiffType is DiffTypeUnknown and the
+// error is set in the Err field of the Diff.
+//
+// When AutomationRules are passed as an option, the operations of the Patch
+// are classified into changes made by the user or by automation, e.g. a
+// HorizontalPodAutoscaler, see Diff.Owners.
 func Unstructured(ctx context.Context, c client.Client, obj *unstructured.Unstructured, opts ...ResourceOption) (*Diff, error) {
 	o := &ResourceOptions{}
 	o.ApplyOptions(opts)
@@ -134,6 +143,9 @@ func Unstructured(ctx context.Context, c client.Client, obj *unstructured.Unstru
 	existingObj := &unstructured.Unstructured{}
 	existingObj.SetGroupVersionKind(obj.GroupVersionKind())
 	if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existingObj); client.IgnoreNotFound(err) != nil {
+		if o.TolerateForbidden && apierrors.IsForbidden(err) {
+			return NewUnknownDiffForUnstructured(obj, err), nil
+		}
 		return nil, err
 	}
 
@@ -149,13 +161,43 @@ func Unstructured(ctx context.Context, c client.Client, obj *unstructured.Unstru
 		client.FieldOwner(o.FieldManager),
 	}
 	if err := c.Patch(ctx, dryRunObj, client.Apply, patchOpts...); err != nil {
+		if o.TolerateForbidden && apierrors.IsForbidden(err) {
+			return NewUnknownDiffForUnstructured(obj, ssaerrors.NewDryRunErr(err, obj)), nil
+		}
 		return nil, ssaerrors.NewDryRunErr(err, obj)
 	}
 
-	if dryRunObj.GetResourceVersion() == "" {
+	return UnstructuredFromDryRun(obj, existingObj, dryRunObj, opts...)
+}
+
+// UnstructuredFromDryRun compares the result of a server-side apply dry-run
+// of the object against the object in the cluster, without making any
+// request. It returns the same Diff as Unstructured would for the given
+// in-cluster and dry-run objects, which allows the callers dry-run applying
+// the object themselves to compute the Diff from the same dry-run. The
+// existing object is nil or empty if the object doesn't exist in the cluster.
+// The given objects are not modified.
+func UnstructuredFromDryRun(obj, existingObj, dryRunObj *unstructured.Unstructured, opts ...ResourceOption) (*Diff, error) {
+	o := &ResourceOptions{}
+	o.ApplyOptions(opts)
+
+	if utils.AnyInMetadata(obj, o.ExclusionSelector) {
+		return NewDiffForUnstructured(obj, nil, DiffTypeExclude, nil), nil
+	}
+	for _, p := range o.IgnorePaths {
+		if p == IgnorePathRoot {
+			return NewDiffForUnstructured(obj, nil, DiffTypeExclude, nil), nil
+		}
+	}
+	if existingObj != nil && utils.AnyInMetadata(existingObj, o.ExclusionSelector) {
+		return NewDiffForUnstructured(obj, nil, DiffTypeExclude, nil), nil
+	}
+
+	if existingObj == nil || dryRunObj.GetResourceVersion() == "" {
 		return NewDiffForUnstructured(obj, nil, DiffTypeCreate, nil), nil
 	}
 
+	dryRunObj = dryRunObj.DeepCopy()
 	if err := normalize.DryRunUnstructured(dryRunObj); err != nil {
 		return nil, err
 	}
@@ -201,7 +243,12 @@ func Unstructured(ctx context.Context, c client.Client, obj *unstructured.Unstru
 			patch = MaskSecretPatchData(patch)
 		}
 	}
-	return NewDiffForUnstructured(obj, existingObj, DiffTypeUpdate, patch), nil
+
+	diff := NewDiffForUnstructured(obj, existingObj, DiffTypeUpdate, patch)
+	if len(o.AutomationRules) > 0 {
+		diff.Owners = ClassifyPatch(existingObj, patch, o.AutomationRules)
+	}
+	return diff, nil
 }
 
 // diffUnstructuredMetadata returns a JSON patch with the differences between
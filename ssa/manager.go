@@ -33,6 +33,8 @@ type ResourceManager struct {
 	poller      *polling.StatusPoller
 	owner       Owner
 	concurrency int
+	metrics     *Metrics
+	attribution Attribution
 }
 
 // NewResourceManager creates a ResourceManager for the given Kubernetes client.
@@ -58,6 +60,32 @@ func (m *ResourceManager) SetConcurrency(c int) {
 	m.concurrency = c
 }
 
+// SetMetrics sets the metrics the duration of the apply, diff and wait
+// operations, the dry-run failures, the conflicts and the wait timeouts are
+// recorded with. Metrics are not recorded by default.
+func (m *ResourceManager) SetMetrics(metrics *Metrics) {
+	m.metrics = metrics
+}
+
+// SetAttribution sets the impersonated identity and the target cluster
+// recorded in the ChangeSet and its entries, for the controllers using
+// the ResourceManager with an impersonating or remote client to emit
+// unambiguous audit data. The field manager defaults to the one of the
+// Owner.
+func (m *ResourceManager) SetAttribution(attribution Attribution) {
+	m.attribution = attribution
+}
+
+// Attribution returns the Attribution recorded in the ChangeSet and its
+// entries.
+func (m *ResourceManager) Attribution() Attribution {
+	a := m.attribution
+	if a.FieldManager == "" {
+		a.FieldManager = m.owner.Field
+	}
+	return a
+}
+
 // SetOwnerLabels adds the ownership labels to the given objects.
 // The ownership labels are in the format:
 //
@@ -91,5 +119,14 @@ func (m *ResourceManager) changeSetEntry(o *unstructured.Unstructured, action Ac
 		GroupVersion: o.GroupVersionKind().Version,
 		Subject:      utils.FmtUnstructured(o),
 		Action:       action,
+		Attribution:  m.Attribution(),
 	}
 }
+
+// newChangeSet returns an empty ChangeSet with the Attribution of the
+// ResourceManager.
+func (m *ResourceManager) newChangeSet() *ChangeSet {
+	changeSet := NewChangeSet()
+	changeSet.Attribution = m.Attribution()
+	return changeSet
+}
@@ -26,6 +26,7 @@ import (
 
 	"golang.org/x/sync/errgroup"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
@@ -36,6 +37,13 @@ import (
 	"github.com/fluxcd/pkg/ssa/utils"
 )
 
+// ReplaceAnnotation is the annotation which, when set to 'true' on an
+// object, makes the manager replace the in-cluster object instead of
+// patching it whenever it has drifted, by deleting it, waiting for its
+// termination, and creating it again. It allows changing immutable fields,
+// such as the selector of a Deployment, within a single apply.
+const ReplaceAnnotation = "fluxcd.io/replace"
+
 // ApplyOptions contains options for server-side apply requests.
 type ApplyOptions struct {
 	// Force configures the engine to recreate objects that contain immutable field changes.
@@ -63,6 +71,11 @@ type ApplyOptions struct {
 
 	// Cleanup defines which in-cluster metadata entries are to be removed before applying objects.
 	Cleanup ApplyCleanupOptions `json:"cleanup"`
+
+	// Progress is called by ApplyAll for each object once it has been applied,
+	// or once its server-side dry-run found nothing to apply. The objects
+	// applied in stages by ApplyAllStaged and ApplyWithCRDs are reported per stage.
+	Progress ProgressFunc `json:"-"`
 }
 
 // ApplyCleanupOptions defines which metadata entries are to be removed before applying objects.
@@ -95,6 +108,13 @@ func DefaultApplyOptions() ApplyOptions {
 // Drift detection is performed by comparing the server-side dry-run result with the existing object.
 // When immutable field changes are detected, the object is recreated if 'force' is set to 'true'.
 func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstructured, opts ApplyOptions) (*ChangeSetEntry, error) {
+	start := time.Now()
+	entry, err := m.applyObject(ctx, object, opts)
+	m.metrics.recordOperation(OperationApply, object.GroupVersionKind().GroupKind(), entry, err, start)
+	return entry, err
+}
+
+func (m *ResourceManager) applyObject(ctx context.Context, object *unstructured.Unstructured, opts ApplyOptions) (*ChangeSetEntry, error) {
 	existingObject := &unstructured.Unstructured{}
 	existingObject.SetGroupVersionKind(object.GroupVersionKind())
 	getError := m.client.Get(ctx, client.ObjectKeyFromObject(object), existingObject)
@@ -104,13 +124,22 @@ func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstru
 	}
 
 	dryRunObject := object.DeepCopy()
-	if err := m.dryRunApply(ctx, dryRunObject); err != nil {
+	dryRunErr := m.dryRunApply(ctx, dryRunObject)
+	if getError == nil && m.shouldReplaceObject(object, existingObject, dryRunObject, dryRunErr) {
+		appliedObject := object.DeepCopy()
+		if err := m.replace(ctx, appliedObject, existingObject, opts); err != nil {
+			return nil, err
+		}
+		return m.changeSetEntry(appliedObject, ReplacedAction), nil
+	}
+
+	if err := dryRunErr; err != nil {
 		if !errors.IsNotFound(getError) && m.shouldForceApply(object, existingObject, opts, err) {
 			if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
 				return nil, fmt.Errorf("%s immutable field detected, failed to delete object: %w",
 					utils.FmtUnstructured(dryRunObject), err)
 			}
-			return m.Apply(ctx, object, opts)
+			return m.applyObject(ctx, object, opts)
 		}
 
 		return nil, ssaerrors.NewDryRunErr(err, dryRunObject)
@@ -140,15 +169,22 @@ func (m *ResourceManager) Apply(ctx context.Context, object *unstructured.Unstru
 }
 
 // ApplyAll performs a server-side dry-run of the given objects, and based on the diff result,
-// it applies the objects that are new or modified.
+// it applies the objects that are new or modified. The objects are applied in the order of their
+// kind, and then of their ApplyWeightAnnotation.
 func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.Unstructured, opts ApplyOptions) (*ChangeSet, error) {
-	sort.Sort(SortableUnstructureds(objects))
+	sorted, err := newWeightedUnstructureds(objects)
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sorted)
 
 	// Results are written to the following arrays from the concurrent goroutines. We use arrays
 	// to avoid complex synchronization. toApply is sparse, slots are only popuplated when there
 	// is an object to apply
 	toApply := make([]*unstructured.Unstructured, len(objects))
+	toReplace := make([]*unstructured.Unstructured, len(objects))
 	changes := make([]ChangeSetEntry, len(objects))
+	durations := make([]time.Duration, len(objects))
 
 	{
 		g, ctx := errgroup.WithContext(ctx)
@@ -157,6 +193,11 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 			i, object := i, object
 
 			g.Go(func() error {
+				start := time.Now()
+				defer func() {
+					durations[i] = time.Since(start)
+				}()
+
 				existingObject := &unstructured.Unstructured{}
 				existingObject.SetGroupVersionKind(object.GroupVersionKind())
 				getError := m.client.Get(ctx, client.ObjectKeyFromObject(object), existingObject)
@@ -167,7 +208,15 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 				}
 
 				dryRunObject := object.DeepCopy()
-				if err := m.dryRunApply(ctx, dryRunObject); err != nil {
+				dryRunErr := m.dryRunApply(ctx, dryRunObject)
+				if getError == nil && m.shouldReplaceObject(object, existingObject, dryRunObject, dryRunErr) {
+					toApply[i] = object
+					toReplace[i] = existingObject
+					changes[i] = *m.changeSetEntry(object, ReplacedAction)
+					return nil
+				}
+
+				if err := dryRunErr; err != nil {
 					// We cannot have an immutable error (and therefore shouldn't force-apply) if the resource doesn't
 					// exist on the cluster. Note that resource might not exist because we wrongly identified an error
 					// as immutable and deleted it when ApplyAll was called the last time (the check for ImmutableError
@@ -226,27 +275,118 @@ func (m *ResourceManager) ApplyAll(ctx context.Context, objects []*unstructured.
 		}
 	}
 
-	for _, object := range toApply {
+	for i, object := range toApply {
 		if object != nil {
-			appliedObject := object.DeepCopy()
-			if err := m.apply(ctx, appliedObject); err != nil {
-				return nil, fmt.Errorf("%s apply failed: %w", utils.FmtUnstructured(appliedObject), err)
+			start := time.Now()
+			if err := m.applyOrReplace(ctx, object.DeepCopy(), toReplace[i], opts); err != nil {
+				m.metrics.recordOperation(OperationApply, object.GroupVersionKind().GroupKind(), nil, err, start.Add(-durations[i]))
+				opts.Progress.report(i, len(objects), changes[i], durations[i]+time.Since(start), err)
+				return nil, err
 			}
+			durations[i] += time.Since(start)
 		}
+		opts.Progress.report(i, len(objects), changes[i], durations[i], nil)
+	}
+
+	now := time.Now()
+	for i, object := range objects {
+		m.metrics.recordOperation(OperationApply, object.GroupVersionKind().GroupKind(), &changes[i], nil, now.Add(-durations[i]))
 	}
 
-	changeSet := NewChangeSet()
+	changeSet := m.newChangeSet()
 	changeSet.Append(changes)
 
 	return changeSet, nil
 }
 
+// applyOrReplace server-side applies the given object, or replaces the
+// existing object with it if the latter is not nil.
+func (m *ResourceManager) applyOrReplace(ctx context.Context, object, existingObject *unstructured.Unstructured, opts ApplyOptions) error {
+	if existingObject != nil {
+		return m.replace(ctx, object, existingObject, opts)
+	}
+	if err := m.apply(ctx, object); err != nil {
+		return fmt.Errorf("%s apply failed: %w", utils.FmtUnstructured(object), err)
+	}
+	return nil
+}
+
+// shouldReplace returns true if the given object has the ReplaceAnnotation
+// set to 'true'.
+func shouldReplace(object *unstructured.Unstructured) bool {
+	return object.GetAnnotations()[ReplaceAnnotation] == "true"
+}
+
+// shouldReplaceObject returns true if the given object has the
+// ReplaceAnnotation set to 'true', and either its dry-run failed due to an
+// immutable field change or the in-cluster object has drifted. Any other
+// dry-run error, such as an admission webhook denial, is not a reason to
+// replace the object, as the new object would be rejected as well once the
+// in-cluster object has been deleted.
+func (m *ResourceManager) shouldReplaceObject(object, existingObject, dryRunObject *unstructured.Unstructured, dryRunErr error) bool {
+	if !shouldReplace(object) {
+		return false
+	}
+	if dryRunErr != nil {
+		return ssaerrors.IsImmutableError(dryRunErr)
+	}
+	return m.hasDrifted(existingObject, dryRunObject)
+}
+
+// replace deletes the existing object, waits for it to be terminated, and
+// server-side applies the given object.
+func (m *ResourceManager) replace(ctx context.Context, object, existingObject *unstructured.Unstructured, opts ApplyOptions) error {
+	if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("%s replace failed, failed to delete object: %w",
+			utils.FmtUnstructured(object), err)
+	}
+
+	// Wait until deleted (in case of any finalizers).
+	err := wait.PollUntilContextTimeout(ctx, opts.WaitInterval, opts.WaitTimeout, true, func(ctx context.Context) (bool, error) {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(existingObject.GroupVersionKind())
+		err := m.client.Get(ctx, client.ObjectKeyFromObject(existingObject), obj)
+		if err != nil && errors.IsNotFound(err) {
+			return true, nil
+		}
+		// Object still exists, or we got another error than NotFound.
+		return false, err
+	})
+	if err != nil {
+		return fmt.Errorf("%s replace failed, failed to wait for object to be deleted: %w",
+			utils.FmtUnstructured(object), err)
+	}
+
+	if err := m.apply(ctx, object); err != nil {
+		return fmt.Errorf("%s apply failed: %w", utils.FmtUnstructured(object), err)
+	}
+	return nil
+}
+
 // ApplyAllStaged extracts the CRDs and Namespaces, applies them with ApplyAll,
 // waits for CRDs and Namespaces to become ready, then is applies all the other objects.
 // This function should be used when the given objects have a mix of custom resource definition and custom resources,
 // or a mix of namespace definitions with namespaced objects.
+// The ApplyWeightAnnotation adjusts the order of the objects of the same kind within each stage.
 func (m *ResourceManager) ApplyAllStaged(ctx context.Context, objects []*unstructured.Unstructured, opts ApplyOptions) (*ChangeSet, error) {
-	changeSet := NewChangeSet()
+	return m.applyAllStaged(ctx, objects, opts, false)
+}
+
+// ApplyWithCRDs applies the given objects in stages like ApplyAllStaged.
+// In addition, it waits for the CRDs to be established, resets the
+// RESTMapper of the client, and retries applying the other objects for as
+// long as the API server reports no match for their kind, until
+// opts.WaitTimeout. This function should be used when the given objects
+// contain custom resources of which the definitions are part of the same set.
+func (m *ResourceManager) ApplyWithCRDs(ctx context.Context, objects []*unstructured.Unstructured, opts ApplyOptions) (*ChangeSet, error) {
+	return m.applyAllStaged(ctx, objects, opts, true)
+}
+
+// applyAllStaged applies the CRDs and Namespaces, waits for them to become
+// ready, then applies all the other objects. If discover is true, the kinds
+// of the CRDs are discovered before applying the other objects.
+func (m *ResourceManager) applyAllStaged(ctx context.Context, objects []*unstructured.Unstructured, opts ApplyOptions, discover bool) (*ChangeSet, error) {
+	changeSet := m.newChangeSet()
 
 	// contains only CRDs and Namespaces
 	var stageOne []*unstructured.Unstructured
@@ -254,14 +394,19 @@ func (m *ResourceManager) ApplyAllStaged(ctx context.Context, objects []*unstruc
 	// contains all objects except for CRDs and Namespaces
 	var stageTwo []*unstructured.Unstructured
 
+	var crds []*unstructured.Unstructured
 	for _, u := range objects {
 		if utils.IsClusterDefinition(u) {
 			stageOne = append(stageOne, u)
 		} else {
 			stageTwo = append(stageTwo, u)
 		}
+		if utils.IsCRD(u) {
+			crds = append(crds, u)
+		}
 	}
 
+	waitOpts := WaitOptions{Interval: opts.WaitInterval, Timeout: opts.WaitTimeout}
 	if len(stageOne) > 0 {
 		cs, err := m.ApplyAll(ctx, stageOne, opts)
 		if err != nil {
@@ -269,27 +414,132 @@ func (m *ResourceManager) ApplyAllStaged(ctx context.Context, objects []*unstruc
 		}
 		changeSet.Append(cs.Entries)
 
-		if err := m.Wait(stageOne, WaitOptions{opts.WaitInterval, opts.WaitTimeout, false}); err != nil {
+		if err := m.Wait(stageOne, waitOpts); err != nil {
 			return nil, err
 		}
 	}
 
-	cs, err := m.ApplyAll(ctx, stageTwo, opts)
-	if err != nil {
-		return nil, err
+	if !discover {
+		cs, err := m.ApplyAll(ctx, stageTwo, opts)
+		if err != nil {
+			return nil, err
+		}
+		changeSet.Append(cs.Entries)
+		return changeSet, nil
+	}
+
+	if len(crds) > 0 {
+		if err := m.WaitForEstablished(ctx, crds, waitOpts); err != nil {
+			return nil, err
+		}
+		m.resetRESTMapper()
+	}
+
+	if len(stageTwo) == 0 {
+		return changeSet, nil
+	}
+
+	var cs *ChangeSet
+	var applyErr error
+	waitErr := wait.PollUntilContextTimeout(ctx, opts.WaitInterval, opts.WaitTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			cs, applyErr = m.ApplyAll(ctx, stageTwo, opts)
+			if applyErr == nil {
+				return true, nil
+			}
+			if meta.IsNoMatchError(applyErr) {
+				m.resetRESTMapper()
+				return false, nil
+			}
+			return false, applyErr
+		})
+	if waitErr != nil {
+		if applyErr != nil {
+			return nil, applyErr
+		}
+		return nil, waitErr
 	}
 	changeSet.Append(cs.Entries)
 
 	return changeSet, nil
 }
 
+// WaitForEstablished waits for the given CRDs to have the Established and
+// NamesAccepted conditions set to True.
+func (m *ResourceManager) WaitForEstablished(ctx context.Context, crds []*unstructured.Unstructured, opts WaitOptions) error {
+	pending := make(map[string]*unstructured.Unstructured, len(crds))
+	for _, crd := range crds {
+		pending[crd.GetName()] = crd
+	}
+
+	var lastErr error
+	err := wait.PollUntilContextTimeout(ctx, opts.Interval, opts.Timeout, true,
+		func(ctx context.Context) (bool, error) {
+			for name, crd := range pending {
+				existing := &unstructured.Unstructured{}
+				existing.SetGroupVersionKind(crd.GroupVersionKind())
+				if err := m.client.Get(ctx, client.ObjectKeyFromObject(crd), existing); err != nil {
+					lastErr = fmt.Errorf("%s: %w", utils.FmtUnstructured(crd), err)
+					return false, nil
+				}
+				if !isEstablished(existing) {
+					lastErr = fmt.Errorf("%s is not established", utils.FmtUnstructured(crd))
+					return false, nil
+				}
+				delete(pending, name)
+			}
+			return true, nil
+		})
+	if err != nil {
+		if lastErr != nil {
+			return fmt.Errorf("timeout waiting for CRDs to be established: %w", lastErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// isEstablished returns true if the given CRD has the Established and
+// NamesAccepted conditions set to True.
+func isEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	var established, namesAccepted bool
+	for _, c := range conditions {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["status"] != string(metav1.ConditionTrue) {
+			continue
+		}
+		switch cond["type"] {
+		case "Established":
+			established = true
+		case "NamesAccepted":
+			namesAccepted = true
+		}
+	}
+	return established && namesAccepted
+}
+
+// resetRESTMapper resets the RESTMapper of the client, if it supports it,
+// so that the kinds of newly established CRDs are discovered.
+func (m *ResourceManager) resetRESTMapper() {
+	if mapper, ok := m.client.RESTMapper().(meta.ResettableRESTMapper); ok {
+		mapper.Reset()
+	}
+}
+
 func (m *ResourceManager) dryRunApply(ctx context.Context, object *unstructured.Unstructured) error {
 	opts := []client.PatchOption{
 		client.DryRunAll,
 		client.ForceOwnership,
 		client.FieldOwner(m.owner.Field),
 	}
-	return m.client.Patch(ctx, object, client.Apply, opts...)
+	gk := object.GroupVersionKind().GroupKind()
+	err := m.client.Patch(ctx, object, client.Apply, opts...)
+	m.metrics.recordApplyError(gk, err, true)
+	return err
 }
 
 func (m *ResourceManager) apply(ctx context.Context, object *unstructured.Unstructured) error {
@@ -297,7 +547,10 @@ func (m *ResourceManager) apply(ctx context.Context, object *unstructured.Unstru
 		client.ForceOwnership,
 		client.FieldOwner(m.owner.Field),
 	}
-	return m.client.Patch(ctx, object, client.Apply, opts...)
+	gk := object.GroupVersionKind().GroupKind()
+	err := m.client.Patch(ctx, object, client.Apply, opts...)
+	m.metrics.recordApplyError(gk, err, false)
+	return err
 }
 
 // cleanupMetadata performs an HTTP PATCH request to remove entries from metadata annotations, labels and managedFields.
@@ -28,8 +28,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/fluxcd/pkg/ssa/normalize"
@@ -447,6 +450,58 @@ func TestApply_NoOp(t *testing.T) {
 	})
 }
 
+func TestApplyAll_Progress(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("progress")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configMapName, configMap := getFirstObject(objects, "ConfigMap", id)
+
+	if _, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := unstructured.SetNestedField(configMap.Object, "val", "data", "key"); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []Progress
+	opts := DefaultApplyOptions()
+	opts.Progress = func(p Progress) {
+		reports = append(reports, p)
+	}
+
+	changeSet, err := manager.ApplyAll(ctx, objects, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify every object is reported once, in order, with its change set entry
+	if diff := cmp.Diff(len(objects), len(reports)); diff != "" {
+		t.Fatalf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+	for i, p := range reports {
+		if p.Index != i || p.Total != len(objects) {
+			t.Errorf("expected report %d/%d, got %d/%d", i, len(objects), p.Index, p.Total)
+		}
+		if diff := cmp.Diff(changeSet.Entries[i].String(), p.Entry.String()); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if p.Duration <= 0 || p.Err != nil {
+			t.Errorf("unexpected report for %s: %v, %v", p.Entry.Subject, p.Duration, p.Err)
+		}
+		if p.Entry.Subject == configMapName && p.Entry.Action != ConfiguredAction {
+			t.Errorf("expected %s to be configured, got %s", configMapName, p.Entry.Action)
+		}
+	}
+}
+
 func TestApply_Exclusions(t *testing.T) {
 	timeout := 10 * time.Second
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -977,3 +1032,190 @@ func containsItemString(s []string, e string) bool {
 	}
 	return false
 }
+
+func TestApply_Replace(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("replace")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.SetOwnerLabels(objects, "app1", "default")
+
+	secretName, secret := getFirstObject(objects, "Secret", id)
+	configMapName, configMap := getFirstObject(objects, "ConfigMap", id)
+	for _, obj := range []*unstructured.Unstructured{secret, configMap} {
+		annotations := obj.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[ReplaceAnnotation] = "true"
+		obj.SetAnnotations(annotations)
+	}
+
+	if _, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	getUID := func(obj *unstructured.Unstructured) types.UID {
+		existing := obj.DeepCopy()
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(existing), existing); err != nil {
+			t.Fatal(err)
+		}
+		return existing.GetUID()
+	}
+
+	t.Run("does not replace unchanged objects", func(t *testing.T) {
+		uid := getUID(configMap)
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range changeSet.Entries {
+			if diff := cmp.Diff(UnchangedAction, entry.Action); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+		}
+		if uid != getUID(configMap) {
+			t.Errorf("expected %s to not be recreated", configMapName)
+		}
+	})
+
+	t.Run("replaces immutable secret", func(t *testing.T) {
+		uid := getUID(secret)
+		if err := unstructured.SetNestedField(secret.Object, "val-secret", "stringData", "key"); err != nil {
+			t.Fatal(err)
+		}
+
+		changeSet, err := manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ReplacedAction, changeSet.ToMap()[secretName]); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if uid == getUID(secret) {
+			t.Errorf("expected %s to be recreated", secretName)
+		}
+	})
+
+	t.Run("replaces drifted object with Apply", func(t *testing.T) {
+		uid := getUID(configMap)
+		if err := unstructured.SetNestedField(configMap.Object, "replaced", "data", "key"); err != nil {
+			t.Fatal(err)
+		}
+
+		entry, err := manager.Apply(ctx, configMap, DefaultApplyOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(ReplacedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if uid == getUID(configMap) {
+			t.Errorf("expected %s to be recreated", configMapName)
+		}
+	})
+
+	t.Run("does not replace object when dry-run is denied", func(t *testing.T) {
+		uid := getUID(configMap)
+		if err := unstructured.SetNestedField(configMap.Object, "denied", "data", "key"); err != nil {
+			t.Fatal(err)
+		}
+
+		deniedManager := &ResourceManager{
+			client: &dryRunDenier{Client: manager.client},
+			owner:  manager.owner,
+		}
+
+		_, err := deniedManager.Apply(ctx, configMap, DefaultApplyOptions())
+		if err == nil || !strings.Contains(err.Error(), "denied by test webhook") {
+			t.Fatalf("expected dry-run error, got %v", err)
+		}
+
+		_, err = deniedManager.ApplyAll(ctx, []*unstructured.Unstructured{configMap}, DefaultApplyOptions())
+		if err == nil || !strings.Contains(err.Error(), "denied by test webhook") {
+			t.Fatalf("expected dry-run error, got %v", err)
+		}
+
+		if uid != getUID(configMap) {
+			t.Errorf("expected %s to not be recreated", configMapName)
+		}
+	})
+}
+
+// dryRunDenier is a client rejecting all the server-side dry-run patches,
+// as an admission webhook denying the objects would.
+type dryRunDenier struct {
+	client.Client
+}
+
+func (c *dryRunDenier) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	po := &client.PatchOptions{}
+	po.ApplyOptions(opts)
+	if len(po.DryRun) > 0 {
+		return apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, obj.GetName(),
+			fmt.Errorf("denied by test webhook"))
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func TestApplyWithCRDs(t *testing.T) {
+	timeout := 30 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("crds")
+	objects, err := readManifest("testdata/test11.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, crd := getFirstObject(objects, "CustomResourceDefinition", "crdtests.apply.fluxcd.io")
+	crName, _ := getFirstObject(objects, "CRDTest", id)
+
+	opts := DefaultApplyOptions()
+	opts.WaitInterval = 500 * time.Millisecond
+	opts.WaitTimeout = timeout
+
+	changeSet, err := manager.ApplyWithCRDs(ctx, objects, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var output []string
+	for _, entry := range changeSet.Entries {
+		if diff := cmp.Diff(CreatedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		output = append(output, entry.Subject)
+	}
+	expected := []string{utils.FmtUnstructured(crd), crName}
+	if diff := cmp.Diff(expected, output); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(crd.GroupVersionKind())
+	if err := manager.client.Get(ctx, client.ObjectKeyFromObject(crd), existing); err != nil {
+		t.Fatal(err)
+	}
+	if !isEstablished(existing) {
+		t.Errorf("expected %s to be established", utils.FmtUnstructured(crd))
+	}
+
+	// Applying the same objects again is a no-op.
+	changeSet, err = manager.ApplyWithCRDs(ctx, objects, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range changeSet.Entries {
+		if diff := cmp.Diff(UnchangedAction, entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+	}
+}
@@ -20,12 +20,17 @@ package ssa
 import (
 	"context"
 	"fmt"
-	"sort"
+	"maps"
+	"slices"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/fluxcd/pkg/ssa/utils"
@@ -48,6 +53,19 @@ type DeleteOptions struct {
 	// A nil Exclusions map means all objects are subject to deletion
 	// irregardless of their metadata labels and annotations.
 	Exclusions map[string]string
+
+	// CascadeKinds are the kinds of the dependent objects reported with the
+	// CascadedAction by DeleteAll and CascadedDependents, when the garbage
+	// collector deletes them after their owners, e.g. the ReplicaSets and Pods
+	// of a Deployment. The dependents are discovered from their
+	// ownerReferences, and are never deleted by the ResourceManager itself.
+	// Nothing is reported if empty, or if PropagationPolicy is Orphan.
+	CascadeKinds []schema.GroupVersionKind
+
+	// Progress is called by DeleteAll for each object once it has been
+	// deleted, skipped or failed to be deleted. The cascaded dependents are
+	// not reported.
+	Progress ProgressFunc
 }
 
 // DefaultDeleteOptions returns the default delete options where the propagation
@@ -62,59 +80,235 @@ func DefaultDeleteOptions() DeleteOptions {
 
 // Delete deletes the given object (not found errors are ignored).
 func (m *ResourceManager) Delete(ctx context.Context, object *unstructured.Unstructured, opts DeleteOptions) (*ChangeSetEntry, error) {
+	cse, _, err := m.delete(ctx, object, opts)
+	return cse, err
+}
+
+// delete deletes the given object, and returns the deleted in-cluster
+// object, or nil if it has not been deleted.
+func (m *ResourceManager) delete(ctx context.Context, object *unstructured.Unstructured, opts DeleteOptions) (*ChangeSetEntry, *unstructured.Unstructured, error) {
+	existingObject, cse, err := m.deleteTarget(ctx, object, opts)
+	if existingObject == nil {
+		return cse, nil, err
+	}
+
+	if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(opts.PropagationPolicy)); err != nil {
+		return m.changeSetEntry(object, UnknownAction), nil,
+			fmt.Errorf("%s delete failed: %w", utils.FmtUnstructured(object), err)
+	}
+
+	return m.changeSetEntry(object, DeletedAction), existingObject, nil
+}
 
+// deleteTarget returns the in-cluster object of the given object if it is
+// subject to deletion, or the ChangeSetEntry of the object otherwise.
+func (m *ResourceManager) deleteTarget(ctx context.Context, object *unstructured.Unstructured, opts DeleteOptions) (*unstructured.Unstructured, *ChangeSetEntry, error) {
 	existingObject := &unstructured.Unstructured{}
 	existingObject.SetGroupVersionKind(object.GroupVersionKind())
 	err := m.client.Get(ctx, client.ObjectKeyFromObject(object), existingObject)
 	if err != nil {
 		if !apierrors.IsNotFound(err) {
-			return m.changeSetEntry(object, UnknownAction),
+			return nil, m.changeSetEntry(object, UnknownAction),
 				fmt.Errorf("%s query failed: %w", utils.FmtUnstructured(object), err)
 		}
-		return m.changeSetEntry(object, DeletedAction), nil
+		return nil, m.changeSetEntry(object, DeletedAction), nil
 	}
 
 	sel, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{MatchLabels: opts.Inclusions})
 	if err != nil {
-		return m.changeSetEntry(object, UnknownAction),
+		return nil, m.changeSetEntry(object, UnknownAction),
 			fmt.Errorf("%s label selector failed: %w", utils.FmtUnstructured(object), err)
 	}
 
 	if !sel.Matches(labels.Set(existingObject.GetLabels())) {
-		return m.changeSetEntry(object, SkippedAction), nil
+		return nil, m.changeSetEntry(object, SkippedAction), nil
 	}
 
 	if utils.AnyInMetadata(existingObject, opts.Exclusions) {
-		return m.changeSetEntry(object, SkippedAction), nil
-	}
-
-	if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(opts.PropagationPolicy)); err != nil {
-		return m.changeSetEntry(object, UnknownAction),
-			fmt.Errorf("%s delete failed: %w", utils.FmtUnstructured(object), err)
+		return nil, m.changeSetEntry(object, SkippedAction), nil
 	}
 
-	return m.changeSetEntry(object, DeletedAction), nil
+	return existingObject, nil, nil
 }
 
 // DeleteAll deletes the given set of objects (not found errors are ignored).
+// If opts.CascadeKinds is set, the ChangeSet also contains the dependents of
+// the deleted objects which will be deleted by the garbage collector, with
+// the CascadedAction.
 func (m *ResourceManager) DeleteAll(ctx context.Context, objects []*unstructured.Unstructured, opts DeleteOptions) (*ChangeSet, error) {
-	sort.Sort(sort.Reverse(SortableUnstructureds(objects)))
-	changeSet := NewChangeSet()
+	sortForDeletion(objects)
+	changeSet := m.newChangeSet()
 
 	var errors string
-	for _, object := range objects {
-		cse, err := m.Delete(ctx, object, opts)
+
+	// The dependents are listed before deleting their owners, which may
+	// otherwise be removed along with their dependents by the time the
+	// dependents are listed.
+	var dependents []*unstructured.Unstructured
+	if cascades(opts) {
+		var err error
+		dependents, err = m.listDependents(ctx, objects, opts.CascadeKinds)
+		if err != nil {
+			errors += err.Error() + ";"
+		}
+	}
+
+	deleted := make(map[types.UID]struct{})
+	for i, object := range objects {
+		start := time.Now()
+		cse, existingObject, err := m.delete(ctx, object, opts)
 		if cse != nil {
 			changeSet.Add(*cse)
+			opts.Progress.report(i, len(objects), *cse, time.Since(start), err)
+		}
+		if existingObject != nil {
+			deleted[existingObject.GetUID()] = struct{}{}
 		}
 		if err != nil {
 			errors += err.Error() + ";"
 		}
 	}
 
+	for _, dependent := range cascadedDependents(deleted, dependents) {
+		changeSet.Add(*m.changeSetEntry(dependent, CascadedAction))
+	}
+
 	if errors != "" {
 		return changeSet, fmt.Errorf("delete failed, errors: %s", errors)
 	}
 
 	return changeSet, nil
 }
+
+// CascadedDependents returns the ChangeSet of the dependents the garbage
+// collector would delete if the given objects were deleted with DeleteAll,
+// without deleting anything. The dependents of the kinds in
+// opts.CascadeKinds are discovered from their ownerReferences. A dependent
+// is reported only if all its owners would be deleted, and the objects
+// deleted by DeleteAll itself are not reported.
+func (m *ResourceManager) CascadedDependents(ctx context.Context, objects []*unstructured.Unstructured, opts DeleteOptions) (*ChangeSet, error) {
+	changeSet := m.newChangeSet()
+	if !cascades(opts) {
+		return changeSet, nil
+	}
+
+	deleted := make(map[types.UID]struct{})
+	for _, object := range objects {
+		existingObject, _, err := m.deleteTarget(ctx, object, opts)
+		if err != nil {
+			return nil, err
+		}
+		if existingObject != nil {
+			deleted[existingObject.GetUID()] = struct{}{}
+		}
+	}
+
+	dependents, err := m.listDependents(ctx, objects, opts.CascadeKinds)
+	if err != nil {
+		return nil, err
+	}
+	for _, dependent := range cascadedDependents(deleted, dependents) {
+		changeSet.Add(*m.changeSetEntry(dependent, CascadedAction))
+	}
+	return changeSet, nil
+}
+
+// cascades returns true if the dependents of the deleted objects are
+// reported with the given options.
+func cascades(opts DeleteOptions) bool {
+	return len(opts.CascadeKinds) > 0 && opts.PropagationPolicy != metav1.DeletePropagationOrphan
+}
+
+// listDependents returns the in-cluster objects of the given kinds that have
+// owner references, in the namespaces of the given objects, or in all the
+// namespaces if some of the objects are cluster-scoped. The kinds not
+// installed in the cluster are ignored.
+func (m *ResourceManager) listDependents(ctx context.Context, objects []*unstructured.Unstructured, kinds []schema.GroupVersionKind) ([]*unstructured.Unstructured, error) {
+	var namespaces []string
+	for _, object := range objects {
+		ns := object.GetNamespace()
+		if ns == "" {
+			// Namespaced objects can be owned by cluster-scoped objects.
+			namespaces = []string{""}
+			break
+		}
+		if !slices.Contains(namespaces, ns) {
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	var dependents []*unstructured.Unstructured
+	seen := make(map[types.UID]struct{})
+	for _, gvk := range kinds {
+		for _, ns := range namespaces {
+			list := &unstructured.UnstructuredList{}
+			list.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			if err := m.client.List(ctx, list, client.InNamespace(ns)); err != nil {
+				if meta.IsNoMatchError(err) {
+					break
+				}
+				return nil, fmt.Errorf("%s list failed: %w", gvk.Kind, err)
+			}
+			for i := range list.Items {
+				item := &list.Items[i]
+				if len(item.GetOwnerReferences()) == 0 {
+					continue
+				}
+				// The cluster-scoped kinds are listed once per namespace.
+				if _, ok := seen[item.GetUID()]; ok {
+					continue
+				}
+				seen[item.GetUID()] = struct{}{}
+				item.SetGroupVersionKind(gvk)
+				dependents = append(dependents, item)
+			}
+		}
+	}
+	return dependents, nil
+}
+
+// cascadedDependents returns the dependents which are deleted by the garbage
+// collector after the deletion of the objects with the given UIDs, i.e.
+// those with all their owners deleted, either directly or by the garbage
+// collector. The deleted objects are not returned.
+func cascadedDependents(deleted map[types.UID]struct{}, dependents []*unstructured.Unstructured) []*unstructured.Unstructured {
+	if len(deleted) == 0 {
+		return nil
+	}
+
+	gone := maps.Clone(deleted)
+	cascaded := make(map[types.UID]struct{})
+	for changed := true; changed; {
+		changed = false
+		for _, dependent := range dependents {
+			uid := dependent.GetUID()
+			if _, ok := gone[uid]; ok {
+				continue
+			}
+			if allOwnersGone(dependent, gone) {
+				gone[uid] = struct{}{}
+				cascaded[uid] = struct{}{}
+				changed = true
+			}
+		}
+	}
+
+	var result []*unstructured.Unstructured
+	for _, dependent := range dependents {
+		if _, ok := cascaded[dependent.GetUID()]; ok {
+			result = append(result, dependent)
+		}
+	}
+	return result
+}
+
+// allOwnersGone returns true if all the owners of the object are in the
+// given set of UIDs.
+func allOwnersGone(object *unstructured.Unstructured, gone map[types.UID]struct{}) bool {
+	for _, ref := range object.GetOwnerReferences() {
+		if _, ok := gone[ref.UID]; !ok {
+			return false
+		}
+	}
+	return true
+}
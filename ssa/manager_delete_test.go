@@ -25,6 +25,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/fluxcd/pkg/ssa/utils"
@@ -98,7 +101,7 @@ func TestDelete(t *testing.T) {
 			t.Error(err)
 		}
 
-		if err := manager.WaitForTermination(objects, WaitOptions{time.Second, 5 * time.Second, false}); err != nil {
+		if err := manager.WaitForTermination(objects, WaitOptions{Interval: time.Second, Timeout: 5 * time.Second}); err != nil {
 			// workaround for https://github.com/kubernetes-sigs/controller-runtime/issues/880
 			if !strings.Contains(err.Error(), "Namespace/") {
 				t.Error(err)
@@ -163,3 +166,197 @@ func TestDelete_Exclusions(t *testing.T) {
 		}
 	})
 }
+
+func TestDeleteAll_Progress(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("delete-progress")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manager.SetOwnerLabels(objects, "app1", "default")
+
+	if _, err = manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, configMap := getFirstObject(objects, "ConfigMap", id)
+	configMapName := utils.FmtUnstructured(configMap)
+
+	// the configmap is skipped as it no longer has the owner labels
+	configMapClone := configMap.DeepCopy()
+	configMapClone.SetLabels(nil)
+	configMapClone.SetManagedFields(nil)
+	if err := manager.client.Update(ctx, configMapClone); err != nil {
+		t.Fatal(err)
+	}
+
+	var reports []Progress
+	opts := DefaultDeleteOptions()
+	opts.Inclusions = manager.GetOwnerLabels("app1", "default")
+	opts.Progress = func(p Progress) {
+		reports = append(reports, p)
+	}
+
+	changeSet, err := manager.DeleteAll(ctx, objects, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify every object is reported once, in the deletion order
+	if diff := cmp.Diff(len(objects), len(reports)); diff != "" {
+		t.Fatalf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+	for i, p := range reports {
+		if p.Index != i || p.Total != len(objects) {
+			t.Errorf("expected report %d/%d, got %d/%d", i, len(objects), p.Index, p.Total)
+		}
+		if diff := cmp.Diff(changeSet.Entries[i].String(), p.Entry.String()); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		want := DeletedAction
+		if p.Entry.Subject == configMapName {
+			want = SkippedAction
+		}
+		if diff := cmp.Diff(want, p.Entry.Action); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+	}
+}
+
+func TestDelete_CascadeKinds(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("cascade")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	_, configMap := getFirstObject(objects, "ConfigMap", id)
+	_, role := getFirstObject(objects, "ClusterRole", id)
+	configMapOwner := getOwnerReference(ctx, t, configMap)
+	roleOwner := getOwnerReference(ctx, t, role)
+
+	external := createDependent(ctx, t, "ConfigMap", id, id+"-external")
+	child := createDependent(ctx, t, "Secret", id, id+"-child", configMapOwner)
+	createDependent(ctx, t, "ConfigMap", id, id+"-grandchild", getOwnerReference(ctx, t, child))
+	createDependent(ctx, t, "Secret", id, id+"-role-child", roleOwner)
+	createDependent(ctx, t, "Secret", id, id+"-shared", configMapOwner, getOwnerReference(ctx, t, external))
+
+	opts := DefaultDeleteOptions()
+	opts.CascadeKinds = []schema.GroupVersionKind{
+		{Version: "v1", Kind: "Secret"},
+		{Version: "v1", Kind: "ConfigMap"},
+	}
+
+	expected := []string{
+		"Secret/" + id + "/" + id + "-child",
+		"Secret/" + id + "/" + id + "-role-child",
+		"ConfigMap/" + id + "/" + id + "-grandchild",
+	}
+
+	t.Run("reports cascaded dependents without deleting", func(t *testing.T) {
+		changeSet, err := manager.CascadedDependents(ctx, objects, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var output []string
+		for _, entry := range changeSet.Entries {
+			if diff := cmp.Diff(CascadedAction, entry.Action); diff != "" {
+				t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+			}
+			output = append(output, entry.Subject)
+		}
+		if diff := cmp.Diff(expected, output); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+
+		configMapClone := configMap.DeepCopy()
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(configMapClone), configMapClone); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Run("reports nothing with orphan propagation", func(t *testing.T) {
+		orphanOpts := opts
+		orphanOpts.PropagationPolicy = metav1.DeletePropagationOrphan
+		changeSet, err := manager.CascadedDependents(ctx, objects, orphanOpts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(changeSet.Entries) != 0 {
+			t.Errorf("Expected no entries, got %s", changeSet.String())
+		}
+	})
+
+	t.Run("deletes objects and reports cascaded dependents", func(t *testing.T) {
+		changeSet, err := manager.DeleteAll(ctx, objects, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var deleted, cascaded []string
+		for _, entry := range changeSet.Entries {
+			switch entry.Action {
+			case DeletedAction:
+				deleted = append(deleted, entry.Subject)
+			case CascadedAction:
+				cascaded = append(cascaded, entry.Subject)
+			default:
+				t.Errorf("Unexpected action %s", entry.String())
+			}
+		}
+		if len(deleted) != len(objects) {
+			t.Errorf("Expected %d deleted objects, got %d", len(objects), len(deleted))
+		}
+		if diff := cmp.Diff(expected, cascaded); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+
+		// The dependents are left to the garbage collector.
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(child), child.DeepCopy()); err != nil {
+			t.Error(err)
+		}
+	})
+}
+
+func getOwnerReference(ctx context.Context, t *testing.T, object *unstructured.Unstructured) metav1.OwnerReference {
+	t.Helper()
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(object.GroupVersionKind())
+	if err := manager.client.Get(ctx, client.ObjectKeyFromObject(object), existing); err != nil {
+		t.Fatal(err)
+	}
+	return metav1.OwnerReference{
+		APIVersion: existing.GetAPIVersion(),
+		Kind:       existing.GetKind(),
+		Name:       existing.GetName(),
+		UID:        existing.GetUID(),
+	}
+}
+
+func createDependent(ctx context.Context, t *testing.T, kind, namespace, name string, owners ...metav1.OwnerReference) *unstructured.Unstructured {
+	t.Helper()
+	object := &unstructured.Unstructured{}
+	object.SetAPIVersion("v1")
+	object.SetKind(kind)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+	object.SetOwnerReferences(owners)
+	if err := manager.client.Create(ctx, object); err != nil {
+		t.Fatal(err)
+	}
+	return object
+}
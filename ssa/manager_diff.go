@@ -19,12 +19,18 @@ package ssa
 
 import (
 	"context"
+	"time"
 
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/fluxcd/cli-utils/pkg/object"
 	"github.com/fluxcd/pkg/ssa/errors"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
 	"github.com/fluxcd/pkg/ssa/normalize"
 	"github.com/fluxcd/pkg/ssa/utils"
 )
@@ -45,6 +51,145 @@ func DefaultDiffOptions() DiffOptions {
 	}
 }
 
+// DiffAllOptions contains options for the server-side dry-run diff of a set
+// of objects.
+type DiffAllOptions struct {
+	// Exclusions determines which objects are skipped from the diff
+	// based on the specified key-value pairs in their metadata.
+	Exclusions map[string]string `json:"exclusions"`
+
+	// IgnoreRules determines which JSON pointers are ignored when computing
+	// the diffs, for the objects matching their selectors.
+	IgnoreRules []jsondiff.IgnoreRule `json:"ignoreRules"`
+
+	// AutomationRules classify the operations of the diffs into changes
+	// made by the user or by automation, see jsondiff.Diff.Owners.
+	AutomationRules []jsondiff.AutomationRule `json:"automationRules,omitempty"`
+
+	// TolerateForbidden returns diffs of type jsondiff.DiffTypeUnknown,
+	// instead of an error, for the objects which can't be read or dry-run
+	// applied because the request is forbidden.
+	TolerateForbidden bool `json:"tolerateForbidden,omitempty"`
+
+	// StaleObjects are the objects to delete, e.g. the objects of the
+	// previous inventory which have been removed from the set. Those which
+	// exist in the cluster are returned with the jsondiff.DiffTypeDelete
+	// type, or jsondiff.DiffTypeExclude if they are skipped from deletion
+	// by DeleteOptions.
+	StaleObjects []*unstructured.Unstructured `json:"-"`
+
+	// DeleteOptions determines which StaleObjects are subject to deletion,
+	// see DeleteOptions.Inclusions and DeleteOptions.Exclusions.
+	DeleteOptions DeleteOptions `json:"-"`
+}
+
+// DefaultDiffAllOptions returns the default options of DiffAll.
+func DefaultDiffAllOptions() DiffAllOptions {
+	return DiffAllOptions{
+		Exclusions:    nil,
+		DeleteOptions: DefaultDeleteOptions(),
+	}
+}
+
+// DiffAll performs a server-side apply dry-run of the given objects, and
+// returns the JSON patch between each object in the cluster and its dry-run
+// result, without changing the cluster state. The objects which don't exist
+// in the cluster are returned with the jsondiff.DiffTypeCreate type,
+// including those which can't be dry-run applied because their
+// CustomResourceDefinition or Namespace is part of the set and doesn't exist
+// yet. The StaleObjects of the options which exist in the cluster are
+// returned last, with the jsondiff.DiffTypeDelete type.
+//
+// The data of Kubernetes Secrets is masked in both the JSON patches and the
+// desired and in-cluster objects of the DiffSet. The given objects are not
+// modified.
+func (m *ResourceManager) DiffAll(ctx context.Context, objects []*unstructured.Unstructured, opts DiffAllOptions) (jsondiff.DiffSet, error) {
+	selectors, err := ignoreRuleSelectors(opts.IgnoreRules)
+	if err != nil {
+		return nil, err
+	}
+
+	// The kinds and namespaces created by the set, of which the objects
+	// can't be dry-run applied before they are created.
+	kinds := make(map[schema.GroupKind]bool)
+	namespaces := make(map[string]bool)
+	desired := make(map[object.ObjMetadata]bool, len(objects))
+	for _, o := range objects {
+		desired[object.UnstructuredToObjMetadata(o)] = true
+		switch {
+		case utils.IsCRD(o):
+			group, _, _ := unstructured.NestedString(o.Object, "spec", "group")
+			kind, _, _ := unstructured.NestedString(o.Object, "spec", "names", "kind")
+			kinds[schema.GroupKind{Group: group, Kind: kind}] = true
+		case utils.IsNamespace(o):
+			namespaces[o.GetName()] = true
+		}
+	}
+
+	var diffSet jsondiff.DiffSet
+	for _, o := range objects {
+		_, diff, err := m.preview(ctx, o,
+			jsondiff.ExclusionSelector(opts.Exclusions),
+			jsondiff.AutomationRules(opts.AutomationRules),
+			jsondiff.TolerateForbidden(opts.TolerateForbidden),
+			ignorePaths(selectors, o),
+		)
+		if err != nil {
+			if !createdWithSet(err, o, kinds, namespaces) {
+				return nil, err
+			}
+			diff = jsondiff.NewDiffForUnstructured(o, nil, jsondiff.DiffTypeCreate, nil)
+		}
+		diffSet = append(diffSet, diff)
+	}
+
+	for _, o := range opts.StaleObjects {
+		if desired[object.UnstructuredToObjMetadata(o)] {
+			continue
+		}
+
+		existingObject, cse, err := m.deleteTarget(ctx, o, opts.DeleteOptions)
+		if err != nil {
+			if opts.TolerateForbidden && apierrors.IsForbidden(err) {
+				diffSet = append(diffSet, jsondiff.NewUnknownDiffForUnstructured(o, err))
+				continue
+			}
+			return nil, err
+		}
+
+		var diff *jsondiff.Diff
+		switch {
+		case existingObject != nil:
+			unstructured.RemoveNestedField(existingObject.Object, "metadata", "managedFields")
+			diff = jsondiff.NewDiffForUnstructured(o, existingObject, jsondiff.DiffTypeDelete, nil)
+		case cse.Action == SkippedAction:
+			diff = jsondiff.NewDiffForUnstructured(o, nil, jsondiff.DiffTypeExclude, nil)
+		default:
+			// The object doesn't exist in the cluster.
+			continue
+		}
+		if err := sanitizeDiff(diff); err != nil {
+			return nil, err
+		}
+		diffSet = append(diffSet, diff)
+	}
+
+	return diffSet, nil
+}
+
+// createdWithSet returns true if the object can't be dry-run applied
+// because its kind or namespace doesn't exist yet, and is created by the
+// set, in which case applying the set creates the object.
+func createdWithSet(err error, object *unstructured.Unstructured, kinds map[schema.GroupKind]bool, namespaces map[string]bool) bool {
+	switch {
+	case meta.IsNoMatchError(err):
+		return kinds[object.GroupVersionKind().GroupKind()]
+	case apierrors.IsNotFound(err):
+		return object.GetNamespace() != "" && namespaces[object.GetNamespace()]
+	}
+	return false
+}
+
 // Diff performs a server-side apply dry-un and returns the live and merged objects if drift is detected.
 // If the diff contains Kubernetes Secrets, the data values are masked.
 func (m *ResourceManager) Diff(ctx context.Context, object *unstructured.Unstructured, opts DiffOptions) (
@@ -52,6 +197,18 @@ func (m *ResourceManager) Diff(ctx context.Context, object *unstructured.Unstruc
 	*unstructured.Unstructured,
 	*unstructured.Unstructured,
 	error,
+) {
+	start := time.Now()
+	entry, existingObject, dryRunObject, err := m.diff(ctx, object, opts)
+	m.metrics.recordOperation(OperationDiff, object.GroupVersionKind().GroupKind(), entry, err, start)
+	return entry, existingObject, dryRunObject, err
+}
+
+func (m *ResourceManager) diff(ctx context.Context, object *unstructured.Unstructured, opts DiffOptions) (
+	*ChangeSetEntry,
+	*unstructured.Unstructured,
+	*unstructured.Unstructured,
+	error,
 ) {
 	existingObject := &unstructured.Unstructured{}
 	existingObject.SetGroupVersionKind(object.GroupVersionKind())
@@ -28,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/yaml"
 
+	"github.com/fluxcd/pkg/ssa/jsondiff"
 	"github.com/fluxcd/pkg/ssa/normalize"
 	"github.com/fluxcd/pkg/ssa/utils"
 )
@@ -462,3 +463,103 @@ func getKeys(m map[string]interface{}) []string {
 
 	return keys
 }
+
+func TestDiffAll(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("diffall")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, configMap := getFirstObject(objects, "ConfigMap", id)
+	_, secret := getFirstObject(objects, "Secret", id)
+	if err := unstructured.SetNestedField(secret.Object, false, "immutable"); err != nil {
+		t.Fatal(err)
+	}
+
+	diffTypes := func(diffSet jsondiff.DiffSet) map[string]jsondiff.DiffType {
+		types := make(map[string]jsondiff.DiffType)
+		for _, d := range diffSet {
+			types[d.GroupVersionKind().Kind+"/"+d.GetName()] = d.Type
+		}
+		return types
+	}
+
+	t.Run("creates the objects of a new namespace", func(t *testing.T) {
+		diffSet, err := manager.DiffAll(ctx, objects, DefaultDiffAllOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(diffSet) != len(objects) {
+			t.Fatalf("expected %d diffs, got %d", len(objects), len(diffSet))
+		}
+		for _, d := range diffSet {
+			if d.Type != jsondiff.DiffTypeCreate {
+				t.Errorf("expected %s/%s to be created, got %s", d.GroupVersionKind().Kind, d.GetName(), d.Type)
+			}
+		}
+	})
+
+	if _, err = manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := configMap.DeepCopy()
+	stale.SetName("stale-" + id)
+	excluded := configMap.DeepCopy()
+	excluded.SetName("excluded-" + id)
+	excluded.SetAnnotations(map[string]string{"prune": "disabled"})
+	if _, err = manager.ApplyAll(ctx, []*unstructured.Unstructured{stale, excluded}, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+	missing := configMap.DeepCopy()
+	missing.SetName("missing-" + id)
+
+	if err := unstructured.SetNestedField(configMap.Object, "diffall-test", "data", "key"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("diffs the updated and deleted objects", func(t *testing.T) {
+		opts := DefaultDiffAllOptions()
+		opts.StaleObjects = []*unstructured.Unstructured{stale, excluded, missing, secret}
+		opts.DeleteOptions.Exclusions = map[string]string{"prune": "disabled"}
+
+		diffSet, err := manager.DiffAll(ctx, objects, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		types := diffTypes(diffSet)
+		if diff := cmp.Diff(len(objects)+2, len(types)); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(jsondiff.DiffTypeUpdate, types["ConfigMap/"+id]); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(jsondiff.DiffTypeNone, types["Secret/"+id]); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(jsondiff.DiffTypeDelete, types["ConfigMap/"+stale.GetName()]); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if diff := cmp.Diff(jsondiff.DiffTypeExclude, types["ConfigMap/"+excluded.GetName()]); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+		if _, ok := types["ConfigMap/"+missing.GetName()]; ok {
+			t.Errorf("expected no diff for the missing object")
+		}
+
+		last := diffSet[len(diffSet)-2]
+		if last.ClusterObject == nil || last.ClusterObject.GetName() != stale.GetName() {
+			t.Errorf("expected the in-cluster object of the deleted object, got %v", last.ClusterObject)
+		}
+		if !diffSet.HasChanges() {
+			t.Errorf("expected the DiffSet to have changes")
+		}
+	})
+}
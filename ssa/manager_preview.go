@@ -0,0 +1,222 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa/errors"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+// PreviewOptions contains options for previewing the changes of a set of
+// objects.
+type PreviewOptions struct {
+	// Exclusions determines which objects are skipped from the preview
+	// based on the specified key-value pairs in their metadata.
+	Exclusions map[string]string `json:"exclusions"`
+
+	// IgnoreRules determines which JSON pointers are ignored when computing
+	// the DiffSet, for the objects matching their selectors.
+	IgnoreRules []jsondiff.IgnoreRule `json:"ignoreRules"`
+}
+
+// DefaultPreviewOptions returns the default preview options.
+func DefaultPreviewOptions() PreviewOptions {
+	return PreviewOptions{
+		Exclusions: nil,
+	}
+}
+
+// Preview is the result of a server-side apply dry-run of a set of objects.
+type Preview struct {
+	// ChangeSet contains the action that applying each object would result in.
+	ChangeSet *ChangeSet
+
+	// DiffSet contains the JSON patch between each object in the cluster and
+	// the dry-run result.
+	DiffSet jsondiff.DiffSet
+}
+
+// Preview performs a server-side apply dry-run of the given objects, and
+// returns both the ChangeSet and the DiffSet of the changes applying them
+// would result in, without changing the cluster state.
+//
+// The data of Kubernetes Secrets is masked in both the JSON patches and the
+// desired and in-cluster objects of the DiffSet, using the same masks as
+// Diff. The given objects are not modified.
+func (m *ResourceManager) Preview(ctx context.Context, objects []*unstructured.Unstructured, opts PreviewOptions) (*Preview, error) {
+	selectors, err := ignoreRuleSelectors(opts.IgnoreRules)
+	if err != nil {
+		return nil, err
+	}
+
+	changeSet := m.newChangeSet()
+	var diffSet jsondiff.DiffSet
+	for _, object := range objects {
+		entry, diff, err := m.preview(ctx, object,
+			jsondiff.ExclusionSelector(opts.Exclusions),
+			ignorePaths(selectors, object),
+		)
+		if err != nil {
+			return nil, err
+		}
+		changeSet.Add(*entry)
+		diffSet = append(diffSet, diff)
+	}
+
+	return &Preview{
+		ChangeSet: changeSet,
+		DiffSet:   diffSet,
+	}, nil
+}
+
+// preview performs a single server-side apply dry-run of the object, and
+// returns both the ChangeSetEntry of applying the object and the Diff
+// between the in-cluster object and the dry-run result. The data of
+// Kubernetes Secrets is masked in the Diff.
+//
+// If jsondiff.TolerateForbidden is set and reading or dry-run applying the
+// object is forbidden, the Diff is of type jsondiff.DiffTypeUnknown and the
+// ChangeSetEntry is nil.
+func (m *ResourceManager) preview(ctx context.Context, object *unstructured.Unstructured, opts ...jsondiff.ResourceOption) (
+	*ChangeSetEntry,
+	*jsondiff.Diff,
+	error,
+) {
+	start := time.Now()
+	entry, diff, err := m.previewObject(ctx, object, opts...)
+	m.metrics.recordOperation(OperationDiff, object.GroupVersionKind().GroupKind(), entry, err, start)
+	return entry, diff, err
+}
+
+func (m *ResourceManager) previewObject(ctx context.Context, object *unstructured.Unstructured, opts ...jsondiff.ResourceOption) (
+	*ChangeSetEntry,
+	*jsondiff.Diff,
+	error,
+) {
+	o := &jsondiff.ResourceOptions{}
+	o.ApplyOptions(opts)
+
+	opts = append([]jsondiff.ResourceOption{
+		jsondiff.FieldOwner(m.owner.Field),
+		jsondiff.MaskSecrets(true),
+		jsondiff.Rationalize(true),
+	}, opts...)
+
+	if utils.AnyInMetadata(object, o.ExclusionSelector) {
+		return m.changeSetEntry(object, SkippedAction),
+			jsondiff.NewDiffForUnstructured(object, nil, jsondiff.DiffTypeExclude, nil), nil
+	}
+
+	existingObject := &unstructured.Unstructured{}
+	existingObject.SetGroupVersionKind(object.GroupVersionKind())
+	if err := m.client.Get(ctx, client.ObjectKeyFromObject(object), existingObject); client.IgnoreNotFound(err) != nil {
+		if o.TolerateForbidden && apierrors.IsForbidden(err) {
+			return nil, jsondiff.NewUnknownDiffForUnstructured(object, err), nil
+		}
+		return nil, nil, err
+	}
+
+	if utils.AnyInMetadata(existingObject, o.ExclusionSelector) {
+		return m.changeSetEntry(existingObject, SkippedAction),
+			jsondiff.NewDiffForUnstructured(object, nil, jsondiff.DiffTypeExclude, nil), nil
+	}
+
+	dryRunObject := object.DeepCopy()
+	if err := m.dryRunApply(ctx, dryRunObject); err != nil {
+		if o.TolerateForbidden && apierrors.IsForbidden(err) {
+			return nil, jsondiff.NewUnknownDiffForUnstructured(object, errors.NewDryRunErr(err, object)), nil
+		}
+		return nil, nil, errors.NewDryRunErr(err, dryRunObject)
+	}
+
+	var entry *ChangeSetEntry
+	switch {
+	case dryRunObject.GetResourceVersion() == "":
+		entry = m.changeSetEntry(dryRunObject, CreatedAction)
+	case m.hasDrifted(existingObject, dryRunObject):
+		entry = m.changeSetEntry(object, ConfiguredAction)
+	default:
+		entry = m.changeSetEntry(dryRunObject, UnchangedAction)
+	}
+
+	diff, err := jsondiff.UnstructuredFromDryRun(object, existingObject, dryRunObject, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := sanitizeDiff(diff); err != nil {
+		return nil, nil, err
+	}
+	return entry, diff, nil
+}
+
+// ignoreRuleSelectors returns the paths of the ignore rules by selector.
+func ignoreRuleSelectors(rules []jsondiff.IgnoreRule) (map[*jsondiff.SelectorRegex][]string, error) {
+	selectors := make(map[*jsondiff.SelectorRegex][]string, len(rules))
+	for _, rule := range rules {
+		sr, err := jsondiff.NewSelectorRegex(rule.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create ignore rule selector: %w", err)
+		}
+		selectors[sr] = rule.Paths
+	}
+	return selectors, nil
+}
+
+// ignorePaths returns the paths of the ignore rules matching the object.
+func ignorePaths(selectors map[*jsondiff.SelectorRegex][]string, object *unstructured.Unstructured) jsondiff.IgnorePaths {
+	var paths jsondiff.IgnorePaths
+	for sr, p := range selectors {
+		if sr.MatchUnstructured(object) {
+			paths = append(paths, p...)
+		}
+	}
+	return paths
+}
+
+// sanitizeDiff replaces the desired and in-cluster objects of a Diff for a
+// Secret with copies of which the data values are masked.
+func sanitizeDiff(diff *jsondiff.Diff) error {
+	desired, ok := diff.DesiredObject.(*unstructured.Unstructured)
+	if !ok || !utils.IsSecret(desired) {
+		return nil
+	}
+
+	desired = desired.DeepCopy()
+	var cluster *unstructured.Unstructured
+	if c, ok := diff.ClusterObject.(*unstructured.Unstructured); ok && c != nil {
+		cluster = c.DeepCopy()
+	}
+
+	if err := SanitizeUnstructuredData(cluster, desired); err != nil {
+		return err
+	}
+
+	diff.DesiredObject = desired
+	if cluster != nil {
+		diff.ClusterObject = cluster
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"encoding/base64"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+	"github.com/fluxcd/pkg/ssa/normalize"
+)
+
+func TestPreview(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("preview")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configMapName, configMap := getFirstObject(objects, "ConfigMap", id)
+	secretName, secret := getFirstObject(objects, "Secret", id)
+
+	if err := unstructured.SetNestedField(secret.Object, false, "immutable"); err != nil {
+		t.Fatal(err)
+	}
+	if err := normalize.Unstructured(secret); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = manager.ApplyAllStaged(ctx, objects, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := unstructured.SetNestedField(configMap.Object, "preview-test", "data", "key"); err != nil {
+		t.Fatal(err)
+	}
+	newSecretValue := base64.StdEncoding.EncodeToString([]byte("new-private-key"))
+	if err := unstructured.SetNestedField(secret.Object, newSecretValue, "data", "key"); err != nil {
+		t.Fatal(err)
+	}
+
+	counter := &dryRunCounter{Client: manager.client}
+	previewManager := &ResourceManager{
+		client: counter,
+		owner:  manager.owner,
+	}
+	preview, err := previewManager.Preview(ctx, objects, DefaultPreviewOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both the ChangeSet and the DiffSet are computed from a single dry-run
+	// per object.
+	if diff := cmp.Diff(int32(len(objects)), counter.dryRuns.Load()); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+
+	actions := preview.ChangeSet.ToMap()
+	if diff := cmp.Diff(ConfiguredAction, actions[configMapName]); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(ConfiguredAction, actions[secretName]); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+
+	if len(preview.DiffSet) != len(objects) {
+		t.Fatalf("expected %d diffs, got %d", len(objects), len(preview.DiffSet))
+	}
+	for _, d := range preview.DiffSet {
+		if d.GetName() != id || d.Type != jsondiff.DiffTypeUpdate {
+			continue
+		}
+		if d.GroupVersionKind().Kind != "Secret" {
+			continue
+		}
+
+		for _, op := range d.Patch {
+			if op.Value == newSecretValue || op.OldValue == newSecretValue {
+				t.Errorf("expected secret data to be masked in patch, got %v", op)
+			}
+		}
+		val, _, _ := unstructured.NestedString(d.DesiredObject.(*unstructured.Unstructured).Object, "data", "key")
+		if val == newSecretValue {
+			t.Errorf("expected secret data to be masked in desired object")
+		}
+	}
+
+	// The given objects are not modified by the preview.
+	val, _, _ := unstructured.NestedString(secret.Object, "data", "key")
+	if diff := cmp.Diff(newSecretValue, val); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+}
+
+// dryRunCounter is a client counting the dry-run patch requests.
+type dryRunCounter struct {
+	client.Client
+	dryRuns atomic.Int32
+}
+
+func (c *dryRunCounter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	po := &client.PatchOptions{}
+	po.ApplyOptions(opts)
+	if len(po.DryRun) > 0 {
+		c.dryRuns.Add(1)
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,282 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/fluxcd/cli-utils/pkg/object"
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+// PropagationPolicyFunc returns the propagation policy of the deletion of
+// the given in-cluster object, or an empty policy for the default one.
+type PropagationPolicyFunc func(object *unstructured.Unstructured) metav1.DeletionPropagation
+
+// PruneOptions contains options for pruning the objects removed from a set.
+type PruneOptions struct {
+	// DeleteOptions determines which in-cluster objects are subject to
+	// deletion, and the default propagation policy of their deletion.
+	DeleteOptions DeleteOptions
+
+	// PropagationPolicy returns the propagation policy of each object,
+	// overriding the one of DeleteOptions, e.g. Foreground for the objects
+	// whose dependents must be deleted first, or Orphan for those whose
+	// dependents must be kept. The dependents of the objects deleted with
+	// the Orphan policy are not reported with the CascadedAction.
+	PropagationPolicy PropagationPolicyFunc
+
+	// ExclusionSelectors determines which in-cluster objects are skipped
+	// from pruning, when they match any of the selectors.
+	ExclusionSelectors []jsondiff.Selector
+
+	// ProtectedKinds are the kinds of the objects which are never pruned,
+	// and are skipped instead.
+	ProtectedKinds []schema.GroupKind
+}
+
+// DefaultProtectedKinds returns the cluster-critical kinds, whose deletion
+// deletes other objects, e.g. all the objects of a Namespace or all the
+// custom resources of a CustomResourceDefinition, or breaks the API server,
+// e.g. the admission webhooks and the aggregated APIs.
+func DefaultProtectedKinds() []schema.GroupKind {
+	return []schema.GroupKind{
+		{Group: "", Kind: "Namespace"},
+		{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+		{Group: "apiregistration.k8s.io", Kind: "APIService"},
+		{Group: "admissionregistration.k8s.io", Kind: "MutatingWebhookConfiguration"},
+		{Group: "admissionregistration.k8s.io", Kind: "ValidatingWebhookConfiguration"},
+	}
+}
+
+// DefaultPruneOptions returns the default prune options where the
+// propagation policy is set to background, and the cluster-critical kinds
+// are protected.
+func DefaultPruneOptions() PruneOptions {
+	return PruneOptions{
+		DeleteOptions:  DefaultDeleteOptions(),
+		ProtectedKinds: DefaultProtectedKinds(),
+	}
+}
+
+// propagationPolicy returns the propagation policy of the deletion of the
+// given in-cluster object.
+func (o PruneOptions) propagationPolicy(object *unstructured.Unstructured) metav1.DeletionPropagation {
+	if o.PropagationPolicy != nil {
+		if policy := o.PropagationPolicy(object); policy != "" {
+			return policy
+		}
+	}
+	return o.DeleteOptions.PropagationPolicy
+}
+
+// PruneAll deletes the objects of the previous inventory which are not part
+// of the given set of objects anymore (not found errors are ignored). The
+// objects of the protected kinds, and the in-cluster objects excluded by
+// the options, are skipped. The objects whose kind is no longer served by
+// the API server are reported as deleted, as they have been deleted along
+// with their kind. If opts.DeleteOptions.CascadeKinds is set, the ChangeSet
+// also contains the dependents of the pruned objects which will be deleted
+// by the garbage collector, with the CascadedAction.
+func (m *ResourceManager) PruneAll(ctx context.Context, objects, inventory []*unstructured.Unstructured, opts PruneOptions) (*ChangeSet, error) {
+	selectors, err := pruneSelectors(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := staleObjects(objects, inventory)
+	sortForDeletion(stale)
+	changeSet := m.newChangeSet()
+
+	var errors string
+
+	// The dependents are listed before deleting their owners, see DeleteAll.
+	// The policy of each object is only known once it has been read, hence
+	// the dependents are listed regardless of the default policy.
+	var dependents []*unstructured.Unstructured
+	if len(opts.DeleteOptions.CascadeKinds) > 0 && len(stale) > 0 {
+		dependents, err = m.listDependents(ctx, stale, opts.DeleteOptions.CascadeKinds)
+		if err != nil {
+			errors += err.Error() + ";"
+		}
+	}
+
+	deleted := make(map[types.UID]struct{})
+	for i, object := range stale {
+		start := time.Now()
+		cse, existingObject, err := m.prune(ctx, object, opts, selectors)
+		if cse != nil {
+			changeSet.Add(*cse)
+			opts.DeleteOptions.Progress.report(i, len(stale), *cse, time.Since(start), err)
+		}
+		if existingObject != nil && opts.propagationPolicy(existingObject) != metav1.DeletePropagationOrphan {
+			deleted[existingObject.GetUID()] = struct{}{}
+		}
+		if err != nil {
+			errors += err.Error() + ";"
+		}
+	}
+
+	for _, dependent := range cascadedDependents(deleted, dependents) {
+		changeSet.Add(*m.changeSetEntry(dependent, CascadedAction))
+	}
+
+	if errors != "" {
+		return changeSet, fmt.Errorf("prune failed, errors: %s", errors)
+	}
+
+	return changeSet, nil
+}
+
+// PlanPrune returns the PrunePlan of PruneAll for the given set of objects
+// and previous inventory, without deleting anything. The plan contains the
+// objects of the inventory which are not part of the set anymore, in the
+// deletion order, with the reason for which they would be deleted or not.
+// The objects which could not be queried are part of the plan with the
+// PruneReasonError, and their errors are returned along with the plan.
+func (m *ResourceManager) PlanPrune(ctx context.Context, objects, inventory []*unstructured.Unstructured, opts PruneOptions) (*PrunePlan, error) {
+	selectors, err := pruneSelectors(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	stale := staleObjects(objects, inventory)
+	sortForDeletion(stale)
+
+	var errors string
+
+	plan := &PrunePlan{Entries: []PrunePlanEntry{}}
+	for _, object := range stale {
+		existingObject, reason, err := m.pruneTarget(ctx, object, opts, selectors)
+		entry := PrunePlanEntry{
+			Subject:    utils.FmtUnstructured(object),
+			APIVersion: object.GetAPIVersion(),
+			Kind:       object.GetKind(),
+			Namespace:  object.GetNamespace(),
+			Name:       object.GetName(),
+			Reason:     reason,
+		}
+		if err != nil {
+			entry.Reason = PruneReasonError
+			entry.Error = err.Error()
+			errors += err.Error() + ";"
+		}
+		if existingObject != nil {
+			entry.Prune = true
+			entry.PropagationPolicy = opts.propagationPolicy(existingObject)
+		}
+		plan.Entries = append(plan.Entries, entry)
+	}
+
+	if errors != "" {
+		return plan, fmt.Errorf("prune plan failed, errors: %s", errors)
+	}
+
+	return plan, nil
+}
+
+// prune deletes the given stale object with its propagation policy, and
+// returns the deleted in-cluster object, or nil if it has not been deleted.
+func (m *ResourceManager) prune(ctx context.Context, object *unstructured.Unstructured, opts PruneOptions,
+	selectors []*jsondiff.SelectorRegex) (*ChangeSetEntry, *unstructured.Unstructured, error) {
+	existingObject, reason, err := m.pruneTarget(ctx, object, opts, selectors)
+	if err != nil {
+		return m.changeSetEntry(object, UnknownAction), nil, err
+	}
+	if existingObject == nil {
+		return m.changeSetEntry(object, reason.action()), nil, nil
+	}
+
+	if err := m.client.Delete(ctx, existingObject, client.PropagationPolicy(opts.propagationPolicy(existingObject))); err != nil {
+		return m.changeSetEntry(object, UnknownAction), nil,
+			fmt.Errorf("%s delete failed: %w", utils.FmtUnstructured(object), err)
+	}
+
+	return m.changeSetEntry(object, DeletedAction), existingObject, nil
+}
+
+// pruneTarget returns the in-cluster object of the given stale object if it
+// is subject to pruning, and the reason for which it is pruned or not.
+func (m *ResourceManager) pruneTarget(ctx context.Context, object *unstructured.Unstructured, opts PruneOptions,
+	selectors []*jsondiff.SelectorRegex) (*unstructured.Unstructured, PruneReason, error) {
+	if slices.Contains(opts.ProtectedKinds, object.GroupVersionKind().GroupKind()) {
+		return nil, PruneReasonProtected, nil
+	}
+
+	existingObject, cse, err := m.deleteTarget(ctx, object, opts.DeleteOptions)
+	switch {
+	case err != nil && meta.IsNoMatchError(err):
+		return nil, PruneReasonKindNotServed, nil
+	case err != nil:
+		return nil, "", err
+	case existingObject == nil && cse.Action == SkippedAction:
+		return nil, PruneReasonExcluded, nil
+	case existingObject == nil:
+		return nil, PruneReasonNotFound, nil
+	}
+
+	for _, sr := range selectors {
+		if sr.MatchUnstructured(existingObject) {
+			return nil, PruneReasonExcluded, nil
+		}
+	}
+
+	return existingObject, PruneReasonRemoved, nil
+}
+
+// pruneSelectors returns the compiled exclusion selectors of the options.
+func pruneSelectors(opts PruneOptions) ([]*jsondiff.SelectorRegex, error) {
+	selectors := make([]*jsondiff.SelectorRegex, 0, len(opts.ExclusionSelectors))
+	for i := range opts.ExclusionSelectors {
+		sr, err := jsondiff.NewSelectorRegex(&opts.ExclusionSelectors[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to create exclusion selector: %w", err)
+		}
+		selectors = append(selectors, sr)
+	}
+	return selectors, nil
+}
+
+// staleObjects returns the objects of the inventory which are not part of
+// the given set of objects. The objects are compared by group, kind,
+// namespace and name, so that an object whose API version has changed is
+// not stale.
+func staleObjects(objects, inventory []*unstructured.Unstructured) []*unstructured.Unstructured {
+	desired := make(map[object.ObjMetadata]struct{}, len(objects))
+	for _, o := range objects {
+		desired[object.UnstructuredToObjMetadata(o)] = struct{}{}
+	}
+
+	var stale []*unstructured.Unstructured
+	for _, o := range inventory {
+		if _, ok := desired[object.UnstructuredToObjMetadata(o)]; !ok {
+			stale = append(stale, o)
+		}
+	}
+	return stale
+}
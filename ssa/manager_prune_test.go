@@ -0,0 +1,311 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/fluxcd/pkg/ssa/jsondiff"
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+// deleteRecorder is a client recording the propagation policy of the
+// deleted objects, keyed by their subject.
+type deleteRecorder struct {
+	client.Client
+
+	mu       sync.Mutex
+	policies map[string]metav1.DeletionPropagation
+}
+
+func (c *deleteRecorder) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	do := &client.DeleteOptions{}
+	do.ApplyOptions(opts)
+	if u, ok := obj.(*unstructured.Unstructured); ok && do.PropagationPolicy != nil {
+		c.mu.Lock()
+		c.policies[utils.FmtUnstructured(u)] = *do.PropagationPolicy
+		c.mu.Unlock()
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func TestPruneAll(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("prune")
+	inventory, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = manager.ApplyAllStaged(ctx, inventory, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	nsName, namespace := getFirstObject(inventory, "Namespace", id)
+	cmName, configMap := getFirstObject(inventory, "ConfigMap", id)
+	crName, clusterRole := getFirstObject(inventory, "ClusterRole", id)
+	svcName, service := getFirstObject(inventory, "Service", id)
+
+	// The namespace, configmap, cluster role and service are removed from
+	// the set, and the storage class is upgraded to a new API version.
+	var objects []*unstructured.Unstructured
+	for _, object := range inventory {
+		switch object {
+		case namespace, configMap, clusterRole, service:
+			continue
+		}
+		object = object.DeepCopy()
+		if object.GetKind() == "StorageClass" {
+			object.SetAPIVersion("storage.k8s.io/v2")
+		}
+		objects = append(objects, object)
+	}
+
+	recorder := &deleteRecorder{
+		Client:   manager.client,
+		policies: make(map[string]metav1.DeletionPropagation),
+	}
+	pruneManager := &ResourceManager{
+		client: recorder,
+		owner:  manager.owner,
+	}
+
+	opts := DefaultPruneOptions()
+	opts.ExclusionSelectors = []jsondiff.Selector{{Kind: "Service"}}
+	opts.PropagationPolicy = func(object *unstructured.Unstructured) metav1.DeletionPropagation {
+		if object.GetKind() == "ClusterRole" {
+			return metav1.DeletePropagationForeground
+		}
+		return ""
+	}
+
+	changeSet, err := pruneManager.PruneAll(ctx, objects, inventory, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]Action{
+		nsName:  SkippedAction,
+		cmName:  DeletedAction,
+		crName:  DeletedAction,
+		svcName: SkippedAction,
+	}
+	if diff := cmp.Diff(expected, changeSet.ToMap()); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+
+	expectedPolicies := map[string]metav1.DeletionPropagation{
+		cmName: metav1.DeletePropagationBackground,
+		crName: metav1.DeletePropagationForeground,
+	}
+	if diff := cmp.Diff(expectedPolicies, recorder.policies); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+
+	configMapClone := configMap.DeepCopy()
+	err = manager.client.Get(ctx, client.ObjectKeyFromObject(configMapClone), configMapClone)
+	if !apierrors.IsNotFound(err) {
+		t.Errorf("expected %s to be deleted, got %v", cmName, err)
+	}
+
+	for _, object := range []*unstructured.Unstructured{namespace, service} {
+		clone := object.DeepCopy()
+		if err := manager.client.Get(ctx, client.ObjectKeyFromObject(clone), clone); err != nil {
+			t.Errorf("expected %s to be skipped, got %v", utils.FmtUnstructured(object), err)
+		}
+	}
+
+	t.Run("ignores the objects not found", func(t *testing.T) {
+		opts.ProtectedKinds = nil
+		opts.ExclusionSelectors = nil
+		opts.PropagationPolicy = nil
+		changeSet, err := manager.PruneAll(ctx, objects, []*unstructured.Unstructured{configMap}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(map[string]Action{cmName: DeletedAction}, changeSet.ToMap()); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("reports the objects of unknown kinds as deleted", func(t *testing.T) {
+		unknown := &unstructured.Unstructured{}
+		unknown.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"})
+		unknown.SetName(id)
+		unknown.SetNamespace(id)
+		changeSet, err := manager.PruneAll(ctx, objects, []*unstructured.Unstructured{unknown}, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(map[string]Action{utils.FmtUnstructured(unknown): DeletedAction}, changeSet.ToMap()); diff != "" {
+			t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+		}
+	})
+}
+
+func TestPlanPrune(t *testing.T) {
+	timeout := 10 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	id := generateName("prune-plan")
+	inventory, err := readManifest("testdata/test1.yaml", id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = manager.ApplyAllStaged(ctx, inventory, DefaultApplyOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	nsName, namespace := getFirstObject(inventory, "Namespace", id)
+	cmName, configMap := getFirstObject(inventory, "ConfigMap", id)
+	crName, clusterRole := getFirstObject(inventory, "ClusterRole", id)
+	svcName, service := getFirstObject(inventory, "Service", id)
+
+	// The object of a kind which is no longer served, and the object
+	// already deleted, are part of the previous inventory.
+	unknown := &unstructured.Unstructured{}
+	unknown.SetGroupVersionKind(schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"})
+	unknown.SetName(id)
+	unknown.SetNamespace(id)
+	deleted := configMap.DeepCopy()
+	deleted.SetName(id + "-deleted")
+	inventory = append(inventory, unknown, deleted)
+
+	var objects []*unstructured.Unstructured
+	for _, object := range inventory {
+		switch object {
+		case namespace, configMap, clusterRole, service, unknown, deleted:
+			continue
+		}
+		objects = append(objects, object)
+	}
+
+	opts := DefaultPruneOptions()
+	opts.ExclusionSelectors = []jsondiff.Selector{{Kind: "Service"}}
+	opts.PropagationPolicy = func(object *unstructured.Unstructured) metav1.DeletionPropagation {
+		if object.GetKind() == "ClusterRole" {
+			return metav1.DeletePropagationForeground
+		}
+		return ""
+	}
+
+	plan, err := manager.PlanPrune(ctx, objects, inventory, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Prune  bool
+		Reason PruneReason
+		Policy metav1.DeletionPropagation
+	}
+	expected := map[string]result{
+		nsName:                         {Reason: PruneReasonProtected},
+		cmName:                         {Prune: true, Reason: PruneReasonRemoved, Policy: metav1.DeletePropagationBackground},
+		crName:                         {Prune: true, Reason: PruneReasonRemoved, Policy: metav1.DeletePropagationForeground},
+		svcName:                        {Reason: PruneReasonExcluded},
+		utils.FmtUnstructured(unknown): {Reason: PruneReasonKindNotServed},
+		utils.FmtUnstructured(deleted): {Reason: PruneReasonNotFound},
+	}
+	got := make(map[string]result)
+	for _, entry := range plan.Entries {
+		got[entry.Subject] = result{Prune: entry.Prune, Reason: entry.Reason, Policy: entry.PropagationPolicy}
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+
+	// Nothing has been deleted.
+	configMapClone := configMap.DeepCopy()
+	if err := manager.client.Get(ctx, client.ObjectKeyFromObject(configMapClone), configMapClone); err != nil {
+		t.Error(err)
+	}
+
+	// The plan objects are deleted by DeleteAll.
+	changeSet, err := manager.DeleteAll(ctx, plan.Objects(), DefaultDeleteOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]Action{cmName: DeletedAction, crName: DeletedAction}, changeSet.ToMap()); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+}
+
+func TestPlanPrune_queryError(t *testing.T) {
+	ctx := context.Background()
+
+	configMap := func(name string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetNamespace("default")
+		u.SetName(name)
+		return u
+	}
+	existing := configMap("existing")
+	failing := configMap("failing")
+
+	kubeClient := fake.NewClientBuilder().WithObjects(existing.DeepCopy()).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if key.Name == failing.GetName() {
+				return apierrors.NewServiceUnavailable("unavailable")
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}).Build()
+	manager := NewResourceManager(kubeClient, nil, Owner{Field: "flux", Group: "fluxcd.io"})
+
+	plan, err := manager.PlanPrune(ctx, nil, []*unstructured.Unstructured{failing, existing}, DefaultPruneOptions())
+	if err == nil {
+		t.Fatal("expected the query error")
+	}
+
+	got := make(map[string]PruneReason)
+	for _, entry := range plan.Entries {
+		got[entry.Name] = entry.Reason
+		if entry.Reason == PruneReasonError && entry.Error == "" {
+			t.Errorf("expected the error of %s in the plan", entry.Subject)
+		}
+	}
+	expected := map[string]PruneReason{
+		"existing": PruneReasonRemoved,
+		"failing":  PruneReasonError,
+	}
+	if diff := cmp.Diff(expected, got); diff != "" {
+		t.Errorf("Mismatch from expected value (-want +got):\n%s", diff)
+	}
+	if objects := plan.Objects(); len(objects) != 1 || objects[0].GetName() != "existing" {
+		t.Errorf("expected only the existing object to be pruned, got %v", objects)
+	}
+}
@@ -32,6 +32,7 @@ import (
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling"
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/aggregator"
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/collector"
+	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/engine"
 	"github.com/fluxcd/cli-utils/pkg/kstatus/polling/event"
 	"github.com/fluxcd/cli-utils/pkg/kstatus/status"
 	"github.com/fluxcd/cli-utils/pkg/object"
@@ -50,6 +51,13 @@ type WaitOptions struct {
 
 	// FailFast makes the Wait function return an error as soon as a resource reaches the failed state.
 	FailFast bool
+
+	// CustomStatusReaders are the status readers used to compute the status of
+	// the objects of the kinds they support, instead of the kstatus conventions,
+	// e.g. the readers of the custom health checks of runtime/cel. When set, the
+	// objects are polled with a status poller built from these readers instead
+	// of the poller of the manager.
+	CustomStatusReaders []engine.StatusReader
 }
 
 // DefaultWaitOptions returns the default wait options where the poll interval is set to
@@ -73,6 +81,7 @@ func (m *ResourceManager) Wait(objects []*unstructured.Unstructured, opts WaitOp
 
 // WaitForSet checks if the given set of FmtObjMetadata has been fully reconciled.
 func (m *ResourceManager) WaitForSet(set object.ObjMetadataSet, opts WaitOptions) error {
+	start := time.Now()
 	statusCollector := collector.NewResourceStatusCollector(set)
 
 	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
@@ -81,9 +90,16 @@ func (m *ResourceManager) WaitForSet(set object.ObjMetadataSet, opts WaitOptions
 	pollingOpts := polling.PollOptions{
 		PollInterval: opts.Interval,
 	}
-	eventsChan := m.poller.Poll(ctx, set, pollingOpts)
+	poller := m.poller
+	if len(opts.CustomStatusReaders) > 0 {
+		poller = polling.NewStatusPoller(m.client, m.client.RESTMapper(), polling.Options{
+			CustomStatusReaders: opts.CustomStatusReaders,
+		})
+	}
+	eventsChan := poller.Poll(ctx, set, pollingOpts)
 
 	lastStatus := make(map[object.ObjMetadata]*event.ResourceStatus)
+	readyAfter := make(map[object.ObjMetadata]time.Duration)
 
 	done := statusCollector.ListenWithObserver(eventsChan, collector.ObserverFunc(
 		func(statusCollector *collector.ResourceStatusCollector, e event.Event) {
@@ -99,6 +115,9 @@ func (m *ResourceManager) WaitForSet(set object.ObjMetadataSet, opts WaitOptions
 				if !errors.Is(rs.Error, context.DeadlineExceeded) {
 					lastStatus[rs.Identifier] = rs
 				}
+				if _, ok := readyAfter[rs.Identifier]; !ok && rs.Status == status.CurrentStatus {
+					readyAfter[rs.Identifier] = time.Since(start)
+				}
 
 				if rs.Status == status.FailedStatus {
 					countFailed++
@@ -121,10 +140,21 @@ func (m *ResourceManager) WaitForSet(set object.ObjMetadataSet, opts WaitOptions
 		return statusCollector.Error
 	}
 
+	for _, id := range set {
+		if d, ok := readyAfter[id]; ok {
+			m.metrics.recordWait(id.GroupKind, true, d)
+		} else {
+			m.metrics.recordWait(id.GroupKind, false, time.Since(start))
+		}
+	}
+
 	var errs []string
 	for id, rs := range statusCollector.ResourceStatuses {
 		switch {
 		case rs == nil || lastStatus[id] == nil:
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				m.metrics.recordWaitTimeout(id.GroupKind)
+			}
 			errs = append(errs, fmt.Sprintf("can't determine status for %s", utils.FmtObjMetadata(id)))
 		case lastStatus[id].Status == status.FailedStatus:
 			var builder strings.Builder
@@ -135,6 +165,7 @@ func (m *ResourceManager) WaitForSet(set object.ObjMetadataSet, opts WaitOptions
 			}
 			errs = append(errs, builder.String())
 		case errors.Is(ctx.Err(), context.DeadlineExceeded) && lastStatus[id].Status != status.CurrentStatus:
+			m.metrics.recordWaitTimeout(id.GroupKind)
 			var builder strings.Builder
 			builder.WriteString(fmt.Sprintf("%s status: '%s'",
 				utils.FmtObjMetadata(rs.Identifier), lastStatus[id].Status))
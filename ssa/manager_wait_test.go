@@ -72,7 +72,7 @@ func TestWaitForSet(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if err := manager.WaitForSet(changeSet.ToObjMetadataSet(), WaitOptions{time.Second, 3 * time.Second, false}); err == nil {
+		if err := manager.WaitForSet(changeSet.ToObjMetadataSet(), WaitOptions{Interval: time.Second, Timeout: 3 * time.Second}); err == nil {
 			t.Error("wanted wait error due to observedGeneration < generation")
 		}
 
@@ -277,3 +277,70 @@ func TestWaitForSet_ErrorOnReaderError(t *testing.T) {
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(Equal("timeout waiting for: [ConfigMap/default/test status: 'Unknown': error reading status]"))
 }
+
+func TestWaitForSet_CustomStatusReaders(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	id := generateName("readers")
+	configMap := &unstructured.Unstructured{
+		Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]any{
+				"name":      id,
+				"namespace": "default",
+			},
+			"data": map[string]any{
+				"phase": "Pending",
+			},
+		},
+	}
+	g.Expect(manager.client.Create(ctx, configMap)).To(Succeed())
+
+	// The ConfigMaps are always current according to kstatus.
+	rm := &ResourceManager{
+		client: manager.client,
+		poller: polling.NewStatusPoller(manager.client, restMapper, polling.Options{}),
+		owner:  manager.owner,
+	}
+	set := object.UnstructuredSetToObjMetadataSet([]*unstructured.Unstructured{configMap})
+	opts := WaitOptions{
+		Interval: 40 * time.Millisecond,
+		Timeout:  200 * time.Millisecond,
+		FailFast: true,
+		CustomStatusReaders: []engine.StatusReader{
+			kstatusreaders.NewGenericStatusReader(restMapper,
+				func(u *unstructured.Unstructured) (*status.Result, error) {
+					phase, _, _ := unstructured.NestedString(u.Object, "data", "phase")
+					switch phase {
+					case "Active":
+						return &status.Result{Status: status.CurrentStatus, Message: phase}, nil
+					case "Failed":
+						return &status.Result{Status: status.FailedStatus, Message: phase}, nil
+					default:
+						return &status.Result{Status: status.InProgressStatus, Message: phase}, nil
+					}
+				},
+			),
+		},
+	}
+
+	err := rm.WaitForSet(set, opts)
+	g.Expect(err).To(MatchError(fmt.Sprintf("timeout waiting for: [ConfigMap/default/%s status: 'InProgress']", id)))
+
+	g.Expect(unstructured.SetNestedField(configMap.Object, "Failed", "data", "phase")).To(Succeed())
+	g.Expect(manager.client.Update(ctx, configMap)).To(Succeed())
+	err = rm.WaitForSet(set, opts)
+	g.Expect(err).To(MatchError(fmt.Sprintf("failed early due to stalled resources: [ConfigMap/default/%s status: 'Failed']", id)))
+
+	g.Expect(unstructured.SetNestedField(configMap.Object, "Active", "data", "phase")).To(Succeed())
+	g.Expect(manager.client.Update(ctx, configMap)).To(Succeed())
+	g.Expect(rm.WaitForSet(set, opts)).To(Succeed())
+
+	// The poller of the manager is used without custom status readers.
+	g.Expect(unstructured.SetNestedField(configMap.Object, "Pending", "data", "phase")).To(Succeed())
+	g.Expect(manager.client.Update(ctx, configMap)).To(Succeed())
+	opts.CustomStatusReaders = nil
+	g.Expect(rm.WaitForSet(set, opts)).To(Succeed())
+}
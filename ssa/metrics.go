@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// OperationApply is the operation label value for server-side apply.
+	OperationApply = "apply"
+	// OperationDiff is the operation label value for server-side diff.
+	OperationDiff = "diff"
+	// OperationWait is the operation label value for waiting on readiness.
+	OperationWait = "wait"
+
+	// actionFailed is the action label value for failed operations.
+	actionFailed = "failed"
+	// actionReady is the action label value for objects which became ready.
+	actionReady = "ready"
+	// actionNotReady is the action label value for objects which did not
+	// become ready.
+	actionNotReady = "not_ready"
+)
+
+// Metrics holds the Prometheus metrics of the operations performed by a
+// ResourceManager, partitioned by the group and kind of the objects.
+//
+// Use NewMetrics to initialise it, register its Collectors in a metrics
+// registry, and pass it to ResourceManager.SetMetrics.
+type Metrics struct {
+	durationHistogram *prometheus.HistogramVec
+	dryRunFailures    *prometheus.CounterVec
+	conflicts         *prometheus.CounterVec
+	waitTimeouts      *prometheus.CounterVec
+}
+
+// NewMetrics returns a new Metrics with the metric names conforming to the
+// GitOps Toolkit standards.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		durationHistogram: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Name: "gotk_ssa_operation_duration_seconds",
+				Help: "The duration in seconds of a server-side apply operation on a single object.",
+				// Use a histogram with 10 count buckets between 10ms - 10min
+				Buckets: prometheus.ExponentialBucketsRange(10e-3, 600, 10),
+			},
+			[]string{"operation", "action", "group", "kind"},
+		),
+		dryRunFailures: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotk_ssa_dry_run_failures_total",
+				Help: "Total number of failed server-side apply dry-runs.",
+			},
+			[]string{"group", "kind"},
+		),
+		conflicts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotk_ssa_conflicts_total",
+				Help: "Total number of server-side apply requests rejected with a conflict.",
+			},
+			[]string{"group", "kind"},
+		),
+		waitTimeouts: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "gotk_ssa_wait_timeouts_total",
+				Help: "Total number of objects not ready when waiting for readiness timed out.",
+			},
+			[]string{"group", "kind"},
+		),
+	}
+}
+
+// Collectors returns a slice of Prometheus collectors, which can be used to
+// register them in a metrics registry.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.durationHistogram,
+		m.dryRunFailures,
+		m.conflicts,
+		m.waitTimeouts,
+	}
+}
+
+// recordOperation records the duration since start of the given operation
+// on an object. The action is the one of the change set entry, or failed if
+// err is not nil.
+func (m *Metrics) recordOperation(operation string, gk schema.GroupKind, entry *ChangeSetEntry, err error, start time.Time) {
+	if m == nil {
+		return
+	}
+	action := actionFailed
+	if err == nil && entry != nil {
+		action = string(entry.Action)
+	}
+	m.durationHistogram.WithLabelValues(operation, action, gk.Group, gk.Kind).Observe(time.Since(start).Seconds())
+}
+
+// recordWait records the time an object took to become ready, or the time
+// waited for it if it did not.
+func (m *Metrics) recordWait(gk schema.GroupKind, ready bool, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	action := actionReady
+	if !ready {
+		action = actionNotReady
+	}
+	m.durationHistogram.WithLabelValues(OperationWait, action, gk.Group, gk.Kind).Observe(duration.Seconds())
+}
+
+// recordApplyError records the dry-run failures and conflicts.
+func (m *Metrics) recordApplyError(gk schema.GroupKind, err error, dryRun bool) {
+	if m == nil || err == nil {
+		return
+	}
+	if dryRun {
+		m.dryRunFailures.WithLabelValues(gk.Group, gk.Kind).Inc()
+	}
+	if apierrors.IsConflict(err) {
+		m.conflicts.WithLabelValues(gk.Group, gk.Kind).Inc()
+	}
+}
+
+// recordWaitTimeout records an object which was not ready when waiting for
+// readiness timed out.
+func (m *Metrics) recordWaitTimeout(gk schema.GroupKind) {
+	if m == nil {
+		return
+	}
+	m.waitTimeouts.WithLabelValues(gk.Group, gk.Kind).Inc()
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestMetrics(t *testing.T) {
+	g := NewWithT(t)
+
+	m := NewMetrics()
+	reg := prometheus.NewPedanticRegistry()
+	for _, c := range m.Collectors() {
+		g.Expect(reg.Register(c)).To(Succeed())
+	}
+
+	deployment := schema.GroupKind{Group: "apps", Kind: "Deployment"}
+	conflict := apierrors.NewConflict(schema.GroupResource{Group: "apps", Resource: "deployments"}, "app", errors.New("conflict"))
+
+	m.recordApplyError(deployment, conflict, true)
+	m.recordApplyError(deployment, conflict, false)
+	m.recordApplyError(deployment, errors.New("invalid"), true)
+	m.recordApplyError(deployment, nil, true)
+	g.Expect(testutil.ToFloat64(m.dryRunFailures.WithLabelValues("apps", "Deployment"))).To(Equal(float64(2)))
+	g.Expect(testutil.ToFloat64(m.conflicts.WithLabelValues("apps", "Deployment"))).To(Equal(float64(2)))
+
+	m.recordWaitTimeout(deployment)
+	g.Expect(testutil.ToFloat64(m.waitTimeouts.WithLabelValues("apps", "Deployment"))).To(Equal(float64(1)))
+
+	start := time.Now()
+	m.recordOperation(OperationApply, deployment, &ChangeSetEntry{Action: ConfiguredAction}, nil, start)
+	m.recordOperation(OperationApply, deployment, nil, errors.New("failed"), start)
+	m.recordWait(deployment, true, time.Second)
+	g.Expect(testutil.CollectAndCount(m.durationHistogram)).To(Equal(3))
+
+	// Recording with nil metrics is a no-op.
+	var disabled *Metrics
+	disabled.recordApplyError(deployment, conflict, true)
+	disabled.recordOperation(OperationApply, deployment, nil, nil, start)
+	disabled.recordWait(deployment, false, time.Second)
+	disabled.recordWaitTimeout(deployment)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"time"
+)
+
+// Progress holds the outcome of an object of an ApplyAll or DeleteAll
+// operation.
+type Progress struct {
+	// Index is the position of the object in the sorted set of objects of
+	// the operation, starting from 0.
+	Index int
+
+	// Total is the number of objects of the operation.
+	Total int
+
+	// Entry is the ChangeSetEntry of the object, holding the action taken.
+	Entry ChangeSetEntry
+
+	// Duration is the time spent on the object, including its server-side
+	// dry-run.
+	Duration time.Duration
+
+	// Err is the error of the object, if any, in which case the action of
+	// Entry has not been taken.
+	Err error
+}
+
+// ProgressFunc is called by ApplyAll and DeleteAll once the action on an
+// object is taken, so that long operations can report their progress in
+// logs, events or a UI before the ChangeSet is returned. The function is
+// called sequentially in the order of the objects, from the goroutine of the
+// operation, and must therefore return quickly.
+type ProgressFunc func(p Progress)
+
+// report calls the ProgressFunc, if any, with the outcome of an object.
+func (f ProgressFunc) report(index, total int, entry ChangeSetEntry, duration time.Duration, err error) {
+	if f == nil {
+		return
+	}
+	f(Progress{
+		Index:    index,
+		Total:    total,
+		Entry:    entry,
+		Duration: duration,
+		Err:      err,
+	})
+}
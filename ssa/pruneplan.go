@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// PruneReason is the reason for which an object of the previous inventory
+// is pruned or not.
+type PruneReason string
+
+// String returns the string representation of the reason.
+func (r PruneReason) String() string {
+	return string(r)
+}
+
+const (
+	// PruneReasonRemoved is the reason of the objects which have been
+	// removed from the source, and are pruned.
+	PruneReasonRemoved PruneReason = "RemovedFromSource"
+	// PruneReasonExcluded is the reason of the objects which have been
+	// removed from the source, but are excluded from pruning by the
+	// selectors of the options.
+	PruneReasonExcluded PruneReason = "ExcludedBySelector"
+	// PruneReasonProtected is the reason of the objects which have been
+	// removed from the source, but are of a protected kind.
+	PruneReasonProtected PruneReason = "ProtectedKind"
+	// PruneReasonKindNotServed is the reason of the objects whose kind is
+	// no longer served by the API server, which have been deleted along
+	// with their kind.
+	PruneReasonKindNotServed PruneReason = "KindNotServed"
+	// PruneReasonNotFound is the reason of the objects which have already
+	// been deleted from the cluster.
+	PruneReasonNotFound PruneReason = "NotFound"
+	// PruneReasonError is the reason of the objects which could not be
+	// queried, and whose pruning is unknown.
+	PruneReasonError PruneReason = "Error"
+)
+
+// action returns the action reported by PruneAll for the objects which are
+// not deleted for the reason.
+func (r PruneReason) action() Action {
+	switch r {
+	case PruneReasonExcluded, PruneReasonProtected:
+		return SkippedAction
+	case PruneReasonKindNotServed, PruneReasonNotFound:
+		return DeletedAction
+	default:
+		return UnknownAction
+	}
+}
+
+// PrunePlan holds the objects of a previous inventory which have been
+// removed from the source, and whether they would be pruned. It can be
+// serialized to JSON, e.g. to be previewed in an event or a pull request
+// comment before the objects are deleted.
+type PrunePlan struct {
+	Entries []PrunePlanEntry `json:"entries"`
+}
+
+// PrunePlanEntry defines whether an object would be pruned, and why.
+type PrunePlanEntry struct {
+	// Subject represents the object ID in the format 'kind/namespace/name'.
+	Subject string `json:"subject"`
+
+	// APIVersion is the API version of the object in the inventory.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind is the kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the object, empty if cluster-scoped.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object.
+	Name string `json:"name"`
+
+	// Prune is true if the object would be deleted.
+	Prune bool `json:"prune"`
+
+	// Reason is the reason for which the object would be deleted or not.
+	Reason PruneReason `json:"reason"`
+
+	// PropagationPolicy is the propagation policy of the deletion of the
+	// object, empty if the object would not be deleted.
+	PropagationPolicy metav1.DeletionPropagation `json:"propagationPolicy,omitempty"`
+
+	// Error is the error which occurred while querying the object, if the
+	// reason is PruneReasonError.
+	Error string `json:"error,omitempty"`
+}
+
+// String returns the entry in the format 'kind/namespace/name reason'.
+func (e PrunePlanEntry) String() string {
+	return fmt.Sprintf("%s %s", e.Subject, e.Reason)
+}
+
+// ToUnstructured returns an object with the API version, kind, namespace
+// and name of the entry, which can be deleted with DeleteAll.
+func (e PrunePlanEntry) ToUnstructured() *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(schema.FromAPIVersionAndKind(e.APIVersion, e.Kind))
+	u.SetNamespace(e.Namespace)
+	u.SetName(e.Name)
+	return u
+}
+
+// String returns a line per entry in the format 'kind/namespace/name reason'.
+func (p *PrunePlan) String() string {
+	var b strings.Builder
+	for _, entry := range p.Entries {
+		b.WriteString(entry.String() + "\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// Objects returns the objects which would be pruned, in the deletion order.
+func (p *PrunePlan) Objects() []*unstructured.Unstructured {
+	var objects []*unstructured.Unstructured
+	for _, entry := range p.Entries {
+		if entry.Prune {
+			objects = append(objects, entry.ToUnstructured())
+		}
+	}
+	return objects
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrunePlan(t *testing.T) {
+	g := NewWithT(t)
+
+	plan := &PrunePlan{Entries: []PrunePlanEntry{
+		{
+			Subject:           "ConfigMap/apps/config",
+			APIVersion:        "v1",
+			Kind:              "ConfigMap",
+			Namespace:         "apps",
+			Name:              "config",
+			Prune:             true,
+			Reason:            PruneReasonRemoved,
+			PropagationPolicy: metav1.DeletePropagationBackground,
+		},
+		{
+			Subject:    "Namespace/apps",
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       "apps",
+			Reason:     PruneReasonProtected,
+		},
+		{
+			Subject:    "ClusterRole/apps",
+			APIVersion: "rbac.authorization.k8s.io/v1",
+			Kind:       "ClusterRole",
+			Name:       "apps",
+			Prune:      true,
+			Reason:     PruneReasonRemoved,
+		},
+	}}
+
+	g.Expect(plan.String()).To(Equal("ConfigMap/apps/config RemovedFromSource\n" +
+		"Namespace/apps ProtectedKind\n" +
+		"ClusterRole/apps RemovedFromSource"))
+
+	// The plan can be previewed, and the objects deleted, from its JSON.
+	data, err := json.Marshal(plan)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(ContainSubstring(`{"subject":"Namespace/apps","apiVersion":"v1","kind":"Namespace","name":"apps","prune":false,"reason":"ProtectedKind"}`))
+	var decoded PrunePlan
+	g.Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+	g.Expect(&decoded).To(Equal(plan))
+
+	objects := decoded.Objects()
+	g.Expect(objects).To(HaveLen(2))
+	g.Expect(objects[0].GetAPIVersion()).To(Equal("v1"))
+	g.Expect(objects[0].GetKind()).To(Equal("ConfigMap"))
+	g.Expect(objects[0].GetNamespace()).To(Equal("apps"))
+	g.Expect(objects[0].GetName()).To(Equal("config"))
+	g.Expect(objects[1].GroupVersionKind().Group).To(Equal("rbac.authorization.k8s.io"))
+	g.Expect(objects[1].GetName()).To(Equal("apps"))
+}
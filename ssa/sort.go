@@ -18,12 +18,16 @@ limitations under the License.
 package ssa
 
 import (
+	"fmt"
 	"sort"
+	"strconv"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/fluxcd/cli-utils/pkg/object"
+
+	"github.com/fluxcd/pkg/ssa/utils"
 )
 
 type KindOrder struct {
@@ -65,6 +69,26 @@ var ReconcileOrder = KindOrder{
 	},
 }
 
+// ApplyWeightAnnotation is the annotation which adjusts the order in which
+// an object is applied among the objects of the same kind. The objects are
+// ordered by kind according to ReconcileOrder, and then by ascending weight.
+// The weight is an integer, and defaults to zero.
+const ApplyWeightAnnotation = "fluxcd.io/apply-weight"
+
+// ApplyWeight returns the value of the ApplyWeightAnnotation of the object,
+// or zero if not set.
+func ApplyWeight(object *unstructured.Unstructured) (int, error) {
+	value, ok := object.GetAnnotations()[ApplyWeightAnnotation]
+	if !ok {
+		return 0, nil
+	}
+	weight, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation value '%s': must be an integer", ApplyWeightAnnotation, value)
+	}
+	return weight, nil
+}
+
 type SortableUnstructureds []*unstructured.Unstructured
 
 var _ sort.Interface = SortableUnstructureds{}
@@ -77,6 +101,57 @@ func (a SortableUnstructureds) Less(i, j int) bool {
 	return less(first, second)
 }
 
+// weightedUnstructureds sorts objects by kind according to ReconcileOrder,
+// and then the objects of the same kind by their ApplyWeightAnnotation.
+type weightedUnstructureds struct {
+	objects []*unstructured.Unstructured
+	metas   []object.ObjMetadata
+	weights []int
+}
+
+var _ sort.Interface = weightedUnstructureds{}
+
+// newWeightedUnstructureds parses the metadata and weights of the objects
+// once for sorting them. Invalid weights are treated as zero, and the error
+// of the first invalid weight is returned.
+func newWeightedUnstructureds(objects []*unstructured.Unstructured) (weightedUnstructureds, error) {
+	w := weightedUnstructureds{
+		objects: objects,
+		metas:   make([]object.ObjMetadata, len(objects)),
+		weights: make([]int, len(objects)),
+	}
+	var firstErr error
+	for i, o := range objects {
+		w.metas[i] = object.UnstructuredToObjMetadata(o)
+		weight, err := ApplyWeight(o)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("%s %w", utils.FmtUnstructured(o), err)
+		}
+		w.weights[i] = weight
+	}
+	return w, firstErr
+}
+
+// sortForDeletion sorts the objects in the reverse order in which they are
+// applied, ignoring invalid weights.
+func sortForDeletion(objects []*unstructured.Unstructured) {
+	w, _ := newWeightedUnstructureds(objects)
+	sort.Sort(sort.Reverse(w))
+}
+
+func (a weightedUnstructureds) Len() int { return len(a.objects) }
+func (a weightedUnstructureds) Swap(i, j int) {
+	a.objects[i], a.objects[j] = a.objects[j], a.objects[i]
+	a.metas[i], a.metas[j] = a.metas[j], a.metas[i]
+	a.weights[i], a.weights[j] = a.weights[j], a.weights[i]
+}
+func (a weightedUnstructureds) Less(i, j int) bool {
+	if Equals(a.metas[i].GroupKind, a.metas[j].GroupKind) && a.weights[i] != a.weights[j] {
+		return a.weights[i] < a.weights[j]
+	}
+	return less(a.metas[i], a.metas[j])
+}
+
 type SortableMetas []object.ObjMetadata
 
 var _ sort.Interface = SortableMetas{}
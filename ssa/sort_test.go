@@ -0,0 +1,104 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssa
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/fluxcd/pkg/ssa/utils"
+)
+
+func TestWeightedUnstructureds(t *testing.T) {
+	g := NewWithT(t)
+
+	newObject := func(kind, name, weight string) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind(kind)
+		u.SetNamespace("default")
+		u.SetName(name)
+		if weight != "" {
+			u.SetAnnotations(map[string]string{ApplyWeightAnnotation: weight})
+		}
+		return u
+	}
+	names := func(objects []*unstructured.Unstructured) []string {
+		var names []string
+		for _, o := range objects {
+			names = append(names, o.GetName())
+		}
+		return names
+	}
+
+	objects := []*unstructured.Unstructured{
+		newObject("Deployment", "app", ""),
+		newObject("ConfigMap", "config", ""),
+		newObject("Service", "late", "10"),
+		newObject("ConfigMap", "webhook-config", "-1"),
+		newObject("Secret", "invalid", "first"),
+		newObject("ServiceAccount", "sa", "0"),
+		newObject("Namespace", "heavy", "100"),
+	}
+
+	sorted, err := newWeightedUnstructureds(objects)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("Secret/default/invalid"))
+	sort.Sort(sorted)
+	g.Expect(names(objects)).To(Equal([]string{"heavy", "sa", "webhook-config", "config", "invalid", "late", "app"}))
+
+	sortForDeletion(objects)
+	g.Expect(names(objects)).To(Equal([]string{"app", "late", "invalid", "config", "webhook-config", "sa", "heavy"}))
+}
+
+func TestApplyWeight(t *testing.T) {
+	g := NewWithT(t)
+
+	u := &unstructured.Unstructured{}
+	weight, err := ApplyWeight(u)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(weight).To(Equal(0))
+
+	u.SetAnnotations(map[string]string{ApplyWeightAnnotation: "-5"})
+	weight, err = ApplyWeight(u)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(weight).To(Equal(-5))
+
+	u.SetAnnotations(map[string]string{ApplyWeightAnnotation: "1.5"})
+	_, err = ApplyWeight(u)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("invalid fluxcd.io/apply-weight annotation value '1.5'"))
+}
+
+func TestApplyAll_invalidApplyWeight(t *testing.T) {
+	g := NewWithT(t)
+
+	id := generateName("weight")
+	objects, err := readManifest("testdata/test1.yaml", id)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	_, configMap := getFirstObject(objects, "ConfigMap", id)
+	configMap.SetAnnotations(map[string]string{ApplyWeightAnnotation: "high"})
+
+	_, err = manager.ApplyAll(context.Background(), objects, DefaultApplyOptions())
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring(utils.FmtUnstructured(configMap)))
+}
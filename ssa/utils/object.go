@@ -28,7 +28,12 @@ import (
 )
 
 // ReadObject decodes a YAML or JSON document from the given reader into an unstructured Kubernetes API object.
-func ReadObject(r io.Reader) (*unstructured.Unstructured, error) {
+// The size of the input can be limited with WithMaxSize.
+func ReadObject(r io.Reader, opts ...ReadOption) (*unstructured.Unstructured, error) {
+	o := makeReadOptions(opts)
+	if o.maxSize > 0 {
+		r = &limitedReader{r: r, max: o.maxSize}
+	}
 	reader := yamlutil.NewYAMLOrJSONDecoder(r, 2048)
 	obj := &unstructured.Unstructured{}
 	err := reader.Decode(obj)
@@ -41,10 +46,33 @@ func ReadObject(r io.Reader) (*unstructured.Unstructured, error) {
 
 // ReadObjects decodes the YAML or JSON documents from the given reader into unstructured Kubernetes API objects.
 // The documents which do not subscribe to the Kubernetes Object interface, are silently dropped from the result.
-func ReadObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
+//
+// The size and the number of documents of the input can be limited with WithMaxSize and WithMaxDocuments, and the
+// objects defined more than once can be rejected or reported with WithDuplicates. The errors of a document are
+// returned as a *DocumentError with the index of the document.
+func ReadObjects(r io.Reader, opts ...ReadOption) ([]*unstructured.Unstructured, error) {
+	o := makeReadOptions(opts)
+	if o.maxSize > 0 {
+		r = &limitedReader{r: r, max: o.maxSize}
+	}
 	reader := yamlutil.NewYAMLOrJSONDecoder(r, 2048)
 	objects := make([]*unstructured.Unstructured, 0)
 
+	var duplicates *duplicateTracker
+	if o.duplicates != DuplicatesAllow {
+		duplicates = newDuplicateTracker()
+	}
+	add := func(obj *unstructured.Unstructured, index int) error {
+		objects = append(objects, obj)
+		if duplicates != nil && duplicates.add(obj, index) && o.duplicates == DuplicatesReject {
+			return duplicates.err()
+		}
+		return nil
+	}
+
+	// index is the index of the document in the input, not counting the
+	// empty documents.
+	index := -1
 	for {
 		obj := &unstructured.Unstructured{}
 		err := reader.Decode(obj)
@@ -53,14 +81,19 @@ func ReadObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
 				err = nil
 				break
 			}
-			return objects, err
+			return objects, &DocumentError{Index: index + 1, Err: err}
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		index++
+		if o.maxDocuments > 0 && index >= o.maxDocuments {
+			return objects, &DocumentError{Index: index, Err: ErrMaxDocumentsExceeded}
 		}
 
 		if obj.IsList() {
 			err = obj.EachListItem(func(item runtime.Object) error {
-				obj := item.(*unstructured.Unstructured)
-				objects = append(objects, obj)
-				return nil
+				return add(item.(*unstructured.Unstructured), index)
 			})
 			if err != nil {
 				return objects, err
@@ -69,10 +102,15 @@ func ReadObjects(r io.Reader) ([]*unstructured.Unstructured, error) {
 		}
 
 		if IsKubernetesObject(obj) && !IsKustomization(obj) {
-			objects = append(objects, obj)
+			if err := add(obj, index); err != nil {
+				return objects, err
+			}
 		}
 	}
 
+	if duplicates != nil {
+		return objects, duplicates.err()
+	}
 	return objects, nil
 }
 
@@ -17,6 +17,7 @@ limitations under the License.
 package utils
 
 import (
+	"errors"
 	"strings"
 	"testing"
 )
@@ -90,3 +91,125 @@ stringData:
 		})
 	}
 }
+
+func TestReadObjects_Options(t *testing.T) {
+	resources := `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test
+  namespace: default
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: other
+    namespace: default
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: test
+    namespace: default
+`
+
+	t.Run("max size", func(t *testing.T) {
+		_, err := ReadObjects(strings.NewReader(resources), WithMaxSize(64))
+		if !errors.Is(err, ErrMaxSizeExceeded) {
+			t.Errorf("expected max size error, got: %v", err)
+		}
+		if _, err := ReadObjects(strings.NewReader(resources), WithMaxSize(int64(len(resources)))); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if _, err := ReadObject(strings.NewReader(resources[5:]), WithMaxSize(16)); !errors.Is(err, ErrMaxSizeExceeded) {
+			t.Errorf("expected max size error, got: %v", err)
+		}
+	})
+
+	t.Run("max documents", func(t *testing.T) {
+		objects, err := ReadObjects(strings.NewReader(resources), WithMaxDocuments(2))
+		var docErr *DocumentError
+		if !errors.Is(err, ErrMaxDocumentsExceeded) || !errors.As(err, &docErr) || docErr.Index != 2 {
+			t.Errorf("expected max documents error at index 2, got: %v", err)
+		}
+		if len(objects) != 2 {
+			t.Errorf("unexpected number of objects in %v", objects)
+		}
+		if _, err := ReadObjects(strings.NewReader(resources), WithMaxDocuments(3)); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("allow duplicates", func(t *testing.T) {
+		objects, err := ReadObjects(strings.NewReader(resources))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if len(objects) != 4 {
+			t.Errorf("unexpected number of objects in %v", objects)
+		}
+	})
+
+	t.Run("reject duplicates", func(t *testing.T) {
+		objects, err := ReadObjects(strings.NewReader(resources), WithDuplicates(DuplicatesReject))
+		var dupErr *DuplicateObjectsError
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("expected duplicate objects error, got: %v", err)
+		}
+		if len(objects) != 4 {
+			t.Errorf("unexpected number of objects in %v", objects)
+		}
+		expected := "duplicate objects: ConfigMap/default/test defined in documents at indexes 0, 2"
+		if err.Error() != expected {
+			t.Errorf("expected error %q, got %q", expected, err.Error())
+		}
+	})
+
+	t.Run("report duplicates", func(t *testing.T) {
+		duplicated := resources + `---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test
+  namespace: default
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: test
+  namespace: other
+`
+		objects, err := ReadObjects(strings.NewReader(duplicated), WithDuplicates(DuplicatesReport))
+		var dupErr *DuplicateObjectsError
+		if !errors.As(err, &dupErr) {
+			t.Fatalf("expected duplicate objects error, got: %v", err)
+		}
+		if len(objects) != 6 {
+			t.Errorf("unexpected number of objects in %v", objects)
+		}
+		if len(dupErr.Duplicates) != 2 {
+			t.Fatalf("unexpected duplicates %v", dupErr.Duplicates)
+		}
+		if d := dupErr.Duplicates[1]; d.ID != "Secret/default/test" || len(d.Indexes) != 2 || d.Indexes[0] != 1 || d.Indexes[1] != 3 {
+			t.Errorf("unexpected duplicate %v", d)
+		}
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		invalid := resources + "---\napiVersion: v1\nkind: [\n"
+		_, err := ReadObjects(strings.NewReader(invalid))
+		var docErr *DocumentError
+		if !errors.As(err, &docErr) || docErr.Index != 3 {
+			t.Errorf("expected error at index 3, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	// ErrMaxSizeExceeded is returned when the input is larger than the
+	// maximum size set with WithMaxSize.
+	ErrMaxSizeExceeded = errors.New("maximum input size exceeded")
+	// ErrMaxDocumentsExceeded is returned when the input has more documents
+	// than the maximum set with WithMaxDocuments.
+	ErrMaxDocumentsExceeded = errors.New("maximum number of documents exceeded")
+)
+
+// DuplicatePolicy is the handling of the objects defined more than once in
+// the documents read with ReadObjects.
+type DuplicatePolicy int
+
+const (
+	// DuplicatesAllow returns all the objects, including the duplicates.
+	DuplicatesAllow DuplicatePolicy = iota
+	// DuplicatesReject stops reading at the first duplicate, and returns a
+	// *DuplicateObjectsError.
+	DuplicatesReject
+	// DuplicatesReport reads all the documents, and returns all the objects
+	// with a *DuplicateObjectsError listing all the duplicates.
+	DuplicatesReport
+)
+
+// ReadOption is an option of ReadObject and ReadObjects.
+type ReadOption func(*readOptions)
+
+type readOptions struct {
+	maxSize      int64
+	maxDocuments int
+	duplicates   DuplicatePolicy
+}
+
+// WithMaxSize sets the maximum size in bytes of the input. Reading more
+// fails with ErrMaxSizeExceeded. Zero means no limit.
+func WithMaxSize(bytes int64) ReadOption {
+	return func(o *readOptions) {
+		o.maxSize = bytes
+	}
+}
+
+// WithMaxDocuments sets the maximum number of documents of the input.
+// Reading more fails with ErrMaxDocumentsExceeded. Zero means no limit.
+func WithMaxDocuments(n int) ReadOption {
+	return func(o *readOptions) {
+		o.maxDocuments = n
+	}
+}
+
+// WithDuplicates sets the handling of the objects defined more than once,
+// i.e. with the same group, kind, namespace and name. Defaults to
+// DuplicatesAllow.
+func WithDuplicates(policy DuplicatePolicy) ReadOption {
+	return func(o *readOptions) {
+		o.duplicates = policy
+	}
+}
+
+func makeReadOptions(opts []ReadOption) *readOptions {
+	o := &readOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// DocumentError is an error of a document of the input, with the index of
+// the document, starting at 0.
+type DocumentError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the error message.
+func (e *DocumentError) Error() string {
+	return fmt.Sprintf("document at index %d: %s", e.Index, e.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (e *DocumentError) Unwrap() error {
+	return e.Err
+}
+
+// DuplicateObject is an object defined in several documents.
+type DuplicateObject struct {
+	// ID is the object ID in the format <kind>/<namespace>/<name>.
+	ID string
+	// Indexes are the indexes of the documents defining the object.
+	Indexes []int
+}
+
+// DuplicateObjectsError is returned when objects are defined more than once
+// in the input.
+type DuplicateObjectsError struct {
+	Duplicates []DuplicateObject
+}
+
+// Error returns the error message.
+func (e *DuplicateObjectsError) Error() string {
+	var b strings.Builder
+	b.WriteString("duplicate objects:")
+	for i, d := range e.Duplicates {
+		if i > 0 {
+			b.WriteString(",")
+		}
+		indexes := make([]string, len(d.Indexes))
+		for j, index := range d.Indexes {
+			indexes[j] = strconv.Itoa(index)
+		}
+		fmt.Fprintf(&b, " %s defined in documents at indexes %s", d.ID, strings.Join(indexes, ", "))
+	}
+	return b.String()
+}
+
+// duplicateTracker records the documents defining each object.
+type duplicateTracker struct {
+	objects map[string]*DuplicateObject
+	keys    []string
+}
+
+func newDuplicateTracker() *duplicateTracker {
+	return &duplicateTracker{objects: make(map[string]*DuplicateObject)}
+}
+
+// add records the object defined in the document, and returns true if it
+// was already defined in another document.
+func (t *duplicateTracker) add(obj *unstructured.Unstructured, index int) bool {
+	gvk := obj.GroupVersionKind()
+	key := strings.Join([]string{gvk.Group, gvk.Kind, obj.GetNamespace(), obj.GetName()}, "/")
+	d, found := t.objects[key]
+	if !found {
+		d = &DuplicateObject{ID: FmtUnstructured(obj)}
+		t.objects[key] = d
+		t.keys = append(t.keys, key)
+	}
+	d.Indexes = append(d.Indexes, index)
+	return found
+}
+
+// err returns a *DuplicateObjectsError of the objects defined more than
+// once, or nil.
+func (t *duplicateTracker) err() error {
+	var duplicates []DuplicateObject
+	for _, key := range t.keys {
+		if d := t.objects[key]; len(d.Indexes) > 1 {
+			duplicates = append(duplicates, *d)
+		}
+	}
+	if len(duplicates) == 0 {
+		return nil
+	}
+	return &DuplicateObjectsError{Duplicates: duplicates}
+}
+
+// limitedReader fails with ErrMaxSizeExceeded when the underlying reader
+// has more than max bytes. It never returns more than max bytes, so that a
+// truncated document is not decoded.
+type limitedReader struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if l.n >= l.max {
+		// Check whether the input ends at the limit.
+		var b [1]byte
+		n, err := l.r.Read(b[:])
+		if n > 0 {
+			return 0, ErrMaxSizeExceeded
+		}
+		if err == nil {
+			return 0, nil
+		}
+		return 0, err
+	}
+	if remaining := l.max - l.n; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	return n, err
+}
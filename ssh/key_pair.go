@@ -17,6 +17,7 @@ limitations under the License.
 package ssh
 
 import (
+	"crypto"
 	"crypto/ecdsa"
 	"crypto/ed25519"
 	"crypto/elliptic"
@@ -54,30 +55,95 @@ const (
 	ED25519 KeyPairType = "ed25519"
 )
 
+// KeyFormat is the encoding format of a private key.
+type KeyFormat string
+
+const (
+	// PKCS8 represents the PKCS#8 format, in a "PRIVATE KEY" PEM block.
+	PKCS8 KeyFormat = "pkcs8"
+	// OpenSSH represents the OpenSSH format, in an "OPENSSH PRIVATE KEY"
+	// PEM block. It is the only format supporting passphrase encryption.
+	OpenSSH KeyFormat = "openssh"
+)
+
+// keyPairOptions holds the options for encoding the private key of a
+// keypair.
+type keyPairOptions struct {
+	format     KeyFormat
+	passphrase []byte
+}
+
+// KeyPairOption configures the encoding of the private key of a keypair.
+type KeyPairOption func(*keyPairOptions)
+
+// WithKeyFormat sets the format of the private key. It defaults to PKCS8,
+// or to OpenSSH if a passphrase is set.
+func WithKeyFormat(format KeyFormat) KeyPairOption {
+	return func(o *keyPairOptions) {
+		o.format = format
+	}
+}
+
+// WithPassphrase encrypts the private key with the given passphrase.
+// Encryption is only supported by the OpenSSH format.
+func WithPassphrase(passphrase []byte) KeyPairOption {
+	return func(o *keyPairOptions) {
+		o.passphrase = passphrase
+	}
+}
+
 // GenerateKeyPair generates a keypair based on KeyPairType.
-func GenerateKeyPair(keyType KeyPairType) (*KeyPair, error) {
+func GenerateKeyPair(keyType KeyPairType, opts ...KeyPairOption) (*KeyPair, error) {
 	switch keyType {
 	case RSA_4096:
-		return NewRSAGenerator(4096).Generate()
+		return NewRSAGenerator(4096, opts...).Generate()
 	case ECDSA_P256:
-		return NewECDSAGenerator(elliptic.P256()).Generate()
+		return NewECDSAGenerator(elliptic.P256(), opts...).Generate()
 	case ECDSA_P384:
-		return NewECDSAGenerator(elliptic.P384()).Generate()
+		return NewECDSAGenerator(elliptic.P384(), opts...).Generate()
 	case ECDSA_P521:
-		return NewECDSAGenerator(elliptic.P521()).Generate()
+		return NewECDSAGenerator(elliptic.P521(), opts...).Generate()
 	case ED25519:
-		return NewEd25519Generator().Generate()
+		return NewEd25519Generator(opts...).Generate()
 	default:
 		return nil, fmt.Errorf("unsupported key type: %s", keyType)
 	}
 }
 
+// ConvertPrivateKey converts the given PEM encoded private key to the format
+// and encryption set by the options. The private key can be in the PKCS#1,
+// PKCS#8, SEC 1 or OpenSSH format, and is decrypted with the given
+// passphrase if encrypted.
+func ConvertPrivateKey(privateKey, passphrase []byte, opts ...KeyPairOption) ([]byte, error) {
+	pk, err := parsePrivateKey(privateKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return encodePrivateKey(pk, opts...)
+}
+
+// PublicKeyFromPrivateKey returns the public key of the given PEM encoded
+// private key, in the authorized_keys format. The private key is decrypted
+// with the given passphrase if encrypted.
+func PublicKeyFromPrivateKey(privateKey, passphrase []byte) ([]byte, error) {
+	pk, err := parsePrivateKey(privateKey, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := pk.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type: %T", pk)
+	}
+	return generatePublicKey(signer.Public())
+}
+
 type RSAGenerator struct {
 	bits int
+	opts []KeyPairOption
 }
 
-func NewRSAGenerator(bits int) KeyPairGenerator {
-	return &RSAGenerator{bits}
+func NewRSAGenerator(bits int, opts ...KeyPairOption) KeyPairGenerator {
+	return &RSAGenerator{bits, opts}
 }
 
 func (g *RSAGenerator) Generate() (*KeyPair, error) {
@@ -93,7 +159,7 @@ func (g *RSAGenerator) Generate() (*KeyPair, error) {
 	if err != nil {
 		return nil, err
 	}
-	priv, err := encodePrivateKeyToPEM(pk)
+	priv, err := encodePrivateKey(pk, g.opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -104,11 +170,12 @@ func (g *RSAGenerator) Generate() (*KeyPair, error) {
 }
 
 type ECDSAGenerator struct {
-	c elliptic.Curve
+	c    elliptic.Curve
+	opts []KeyPairOption
 }
 
-func NewECDSAGenerator(c elliptic.Curve) KeyPairGenerator {
-	return &ECDSAGenerator{c}
+func NewECDSAGenerator(c elliptic.Curve, opts ...KeyPairOption) KeyPairGenerator {
+	return &ECDSAGenerator{c, opts}
 }
 
 func (g *ECDSAGenerator) Generate() (*KeyPair, error) {
@@ -120,7 +187,7 @@ func (g *ECDSAGenerator) Generate() (*KeyPair, error) {
 	if err != nil {
 		return nil, err
 	}
-	priv, err := encodePrivateKeyToPEM(pk)
+	priv, err := encodePrivateKey(pk, g.opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -130,10 +197,12 @@ func (g *ECDSAGenerator) Generate() (*KeyPair, error) {
 	}, nil
 }
 
-type Ed25519Generator struct{}
+type Ed25519Generator struct {
+	opts []KeyPairOption
+}
 
-func NewEd25519Generator() KeyPairGenerator {
-	return &Ed25519Generator{}
+func NewEd25519Generator(opts ...KeyPairOption) KeyPairGenerator {
+	return &Ed25519Generator{opts}
 }
 
 func (g *Ed25519Generator) Generate() (*KeyPair, error) {
@@ -145,7 +214,7 @@ func (g *Ed25519Generator) Generate() (*KeyPair, error) {
 	if err != nil {
 		return nil, err
 	}
-	priv, err := encodePrivateKeyToPEM(pv)
+	priv, err := encodePrivateKey(pv, g.opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -178,3 +247,62 @@ func encodePrivateKeyToPEM(pk interface{}) ([]byte, error) {
 	}
 	return pem.EncodeToMemory(&block), nil
 }
+
+// encodePrivateKey encodes the given private key to a PEM block in the
+// format and with the encryption set by the options.
+func encodePrivateKey(pk interface{}, opts ...KeyPairOption) ([]byte, error) {
+	o := keyPairOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	format := o.format
+	if format == "" {
+		format = PKCS8
+		if len(o.passphrase) > 0 {
+			format = OpenSSH
+		}
+	}
+
+	switch format {
+	case PKCS8:
+		if len(o.passphrase) > 0 {
+			return nil, fmt.Errorf("passphrase encryption is not supported by the %s format, use the %s format", PKCS8, OpenSSH)
+		}
+		return encodePrivateKeyToPEM(pk)
+	case OpenSSH:
+		var block *pem.Block
+		var err error
+		if len(o.passphrase) > 0 {
+			block, err = ssh.MarshalPrivateKeyWithPassphrase(pk, "", o.passphrase)
+		} else {
+			block, err = ssh.MarshalPrivateKey(pk, "")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(block), nil
+	default:
+		return nil, fmt.Errorf("unsupported key format: %s", format)
+	}
+}
+
+// parsePrivateKey parses the given PEM encoded private key, decrypting it
+// with the passphrase if encrypted.
+func parsePrivateKey(privateKey, passphrase []byte) (interface{}, error) {
+	var pk interface{}
+	var err error
+	if len(passphrase) > 0 {
+		pk, err = ssh.ParseRawPrivateKeyWithPassphrase(privateKey, passphrase)
+	} else {
+		pk, err = ssh.ParseRawPrivateKey(privateKey)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	// Keys in the OpenSSH format are parsed as pointers to ed25519 keys,
+	// which can't be encoded to the PKCS#8 format.
+	if k, ok := pk.(*ed25519.PrivateKey); ok {
+		pk = *k
+	}
+	return pk, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2026 The Flux authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ssh
+
+import (
+	"crypto/elliptic"
+	"encoding/pem"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestGenerateKeyPair_Formats(t *testing.T) {
+	passphrase := []byte("passphrase")
+
+	tests := []struct {
+		name          string
+		generator     KeyPairGenerator
+		wantPEMType   string
+		wantEncrypted bool
+		wantErr       string
+	}{
+		{
+			name:        "default format",
+			generator:   NewEd25519Generator(),
+			wantPEMType: "PRIVATE KEY",
+		},
+		{
+			name:        "OpenSSH format",
+			generator:   NewECDSAGenerator(elliptic.P256(), WithKeyFormat(OpenSSH)),
+			wantPEMType: "OPENSSH PRIVATE KEY",
+		},
+		{
+			name:          "passphrase defaults to the OpenSSH format",
+			generator:     NewEd25519Generator(WithPassphrase(passphrase)),
+			wantPEMType:   "OPENSSH PRIVATE KEY",
+			wantEncrypted: true,
+		},
+		{
+			name:          "RSA key with passphrase",
+			generator:     NewRSAGenerator(2048, WithKeyFormat(OpenSSH), WithPassphrase(passphrase)),
+			wantPEMType:   "OPENSSH PRIVATE KEY",
+			wantEncrypted: true,
+		},
+		{
+			name:      "PKCS8 format with passphrase",
+			generator: NewEd25519Generator(WithKeyFormat(PKCS8), WithPassphrase(passphrase)),
+			wantErr:   "passphrase encryption is not supported by the pkcs8 format, use the openssh format",
+		},
+		{
+			name:      "unsupported format",
+			generator: NewEd25519Generator(WithKeyFormat("pkcs12")),
+			wantErr:   "unsupported key format: pkcs12",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kp, err := tt.generator.Generate()
+			if tt.wantErr != "" {
+				g.Expect(err).To(MatchError(tt.wantErr))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			block, _ := pem.Decode(kp.PrivateKey)
+			g.Expect(block).ToNot(BeNil())
+			g.Expect(block.Type).To(Equal(tt.wantPEMType))
+
+			var signer ssh.Signer
+			if tt.wantEncrypted {
+				_, err = ssh.ParsePrivateKey(kp.PrivateKey)
+				g.Expect(err).To(BeAssignableToTypeOf(&ssh.PassphraseMissingError{}))
+				signer, err = ssh.ParsePrivateKeyWithPassphrase(kp.PrivateKey, passphrase)
+			} else {
+				signer, err = ssh.ParsePrivateKey(kp.PrivateKey)
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(ssh.MarshalAuthorizedKey(signer.PublicKey())).To(Equal(kp.PublicKey))
+		})
+	}
+}
+
+func TestConvertPrivateKey(t *testing.T) {
+	g := NewWithT(t)
+
+	kp, err := GenerateKeyPair(ED25519)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// PKCS#8 to encrypted OpenSSH.
+	passphrase := []byte("passphrase")
+	encrypted, err := ConvertPrivateKey(kp.PrivateKey, nil, WithPassphrase(passphrase))
+	g.Expect(err).ToNot(HaveOccurred())
+	block, _ := pem.Decode(encrypted)
+	g.Expect(block.Type).To(Equal("OPENSSH PRIVATE KEY"))
+
+	_, err = ConvertPrivateKey(encrypted, nil)
+	g.Expect(err).To(MatchError(ContainSubstring("failed to parse private key")))
+
+	pub, err := PublicKeyFromPrivateKey(encrypted, passphrase)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pub).To(Equal(kp.PublicKey))
+
+	// Encrypted OpenSSH back to PKCS#8.
+	decrypted, err := ConvertPrivateKey(encrypted, passphrase, WithKeyFormat(PKCS8))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(decrypted).To(Equal(kp.PrivateKey))
+}
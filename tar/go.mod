@@ -2,6 +2,9 @@ module github.com/fluxcd/pkg/tar
 
 go 1.23.0
 
-require github.com/cyphar/filepath-securejoin v0.4.1
+require (
+	github.com/cyphar/filepath-securejoin v0.4.1
+	github.com/go-git/go-billy/v5 v5.6.2
+)
 
 require golang.org/x/sys v0.29.0 // indirect
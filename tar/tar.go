@@ -22,6 +22,7 @@ import (
 	"time"
 
 	securejoin "github.com/cyphar/filepath-securejoin"
+	"github.com/go-git/go-billy/v5"
 )
 
 const (
@@ -80,6 +81,97 @@ func Untar(r io.Reader, dir string, inOpts ...TarOption) (err error) {
 		return fmt.Errorf("dir '%s' must be a directory", dir)
 	}
 
+	return untar(r, &osTarget{dir: dir}, opts)
+}
+
+// UntarFS reads the gzip-compressed tar file from r and writes it into the
+// root of the given filesystem, e.g. a memfs.Memory filesystem to extract
+// an archive in memory.
+func UntarFS(r io.Reader, fsys billy.Filesystem, inOpts ...TarOption) error {
+	opts := tarOpts{
+		maxUntarSize: DefaultMaxUntarSize,
+	}
+	opts.applyOpts(inOpts...)
+
+	return untar(r, &fsTarget{fs: fsys}, opts)
+}
+
+// untarTarget is the destination of the entries of a tar file, addressed
+// by their relative path.
+type untarTarget interface {
+	// MkdirAll creates the directory at the path, along with its parents.
+	MkdirAll(rel string) error
+	// Create creates or truncates the file at the path.
+	Create(rel string, mode fs.FileMode) (io.WriteCloser, error)
+	// Chtimes changes the access and modification times of the file at
+	// the path.
+	Chtimes(rel string, t time.Time) error
+	// Path returns the path to display in errors.
+	Path(rel string) string
+}
+
+// osTarget writes the entries of a tar file into a directory of the OS
+// filesystem.
+type osTarget struct {
+	dir string
+}
+
+func (t *osTarget) MkdirAll(rel string) error {
+	return os.MkdirAll(t.Path(rel), 0o750)
+}
+
+func (t *osTarget) Create(rel string, mode fs.FileMode) (io.WriteCloser, error) {
+	abs := t.Path(rel)
+	if runtime.GOOS == "darwin" && mode&0111 != 0 {
+		// The darwin kernel caches binary signatures
+		// and SIGKILLs binaries with mismatched
+		// signatures. Overwriting a binary with
+		// O_TRUNC does not clear the cache, rendering
+		// the new copy unusable. Removing the original
+		// file first does clear the cache. See #54132.
+		err := os.Remove(abs)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return os.OpenFile(abs, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
+}
+
+func (t *osTarget) Chtimes(rel string, tm time.Time) error {
+	return os.Chtimes(t.Path(rel), tm, tm)
+}
+
+func (t *osTarget) Path(rel string) string {
+	return filepath.Join(t.dir, rel)
+}
+
+// fsTarget writes the entries of a tar file into a billy.Filesystem.
+type fsTarget struct {
+	fs billy.Filesystem
+}
+
+func (t *fsTarget) MkdirAll(rel string) error {
+	return t.fs.MkdirAll(rel, 0o750)
+}
+
+func (t *fsTarget) Create(rel string, mode fs.FileMode) (io.WriteCloser, error) {
+	return t.fs.OpenFile(rel, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
+}
+
+func (t *fsTarget) Chtimes(rel string, tm time.Time) error {
+	// Not all the filesystems support changing the file times.
+	if c, ok := t.fs.(billy.Change); ok {
+		return c.Chtimes(rel, tm, tm)
+	}
+	return nil
+}
+
+func (t *fsTarget) Path(rel string) string {
+	return t.fs.Join(t.fs.Root(), rel)
+}
+
+// untar reads the tar file from r and writes its entries into the target.
+func untar(r io.Reader, target untarTarget, opts tarOpts) error {
 	madeDir := map[string]bool{}
 	var tr *tar.Reader
 	if opts.skipGzip {
@@ -116,7 +208,7 @@ func Untar(r io.Reader, dir string, inOpts ...TarOption) (err error) {
 			return fmt.Errorf("tar contained invalid name error %q", f.Name)
 		}
 		rel := filepath.FromSlash(f.Name)
-		abs := filepath.Join(dir, rel)
+		abs := target.Path(rel)
 
 		fi := f.FileInfo()
 		mode := fi.Mode()
@@ -127,26 +219,14 @@ func Untar(r io.Reader, dir string, inOpts ...TarOption) (err error) {
 			// already be made by a directory entry in the tar
 			// beforehand. Thus, don't check for errors; the next
 			// write will fail with the same error.
-			dir := filepath.Dir(abs)
+			dir := filepath.Dir(rel)
 			if !madeDir[dir] {
-				if err := os.MkdirAll(filepath.Dir(abs), 0o750); err != nil {
+				if err := target.MkdirAll(dir); err != nil {
 					return err
 				}
 				madeDir[dir] = true
 			}
-			if runtime.GOOS == "darwin" && mode&0111 != 0 {
-				// The darwin kernel caches binary signatures
-				// and SIGKILLs binaries with mismatched
-				// signatures. Overwriting a binary with
-				// O_TRUNC does not clear the cache, rendering
-				// the new copy unusable. Removing the original
-				// file first does clear the cache. See #54132.
-				err := os.Remove(abs)
-				if err != nil && !errors.Is(err, fs.ErrNotExist) {
-					return err
-				}
-			}
-			wf, err := os.OpenFile(abs, os.O_RDWR|os.O_CREATE|os.O_TRUNC, mode.Perm())
+			wf, err := target.Create(rel, mode)
 			if err != nil {
 				return err
 			}
@@ -172,15 +252,15 @@ func Untar(r io.Reader, dir string, inOpts ...TarOption) (err error) {
 				modTime = t0
 			}
 			if !modTime.IsZero() {
-				if err = os.Chtimes(abs, modTime, modTime); err != nil {
+				if err = target.Chtimes(rel, modTime); err != nil {
 					return fmt.Errorf("error changing file time %s: %w", abs, err)
 				}
 			}
 		case mode.IsDir():
-			if err := os.MkdirAll(abs, 0o750); err != nil {
+			if err := target.MkdirAll(rel); err != nil {
 				return err
 			}
-			madeDir[abs] = true
+			madeDir[rel] = true
 		case mode&os.ModeSymlink == os.ModeSymlink:
 			if !opts.skipSymlinks {
 				return fmt.Errorf("tar file entry %s is a symlink, which is not allowed in this context", f.Name)
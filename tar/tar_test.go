@@ -25,6 +25,9 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
 )
 
 type untarTestCase struct {
@@ -178,6 +181,70 @@ func TestUntar(t *testing.T) {
 	}
 }
 
+func TestUntarFS(t *testing.T) {
+	cases := []untarTestCase{
+		{
+			name:     "file at root",
+			fileName: "file1",
+			content:  geRandomContent(256),
+		},
+		{
+			name:     "file at subdir root",
+			fileName: "abc/fileX",
+			content:  geRandomContent(256),
+		},
+		{
+			name:     "directory traversal parent",
+			fileName: "../abc/file",
+			content:  geRandomContent(256),
+			wantErr:  `tar contained invalid name error "../abc/file"`,
+		},
+		{
+			name:         "breach max size",
+			fileName:     "big-file",
+			content:      geRandomContent(256),
+			maxUntarSize: 255,
+			wantErr:      `tar "big-file" is bigger than max archive size of 255 bytes`,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := createTestTar(tt)
+			if err != nil {
+				t.Fatalf("creating test tar: %v", err)
+			}
+			defer os.Remove(f.Name())
+
+			var opts []TarOption
+			if tt.maxUntarSize != 0 {
+				opts = append(opts, WithMaxUntarSize(tt.maxUntarSize))
+			}
+
+			fsys := memfs.New()
+			err = UntarFS(f, fsys, opts...)
+			var got string
+			if err != nil {
+				got = err.Error()
+			}
+			if tt.wantErr != got {
+				t.Fatalf("wanted error: '%s' got: '%v'", tt.wantErr, err)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+
+			content, err := util.ReadFile(fsys, tt.fileName)
+			if err != nil {
+				t.Fatalf("read %q: %v", tt.fileName, err)
+			}
+			if !bytes.Equal(content, tt.content) {
+				t.Errorf("file content of %q does not match", tt.fileName)
+			}
+		})
+	}
+}
+
 func Fuzz_Untar(f *testing.F) {
 	tf, err := createTestTar(untarTestCase{
 		name:     "file at root",